@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout is the maximum time that the completion subcommand of a
+// plugin may take.
+const completionTimeout = 10 * time.Second
+
+// completionSubcommand returns the name of the subcommand of the plugin that
+// completes its arguments. Plugins built with the plugin package always
+// supported cobra's "__complete" command, before they could set it.
+func (p *Plugin) completionSubcommand() string {
+	if p.CompletionSubcommand != "" {
+		return p.CompletionSubcommand
+	}
+	return cobra.ShellCompRequestCmd
+}
+
+// complete offers completion for the arguments of `docker <plugin> ...` by
+// running the completion subcommand of the plugin, with the global options of
+// the CLI that precede the name of the plugin, so that the plugin connects to
+// the same daemon. Its completions are returned to cobra rather than printed
+// by the plugin, so that they are rendered as those of the commands of the
+// CLI.
+func (p *Plugin) complete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if p.Err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	cargs := []string{p.completionSubcommand()}
+	cargs = append(cargs, globalOptions(os.Args, p.Name, args)...)
+	cargs = append(cargs, p.Name)
+	cargs = append(cargs, args...)
+	cargs = append(cargs, toComplete)
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+	pcmd := exec.CommandContext(ctx, p.Path, cargs...)
+	pcmd.Env = append(os.Environ(), ReexecEnvvar+"="+os.Args[0])
+	pcmd.Env = appendPluginResourceAttributesEnvvar(pcmd.Env, cmd.Root(), *p)
+	out, err := pcmd.Output()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return parseCompletions(out)
+}
+
+// globalOptions returns the global options of the CLI in osArgs, which are
+// "docker __complete [OPTIONS] PLUGIN [ARG...] TOCOMPLETE" when completing
+// the arguments of the plugin with the given name.
+func globalOptions(osArgs []string, name string, args []string) []string {
+	i := len(osArgs) - len(args) - 2
+	if i <= 2 || osArgs[i] != name {
+		return nil
+	}
+	return osArgs[2:i]
+}
+
+// parseCompletions parses the output of cobra's "__complete" command: a
+// completion per line, optionally with a tab-separated description, followed
+// by the directive, as ":<directive>".
+func parseCompletions(out []byte) ([]string, cobra.ShellCompDirective) {
+	lines := strings.Split(strings.TrimRight(string(bytes.ReplaceAll(out, []byte("\r\n"), []byte("\n"))), "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, ":") {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	directive, err := strconv.Atoi(last[1:])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var completions []string
+	for _, l := range lines[:len(lines)-1] {
+		if l != "" {
+			completions = append(completions, l)
+		}
+	}
+	return completions, cobra.ShellCompDirective(directive)
+}
@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseCompletions(t *testing.T) {
+	testCases := []struct {
+		doc                 string
+		out                 string
+		expectedCompletions []string
+		expectedDirective   cobra.ShellCompDirective
+	}{
+		{
+			doc:                 "completions",
+			out:                 "create\nls\tList the things\n:4\n",
+			expectedCompletions: []string{"create", "ls\tList the things"},
+			expectedDirective:   cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			doc:               "no completions",
+			out:               ":0\n",
+			expectedDirective: cobra.ShellCompDirectiveDefault,
+		},
+		{
+			doc:                 "CRLF",
+			out:                 "create\r\n:4\r\n",
+			expectedCompletions: []string{"create"},
+			expectedDirective:   cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			doc:               "no directive",
+			out:               "create\nls\n",
+			expectedDirective: cobra.ShellCompDirectiveError,
+		},
+		{
+			doc:               "invalid directive",
+			out:               "create\n:four\n",
+			expectedDirective: cobra.ShellCompDirectiveError,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			completions, directive := parseCompletions([]byte(tc.out))
+			assert.Check(t, is.DeepEqual(completions, tc.expectedCompletions))
+			assert.Check(t, is.Equal(directive, tc.expectedDirective))
+		})
+	}
+}
+
+func TestGlobalOptions(t *testing.T) {
+	testCases := []struct {
+		osArgs   []string
+		args     []string
+		expected []string
+	}{
+		{
+			osArgs: []string{"docker", "__complete", "hello", ""},
+		},
+		{
+			osArgs:   []string{"docker", "__complete", "--context", "remote", "hello", "world", "w"},
+			args:     []string{"world"},
+			expected: []string{"--context", "remote"},
+		},
+		{
+			// The CLI has been invoked otherwise.
+			osArgs: []string{"docker", "hello", "w"},
+			args:   []string{"world"},
+		},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.DeepEqual(globalOptions(tc.osArgs, "hello", tc.args), tc.expected))
+	}
+}
+
+func TestPluginComplete(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "docker-hello")
+	// The plugin completes its arguments with its own.
+	script := "#!/bin/sh\necho \"$1\"\nshift\n" + `for arg in "$@"; do echo "$arg"; done` + "\necho :4\n"
+	assert.NilError(t, os.WriteFile(path, []byte(script), 0o755))
+
+	oldArgs := os.Args
+	t.Cleanup(func() { os.Args = oldArgs })
+	os.Args = []string{"docker", "__complete", "--context", "remote", "hello", "world", "w"}
+
+	root := &cobra.Command{Use: "docker"}
+	cmd := &cobra.Command{Use: "hello"}
+	root.AddCommand(cmd)
+
+	p := &Plugin{Name: "hello", Path: path}
+	completions, directive := p.complete(cmd, []string{"world"}, "w")
+	assert.Check(t, is.DeepEqual(completions, []string{"__complete", "--context", "remote", "hello", "world", "w"}))
+	assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveNoFileComp))
+
+	p.CompletionSubcommand = "complete"
+	completions, _ = p.complete(cmd, []string{"world"}, "w")
+	assert.Check(t, is.Equal(completions[0], "complete"))
+
+	p.Err = NewPluginError("invalid metadata")
+	completions, directive = p.complete(cmd, []string{"world"}, "w")
+	assert.Check(t, is.Len(completions, 0))
+	assert.Check(t, is.Equal(directive, cobra.ShellCompDirectiveError))
+}
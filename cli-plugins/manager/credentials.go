@@ -0,0 +1,134 @@
+package manager
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli-plugins/socket"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker/registry"
+)
+
+const (
+	// CredentialsEnvvar is the name of the envvar with the address of the
+	// socket that the CLI brokers the credentials of registries on, for
+	// plugins which credentials are restricted to those of the registries
+	// they were granted.
+	CredentialsEnvvar = "DOCKER_CLI_PLUGIN_CREDENTIALS_SOCKET"
+
+	// CredentialsTokenEnvvar is the name of the envvar with the token that
+	// the plugin presents to the credentials broker. The token can only be
+	// used once.
+	CredentialsTokenEnvvar = "DOCKER_CLI_PLUGIN_CREDENTIALS_TOKEN"
+)
+
+// brokerTimeout is the time that a connection to the credentials broker has
+// to present its token.
+const brokerTimeout = 5 * time.Second
+
+// CredentialsBroker serves the credentials of the registries that a plugin
+// was granted, once, to the connection that presents its token.
+type CredentialsBroker struct {
+	*socket.PluginServer
+
+	mu    sync.Mutex
+	token string
+}
+
+// Env returns the envvars that give the plugin access to the broker.
+func (b *CredentialsBroker) Env() []string {
+	return []string{
+		CredentialsEnvvar + "=" + b.Addr().String(),
+		CredentialsTokenEnvvar + "=" + b.token,
+	}
+}
+
+// redeem returns whether token is the token of the broker, which can't be
+// used anymore after that.
+func (b *CredentialsBroker) redeem(token string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(b.token)) != 1 {
+		return false
+	}
+	b.token = ""
+	return true
+}
+
+// NewCredentialsBroker returns a broker of the credentials in configFile of
+// the registries that the plugin with the given name was granted, if it was
+// granted permissions, but not the credentials of all registries. It returns
+// nil otherwise.
+//
+// Only connections of processes of the user of the CLI, where the platform
+// allows checking it, that present the token of the broker are served.
+func NewCredentialsBroker(configFile *configfile.ConfigFile, name string) (*CredentialsBroker, error) {
+	granted, err := GrantedPermissions()
+	if err != nil {
+		return nil, err
+	}
+	perms, ok := granted[name]
+	if !ok || perms.allowsCredentials("*") {
+		return nil, nil
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	b := &CredentialsBroker{token: hex.EncodeToString(token)}
+	b.PluginServer, err = socket.NewPluginServer(func(conn net.Conn) {
+		defer conn.Close()
+		if !isCurrentUser(conn) {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(brokerTimeout))
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil || !b.redeem(strings.TrimSuffix(line, "\n")) {
+			return
+		}
+		creds := map[string]types.AuthConfig{}
+		for _, r := range perms.Credentials {
+			key := r
+			if r == "docker.io" || r == "index.docker.io" {
+				key = registry.IndexServer
+			}
+			authConfig, err := configFile.GetAuthConfig(key)
+			if err != nil || (authConfig.Username == "" && authConfig.IdentityToken == "" && authConfig.RegistryToken == "") {
+				continue
+			}
+			creds[key] = authConfig
+		}
+		_ = json.NewEncoder(conn).Encode(creds)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// BrokeredCredentials returns the credentials that the CLI brokers on the
+// socket with the given address, by the registries that they're the
+// credentials of, presenting token to the broker.
+func BrokeredCredentials(addr, token string) (map[string]types.AuthConfig, error) {
+	conn, err := net.Dial("unix", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte(token + "\n")); err != nil {
+		return nil, err
+	}
+	var creds map[string]types.AuthConfig
+	if err := json.NewDecoder(conn).Decode(&creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
@@ -0,0 +1,31 @@
+package manager
+
+import (
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// isCurrentUser returns whether the peer of conn runs as the user of the
+// CLI, as abstract sockets can be connected to by any user.
+func isCurrentUser(conn net.Conn) bool {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return false
+	}
+	raw, err := uc.SyscallConn()
+	if err != nil {
+		return false
+	}
+	var (
+		cred    *unix.Ucred
+		credErr error
+	)
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return false
+	}
+	return credErr == nil && int(cred.Uid) == os.Getuid()
+}
@@ -219,6 +219,10 @@ func PluginRunCommand(dockerCli command.Cli, name string, rootcmd *cobra.Command
 	if err != nil {
 		return nil, err
 	}
+	granted, err := GrantedPermissions()
+	if err != nil {
+		return nil, err
+	}
 
 	for _, d := range pluginDirs {
 		path := filepath.Join(d, exename)
@@ -250,7 +254,13 @@ func PluginRunCommand(dockerCli command.Cli, name string, rootcmd *cobra.Command
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
 
-		cmd.Env = append(cmd.Environ(), ReexecEnvvar+"="+os.Args[0])
+		cmd.Env = cmd.Environ()
+		if perms, ok := granted[name]; ok {
+			// Only pass the environment variables that the plugin was
+			// granted, and those that configure the CLI.
+			cmd.Env = filterEnv(cmd.Env, perms.Env)
+		}
+		cmd.Env = append(cmd.Env, ReexecEnvvar+"="+os.Args[0])
 		cmd.Env = appendPluginResourceAttributesEnvvar(cmd.Env, rootcmd, plugin)
 
 		return cmd, nil
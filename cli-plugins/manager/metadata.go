@@ -13,6 +13,11 @@ const (
 	// which must be implemented by plugins declaring support
 	// for hooks in their metadata.
 	HookSubcommandName = "docker-cli-plugin-hooks"
+
+	// ScannerSubcommandName is the name of the plugin subcommand
+	// which must be implemented by plugins declaring that they are
+	// scanners in their metadata.
+	ScannerSubcommandName = "docker-cli-plugin-scan"
 )
 
 // Metadata provided by the plugin.
@@ -27,4 +32,13 @@ type Metadata struct {
 	ShortDescription string `json:",omitempty"`
 	// URL is a pointer to the plugin's homepage.
 	URL string `json:",omitempty"`
+	// CompletionSubcommand is the name of the plugin subcommand which the
+	// CLI runs to complete the arguments of `docker <plugin> ...`, in the
+	// format of cobra's "__complete" command. Optional; the CLI runs
+	// "__complete" for plugins that don't set it.
+	CompletionSubcommand string `json:",omitempty"`
+	// Scanner declares that the plugin scans images for vulnerabilities
+	// with its ScannerSubcommandName subcommand, which "docker image scan"
+	// runs. Optional.
+	Scanner bool `json:",omitempty"`
 }
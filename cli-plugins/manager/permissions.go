@@ -0,0 +1,189 @@
+package manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/pkg/errors"
+)
+
+// PermissionsAnnotation is the annotation of the manifest of the OCI artifact
+// of a plugin with the permissions that the plugin requires, in JSON. They're
+// requested when the plugin is installed with "docker plugin-cli install",
+// before the plugin is fetched and executed. Plugins which artifacts don't
+// declare them aren't restricted.
+const PermissionsAnnotation = "com.docker.cli.plugin.permissions"
+
+// permissionsFileName is the name of the file, in the "cli-plugins"
+// directory of the config directory, with the permissions that were granted
+// to the plugins installed with "docker plugin-cli install".
+const permissionsFileName = "permissions.json"
+
+// Permissions are the permissions that a plugin requires, as declared in the
+// manifest of its OCI artifact, or that were granted to it.
+type Permissions struct {
+	// Credentials are the registries, such as "docker.io" or "ghcr.io",
+	// that the plugin uses the credentials of, or "*" for all of them.
+	Credentials []string `json:",omitempty"`
+	// Socket is whether the plugin requires raw access to the socket of
+	// the daemon, such as to mount it in the containers that it runs. It's
+	// advisory: it's requested and displayed, but not enforced, as plugins
+	// connect to the daemon that the CLI is configured for.
+	Socket bool `json:",omitempty"`
+	// Env are the environment variables that the plugin uses, in addition
+	// to those that configure the CLI, and the system. A trailing "*"
+	// matches the variables with that prefix.
+	Env []string `json:",omitempty"`
+}
+
+// IsEmpty returns whether p doesn't have any permission.
+func (p Permissions) IsEmpty() bool {
+	return len(p.Credentials) == 0 && !p.Socket && len(p.Env) == 0
+}
+
+// Covers returns whether p has all the permissions of other.
+func (p Permissions) Covers(other Permissions) bool {
+	if other.Socket && !p.Socket {
+		return false
+	}
+	for _, r := range other.Credentials {
+		if !p.allowsCredentials(r) {
+			return false
+		}
+	}
+	for _, e := range other.Env {
+		if !matchesEnv(p.Env, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe returns a description of each permission of p.
+func (p Permissions) Describe() []string {
+	var desc []string
+	if len(p.Credentials) > 0 {
+		if p.allowsCredentials("*") {
+			desc = append(desc, "credentials: all registries")
+		} else {
+			desc = append(desc, "credentials: "+strings.Join(p.Credentials, ", "))
+		}
+	}
+	if p.Socket {
+		desc = append(desc, "socket: raw access to the socket of the daemon (advisory, not enforced)")
+	}
+	if len(p.Env) > 0 {
+		desc = append(desc, "env: "+strings.Join(p.Env, ", "))
+	}
+	return desc
+}
+
+func (p Permissions) allowsCredentials(registry string) bool {
+	for _, r := range p.Credentials {
+		if r == "*" || r == registry {
+			return true
+		}
+	}
+	return false
+}
+
+// passthroughEnv are the environment variables that are passed to plugins
+// which environment variables are restricted: those that configure the
+// connection to the daemon, the output of the CLI, and the system. The
+// location of the configuration file, DOCKER_CONFIG, is passed, so that
+// plugins use the contexts of the CLI, as credentials are brokered. Variables
+// that configure credentials, such as DOCKER_AUTH_CONFIG, must be granted.
+var passthroughEnv = []string{
+	"DOCKER_HOST", "DOCKER_CONTEXT", "DOCKER_CONFIG", "DOCKER_TLS", "DOCKER_TLS_VERIFY", "DOCKER_CERT_PATH",
+	"DOCKER_API_VERSION", "DOCKER_DEFAULT_PLATFORM", "DOCKER_CLI_HINTS", "DOCKER_CLI_EXPERIMENTAL",
+	"DOCKER_HIDE_LEGACY_COMMANDS", "DOCKER_BUILDKIT", "DOCKER_CLI_PLUGIN_USE_DIAL_STDIO", "OTEL_*",
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "COLORTERM", "NO_COLOR",
+	"LANG", "LANGUAGE", "LC_*", "TZ", "TMPDIR", "XDG_*", "SSH_AUTH_SOCK",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY",
+	// Windows
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "TEMP", "TMP",
+	"APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES", "USERPROFILE",
+	"HOMEDRIVE", "HOMEPATH", "USERNAME",
+}
+
+// filterEnv returns the variables of env that are passed to a plugin that
+// was granted the given environment variables.
+func filterEnv(env []string, granted []string) []string {
+	filtered := make([]string, 0, len(env))
+	for _, kv := range env {
+		k, _, _ := strings.Cut(kv, "=")
+		if matchesEnv(passthroughEnv, k) || matchesEnv(granted, k) {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// matchesEnv returns whether the environment variable with the given name
+// matches one of patterns. Proxy variables, and all of them on Windows, are
+// matched case-insensitively.
+func matchesEnv(patterns []string, name string) bool {
+	for _, p := range patterns {
+		n := name
+		if runtime.GOOS == "windows" || strings.HasSuffix(strings.ToUpper(name), "_PROXY") {
+			p, n = strings.ToUpper(p), strings.ToUpper(n)
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(n, prefix) {
+				return true
+			}
+		} else if p == n {
+			return true
+		}
+	}
+	return false
+}
+
+func permissionsFile() (string, error) {
+	return config.Path("cli-plugins", permissionsFileName)
+}
+
+// GrantedPermissions returns the permissions that were granted to the plugins
+// installed with "docker plugin-cli install", by name. Plugins that don't
+// declare permissions in their metadata aren't granted any, and aren't
+// restricted.
+func GrantedPermissions() (map[string]Permissions, error) {
+	granted := map[string]Permissions{}
+	fn, err := permissionsFile()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return granted, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &granted); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", fn)
+	}
+	return granted, nil
+}
+
+// SaveGrantedPermissions saves the permissions that were granted to
+// plugins.
+func SaveGrantedPermissions(granted map[string]Permissions) error {
+	fn, err := permissionsFile()
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(granted, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(fn), 0o755); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(fn, b, 0o644)
+}
@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/config/types"
+	"github.com/docker/docker/registry"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPermissionsCovers(t *testing.T) {
+	granted := Permissions{Credentials: []string{"docker.io"}, Env: []string{"GITHUB_*", "TOKEN"}}
+	testCases := []struct {
+		doc      string
+		perms    Permissions
+		expected bool
+	}{
+		{doc: "none", expected: true},
+		{doc: "granted", perms: Permissions{Credentials: []string{"docker.io"}, Env: []string{"GITHUB_TOKEN", "TOKEN"}}, expected: true},
+		{doc: "other registry", perms: Permissions{Credentials: []string{"ghcr.io"}}},
+		{doc: "all registries", perms: Permissions{Credentials: []string{"*"}}},
+		{doc: "other env", perms: Permissions{Env: []string{"TOKEN2"}}},
+		{doc: "socket", perms: Permissions{Socket: true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			assert.Check(t, is.Equal(granted.Covers(tc.perms), tc.expected))
+		})
+	}
+	assert.Check(t, Permissions{Credentials: []string{"*"}}.Covers(Permissions{Credentials: []string{"ghcr.io"}}))
+}
+
+func TestFilterEnv(t *testing.T) {
+	env := []string{"PATH=/bin", "DOCKER_HOST=tcp://remote:2376", "DOCKER_AUTH_CONFIG={}", "DOCKER_CONFIG=/config", "https_proxy=http://proxy", "GITHUB_TOKEN=secret", "AWS_SECRET_ACCESS_KEY=secret", "LC_ALL=C"}
+	assert.Check(t, is.DeepEqual(filterEnv(env, nil), []string{"PATH=/bin", "DOCKER_HOST=tcp://remote:2376", "DOCKER_CONFIG=/config", "https_proxy=http://proxy", "LC_ALL=C"}))
+	assert.Check(t, is.DeepEqual(filterEnv(env, []string{"GITHUB_*", "DOCKER_AUTH_CONFIG"}), []string{"PATH=/bin", "DOCKER_HOST=tcp://remote:2376", "DOCKER_AUTH_CONFIG={}", "DOCKER_CONFIG=/config", "https_proxy=http://proxy", "GITHUB_TOKEN=secret", "LC_ALL=C"}))
+}
+
+func TestGrantedPermissions(t *testing.T) {
+	config.SetDir(t.TempDir())
+	granted, err := GrantedPermissions()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(granted, 0))
+
+	expected := map[string]Permissions{"hello": {Credentials: []string{"ghcr.io"}}, "world": {}}
+	assert.NilError(t, SaveGrantedPermissions(expected))
+	granted, err = GrantedPermissions()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(granted, expected))
+}
+
+func TestCredentialsBroker(t *testing.T) {
+	config.SetDir(t.TempDir())
+	configFile := configfile.New("config.json")
+	configFile.AuthConfigs = map[string]types.AuthConfig{
+		registry.IndexServer: {Username: "hub-user", Password: "hub-password"},
+		"ghcr.io":            {Username: "gh-user", Password: "gh-token"},
+		"registry.example":   {Username: "user", Password: "password"},
+	}
+	assert.NilError(t, SaveGrantedPermissions(map[string]Permissions{
+		"hello": {Credentials: []string{"docker.io", "ghcr.io", "unknown.example"}},
+		"world": {Credentials: []string{"*"}},
+	}))
+
+	broker, err := NewCredentialsBroker(configFile, "hello")
+	assert.NilError(t, err)
+	defer broker.Close()
+	addr, token := broker.Addr().String(), broker.token
+	assert.Check(t, is.Contains(broker.Env(), CredentialsTokenEnvvar+"="+token))
+
+	// Connections without the token aren't served.
+	_, err = BrokeredCredentials(addr, "not-the-token")
+	assert.Check(t, err != nil)
+
+	creds, err := BrokeredCredentials(addr, token)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(creds, map[string]types.AuthConfig{
+		registry.IndexServer: {Username: "hub-user", Password: "hub-password"},
+		"ghcr.io":            {Username: "gh-user", Password: "gh-token"},
+	}))
+
+	// The token can only be used once.
+	_, err = BrokeredCredentials(addr, token)
+	assert.Check(t, err != nil)
+
+	// Plugins that aren't restricted, or have access to all credentials,
+	// don't need a broker.
+	for _, name := range []string{"world", "buildx"} {
+		broker, err := NewCredentialsBroker(configFile, name)
+		assert.NilError(t, err)
+		assert.Check(t, broker == nil)
+	}
+}
@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/docker/cli/cli-plugins/scanner"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// ListScanners returns the valid plugins that declare that they are scanners
+// in their metadata.
+func ListScanners(dockerCli command.Cli, rootcmd *cobra.Command) ([]Plugin, error) {
+	plugins, err := ListPlugins(dockerCli, rootcmd)
+	if err != nil {
+		return nil, err
+	}
+	var scanners []Plugin
+	for _, p := range plugins {
+		if p.Err == nil && p.Scanner {
+			scanners = append(scanners, p)
+		}
+	}
+	return scanners, nil
+}
+
+// RunScanner executes the plugin's scanner command, and returns the report
+// that it writes to its standard output. Its standard error, where it may
+// write its progress, is written to stderr.
+func (p *Plugin) RunScanner(ctx context.Context, stderr io.Writer, req scanner.Request) (*scanner.Report, error) {
+	req.SchemaVersion = scanner.SchemaVersion
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, wrapAsPluginError(err, "failed to marshall scan request")
+	}
+
+	pCmd := exec.CommandContext(ctx, p.Path, p.Name, ScannerSubcommandName, string(reqBytes))
+	pCmd.Stderr = stderr
+	pCmd.Env = os.Environ()
+	granted, err := GrantedPermissions()
+	if err != nil {
+		return nil, err
+	}
+	if perms, ok := granted[p.Name]; ok {
+		pCmd.Env = filterEnv(pCmd.Env, perms.Env)
+	}
+	pCmd.Env = append(pCmd.Env, ReexecEnvvar+"="+os.Args[0])
+	out, err := pCmd.Output()
+	if err != nil {
+		return nil, wrapAsPluginError(err, "failed to execute plugin scanner subcommand")
+	}
+
+	report, err := scanner.ParseReport(out)
+	if err != nil {
+		return nil, wrapAsPluginError(err, "plugin "+p.Name)
+	}
+	return report, nil
+}
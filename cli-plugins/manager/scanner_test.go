@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/scanner"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRunScanner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	// The plugin reports the request that it's passed as the image.
+	script := `#!/bin/sh
+if [ "$1" = "docker-cli-plugin-metadata" ]; then
+	echo '{"SchemaVersion":"0.1.0","Vendor":"Example","Scanner":true}'
+	exit
+fi
+echo "Scanning with $1 $2" >&2
+printf '{"SchemaVersion":"0.1.0","Image":%s,"Vulnerabilities":[]}' "$(echo "$3" | sed 's/"/\\"/g; s/^/"/; s/$/"/')"
+`
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "docker-scanner"), []byte(script), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "docker-other"), []byte("#!/bin/sh\necho '{\"SchemaVersion\":\"0.1.0\",\"Vendor\":\"Example\"}'\n"), 0o755))
+
+	cli := test.NewFakeCli(nil)
+	cli.SetConfigFile(&configfile.ConfigFile{CLIPluginsExtraDirs: []string{dir}})
+	scanners, err := ListScanners(cli, &cobra.Command{})
+	assert.NilError(t, err)
+	// We're only interested in the plugins we created for testing this.
+	var names []string
+	for _, p := range scanners {
+		if p.Name == "scanner" || p.Name == "other" {
+			names = append(names, p.Name)
+		}
+	}
+	assert.Check(t, is.DeepEqual(names, []string{"scanner"}))
+
+	var stderr bytes.Buffer
+	p := &Plugin{Name: "scanner", Path: filepath.Join(dir, "docker-scanner")}
+	report, err := p.RunScanner(context.Background(), &stderr, scanner.Request{Image: "alpine"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(report.Image, `{"SchemaVersion":"0.1.0","Image":"alpine"}`))
+	assert.Check(t, is.Equal(stderr.String(), "Scanning with scanner docker-cli-plugin-scan\n"))
+}
+
+func TestRunScannerInvalidReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	path := filepath.Join(t.TempDir(), "docker-scanner")
+	assert.NilError(t, os.WriteFile(path, []byte("#!/bin/sh\necho '{\"SchemaVersion\":\"2.0.0\"}'\n"), 0o755))
+
+	p := &Plugin{Name: "scanner", Path: path}
+	_, err := p.RunScanner(context.Background(), &bytes.Buffer{}, scanner.Request{Image: "alpine"})
+	assert.Check(t, is.Error(err, `plugin scanner: invalid scan report: SchemaVersion "2.0.0" is not valid, must be 0.1.0`))
+
+	assert.NilError(t, os.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0o755))
+	_, err = p.RunScanner(context.Background(), &bytes.Buffer{}, scanner.Request{Image: "alpine"})
+	assert.Check(t, is.Error(err, "failed to execute plugin scanner subcommand: exit status 1"))
+}
@@ -46,6 +46,9 @@ func RunPlugin(dockerCli *command.DockerCli, plugin *cobra.Command, meta manager
 				opts = append(opts, withPluginClientConn(plugin.Name()))
 			}
 			err = tcmd.Initialize(opts...)
+			if err == nil {
+				err = useBrokeredCredentials(dockerCli)
+			}
 			ogRunE := cmd.RunE
 			if ogRunE == nil {
 				ogRun := cmd.Run
@@ -107,6 +110,27 @@ func Run(makeCmd func(command.Cli) *cobra.Command, meta manager.Metadata) {
 	}
 }
 
+// useBrokeredCredentials replaces the credentials of the config file with
+// those that the CLI brokers, if it restricts the plugin to the credentials
+// of the registries that it was granted. The config file can't be saved
+// then, so that they don't replace those of the config file.
+func useBrokeredCredentials(dockerCli *command.DockerCli) error {
+	addr, ok := os.LookupEnv(manager.CredentialsEnvvar)
+	if !ok {
+		return nil
+	}
+	creds, err := manager.BrokeredCredentials(addr, os.Getenv(manager.CredentialsTokenEnvvar))
+	if err != nil {
+		return fmt.Errorf("failed to get credentials from the CLI: %w", err)
+	}
+	configFile := dockerCli.ConfigFile()
+	configFile.AuthConfigs = creds
+	configFile.CredentialsStore = ""
+	configFile.CredentialHelpers = nil
+	configFile.Filename = ""
+	return nil
+}
+
 func withPluginClientConn(name string) command.CLIOption {
 	return command.WithInitializeClient(func(dockerCli *command.DockerCli) (client.APIClient, error) {
 		cmd := "docker"
@@ -180,6 +204,9 @@ func newMetadataSubcommand(plugin *cobra.Command, meta manager.Metadata) *cobra.
 	if meta.ShortDescription == "" {
 		meta.ShortDescription = plugin.Short
 	}
+	if meta.CompletionSubcommand == "" {
+		meta.CompletionSubcommand = cobra.ShellCompRequestCmd
+	}
 	cmd := &cobra.Command{
 		Use:    manager.MetadataSubcommandName,
 		Hidden: true,
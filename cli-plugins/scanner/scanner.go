@@ -0,0 +1,167 @@
+// Package scanner defines the schema of the requests that "docker image scan"
+// passes to scanner plugins, and of the reports that they return.
+//
+// A scanner plugin declares that it scans images in its metadata, and
+// implements the "docker-cli-plugin-scan" subcommand, which is run with a
+// [Request] as JSON as its argument, and writes a [Report] as JSON to its
+// standard output. It may write progress to its standard error, which the CLI
+// shows to the user.
+package scanner
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaVersion is the version of the schema of requests and reports.
+const SchemaVersion = "0.1.0"
+
+// Severities of vulnerabilities, from the most to the least severe.
+const (
+	SeverityCritical   = "critical"
+	SeverityHigh       = "high"
+	SeverityMedium     = "medium"
+	SeverityLow        = "low"
+	SeverityNegligible = "negligible"
+	SeverityUnknown    = "unknown"
+)
+
+var severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityNegligible, SeverityUnknown}
+
+// Severities returns the severities of vulnerabilities, from the most to the
+// least severe.
+func Severities() []string {
+	return append([]string(nil), severities...)
+}
+
+// ParseSeverity parses a severity, case-insensitively.
+func ParseSeverity(s string) (string, error) {
+	s = strings.ToLower(s)
+	for _, severity := range severities {
+		if s == severity {
+			return s, nil
+		}
+	}
+	return "", errors.Errorf("invalid severity %q: must be one of %s", s, strings.Join(severities, ", "))
+}
+
+// AtLeast returns whether severity is at least as severe as minimum. Unknown
+// severities are only at least as severe as SeverityUnknown.
+func AtLeast(severity, minimum string) bool {
+	return rank(severity) <= rank(minimum)
+}
+
+func rank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severities) - 1
+}
+
+// Request is the request to scan an image.
+type Request struct {
+	// SchemaVersion is the version of the schema of the request.
+	SchemaVersion string
+	// Image is the reference of the image to scan, as passed to "docker
+	// image scan". It's an image of the daemon, if the daemon has it, or
+	// an image of its registry otherwise.
+	Image string
+	// Platform is the platform of the image to scan, of a multi-platform
+	// image, in the format "os[/arch[/variant]]". Optional; scanners use
+	// the platform of the daemon if it's empty.
+	Platform string `json:",omitempty"`
+}
+
+// Report is the result of the scan of an image.
+type Report struct {
+	// SchemaVersion is the version of the schema of the report. Mandatory,
+	// must be SchemaVersion.
+	SchemaVersion string
+	// Scanner is the scanner that scanned the image.
+	Scanner Scanner
+	// Image is the reference of the image that was scanned.
+	Image string
+	// Digest is the digest of the image that was scanned, if known.
+	Digest string `json:",omitempty"`
+	// Vulnerabilities are the vulnerabilities that were found.
+	Vulnerabilities []Vulnerability
+}
+
+// Scanner describes the tool that scanned an image.
+type Scanner struct {
+	// Name is the name of the scanner, such as "grype" or "trivy".
+	Name string
+	// Version is the version of the scanner.
+	Version string `json:",omitempty"`
+	// URL is the homepage of the scanner.
+	URL string `json:",omitempty"`
+}
+
+// Vulnerability is a vulnerability of a package of an image.
+type Vulnerability struct {
+	// ID is the identifier of the vulnerability, such as "CVE-2024-0727".
+	ID string
+	// Severity is the severity of the vulnerability: one of the Severity
+	// constants.
+	Severity string
+	// Package is the name of the vulnerable package.
+	Package string
+	// Version is the installed version of the package.
+	Version string
+	// FixedVersion is the version of the package that fixes the
+	// vulnerability, if any.
+	FixedVersion string `json:",omitempty"`
+	// Title is a short description of the vulnerability.
+	Title string `json:",omitempty"`
+	// URL is a link to the details of the vulnerability.
+	URL string `json:",omitempty"`
+}
+
+// ParseReport parses and validates a report. The severities of its
+// vulnerabilities are normalized, and unknown severities are replaced with
+// SeverityUnknown.
+func ParseReport(b []byte) (*Report, error) {
+	var r Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, errors.Wrap(err, "invalid scan report")
+	}
+	if r.SchemaVersion != SchemaVersion {
+		return nil, errors.Errorf("invalid scan report: SchemaVersion %q is not valid, must be %s", r.SchemaVersion, SchemaVersion)
+	}
+	for i, v := range r.Vulnerabilities {
+		if v.ID == "" {
+			return nil, errors.Errorf("invalid scan report: vulnerability of package %q has no ID", v.Package)
+		}
+		severity, err := ParseSeverity(v.Severity)
+		if err != nil {
+			severity = SeverityUnknown
+		}
+		r.Vulnerabilities[i].Severity = severity
+	}
+	return &r, nil
+}
+
+// Filter returns the vulnerabilities of the report that are at least as
+// severe as minimum.
+func (r *Report) Filter(minimum string) []Vulnerability {
+	var vulns []Vulnerability
+	for _, v := range r.Vulnerabilities {
+		if AtLeast(v.Severity, minimum) {
+			vulns = append(vulns, v)
+		}
+	}
+	return vulns
+}
+
+// Counts returns the number of vulnerabilities of the report by severity.
+func (r *Report) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, v := range r.Vulnerabilities {
+		counts[v.Severity]++
+	}
+	return counts
+}
@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseReport(t *testing.T) {
+	report, err := ParseReport([]byte(`{
+	"SchemaVersion": "0.1.0",
+	"Scanner": {"Name": "grype", "Version": "0.74.0"},
+	"Image": "alpine:3.19",
+	"Vulnerabilities": [
+		{"ID": "CVE-2024-0727", "Severity": "High", "Package": "libcrypto3", "Version": "3.1.4-r2", "FixedVersion": "3.1.4-r5"},
+		{"ID": "CVE-2023-42363", "Severity": "moderate", "Package": "busybox", "Version": "1.36.1-r15"}
+	]
+}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(report.Scanner.Name, "grype"))
+	assert.Check(t, is.Equal(report.Vulnerabilities[0].Severity, SeverityHigh))
+	assert.Check(t, is.Equal(report.Vulnerabilities[1].Severity, SeverityUnknown))
+	assert.Check(t, is.DeepEqual(report.Counts(), map[string]int{SeverityHigh: 1, SeverityUnknown: 1}))
+	assert.Check(t, is.Len(report.Filter(SeverityCritical), 0))
+	assert.Check(t, is.Len(report.Filter(SeverityLow), 1))
+	assert.Check(t, is.Len(report.Filter(SeverityUnknown), 2))
+
+	_, err = ParseReport([]byte(`{"SchemaVersion": "1.0.0"}`))
+	assert.Check(t, is.Error(err, `invalid scan report: SchemaVersion "1.0.0" is not valid, must be 0.1.0`))
+	_, err = ParseReport([]byte(`{"SchemaVersion": "0.1.0", "Vulnerabilities": [{"Package": "busybox"}]}`))
+	assert.Check(t, is.Error(err, `invalid scan report: vulnerability of package "busybox" has no ID`))
+}
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := ParseSeverity("CRITICAL")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(severity, SeverityCritical))
+
+	_, err = ParseSeverity("severe")
+	assert.Check(t, is.Error(err, `invalid severity "severe": must be one of critical, high, medium, low, negligible, unknown`))
+}
+
+func TestAtLeast(t *testing.T) {
+	assert.Check(t, AtLeast(SeverityCritical, SeverityHigh))
+	assert.Check(t, AtLeast(SeverityHigh, SeverityHigh))
+	assert.Check(t, !AtLeast(SeverityMedium, SeverityHigh))
+	assert.Check(t, !AtLeast(SeverityUnknown, SeverityNegligible))
+	assert.Check(t, AtLeast(SeverityUnknown, SeverityUnknown))
+}
@@ -8,13 +8,13 @@ import (
 	"strings"
 
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/color"
 	"github.com/docker/cli/cli/command"
 	cliflags "github.com/docker/cli/cli/flags"
 	"github.com/docker/docker/pkg/homedir"
 	"github.com/docker/docker/registry"
 	"github.com/fvbommel/sortorder"
 	"github.com/moby/term"
-	"github.com/morikuni/aec"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -241,12 +241,7 @@ func isExperimental(cmd *cobra.Command) bool {
 
 func additionalHelp(cmd *cobra.Command) string {
 	if msg, ok := cmd.Annotations["additionalHelp"]; ok {
-		out := cmd.OutOrStderr()
-		if _, isTerminal := term.GetFdInfo(out); !isTerminal {
-			return msg
-		}
-		style := aec.EmptyBuilder.Bold().ANSI
-		return style.Apply(msg)
+		return color.Apply(cmd.OutOrStderr(), color.Heading, msg)
 	}
 	return ""
 }
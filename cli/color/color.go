@@ -0,0 +1,244 @@
+// Package color provides the styles that the CLI uses to color its output.
+//
+// Whether colors are used depends on the "--color" option (see [SetMode]),
+// the NO_COLOR environment variable (https://no-color.org), and whether the
+// output is a terminal. The styles can be customized with a theme in the
+// CLI configuration file (see [SetTheme]).
+package color
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/moby/term"
+	"github.com/morikuni/aec"
+	"github.com/pkg/errors"
+)
+
+// Values for the "--color" option.
+const (
+	ModeAuto   = "auto"
+	ModeAlways = "always"
+	ModeNever  = "never"
+)
+
+// Role is the purpose of a piece of output, which determines its style.
+type Role string
+
+// Roles of output that can be styled using a theme.
+const (
+	Heading Role = "heading"
+	Notice  Role = "notice"
+	Success Role = "success"
+	Warning Role = "warning"
+	Error   Role = "error"
+	Muted   Role = "muted"
+)
+
+var defaultTheme = map[Role]string{
+	Heading: "bold",
+	Notice:  "white on cyan",
+	Success: "green",
+	Warning: "yellow",
+	Error:   "red",
+	Muted:   "faint",
+}
+
+var (
+	mu     sync.RWMutex
+	mode   = ModeAuto
+	styles = mustParseTheme(defaultTheme)
+)
+
+// SetMode sets whether colors are used: "auto" uses colors if the output is
+// a terminal and the NO_COLOR environment variable is not set, "always" and
+// "never" always or never use colors.
+func SetMode(m string) error {
+	switch m {
+	case "":
+		m = ModeAuto
+	case ModeAuto, ModeAlways, ModeNever:
+	default:
+		return errors.Errorf("invalid color mode %q: must be %q, %q, or %q", m, ModeAuto, ModeAlways, ModeNever)
+	}
+	mu.Lock()
+	mode = m
+	mu.Unlock()
+	return nil
+}
+
+// SetTheme overrides the styles of the given roles. A style is a list of
+// attributes, separated by spaces: "bold", "faint", "italic", "underline",
+// "inverse", and a color, optionally followed by "on" and a background
+// color. Colors are "black", "red", "green", "yellow", "blue", "magenta",
+// "cyan", and "white", optionally prefixed with "light" (for example,
+// "lightred"). An empty style disables styling for the role.
+func SetTheme(theme map[string]string) error {
+	t := make(map[Role]string, len(defaultTheme))
+	for role, style := range defaultTheme {
+		t[role] = style
+	}
+	for role, style := range theme {
+		if _, ok := defaultTheme[Role(role)]; !ok {
+			return errors.Errorf("invalid color theme: unknown role %q: must be one of %s", role, strings.Join(roleNames(), ", "))
+		}
+		t[Role(role)] = style
+	}
+	s, err := parseTheme(t)
+	if err != nil {
+		return errors.Wrap(err, "invalid color theme")
+	}
+	mu.Lock()
+	styles = s
+	mu.Unlock()
+	return nil
+}
+
+// Enabled returns whether colors are used for output to out.
+func Enabled(out io.Writer) bool {
+	mu.RLock()
+	m := mode
+	mu.RUnlock()
+	switch m {
+	case ModeAlways:
+		return true
+	case ModeNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if t, ok := out.(interface{ IsTerminal() bool }); ok {
+		return t.IsTerminal()
+	}
+	_, isTerminal := term.GetFdInfo(out)
+	return isTerminal
+}
+
+// Apply returns text in the style of role if colors are used for output to
+// out, and text unchanged otherwise.
+func Apply(out io.Writer, role Role, text string) string {
+	if !Enabled(out) {
+		return text
+	}
+	mu.RLock()
+	style := styles[role]
+	mu.RUnlock()
+	if style == nil {
+		return text
+	}
+	return style.Apply(text)
+}
+
+func roleNames() []string {
+	names := make([]string, 0, len(defaultTheme))
+	for role := range defaultTheme {
+		names = append(names, string(role))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mustParseTheme(theme map[Role]string) map[Role]aec.ANSI {
+	s, err := parseTheme(theme)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parseTheme(theme map[Role]string) (map[Role]aec.ANSI, error) {
+	s := make(map[Role]aec.ANSI, len(theme))
+	for role, style := range theme {
+		ansi, err := parseStyle(style)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid style for %q", role)
+		}
+		s[role] = ansi
+	}
+	return s, nil
+}
+
+var (
+	attributes = map[string]aec.ANSI{
+		"bold":      aec.Bold,
+		"faint":     aec.Faint,
+		"italic":    aec.Italic,
+		"underline": aec.Underline,
+		"inverse":   aec.Inverse,
+	}
+	foregrounds = map[string]aec.ANSI{
+		"black":        aec.BlackF,
+		"red":          aec.RedF,
+		"green":        aec.GreenF,
+		"yellow":       aec.YellowF,
+		"blue":         aec.BlueF,
+		"magenta":      aec.MagentaF,
+		"cyan":         aec.CyanF,
+		"white":        aec.WhiteF,
+		"lightblack":   aec.LightBlackF,
+		"lightred":     aec.LightRedF,
+		"lightgreen":   aec.LightGreenF,
+		"lightyellow":  aec.LightYellowF,
+		"lightblue":    aec.LightBlueF,
+		"lightmagenta": aec.LightMagentaF,
+		"lightcyan":    aec.LightCyanF,
+		"lightwhite":   aec.LightWhiteF,
+	}
+	backgrounds = map[string]aec.ANSI{
+		"black":        aec.BlackB,
+		"red":          aec.RedB,
+		"green":        aec.GreenB,
+		"yellow":       aec.YellowB,
+		"blue":         aec.BlueB,
+		"magenta":      aec.MagentaB,
+		"cyan":         aec.CyanB,
+		"white":        aec.WhiteB,
+		"lightblack":   aec.LightBlackB,
+		"lightred":     aec.LightRedB,
+		"lightgreen":   aec.LightGreenB,
+		"lightyellow":  aec.LightYellowB,
+		"lightblue":    aec.LightBlueB,
+		"lightmagenta": aec.LightMagentaB,
+		"lightcyan":    aec.LightCyanB,
+		"lightwhite":   aec.LightWhiteB,
+	}
+)
+
+// parseStyle parses a style, as described in [SetTheme]. It returns nil for
+// an empty style.
+func parseStyle(style string) (aec.ANSI, error) {
+	words := strings.Fields(strings.ToLower(style))
+	if len(words) == 0 {
+		return nil, nil
+	}
+	var ansi []aec.ANSI
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		if word == "on" {
+			if i+1 == len(words) {
+				return nil, errors.New(`missing background color after "on"`)
+			}
+			i++
+			bg, ok := backgrounds[words[i]]
+			if !ok {
+				return nil, errors.Errorf("unknown color %q", words[i])
+			}
+			ansi = append(ansi, bg)
+			continue
+		}
+		if a, ok := attributes[word]; ok {
+			ansi = append(ansi, a)
+			continue
+		}
+		fg, ok := foregrounds[word]
+		if !ok {
+			return nil, errors.Errorf("unknown color or attribute %q", word)
+		}
+		ansi = append(ansi, fg)
+	}
+	return aec.EmptyBuilder.With(ansi...).ANSI, nil
+}
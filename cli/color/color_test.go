@@ -0,0 +1,110 @@
+package color
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type terminal struct{ bytes.Buffer }
+
+func (*terminal) IsTerminal() bool { return true }
+
+func setMode(t *testing.T, m string) {
+	t.Helper()
+	assert.NilError(t, SetMode(m))
+	t.Cleanup(func() { _ = SetMode(ModeAuto) })
+}
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	tests := []struct {
+		doc      string
+		mode     string
+		noColor  string
+		terminal bool
+		expected bool
+	}{
+		{doc: "auto, terminal", mode: ModeAuto, terminal: true, expected: true},
+		{doc: "auto, no terminal", mode: ModeAuto},
+		{doc: "auto, terminal, NO_COLOR", mode: ModeAuto, noColor: "1", terminal: true},
+		{doc: "always, no terminal", mode: ModeAlways, expected: true},
+		{doc: "always, NO_COLOR", mode: ModeAlways, noColor: "1", expected: true},
+		{doc: "never, terminal", mode: ModeNever, terminal: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			setMode(t, tc.mode)
+			t.Setenv("NO_COLOR", tc.noColor)
+			var out io.Writer = &bytes.Buffer{}
+			if tc.terminal {
+				out = &terminal{}
+			}
+			assert.Check(t, is.Equal(Enabled(out), tc.expected))
+		})
+	}
+}
+
+func TestSetModeInvalid(t *testing.T) {
+	err := SetMode("sometimes")
+	assert.Check(t, is.Error(err, `invalid color mode "sometimes": must be "auto", "always", or "never"`))
+}
+
+func TestApply(t *testing.T) {
+	setMode(t, ModeAlways)
+	out := &bytes.Buffer{}
+	assert.Check(t, is.Equal(Apply(out, Heading, "title"), "\x1b[1mtitle\x1b[0m"))
+	assert.Check(t, is.Equal(Apply(out, Notice, "note"), "\x1b[37m\x1b[46mnote\x1b[0m"))
+
+	setMode(t, ModeNever)
+	assert.Check(t, is.Equal(Apply(out, Heading, "title"), "title"))
+}
+
+func TestSetTheme(t *testing.T) {
+	setMode(t, ModeAlways)
+	t.Cleanup(func() { _ = SetTheme(nil) })
+	out := &bytes.Buffer{}
+
+	assert.NilError(t, SetTheme(map[string]string{
+		"heading": "Underline LightBlue",
+		"error":   "bold white on red",
+		"muted":   "",
+	}))
+	assert.Check(t, is.Equal(Apply(out, Heading, "title"), "\x1b[4m\x1b[94mtitle\x1b[0m"))
+	assert.Check(t, is.Equal(Apply(out, Error, "failed"), "\x1b[1m\x1b[37m\x1b[41mfailed\x1b[0m"))
+	assert.Check(t, is.Equal(Apply(out, Muted, "hint"), "hint"))
+	assert.Check(t, is.Equal(Apply(out, Success, "done"), "\x1b[32mdone\x1b[0m"), "roles that are not in the theme should use the default style")
+}
+
+func TestSetThemeInvalid(t *testing.T) {
+	t.Cleanup(func() { _ = SetTheme(nil) })
+	tests := []struct {
+		doc      string
+		theme    map[string]string
+		expected string
+	}{
+		{
+			doc:      "unknown role",
+			theme:    map[string]string{"title": "bold"},
+			expected: `invalid color theme: unknown role "title": must be one of error, heading, muted, notice, success, warning`,
+		},
+		{
+			doc:      "unknown color",
+			theme:    map[string]string{"heading": "bold orange"},
+			expected: `invalid color theme: invalid style for "heading": unknown color or attribute "orange"`,
+		},
+		{
+			doc:      "missing background",
+			theme:    map[string]string{"notice": "white on"},
+			expected: `invalid color theme: invalid style for "notice": missing background color after "on"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			assert.Check(t, is.Error(SetTheme(tc.theme), tc.expected))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type addOptions struct {
+	name    string
+	command []string
+	force   bool
+}
+
+func newAddCommand(dockerCli command.Cli) *cobra.Command {
+	var opts addOptions
+
+	cmd := &cobra.Command{
+		Use:   "add [OPTIONS] NAME COMMAND [ARG...]",
+		Short: "Add an alias of a command",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			opts.command = args[1:]
+			return runAdd(dockerCli, cmd.Root(), opts)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+	flags.BoolVarP(&opts.force, "force", "f", false, "Replace the alias if it exists")
+
+	return cmd
+}
+
+func runAdd(dockerCli command.Cli, root *cobra.Command, opts addOptions) error {
+	if err := validateName(opts.name); err != nil {
+		return err
+	}
+	if IsReserved(opts.name) {
+		return errors.Errorf("cannot add alias %q: the name is reserved", opts.name)
+	}
+	if isBuiltin(root, opts.name) {
+		return errors.Errorf(`cannot add alias %q: it would shadow the "docker %s" command`, opts.name, opts.name)
+	}
+	if _, err := pluginmanager.GetPlugin(opts.name, dockerCli, root); err == nil {
+		return errors.Errorf(`cannot add alias %q: it would shadow the "docker %s" plugin`, opts.name, opts.name)
+	}
+
+	// A single argument is the whole command, such as in
+	// `docker alias add rmi-dangling "image prune --filter dangling=true"`.
+	command := opts.command[0]
+	if len(opts.command) > 1 {
+		command = join(opts.command)
+	}
+	if _, err := split(opts.name, command); err != nil {
+		return err
+	}
+
+	cfg := dockerCli.ConfigFile()
+	if _, ok := cfg.Aliases[opts.name]; ok && !opts.force {
+		return errors.Errorf("alias %q already exists: use --force to replace it", opts.name)
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
+	cfg.Aliases[opts.name] = command
+	if err := cfg.Save(); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), opts.name)
+	return nil
+}
+
+// join joins args into a command, quoting the arguments that would otherwise
+// be split.
+func join(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\#") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+		}
+		quoted = append(quoted, arg)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,83 @@
+package alias
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newTestCli(t *testing.T, aliases map[string]string) *test.FakeCli {
+	t.Helper()
+	cli := test.NewFakeCli(nil)
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	for name, command := range aliases {
+		cfg.Aliases[name] = command
+	}
+	cli.SetConfigFile(cfg)
+	return cli
+}
+
+// savedAliases returns the aliases in the configuration file of cli.
+func savedAliases(t *testing.T, cli *test.FakeCli) map[string]string {
+	t.Helper()
+	f, err := os.Open(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	defer f.Close()
+	cfg := configfile.New(cli.ConfigFile().Filename)
+	assert.NilError(t, cfg.LoadFromReader(f))
+	return cfg.Aliases
+}
+
+func runAddCommand(cli *test.FakeCli, args ...string) error {
+	root := newRootCommand()
+	root.AddCommand(newAddCommand(cli))
+	root.SetArgs(append([]string{"add"}, args...))
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	return root.Execute()
+}
+
+func TestAdd(t *testing.T) {
+	cli := newTestCli(t, map[string]string{"builder": "buildx"})
+	assert.NilError(t, runAddCommand(cli, "rmi-dangling", "image prune --filter dangling=true"))
+	assert.NilError(t, runAddCommand(cli, "msg", "run", "busybox", "echo", "hello world"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "rmi-dangling\nmsg\n"))
+	assert.Check(t, is.DeepEqual(savedAliases(t, cli), map[string]string{
+		"builder":      "buildx",
+		"rmi-dangling": "image prune --filter dangling=true",
+		"msg":          `run busybox echo 'hello world'`,
+	}))
+}
+
+func TestAddErrors(t *testing.T) {
+	tests := []struct {
+		args          []string
+		expectedError string
+	}{
+		{args: []string{"ps", "container ls"}, expectedError: `cannot add alias "ps": it would shadow the "docker ps" command`},
+		{args: []string{"list", "image ls"}, expectedError: `cannot add alias "list": it would shadow the "docker list" command`},
+		{args: []string{"builder", "buildx"}, expectedError: `cannot add alias "builder": the name is reserved`},
+		{args: []string{"-x", "ps"}, expectedError: `unknown shorthand flag: 'x' in -x`},
+		{args: []string{"my alias", "ps"}, expectedError: `invalid alias name "my alias"`},
+		{args: []string{"empty", ""}, expectedError: `invalid alias "empty": the command is empty`},
+		{args: []string{"msg", "version"}, expectedError: `alias "msg" already exists: use --force to replace it`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.args[0], func(t *testing.T) {
+			cli := newTestCli(t, map[string]string{"msg": "run busybox echo hello"})
+			assert.Check(t, is.Error(runAddCommand(cli, tc.args...), tc.expectedError))
+		})
+	}
+}
+
+func TestAddForce(t *testing.T) {
+	cli := newTestCli(t, map[string]string{"msg": "run busybox echo hello"})
+	assert.NilError(t, runAddCommand(cli, "--force", "msg", "run", "alpine", "echo", "hello"))
+	assert.Check(t, is.DeepEqual(savedAliases(t, cli), map[string]string{"msg": "run alpine echo hello"}))
+}
@@ -0,0 +1,118 @@
+package alias
+
+import (
+	"sort"
+	"strings"
+
+	pluginmanager "github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/google/shlex"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// reservedNames are the keys of the "aliases" property of the configuration
+// file that are not aliases of commands. "builder" sets the plugin that
+// "docker build" uses.
+var reservedNames = map[string]struct{}{
+	"builder": {},
+}
+
+// IsReserved returns whether name is a key of the "aliases" property of the
+// configuration file that has a special meaning, and which isn't an alias of
+// a command.
+func IsReserved(name string) bool {
+	_, ok := reservedNames[name]
+	return ok
+}
+
+// names returns the sorted names of the aliases of commands.
+func names(aliases map[string]string) []string {
+	var names []string
+	for name := range aliases {
+		if !IsReserved(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isBuiltin returns whether name is the name, or an alias, of a command of
+// root.
+func isBuiltin(root *cobra.Command, name string) bool {
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\n\"'") {
+		return errors.Errorf("invalid alias name %q", name)
+	}
+	return nil
+}
+
+// split splits the command of an alias into arguments, as a shell would.
+func split(name, command string) ([]string, error) {
+	args, err := shlex.Split(command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid alias %q", name)
+	}
+	if len(args) == 0 {
+		return nil, errors.Errorf("invalid alias %q: the command is empty", name)
+	}
+	return args, nil
+}
+
+// Validate returns the errors of the aliases of commands that are invalid:
+// aliases which name is invalid, or which command cannot be split.
+func Validate(aliases map[string]string) []error {
+	var errs []error
+	for _, name := range names(aliases) {
+		if err := validateName(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := split(name, aliases[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Expand replaces the name of an alias, if args starts with one, with the
+// arguments of the command it's an alias of. args are the arguments after the
+// global options; the name of the alias may follow the hidden command that
+// cobra uses for completion.
+//
+// Aliases cannot shadow the commands of root, or plugins: a command or a
+// plugin takes precedence over an alias with the same name. Aliases are not
+// expanded recursively.
+func Expand(dockerCli command.Cli, root *cobra.Command, aliases map[string]string, args []string) ([]string, error) {
+	i := 0
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		i = 1
+	}
+	if len(args) <= i || IsReserved(args[i]) || validateName(args[i]) != nil || isBuiltin(root, args[i]) {
+		return args, nil
+	}
+	aliased, ok := aliases[args[i]]
+	if !ok {
+		return args, nil
+	}
+	// Plugins are only looked up for aliases, as it's slow.
+	if _, err := pluginmanager.GetPlugin(args[i], dockerCli, root); err == nil {
+		return args, nil
+	}
+	expanded, err := split(args[i], aliased)
+	if err != nil {
+		return args, err
+	}
+	out := append([]string{}, args[:i]...)
+	out = append(out, expanded...)
+	return append(out, args[i+1:]...), nil
+}
@@ -0,0 +1,86 @@
+package alias
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{Use: "docker"}
+	root.AddCommand(
+		&cobra.Command{Use: "image"},
+		&cobra.Command{Use: "ps", Aliases: []string{"list"}},
+		&cobra.Command{Use: "builder"},
+	)
+	return root
+}
+
+func TestExpand(t *testing.T) {
+	aliases := map[string]string{
+		"rmi-dangling": "image prune --filter dangling=true",
+		"msg":          `run busybox echo "hello world"`,
+		"ps":           "container ls --all",
+		"list":         "image ls",
+		"builder":      "buildx",
+		"loop":         "loop",
+		"broken":       `run "busybox`,
+		"myplugin":     "image ls",
+	}
+	tests := []struct {
+		args          []string
+		expected      []string
+		expectedError string
+	}{
+		{args: []string{}, expected: []string{}},
+		{args: []string{"rmi-dangling", "-f"}, expected: []string{"image", "prune", "--filter", "dangling=true", "-f"}},
+		{args: []string{"msg"}, expected: []string{"run", "busybox", "echo", "hello world"}},
+		{args: []string{"__complete", "rmi-dangling", ""}, expected: []string{"__complete", "image", "prune", "--filter", "dangling=true", ""}},
+		{args: []string{"image", "rmi-dangling"}, expected: []string{"image", "rmi-dangling"}},
+		{args: []string{"ps", "-q"}, expected: []string{"ps", "-q"}},
+		{args: []string{"list"}, expected: []string{"list"}},
+		{args: []string{"builder"}, expected: []string{"builder"}},
+		{args: []string{"loop"}, expected: []string{"loop"}},
+		{args: []string{"broken"}, expectedError: `invalid alias "broken": EOF found when expecting closing quote`},
+		{args: []string{"myplugin"}, expected: []string{"myplugin"}},
+	}
+	dir := fs.NewDir(t, t.Name(), fs.WithFile("docker-myplugin", "", fs.WithMode(0o777)))
+	defer dir.Remove()
+	cli := test.NewFakeCli(nil)
+	cli.ConfigFile().CLIPluginsExtraDirs = []string{dir.Path()}
+	root := newRootCommand()
+	for _, tc := range tests {
+		args, err := Expand(cli, root, aliases, tc.args)
+		if tc.expectedError != "" {
+			assert.Check(t, is.Error(err, tc.expectedError))
+			continue
+		}
+		assert.Check(t, err)
+		assert.Check(t, is.DeepEqual(args, tc.expected), tc.args)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	errs := Validate(map[string]string{
+		"ok":        "image ls",
+		"-flag":     "image ls",
+		"two words": "image ls",
+		"broken":    `run "busybox`,
+		"empty":     "",
+		"builder":   "buildx",
+	})
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Check(t, is.DeepEqual(messages, []string{
+		`invalid alias name "-flag"`,
+		`invalid alias "broken": EOF found when expecting closing quote`,
+		`invalid alias "empty": the command is empty`,
+		`invalid alias name "two words"`,
+	}))
+}
@@ -0,0 +1,31 @@
+package alias
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/spf13/cobra"
+)
+
+// NewAliasCommand returns a cobra command for `alias` subcommands
+func NewAliasCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage command aliases",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newAddCommand(dockerCli),
+		newListCommand(dockerCli),
+		newRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+// completeNames offers completion for the aliases of commands
+func completeNames(dockerCli command.Cli) completion.ValidArgsFn {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return names(dockerCli.ConfigFile().Aliases), cobra.ShellCompDirectiveNoFileComp
+	}
+}
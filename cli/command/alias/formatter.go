@@ -0,0 +1,68 @@
+package alias
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultAliasTableFormat = "table {{.Name}}\t{{.Command}}"
+
+	aliasNameHeader    = "NAME"
+	aliasCommandHeader = "COMMAND"
+)
+
+// alias is an alias of a command.
+type alias struct {
+	Name    string
+	Command string
+}
+
+func newFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return `{{.Name}}`
+		}
+		return defaultAliasTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\ncommand: {{.Command}}\n`
+	}
+	return formatter.Format(source)
+}
+
+func formatWrite(ctx formatter.Context, aliases []alias) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, a := range aliases {
+			if err := format(&aliasContext{a: a}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	aliasCtx := aliasContext{}
+	aliasCtx.Header = formatter.SubHeaderContext{
+		"Name":    aliasNameHeader,
+		"Command": aliasCommandHeader,
+	}
+	return ctx.Write(&aliasCtx, render)
+}
+
+type aliasContext struct {
+	formatter.HeaderContext
+	a alias
+}
+
+func (c *aliasContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *aliasContext) Name() string {
+	return c.a.Name
+}
+
+func (c *aliasContext) Command() string {
+	return c.a.Command
+}
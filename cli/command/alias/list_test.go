@@ -0,0 +1,41 @@
+package alias
+
+import (
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestList(t *testing.T) {
+	aliases := map[string]string{
+		"builder":      "buildx",
+		"rmi-dangling": "image prune --filter dangling=true",
+		"msg":          "run busybox echo hello",
+	}
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			expected: `NAME           COMMAND
+msg            run busybox echo hello
+rmi-dangling   image prune --filter dangling=true
+`,
+		},
+		{args: []string{"-q"}, expected: "msg\nrmi-dangling\n"},
+		{args: []string{"--format", "{{.Name}}={{.Command}}"}, expected: "msg=run busybox echo hello\nrmi-dangling=image prune --filter dangling=true\n"},
+		{args: []string{"--format", "json"}, expected: `{"Command":"run busybox echo hello","Name":"msg"}
+{"Command":"image prune --filter dangling=true","Name":"rmi-dangling"}
+`},
+	}
+	for _, tc := range tests {
+		cli := newTestCli(t, aliases)
+		cmd := newListCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(io.Discard)
+		assert.NilError(t, cmd.Execute())
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+	}
+}
@@ -0,0 +1,50 @@
+package alias
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm NAME [NAME...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more aliases",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(dockerCli, args)
+		},
+		ValidArgsFunction: completeNames(dockerCli),
+	}
+}
+
+func runRemove(dockerCli command.Cli, names []string) error {
+	cfg := dockerCli.ConfigFile()
+	var errs []string
+	var removed []string
+	for _, name := range names {
+		if _, ok := cfg.Aliases[name]; !ok || IsReserved(name) {
+			errs = append(errs, fmt.Sprintf("no such alias: %s", name))
+			continue
+		}
+		delete(cfg.Aliases, name)
+		removed = append(removed, name)
+	}
+	if len(removed) > 0 {
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+		for _, name := range removed {
+			_, _ = fmt.Fprintln(dockerCli.Out(), name)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package alias
+
+import (
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRemove(t *testing.T) {
+	cli := newTestCli(t, map[string]string{
+		"builder": "buildx",
+		"msg":     "run busybox echo hello",
+		"v":       "version",
+	})
+	cmd := newRemoveCommand(cli)
+	cmd.SetArgs([]string{"v", "builder", "nope"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "no such alias: builder\nno such alias: nope"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "v\n"))
+	assert.Check(t, is.DeepEqual(savedAliases(t, cli), map[string]string{
+		"builder": "buildx",
+		"msg":     "run busybox echo hello",
+	}))
+}
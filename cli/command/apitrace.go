@@ -0,0 +1,222 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/client"
+)
+
+// maxTraceBody is the maximum size of the bodies of the requests and
+// responses that are printed in API traces.
+const maxTraceBody = 64 * 1024
+
+const redacted = "[redacted]"
+
+// redactedHeaders are the headers of requests and responses of which the
+// values are redacted in API traces.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+	"Set-Cookie":          true,
+	"X-Registry-Auth":     true,
+	"X-Registry-Config":   true,
+}
+
+// redactedFields are the fields of JSON bodies, in lower case, of which the
+// values are redacted in API traces.
+var redactedFields = map[string]bool{
+	"auth":          true,
+	"data":          true,
+	"identitytoken": true,
+	"password":      true,
+	"registrytoken": true,
+	"secret":        true,
+	"token":         true,
+}
+
+// redactedQuery are the query parameters of requests, in lower case, of which
+// the values are redacted in API traces. Build arguments often hold
+// credentials, and the remote context of a build may be a URL with
+// credentials.
+var redactedQuery = map[string]bool{
+	"buildargs": true,
+	"remote":    true,
+}
+
+// traceAPIRequests makes apiClient write a summary of its requests to out if
+// its level is [streams.LevelVerbose], and traces of the requests and
+// responses if its level is [streams.LevelDebug]. The transport of the client
+// is wrapped after the client is created, so that the options of the client
+// configure the transport it's created with.
+func traceAPIRequests(apiClient client.APIClient, out *streams.Out) {
+	c, ok := apiClient.(*client.Client)
+	if !ok {
+		return
+	}
+	httpClient := c.HTTPClient()
+	httpClient.Transport = &traceTransport{base: httpClient.Transport, out: out}
+	_ = client.WithHTTPClient(httpClient)(c)
+}
+
+// traceTransport is an [http.RoundTripper] that writes a summary of the API
+// requests, and traces of the requests and responses, to out, depending on
+// its level of output.
+type traceTransport struct {
+	base http.RoundTripper
+	out  *streams.Out
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	debug := t.out.Level() >= streams.LevelDebug
+	if debug {
+		t.traceRequest(req)
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.out.Verbosef("API %s %s: %v (%s)\n", req.Method, req.URL.Path, err, elapsed)
+		return nil, err
+	}
+	t.out.Verbosef("API %s %s: %s (%s)\n", req.Method, req.URL.Path, resp.Status, elapsed)
+	if debug {
+		t.traceResponse(resp)
+	}
+	return resp, nil
+}
+
+func (t *traceTransport) traceRequest(req *http.Request) {
+	t.out.Debugf("> %s %s %s\n", req.Method, redactRequestURI(req.URL), req.Proto)
+	traceHeaders(t.out, ">", req.Header)
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	body, ok := readTraceBody(req.Header, req.ContentLength, &req.Body)
+	traceBody(t.out, ">", body, ok)
+}
+
+func (t *traceTransport) traceResponse(resp *http.Response) {
+	t.out.Debugf("< %s %s\n", resp.Proto, resp.Status)
+	traceHeaders(t.out, "<", resp.Header)
+	if resp.Body == nil || resp.Body == http.NoBody || resp.ContentLength == 0 {
+		return
+	}
+	body, ok := readTraceBody(resp.Header, resp.ContentLength, &resp.Body)
+	traceBody(t.out, "<", body, ok)
+}
+
+// redactRequestURI returns the request URI of u, with the values of its
+// sensitive query parameters redacted.
+func redactRequestURI(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		k, v, _ := strings.Cut(param, "=")
+		if key, err := url.QueryUnescape(k); err == nil && redactedQuery[strings.ToLower(key)] && v != "" {
+			params[i] = k + "=" + redacted
+		}
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = strings.Join(params, "&")
+	return redactedURL.RequestURI()
+}
+
+func traceHeaders(out *streams.Out, prefix string, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			if redactedHeaders[http.CanonicalHeaderKey(k)] {
+				v = redacted
+			}
+			out.Debugf("%s %s: %s\n", prefix, k, v)
+		}
+	}
+}
+
+// readTraceBody reads a JSON body of a known size, and replaces it with a
+// reader of its content. Other bodies, such as streams and archives, aren't
+// read.
+func readTraceBody(header http.Header, length int64, body *io.ReadCloser) ([]byte, bool) {
+	if length < 0 || length > maxTraceBody || !strings.HasPrefix(header.Get("Content-Type"), "application/json") {
+		return nil, false
+	}
+	b, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	*body = io.NopCloser(bytes.NewReader(b))
+	return b, err == nil
+}
+
+func traceBody(out *streams.Out, prefix string, body []byte, ok bool) {
+	if !ok {
+		out.Debugf("%s [body not shown]\n", prefix)
+		return
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		out.Debugf("%s [body not shown]\n", prefix)
+		return
+	}
+	b, err := json.Marshal(redact(v))
+	if err != nil {
+		return
+	}
+	out.Debugf("%s %s\n", prefix, b)
+}
+
+// redact returns v with the values of its sensitive fields redacted, and the
+// values of environment variables, which often hold credentials.
+func redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, value := range v {
+			switch {
+			case redactedFields[strings.ToLower(k)]:
+				if value != nil && value != "" {
+					v[k] = redacted
+				}
+			case strings.EqualFold(k, "env"):
+				v[k] = redactEnv(value)
+			default:
+				v[k] = redact(value)
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func redactEnv(v any) any {
+	list, ok := v.([]any)
+	if !ok {
+		return redact(v)
+	}
+	for i, e := range list {
+		if s, ok := e.(string); ok {
+			if k, _, ok := strings.Cut(s, "="); ok {
+				list[i] = k + "=" + redacted
+			}
+		}
+	}
+	return list
+}
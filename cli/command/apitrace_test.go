@@ -0,0 +1,92 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTraceTransport(t *testing.T) {
+	const reqBody = `{"Name":"web","Env":["PASSWORD=hunter2"],"Auth":{"password":"hunter2"}}`
+	const respBody = `{"Id":"abc","Warnings":[]}`
+
+	for _, level := range []streams.Level{streams.LevelNormal, streams.LevelVerbose, streams.LevelDebug} {
+		var buf bytes.Buffer
+		out := streams.NewOut(&buf)
+		out.SetLevel(level)
+		transport := &traceTransport{
+			out: out,
+			base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				b, err := io.ReadAll(req.Body)
+				assert.NilError(t, err)
+				assert.Check(t, is.Equal(string(b), reqBody), "the request body must not be changed")
+				return &http.Response{
+					Status:        "201 Created",
+					StatusCode:    http.StatusCreated,
+					Proto:         "HTTP/1.1",
+					Header:        http.Header{"Content-Type": {"application/json"}},
+					ContentLength: int64(len(respBody)),
+					Body:          io.NopCloser(strings.NewReader(respBody)),
+				}, nil
+			}),
+		}
+
+		req, err := http.NewRequest(http.MethodPost, "http://docker/v1.45/containers/create?name=web", strings.NewReader(reqBody))
+		assert.NilError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Registry-Auth", "c2VjcmV0")
+		resp, err := transport.RoundTrip(req)
+		assert.NilError(t, err)
+		b, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(b), respBody), "the response body must not be changed")
+
+		output := buf.String()
+		switch level {
+		case streams.LevelNormal:
+			assert.Check(t, is.Equal(output, ""))
+		case streams.LevelVerbose:
+			assert.Check(t, strings.HasPrefix(output, "API POST /v1.45/containers/create: 201 Created ("))
+			assert.Check(t, !strings.Contains(output, ">"))
+		case streams.LevelDebug:
+			assert.Check(t, is.Contains(output, "> POST /v1.45/containers/create?name=web HTTP/1.1\n"))
+			assert.Check(t, is.Contains(output, "> X-Registry-Auth: [redacted]\n"))
+			assert.Check(t, is.Contains(output, `> {"Auth":"[redacted]","Env":["PASSWORD=[redacted]"],"Name":"web"}`+"\n"))
+			assert.Check(t, is.Contains(output, "< HTTP/1.1 201 Created\n"))
+			assert.Check(t, is.Contains(output, "< "+respBody+"\n"))
+			assert.Check(t, !strings.Contains(output, "hunter2"))
+		}
+	}
+}
+
+func TestRedactRequestURI(t *testing.T) {
+	for _, tc := range []struct {
+		uri      string
+		expected string
+	}{
+		{uri: "/v1.45/containers/json", expected: "/v1.45/containers/json"},
+		{uri: "/v1.45/containers/create?name=web", expected: "/v1.45/containers/create?name=web"},
+		{
+			uri:      "/v1.45/build?t=app&buildargs=%7B%22TOKEN%22%3A%22hunter2%22%7D&remote=https%3A%2F%2Fuser%3Ahunter2%40example.com%2Fapp.git",
+			expected: "/v1.45/build?t=app&buildargs=[redacted]&remote=[redacted]",
+		},
+		{uri: "/v1.45/build?buildargs=", expected: "/v1.45/build?buildargs="},
+	} {
+		u, err := url.Parse(tc.uri)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(redactRequestURI(u), tc.expected))
+	}
+}
@@ -19,6 +19,7 @@ func NewBuilderCommand(dockerCli command.Cli) *cobra.Command {
 	}
 	cmd.AddCommand(
 		NewPruneCommand(dockerCli),
+		newContextCommand(dockerCli),
 		image.NewBuildCommand(dockerCli),
 	)
 	return cmd
@@ -0,0 +1,352 @@
+package builder
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/image/build"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	units "github.com/docker/go-units"
+	"github.com/moby/patternmatcher"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultContextFileTableFormat = "table {{.Path}}\t{{.Size}}"
+
+	contextFilePathHeader = "PATH"
+	contextFileSizeHeader = "SIZE"
+
+	// buildFileSource is the source of the patterns that keep the Dockerfile
+	// and the .dockerignore file in the build context.
+	buildFileSource = "build files"
+)
+
+type contextListOptions struct {
+	context        string
+	dockerfileName string
+	contextFilters []string
+	why            string
+	format         string
+	quiet          bool
+}
+
+func newContextListCommand(dockerCli command.Cli) *cobra.Command {
+	var options contextListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS] [PATH]",
+		Aliases: []string{"list"},
+		Short:   "List the files that docker build sends in a build context",
+		Args:    cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.context = "."
+			if len(args) > 0 {
+				options.context = args[0]
+			}
+			return runContextList(dockerCli, options)
+		},
+		ValidArgsFunction: func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&options.dockerfileName, "file", "f", "", `Name of the Dockerfile (Default is "PATH/Dockerfile")`)
+	flags.StringArrayVar(&options.contextFilters, "context-filter", nil, "Exclude files that match a pattern from the build context, in addition to the .dockerignore file")
+	flags.StringVar(&options.why, "why", "", "Explain which patterns include or exclude a file of the build context")
+	flags.StringVar(&options.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display file paths")
+	return cmd
+}
+
+func runContextList(dockerCli command.Cli, options contextListOptions) error {
+	buildCtx, err := loadBuildContext(options)
+	if err != nil {
+		return err
+	}
+	if options.why != "" {
+		explanation, err := buildCtx.explain(options.why)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(dockerCli.Out(), explanation)
+		return nil
+	}
+
+	files, summary, err := buildCtx.files()
+	if err != nil {
+		return err
+	}
+	format := options.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	fileCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newContextFileFormat(format, options.quiet),
+	}
+	if err := contextFileWrite(fileCtx, files); err != nil {
+		return err
+	}
+	if format == formatter.TableFormatKey && !options.quiet {
+		_, _ = fmt.Fprintf(dockerCli.Out(), "\n%s\n", summary)
+	}
+	return nil
+}
+
+// buildContext is the build context of a local directory, with the patterns
+// that exclude files from it, as "docker build" sends it to the daemon.
+type buildContext struct {
+	dir      string
+	patterns []contextPattern
+}
+
+// contextPattern is a pattern that excludes files from a build context, or
+// includes them again if it's an exclusion pattern ("!PATTERN").
+type contextPattern struct {
+	pattern   string
+	source    string
+	exclusion bool
+	matcher   *patternmatcher.PatternMatcher
+}
+
+// contextFile is a file of a build context.
+type contextFile struct {
+	path string
+	size int64
+}
+
+func loadBuildContext(options contextListOptions) (*buildContext, error) {
+	contextDir, relDockerfile, err := build.GetContextFromLocalDir(options.context, options.dockerfileName)
+	if err != nil {
+		return nil, errors.Errorf("unable to prepare context: %s", err)
+	}
+	dockerignore, err := build.ReadDockerignore(contextDir)
+	if err != nil {
+		return nil, err
+	}
+	excludes := append(append([]string{}, dockerignore...), options.contextFilters...)
+	if err := build.ValidateContextDirectory(contextDir, excludes); err != nil {
+		return nil, errors.Wrap(err, "error checking context")
+	}
+	excludes = build.TrimBuildFilesFromExcludes(excludes, filepath.ToSlash(relDockerfile), false)
+
+	buildCtx := &buildContext{dir: contextDir}
+	for i, p := range excludes {
+		source := buildFileSource
+		switch {
+		case i < len(dockerignore):
+			source = ".dockerignore"
+		case i < len(dockerignore)+len(options.contextFilters):
+			source = "--context-filter"
+		}
+		exclusion := strings.HasPrefix(strings.TrimSpace(p), "!")
+		matcher, err := patternmatcher.New([]string{strings.TrimPrefix(strings.TrimSpace(p), "!")})
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %q of %s", p, source)
+		}
+		if len(matcher.Patterns()) == 0 {
+			continue
+		}
+		buildCtx.patterns = append(buildCtx.patterns, contextPattern{
+			pattern:   strings.TrimSpace(p),
+			source:    source,
+			exclusion: exclusion,
+			matcher:   matcher,
+		})
+	}
+	return buildCtx, nil
+}
+
+// match evaluates the patterns against a file of the build context, as the
+// pattern matcher that excludes files from the context does: the file is
+// excluded if the last pattern that matches it, or one of its parent
+// directories, isn't an exclusion pattern. It returns whether the file is
+// excluded, and the indexes of the patterns that match it.
+func (c *buildContext) match(file string) (bool, []int, error) {
+	excluded := false
+	var matches []int
+	for i, p := range c.patterns {
+		// As with the pattern matcher, patterns that can't change the result
+		// aren't evaluated.
+		if p.exclusion != excluded {
+			continue
+		}
+		match, err := p.matcher.MatchesOrParentMatches(file)
+		if err != nil {
+			return false, nil, err
+		}
+		if match {
+			excluded = !p.exclusion
+			matches = append(matches, i)
+		}
+	}
+	return excluded, matches, nil
+}
+
+// files returns the files that are sent in the build context, and a summary
+// of their sizes, and of the sizes of the files that are excluded.
+func (c *buildContext) files() ([]contextFile, string, error) {
+	var files []contextFile
+	var size, excludedCount, excludedSize int64
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(c.dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var fileSize int64
+		if info.Mode().IsRegular() {
+			fileSize = info.Size()
+		}
+		excluded, _, err := c.match(rel)
+		if err != nil {
+			return err
+		}
+		if excluded {
+			excludedCount++
+			excludedSize += fileSize
+			return nil
+		}
+		files = append(files, contextFile{path: filepath.ToSlash(rel), size: fileSize})
+		size += fileSize
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	summary := fmt.Sprintf("%s, %s", fileCount(int64(len(files))), units.HumanSizeWithPrecision(float64(size), 3))
+	if excludedCount > 0 {
+		summary += fmt.Sprintf(" (%s, %s excluded)", fileCount(excludedCount), units.HumanSizeWithPrecision(float64(excludedSize), 3))
+	}
+	return files, summary, nil
+}
+
+func fileCount(n int64) string {
+	if n == 1 {
+		return "1 file"
+	}
+	return fmt.Sprintf("%d files", n)
+}
+
+// explain explains which patterns include or exclude a file of the build
+// context, such as:
+//
+//	tmp/keep.log is sent in the build context: the .dockerignore pattern "!tmp/keep.log" includes it
+//
+//	PATTERN         SOURCE          RESULT
+//	*.log           .dockerignore   excludes
+//	!tmp/keep.log   .dockerignore   includes
+func (c *buildContext) explain(file string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(file))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid path %s: it must be relative to the build context", file)
+	}
+	if _, err := os.Lstat(filepath.Join(c.dir, rel)); err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.Errorf("%s doesn't exist in the build context %s", file, c.dir)
+		}
+		return "", err
+	}
+	file = filepath.ToSlash(rel)
+
+	excluded, matches, err := c.match(rel)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	switch {
+	case len(matches) == 0:
+		_, _ = fmt.Fprintf(&buf, "%s is sent in the build context: no pattern excludes it\n", file)
+		return buf.String(), nil
+	case excluded:
+		p := c.patterns[matches[len(matches)-1]]
+		_, _ = fmt.Fprintf(&buf, "%s isn't sent in the build context: the %s pattern %q excludes it\n", file, p.source, p.pattern)
+	default:
+		p := c.patterns[matches[len(matches)-1]]
+		if p.source == buildFileSource {
+			_, _ = fmt.Fprintf(&buf, "%s is sent in the build context: the Dockerfile and the .dockerignore file are always sent\n", file)
+		} else {
+			_, _ = fmt.Fprintf(&buf, "%s is sent in the build context: the %s pattern %q includes it\n", file, p.source, p.pattern)
+		}
+	}
+
+	_, _ = fmt.Fprintln(&buf)
+	w := tabwriter.NewWriter(&buf, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PATTERN\tSOURCE\tRESULT")
+	for _, i := range matches {
+		result := "excludes"
+		if c.patterns[i].exclusion {
+			result = "includes"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", c.patterns[i].pattern, c.patterns[i].source, result)
+	}
+	_ = w.Flush()
+	return buf.String(), nil
+}
+
+// newContextFileFormat returns a format for use with a context file Context
+func newContextFileFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return `{{.Path}}`
+		}
+		return defaultContextFileTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// contextFileWrite writes formatted files of a build context using the Context
+func contextFileWrite(ctx formatter.Context, files []contextFile) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, f := range files {
+			if err := format(&contextFileContext{f: f}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	fileCtx := contextFileContext{}
+	fileCtx.Header = formatter.SubHeaderContext{
+		"Path": contextFilePathHeader,
+		"Size": contextFileSizeHeader,
+	}
+	return ctx.Write(&fileCtx, render)
+}
+
+type contextFileContext struct {
+	formatter.HeaderContext
+	f contextFile
+}
+
+func (c *contextFileContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *contextFileContext) Path() string {
+	return c.f.path
+}
+
+func (c *contextFileContext) Size() string {
+	return units.HumanSizeWithPrecision(float64(c.f.size), 3)
+}
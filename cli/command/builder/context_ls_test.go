@@ -0,0 +1,142 @@
+package builder
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func newTestContext(t *testing.T) *fs.Dir {
+	t.Helper()
+	return fs.NewDir(t, "build-context",
+		fs.WithFile("Dockerfile", "FROM alpine\nCOPY . /\n"),
+		fs.WithFile(".dockerignore", "# logs\n**/*.log\n!tmp/keep.log\n.git\nDockerfile\n"),
+		fs.WithFile("app.go", "package main\n"),
+		fs.WithFile("debug.log", "debug\n"),
+		fs.WithDir(".git", fs.WithFile("HEAD", "ref: refs/heads/main\n")),
+		fs.WithDir("tmp",
+			fs.WithFile("keep.log", "keep\n"),
+			fs.WithFile("cache", "cache\n")),
+	)
+}
+
+func TestContextList(t *testing.T) {
+	dir := newTestContext(t)
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newContextListCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{dir.Path()})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `PATH            SIZE
+.dockerignore   46B
+Dockerfile      21B
+app.go          13B
+tmp/cache       6B
+tmp/keep.log    5B
+
+5 files, 91B (2 files, 27B excluded)
+`))
+
+	cli.ResetOutputBuffers()
+	cmd = newContextListCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--quiet", "--context-filter", "tmp", dir.Path()})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ".dockerignore\nDockerfile\napp.go\n"))
+}
+
+func TestContextListWhy(t *testing.T) {
+	dir := newTestContext(t)
+
+	testCases := []struct {
+		file     string
+		filters  []string
+		expected string
+	}{
+		{
+			file:     "app.go",
+			expected: "app.go is sent in the build context: no pattern excludes it\n",
+		},
+		{
+			file: "debug.log",
+			expected: `debug.log isn't sent in the build context: the .dockerignore pattern "**/*.log" excludes it
+
+PATTERN    SOURCE          RESULT
+**/*.log   .dockerignore   excludes
+`,
+		},
+		{
+			file: "tmp/keep.log",
+			expected: `tmp/keep.log is sent in the build context: the .dockerignore pattern "!tmp/keep.log" includes it
+
+PATTERN         SOURCE          RESULT
+**/*.log        .dockerignore   excludes
+!tmp/keep.log   .dockerignore   includes
+`,
+		},
+		{
+			file:    "tmp/keep.log",
+			filters: []string{"tmp"},
+			expected: `tmp/keep.log isn't sent in the build context: the --context-filter pattern "tmp" excludes it
+
+PATTERN         SOURCE             RESULT
+**/*.log        .dockerignore      excludes
+!tmp/keep.log   .dockerignore      includes
+tmp             --context-filter   excludes
+`,
+		},
+		{
+			file: ".git/HEAD",
+			expected: `.git/HEAD isn't sent in the build context: the .dockerignore pattern ".git" excludes it
+
+PATTERN   SOURCE          RESULT
+.git      .dockerignore   excludes
+`,
+		},
+		{
+			file: "Dockerfile",
+			expected: `Dockerfile is sent in the build context: the Dockerfile and the .dockerignore file are always sent
+
+PATTERN       SOURCE          RESULT
+Dockerfile    .dockerignore   excludes
+!Dockerfile   build files     includes
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cmd := newContextListCommand(cli)
+			cmd.SetOut(io.Discard)
+			args := []string{"--why", tc.file}
+			for _, f := range tc.filters {
+				args = append(args, "--context-filter", f)
+			}
+			cmd.SetArgs(append(args, dir.Path()))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
+
+func TestContextListWhyErrors(t *testing.T) {
+	dir := newTestContext(t)
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newContextListCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--why", "missing.txt", dir.Path()})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "missing.txt doesn't exist in the build context"))
+
+	cmd = newContextListCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--why", "../outside", dir.Path()})
+	assert.Check(t, is.Error(cmd.Execute(), "invalid path ../outside: it must be relative to the build context"))
+}
@@ -0,0 +1,110 @@
+package checkpoint
+
+import (
+	"context"
+	"path"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/inspect"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types/checkpoint"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type inspectOptions struct {
+	container     string
+	checkpoints   []string
+	checkpointDir string
+	format        string
+}
+
+// checkpointDetails is the information about a checkpoint that's shown by
+// "docker checkpoint inspect".
+type checkpointDetails struct {
+	Name      string
+	Container string
+	// Directory is the directory of the checkpoint on the daemon's host.
+	Directory string
+}
+
+func newInspectCommand(dockerCli command.Cli) *cobra.Command {
+	var opts inspectOptions
+
+	cmd := &cobra.Command{
+		Use:   "inspect [OPTIONS] CONTAINER CHECKPOINT [CHECKPOINT...]",
+		Short: "Display detailed information on one or more checkpoints",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			opts.checkpoints = args[1:]
+			return runInspect(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.ContainerNames(dockerCli, true)(cmd, args, toComplete)
+			}
+			return checkpointNames(cmd.Context(), dockerCli, args[0]), cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	flags.StringVar(&opts.checkpointDir, "checkpoint-dir", "", "Use a custom checkpoint storage directory")
+
+	return cmd
+}
+
+func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions) error {
+	apiClient := dockerCli.Client()
+	c, err := apiClient.ContainerInspect(ctx, opts.container)
+	if err != nil {
+		return err
+	}
+	checkpoints, err := apiClient.CheckpointList(ctx, c.ID, checkpoint.ListOptions{
+		CheckpointDir: opts.checkpointDir,
+	})
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(checkpoints))
+	for _, cp := range checkpoints {
+		exists[cp.Name] = true
+	}
+
+	// Checkpoints are stored in the directory of the container, unless a
+	// custom directory is used.
+	dir := opts.checkpointDir
+	if dir == "" && c.HostnamePath != "" {
+		dir = path.Join(path.Dir(c.HostnamePath), "checkpoints")
+	}
+
+	getRefFunc := func(name string) (any, []byte, error) {
+		if !exists[name] {
+			return nil, nil, errdefs.NotFound(errors.Errorf("no such checkpoint: %s", name))
+		}
+		details := checkpointDetails{Name: name, Container: c.ID}
+		if dir != "" {
+			details.Directory = path.Join(dir, name)
+		}
+		return details, nil, nil
+	}
+	return inspect.Inspect(dockerCli.Out(), opts.checkpoints, opts.format, getRefFunc)
+}
+
+// checkpointNames returns the names of the checkpoints of the container, for
+// completion.
+func checkpointNames(ctx context.Context, dockerCli command.Cli, container string) []string {
+	checkpoints, err := dockerCli.Client().CheckpointList(ctx, container, checkpoint.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(checkpoints))
+	for _, cp := range checkpoints {
+		names = append(names, cp.Name)
+	}
+	return names
+}
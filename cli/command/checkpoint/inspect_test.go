@@ -0,0 +1,48 @@
+package checkpoint
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/checkpoint"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestCheckpointInspect(t *testing.T) {
+	var checkpointDir string
+	cli := test.NewFakeCli(&fakeClient{
+		containerInspectFunc: func(container string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+				ID:           "container-id",
+				HostnamePath: "/var/lib/docker/containers/container-id/hostname",
+			}}, nil
+		},
+		checkpointListFunc: func(container string, options checkpoint.ListOptions) ([]checkpoint.Summary, error) {
+			assert.Check(t, is.Equal(container, "container-id"))
+			checkpointDir = options.CheckpointDir
+			return []checkpoint.Summary{{Name: "checkpoint-foo"}, {Name: "checkpoint-bar"}}, nil
+		},
+	})
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"container-foo", "checkpoint-foo"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "checkpoint-inspect.golden")
+
+	cli.OutBuffer().Reset()
+	cmd = newInspectCommand(cli)
+	cmd.SetArgs([]string{"--checkpoint-dir", "/dir/foo", "--format", "{{.Directory}}", "container-foo", "checkpoint-foo", "checkpoint-bar"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(checkpointDir, "/dir/foo"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "/dir/foo/checkpoint-foo\n/dir/foo/checkpoint-bar\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newInspectCommand(cli)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--format", "{{.Name}}", "container-foo", "checkpoint-foo", "checkpoint-baz"})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "no such checkpoint: checkpoint-baz"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "checkpoint-foo\n"))
+}
@@ -7,12 +7,14 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/docker/api/types/checkpoint"
 	"github.com/spf13/cobra"
 )
 
 type listOptions struct {
 	checkpointDir string
+	format        string
 }
 
 func newListCommand(dockerCli command.Cli) *cobra.Command {
@@ -31,6 +33,7 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.StringVar(&opts.checkpointDir, "checkpoint-dir", "", "Use a custom checkpoint storage directory")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
 
 	return cmd
 }
@@ -43,9 +46,13 @@ func runList(ctx context.Context, dockerCli command.Cli, container string, opts
 		return err
 	}
 
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
 	cpCtx := formatter.Context{
 		Output: dockerCli.Out(),
-		Format: NewFormat(formatter.TableFormatKey),
+		Format: NewFormat(format),
 	}
 	return FormatWrite(cpCtx, checkpoints)
 }
@@ -65,3 +65,21 @@ func TestCheckpointListWithOptions(t *testing.T) {
 	assert.Check(t, is.Equal("/dir/foo", checkpointDir))
 	golden.Assert(t, cli.OutBuffer().String(), "checkpoint-list-with-options.golden")
 }
+
+func TestCheckpointListFormat(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		checkpointListFunc: func(container string, options checkpoint.ListOptions) ([]checkpoint.Summary, error) {
+			return []checkpoint.Summary{{Name: "checkpoint-foo"}, {Name: "checkpoint-bar"}}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Name}}", "container-foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "checkpoint-foo\ncheckpoint-bar\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newListCommand(cli)
+	cmd.SetArgs([]string{"--format", "json", "container-foo"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "{\"Name\":\"checkpoint-foo\"}\n{\"Name\":\"checkpoint-bar\"}\n"))
+}
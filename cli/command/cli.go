@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/docker/cli/cli/color"
 	"github.com/docker/cli/cli/config"
 	"github.com/docker/cli/cli/config/configfile"
 	dcontext "github.com/docker/cli/cli/context"
@@ -240,7 +241,15 @@ func (cli *DockerCli) RegistryClient(allowInsecure bool) registryclient.Registry
 	resolver := func(ctx context.Context, index *registry.IndexInfo) registry.AuthConfig {
 		return ResolveAuthConfig(cli.ConfigFile(), index)
 	}
-	return registryclient.NewRegistryClient(resolver, UserAgent(), allowInsecure)
+	registryOptions := func(domain string) registryclient.RegistryOptions {
+		rc := cli.ConfigFile().Registries[domain]
+		return registryclient.RegistryOptions{
+			Mirrors:  rc.Mirrors,
+			Insecure: rc.Insecure,
+			CAFile:   rc.CAFile,
+		}
+	}
+	return registryclient.NewRegistryClient(resolver, UserAgent(), allowInsecure, registryclient.WithRegistryOptions(registryOptions))
 }
 
 // WithInitializeClient is passed to DockerCli.Initialize by callers who wish to set a particular API Client for use by the CLI.
@@ -269,12 +278,31 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 	if opts.Debug {
 		debug.Enable()
 	}
+	if err := color.SetMode(opts.Color); err != nil {
+		return err
+	}
+	if !opts.NoPager && cli.out != nil {
+		cli.out.SetPager(streams.DefaultPager())
+	}
+	level, err := outputLevel(opts)
+	if err != nil {
+		return err
+	}
+	if cli.out != nil {
+		cli.out.SetLevel(level)
+	}
+	if cli.err != nil {
+		cli.err.SetLevel(level)
+	}
 	if opts.Context != "" && len(opts.Hosts) > 0 {
 		return errors.New("conflicting options: either specify --host or --context, not both")
 	}
 
 	cli.options = opts
 	cli.configFile = config.LoadDefaultConfigFile(cli.err)
+	if err := color.SetTheme(cli.configFile.ColorTheme); err != nil {
+		_, _ = fmt.Fprintln(cli.err, "WARNING:", err)
+	}
 	cli.currentContext = resolveContextName(cli.options, cli.configFile)
 	cli.contextStore = &ContextStoreWithDefault{
 		Store: store.New(config.ContextStoreDir(), cli.contextStoreConfig),
@@ -290,6 +318,25 @@ func (cli *DockerCli) Initialize(opts *cliflags.ClientOptions, ops ...CLIOption)
 	return nil
 }
 
+// outputLevel returns the level of output set by the --quiet, --verbose, and
+// --debug options.
+func outputLevel(opts *cliflags.ClientOptions) (streams.Level, error) {
+	switch {
+	case opts.Quiet && opts.Debug:
+		return streams.LevelNormal, errors.New("conflicting options: --quiet and --debug")
+	case opts.Quiet && opts.Verbose:
+		return streams.LevelNormal, errors.New("conflicting options: --quiet and --verbose")
+	case opts.Quiet:
+		return streams.LevelQuiet, nil
+	case opts.Debug:
+		return streams.LevelDebug, nil
+	case opts.Verbose:
+		return streams.LevelVerbose, nil
+	default:
+		return streams.LevelNormal, nil
+	}
+}
+
 // NewAPIClientFromFlags creates a new APIClient from command line flags
 func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.ConfigFile) (client.APIClient, error) {
 	if opts.Context != "" && len(opts.Hosts) > 0 {
@@ -310,6 +357,16 @@ func NewAPIClientFromFlags(opts *cliflags.ClientOptions, configFile *configfile.
 	return newAPIClientFromEndpoint(endpoint, configFile)
 }
 
+// NewAPIClientForContext creates a new APIClient for the Docker endpoint of
+// the context with the given name, which may differ from the current context.
+func NewAPIClientForContext(s store.Reader, contextName string, configFile *configfile.ConfigFile) (client.APIClient, error) {
+	endpoint, err := resolveDockerEndpoint(s, contextName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to resolve docker endpoint of context %q", contextName)
+	}
+	return newAPIClientFromEndpoint(endpoint, configFile)
+}
+
 func newAPIClientFromEndpoint(ep docker.Endpoint, configFile *configfile.ConfigFile) (client.APIClient, error) {
 	opts, err := ep.ClientOpts()
 	if err != nil {
@@ -413,9 +470,11 @@ func (cli *DockerCli) ContextStore() store.Store {
 //
 //  1. The "--context" command-line option.
 //  2. The "DOCKER_CONTEXT" environment variable ([EnvOverrideContext]).
-//  3. The current context as configured through the in "currentContext"
+//  3. The first of the "contextRules" in the CLI configuration file that
+//     matches the working directory (see [MatchContextRule]).
+//  4. The current context as configured through the in "currentContext"
 //     field in the CLI configuration file ("~/.docker/config.json").
-//  4. If no context is configured, use the "default" context.
+//  5. If no context is configured, use the "default" context.
 //
 // # Fallbacks for backward-compatibility
 //
@@ -457,6 +516,13 @@ func resolveContextName(opts *cliflags.ClientOptions, cfg *configfile.ConfigFile
 	if ctxName := os.Getenv(EnvOverrideContext); ctxName != "" {
 		return ctxName
 	}
+	if cfg != nil && len(cfg.ContextRules) > 0 {
+		if wd, err := os.Getwd(); err == nil {
+			if i := MatchContextRule(cfg.ContextRules, wd); i >= 0 {
+				return cfg.ContextRules[i].Context
+			}
+		}
+	}
 	if cfg != nil && cfg.CurrentContext != "" {
 		// We don't validate if this context exists: errors may occur when trying to use it.
 		return cfg.CurrentContext
@@ -493,6 +559,9 @@ func (cli *DockerCli) initialize() error {
 			if cli.client, cli.initErr = newAPIClientFromEndpoint(cli.dockerEndpoint, cli.configFile); cli.initErr != nil {
 				return
 			}
+			if cli.err != nil && cli.err.Level() >= streams.LevelVerbose {
+				traceAPIRequests(cli.client, cli.err)
+			}
 		}
 		if cli.baseCtx == nil {
 			cli.baseCtx = context.Background()
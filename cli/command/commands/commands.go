@@ -4,16 +4,19 @@ import (
 	"os"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/alias"
 	"github.com/docker/cli/cli/command/builder"
 	"github.com/docker/cli/cli/command/checkpoint"
 	"github.com/docker/cli/cli/command/config"
 	"github.com/docker/cli/cli/command/container"
 	"github.com/docker/cli/cli/command/context"
+	"github.com/docker/cli/cli/command/helper"
 	"github.com/docker/cli/cli/command/image"
 	"github.com/docker/cli/cli/command/manifest"
 	"github.com/docker/cli/cli/command/network"
 	"github.com/docker/cli/cli/command/node"
 	"github.com/docker/cli/cli/command/plugin"
+	"github.com/docker/cli/cli/command/plugincli"
 	"github.com/docker/cli/cli/command/registry"
 	"github.com/docker/cli/cli/command/secret"
 	"github.com/docker/cli/cli/command/service"
@@ -41,16 +44,22 @@ func AddCommands(cmd *cobra.Command, dockerCli command.Cli) {
 		registry.NewSearchCommand(dockerCli),
 		system.NewVersionCommand(dockerCli),
 		system.NewInfoCommand(dockerCli),
+		system.NewDoctorCommand(dockerCli),
 
 		// management commands
+		alias.NewAliasCommand(dockerCli),
+		registry.NewAuthCommand(dockerCli),
 		builder.NewBuilderCommand(dockerCli),
 		checkpoint.NewCheckpointCommand(dockerCli),
 		container.NewContainerCommand(dockerCli),
 		context.NewContextCommand(dockerCli),
+		helper.NewHelperCommand(dockerCli),
 		image.NewImageCommand(dockerCli),
 		manifest.NewManifestCommand(dockerCli),
 		network.NewNetworkCommand(dockerCli),
 		plugin.NewPluginCommand(dockerCli),
+		plugincli.NewPluginCLICommand(dockerCli),
+		registry.NewRegistryCommand(dockerCli),
 		system.NewSystemCommand(dockerCli),
 		trust.NewTrustCommand(dockerCli),
 		volume.NewVolumeCommand(dockerCli),
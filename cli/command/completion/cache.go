@@ -0,0 +1,77 @@
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/ioutils"
+)
+
+const (
+	// cacheTTLEnvVar is the name of the environment variable that overrides
+	// the time for which the results of the API calls made for completion
+	// are cached. Setting it to "0" disables the cache.
+	cacheTTLEnvVar = "DOCKER_COMPLETION_CACHE_TTL"
+
+	// defaultRemoteCacheTTL is the time for which the results are cached for
+	// daemons that are not reached through a local socket or named pipe.
+	// Completing against a remote daemon (for example, over SSH) takes a
+	// round-trip for every key-press otherwise.
+	defaultRemoteCacheTTL = 30 * time.Second
+
+	cacheDirName = "completion-cache"
+)
+
+// cacheTTL returns the time for which the results of the API calls made for
+// completion against the daemon at host are cached.
+func cacheTTL(host string) time.Duration {
+	if v, ok := os.LookupEnv(cacheTTLEnvVar); ok {
+		if ttl, err := time.ParseDuration(v); err == nil {
+			return ttl
+		}
+		return 0
+	}
+	if strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://") {
+		return 0
+	}
+	return defaultRemoteCacheTTL
+}
+
+// cacheFile returns the path of the file in which the objects of the given
+// kind of the daemon at host are cached.
+func cacheFile(host, kind string) string {
+	sum := sha256.Sum256([]byte(host))
+	return filepath.Join(config.Dir(), cacheDirName, hex.EncodeToString(sum[:8])+"-"+kind+".json")
+}
+
+// cachedList loads the cached objects of the given kind of the daemon at host
+// into v, which must be a pointer. If they are not cached, or the cache
+// expired, it calls list, which must store the objects in v, and caches them.
+func cachedList(host, kind string, v any, list func() error) error {
+	ttl := cacheTTL(host)
+	if ttl <= 0 {
+		return list()
+	}
+	fileName := cacheFile(host, kind)
+	if fi, err := os.Stat(fileName); err == nil && time.Since(fi.ModTime()) < ttl {
+		if data, err := os.ReadFile(fileName); err == nil && json.Unmarshal(data, v) == nil {
+			return nil
+		}
+	}
+	if err := list(); err != nil {
+		return err
+	}
+	// Failing to write the cache only makes the next completion slower.
+	if data, err := json.Marshal(v); err == nil {
+		if err := os.MkdirAll(filepath.Dir(fileName), 0o700); err == nil {
+			_ = ioutils.AtomicWriteFile(fileName, data, 0o600)
+		}
+	}
+	return nil
+}
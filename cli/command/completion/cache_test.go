@@ -0,0 +1,75 @@
+package completion
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCacheTTL(t *testing.T) {
+	tests := []struct {
+		host     string
+		env      string
+		expected time.Duration
+	}{
+		{host: "unix:///var/run/docker.sock", expected: 0},
+		{host: "npipe:////./pipe/docker_engine", expected: 0},
+		{host: "ssh://me@example.com", expected: defaultRemoteCacheTTL},
+		{host: "tcp://example.com:2376", env: "1m", expected: time.Minute},
+		{host: "unix:///var/run/docker.sock", env: "10s", expected: 10 * time.Second},
+		{host: "tcp://example.com:2376", env: "0", expected: 0},
+		{host: "tcp://example.com:2376", env: "forever", expected: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.host+"/"+tc.env, func(t *testing.T) {
+			if tc.env != "" {
+				t.Setenv(cacheTTLEnvVar, tc.env)
+			}
+			assert.Check(t, is.Equal(cacheTTL(tc.host), tc.expected))
+		})
+	}
+}
+
+func TestCachedList(t *testing.T) {
+	config.SetDir(t.TempDir())
+	const host = "ssh://me@example.com"
+
+	calls := 0
+	list := func(v *[]string, names ...string) func() error {
+		return func() error {
+			calls++
+			*v = names
+			return nil
+		}
+	}
+
+	var names []string
+	assert.NilError(t, cachedList(host, "volumes", &names, list(&names, "one", "two")))
+	assert.Check(t, is.DeepEqual(names, []string{"one", "two"}))
+
+	// The cached names are returned until the cache expires.
+	names = nil
+	assert.NilError(t, cachedList(host, "volumes", &names, list(&names, "three")))
+	assert.Check(t, is.DeepEqual(names, []string{"one", "two"}))
+	assert.Check(t, is.Equal(calls, 1))
+
+	// Each daemon has its own cache.
+	names = nil
+	assert.NilError(t, cachedList("tcp://example.com:2376", "volumes", &names, list(&names, "three")))
+	assert.Check(t, is.DeepEqual(names, []string{"three"}))
+	assert.Check(t, is.Equal(calls, 2))
+
+	// Errors are not cached.
+	err := cachedList(host, "networks", &names, func() error { return errors.New("no daemon") })
+	assert.Check(t, is.Error(err, "no daemon"))
+
+	// The cache is not used if it's disabled.
+	t.Setenv(cacheTTLEnvVar, "0")
+	names = nil
+	assert.NilError(t, cachedList(host, "volumes", &names, list(&names, "four")))
+	assert.Check(t, is.DeepEqual(names, []string{"four"}))
+}
@@ -1,15 +1,23 @@
 package completion
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
+	"strings"
 
+	"github.com/containerd/platforms"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -26,10 +34,21 @@ type APIClientProvider interface {
 	Client() client.APIClient
 }
 
+// ContextStoreProvider provides a method to get the [store.Store] of the
+// contexts.
+type ContextStoreProvider interface {
+	ContextStore() store.Store
+}
+
 // ImageNames offers completion for images present within the local store
 func ImageNames(dockerCLI APIClientProvider) ValidArgsFn {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := dockerCLI.Client().ImageList(cmd.Context(), image.ListOptions{})
+		apiClient := dockerCLI.Client()
+		var list []image.Summary
+		err := cachedList(apiClient.DaemonHost(), "images", &list, func() (err error) {
+			list, err = apiClient.ImageList(cmd.Context(), image.ListOptions{})
+			return err
+		})
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
@@ -46,8 +65,17 @@ func ImageNames(dockerCLI APIClientProvider) ValidArgsFn {
 // Set DOCKER_COMPLETION_SHOW_CONTAINER_IDS=yes to also complete IDs.
 func ContainerNames(dockerCLI APIClientProvider, all bool, filters ...func(types.Container) bool) ValidArgsFn {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := dockerCLI.Client().ContainerList(cmd.Context(), container.ListOptions{
-			All: all,
+		kind := "containers"
+		if all {
+			kind = "containers-all"
+		}
+		apiClient := dockerCLI.Client()
+		var list []types.Container
+		err := cachedList(apiClient.DaemonHost(), kind, &list, func() (err error) {
+			list, err = apiClient.ContainerList(cmd.Context(), container.ListOptions{
+				All: all,
+			})
+			return err
 		})
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
@@ -79,7 +107,12 @@ func ContainerNames(dockerCLI APIClientProvider, all bool, filters ...func(types
 // VolumeNames offers completion for volumes
 func VolumeNames(dockerCLI APIClientProvider) ValidArgsFn {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := dockerCLI.Client().VolumeList(cmd.Context(), volume.ListOptions{})
+		apiClient := dockerCLI.Client()
+		var list volume.ListResponse
+		err := cachedList(apiClient.DaemonHost(), "volumes", &list, func() (err error) {
+			list, err = apiClient.VolumeList(cmd.Context(), volume.ListOptions{})
+			return err
+		})
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
@@ -94,7 +127,12 @@ func VolumeNames(dockerCLI APIClientProvider) ValidArgsFn {
 // NetworkNames offers completion for networks
 func NetworkNames(dockerCLI APIClientProvider) ValidArgsFn {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := dockerCLI.Client().NetworkList(cmd.Context(), network.ListOptions{})
+		apiClient := dockerCLI.Client()
+		var list []network.Summary
+		err := cachedList(apiClient.DaemonHost(), "networks", &list, func() (err error) {
+			list, err = apiClient.NetworkList(cmd.Context(), network.ListOptions{})
+			return err
+		})
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
@@ -106,6 +144,186 @@ func NetworkNames(dockerCLI APIClientProvider) ValidArgsFn {
 	}
 }
 
+// ServiceNames offers completion for swarm services
+// By default, only names are returned.
+// Set DOCKER_COMPLETION_SHOW_SERVICE_IDS=yes to also complete IDs.
+func ServiceNames(dockerCLI APIClientProvider) ValidArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		apiClient := dockerCLI.Client()
+		var list []swarm.Service
+		err := cachedList(apiClient.DaemonHost(), "services", &list, func() (err error) {
+			list, err = apiClient.ServiceList(cmd.Context(), types.ServiceListOptions{})
+			return err
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		showServiceIDs := os.Getenv("DOCKER_COMPLETION_SHOW_SERVICE_IDS") == "yes"
+
+		var names []string
+		for _, service := range list {
+			if showServiceIDs {
+				names = append(names, service.ID)
+			}
+			names = append(names, service.Spec.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// ContextNames offers completion for contexts
+func ContextNames(dockerCLI ContextStoreProvider) ValidArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names, err := store.Names(dockerCLI.ContextStore())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// VolumeDrivers offers completion for the volume drivers of the daemon
+func VolumeDrivers(dockerCLI APIClientProvider) ValidArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		apiClient := dockerCLI.Client()
+		var plugins system.PluginsInfo
+		err := cachedList(apiClient.DaemonHost(), "volume-drivers", &plugins, func() error {
+			info, err := apiClient.Info(cmd.Context())
+			plugins = info.Plugins
+			return err
+		})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return plugins.Volume, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// commonPlatforms are offered for completion of the "--platform" flag if
+// the platforms of the image are not known.
+var commonPlatforms = []string{
+	"linux/386",
+	"linux/amd64",
+	"linux/arm/v6",
+	"linux/arm/v7",
+	"linux/arm64",
+	"linux/ppc64le",
+	"linux/riscv64",
+	"linux/s390x",
+	"windows/amd64",
+}
+
+// Platforms offers completion for the "--platform" flag: the platforms of the
+// image given as first argument, as listed by the registry, or commonly used
+// platforms if there's no image, or its platforms cannot be determined.
+func Platforms(dockerCLI APIClientProvider) ValidArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return commonPlatforms, cobra.ShellCompDirectiveNoFileComp
+		}
+		ref := args[0]
+		apiClient := dockerCLI.Client()
+		sum := sha256.Sum256([]byte(ref))
+		var list []ocispec.Platform
+		err := cachedList(apiClient.DaemonHost(), "platforms-"+hex.EncodeToString(sum[:8]), &list, func() error {
+			// Only public images can be inspected without credentials, but
+			// the commonly used platforms are offered for private images.
+			res, err := apiClient.DistributionInspect(cmd.Context(), ref, "")
+			list = res.Platforms
+			return err
+		})
+		if err != nil || len(list) == 0 {
+			return commonPlatforms, cobra.ShellCompDirectiveNoFileComp
+		}
+		var names []string
+		for _, p := range list {
+			if p.OS == "unknown" {
+				// Attestation manifests.
+				continue
+			}
+			names = append(names, platforms.Format(p))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// addableCapabilities are the Linux capabilities that are not granted to
+// containers by default.
+var addableCapabilities = []string{
+	"ALL",
+	"CAP_AUDIT_CONTROL",
+	"CAP_AUDIT_READ",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_LEASE",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_MAC_ADMIN",
+	"CAP_MAC_OVERRIDE",
+	"CAP_NET_ADMIN",
+	"CAP_NET_BROADCAST",
+	"CAP_PERFMON",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYSLOG",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_NICE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_WAKE_ALARM",
+}
+
+// droppableCapabilities are the Linux capabilities that are granted to
+// containers by default.
+var droppableCapabilities = []string{
+	"ALL",
+	"CAP_AUDIT_WRITE",
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_MKNOD",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_RAW",
+	"CAP_SETFCAP",
+	"CAP_SETGID",
+	"CAP_SETPCAP",
+	"CAP_SETUID",
+	"CAP_SYS_CHROOT",
+}
+
+// CapabilitiesAddable offers completion for the "--cap-add" flag
+func CapabilitiesAddable(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return capabilities(addableCapabilities, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CapabilitiesDroppable offers completion for the "--cap-drop" flag
+func CapabilitiesDroppable(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return capabilities(droppableCapabilities, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// capabilities returns the capabilities with the "CAP_" prefix, which is
+// optional, if toComplete has it, and without it otherwise.
+func capabilities(caps []string, toComplete string) []string {
+	if strings.HasPrefix(strings.ToUpper(toComplete), "CAP_") {
+		return caps
+	}
+	names := make([]string, 0, len(caps))
+	for _, c := range caps {
+		names = append(names, strings.TrimPrefix(c, "CAP_"))
+	}
+	return names
+}
+
 // NoComplete is used for commands where there's no relevant completion
 func NoComplete(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 	return nil, cobra.ShellCompDirectiveNoFileComp
@@ -0,0 +1,21 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCapabilitiesAddable(t *testing.T) {
+	names, _ := CapabilitiesAddable(nil, nil, "")
+	assert.Check(t, is.Contains(names, "SYS_ADMIN"))
+	for _, name := range names {
+		assert.Check(t, !strings.HasPrefix(name, "CAP_"), name)
+	}
+
+	names, _ = CapabilitiesAddable(nil, nil, "cap_")
+	assert.Check(t, is.Contains(names, "CAP_SYS_ADMIN"))
+	assert.Check(t, is.Contains(names, "ALL"))
+}
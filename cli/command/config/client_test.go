@@ -14,6 +14,8 @@ type fakeClient struct {
 	configInspectFunc func(context.Context, string) (swarm.Config, []byte, error)
 	configListFunc    func(context.Context, types.ConfigListOptions) ([]swarm.Config, error)
 	configRemoveFunc  func(string) error
+	serviceListFunc   func(context.Context, types.ServiceListOptions) ([]swarm.Service, error)
+	serviceUpdateFunc func(context.Context, string, swarm.Version, swarm.ServiceSpec, types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error)
 }
 
 func (c *fakeClient) ConfigCreate(ctx context.Context, spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
@@ -43,3 +45,17 @@ func (c *fakeClient) ConfigRemove(_ context.Context, name string) error {
 	}
 	return nil
 }
+
+func (c *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+	if c.serviceListFunc != nil {
+		return c.serviceListFunc(ctx, options)
+	}
+	return []swarm.Service{}, nil
+}
+
+func (c *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
+	if c.serviceUpdateFunc != nil {
+		return c.serviceUpdateFunc(ctx, serviceID, version, service, options)
+	}
+	return swarm.ServiceUpdateResponse{}, nil
+}
@@ -23,8 +23,10 @@ func NewConfigCommand(dockerCli command.Cli) *cobra.Command {
 	cmd.AddCommand(
 		newConfigListCommand(dockerCli),
 		newConfigCreateCommand(dockerCli),
+		newConfigEditCommand(dockerCli),
 		newConfigInspectCommand(dockerCli),
 		newConfigRemoveCommand(dockerCli),
+		newFormatCommand(dockerCli),
 	)
 	return cmd
 }
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type editOptions struct {
+	config         string
+	name           string
+	rotateServices bool
+}
+
+func newConfigEditCommand(dockerCli command.Cli) *cobra.Command {
+	var options editOptions
+
+	cmd := &cobra.Command{
+		Use:   "edit [OPTIONS] CONFIG",
+		Short: "Edit the content of a config in a new version of the config",
+		Long: `Edit the content of a config in the editor set by the VISUAL or EDITOR
+environment variables. As configs can't be updated, the edited content is
+stored in a new config, named after the version of the config ("NAME-v2",
+"NAME-v3", and so on), with the labels of the config.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.config = args[0]
+			return runConfigEdit(cmd.Context(), dockerCli, options)
+		},
+		ValidArgsFunction: completeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.name, "name", "", "Name of the new config (default: the next version of the name of the config)")
+	flags.BoolVar(&options.rotateServices, "rotate-services", false, "Update the services that use the config to use the new config")
+	return cmd
+}
+
+func runConfigEdit(ctx context.Context, dockerCli command.Cli, options editOptions) error {
+	apiClient := dockerCli.Client()
+
+	config, _, err := apiClient.ConfigInspectWithRaw(ctx, options.config)
+	if err != nil {
+		return err
+	}
+	data, err := command.EditContent(config.Spec.Name, config.Spec.Data)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, config.Spec.Data) {
+		dockerCli.Err().Infof("No changes to config %s\n", config.Spec.Name)
+		return nil
+	}
+	if len(data) == 0 {
+		return errors.New("the edited config is empty, no config was created")
+	}
+
+	spec := config.Spec
+	spec.Data = data
+	spec.Name = options.name
+	if spec.Name == "" {
+		spec.Name = command.NextVersionName(config.Spec.Name)
+	}
+	r, err := apiClient.ConfigCreate(ctx, spec)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), r.ID)
+
+	if !options.rotateServices {
+		return nil
+	}
+	return command.RotateServices(ctx, dockerCli, func(cspec *swarm.ContainerSpec) bool {
+		var uses bool
+		for _, ref := range cspec.Configs {
+			if ref.ConfigID == config.ID {
+				ref.ConfigID, ref.ConfigName = r.ID, spec.Name
+				uses = true
+			}
+		}
+		return uses
+	})
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestConfigEdit(t *testing.T) {
+	test.SetEditor(t, "new")
+	config := swarm.Config{
+		ID: "config-id",
+		Spec: swarm.ConfigSpec{
+			Annotations: swarm.Annotations{Name: "app", Labels: map[string]string{"env": "prod"}},
+			Data:        []byte("old"),
+		},
+	}
+	var created swarm.ConfigSpec
+	var updated []swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(context.Context, string) (swarm.Config, []byte, error) {
+			return config, nil, nil
+		},
+		configCreateFunc: func(_ context.Context, spec swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+			created = spec
+			return types.ConfigCreateResponse{ID: "new-id"}, nil
+		},
+		serviceListFunc: func(context.Context, types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{
+				{Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "web"},
+					TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+						Configs: []*swarm.ConfigReference{{ConfigID: "config-id", ConfigName: "app", File: &swarm.ConfigReferenceFileTarget{Name: "/app.conf"}}},
+					}},
+				}},
+				{Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "db"}, TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}}}},
+			}, nil
+		},
+		serviceUpdateFunc: func(_ context.Context, _ string, _ swarm.Version, spec swarm.ServiceSpec, _ types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
+			updated = append(updated, spec)
+			return swarm.ServiceUpdateResponse{}, nil
+		},
+	})
+
+	cmd := newConfigEditCommand(cli)
+	cmd.SetArgs([]string{"--rotate-services", "app"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(created.Name, "app-v2"))
+	assert.Check(t, is.Equal(string(created.Data), "new"))
+	assert.Check(t, is.DeepEqual(created.Labels, map[string]string{"env": "prod"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "new-id\nUpdating service web\n"))
+	assert.Assert(t, is.Len(updated, 1))
+	assert.Check(t, is.DeepEqual(updated[0].TaskTemplate.ContainerSpec.Configs, []*swarm.ConfigReference{
+		{ConfigID: "new-id", ConfigName: "app-v2", File: &swarm.ConfigReferenceFileTarget{Name: "/app.conf"}},
+	}))
+}
+
+func TestConfigEditNoChanges(t *testing.T) {
+	test.SetEditor(t, "old")
+	cli := test.NewFakeCli(&fakeClient{
+		configInspectFunc: func(context.Context, string) (swarm.Config, []byte, error) {
+			return swarm.Config{Spec: swarm.ConfigSpec{Annotations: swarm.Annotations{Name: "app"}, Data: []byte("old")}}, nil, nil
+		},
+		configCreateFunc: func(context.Context, swarm.ConfigSpec) (types.ConfigCreateResponse, error) {
+			t.Fatal("no config must be created")
+			return types.ConfigCreateResponse{}, nil
+		},
+	})
+	cmd := newConfigEditCommand(cli)
+	cmd.SetArgs([]string{"app"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "No changes to config app\n"))
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/templates"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// newFormatCommand returns a cobra command for `config format` subcommands.
+//
+// Format presets are stored in the CLI configuration file, and not in the
+// swarm, so these commands don't require a connection with the daemon.
+func newFormatCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "format",
+		Aliases: []string{"formats"},
+		Short:   "Manage format presets for the --format option",
+		Args:    cli.NoArgs,
+		RunE:    command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newFormatSaveCommand(dockerCli),
+		newFormatListCommand(dockerCli),
+		newFormatRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+func newFormatSaveCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "save NAME FORMAT",
+		Short: "Save a format preset",
+		Example: `$ docker config format save minimal 'table {{.Names}}\t{{.Status}}'
+$ docker ps --format preset:minimal`,
+		Args: cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFormatSave(dockerCli, args[0], args[1])
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+}
+
+func runFormatSave(dockerCli command.Cli, name, format string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return errors.Errorf("invalid format preset name %q: must not be empty or contain whitespace", name)
+	}
+	if command.IsFormatPreset(format) {
+		return errors.New("a format preset cannot refer to another format preset")
+	}
+	tmpl, err := formatter.ExpandJSONPath(format)
+	if err != nil {
+		return err
+	}
+	if _, err := templates.Parse(tmpl); err != nil {
+		return errors.Errorf("template parsing error: %v", err)
+	}
+
+	cfg := dockerCli.ConfigFile()
+	if cfg.Formats == nil {
+		cfg.Formats = map[string]string{}
+	}
+	cfg.Formats[name] = format
+	if err := cfg.Save(); err != nil {
+		return errors.Wrap(err, "failed to save format preset")
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), name)
+	return nil
+}
+
+func newFormatListCommand(dockerCli command.Cli) *cobra.Command {
+	var quiet bool
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List format presets",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFormatList(dockerCli, quiet)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only display preset names")
+	return cmd
+}
+
+func runFormatList(dockerCli command.Cli, quiet bool) error {
+	formats := dockerCli.ConfigFile().Formats
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if quiet {
+		for _, name := range names {
+			_, _ = fmt.Fprintln(dockerCli.Out(), name)
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(dockerCli.Out(), 20, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tFORMAT")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", name, formats[name])
+	}
+	return w.Flush()
+}
+
+func newFormatRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm NAME [NAME...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more format presets",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFormatRemove(dockerCli, args)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			names := make([]string, 0, len(dockerCli.ConfigFile().Formats))
+			for name := range dockerCli.ConfigFile().Formats {
+				names = append(names, name)
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+}
+
+func runFormatRemove(dockerCli command.Cli, names []string) error {
+	cfg := dockerCli.ConfigFile()
+
+	var removed, errs []string
+	for _, name := range names {
+		if _, ok := cfg.Formats[name]; !ok {
+			errs = append(errs, fmt.Sprintf("format preset %q not found", name))
+			continue
+		}
+		delete(cfg.Formats, name)
+		removed = append(removed, name)
+	}
+	if len(removed) > 0 {
+		if err := cfg.Save(); err != nil {
+			return errors.Wrap(err, "failed to remove format preset")
+		}
+	}
+	for _, name := range removed {
+		_, _ = fmt.Fprintln(dockerCli.Out(), name)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func newFormatTestCli(t *testing.T, formats map[string]string) *test.FakeCli {
+	t.Helper()
+	cli := test.NewFakeCli(&fakeClient{})
+	cfg := configfile.New(filepath.Join(t.TempDir(), "config.json"))
+	cfg.Formats = formats
+	cli.SetConfigFile(cfg)
+	return cli
+}
+
+func TestFormatSave(t *testing.T) {
+	cli := newFormatTestCli(t, nil)
+	cmd := newFormatCommand(cli)
+	cmd.SetArgs([]string{"save", "minimal", `table {{.Names}}\t{{.Status}}`})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "minimal\n"))
+
+	content, err := os.ReadFile(cli.ConfigFile().Filename)
+	assert.NilError(t, err)
+	var saved configfile.ConfigFile
+	assert.NilError(t, json.Unmarshal(content, &saved))
+	assert.Check(t, is.DeepEqual(saved.Formats, map[string]string{"minimal": `table {{.Names}}\t{{.Status}}`}))
+}
+
+func TestFormatSaveErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"save", "minimal"},
+			expectedError: "requires exactly 2 arguments",
+		},
+		{
+			args:          []string{"save", "two words", "{{.ID}}"},
+			expectedError: `invalid format preset name "two words"`,
+		},
+		{
+			args:          []string{"save", "minimal", "{{.ID"},
+			expectedError: "template parsing error",
+		},
+		{
+			args:          []string{"save", "minimal", "preset:other"},
+			expectedError: "a format preset cannot refer to another format preset",
+		},
+		{
+			args:          []string{"save", "minimal", "name=other"},
+			expectedError: "a format preset cannot refer to another format preset",
+		},
+	}
+	for _, tc := range testCases {
+		cli := newFormatTestCli(t, nil)
+		cmd := newFormatCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		assert.Check(t, is.Len(cli.ConfigFile().Formats, 0))
+	}
+}
+
+func TestFormatList(t *testing.T) {
+	cli := newFormatTestCli(t, map[string]string{
+		"wide":    `table {{.Names}}\t{{.Image}}\t{{.Ports}}`,
+		"minimal": `table {{.Names}}\t{{.Status}}`,
+	})
+	cmd := newFormatCommand(cli)
+	cmd.SetArgs([]string{"ls"})
+	assert.NilError(t, cmd.Execute())
+	const expected = `NAME                FORMAT
+minimal             table {{.Names}}\t{{.Status}}
+wide                table {{.Names}}\t{{.Image}}\t{{.Ports}}
+`
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), expected))
+
+	cli.OutBuffer().Reset()
+	cmd = newFormatCommand(cli)
+	cmd.SetArgs([]string{"ls", "-q"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "minimal\nwide\n"))
+}
+
+func TestFormatRemove(t *testing.T) {
+	cli := newFormatTestCli(t, map[string]string{
+		"wide":    `table {{.Names}}\t{{.Image}}\t{{.Ports}}`,
+		"minimal": `table {{.Names}}\t{{.Status}}`,
+	})
+	cmd := newFormatCommand(cli)
+	cmd.SetArgs([]string{"rm", "minimal", "nosuchpreset"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `format preset "nosuchpreset" not found`))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "minimal\n"))
+	assert.Check(t, is.DeepEqual(cli.ConfigFile().Formats, map[string]string{
+		"wide": `table {{.Names}}\t{{.Image}}\t{{.Ports}}`,
+	}))
+}
@@ -0,0 +1,326 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	yaml "gopkg.in/yaml.v2"
+)
+
+type applyOptions struct {
+	file   string
+	dryRun bool
+}
+
+// containerSpec is the declarative specification of a container, as read by
+// "docker container apply".
+type containerSpec struct {
+	Name    string            `yaml:"name"`
+	Image   string            `yaml:"image"`
+	Command []string          `yaml:"command,omitempty"`
+	Ports   []string          `yaml:"ports,omitempty"`
+	Env     map[string]string `yaml:"env,omitempty"`
+	Mounts  []string          `yaml:"mounts,omitempty"`
+	Restart string            `yaml:"restart,omitempty"`
+}
+
+// newApplyCommand creates a new cobra.Command for `docker container apply`
+func newApplyCommand(dockerCli command.Cli) *cobra.Command {
+	var opts applyOptions
+
+	cmd := &cobra.Command{
+		Use:   "apply [OPTIONS]",
+		Short: "Create or update a container from a specification file",
+		Long: `Create or update a container from a specification file, which sets the name,
+image, command, ports, environment variables, mounts, and restart policy of
+the container.
+
+If a container with the name of the specification exists, its configuration
+is compared with the specification, and the container is recreated if they
+differ. The differences are printed before the container is recreated.`,
+		Args: cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runApply(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.file, "file", "f", "", `Specification file ("-" to read from STDIN)`)
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Only print the differences, without changing the container")
+	_ = cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func runApply(ctx context.Context, dockerCli command.Cli, opts applyOptions) error {
+	spec, err := loadContainerSpec(dockerCli.In(), opts.file)
+	if err != nil {
+		return err
+	}
+	containerCfg, err := containerConfigFromSpec(spec, dockerCli.ServerInfo().OSType)
+	if err != nil {
+		return errors.Wrapf(err, "invalid specification %s", opts.file)
+	}
+
+	apiClient := dockerCli.Client()
+	current, err := apiClient.ContainerInspect(ctx, spec.Name)
+	switch {
+	case errdefs.IsNotFound(err):
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Container %s doesn't exist\n", spec.Name)
+		if opts.dryRun {
+			return nil
+		}
+		return createFromSpec(ctx, dockerCli, spec, containerCfg)
+	case err != nil:
+		return err
+	}
+
+	var imageEnv []string
+	if img, _, err := apiClient.ImageInspectWithRaw(ctx, current.Image); err == nil && img.Config != nil {
+		imageEnv = img.Config.Env
+	}
+	diff := specDiff(
+		canonicalSpec(current.Config, current.HostConfig, imageEnv),
+		canonicalSpec(containerCfg.Config, containerCfg.HostConfig, imageEnv),
+		spec.Command != nil,
+	)
+	if len(diff) == 0 {
+		dockerCli.Err().Infof("Container %s is up to date\n", spec.Name)
+		if opts.dryRun || current.State.Running {
+			return nil
+		}
+		return apiClient.ContainerStart(ctx, current.ID, container.StartOptions{})
+	}
+
+	for _, line := range diff {
+		_, _ = fmt.Fprintln(dockerCli.Out(), line)
+	}
+	if opts.dryRun {
+		return nil
+	}
+	dockerCli.Err().Infof("Recreating container %s\n", spec.Name)
+	if err := apiClient.ContainerRemove(ctx, current.ID, container.RemoveOptions{Force: true}); err != nil {
+		return err
+	}
+	return createFromSpec(ctx, dockerCli, spec, containerCfg)
+}
+
+// loadContainerSpec reads the specification of a container from the given
+// file, or from in if the file is "-".
+func loadContainerSpec(in io.Reader, file string) (containerSpec, error) {
+	var (
+		b   []byte
+		err error
+		dir = filepath.Dir(file)
+	)
+	if file == "-" {
+		b, err = io.ReadAll(in)
+		dir = "."
+	} else {
+		b, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return containerSpec{}, err
+	}
+
+	var spec containerSpec
+	if err := yaml.UnmarshalStrict(b, &spec); err != nil {
+		return containerSpec{}, errors.Wrapf(err, "invalid specification %s", file)
+	}
+	if spec.Name == "" {
+		return containerSpec{}, errors.Errorf("invalid specification %s: name is required", file)
+	}
+	if spec.Image == "" {
+		return containerSpec{}, errors.Errorf("invalid specification %s: image is required", file)
+	}
+
+	// Relative host paths of bind mounts are relative to the directory of
+	// the specification, instead of the working directory.
+	for i, m := range spec.Mounts {
+		if source, target, ok := strings.Cut(m, ":"); ok && (source == "." || strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")) {
+			if abs, err := filepath.Abs(filepath.Join(dir, source)); err == nil {
+				spec.Mounts[i] = abs + ":" + target
+			}
+		}
+	}
+	return spec, nil
+}
+
+// containerConfigFromSpec returns the configuration of the container of spec,
+// as created by "docker run" with the equivalent options.
+func containerConfigFromSpec(spec containerSpec, serverOS string) (*containerConfig, error) {
+	flags := pflag.NewFlagSet("apply", pflag.ContinueOnError)
+	copts := addFlags(flags)
+	copts.Image = spec.Image
+	copts.Args = spec.Command
+
+	set := func(name, value string) error {
+		if err := flags.Set(name, value); err != nil {
+			return errors.Wrapf(err, "invalid %s %q", name, value)
+		}
+		return nil
+	}
+	for _, p := range spec.Ports {
+		if err := set("publish", p); err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range sortedKeys(spec.Env) {
+		if err := set("env", k+"="+spec.Env[k]); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range spec.Mounts {
+		name := "volume"
+		if strings.Contains(m, "=") {
+			name = "mount"
+		}
+		if err := set(name, m); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Restart != "" {
+		if err := set("restart", spec.Restart); err != nil {
+			return nil, err
+		}
+	}
+	return parse(flags, copts, serverOS)
+}
+
+func createFromSpec(ctx context.Context, dockerCli command.Cli, spec containerSpec, containerCfg *containerConfig) error {
+	dockerCli.Err().Infof("Creating container %s\n", spec.Name)
+	id, err := createContainer(ctx, dockerCli, containerCfg, &createOptions{
+		name:      spec.Name,
+		pull:      PullImageMissing,
+		untrusted: !dockerCli.ContentTrustEnabled(),
+	})
+	if err != nil {
+		return err
+	}
+	return dockerCli.Client().ContainerStart(ctx, id, container.StartOptions{})
+}
+
+// appliedSpec is the part of the configuration of a container that "docker
+// container apply" manages, in a form that can be compared.
+type appliedSpec struct {
+	image   string
+	command []string
+	ports   []string
+	env     []string
+	mounts  []string
+	restart string
+}
+
+// canonicalSpec returns the part of the configuration of a container that
+// "docker container apply" manages. Environment variables of imageEnv are
+// omitted, as they're set by the image.
+func canonicalSpec(config *container.Config, hostConfig *container.HostConfig, imageEnv []string) appliedSpec {
+	s := appliedSpec{restart: string(container.RestartPolicyDisabled)}
+	if config != nil {
+		s.image = config.Image
+		s.command = config.Cmd
+		fromImage := make(map[string]bool, len(imageEnv))
+		for _, e := range imageEnv {
+			fromImage[e] = true
+		}
+		for _, e := range config.Env {
+			if !fromImage[e] {
+				s.env = append(s.env, e)
+			}
+		}
+	}
+	if hostConfig != nil {
+		for port, bindings := range hostConfig.PortBindings {
+			for _, b := range bindings {
+				hostPort := b.HostPort
+				if b.HostIP != "" {
+					hostPort = net.JoinHostPort(b.HostIP, b.HostPort)
+				}
+				s.ports = append(s.ports, hostPort+":"+string(port))
+			}
+		}
+		s.mounts = append(s.mounts, hostConfig.Binds...)
+		for _, m := range hostConfig.Mounts {
+			desc := fmt.Sprintf("type=%s,source=%s,target=%s", m.Type, m.Source, m.Target)
+			if m.ReadOnly {
+				desc += ",readonly"
+			}
+			s.mounts = append(s.mounts, desc)
+		}
+		if name := hostConfig.RestartPolicy.Name; name != "" {
+			s.restart = string(name)
+			if hostConfig.RestartPolicy.MaximumRetryCount > 0 {
+				s.restart += ":" + strconv.Itoa(hostConfig.RestartPolicy.MaximumRetryCount)
+			}
+		}
+	}
+	sort.Strings(s.ports)
+	sort.Strings(s.env)
+	sort.Strings(s.mounts)
+	return s
+}
+
+// specDiff returns the differences between the current and the desired
+// configuration of a container, one per line. The command is only compared
+// if compareCommand is set, as the container otherwise uses the command of
+// its image.
+func specDiff(current, desired appliedSpec, compareCommand bool) []string {
+	var diff []string
+	if current.image != desired.image {
+		diff = append(diff, fmt.Sprintf("~ image: %s -> %s", current.image, desired.image))
+	}
+	if compareCommand && !equalStrings(current.command, desired.command) {
+		diff = append(diff, fmt.Sprintf("~ command: %q -> %q", current.command, desired.command))
+	}
+	diff = append(diff, listDiff("port", current.ports, desired.ports)...)
+	diff = append(diff, listDiff("env", current.env, desired.env)...)
+	diff = append(diff, listDiff("mount", current.mounts, desired.mounts)...)
+	if current.restart != desired.restart {
+		diff = append(diff, fmt.Sprintf("~ restart: %s -> %s", current.restart, desired.restart))
+	}
+	return diff
+}
+
+// listDiff returns the removed and the added values of sorted lists.
+func listDiff(kind string, current, desired []string) []string {
+	in := func(v string, list []string) bool {
+		i := sort.SearchStrings(list, v)
+		return i < len(list) && list[i] == v
+	}
+	var diff []string
+	for _, v := range current {
+		if !in(v, desired) {
+			diff = append(diff, fmt.Sprintf("- %s: %s", kind, v))
+		}
+	}
+	for _, v := range desired {
+		if !in(v, current) {
+			diff = append(diff, fmt.Sprintf("+ %s: %s", kind, v))
+		}
+	}
+	return diff
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,139 @@
+package container
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const applySpec = `
+name: web
+image: nginx:1.25
+ports:
+  - "8080:80"
+env:
+  MODE: production
+mounts:
+  - html:/usr/share/nginx/html:ro
+restart: unless-stopped
+`
+
+func TestLoadContainerSpec(t *testing.T) {
+	spec, err := loadContainerSpec(strings.NewReader(applySpec+"command: [nginx, -g, daemon off;]\n"), "-")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(spec, containerSpec{
+		Name:    "web",
+		Image:   "nginx:1.25",
+		Command: []string{"nginx", "-g", "daemon off;"},
+		Ports:   []string{"8080:80"},
+		Env:     map[string]string{"MODE": "production"},
+		Mounts:  []string{"html:/usr/share/nginx/html:ro"},
+		Restart: "unless-stopped",
+	}))
+
+	_, err = loadContainerSpec(strings.NewReader("name: web\n"), "-")
+	assert.Check(t, is.Error(err, "invalid specification -: image is required"))
+	_, err = loadContainerSpec(strings.NewReader("name: web\nimage: nginx\nvolumes: [data:/data]\n"), "-")
+	assert.Check(t, is.ErrorContains(err, "field volumes not found"))
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name           string
+		current        string
+		running        bool
+		expectedOut    string
+		expectedEvents []string
+	}{
+		{
+			name:           "create",
+			expectedEvents: []string{"create web nginx:1.25", "start new-id"},
+		},
+		{
+			name:    "up-to-date",
+			current: applySpec,
+			running: true,
+		},
+		{
+			name:           "up-to-date-stopped",
+			current:        applySpec,
+			expectedEvents: []string{"start web-id"},
+		},
+		{
+			name: "drift",
+			current: `
+name: web
+image: nginx:1.24
+ports: ["8081:80"]
+env: {MODE: production}
+mounts: [html:/usr/share/nginx/html:ro, logs:/var/log/nginx]
+restart: unless-stopped
+`,
+			running: true,
+			expectedOut: `~ image: nginx:1.24 -> nginx:1.25
+- port: 8081:80/tcp
++ port: 8080:80/tcp
+- mount: logs:/var/log/nginx
+`,
+			expectedEvents: []string{"remove web-id", "create web nginx:1.25", "start new-id"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			fakeCli := test.NewFakeCli(&fakeClient{
+				inspectFunc: func(string) (types.ContainerJSON, error) {
+					if tc.current == "" {
+						return types.ContainerJSON{}, errdefs.NotFound(errors.New("no such container"))
+					}
+					spec, err := loadContainerSpec(strings.NewReader(tc.current), "-")
+					assert.NilError(t, err)
+					cfg, err := containerConfigFromSpec(spec, "linux")
+					assert.NilError(t, err)
+					return types.ContainerJSON{
+						ContainerJSONBase: &types.ContainerJSONBase{
+							ID:         "web-id",
+							Image:      "sha256:nginx",
+							State:      &types.ContainerState{Running: tc.running},
+							HostConfig: cfg.HostConfig,
+						},
+						Config: cfg.Config,
+					}, nil
+				},
+				imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+					return types.ImageInspect{Config: &container.Config{Env: []string{"PATH=/usr/bin"}}}, nil, nil
+				},
+				createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, name string) (container.CreateResponse, error) {
+					events = append(events, "create "+name+" "+config.Image)
+					return container.CreateResponse{ID: "new-id"}, nil
+				},
+				containerStartFunc: func(containerID string, _ container.StartOptions) error {
+					events = append(events, "start "+containerID)
+					return nil
+				},
+				containerRemoveFunc: func(_ context.Context, containerID string, _ container.RemoveOptions) error {
+					events = append(events, "remove "+containerID)
+					return nil
+				},
+			})
+			fakeCli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(applySpec))))
+			err := runApply(context.Background(), fakeCli, applyOptions{file: "-"})
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), tc.expectedOut))
+			assert.Check(t, is.DeepEqual(events, tc.expectedEvents))
+		})
+	}
+}
@@ -21,6 +21,8 @@ type AttachOptions struct {
 	NoStdin    bool
 	Proxy      bool
 	DetachKeys string
+	Multi      bool
+	SwitchKeys string
 }
 
 func inspectContainerAndCheckState(ctx context.Context, apiClient client.APIClient, args string) (*types.ContainerJSON, error) {
@@ -48,8 +50,16 @@ func NewAttachCommand(dockerCLI command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "attach [OPTIONS] CONTAINER",
 		Short: "Attach local standard input, output, and error streams to a running container",
-		Args:  cli.ExactArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.Multi {
+				return cli.RequiresMinArgs(1)(cmd, args)
+			}
+			return cli.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Multi {
+				return runAttachMulti(cmd.Context(), dockerCLI, args, &opts)
+			}
 			containerID := args[0]
 			return RunAttach(cmd.Context(), dockerCLI, containerID, &opts)
 		},
@@ -65,6 +75,8 @@ func NewAttachCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.NoStdin, "no-stdin", false, "Do not attach STDIN")
 	flags.BoolVar(&opts.Proxy, "sig-proxy", true, "Proxy all received signals to the process")
 	flags.StringVar(&opts.DetachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.BoolVar(&opts.Multi, "multi", false, "Attach to the output of multiple containers, prefixed with the name of each container")
+	flags.StringVar(&opts.SwitchKeys, "switch-keys", defaultSwitchKeys, "Key sequence that sends the input to the next container, with --multi")
 	return cmd
 }
 
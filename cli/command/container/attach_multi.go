@@ -0,0 +1,244 @@
+package container
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+	"github.com/pkg/errors"
+)
+
+// defaultSwitchKeys is the key sequence of "docker attach --multi" that
+// sends the input to the next container.
+const defaultSwitchKeys = "ctrl-n"
+
+// prefixColors are the ANSI colors of the prefixes of the output of
+// containers, when writing to a terminal.
+var prefixColors = []int{36, 33, 32, 35, 34, 31, 96, 93, 92, 95, 94, 91}
+
+// multiAttachedContainer is a container that "docker attach --multi" is
+// attached to.
+type multiAttachedContainer struct {
+	name    string
+	tty     bool
+	stdin   bool
+	resp    types.HijackedResponse
+	resultC <-chan container.WaitResponse
+	errC    <-chan error
+}
+
+// runAttachMulti attaches to the output streams of multiple containers, and
+// writes their output with the name of the container as prefix of each line.
+// The input is sent to one of the containers that have an open STDIN at a
+// time; the switch keys, followed by a new line, send the input to the next
+// one. Signals are not proxied, as they'd apply to all containers.
+func runAttachMulti(ctx context.Context, dockerCLI command.Cli, containers []string, opts *AttachOptions) error {
+	switchKeys := opts.SwitchKeys
+	if switchKeys == "" {
+		switchKeys = defaultSwitchKeys
+	}
+	switchBytes, err := term.ToBytes(switchKeys)
+	if err != nil {
+		return errors.Wrapf(err, "invalid switch keys (%s)", switchKeys)
+	}
+	detachKeys := dockerCLI.ConfigFile().DetachKeys
+	if opts.DetachKeys != "" {
+		detachKeys = opts.DetachKeys
+	}
+
+	apiClient := dockerCLI.Client()
+	attached := make([]*multiAttachedContainer, 0, len(containers))
+	defer func() {
+		for _, a := range attached {
+			a.resp.Close()
+		}
+	}()
+	for _, name := range containers {
+		resultC, errC := apiClient.ContainerWait(ctx, name, "")
+		c, err := inspectContainerAndCheckState(ctx, apiClient, name)
+		if err != nil {
+			return errors.Wrap(err, name)
+		}
+		a := &multiAttachedContainer{
+			name:    name,
+			tty:     c.Config.Tty,
+			stdin:   !opts.NoStdin && c.Config.OpenStdin,
+			resultC: resultC,
+			errC:    errC,
+		}
+		a.resp, err = apiClient.ContainerAttach(ctx, c.ID, container.AttachOptions{
+			Stream:     true,
+			Stdin:      a.stdin,
+			Stdout:     true,
+			Stderr:     true,
+			DetachKeys: detachKeys,
+		})
+		if err != nil {
+			return err
+		}
+		attached = append(attached, a)
+	}
+
+	prefixes := attachPrefixes(attached, dockerCLI.Out().IsTerminal())
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	// Messages are written with the same lock as the output of the
+	// containers, so that they're not written in the middle of a line.
+	printf := func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintf(dockerCLI.Err(), format, args...)
+	}
+	for i, a := range attached {
+		wg.Add(1)
+		go func(a *multiAttachedContainer, prefix string) {
+			defer wg.Done()
+			stdout := &prefixWriter{mu: &mu, out: dockerCLI.Out(), prefix: prefix}
+			stderr := &prefixWriter{mu: &mu, out: dockerCLI.Err(), prefix: prefix}
+			if a.tty {
+				_, _ = io.Copy(stdout, a.resp.Reader)
+			} else {
+				_, _ = stdcopy.StdCopy(stdout, stderr, a.resp.Reader)
+			}
+			stdout.flush()
+			stderr.flush()
+		}(a, prefixes[i])
+	}
+
+	var inputs []*multiAttachedContainer
+	for _, a := range attached {
+		if a.stdin {
+			inputs = append(inputs, a)
+		}
+	}
+	if len(inputs) > 0 {
+		if len(inputs) > 1 {
+			printf("Sending input to %s (press %s and Enter to switch)\n", inputs[0].name, switchKeys)
+		}
+		go forwardMultiInput(dockerCLI.In(), inputs, switchBytes, printf)
+	}
+	wg.Wait()
+
+	var status int
+	for _, a := range attached {
+		select {
+		case result := <-a.resultC:
+			if result.Error != nil {
+				return errors.Errorf("%s: %s", a.name, result.Error.Message)
+			}
+			printf("%s exited with code %d\n", a.name, result.StatusCode)
+			if result.StatusCode != 0 {
+				status = int(result.StatusCode)
+			}
+		case err := <-a.errC:
+			return err
+		case <-time.After(time.Second):
+			// The stream ended without the container exiting, which is the
+			// case when detaching from it.
+		}
+	}
+	if status != 0 {
+		return cli.StatusError{StatusCode: status}
+	}
+	return nil
+}
+
+// attachPrefixes returns the prefixes of the output of the containers, with
+// the names of the containers padded to the same width, and colored if the
+// output is a terminal.
+func attachPrefixes(attached []*multiAttachedContainer, color bool) []string {
+	var width int
+	for _, a := range attached {
+		if len(a.name) > width {
+			width = len(a.name)
+		}
+	}
+	prefixes := make([]string, 0, len(attached))
+	for i, a := range attached {
+		if color {
+			prefixes = append(prefixes, fmt.Sprintf("\x1b[%dm%-*s |\x1b[0m ", prefixColors[i%len(prefixColors)], width, a.name))
+		} else {
+			prefixes = append(prefixes, fmt.Sprintf("%-*s | ", width, a.name))
+		}
+	}
+	return prefixes
+}
+
+// forwardMultiInput sends the lines of the input to the first of inputs, and
+// sends the following lines to the next one each time the input contains the
+// switch keys.
+func forwardMultiInput(in io.Reader, inputs []*multiAttachedContainer, switchKeys []byte, printf func(format string, args ...any)) {
+	var current int
+	r := bufio.NewReader(in)
+	for {
+		line, err := r.ReadBytes('\n')
+		if n := bytes.Count(line, switchKeys); n > 0 {
+			line = bytes.ReplaceAll(line, switchKeys, nil)
+			current = (current + n) % len(inputs)
+			printf("Sending input to %s\n", inputs[current].name)
+			if len(bytes.TrimRight(line, "\r\n")) == 0 {
+				line = nil
+			}
+		}
+		if len(line) > 0 {
+			// Writes to a container that exited fail, and are discarded.
+			_, _ = inputs[current].resp.Conn.Write(line)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// prefixWriter writes the lines that are written to it to out, with prefix
+// before each line. Lines of the writers that share mu are not interleaved.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// flush writes the last line, if it doesn't end with a new line.
+func (w *prefixWriter) flush() {
+	if len(w.buf) > 0 {
+		_ = w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := io.WriteString(w.out, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
@@ -0,0 +1,83 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestAttachMulti(t *testing.T) {
+	exitCodes := map[string]int64{"web": 0, "db": 3}
+	received := make(map[string]chan string, len(exitCodes))
+	for name := range exitCodes {
+		received[name] = make(chan string, 1)
+	}
+	fakeCli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{ID: name, State: &types.ContainerState{Running: true}},
+				Config:            &container.Config{OpenStdin: true},
+			}, nil
+		},
+		containerAttachFunc: func(_ context.Context, name string, options container.AttachOptions) (types.HijackedResponse, error) {
+			assert.Check(t, options.Stdin)
+			server, client := net.Pipe()
+			go func() {
+				defer server.Close()
+				_, _ = stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte("hello from " + name + "\n"))
+				_, _ = stdcopy.NewStdWriter(server, stdcopy.Stderr).Write([]byte("warning from " + name))
+				line, _ := bufio.NewReader(server).ReadString('\n')
+				received[name] <- line
+			}()
+			return types.NewHijackedResponse(client, types.MediaTypeMultiplexedStream), nil
+		},
+		waitFunc: func(name string) (<-chan container.WaitResponse, <-chan error) {
+			resultC := make(chan container.WaitResponse, 1)
+			resultC <- container.WaitResponse{StatusCode: exitCodes[name]}
+			return resultC, make(chan error)
+		},
+	})
+	fakeCli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("to web\n\x0e\nto db\n"))))
+
+	cmd := NewAttachCommand(fakeCli)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--multi", "web", "db"})
+	assert.Check(t, is.DeepEqual(cmd.Execute(), cli.StatusError{StatusCode: 3}))
+	assert.Check(t, is.Equal(<-received["web"], "to web\n"))
+	assert.Check(t, is.Equal(<-received["db"], "to db\n"))
+
+	lines := strings.Split(strings.TrimSuffix(fakeCli.OutBuffer().String(), "\n"), "\n")
+	sort.Strings(lines)
+	assert.Check(t, is.DeepEqual(lines, []string{"db  | hello from db", "web | hello from web"}))
+	errLines := strings.Split(fakeCli.ErrBuffer().String(), "\n")
+	for _, l := range []string{"db  | warning from db", "web | warning from web", "Sending input to web (press ctrl-n and Enter to switch)", "Sending input to db", "web exited with code 0", "db exited with code 3"} {
+		assert.Check(t, is.Contains(errLines, l))
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var (
+		out strings.Builder
+		mu  sync.Mutex
+	)
+	w := &prefixWriter{mu: &mu, out: &out, prefix: "web | "}
+	_, _ = w.Write([]byte("first li"))
+	_, _ = w.Write([]byte("ne\nsecond line\nlast"))
+	assert.Check(t, is.Equal(out.String(), "web | first line\nweb | second line\n"))
+	w.flush()
+	assert.Check(t, is.Equal(out.String(), "web | first line\nweb | second line\nweb | last\n"))
+}
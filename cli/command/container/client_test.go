@@ -3,9 +3,12 @@ package container
 import (
 	"context"
 	"io"
+	"strings"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/checkpoint"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
@@ -26,6 +29,7 @@ type fakeClient struct {
 		containerName string) (container.CreateResponse, error)
 	containerStartFunc      func(containerID string, options container.StartOptions) error
 	imageCreateFunc         func(parentReference string, options image.CreateOptions) (io.ReadCloser, error)
+	imageInspectFunc        func(imageID string) (types.ImageInspect, []byte, error)
 	infoFunc                func() (system.Info, error)
 	containerStatPathFunc   func(containerID, path string) (container.PathStat, error)
 	containerCopyFromFunc   func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error)
@@ -38,6 +42,14 @@ type fakeClient struct {
 	containerKillFunc       func(ctx context.Context, containerID, signal string) error
 	containerPruneFunc      func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error)
 	containerAttachFunc     func(ctx context.Context, containerID string, options container.AttachOptions) (types.HijackedResponse, error)
+	containerExecAttachFunc func(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error)
+	eventsFunc              func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
+	containerStatsFunc      func(containerID string) (container.StatsResponseReader, error)
+	containerUpdateFunc     func(containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error)
+	checkpointCreateFunc    func(containerID string, options checkpoint.CreateOptions) error
+	copyToContainerFunc     func(containerID, dstPath string, content io.Reader) error
+	networkConnectFunc      func(networkID, containerID string, config *network.EndpointSettings) error
+	pingFunc                func() (types.Ping, error)
 	Version                 string
 }
 
@@ -69,6 +81,13 @@ func (f *fakeClient) ContainerExecInspect(_ context.Context, execID string) (con
 	return container.ExecInspect{}, nil
 }
 
+func (f *fakeClient) ContainerExecAttach(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+	if f.containerExecAttachFunc != nil {
+		return f.containerExecAttachFunc(ctx, execID, options)
+	}
+	return types.HijackedResponse{}, nil
+}
+
 func (f *fakeClient) ContainerExecStart(context.Context, string, container.ExecStartOptions) error {
 	return nil
 }
@@ -101,6 +120,13 @@ func (f *fakeClient) ImageCreate(_ context.Context, parentReference string, opti
 	return nil, nil
 }
 
+func (f *fakeClient) ImageInspectWithRaw(_ context.Context, imageID string) (types.ImageInspect, []byte, error) {
+	if f.imageInspectFunc != nil {
+		return f.imageInspectFunc(imageID)
+	}
+	return types.ImageInspect{}, nil, nil
+}
+
 func (f *fakeClient) Info(_ context.Context) (system.Info, error) {
 	if f.infoFunc != nil {
 		return f.infoFunc()
@@ -181,3 +207,57 @@ func (f *fakeClient) ContainerAttach(ctx context.Context, containerID string, op
 	}
 	return types.HijackedResponse{}, nil
 }
+
+func (f *fakeClient) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	if f.eventsFunc != nil {
+		return f.eventsFunc(ctx, options)
+	}
+	errs := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		errs <- ctx.Err()
+	}()
+	return make(chan events.Message), errs
+}
+
+func (f *fakeClient) ContainerStatsOneShot(_ context.Context, containerID string) (container.StatsResponseReader, error) {
+	if f.containerStatsFunc != nil {
+		return f.containerStatsFunc(containerID)
+	}
+	return container.StatsResponseReader{Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+func (f *fakeClient) ContainerUpdate(_ context.Context, containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+	if f.containerUpdateFunc != nil {
+		return f.containerUpdateFunc(containerID, updateConfig)
+	}
+	return container.ContainerUpdateOKBody{}, nil
+}
+
+func (f *fakeClient) CheckpointCreate(_ context.Context, containerID string, options checkpoint.CreateOptions) error {
+	if f.checkpointCreateFunc != nil {
+		return f.checkpointCreateFunc(containerID, options)
+	}
+	return nil
+}
+
+func (f *fakeClient) CopyToContainer(_ context.Context, containerID, dstPath string, content io.Reader, _ container.CopyToContainerOptions) error {
+	if f.copyToContainerFunc != nil {
+		return f.copyToContainerFunc(containerID, dstPath, content)
+	}
+	return nil
+}
+
+func (f *fakeClient) NetworkConnect(_ context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	if f.networkConnectFunc != nil {
+		return f.networkConnectFunc(networkID, containerID, config)
+	}
+	return nil
+}
+
+func (f *fakeClient) Ping(_ context.Context) (types.Ping, error) {
+	if f.pingFunc != nil {
+		return f.pingFunc()
+	}
+	return types.Ping{}, nil
+}
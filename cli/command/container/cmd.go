@@ -15,6 +15,7 @@ func NewContainerCommand(dockerCli command.Cli) *cobra.Command {
 		RunE:  command.ShowHelp(dockerCli.Err()),
 	}
 	cmd.AddCommand(
+		newApplyCommand(dockerCli),
 		NewAttachCommand(dockerCli),
 		NewCommitCommand(dockerCli),
 		NewCopyCommand(dockerCli),
@@ -22,9 +23,13 @@ func NewContainerCommand(dockerCli command.Cli) *cobra.Command {
 		NewDiffCommand(dockerCli),
 		NewExecCommand(dockerCli),
 		NewExportCommand(dockerCli),
+		newDebugCommand(dockerCli),
+		newExportComposeCommand(dockerCli),
 		NewKillCommand(dockerCli),
 		NewLogsCommand(dockerCli),
+		newMigrateCommand(dockerCli),
 		NewPauseCommand(dockerCli),
+		newPoliciesCommand(dockerCli),
 		NewPortCommand(dockerCli),
 		NewRenameCommand(dockerCli),
 		NewRestartCommand(dockerCli),
@@ -0,0 +1,57 @@
+package container
+
+import (
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/spf13/cobra"
+)
+
+// addCompletions adds the completions of the flags of "docker run" and
+// "docker create" that are added by addFlags.
+func addCompletions(cmd *cobra.Command, dockerCLI command.Cli) {
+	_ = cmd.RegisterFlagCompletionFunc("cap-add", completion.CapabilitiesAddable)
+	_ = cmd.RegisterFlagCompletionFunc("cap-drop", completion.CapabilitiesDroppable)
+	_ = cmd.RegisterFlagCompletionFunc("env", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return os.Environ(), cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("env-file", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveDefault
+	})
+	_ = cmd.RegisterFlagCompletionFunc("network", completion.NetworkNames(dockerCLI))
+	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms(dockerCLI))
+	_ = cmd.RegisterFlagCompletionFunc("pull", cobra.FixedCompletions([]string{PullImageAlways, PullImageMissing, PullImageNever}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("volume", completeVolumes(dockerCLI))
+	_ = cmd.RegisterFlagCompletionFunc("volume-driver", completion.VolumeDrivers(dockerCLI))
+}
+
+// completeVolumes offers completion for the "--volume" flag: host paths, and
+// the names of volumes, followed by a colon to complete the path in the
+// container.
+func completeVolumes(dockerCLI completion.APIClientProvider) completion.ValidArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if strings.Contains(toComplete, ":") && !isWindowsDrive(toComplete) {
+			// The path in the container, or the options of the mount.
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if toComplete == "" || strings.ContainsAny(toComplete[:1], `/.~\`) || isWindowsDrive(toComplete) {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		names, directive := completion.VolumeNames(dockerCLI)(cmd, args, toComplete)
+		if directive == cobra.ShellCompDirectiveError {
+			return nil, directive
+		}
+		for i, name := range names {
+			names[i] = name + ":"
+		}
+		return names, cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// isWindowsDrive returns whether path starts with a drive letter, such as
+// "C:".
+func isWindowsDrive(path string) bool {
+	return len(path) >= 2 && path[1] == ':' && (path[0]|0x20) >= 'a' && (path[0]|0x20) <= 'z' && !strings.Contains(path[2:], ":")
+}
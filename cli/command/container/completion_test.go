@@ -0,0 +1,32 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCompleteVolumes(t *testing.T) {
+	fn := completeVolumes(test.NewFakeCli(&fakeClient{}))
+	tests := []struct {
+		toComplete string
+		expected   cobra.ShellCompDirective
+	}{
+		{toComplete: "", expected: cobra.ShellCompDirectiveDefault},
+		{toComplete: "/home/", expected: cobra.ShellCompDirectiveDefault},
+		{toComplete: "./data", expected: cobra.ShellCompDirectiveDefault},
+		{toComplete: `C:\Users`, expected: cobra.ShellCompDirectiveDefault},
+		{toComplete: "data:/var/lib", expected: cobra.ShellCompDirectiveNoFileComp},
+		{toComplete: "/home/me:/data:ro", expected: cobra.ShellCompDirectiveNoFileComp},
+	}
+	for _, tc := range tests {
+		t.Run(tc.toComplete, func(t *testing.T) {
+			names, directive := fn(&cobra.Command{}, nil, tc.toComplete)
+			assert.Check(t, is.Len(names, 0))
+			assert.Check(t, is.Equal(directive, tc.expected))
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/pkg/archive"
@@ -156,6 +157,24 @@ func NewCopyCommand(dockerCli command.Cli) *cobra.Command {
 		Annotations: map[string]string{
 			"aliases": "docker container cp, docker cp",
 		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			// Either the source or the destination is a path in a container,
+			// the other is a local path.
+			if len(args) > 1 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			if strings.Contains(toComplete, ":") || (len(args) == 1 && strings.Contains(args[0], ":")) {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			names, directive := completion.ContainerNames(dockerCli, true)(cmd, args, toComplete)
+			if directive == cobra.ShellCompDirectiveError {
+				return nil, directive
+			}
+			for i, name := range names {
+				names[i] = name + ":"
+			}
+			return names, cobra.ShellCompDirectiveNoSpace
+		},
 	}
 
 	flags := cmd.Flags()
@@ -290,7 +309,7 @@ func copyFromContainer(ctx context.Context, dockerCli command.Cli, copyConfig cp
 	cancel()
 	<-done
 	restore()
-	fmt.Fprintln(dockerCli.Err(), "Successfully copied", progressHumanSize(copiedSize), "to", dstPath)
+	dockerCli.Err().Infof("Successfully copied %s to %s\n", progressHumanSize(copiedSize), dstPath)
 
 	return res
 }
@@ -412,7 +431,7 @@ func copyToContainer(ctx context.Context, dockerCli command.Cli, copyConfig cpCo
 	cancel()
 	<-done
 	restore()
-	fmt.Fprintln(dockerCli.Err(), "Successfully copied", progressHumanSize(copiedSize), "to", copyConfig.container+":"+dstInfo.Path)
+	dockerCli.Err().Infof("Successfully copied %s to %s:%s\n", progressHumanSize(copiedSize), copyConfig.container, dstInfo.Path)
 
 	return res
 }
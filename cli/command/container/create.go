@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/containerd/platforms"
 	"github.com/distribution/reference"
@@ -13,10 +15,14 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/cli/mountpath"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	imagetypes "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -26,6 +32,20 @@ import (
 	"github.com/spf13/pflag"
 )
 
+const (
+	// hostDockerInternal is the hostname that Docker Desktop resolves to the
+	// host, and which can be mapped to the "host-gateway" on Linux daemons.
+	hostDockerInternal = "host.docker.internal"
+
+	// hostGatewayLabel is the image label that an image can set to request
+	// a "host.docker.internal" entry when running on a Linux daemon.
+	hostGatewayLabel = "com.docker.host-gateway"
+
+	// imageDigestLabel is the container label that records the digest of the
+	// image of the container, when using "--print-digest" or "--pin".
+	imageDigestLabel = "com.docker.cli.image.digest"
+)
+
 // Pull constants
 const (
 	PullImageAlways  = "always"
@@ -34,11 +54,19 @@ const (
 )
 
 type createOptions struct {
-	name      string
-	platform  string
-	untrusted bool
-	pull      string // always, missing, never
-	quiet     bool
+	name           string
+	platform       string
+	untrusted      bool
+	pull           string // always, missing, never
+	quiet          bool
+	printDigest    bool
+	pin            bool
+	createHostDirs bool
+	verifyPolicy   string
+
+	// rootCmd is the root command of the CLI, which is needed to find the
+	// scanner plugins that scan images for --verify-policy.
+	rootCmd *cobra.Command
 }
 
 // NewCreateCommand creates a new cobra.Command for `docker create`
@@ -55,6 +83,10 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 			if len(args) > 1 {
 				copts.Args = args[1:]
 			}
+			options.rootCmd = cmd.Root()
+			if dockerCli.Out().Level() == streams.LevelQuiet {
+				options.quiet = true
+			}
 			return runCreate(cmd.Context(), dockerCli, cmd.Flags(), &options, copts)
 		},
 		Annotations: map[string]string{
@@ -67,8 +99,9 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetInterspersed(false)
 
 	flags.StringVar(&options.name, "name", "", "Assign a name to the container")
-	flags.StringVar(&options.pull, "pull", PullImageMissing, `Pull image before creating ("`+PullImageAlways+`", "|`+PullImageMissing+`", "`+PullImageNever+`")`)
-	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the pull output")
+	addPullFlags(flags, &options, "creating")
+	flags.StringVar(&options.verifyPolicy, "verify-policy", "", "Verify the image against a policy file before creating the container")
+	flags.BoolVar(&options.createHostDirs, "create-host-dirs", false, "Create the missing host directories of bind mounts")
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -77,6 +110,7 @@ func NewCreateCommand(dockerCli command.Cli) *cobra.Command {
 	command.AddPlatformFlag(flags, &options.platform)
 	command.AddTrustVerificationFlags(flags, &options.untrusted, dockerCli.ContentTrustEnabled())
 	copts = addFlags(flags)
+	addCompletions(cmd, dockerCli)
 	return cmd
 }
 
@@ -95,11 +129,30 @@ func runCreate(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet,
 		}
 	}
 	copts.env = *opts.NewListOptsRef(&newEnv, nil)
+	if copts.setupCmds.Len() > 0 {
+		// The container isn't started by "docker create", so the setup
+		// commands are recorded on it, to be executed when it's started.
+		copts.setupCmdOnStart = true
+	}
 	containerCfg, err := parse(flags, copts, dockerCli.ServerInfo().OSType)
 	if err != nil {
 		reportError(dockerCli.Err(), "create", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
 	}
+	addHostGateway(dockerCli, flags, copts, containerCfg)
+	translateMountPaths(dockerCli.Err(), mountpath.CurrentHost(), dockerCli.ServerInfo().OSType, containerCfg.HostConfig)
+	if err := checkHostPaths(ctx, dockerCli, containerCfg.HostConfig, options.createHostDirs); err != nil {
+		reportError(dockerCli.Err(), "create", err.Error(), false)
+		return cli.StatusError{StatusCode: 125}
+	}
+	if err := checkResources(ctx, dockerCli, containerCfg.HostConfig); err != nil {
+		reportError(dockerCli.Err(), "create", err.Error(), false)
+		return cli.StatusError{StatusCode: 125}
+	}
+	if err := checkGPURuntime(ctx, dockerCli, copts); err != nil {
+		reportError(dockerCli.Err(), "create", err.Error(), false)
+		return cli.StatusError{StatusCode: 125}
+	}
 	if err = validateAPIVersion(containerCfg, dockerCli.Client().ClientVersion()); err != nil {
 		reportError(dockerCli.Err(), "create", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
@@ -112,6 +165,15 @@ func runCreate(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet,
 	return nil
 }
 
+// addPullFlags adds the flags that control how the image of the container is
+// pulled, which are shared by "docker create" and "docker run".
+func addPullFlags(flags *pflag.FlagSet, options *createOptions, action string) {
+	flags.StringVar(&options.pull, "pull", PullImageMissing, `Pull image before `+action+` ("`+PullImageAlways+`", "`+PullImageMissing+`", "`+PullImageNever+`")`)
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the pull output")
+	flags.BoolVar(&options.printDigest, "print-digest", false, "Print the digest of the image of the container")
+	flags.BoolVar(&options.pin, "pin", false, "Create the container from the digest of the image, instead of its tag")
+}
+
 // FIXME(thaJeztah): this is the only code-path that uses APIClient.ImageCreate. Rewrite this to use the regular "pull" code (or vice-versa).
 func pullImage(ctx context.Context, dockerCli command.Cli, img string, options *createOptions) error {
 	encodedAuth, err := command.RetrieveAuthTokenFromImage(dockerCli.ConfigFile(), img)
@@ -204,11 +266,28 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerCfg *c
 	}
 	defer containerIDFile.Close()
 
+	// Images of OCI image layouts are loaded, instead of pulled.
+	fromLayout := false
+	if layoutPath, tag, ok := image.ParseOCILayoutReference(config.Image); ok {
+		loaded, err := image.LoadOCILayout(ctx, dockerCli, layoutPath, tag)
+		if err != nil {
+			return "", err
+		}
+		if len(loaded) != 1 {
+			return "", errors.Errorf("OCI image layout %s has %d images: select one with oci:PATH:TAG", layoutPath, len(loaded))
+		}
+		if !options.quiet {
+			dockerCli.Err().Infof("Loaded image '%s' from OCI image layout %s\n", loaded[0], layoutPath)
+		}
+		config.Image = loaded[0]
+		fromLayout = true
+	}
+
 	ref, err := reference.ParseAnyReference(config.Image)
 	if err != nil {
 		return "", err
 	}
-	if named, ok := ref.(reference.Named); ok {
+	if named, ok := ref.(reference.Named); ok && !fromLayout {
 		namedRef = reference.TagNameOnly(named)
 
 		if taggedRef, ok := namedRef.(reference.NamedTagged); ok && !options.untrusted {
@@ -244,12 +323,90 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerCfg *c
 		platform = &p
 	}
 
-	if options.pull == PullImageAlways {
+	if options.verifyPolicy != "" {
+		digested, err := verifyImagePolicy(ctx, dockerCli, config.Image, namedRef, options, pullAndTagImage)
+		if err != nil {
+			return "", err
+		}
+		if digested != nil {
+			config.Image = reference.FamiliarString(digested)
+		}
+	} else if options.pull == PullImageAlways && !fromLayout {
 		if err := pullAndTagImage(); err != nil {
 			return "", err
 		}
 	}
 
+	// inspectImage inspects the image once, for the options that depend on
+	// it, after pulling it if it's missing.
+	var inspected *types.ImageInspect
+	inspectImage := func() (types.ImageInspect, error) {
+		if inspected == nil {
+			img, err := inspectOrPullImage(ctx, dockerCli, config.Image, namedRef, options, pullAndTagImage)
+			if err != nil {
+				return img, err
+			}
+			inspected = &img
+		}
+		return *inspected, nil
+	}
+
+	if (options.printDigest || options.pin) && namedRef != nil {
+		digested, err := resolveImageDigest(config.Image, namedRef, inspectImage)
+		if err != nil {
+			return "", err
+		}
+		if options.printDigest {
+			_, _ = fmt.Fprintf(dockerCli.Err(), "Using image %s\n", reference.FamiliarString(digested))
+		}
+		if config.Labels == nil {
+			config.Labels = map[string]string{}
+		}
+		config.Labels[imageDigestLabel] = digested.Digest().String()
+		if options.pin {
+			config.Image = reference.FamiliarString(digested)
+		}
+	}
+
+	// checkHostGatewayLabel adds a "host.docker.internal" entry if the image
+	// requests it through its label. The image is not pulled to read the
+	// label: a missing image is checked once it's pulled below.
+	checkHostGateway := containerCfg.hostGatewayFromImage
+	checkHostGatewayLabel := func() error {
+		if !checkHostGateway {
+			return nil
+		}
+		img := inspected
+		if img == nil {
+			inspect, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, config.Image)
+			if errdefs.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			img = &inspect
+		}
+		checkHostGateway = false
+		if img.Config != nil {
+			if ok, _ := strconv.ParseBool(img.Config.Labels[hostGatewayLabel]); ok {
+				hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, hostDockerInternal+":host-gateway")
+			}
+		}
+		return nil
+	}
+	if err := checkHostGatewayLabel(); err != nil {
+		return "", err
+	}
+
+	// Files can only be bind-mounted if the daemon runs on the same host.
+	daemonHost := dockerCli.Client().DaemonHost()
+	localDaemon := strings.HasPrefix(daemonHost, "unix://") || strings.HasPrefix(daemonHost, "npipe://")
+	stagedSecrets, err := prepareSecrets(containerCfg.secrets, hostConfig, localDaemon)
+	if err != nil {
+		return "", err
+	}
+
 	hostConfig.ConsoleSize[0], hostConfig.ConsoleSize[1] = dockerCli.Out().GetTtySize()
 
 	response, err := dockerCli.Client().ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, options.name)
@@ -258,12 +415,15 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerCfg *c
 		if errdefs.IsNotFound(err) && namedRef != nil && options.pull == PullImageMissing {
 			if !options.quiet {
 				// we don't want to write to stdout anything apart from container.ID
-				fmt.Fprintf(dockerCli.Err(), "Unable to find image '%s' locally\n", reference.FamiliarString(namedRef))
+				dockerCli.Err().Infof("Unable to find image '%s' locally\n", reference.FamiliarString(namedRef))
 			}
 
 			if err := pullAndTagImage(); err != nil {
 				return "", err
 			}
+			if err := checkHostGatewayLabel(); err != nil {
+				return "", err
+			}
 
 			var retryErr error
 			response, retryErr = dockerCli.Client().ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, options.name)
@@ -278,10 +438,56 @@ func createContainer(ctx context.Context, dockerCli command.Cli, containerCfg *c
 	for _, w := range response.Warnings {
 		_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", w)
 	}
+	if err := copySecrets(ctx, dockerCli, response.ID, stagedSecrets); err != nil {
+		_ = dockerCli.Client().ContainerRemove(context.WithoutCancel(ctx), response.ID, container.RemoveOptions{Force: true})
+		return "", err
+	}
+	image.RecordUse(dockerCli, config.Image)
 	err = containerIDFile.Write(response.ID)
 	return response.ID, err
 }
 
+// inspectOrPullImage inspects the image of the container. The image is
+// pulled first if it's missing, and the pull policy allows it.
+func inspectOrPullImage(ctx context.Context, dockerCli command.Cli, img string, namedRef reference.Named, options *createOptions, pull func() error) (types.ImageInspect, error) {
+	inspect, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, img)
+	if errdefs.IsNotFound(err) && namedRef != nil && options.pull == PullImageMissing {
+		if !options.quiet {
+			dockerCli.Err().Infof("Unable to find image '%s' locally\n", reference.FamiliarString(namedRef))
+		}
+		if err := pull(); err != nil {
+			return types.ImageInspect{}, err
+		}
+		inspect, _, err = dockerCli.Client().ImageInspectWithRaw(ctx, img)
+	}
+	return inspect, err
+}
+
+// resolveImageDigest returns the reference of the image of the container,
+// pinned to its digest in its repository.
+func resolveImageDigest(img string, namedRef reference.Named, inspectImage func() (types.ImageInspect, error)) (reference.Canonical, error) {
+	// Images of trusted references are pinned to their digest already.
+	if ref, err := reference.ParseNormalizedNamed(img); err == nil {
+		if canonical, ok := ref.(reference.Canonical); ok {
+			return canonical, nil
+		}
+	}
+	inspect, err := inspectImage()
+	if err != nil {
+		return nil, err
+	}
+	for _, repoDigest := range inspect.RepoDigests {
+		ref, err := reference.ParseNormalizedNamed(repoDigest)
+		if err != nil {
+			continue
+		}
+		if canonical, ok := ref.(reference.Canonical); ok && canonical.Name() == namedRef.Name() {
+			return reference.WithDigest(reference.TrimNamed(namedRef), canonical.Digest())
+		}
+	}
+	return nil, errors.Errorf("image %s has no digest: only images that were pulled from, or pushed to, a registry have one", reference.FamiliarString(namedRef))
+}
+
 func warnOnOomKillDisable(hostConfig container.HostConfig, stderr io.Writer) {
 	if hostConfig.OomKillDisable != nil && *hostConfig.OomKillDisable && hostConfig.Memory == 0 {
 		fmt.Fprintln(stderr, "WARNING: Disabling the OOM killer on containers without setting a '-m/--memory' limit may be dangerous.")
@@ -299,6 +505,70 @@ func warnOnLocalhostDNS(hostConfig container.HostConfig, stderr io.Writer) {
 	}
 }
 
+// addHostGateway adds a "host.docker.internal:host-gateway" entry to the
+// container's extra hosts when running on a Linux daemon, and the entry is
+// requested through the "--add-host-gateway" flag, the "addHostGateway"
+// option in the CLI configuration file, or the image's "com.docker.host-gateway"
+// label. Explicitly setting "--add-host-gateway=false" takes precedence over
+// the configuration file and the image label.
+//
+// The image's label is checked by createContainer before creating the
+// container if the image is present, or once it's pulled otherwise.
+//
+// No entry is added if the user already passed a "host.docker.internal" entry
+// through "--add-host".
+func addHostGateway(dockerCli command.Cli, flags *pflag.FlagSet, copts *containerOptions, containerCfg *containerConfig) {
+	if dockerCli.ServerInfo().OSType != "linux" {
+		return
+	}
+	for _, h := range containerCfg.HostConfig.ExtraHosts {
+		if name, _, _ := strings.Cut(h, ":"); name == hostDockerInternal {
+			return
+		}
+	}
+	switch {
+	case flags.Changed("add-host-gateway"):
+		if !copts.addHostGateway {
+			return
+		}
+	case dockerCli.ConfigFile().AddHostGateway:
+	default:
+		containerCfg.hostGatewayFromImage = true
+		return
+	}
+	containerCfg.HostConfig.ExtraHosts = append(containerCfg.HostConfig.ExtraHosts, hostDockerInternal+":host-gateway")
+}
+
+// translateMountPaths translates the host paths of the bind mounts of
+// hostConfig that are written in the style of another environment than the
+// host, such as Git Bash paths on Windows, into paths that the daemon
+// understands (see [mountpath.Translate]). Each translation is reported on
+// stderr, so that the path that is mounted is never a surprise.
+func translateMountPaths(stderr io.Writer, host mountpath.Host, daemonOS string, hostConfig *container.HostConfig) {
+	report := func(tr mountpath.Translation) {
+		_, _ = fmt.Fprintf(stderr, "Translated the %s path %q of a bind mount to %q\n", tr.Style, tr.Original, tr.Path)
+	}
+	for i, bind := range hostConfig.Binds {
+		parsed, err := loader.ParseVolume(bind)
+		if err != nil || parsed.Type != string(mount.TypeBind) {
+			continue
+		}
+		if tr, ok := mountpath.Translate(host, daemonOS, parsed.Source); ok {
+			hostConfig.Binds[i] = tr.Path + strings.TrimPrefix(bind, parsed.Source)
+			report(tr)
+		}
+	}
+	for i, m := range hostConfig.Mounts {
+		if m.Type != mount.TypeBind {
+			continue
+		}
+		if tr, ok := mountpath.Translate(host, daemonOS, m.Source); ok {
+			hostConfig.Mounts[i].Source = tr.Path
+			report(tr)
+		}
+	}
+}
+
 // IPLocalhost is a regex pattern for IPv4 or IPv6 loopback range.
 const ipLocalhost = `((127\.([0-9]{1,3}\.){2}[0-9]{1,3})|(::1)$)`
 
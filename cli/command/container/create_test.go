@@ -11,11 +11,15 @@ import (
 	"testing"
 
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/mountpath"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/notary"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/system"
 	"github.com/google/go-cmp/cmp"
@@ -161,6 +165,91 @@ func TestCreateContainerImagePullPolicy(t *testing.T) {
 	}
 }
 
+func TestCreateContainerPinDigest(t *testing.T) {
+	const digest = "sha256:4b8e6b4a4ba0bb5a8f1e5e4e1c3f8a4e9b51f0c2b1a8e4f3f1e2d9c7a5b3e1d0"
+	cases := []struct {
+		doc           string
+		options       createOptions
+		missing       bool
+		repoDigests   []string
+		expectedImage string
+		expectedErr   string
+		expectedPulls int
+	}{
+		{
+			doc:           "print digest",
+			options:       createOptions{printDigest: true},
+			repoDigests:   []string{"other@" + digest, "alpine@" + digest},
+			expectedImage: "alpine",
+		},
+		{
+			doc:           "pin",
+			options:       createOptions{printDigest: true, pin: true},
+			repoDigests:   []string{"alpine@" + digest},
+			expectedImage: "alpine@" + digest,
+		},
+		{
+			doc:           "pull missing",
+			options:       createOptions{printDigest: true, pull: PullImageMissing},
+			missing:       true,
+			repoDigests:   []string{"alpine@" + digest},
+			expectedImage: "alpine",
+			expectedPulls: 1,
+		},
+		{
+			doc:         "never pull",
+			options:     createOptions{pin: true, pull: PullImageNever},
+			missing:     true,
+			expectedErr: "error fake not found",
+		},
+		{
+			doc:         "no digest",
+			options:     createOptions{pin: true},
+			expectedErr: "image alpine:latest has no digest",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.doc, func(t *testing.T) {
+			var createdConfig *container.Config
+			pulls := 0
+			client := &fakeClient{
+				imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+					if tc.missing && pulls == 0 {
+						return types.ImageInspect{}, nil, fakeNotFound{}
+					}
+					return types.ImageInspect{RepoDigests: tc.repoDigests}, nil, nil
+				},
+				imageCreateFunc: func(string, image.CreateOptions) (io.ReadCloser, error) {
+					pulls++
+					return io.NopCloser(strings.NewReader("")), nil
+				},
+				createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+					createdConfig = config
+					return container.CreateResponse{ID: "abcdef"}, nil
+				},
+			}
+			fakeCLI := test.NewFakeCli(client)
+			options := tc.options
+			options.untrusted = true
+			_, err := createContainer(context.Background(), fakeCLI, &containerConfig{
+				Config:     &container.Config{Image: "alpine"},
+				HostConfig: &container.HostConfig{},
+			}, &options)
+			assert.Check(t, is.Equal(pulls, tc.expectedPulls))
+			if tc.expectedErr != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(createdConfig.Image, tc.expectedImage))
+			assert.Check(t, is.Equal(createdConfig.Labels[imageDigestLabel], digest))
+			if tc.options.printDigest {
+				assert.Check(t, is.Contains(fakeCLI.ErrBuffer().String(), "Using image alpine@"+digest+"\n"))
+			}
+		})
+	}
+}
+
 func TestCreateContainerImagePullPolicyInvalid(t *testing.T) {
 	cases := []struct {
 		PullPolicy     string
@@ -353,7 +442,152 @@ func TestCreateContainerWithProxyConfig(t *testing.T) {
 	assert.NilError(t, err)
 }
 
+func TestCreateContainerWithHostGateway(t *testing.T) {
+	const gatewayEntry = "host.docker.internal:host-gateway"
+	testCases := []struct {
+		name        string
+		args        []string
+		osType      string
+		configFile  bool
+		imageLabels map[string]string
+		notPulled   bool
+		expected    []string
+	}{
+		{
+			name:     "not requested",
+			args:     []string{"image:tag"},
+			osType:   "linux",
+			expected: nil,
+		},
+		{
+			name:     "flag",
+			args:     []string{"--add-host-gateway", "image:tag"},
+			osType:   "linux",
+			expected: []string{gatewayEntry},
+		},
+		{
+			name:       "config file",
+			args:       []string{"image:tag"},
+			osType:     "linux",
+			configFile: true,
+			expected:   []string{gatewayEntry},
+		},
+		{
+			name:        "image label",
+			args:        []string{"image:tag"},
+			osType:      "linux",
+			imageLabels: map[string]string{"com.docker.host-gateway": "true"},
+			expected:    []string{gatewayEntry},
+		},
+		{
+			name:        "image label of an image that is pulled",
+			args:        []string{"image:tag"},
+			osType:      "linux",
+			imageLabels: map[string]string{"com.docker.host-gateway": "true"},
+			notPulled:   true,
+			expected:    []string{gatewayEntry},
+		},
+		{
+			name:        "flag disabled overrides config file and image label",
+			args:        []string{"--add-host-gateway=false", "image:tag"},
+			osType:      "linux",
+			configFile:  true,
+			imageLabels: map[string]string{"com.docker.host-gateway": "true"},
+			expected:    nil,
+		},
+		{
+			name:     "user-defined entry",
+			args:     []string{"--add-host-gateway", "--add-host=host.docker.internal:192.168.1.1", "image:tag"},
+			osType:   "linux",
+			expected: []string{"host.docker.internal:192.168.1.1"},
+		},
+		{
+			name:     "windows daemon",
+			args:     []string{"--add-host-gateway", "image:tag"},
+			osType:   "windows",
+			expected: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			pulled := !tc.notPulled
+			fakeCLI := test.NewFakeCli(&fakeClient{
+				imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+					if !pulled {
+						return types.ImageInspect{}, nil, fakeNotFound{}
+					}
+					return types.ImageInspect{Config: &container.Config{Labels: tc.imageLabels}}, nil, nil
+				},
+				imageCreateFunc: func(string, image.CreateOptions) (io.ReadCloser, error) {
+					pulled = true
+					return io.NopCloser(strings.NewReader("")), nil
+				},
+				infoFunc: func() (system.Info, error) {
+					return system.Info{IndexServerAddress: "https://indexserver.example.com"}, nil
+				},
+				createContainerFunc: func(config *container.Config,
+					hostConfig *container.HostConfig,
+					networkingConfig *network.NetworkingConfig,
+					platform *specs.Platform,
+					containerName string,
+				) (container.CreateResponse, error) {
+					if !pulled {
+						return container.CreateResponse{}, fakeNotFound{}
+					}
+					assert.Check(t, is.DeepEqual(hostConfig.ExtraHosts, tc.expected))
+					return container.CreateResponse{}, nil
+				},
+			})
+			fakeCLI.SetServerInfo(command.ServerInfo{OSType: tc.osType})
+			fakeCLI.SetConfigFile(&configfile.ConfigFile{AddHostGateway: tc.configFile})
+			cmd := NewCreateCommand(fakeCLI)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, pulled)
+			// A missing image is only pulled once, by the existing create flow.
+			var pulls int
+			if tc.notPulled {
+				pulls = 1
+			}
+			assert.Check(t, is.Equal(strings.Count(fakeCLI.ErrBuffer().String(), "Unable to find image"), pulls))
+		})
+	}
+}
+
 type fakeNotFound struct{}
 
 func (f fakeNotFound) NotFound()     {}
 func (f fakeNotFound) Error() string { return "error fake not found" }
+
+func TestTranslateMountPaths(t *testing.T) {
+	hostConfig := &container.HostConfig{
+		Binds: []string{
+			"/c/Users/me/src:/src:ro",
+			"/var/run/docker.sock:/var/run/docker.sock",
+			"data:/data",
+			`C:\Users\me\config:/config`,
+		},
+		Mounts: []mount.Mount{
+			{Type: mount.TypeBind, Source: "/mnt/d/cache", Target: "/cache"},
+			{Type: mount.TypeVolume, Source: "logs", Target: "/logs"},
+		},
+	}
+	var stderr strings.Builder
+	translateMountPaths(&stderr, mountpath.Host{OS: "windows"}, "linux", hostConfig)
+
+	assert.Check(t, is.DeepEqual(hostConfig.Binds, []string{
+		`C:\Users\me\src:/src:ro`,
+		"/var/run/docker.sock:/var/run/docker.sock",
+		"data:/data",
+		`C:\Users\me\config:/config`,
+	}))
+	assert.Check(t, is.DeepEqual(hostConfig.Mounts, []mount.Mount{
+		{Type: mount.TypeBind, Source: `D:\cache`, Target: "/cache"},
+		{Type: mount.TypeVolume, Source: "logs", Target: "/logs"},
+	}))
+	assert.Check(t, is.Equal(stderr.String(), `Translated the Git Bash path "/c/Users/me/src" of a bind mount to "C:\\Users\\me\\src"
+Translated the WSL path "/mnt/d/cache" of a bind mount to "D:\\cache"
+`))
+}
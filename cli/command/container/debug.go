@@ -0,0 +1,184 @@
+package container
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// defaultDebugImage is the toolbox image of "docker container debug", if
+// neither the --image flag nor the "debugImage" property of the configuration
+// file is set.
+const defaultDebugImage = "busybox"
+
+// debugTargetPath is the path in the debug container where the filesystem of
+// a container that isn't running is copied to.
+const debugTargetPath = "/target"
+
+type debugOptions struct {
+	container  string
+	command    []string
+	image      string
+	privileged bool
+}
+
+// newDebugCommand creates a new cobra.Command for `docker container debug`
+func newDebugCommand(dockerCli command.Cli) *cobra.Command {
+	var opts debugOptions
+
+	cmd := &cobra.Command{
+		Use:   "debug [OPTIONS] CONTAINER [COMMAND] [ARG...]",
+		Short: "Start a shell with debugging tools next to a container",
+		Long: `Start a shell with debugging tools next to a container, in a new container of
+a toolbox image, so that containers of images without a shell can be debugged.
+
+If the container is running, the shell shares its process and network
+namespaces and its volumes; the filesystem of the container is available at
+/proc/1/root. If the container isn't running, its filesystem is copied to
+/target instead.`,
+		Args: cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			opts.command = args[1:]
+			return runDebug(cmd.Context(), dockerCli, opts)
+		},
+		Annotations: map[string]string{
+			"ostype": "linux",
+		},
+		ValidArgsFunction: completion.ContainerNames(dockerCli, true),
+	}
+
+	flags := cmd.Flags()
+	flags.SetInterspersed(false)
+	flags.StringVar(&opts.image, "image", "", `Toolbox image to use (default: the "debugImage" property of the configuration file, or "`+defaultDebugImage+`")`)
+	flags.BoolVar(&opts.privileged, "privileged", false, "Give extended privileges to the debug container")
+	return cmd
+}
+
+func runDebug(ctx context.Context, dockerCli command.Cli, opts debugOptions) error {
+	id, err := createDebugContainer(ctx, dockerCli, opts)
+	if err != nil {
+		return err
+	}
+	err = RunStart(ctx, dockerCli, &StartOptions{
+		Attach:     true,
+		OpenStdin:  true,
+		Containers: []string{id},
+	})
+	var sErr cli.StatusError
+	if err != nil && !errors.As(err, &sErr) {
+		// The container is only removed by the daemon once it started.
+		_ = dockerCli.Client().ContainerRemove(context.WithoutCancel(ctx), id, container.RemoveOptions{Force: true, RemoveVolumes: true})
+	}
+	return err
+}
+
+// createDebugContainer creates the debug container of the container of opts,
+// and returns its ID.
+func createDebugContainer(ctx context.Context, dockerCli command.Cli, opts debugOptions) (string, error) {
+	apiClient := dockerCli.Client()
+	c, err := apiClient.ContainerInspect(ctx, opts.container)
+	if err != nil {
+		return "", err
+	}
+
+	img := opts.image
+	if img == "" {
+		img = dockerCli.ConfigFile().DebugImage
+	}
+	if img == "" {
+		img = defaultDebugImage
+	}
+	config, hostConfig := debugContainerConfig(c, img, opts)
+	config.Tty = dockerCli.In().IsTerminal() && dockerCli.Out().IsTerminal()
+
+	resp, err := apiClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	if errdefs.IsNotFound(err) {
+		dockerCli.Err().Infof("Unable to find image '%s' locally\n", img)
+		if err := pullImageWithClient(ctx, dockerCli, apiClient, img); err != nil {
+			return "", err
+		}
+		resp, err = apiClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create debug container")
+	}
+
+	if c.State.Running {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Debugging container %s; its filesystem is available at /proc/1/root\n", opts.container)
+		return resp.ID, nil
+	}
+
+	// The namespaces of a container that isn't running can't be shared, so
+	// a copy of its filesystem is made instead.
+	_, _ = fmt.Fprintf(dockerCli.Err(), "Container %s is not running; copying its filesystem to %s\n", opts.container, debugTargetPath)
+	if err := copyDebugTarget(ctx, dockerCli, c.ID, resp.ID); err != nil {
+		_ = apiClient.ContainerRemove(context.WithoutCancel(ctx), resp.ID, container.RemoveOptions{Force: true, RemoveVolumes: true})
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// debugContainerConfig returns the configuration of the debug container of
+// c. The namespaces of c are only shared if it's running, and are shared
+// with the same containers as c if c shares them itself.
+func debugContainerConfig(c types.ContainerJSON, img string, opts debugOptions) (*container.Config, *container.HostConfig) {
+	cmd := opts.command
+	if len(cmd) == 0 {
+		cmd = []string{"sh"}
+	}
+	config := &container.Config{
+		Image:        img,
+		Cmd:          cmd,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Labels:       map[string]string{"com.docker.cli.debug-target": c.ID},
+	}
+	hostConfig := &container.HostConfig{
+		AutoRemove:  true,
+		Privileged:  opts.privileged,
+		CapAdd:      []string{"SYS_PTRACE"},
+		VolumesFrom: []string{c.ID},
+	}
+
+	if !c.State.Running {
+		config.Volumes = map[string]struct{}{debugTargetPath: {}}
+		return config, hostConfig
+	}
+
+	hostConfig.PidMode = container.PidMode("container:" + c.ID)
+	if c.HostConfig.PidMode.IsHost() || c.HostConfig.PidMode.IsContainer() {
+		hostConfig.PidMode = c.HostConfig.PidMode
+	}
+	hostConfig.NetworkMode = container.NetworkMode("container:" + c.ID)
+	if c.HostConfig.NetworkMode.IsHost() || c.HostConfig.NetworkMode.IsContainer() {
+		hostConfig.NetworkMode = c.HostConfig.NetworkMode
+	}
+	return config, hostConfig
+}
+
+// copyDebugTarget copies the filesystem of the container with the ID
+// targetID to the debug container with the ID debugID.
+func copyDebugTarget(ctx context.Context, dockerCli command.Cli, targetID, debugID string) error {
+	apiClient := dockerCli.Client()
+	content, err := apiClient.ContainerExport(ctx, targetID)
+	if err != nil {
+		return errors.Wrap(err, "failed to export container")
+	}
+	defer content.Close()
+	if err := apiClient.CopyToContainer(ctx, debugID, debugTargetPath, content, container.CopyToContainerOptions{}); err != nil {
+		return errors.Wrap(err, "failed to copy the filesystem of the container")
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package container
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCreateDebugContainer(t *testing.T) {
+	testCases := []struct {
+		name           string
+		running        bool
+		networkMode    container.NetworkMode
+		configImage    string
+		opts           debugOptions
+		expectedImage  string
+		expectedCmd    []string
+		expectedPid    container.PidMode
+		expectedNet    container.NetworkMode
+		expectedCopied string
+	}{
+		{
+			name:          "running",
+			running:       true,
+			networkMode:   "bridge",
+			opts:          debugOptions{container: "web"},
+			expectedImage: defaultDebugImage,
+			expectedCmd:   []string{"sh"},
+			expectedPid:   "container:web-id",
+			expectedNet:   "container:web-id",
+		},
+		{
+			name:          "host-network",
+			running:       true,
+			networkMode:   "host",
+			configImage:   "nicolaka/netshoot",
+			opts:          debugOptions{container: "web", command: []string{"tcpdump", "-i", "any"}},
+			expectedImage: "nicolaka/netshoot",
+			expectedCmd:   []string{"tcpdump", "-i", "any"},
+			expectedPid:   "container:web-id",
+			expectedNet:   "host",
+		},
+		{
+			name:           "stopped",
+			networkMode:    "bridge",
+			configImage:    "nicolaka/netshoot",
+			opts:           debugOptions{container: "web", image: "alpine"},
+			expectedImage:  "alpine",
+			expectedCmd:    []string{"sh"},
+			expectedCopied: "/target rootfs",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				config     *container.Config
+				hostConfig *container.HostConfig
+				copied     string
+			)
+			fakeCli := test.NewFakeCli(&fakeClient{
+				inspectFunc: func(string) (types.ContainerJSON, error) {
+					return types.ContainerJSON{
+						ContainerJSONBase: &types.ContainerJSONBase{
+							ID:         "web-id",
+							State:      &types.ContainerState{Running: tc.running},
+							HostConfig: &container.HostConfig{NetworkMode: tc.networkMode},
+						},
+					}, nil
+				},
+				createContainerFunc: func(c *container.Config, hc *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+					config, hostConfig = c, hc
+					return container.CreateResponse{ID: "debug-id"}, nil
+				},
+				containerExportFunc: func(string) (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader("rootfs")), nil
+				},
+				copyToContainerFunc: func(containerID, dstPath string, content io.Reader) error {
+					b, err := io.ReadAll(content)
+					copied = dstPath + " " + string(b)
+					return err
+				},
+			})
+			fakeCli.SetConfigFile(&configfile.ConfigFile{DebugImage: tc.configImage})
+
+			id, err := createDebugContainer(context.Background(), fakeCli, tc.opts)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(id, "debug-id"))
+			assert.Check(t, is.Equal(config.Image, tc.expectedImage))
+			assert.Check(t, is.DeepEqual([]string(config.Cmd), tc.expectedCmd))
+			assert.Check(t, is.Equal(hostConfig.PidMode, tc.expectedPid))
+			assert.Check(t, is.Equal(hostConfig.NetworkMode, tc.expectedNet))
+			assert.Check(t, is.DeepEqual(hostConfig.VolumesFrom, []string{"web-id"}))
+			assert.Check(t, hostConfig.AutoRemove)
+			assert.Check(t, is.Equal(copied, tc.expectedCopied))
+		})
+	}
+}
@@ -0,0 +1,522 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// composeServiceLabel is the label on which docker compose records the name
+// of the service of a container.
+const composeServiceLabel = "com.docker.compose.service"
+
+type exportComposeOptions struct {
+	containers []string
+	output     string
+}
+
+// newExportComposeCommand creates a new `docker container export-compose` command
+func newExportComposeCommand(dockerCli command.Cli) *cobra.Command {
+	var opts exportComposeOptions
+
+	cmd := &cobra.Command{
+		Use:   "export-compose [OPTIONS] CONTAINER [CONTAINER...]",
+		Short: "Generate a Compose file from one or more containers",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.containers = args
+			return runExportCompose(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.ContainerNames(dockerCli, true),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+
+	return cmd
+}
+
+func runExportCompose(ctx context.Context, dockerCli command.Cli, opts exportComposeOptions) error {
+	if err := command.ValidateOutputPath(opts.output); err != nil {
+		return errors.Wrap(err, "failed to export containers")
+	}
+
+	var containers []types.ContainerJSON
+	for _, name := range opts.containers {
+		c, err := dockerCli.Client().ContainerInspect(ctx, name)
+		if err != nil {
+			return err
+		}
+		containers = append(containers, c)
+	}
+	images := make(map[string]*container.Config)
+	for _, c := range containers {
+		if _, ok := images[c.Image]; ok {
+			continue
+		}
+		// The image may have been removed since the container was created,
+		// in which case all options of the container are exported.
+		img, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, c.Image)
+		if err != nil || img.Config == nil {
+			images[c.Image] = &container.Config{}
+			continue
+		}
+		images[c.Image] = img.Config
+	}
+
+	project, warnings := composeFromContainers(containers, images)
+	for _, w := range warnings {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", w)
+	}
+	out, err := yaml.Marshal(project)
+	if err != nil {
+		return err
+	}
+
+	if opts.output == "" {
+		_, err := dockerCli.Out().Write(out)
+		return err
+	}
+	return os.WriteFile(opts.output, out, 0o644)
+}
+
+// composeFile is the subset of a Compose file that is generated from
+// containers.
+type composeFile struct {
+	Services composetypes.Services                 `yaml:"services"`
+	Networks map[string]composetypes.NetworkConfig `yaml:",omitempty"`
+	Volumes  map[string]composetypes.VolumeConfig  `yaml:",omitempty"`
+}
+
+// composeFromContainers generates a Compose file with a service for each
+// container. Options that are set by the image of a container are omitted.
+// images holds the config of the image of each container, by image ID.
+//
+// Named volumes and user-defined networks are declared with their current
+// name, so that the Compose file uses the existing volumes and networks. The
+// dependencies between services are derived from links, shared namespaces,
+// "--volumes-from", and environment variables that refer to another
+// container by name.
+//
+// It returns warnings for options that cannot be represented.
+func composeFromContainers(containers []types.ContainerJSON, images map[string]*container.Config) (composeFile, []string) {
+	project := composeFile{
+		Networks: map[string]composetypes.NetworkConfig{},
+		Volumes:  map[string]composetypes.VolumeConfig{},
+	}
+	var warnings []string
+
+	// Map the names and IDs of the containers to their service names.
+	serviceNames := make(map[string]string, 2*len(containers))
+	used := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		name := strings.TrimPrefix(c.Name, "/")
+		if s := c.Config.Labels[composeServiceLabel]; s != "" && !used[s] {
+			name = s
+		}
+		for used[name] {
+			name += "_"
+		}
+		used[name] = true
+		serviceNames[c.ID] = name
+		serviceNames[strings.TrimPrefix(c.Name, "/")] = name
+	}
+
+	for _, c := range containers {
+		img := images[c.Image]
+		if img == nil {
+			img = &container.Config{}
+		}
+		name := serviceNames[c.ID]
+		svc := composetypes.ServiceConfig{
+			Name:        name,
+			Image:       c.Config.Image,
+			Environment: composetypes.MappingWithEquals{},
+			Labels:      composetypes.Labels{},
+			Networks:    map[string]*composetypes.ServiceNetworkConfig{},
+			Extras:      map[string]any{},
+		}
+		if !equalStrings(c.Config.Entrypoint, img.Entrypoint) {
+			svc.Entrypoint = composetypes.ShellCommand(c.Config.Entrypoint)
+		}
+		if !equalStrings(c.Config.Cmd, img.Cmd) || svc.Entrypoint != nil {
+			svc.Command = composetypes.ShellCommand(c.Config.Cmd)
+		}
+		if c.Config.User != img.User {
+			svc.User = c.Config.User
+		}
+		if c.Config.WorkingDir != img.WorkingDir {
+			svc.WorkingDir = c.Config.WorkingDir
+		}
+		if c.Config.StopSignal != img.StopSignal {
+			svc.StopSignal = c.Config.StopSignal
+		}
+		svc.Tty = c.Config.Tty
+		svc.StdinOpen = c.Config.OpenStdin
+		svc.HealthCheck = composeHealthCheck(c.Config.Healthcheck, img.Healthcheck)
+
+		imageEnv := make(map[string]bool, len(img.Env))
+		for _, e := range img.Env {
+			imageEnv[e] = true
+		}
+		for _, e := range c.Config.Env {
+			if imageEnv[e] {
+				continue
+			}
+			k, v, _ := strings.Cut(e, "=")
+			svc.Environment[k] = &v
+		}
+		for k, v := range c.Config.Labels {
+			if strings.HasPrefix(k, "com.docker.compose.") {
+				continue
+			}
+			if iv, ok := img.Labels[k]; ok && iv == v {
+				continue
+			}
+			svc.Labels[k] = v
+		}
+
+		if hc := c.HostConfig; hc != nil {
+			warnings = append(warnings, composeHostConfig(&svc, hc, serviceNames)...)
+			svc.Ports, warnings = composePorts(name, hc, warnings)
+		}
+		for port := range c.Config.ExposedPorts {
+			if _, ok := img.ExposedPorts[port]; ok {
+				continue
+			}
+			if c.HostConfig != nil {
+				if _, ok := c.HostConfig.PortBindings[port]; ok {
+					continue
+				}
+			}
+			svc.Expose = append(svc.Expose, string(port))
+		}
+		sort.Strings(svc.Expose)
+
+		svc.Volumes = composeVolumes(c, img, project.Volumes)
+		composeNetworks(&svc, c, project.Networks)
+		svc.DependsOn = composeDependsOn(&svc, c, serviceNames)
+		project.Services = append(project.Services, svc)
+	}
+	return project, warnings
+}
+
+func composeHostConfig(svc *composetypes.ServiceConfig, hc *container.HostConfig, serviceNames map[string]string) []string {
+	var warnings []string
+
+	switch {
+	case hc.RestartPolicy.IsOnFailure() && hc.RestartPolicy.MaximumRetryCount > 0:
+		svc.Restart = fmt.Sprintf("%s:%d", hc.RestartPolicy.Name, hc.RestartPolicy.MaximumRetryCount)
+	case hc.RestartPolicy.Name != "" && hc.RestartPolicy.Name != container.RestartPolicyDisabled:
+		svc.Restart = string(hc.RestartPolicy.Name)
+	}
+	svc.Privileged = hc.Privileged
+	svc.ReadOnly = hc.ReadonlyRootfs
+	if hc.Init != nil && *hc.Init {
+		svc.Init = hc.Init
+	}
+	svc.CapAdd = hc.CapAdd
+	svc.CapDrop = hc.CapDrop
+	svc.DNS = hc.DNS
+	svc.DNSSearch = hc.DNSSearch
+	svc.ExtraHosts = hc.ExtraHosts
+	svc.SecurityOpt = hc.SecurityOpt
+	if len(hc.Tmpfs) > 0 {
+		for target, options := range hc.Tmpfs {
+			if options != "" {
+				target += ":" + options
+			}
+			svc.Tmpfs = append(svc.Tmpfs, target)
+		}
+		sort.Strings(svc.Tmpfs)
+	}
+	if hc.LogConfig.Type != "" && len(hc.LogConfig.Config) > 0 {
+		svc.Logging = &composetypes.LoggingConfig{Driver: hc.LogConfig.Type, Options: hc.LogConfig.Config}
+	}
+	if hc.Memory > 0 || hc.NanoCPUs > 0 || (hc.PidsLimit != nil && *hc.PidsLimit > 0) {
+		limits := &composetypes.ResourceLimit{MemoryBytes: composetypes.UnitBytes(hc.Memory)}
+		if hc.NanoCPUs > 0 {
+			limits.NanoCPUs = strconv.FormatFloat(float64(hc.NanoCPUs)/1e9, 'f', -1, 64)
+		}
+		if hc.PidsLimit != nil && *hc.PidsLimit > 0 {
+			limits.Pids = *hc.PidsLimit
+		}
+		svc.Deploy.Resources.Limits = limits
+	}
+
+	switch mode := hc.NetworkMode; {
+	case mode.IsHost(), mode.IsNone():
+		svc.NetworkMode = string(mode)
+	case mode.IsContainer():
+		if s, ok := serviceNames[mode.ConnectedContainer()]; ok {
+			svc.NetworkMode = "service:" + s
+		} else {
+			svc.NetworkMode = string(mode)
+		}
+	}
+	svc.Pid = composeNamespaceMode(string(hc.PidMode), serviceNames)
+	svc.Ipc = composeNamespaceMode(string(hc.IpcMode), serviceNames)
+	if len(hc.VolumesFrom) > 0 {
+		var volumesFrom []string
+		for _, v := range hc.VolumesFrom {
+			ref, mode, _ := strings.Cut(v, ":")
+			if s, ok := serviceNames[ref]; ok {
+				v = s
+				if mode != "" {
+					v += ":" + mode
+				}
+			} else {
+				v = "container:" + v
+			}
+			volumesFrom = append(volumesFrom, v)
+		}
+		svc.Extras["volumes_from"] = volumesFrom
+	}
+	if len(hc.Devices) > 0 {
+		warnings = append(warnings, fmt.Sprintf("devices of container %q are not exported", svc.Name))
+	}
+	return warnings
+}
+
+// composeNamespaceMode returns the "pid" or "ipc" option for mode. Only the
+// modes that share a namespace with the host or another container are
+// exported.
+func composeNamespaceMode(mode string, serviceNames map[string]string) string {
+	ref, isContainer := strings.CutPrefix(mode, "container:")
+	switch {
+	case isContainer && serviceNames[ref] != "":
+		return "service:" + serviceNames[ref]
+	case mode == "host" || isContainer:
+		return mode
+	}
+	return ""
+}
+
+func composePorts(name string, hc *container.HostConfig, warnings []string) ([]composetypes.ServicePortConfig, []string) {
+	var ports []composetypes.ServicePortConfig
+	for port, bindings := range hc.PortBindings {
+		published := make(map[uint32]bool)
+		for _, b := range bindings {
+			if b.HostIP != "" && b.HostIP != "0.0.0.0" && b.HostIP != "::" {
+				warnings = append(warnings, fmt.Sprintf("the host IP (%s) of published port %s of %q is not exported", b.HostIP, port, name))
+			}
+			var hostPort uint32
+			if b.HostPort != "" {
+				p, err := strconv.ParseUint(b.HostPort, 10, 16)
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("the host port (%s) of published port %s of %q is not exported", b.HostPort, port, name))
+				}
+				hostPort = uint32(p)
+			}
+			if published[hostPort] {
+				continue
+			}
+			published[hostPort] = true
+			ports = append(ports, composetypes.ServicePortConfig{
+				Target:    uint32(port.Int()),
+				Published: hostPort,
+				Protocol:  port.Proto(),
+			})
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Target != ports[j].Target {
+			return ports[i].Target < ports[j].Target
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Published < ports[j].Published
+	})
+	return ports, warnings
+}
+
+func composeHealthCheck(hc, imageHC *container.HealthConfig) *composetypes.HealthCheckConfig {
+	if hc == nil || (imageHC != nil && equalHealthConfig(*hc, *imageHC)) {
+		return nil
+	}
+	if len(hc.Test) == 1 && hc.Test[0] == "NONE" {
+		return &composetypes.HealthCheckConfig{Disable: true}
+	}
+	duration := func(d int64) *composetypes.Duration {
+		if d == 0 {
+			return nil
+		}
+		v := composetypes.Duration(d)
+		return &v
+	}
+	cfg := &composetypes.HealthCheckConfig{
+		Test:          composetypes.HealthCheckTest(hc.Test),
+		Interval:      duration(int64(hc.Interval)),
+		Timeout:       duration(int64(hc.Timeout)),
+		StartPeriod:   duration(int64(hc.StartPeriod)),
+		StartInterval: duration(int64(hc.StartInterval)),
+	}
+	if hc.Retries > 0 {
+		retries := uint64(hc.Retries)
+		cfg.Retries = &retries
+	}
+	return cfg
+}
+
+func equalHealthConfig(a, b container.HealthConfig) bool {
+	return equalStrings(a.Test, b.Test) && a.Interval == b.Interval && a.Timeout == b.Timeout &&
+		a.StartPeriod == b.StartPeriod && a.StartInterval == b.StartInterval && a.Retries == b.Retries
+}
+
+// composeVolumes returns the mounts of c, and declares the named volumes it
+// uses in volumes. Anonymous volumes that are created for the volumes of
+// the image are omitted.
+func composeVolumes(c types.ContainerJSON, img *container.Config, volumes map[string]composetypes.VolumeConfig) []composetypes.ServiceVolumeConfig {
+	var result []composetypes.ServiceVolumeConfig
+	for _, m := range c.Mounts {
+		v := composetypes.ServiceVolumeConfig{
+			Type:     string(m.Type),
+			Target:   m.Destination,
+			ReadOnly: !m.RW,
+		}
+		switch m.Type {
+		case mount.TypeBind:
+			v.Source = m.Source
+		case mount.TypeVolume:
+			if _, ok := img.Volumes[m.Destination]; ok && isAnonymousVolume(m.Name) {
+				continue
+			}
+			v.Source = m.Name
+			if !isAnonymousVolume(m.Name) {
+				cfg := composetypes.VolumeConfig{Name: m.Name}
+				if m.Driver != "" && m.Driver != "local" {
+					cfg.Driver = m.Driver
+				}
+				volumes[m.Name] = cfg
+			} else {
+				v.Source = ""
+			}
+		default:
+			// tmpfs mounts are exported as "tmpfs", and other types of
+			// mounts cannot be represented.
+			continue
+		}
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Target < result[j].Target })
+	return result
+}
+
+var anonymousVolumeRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+func isAnonymousVolume(name string) bool {
+	return anonymousVolumeRegexp.MatchString(name)
+}
+
+// composeNetworks sets the networks of svc to the user-defined networks
+// that c is connected to, and declares these networks in networks. Containers
+// that are only connected to the default "bridge" network use the default
+// network of the project, on which services can reach each other by name.
+func composeNetworks(svc *composetypes.ServiceConfig, c types.ContainerJSON, networks map[string]composetypes.NetworkConfig) {
+	if c.NetworkSettings == nil || svc.NetworkMode != "" {
+		return
+	}
+	names := make([]string, 0, len(c.NetworkSettings.Networks))
+	for name := range c.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == network.NetworkBridge || name == network.NetworkHost || name == network.NetworkNone {
+			continue
+		}
+		// Compose prefixes network names with the project name, unless
+		// they have a name.
+		networks[name] = composetypes.NetworkConfig{Name: name}
+
+		ep := c.NetworkSettings.Networks[name]
+		cfg := &composetypes.ServiceNetworkConfig{}
+		for _, alias := range ep.Aliases {
+			if alias != svc.Name && alias != strings.TrimPrefix(c.Name, "/") && !strings.HasPrefix(c.ID, alias) {
+				cfg.Aliases = append(cfg.Aliases, alias)
+			}
+		}
+		if ep.IPAMConfig != nil {
+			cfg.Ipv4Address = ep.IPAMConfig.IPv4Address
+			cfg.Ipv6Address = ep.IPAMConfig.IPv6Address
+		}
+		if len(cfg.Aliases) == 0 && cfg.Ipv4Address == "" && cfg.Ipv6Address == "" {
+			cfg = nil
+		}
+		svc.Networks[name] = cfg
+	}
+}
+
+var hostnameRegexp = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9_.-]*`)
+
+// composeDependsOn returns the services that svc depends on: the services it
+// links to, shares a namespace or volumes with, or refers to by name in an
+// environment variable (for example, "DB_HOST=db").
+func composeDependsOn(svc *composetypes.ServiceConfig, c types.ContainerJSON, serviceNames map[string]string) []string {
+	deps := map[string]bool{}
+	addDep := func(ref string) {
+		if s, ok := serviceNames[ref]; ok && s != svc.Name {
+			deps[s] = true
+		}
+	}
+	if hc := c.HostConfig; hc != nil {
+		for _, link := range hc.Links {
+			// links are stored as "/target:/container/alias".
+			target, _, _ := strings.Cut(link, ":")
+			addDep(strings.TrimPrefix(target, "/"))
+		}
+		for _, v := range hc.VolumesFrom {
+			ref, _, _ := strings.Cut(v, ":")
+			addDep(ref)
+		}
+		for _, mode := range []string{string(hc.NetworkMode), string(hc.PidMode), string(hc.IpcMode)} {
+			if ref, ok := strings.CutPrefix(mode, "container:"); ok {
+				addDep(ref)
+			}
+		}
+	}
+	for _, v := range svc.Environment {
+		if v == nil {
+			continue
+		}
+		for _, word := range hostnameRegexp.FindAllString(*v, -1) {
+			addDep(word)
+		}
+	}
+
+	result := make([]string, 0, len(deps))
+	for s := range deps {
+		result = append(result, s)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,173 @@
+package container
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func exportComposeTestContainers() map[string]types.ContainerJSON {
+	restart := true
+	return map[string]types.ContainerJSON{
+		"web": {
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "1111111111111111111111111111111111111111111111111111111111111111",
+				Name:  "/web",
+				Image: "sha256:nginx",
+				HostConfig: &container.HostConfig{
+					RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyUnlessStopped},
+					PortBindings: nat.PortMap{
+						"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "8080"}, {HostIP: "::", HostPort: "8080"}},
+						"443/tcp": {{HostIP: "127.0.0.1", HostPort: "8443"}},
+					},
+					NetworkMode: "appnet",
+					Init:        &restart,
+				},
+			},
+			Config: &container.Config{
+				Image:  "nginx:1.27",
+				Env:    []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin", "NGINX_VERSION=1.27.0", "DB_HOST=db", "DEBUG="},
+				Cmd:    []string{"nginx", "-g", "daemon off;"},
+				Labels: map[string]string{"maintainer": "NGINX Docker Maintainers", "com.example.tier": "frontend"},
+				ExposedPorts: nat.PortSet{
+					"80/tcp":   {},
+					"443/tcp":  {},
+					"9113/tcp": {},
+				},
+			},
+			Mounts: []types.MountPoint{
+				{Type: mount.TypeVolume, Name: "webdata", Destination: "/usr/share/nginx/html", Driver: "local", RW: true},
+				{Type: mount.TypeBind, Source: "/srv/nginx.conf", Destination: "/etc/nginx/nginx.conf"},
+			},
+			NetworkSettings: &types.NetworkSettings{
+				Networks: map[string]*network.EndpointSettings{
+					"appnet": {Aliases: []string{"web", "111111111111", "frontend"}},
+				},
+			},
+		},
+		"db": {
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "2222222222222222222222222222222222222222222222222222222222222222",
+				Name:  "/db",
+				Image: "sha256:postgres",
+				HostConfig: &container.HostConfig{
+					RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+					NetworkMode:   "appnet",
+					Resources:     container.Resources{Memory: 512 * 1024 * 1024, NanoCPUs: 1500000000},
+				},
+			},
+			Config: &container.Config{
+				Image:      "postgres:16",
+				Env:        []string{"PATH=/usr/local/bin:/usr/bin:/bin", "POSTGRES_PASSWORD=secret"},
+				Cmd:        []string{"postgres"},
+				Entrypoint: []string{"docker-entrypoint.sh"},
+				Healthcheck: &container.HealthConfig{
+					Test:     []string{"CMD-SHELL", "pg_isready"},
+					Interval: 10 * time.Second,
+					Retries:  5,
+				},
+			},
+			Mounts: []types.MountPoint{
+				{Type: mount.TypeVolume, Name: "3333333333333333333333333333333333333333333333333333333333333333", Destination: "/var/lib/postgresql/data", Driver: "local", RW: true},
+			},
+			NetworkSettings: &types.NetworkSettings{
+				Networks: map[string]*network.EndpointSettings{
+					"appnet": {Aliases: []string{"db", "222222222222"}},
+				},
+			},
+		},
+		"sidecar": {
+			ContainerJSONBase: &types.ContainerJSONBase{
+				ID:    "4444444444444444444444444444444444444444444444444444444444444444",
+				Name:  "/sidecar",
+				Image: "sha256:busybox",
+				HostConfig: &container.HostConfig{
+					NetworkMode: "container:1111111111111111111111111111111111111111111111111111111111111111",
+					VolumesFrom: []string{"web:ro"},
+				},
+			},
+			Config: &container.Config{
+				Image: "busybox",
+				Cmd:   []string{"sleep", "infinity"},
+			},
+			NetworkSettings: &types.NetworkSettings{},
+		},
+	}
+}
+
+var exportComposeTestImages = map[string]types.ImageInspect{
+	"sha256:nginx": {Config: &container.Config{
+		Env:          []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin", "NGINX_VERSION=1.27.0"},
+		Cmd:          []string{"nginx", "-g", "daemon off;"},
+		Labels:       map[string]string{"maintainer": "NGINX Docker Maintainers"},
+		ExposedPorts: nat.PortSet{"80/tcp": {}},
+	}},
+	"sha256:postgres": {Config: &container.Config{
+		Env:        []string{"PATH=/usr/local/bin:/usr/bin:/bin"},
+		Cmd:        []string{"postgres"},
+		Entrypoint: []string{"docker-entrypoint.sh"},
+		Volumes:    map[string]struct{}{"/var/lib/postgresql/data": {}},
+	}},
+}
+
+func TestExportCompose(t *testing.T) {
+	containers := exportComposeTestContainers()
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return containers[name], nil
+		},
+		imageInspectFunc: func(imageID string) (types.ImageInspect, []byte, error) {
+			img, ok := exportComposeTestImages[imageID]
+			if !ok {
+				return types.ImageInspect{}, nil, os.ErrNotExist
+			}
+			return img, nil, nil
+		},
+	})
+	cmd := newExportComposeCommand(cli)
+	cmd.SetArgs([]string{"web", "db", "sidecar"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "container-export-compose.golden")
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "WARNING: the host IP (127.0.0.1) of published port 443/tcp of \"web\" is not exported\n"))
+}
+
+func TestExportComposeOutputFile(t *testing.T) {
+	containers := exportComposeTestContainers()
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return containers[name], nil
+		},
+	})
+	output := filepath.Join(t.TempDir(), "compose.yaml")
+	cmd := newExportComposeCommand(cli)
+	cmd.SetArgs([]string{"--output", output, "sidecar"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+
+	content, err := os.ReadFile(output)
+	assert.NilError(t, err)
+	const expected = `services:
+  sidecar:
+    command:
+    - sleep
+    - infinity
+    image: busybox
+    network_mode: container:1111111111111111111111111111111111111111111111111111111111111111
+    volumes_from:
+    - container:web:ro
+`
+	assert.Check(t, is.Equal(string(content), expected))
+}
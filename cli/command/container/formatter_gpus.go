@@ -0,0 +1,72 @@
+package container
+
+import (
+	"strconv"
+
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultGPUsTableFormat = "table {{.Index}}\t{{.Device}}\t{{.Runtime}}"
+
+	gpuIndexHeader   = "INDEX"
+	gpuDeviceHeader  = "DEVICE"
+	gpuRuntimeHeader = "RUNTIME"
+)
+
+// newGPUsFormat returns a format for use with a GPUs Context
+func newGPUsFormat(source string) formatter.Format {
+	if source == formatter.TableFormatKey {
+		return defaultGPUsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// gpusFormatWrite writes the GPUs using the Context
+func gpusFormatWrite(ctx formatter.Context, gpus []gpuDevice) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, g := range gpus {
+			if err := format(&gpuContext{g: g}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newGPUContext(), render)
+}
+
+type gpuContext struct {
+	formatter.HeaderContext
+	g gpuDevice
+}
+
+func newGPUContext() *gpuContext {
+	gpuCtx := gpuContext{}
+	gpuCtx.Header = formatter.SubHeaderContext{
+		"Index":   gpuIndexHeader,
+		"Device":  gpuDeviceHeader,
+		"Runtime": gpuRuntimeHeader,
+	}
+	return &gpuCtx
+}
+
+func (c *gpuContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+// Index is the index of the GPU, as used in "--gpus device=INDEX".
+func (c *gpuContext) Index() string {
+	return strconv.Itoa(c.g.index)
+}
+
+func (c *gpuContext) Device() string {
+	return c.g.device
+}
+
+// Runtime is the GPU runtime of the daemon, or "none".
+func (c *gpuContext) Runtime() string {
+	if c.g.runtime == "" {
+		return "none"
+	}
+	return c.g.runtime
+}
@@ -0,0 +1,164 @@
+package container
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	units "github.com/docker/go-units"
+)
+
+const (
+	defaultPoliciesTableFormat = "table {{.Name}}\t{{.RestartPolicy}}\t{{.OOMKill}}\t{{.OOMScoreAdj}}\t{{.Memory}}\t{{.CPUs}}\t{{.PidsLimit}}"
+
+	restartPolicyHeader = "RESTART POLICY"
+	oomKillHeader       = "OOM KILL"
+	oomScoreAdjHeader   = "OOM SCORE ADJ"
+	memoryHeader        = "MEMORY"
+	memorySwapHeader    = "MEMORY SWAP"
+	cpusHeader          = "CPUS"
+	cpuSharesHeader     = "CPU SHARES"
+	pidsLimitHeader     = "PIDS LIMIT"
+
+	unlimited = "unlimited"
+)
+
+// newPoliciesFormat returns a format for use with a policies Context
+func newPoliciesFormat(source string) formatter.Format {
+	if source == formatter.TableFormatKey {
+		return defaultPoliciesTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// policiesFormatWrite writes the policies of containers using the Context
+func policiesFormatWrite(ctx formatter.Context, containers []types.ContainerJSON) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, c := range containers {
+			if err := format(&policiesContext{c: c}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newPoliciesContext(), render)
+}
+
+type policiesContext struct {
+	formatter.HeaderContext
+	c types.ContainerJSON
+}
+
+func newPoliciesContext() *policiesContext {
+	policiesCtx := policiesContext{}
+	policiesCtx.Header = formatter.SubHeaderContext{
+		"Name":          formatter.NameHeader,
+		"RestartPolicy": restartPolicyHeader,
+		"OOMKill":       oomKillHeader,
+		"OOMScoreAdj":   oomScoreAdjHeader,
+		"Memory":        memoryHeader,
+		"MemorySwap":    memorySwapHeader,
+		"CPUs":          cpusHeader,
+		"CPUShares":     cpuSharesHeader,
+		"PidsLimit":     pidsLimitHeader,
+	}
+	return &policiesCtx
+}
+
+func (c *policiesContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *policiesContext) Name() string {
+	return strings.TrimPrefix(c.c.Name, "/")
+}
+
+// RestartPolicy is the restart policy of the container, in the format of the
+// "--restart" option, such as "on-failure:3".
+func (c *policiesContext) RestartPolicy() string {
+	if c.c.HostConfig == nil || c.c.HostConfig.RestartPolicy.Name == "" {
+		return "no"
+	}
+	p := c.c.HostConfig.RestartPolicy
+	if p.IsOnFailure() && p.MaximumRetryCount > 0 {
+		return string(p.Name) + ":" + strconv.Itoa(p.MaximumRetryCount)
+	}
+	return string(p.Name)
+}
+
+// OOMKill is whether the OOM killer can kill the processes of the container.
+func (c *policiesContext) OOMKill() string {
+	if c.c.HostConfig != nil && c.c.HostConfig.OomKillDisable != nil && *c.c.HostConfig.OomKillDisable {
+		return "disabled"
+	}
+	return "enabled"
+}
+
+func (c *policiesContext) OOMScoreAdj() string {
+	if c.c.HostConfig == nil {
+		return "0"
+	}
+	return strconv.Itoa(c.c.HostConfig.OomScoreAdj)
+}
+
+func (c *policiesContext) Memory() string {
+	if c.c.HostConfig == nil || c.c.HostConfig.Memory <= 0 {
+		return unlimited
+	}
+	return units.BytesSize(float64(c.c.HostConfig.Memory))
+}
+
+// MemorySwap is the limit of memory plus swap. It's twice the memory limit if
+// it's not set.
+func (c *policiesContext) MemorySwap() string {
+	if c.c.HostConfig == nil || c.c.HostConfig.MemorySwap < 0 {
+		return unlimited
+	}
+	if c.c.HostConfig.MemorySwap == 0 {
+		if c.c.HostConfig.Memory <= 0 {
+			return unlimited
+		}
+		return units.BytesSize(float64(2 * c.c.HostConfig.Memory))
+	}
+	return units.BytesSize(float64(c.c.HostConfig.MemorySwap))
+}
+
+// CPUs is the number of CPUs that the container can use, from either the
+// "--cpus" option or the CPU CFS quota and period.
+func (c *policiesContext) CPUs() string {
+	if c.c.HostConfig == nil {
+		return unlimited
+	}
+	var cpus float64
+	switch r := c.c.HostConfig.Resources; {
+	case r.NanoCPUs > 0:
+		cpus = float64(r.NanoCPUs) / 1e9
+	case r.CPUQuota > 0:
+		period := r.CPUPeriod
+		if period == 0 {
+			// Default CFS period of the kernel.
+			period = 100000
+		}
+		cpus = float64(r.CPUQuota) / float64(period)
+	default:
+		return unlimited
+	}
+	return strconv.FormatFloat(cpus, 'f', -1, 64)
+}
+
+// CPUShares is the relative weight of the container for CPU time, which
+// defaults to 1024 if it's not set.
+func (c *policiesContext) CPUShares() string {
+	if c.c.HostConfig == nil || c.c.HostConfig.CPUShares == 0 {
+		return "1024"
+	}
+	return strconv.FormatInt(c.c.HostConfig.CPUShares, 10)
+}
+
+func (c *policiesContext) PidsLimit() string {
+	if c.c.HostConfig == nil || c.c.HostConfig.PidsLimit == nil || *c.c.HostConfig.PidsLimit <= 0 {
+		return unlimited
+	}
+	return strconv.FormatInt(*c.c.HostConfig.PidsLimit, 10)
+}
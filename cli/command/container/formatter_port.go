@@ -0,0 +1,106 @@
+package container
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	defaultPortTableFormat = "table {{.Port}}\t{{.HostIP}}\t{{.HostPort}}"
+
+	// hostPortTableFormat is the default format of "docker port --host",
+	// which shows the mappings of several containers.
+	hostPortTableFormat = "table {{.Container}}\t{{.Port}}\t{{.HostIP}}\t{{.HostPort}}"
+
+	portHeader          = "PORT"
+	hostIPHeader        = "HOST IP"
+	hostPortHeader      = "HOST PORT"
+	portContainerHeader = "CONTAINER"
+)
+
+// portMapping is the mapping of a port of a container to a port of the host.
+type portMapping struct {
+	container string
+	port      nat.Port
+	binding   nat.PortBinding
+	// status is the result of checking that the port accepts connections,
+	// if it was checked.
+	status string
+}
+
+// hostAddress is the address of the host, such as "0.0.0.0:8080".
+func (m portMapping) hostAddress() string {
+	return net.JoinHostPort(m.binding.HostIP, m.binding.HostPort)
+}
+
+func (m portMapping) String() string {
+	return fmt.Sprintf("%s -> %s", m.port, m.hostAddress())
+}
+
+// NewPortFormat returns a format for use with a port Context
+func NewPortFormat(source string) formatter.Format {
+	if source == formatter.TableFormatKey {
+		return defaultPortTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// portFormatWrite writes the port mappings using the Context
+func portFormatWrite(ctx formatter.Context, mappings []portMapping) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, m := range mappings {
+			if err := format(&portContext{m: m}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newPortContext(), render)
+}
+
+type portContext struct {
+	formatter.HeaderContext
+	m portMapping
+}
+
+func newPortContext() *portContext {
+	portCtx := portContext{}
+	portCtx.Header = formatter.SubHeaderContext{
+		"Container": portContainerHeader,
+		"Port":      portHeader,
+		"HostIP":    hostIPHeader,
+		"HostPort":  hostPortHeader,
+		"Status":    formatter.StatusHeader,
+	}
+	return &portCtx
+}
+
+func (c *portContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+// Container is the name of the container, which is only set by "docker port --host".
+func (c *portContext) Container() string {
+	return c.m.container
+}
+
+// Port is the port of the container, with its protocol, such as "80/tcp".
+func (c *portContext) Port() string {
+	return string(c.m.port)
+}
+
+func (c *portContext) HostIP() string {
+	return c.m.binding.HostIP
+}
+
+func (c *portContext) HostPort() string {
+	return c.m.binding.HostPort
+}
+
+// Status is the result of "docker port --check".
+func (c *portContext) Status() string {
+	return c.m.status
+}
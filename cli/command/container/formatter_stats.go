@@ -101,7 +101,10 @@ func (cs *Stats) GetStatistics() StatsEntry {
 
 // NewStatsFormat returns a format for rendering an CStatsContext
 func NewStatsFormat(source, osType string) formatter.Format {
-	if source == formatter.TableFormatKey {
+	switch source {
+	case formatter.CSVFormatKey, formatter.TSVFormatKey:
+		return NewStatsFormat(formatter.TableFormatKey, osType).Delimited(source)
+	case formatter.TableFormatKey:
 		if osType == winOSType {
 			return winDefaultStatsTableFormat
 		}
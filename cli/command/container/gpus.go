@@ -0,0 +1,134 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+const (
+	// gpuHelperPath is the path where the helper container of "docker system
+	// gpus" has the /dev directory of the host mounted.
+	gpuHelperPath = "/host-dev"
+
+	gpuHelperLabel = "com.docker.cli.gpus-helper"
+
+	// maxGPUs is the number of NVIDIA device nodes that are probed.
+	maxGPUs = 64
+)
+
+// GPUsOptions are the options of "docker system gpus".
+type GPUsOptions struct {
+	Format      string
+	HelperImage string
+}
+
+// gpuDevice is a GPU of the host of the daemon.
+type gpuDevice struct {
+	index  int
+	device string
+	// runtime is the GPU runtime of the daemon, or empty if it has none.
+	runtime string
+}
+
+// RunGPUs lists the GPUs of the host of the daemon, and the GPU runtime that
+// makes them available to containers.
+func RunGPUs(ctx context.Context, dockerCli command.Cli, opts GPUsOptions) error {
+	runtime, err := gpuRuntime(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	gpus, err := listGPUs(ctx, dockerCli, opts.HelperImage)
+	if err != nil {
+		return err
+	}
+	for i := range gpus {
+		gpus[i].runtime = runtime
+	}
+	if len(gpus) > 0 && runtime == "" {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING: the daemon has no GPU runtime; install the NVIDIA Container Toolkit to use GPUs in containers")
+	}
+
+	format := opts.Format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	return gpusFormatWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newGPUsFormat(format),
+	}, gpus)
+}
+
+// gpuRuntime returns the name of the GPU runtime of the daemon, which is the
+// first runtime that has "nvidia" in its name or path, or an empty string if
+// the daemon has no GPU runtime.
+func gpuRuntime(ctx context.Context, dockerCli command.Cli) (string, error) {
+	info, err := dockerCli.Client().Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(info.Runtimes))
+	for name := range info.Runtimes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if strings.Contains(name, "nvidia") || strings.Contains(info.Runtimes[name].Path, "nvidia") {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// listGPUs returns the NVIDIA GPUs of the host of the daemon, from the device
+// nodes in its /dev directory, which are stat-ed through a helper container
+// that is never started.
+func listGPUs(ctx context.Context, dockerCli command.Cli, helperImage string) ([]gpuDevice, error) {
+	if helperImage == "" {
+		helperImage = defaultMigrateHelperImage
+	}
+	apiClient := dockerCli.Client()
+	id, err := createHelperContainer(ctx, dockerCli, apiClient, helperImage, gpuHelperLabel, "/dev:"+gpuHelperPath+":ro")
+	if err != nil {
+		return nil, err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, apiClient, id)
+
+	var gpus []gpuDevice
+	for i := 0; i < maxGPUs; i++ {
+		name := "nvidia" + strconv.Itoa(i)
+		_, err := apiClient.ContainerStatPath(ctx, id, gpuHelperPath+"/"+name)
+		if errdefs.IsNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list GPUs")
+		}
+		gpus = append(gpus, gpuDevice{index: i, device: "/dev/" + name})
+	}
+	return gpus, nil
+}
+
+// checkGPURuntime returns an error if all GPUs are requested with "--gpus
+// auto", and the daemon has no GPU runtime.
+func checkGPURuntime(ctx context.Context, dockerCli command.Cli, copts *containerOptions) error {
+	if !copts.gpus.Auto() {
+		return nil
+	}
+	runtime, err := gpuRuntime(ctx, dockerCli)
+	if err != nil {
+		return err
+	}
+	if runtime == "" {
+		return errors.New("--gpus auto requires a GPU runtime, but the daemon has none; install the NVIDIA Container Toolkit, or use \"docker system gpus\" to list the GPUs of the daemon")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/errdefs"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRunGPUs(t *testing.T) {
+	testCases := []struct {
+		name           string
+		runtimes       map[string]system.RuntimeWithStatus
+		expectedOut    string
+		expectedStderr string
+	}{
+		{
+			name: "with-runtime",
+			runtimes: map[string]system.RuntimeWithStatus{
+				"runc":   {},
+				"gpu-rt": {Runtime: system.Runtime{Path: "/usr/bin/nvidia-container-runtime"}},
+			},
+			expectedOut: "INDEX     DEVICE         RUNTIME\n0         /dev/nvidia0   gpu-rt\n1         /dev/nvidia1   gpu-rt\n",
+		},
+		{
+			name:           "without-runtime",
+			runtimes:       map[string]system.RuntimeWithStatus{"runc": {}},
+			expectedOut:    "INDEX     DEVICE         RUNTIME\n0         /dev/nvidia0   none\n1         /dev/nvidia1   none\n",
+			expectedStderr: "WARNING: the daemon has no GPU runtime; install the NVIDIA Container Toolkit to use GPUs in containers\n",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var binds []string
+			removed := false
+			fakeCli := test.NewFakeCli(&fakeClient{
+				infoFunc: func() (system.Info, error) {
+					return system.Info{Runtimes: tc.runtimes}, nil
+				},
+				createContainerFunc: func(_ *container.Config, hc *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+					binds = hc.Binds
+					return container.CreateResponse{ID: "helper-id"}, nil
+				},
+				containerStatPathFunc: func(_, path string) (container.PathStat, error) {
+					if path == "/host-dev/nvidia0" || path == "/host-dev/nvidia1" {
+						return container.PathStat{}, nil
+					}
+					return container.PathStat{}, errdefs.NotFound(errors.New("no such file"))
+				},
+				containerRemoveFunc: func(_ context.Context, containerID string, _ container.RemoveOptions) error {
+					removed = containerID == "helper-id"
+					return nil
+				},
+			})
+			err := RunGPUs(context.Background(), fakeCli, GPUsOptions{})
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), tc.expectedOut))
+			assert.Check(t, is.Equal(fakeCli.ErrBuffer().String(), tc.expectedStderr))
+			assert.Check(t, is.DeepEqual(binds, []string{"/dev:/host-dev:ro"}))
+			assert.Check(t, removed)
+		})
+	}
+}
+
+func TestCheckGPURuntime(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		infoFunc: func() (system.Info, error) {
+			return system.Info{Runtimes: map[string]system.RuntimeWithStatus{"runc": {}}}, nil
+		},
+	})
+
+	copts := &containerOptions{}
+	assert.NilError(t, checkGPURuntime(context.Background(), fakeCli, copts))
+
+	assert.NilError(t, copts.gpus.Set("auto"))
+	err := checkGPURuntime(context.Background(), fakeCli, copts)
+	assert.Check(t, is.ErrorContains(err, "--gpus auto requires a GPU runtime, but the daemon has none"))
+}
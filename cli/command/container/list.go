@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"io"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -25,6 +26,8 @@ type psOptions struct {
 	nLatest     bool
 	last        int
 	format      string
+	columns     []string
+	wrap        bool
 	filter      opts.FilterOpt
 }
 
@@ -55,7 +58,9 @@ func NewPsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.BoolVarP(&options.nLatest, "latest", "l", false, "Show the latest created container (includes all states)")
 	flags.IntVarP(&options.last, "last", "n", -1, "Show n last created containers (includes all states)")
-	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	flags.StringSliceVar(&options.columns, "columns", nil, flagsHelper.ColumnsHelp)
+	flags.BoolVar(&options.wrap, "wrap", false, flagsHelper.WrapHelp)
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -80,9 +85,21 @@ func buildContainerListOptions(options *psOptions) (*container.ListOptions, erro
 		listOptions.Limit = 1
 	}
 
+	if !options.sizeChanged {
+		for _, col := range options.columns {
+			if strings.EqualFold(strings.TrimLeft(col, "+"), "size") {
+				listOptions.Size = true
+			}
+		}
+	}
+
 	// always validate template when `--format` is used, for consistency
 	if len(options.format) > 0 {
-		tmpl, err := templates.NewParse("", options.format)
+		format, err := formatter.ExpandJSONPath(options.format)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := templates.NewParse("", format)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to parse template")
 		}
@@ -115,6 +132,9 @@ func buildContainerListOptions(options *psOptions) (*container.ListOptions, erro
 }
 
 func runPs(ctx context.Context, dockerCLI command.Cli, options *psOptions) error {
+	if len(options.columns) > 0 && (options.format != "" || options.quiet) {
+		return errors.New("conflicting options: --columns cannot be used together with --format or --quiet")
+	}
 	if len(options.format) == 0 {
 		// load custom psFormat from CLI config (if any)
 		options.format = dockerCLI.ConfigFile().PsFormat
@@ -133,9 +153,11 @@ func runPs(ctx context.Context, dockerCLI command.Cli, options *psOptions) error
 	}
 
 	containerCtx := formatter.Context{
-		Output: dockerCLI.Out(),
-		Format: formatter.NewContainerFormat(options.format, options.quiet, listOptions.Size),
-		Trunc:  !options.noTrunc,
+		Output:  dockerCLI.Out(),
+		Format:  formatter.NewContainerFormat(options.format, options.quiet, listOptions.Size),
+		Trunc:   !options.noTrunc,
+		Columns: options.columns,
+		Wrap:    options.wrap,
 	}
 	return formatter.ContainerWrite(containerCtx, containers)
 }
@@ -284,6 +284,27 @@ func TestContainerListFormatSizeSetsOption(t *testing.T) {
 	}
 }
 
+func TestContainerListColumns(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+			assert.Check(t, options.Size)
+			return []types.Container{
+				*builders.Container("c1", builders.WithSize(10700000)),
+			}, nil
+		},
+	})
+	cmd := newListCommand(cli)
+	assert.Check(t, cmd.Flags().Set("columns", "names,+SIZE"))
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "NAMES     SIZE\nc1        10.7MB\n"))
+
+	cmd = newListCommand(test.NewFakeCli(&fakeClient{}))
+	assert.Check(t, cmd.Flags().Set("columns", "names"))
+	assert.Check(t, cmd.Flags().Set("format", "{{.ID}}"))
+	cmd.SetOut(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "conflicting options: --columns cannot be used together with --format or --quiet"))
+}
+
 func TestContainerListWithConfigFormat(t *testing.T) {
 	cli := test.NewFakeCli(&fakeClient{
 		containerListFunc: func(_ container.ListOptions) ([]types.Container, error) {
@@ -73,6 +73,10 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	}
 	defer responseBody.Close()
 
+	if !opts.follow {
+		defer dockerCli.Out().StartPager()()
+	}
+
 	if c.Config.Tty {
 		_, err = io.Copy(dockerCli.Out(), responseBody)
 	} else {
@@ -0,0 +1,314 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/checkpoint"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// defaultMigrateHelperImage is the image used for the (never started) helper
+// containers that give access to the checkpoint of a container. The
+// containers are only used as a mount-point for the archive endpoints, so any
+// image that exists for the daemon's platform will do.
+const defaultMigrateHelperImage = "busybox"
+
+const migrateHelperPath = "/checkpoint"
+
+const migrateHelperLabel = "com.docker.cli.migrate-helper"
+
+type migrateOptions struct {
+	container   string
+	toContext   string
+	name        string
+	checkpoint  string
+	rm          bool
+	helperImage string
+}
+
+// newMigrateCommand creates a new cobra.Command for `docker container migrate`
+func newMigrateCommand(dockerCli command.Cli) *cobra.Command {
+	var opts migrateOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS] CONTAINER",
+		Short: "Move a running container to the daemon of another context",
+		Long: `Move a running container to the daemon of another context, by creating a
+checkpoint of the container, copying the checkpoint to the other daemon, and
+restoring it in a new container with the same configuration.
+
+Both daemons must have experimental features enabled, and CRIU installed. The
+image of the container is pulled on the other daemon if it's not present. The
+content of volumes isn't copied; use "docker volume migrate" to copy it first.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.container = args[0]
+			return runMigrate(cmd.Context(), dockerCli, opts)
+		},
+		Annotations: map[string]string{
+			"experimental": "",
+			"ostype":       "linux",
+			"version":      "1.25",
+		},
+		ValidArgsFunction: completion.ContainerNames(dockerCli, false),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.toContext, "to-context", "", "Context to move the container to")
+	flags.StringVar(&opts.name, "name", "", "Name of the new container (default: the name of the container)")
+	flags.StringVar(&opts.checkpoint, "checkpoint", "migrate", "Name of the checkpoint to create")
+	flags.BoolVar(&opts.rm, "rm", false, "Remove the container once it's restored on the other daemon")
+	flags.StringVar(&opts.helperImage, "helper-image", defaultMigrateHelperImage, "Image to use for the helper containers that copy the checkpoint")
+	_ = cmd.MarkFlagRequired("to-context")
+	return cmd
+}
+
+func runMigrate(ctx context.Context, dockerCli command.Cli, opts migrateOptions) error {
+	if opts.toContext == dockerCli.CurrentContext() {
+		return errors.Errorf("the container is already on context %s", opts.toContext)
+	}
+	dstClient, err := command.NewAPIClientForContext(dockerCli.ContextStore(), opts.toContext, dockerCli.ConfigFile())
+	if err != nil {
+		return err
+	}
+	defer dstClient.Close()
+
+	return migrateContainer(ctx, dockerCli, dockerCli.Client(), dstClient, opts)
+}
+
+// migrateContainer moves the running container of srcClient to a new
+// container of dstClient. If the container can't be restored on dstClient,
+// it's restored on srcClient from the checkpoint.
+func migrateContainer(ctx context.Context, dockerCli command.Cli, srcClient, dstClient client.APIClient, opts migrateOptions) error {
+	c, err := srcClient.ContainerInspect(ctx, opts.container)
+	if err != nil {
+		return err
+	}
+	if c.State == nil || !c.State.Running {
+		return errors.Errorf("container %s is not running", opts.container)
+	}
+	if err := checkCheckpointSupport(ctx, srcClient, dockerCli.CurrentContext()); err != nil {
+		return err
+	}
+	if err := checkCheckpointSupport(ctx, dstClient, opts.toContext); err != nil {
+		return err
+	}
+
+	name := opts.name
+	if name == "" {
+		name = strings.TrimPrefix(c.Name, "/")
+	}
+	if _, err := dstClient.ContainerInspect(ctx, name); err == nil {
+		return errdefs.Conflict(errors.Errorf("container %s already exists in context %s", name, opts.toContext))
+	}
+	if err := ensureImage(ctx, dockerCli, dstClient, c); err != nil {
+		return err
+	}
+	for _, m := range c.Mounts {
+		if m.Type == mount.TypeVolume {
+			_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: the content of volume %s isn't migrated\n", m.Name)
+		}
+	}
+
+	err = srcClient.CheckpointCreate(ctx, c.ID, checkpoint.CreateOptions{
+		CheckpointID: opts.checkpoint,
+		Exit:         true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to checkpoint container %s", opts.container)
+	}
+
+	id, err := restoreContainer(ctx, dockerCli, srcClient, dstClient, c, name, opts)
+	if err != nil {
+		if rerr := srcClient.ContainerStart(context.WithoutCancel(ctx), c.ID, container.StartOptions{CheckpointID: opts.checkpoint}); rerr != nil {
+			return errors.Errorf("%v; failed to restore container %s from checkpoint %s: %v", err, opts.container, opts.checkpoint, rerr)
+		}
+		return errors.Errorf("%v; container %s was restored from checkpoint %s", err, opts.container, opts.checkpoint)
+	}
+
+	if opts.rm {
+		if err := srcClient.ContainerRemove(ctx, c.ID, container.RemoveOptions{}); err != nil {
+			_, _ = fmt.Fprintf(dockerCli.Err(), "failed to remove container %s: %v\n", opts.container, err)
+		}
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), id)
+	return nil
+}
+
+// checkCheckpointSupport returns an error if the daemon of apiClient doesn't
+// support checkpoints.
+func checkCheckpointSupport(ctx context.Context, apiClient client.APIClient, contextName string) error {
+	ping, err := apiClient.Ping(ctx)
+	if err != nil {
+		return err
+	}
+	if !ping.Experimental {
+		return errors.Errorf("checkpoints require experimental features to be enabled on the daemon of context %s", contextName)
+	}
+	if ping.OSType != "" && ping.OSType != "linux" {
+		return errors.Errorf("checkpoints are not supported on %s daemons (context %s)", ping.OSType, contextName)
+	}
+	return nil
+}
+
+// ensureImage pulls the image of the container on the daemon of dstClient if
+// it's not present, and warns if it differs from the image of the container,
+// as the checkpoint can only be restored on the same filesystem.
+func ensureImage(ctx context.Context, dockerCli command.Cli, dstClient client.APIClient, c types.ContainerJSON) error {
+	img, _, err := dstClient.ImageInspectWithRaw(ctx, c.Config.Image)
+	if errdefs.IsNotFound(err) {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Unable to find image '%s' on the other daemon\n", c.Config.Image)
+		if err := pullImageWithClient(ctx, dockerCli, dstClient, c.Config.Image); err != nil {
+			return err
+		}
+		img, _, err = dstClient.ImageInspectWithRaw(ctx, c.Config.Image)
+	}
+	if err != nil {
+		return err
+	}
+	if img.ID != c.Image {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: image %s on the other daemon (%s) differs from the image of the container (%s); the checkpoint may fail to restore\n", c.Config.Image, img.ID, c.Image)
+	}
+	return nil
+}
+
+func pullImageWithClient(ctx context.Context, dockerCli command.Cli, apiClient client.APIClient, img string) error {
+	encodedAuth, err := command.RetrieveAuthTokenFromImage(dockerCli.ConfigFile(), img)
+	if err != nil {
+		return err
+	}
+	responseBody, err := apiClient.ImageCreate(ctx, img, image.CreateOptions{
+		RegistryAuth: encodedAuth,
+	})
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+	return jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Err(), nil)
+}
+
+// restoreContainer creates a container with the configuration of c on the
+// daemon of dstClient, copies the checkpoint to it, and starts it from the
+// checkpoint. The container is removed if it fails to start.
+func restoreContainer(ctx context.Context, dockerCli command.Cli, srcClient, dstClient client.APIClient, c types.ContainerJSON, name string, opts migrateOptions) (string, error) {
+	resp, err := dstClient.ContainerCreate(ctx, c.Config, c.HostConfig, nil, nil, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to create container in context %s", opts.toContext)
+	}
+	for _, w := range resp.Warnings {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", w)
+	}
+
+	err = connectNetworks(ctx, dstClient, c, resp.ID)
+	if err == nil {
+		err = transferCheckpoint(ctx, dockerCli, srcClient, dstClient, c.ID, resp.ID, opts)
+	}
+	if err == nil {
+		err = dstClient.ContainerStart(ctx, resp.ID, container.StartOptions{CheckpointID: opts.checkpoint})
+		if err != nil {
+			err = errors.Wrapf(err, "failed to restore container in context %s", opts.toContext)
+		}
+	}
+	if err != nil {
+		_ = dstClient.ContainerRemove(context.WithoutCancel(ctx), resp.ID, container.RemoveOptions{Force: true})
+		return "", err
+	}
+	return name, nil
+}
+
+// connectNetworks connects the container with the given id to the networks
+// of c, other than the network it's created with.
+func connectNetworks(ctx context.Context, dstClient client.APIClient, c types.ContainerJSON, id string) error {
+	if c.NetworkSettings == nil || c.HostConfig == nil {
+		return nil
+	}
+	for name, ep := range c.NetworkSettings.Networks {
+		if name == string(c.HostConfig.NetworkMode) || ep == nil {
+			continue
+		}
+		err := dstClient.NetworkConnect(ctx, name, id, &network.EndpointSettings{Aliases: ep.Aliases})
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect the container to network %s", name)
+		}
+	}
+	return nil
+}
+
+// transferCheckpoint streams the checkpoint of the srcID container of
+// srcClient to the dstID container of dstClient, through a helper container
+// on each daemon that has the directory of the checkpoint on the daemon's
+// host mounted.
+func transferCheckpoint(ctx context.Context, dockerCli command.Cli, srcClient, dstClient client.APIClient, srcID, dstID string, opts migrateOptions) error {
+	srcDir, err := checkpointDir(ctx, srcClient, srcID, opts.checkpoint)
+	if err != nil {
+		return err
+	}
+	dstDir, err := checkpointDir(ctx, dstClient, dstID, opts.checkpoint)
+	if err != nil {
+		return err
+	}
+
+	srcHelper, err := createHelperContainer(ctx, dockerCli, srcClient, opts.helperImage, migrateHelperLabel, srcDir+":"+migrateHelperPath+":ro")
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, srcClient, srcHelper)
+
+	dstHelper, err := createHelperContainer(ctx, dockerCli, dstClient, opts.helperImage, migrateHelperLabel, dstDir+":"+migrateHelperPath)
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, dstClient, dstHelper)
+
+	content, _, err := srcClient.CopyFromContainer(ctx, srcHelper, migrateHelperPath+"/.")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read checkpoint %s", opts.checkpoint)
+	}
+	defer content.Close()
+
+	err = dstClient.CopyToContainer(ctx, dstHelper, migrateHelperPath, content, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write checkpoint %s in context %s", opts.checkpoint, opts.toContext)
+	}
+	return nil
+}
+
+// checkpointDir returns the directory of the named checkpoint of the
+// container on the daemon's host, which is in the directory of the container.
+func checkpointDir(ctx context.Context, apiClient client.APIClient, id, name string) (string, error) {
+	info, err := apiClient.Info(ctx)
+	if err != nil {
+		return "", err
+	}
+	if info.DockerRootDir == "" {
+		return "", errors.New("the root directory of the daemon is unknown")
+	}
+	return path.Join(info.DockerRootDir, "containers", id, "checkpoints", name), nil
+}
+
+// createHelperContainer creates a helper container with the given label and
+// bind-mount on the daemon of apiClient, pulling the helper image if it's not
+// present.
+func createHelperContainer(ctx context.Context, dockerCli command.Cli, apiClient client.APIClient, helperImage, label, bind string) (string, error) {
+	// Binds are used instead of Mounts, so that the directory of the
+	// checkpoint is created if it doesn't exist.
+	return helpercontainer.Create(ctx, dockerCli, apiClient, helperImage, label, &container.HostConfig{Binds: []string{bind}})
+}
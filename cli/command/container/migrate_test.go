@@ -0,0 +1,202 @@
+package container
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/checkpoint"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/errdefs"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// migrateClients returns the fake clients of the source and the target
+// daemons of a migration, which record the calls to events.
+func migrateClients(events *[]string) (src, dst *fakeClient) {
+	ping := func() (types.Ping, error) {
+		return types.Ping{Experimental: true, OSType: "linux"}, nil
+	}
+	createHelper := func(id string) func(*container.Config, *container.HostConfig, *network.NetworkingConfig, *specs.Platform, string) (container.CreateResponse, error) {
+		return func(config *container.Config, hostConfig *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, name string) (container.CreateResponse, error) {
+			if config.Image == defaultMigrateHelperImage {
+				*events = append(*events, "create helper "+id+" "+strings.Join(hostConfig.Binds, ","))
+				return container.CreateResponse{ID: "helper-" + id}, nil
+			}
+			*events = append(*events, "create "+id+" "+name+" "+config.Image)
+			return container.CreateResponse{ID: "new-id"}, nil
+		}
+	}
+	src = &fakeClient{
+		pingFunc: ping,
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				ContainerJSONBase: &types.ContainerJSONBase{
+					ID:         "web-id",
+					Name:       "/web",
+					Image:      "sha256:web-image",
+					State:      &types.ContainerState{Running: true},
+					HostConfig: &container.HostConfig{NetworkMode: "bridge"},
+				},
+				Config: &container.Config{Image: "nginx"},
+				NetworkSettings: &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{
+					"bridge": {},
+					"front":  {Aliases: []string{"web"}},
+				}},
+			}, nil
+		},
+		infoFunc: func() (system.Info, error) {
+			return system.Info{DockerRootDir: "/var/lib/docker"}, nil
+		},
+		checkpointCreateFunc: func(containerID string, options checkpoint.CreateOptions) error {
+			*events = append(*events, "checkpoint "+containerID+" "+options.CheckpointID)
+			return nil
+		},
+		createContainerFunc: createHelper("src"),
+		containerCopyFromFunc: func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			return io.NopCloser(strings.NewReader("checkpoint")), container.PathStat{}, nil
+		},
+		containerStartFunc: func(containerID string, options container.StartOptions) error {
+			*events = append(*events, "start src "+containerID+" "+options.CheckpointID)
+			return nil
+		},
+		containerRemoveFunc: func(_ context.Context, containerID string, _ container.RemoveOptions) error {
+			*events = append(*events, "remove src "+containerID)
+			return nil
+		},
+	}
+	dst = &fakeClient{
+		pingFunc: ping,
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{}, errdefs.NotFound(errors.New("no such container: " + name))
+		},
+		imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{ID: "sha256:web-image"}, nil, nil
+		},
+		infoFunc: func() (system.Info, error) {
+			return system.Info{DockerRootDir: "/data/docker"}, nil
+		},
+		createContainerFunc: createHelper("dst"),
+		networkConnectFunc: func(networkID, containerID string, config *network.EndpointSettings) error {
+			*events = append(*events, "connect "+networkID+" "+containerID+" "+strings.Join(config.Aliases, ","))
+			return nil
+		},
+		copyToContainerFunc: func(containerID, dstPath string, content io.Reader) error {
+			b, err := io.ReadAll(content)
+			*events = append(*events, "copy "+containerID+" "+dstPath+" "+string(b))
+			return err
+		},
+		containerStartFunc: func(containerID string, options container.StartOptions) error {
+			*events = append(*events, "start dst "+containerID+" "+options.CheckpointID)
+			return nil
+		},
+		containerRemoveFunc: func(_ context.Context, containerID string, _ container.RemoveOptions) error {
+			*events = append(*events, "remove dst "+containerID)
+			return nil
+		},
+	}
+	return src, dst
+}
+
+func TestContainerMigrate(t *testing.T) {
+	var events []string
+	src, dst := migrateClients(&events)
+	cli := test.NewFakeCli(src)
+	err := migrateContainer(context.Background(), cli, src, dst, migrateOptions{
+		container:   "web",
+		toContext:   "remote",
+		checkpoint:  "migrate",
+		rm:          true,
+		helperImage: defaultMigrateHelperImage,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(events, []string{
+		"checkpoint web-id migrate",
+		"create dst web nginx",
+		"connect front new-id web",
+		"create helper src /var/lib/docker/containers/web-id/checkpoints/migrate:/checkpoint:ro",
+		"create helper dst /data/docker/containers/new-id/checkpoints/migrate:/checkpoint",
+		"copy helper-dst /checkpoint checkpoint",
+		"remove dst helper-dst",
+		"remove src helper-src",
+		"start dst new-id migrate",
+		"remove src web-id",
+	}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "web\n"))
+}
+
+func TestContainerMigrateRestoreFails(t *testing.T) {
+	var events []string
+	src, dst := migrateClients(&events)
+	dst.containerStartFunc = func(string, container.StartOptions) error {
+		return errors.New("criu failed")
+	}
+	cli := test.NewFakeCli(src)
+	err := migrateContainer(context.Background(), cli, src, dst, migrateOptions{
+		container:   "web",
+		toContext:   "remote",
+		name:        "web2",
+		checkpoint:  "cp",
+		helperImage: defaultMigrateHelperImage,
+	})
+	assert.Check(t, is.Error(err, "failed to restore container in context remote: criu failed; container web was restored from checkpoint cp"))
+	assert.Check(t, is.DeepEqual(events[len(events)-2:], []string{
+		"remove dst new-id",
+		"start src web-id cp",
+	}))
+}
+
+func TestContainerMigrateErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		modify        func(src, dst *fakeClient)
+		expectedError string
+	}{
+		{
+			name: "not-running",
+			modify: func(src, _ *fakeClient) {
+				src.inspectFunc = func(string) (types.ContainerJSON, error) {
+					return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{}}}, nil
+				}
+			},
+			expectedError: "container web is not running",
+		},
+		{
+			name: "not-experimental",
+			modify: func(_, dst *fakeClient) {
+				dst.pingFunc = func() (types.Ping, error) { return types.Ping{OSType: "linux"}, nil }
+			},
+			expectedError: "checkpoints require experimental features to be enabled on the daemon of context remote",
+		},
+		{
+			name: "name-conflict",
+			modify: func(_, dst *fakeClient) {
+				dst.inspectFunc = func(string) (types.ContainerJSON, error) { return types.ContainerJSON{}, nil }
+			},
+			expectedError: "container web already exists in context remote",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var events []string
+			src, dst := migrateClients(&events)
+			tc.modify(src, dst)
+			err := migrateContainer(context.Background(), test.NewFakeCli(src), src, dst, migrateOptions{
+				container:  "web",
+				toContext:  "remote",
+				checkpoint: "migrate",
+			})
+			assert.Check(t, is.Error(err, tc.expectedError))
+			assert.Check(t, is.Len(events, 0))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli/command/watch"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/sirupsen/logrus"
+)
+
+// eventNotificationPrefix marks lines written by the CLI among the output
+// of an attached container.
+const eventNotificationPrefix = "[docker] "
+
+// notifyContainerEvents subscribes to the events of the given container, and
+// writes a notification to out for events that explain what happens to the
+// container while attached to it, such as an OOM kill or a change of its
+// health status. lineEnd is used to terminate lines, and should be "\r\n"
+// if the terminal is in raw mode.
+//
+// It returns once subscribed, and stops reporting events when ctx is done.
+func notifyContainerEvents(ctx context.Context, apiClient client.SystemAPIClient, containerID string, out io.Writer, lineEnd string) {
+	eventChan, errChan := watch.Events(ctx, apiClient, watch.Options{
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("container", containerID),
+		),
+	})
+	go func() {
+		var died bool
+		for {
+			select {
+			case event := <-eventChan:
+				msg := eventNotification(event, died)
+				switch event.Action {
+				case events.ActionDie:
+					died = true
+				case events.ActionStart:
+					died = false
+				}
+				if msg != "" {
+					_, _ = fmt.Fprint(out, eventNotificationPrefix, msg, lineEnd)
+				}
+			case err := <-errChan:
+				if err != nil && ctx.Err() == nil {
+					logrus.Debugf("Error receiving events for container %s: %v", containerID, err)
+				}
+				return
+			}
+		}
+	}()
+}
+
+// eventNotification returns the notification to print for event, or an
+// empty string if the event should not be reported. died indicates whether
+// the container exited before the event, in which case a start event means
+// the container was restarted.
+func eventNotification(event events.Message, died bool) string {
+	switch event.Action {
+	case events.ActionOOM:
+		return "container ran out of memory: a process in the container was killed by the kernel OOM killer"
+	case events.ActionKill:
+		if sig := event.Actor.Attributes["signal"]; sig != "" {
+			return "container received signal " + sig
+		}
+	case events.ActionRestart:
+		return "container is restarting"
+	case events.ActionStart:
+		if died {
+			return "container restarted"
+		}
+	case events.ActionPause:
+		return "container paused"
+	case events.ActionUnPause:
+		return "container unpaused"
+	default:
+		if status, ok := strings.CutPrefix(string(event.Action), string(events.ActionHealthStatus)+": "); ok {
+			return "health status changed to " + status
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package container
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/docker/api/types/events"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestNotifyContainerEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	apiClient := &fakeClient{
+		eventsFunc: func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+			assert.Check(t, is.DeepEqual(options.Filters.Get("container"), []string{"id"}))
+			messages := make(chan events.Message)
+			go func() {
+				for i, action := range []events.Action{
+					events.ActionHealthStatusHealthy,
+					events.ActionExecStart, // not reported
+					events.ActionOOM,
+					events.ActionKill,
+					events.ActionDie,
+					events.ActionStart,
+					events.ActionHealthStatusUnhealthy,
+				} {
+					msg := events.Message{
+						Type:     events.ContainerEventType,
+						Action:   action,
+						Actor:    events.Actor{ID: "id", Attributes: map[string]string{}},
+						TimeNano: int64(i + 1),
+					}
+					if action == events.ActionKill {
+						msg.Actor.Attributes["signal"] = "9"
+					}
+					select {
+					case messages <- msg:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return messages, make(chan error)
+		},
+	}
+
+	r, w := io.Pipe()
+	notifyContainerEvents(ctx, apiClient, "id", w, "\r\n")
+
+	// bufio.ScanLines drops the trailing "\r" of each line.
+	scanner := bufio.NewScanner(r)
+	var lines []string
+	for len(lines) < 5 && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Check(t, is.DeepEqual(lines, []string{
+		"[docker] health status changed to healthy",
+		"[docker] container ran out of memory: a process in the container was killed by the kernel OOM killer",
+		"[docker] container received signal 9",
+		"[docker] container restarted",
+		"[docker] health status changed to unhealthy",
+	}))
+}
@@ -42,6 +42,19 @@ const (
 	seccompProfileUnconfined = "unconfined"
 )
 
+const (
+	// autoHostPort is the host port of a "--publish" mapping to let the
+	// "--publish-strategy" pick a free host port, as in "-p auto:80".
+	autoHostPort = "auto"
+
+	// publishStrategyRandom picks ephemeral host ports, which are allocated
+	// by the daemon.
+	publishStrategyRandom = "random"
+	// publishStrategySequential picks the first free host port from the
+	// container port.
+	publishStrategySequential = "sequential"
+)
+
 var deviceCgroupRuleRegexp = regexp.MustCompile(`^[acb] ([0-9]+|\*):([0-9]+|\*) [rwm]{1,3}$`)
 
 // containerOptions is a data object with all the options for creating a container
@@ -50,6 +63,7 @@ type containerOptions struct {
 	volumes             opts.ListOpts
 	tmpfs               opts.ListOpts
 	mounts              opts.MountOpt
+	secrets             opts.RunSecretOpt
 	blkioWeightDevice   opts.WeightdeviceOpt
 	deviceReadBps       opts.ThrottledeviceOpt
 	deviceWriteBps      opts.ThrottledeviceOpt
@@ -71,14 +85,19 @@ type containerOptions struct {
 	dnsSearch           opts.ListOpts
 	dnsOptions          opts.ListOpts
 	extraHosts          opts.ListOpts
+	addHostGateway      bool
 	volumesFrom         opts.ListOpts
 	envFile             opts.ListOpts
+	envFileRequired     bool
+	envFileInterpolate  bool
 	capAdd              opts.ListOpts
 	capDrop             opts.ListOpts
 	groupAdd            opts.ListOpts
 	securityOpt         opts.ListOpts
 	storageOpt          opts.ListOpts
 	labelsFile          opts.ListOpts
+	setupCmds           opts.ListOpts
+	setupCmdOnStart     bool
 	loggingOpts         opts.ListOpts
 	privileged          bool
 	pidMode             string
@@ -86,6 +105,7 @@ type containerOptions struct {
 	usernsMode          string
 	cgroupnsMode        string
 	publishAll          bool
+	publishStrategy     string
 	stdin               bool
 	tty                 bool
 	oomKillDisable      bool
@@ -174,6 +194,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 		loggingOpts:       opts.NewListOpts(nil),
 		publish:           opts.NewListOpts(nil),
 		securityOpt:       opts.NewListOpts(nil),
+		setupCmds:         opts.NewListOpts(nil),
 		storageOpt:        opts.NewListOpts(nil),
 		sysctls:           opts.NewMapOpts(nil, opts.ValidateSysctl),
 		tmpfs:             opts.NewListOpts(nil),
@@ -187,10 +208,12 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.VarP(&copts.attach, "attach", "a", "Attach to STDIN, STDOUT or STDERR")
 	flags.Var(&copts.deviceCgroupRules, "device-cgroup-rule", "Add a rule to the cgroup allowed devices list")
 	flags.Var(&copts.devices, "device", "Add a host device to the container")
-	flags.Var(&copts.gpus, "gpus", "GPU devices to add to the container ('all' to pass all GPUs)")
+	flags.Var(&copts.gpus, "gpus", "GPU devices to add to the container ('all' to pass all GPUs, 'auto' to pass all GPUs if the daemon has a GPU runtime)")
 	flags.SetAnnotation("gpus", "version", []string{"1.40"})
 	flags.VarP(&copts.env, "env", "e", "Set environment variables")
 	flags.Var(&copts.envFile, "env-file", "Read in a file of environment variables")
+	flags.BoolVar(&copts.envFileRequired, "env-file-required", true, "Fail if an env file does not exist (use --env-file-required=false to skip missing env files)")
+	flags.BoolVar(&copts.envFileInterpolate, "env-file-interpolate", false, `Substitute variables such as "${VAR}" in the values of env files`)
 	flags.StringVar(&copts.entrypoint, "entrypoint", "", "Overwrite the default ENTRYPOINT of the image")
 	flags.Var(&copts.groupAdd, "group-add", "Add additional groups to join")
 	flags.StringVarP(&copts.hostname, "hostname", "h", "", "Container host name")
@@ -209,6 +232,8 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.StringVarP(&copts.user, "user", "u", "", "Username or UID (format: <name|uid>[:<group|gid>])")
 	flags.StringVarP(&copts.workingDir, "workdir", "w", "", "Working directory inside the container")
 	flags.BoolVar(&copts.autoRemove, "rm", false, "Automatically remove the container and its associated anonymous volumes when it exits")
+	flags.Var(&copts.setupCmds, "setup-cmd", "Command to execute in the container once it is running")
+	flags.BoolVar(&copts.setupCmdOnStart, "setup-cmd-on-start", false, `Record the setup commands on the container, and execute them each time it is started with "docker start"`)
 
 	// Security
 	flags.Var(&copts.capAdd, "cap-add", "Add Linux capabilities")
@@ -225,6 +250,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 
 	// Network and port publishing flag
 	flags.Var(&copts.extraHosts, "add-host", "Add a custom host-to-IP mapping (host:ip)")
+	flags.BoolVar(&copts.addHostGateway, "add-host-gateway", false, `Add a "host.docker.internal" entry that resolves to the host-gateway (Linux daemons only)`)
 	flags.Var(&copts.dns, "dns", "Set custom DNS servers")
 	// We allow for both "--dns-opt" and "--dns-option", although the latter is the recommended way.
 	// This is to be consistent with service create/update
@@ -240,6 +266,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.StringVar(&copts.macAddress, "mac-address", "", "Container MAC address (e.g., 92:d0:c6:0a:29:33)")
 	flags.VarP(&copts.publish, "publish", "p", "Publish a container's port(s) to the host")
 	flags.BoolVarP(&copts.publishAll, "publish-all", "P", false, "Publish all exposed ports to random ports")
+	flags.StringVar(&copts.publishStrategy, "publish-strategy", publishStrategyRandom, `Strategy to pick the host ports of "auto" mappings ("`+publishStrategyRandom+`", "`+publishStrategySequential+`")`)
 	// We allow for both "--net" and "--network", although the latter is the recommended way.
 	flags.Var(&copts.netMode, "net", "Connect a container to a network")
 	flags.Var(&copts.netMode, "network", "Connect a container to a network")
@@ -258,6 +285,7 @@ func addFlags(flags *pflag.FlagSet) *containerOptions {
 	flags.Var(&copts.volumesFrom, "volumes-from", "Mount volumes from the specified container(s)")
 	flags.VarP(&copts.volumes, "volume", "v", "Bind mount a volume")
 	flags.Var(&copts.mounts, "mount", "Attach a filesystem mount to the container")
+	flags.Var(&copts.secrets, "secret", `Secret to expose to the container, such as "id=mysecret,src=/local/secret"`)
 
 	// Health-checking
 	flags.StringVar(&copts.healthCmd, "health-cmd", "", "Command to run to check health")
@@ -328,6 +356,15 @@ type containerConfig struct {
 	Config           *container.Config
 	HostConfig       *container.HostConfig
 	NetworkingConfig *networktypes.NetworkingConfig
+
+	// secrets are the secrets that are mounted or copied to the container
+	// when it's created.
+	secrets []opts.RunSecret
+
+	// hostGatewayFromImage is set if a "host.docker.internal" entry is added
+	// depending on the "com.docker.host-gateway" label of the image, which is
+	// checked once the image is present.
+	hostGatewayFromImage bool
 }
 
 // parse parses the args for the specified command and generates a Config,
@@ -434,6 +471,10 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 	if err != nil {
 		return nil, err
 	}
+	convertedOpts, err = expandPortSpecs(convertedOpts, copts.publishStrategy)
+	if err != nil {
+		return nil, err
+	}
 
 	ports, portBindings, err = nat.ParsePortSpecs(convertedOpts)
 	if err != nil {
@@ -493,7 +534,10 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 	}
 
 	// collect all the environment variables for the container
-	envVariables, err := opts.ReadKVEnvStrings(copts.envFile.GetAll(), copts.env.GetAll())
+	envVariables, err := opts.ReadEnvFiles(copts.envFile.GetAll(), copts.env.GetAll(), opts.EnvFileOptions{
+		Optional:    !copts.envFileRequired,
+		Interpolate: copts.envFileInterpolate,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -503,6 +547,16 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 	if err != nil {
 		return nil, err
 	}
+	if copts.setupCmdOnStart {
+		if copts.setupCmds.Len() == 0 {
+			return nil, errors.New("--setup-cmd-on-start requires --setup-cmd")
+		}
+		setupCmds, err := json.Marshal(copts.setupCmds.GetAll())
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, setupCmdLabel+"="+string(setupCmds))
+	}
 
 	pidMode := container.PidMode(copts.pidMode)
 	if !pidMode.Valid() {
@@ -736,6 +790,7 @@ func parse(flags *pflag.FlagSet, copts *containerOptions, serverOS string) (*con
 		Config:           config,
 		HostConfig:       hostConfig,
 		NetworkingConfig: networkingConfig,
+		secrets:          copts.secrets.Value(),
 	}, nil
 }
 
@@ -909,6 +964,59 @@ func convertToStandardNotation(ports []string) ([]string, error) {
 	return optsList, nil
 }
 
+// expandPortSpecs expands the shorthands of the "--publish" flag that the
+// daemon doesn't support: mappings with multiple protocols, such as
+// "8000-8010:8000-8010/tcp+udp", and mappings with an "auto" host port, of
+// which the host port is picked with the given strategy.
+func expandPortSpecs(specs []string, strategy string) ([]string, error) {
+	if strategy != publishStrategyRandom && strategy != publishStrategySequential {
+		return nil, errors.Errorf("invalid publish strategy %q: must be %q or %q", strategy, publishStrategyRandom, publishStrategySequential)
+	}
+	var expanded []string
+	for _, spec := range specs {
+		rawPort, protos := spec, ""
+		if i := strings.LastIndex(spec, "/"); i >= 0 {
+			rawPort, protos = spec[:i], spec[i+1:]
+		}
+		var prefix, containerPort string
+		auto := false
+		parts := strings.Split(rawPort, ":")
+		if n := len(parts); n >= 2 && parts[n-2] == autoHostPort {
+			containerPort, auto = parts[n-1], true
+			if n > 2 {
+				// The host IP.
+				prefix = strings.Join(parts[:n-2], ":") + ":"
+			}
+		}
+
+		for _, proto := range strings.Split(protos, "+") {
+			if protos != "" && proto == "" {
+				return nil, errors.Errorf("invalid proto: %s", spec)
+			}
+			suffix := ""
+			if proto != "" {
+				suffix = "/" + proto
+			}
+			if !auto {
+				expanded = append(expanded, rawPort+suffix)
+				continue
+			}
+			if strategy == publishStrategyRandom {
+				expanded = append(expanded, prefix+":"+containerPort+suffix)
+				continue
+			}
+			start, end, err := nat.ParsePortRange(containerPort)
+			if err != nil {
+				return nil, errors.Errorf("invalid containerPort: %s", containerPort)
+			}
+			for p := start; p <= end; p++ {
+				expanded = append(expanded, fmt.Sprintf("%s%d-65535:%d%s", prefix, p, p, suffix))
+			}
+		}
+	}
+	return expanded, nil
+}
+
 func parseLoggingOpts(loggingDriver string, loggingOpts []string) (map[string]string, error) {
 	loggingOptsMap := opts.ConvertKVStringsToMap(loggingOpts)
 	if loggingDriver == "none" && len(loggingOpts) > 0 {
@@ -904,6 +904,18 @@ func TestParseEnvfileVariables(t *testing.T) {
 	}
 }
 
+func TestParseEnvfileLayering(t *testing.T) {
+	config, _, _, err := parseRun([]string{"--env-file=testdata/valid.env", "--env-file=testdata/override.env", "--env-file-interpolate", "img", "cmd"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(config.Env, []string{"ENV1=override", "ENV2=override-value2"}))
+
+	_, _, _, err = parseRun([]string{"--env-file=testdata/valid.env", "--env-file=nonexistent", "img", "cmd"})
+	assert.Check(t, os.IsNotExist(err))
+	config, _, _, err = parseRun([]string{"--env-file=testdata/valid.env", "--env-file=nonexistent", "--env-file-required=false", "img", "cmd"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(config.Env, []string{"ENV1=value1"}))
+}
+
 func TestParseEnvfileVariablesWithBOMUnicode(t *testing.T) {
 	// UTF8 with BOM
 	config, _, _, err := parseRun([]string{"--env-file=testdata/utf8.env", "img", "cmd"})
@@ -1071,6 +1083,69 @@ func TestParseSystemPaths(t *testing.T) {
 	}
 }
 
+func TestExpandPortSpecs(t *testing.T) {
+	testCases := []struct {
+		specs    []string
+		strategy string
+		expected []string
+	}{
+		{
+			specs:    []string{"80", "8080:80/udp", "127.0.0.1:8000-8001:8000-8001"},
+			strategy: publishStrategyRandom,
+			expected: []string{"80", "8080:80/udp", "127.0.0.1:8000-8001:8000-8001"},
+		},
+		{
+			specs:    []string{"8000-8010:8000-8010/tcp+udp"},
+			strategy: publishStrategyRandom,
+			expected: []string{"8000-8010:8000-8010/tcp", "8000-8010:8000-8010/udp"},
+		},
+		{
+			specs:    []string{"auto:80", "127.0.0.1:auto:443/tcp+udp", "[::1]:auto:53/udp"},
+			strategy: publishStrategyRandom,
+			expected: []string{":80", "127.0.0.1::443/tcp", "127.0.0.1::443/udp", "[::1]::53/udp"},
+		},
+		{
+			specs:    []string{"auto:80", "127.0.0.1:auto:8000-8001/tcp+udp"},
+			strategy: publishStrategySequential,
+			expected: []string{
+				"80-65535:80",
+				"127.0.0.1:8000-65535:8000/tcp",
+				"127.0.0.1:8001-65535:8001/tcp",
+				"127.0.0.1:8000-65535:8000/udp",
+				"127.0.0.1:8001-65535:8001/udp",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		specs, err := expandPortSpecs(tc.specs, tc.strategy)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(specs, tc.expected))
+	}
+
+	_, err := expandPortSpecs([]string{"80/tcp+"}, publishStrategyRandom)
+	assert.Check(t, is.Error(err, "invalid proto: 80/tcp+"))
+	_, err = expandPortSpecs([]string{"auto:http"}, publishStrategySequential)
+	assert.Check(t, is.Error(err, "invalid containerPort: http"))
+	_, err = expandPortSpecs([]string{"80"}, "lowest")
+	assert.Check(t, is.Error(err, `invalid publish strategy "lowest": must be "random" or "sequential"`))
+}
+
+func TestParsePublishAuto(t *testing.T) {
+	_, hostConfig, _, err := parseRun([]string{"-p", "auto:80/tcp+udp", "-p", "published=auto,target=443", "img"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(hostConfig.PortBindings, nat.PortMap{
+		"80/tcp":  {{HostPort: ""}},
+		"80/udp":  {{HostPort: ""}},
+		"443/tcp": {{HostPort: ""}},
+	}))
+
+	_, hostConfig, _, err = parseRun([]string{"-p", "auto:80", "--publish-strategy", "sequential", "img"})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(hostConfig.PortBindings, nat.PortMap{
+		"80/tcp": {{HostPort: "80-65535"}},
+	}))
+}
+
 func TestConvertToStandardNotation(t *testing.T) {
 	valid := map[string][]string{
 		"20:10/tcp":               {"target=10,published=20"},
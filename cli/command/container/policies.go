@@ -0,0 +1,84 @@
+package container
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/fvbommel/sortorder"
+	"github.com/spf13/cobra"
+)
+
+type policiesOptions struct {
+	all        bool
+	filter     opts.FilterOpt
+	format     string
+	containers []string
+}
+
+// newPoliciesCommand creates a new cobra.Command for `docker container policies`
+func newPoliciesCommand(dockerCli command.Cli) *cobra.Command {
+	options := policiesOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "policies [OPTIONS] [CONTAINER...]",
+		Short: "List the restart policy, OOM settings, and resource limits of containers",
+		Long: `List the restart policy, the OOM settings, and the resource limits of the
+specified containers and of the containers that match the filter, or of the
+running containers if none are specified, to find the containers of which the
+policies differ. Use "docker container update" to change them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.containers = args
+			return runPolicies(cmd.Context(), dockerCli, &options)
+		},
+		ValidArgsFunction: completion.ContainerNames(dockerCli, true),
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.all, "all", "a", false, "Show all containers (default shows just running)")
+	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runPolicies(ctx context.Context, dockerCli command.Cli, options *policiesOptions) error {
+	apiClient := dockerCli.Client()
+	names := options.containers
+	if len(names) == 0 || options.filter.Value().Len() > 0 {
+		list, err := apiClient.ContainerList(ctx, container.ListOptions{
+			All:     options.all,
+			Filters: options.filter.Value(),
+		})
+		if err != nil {
+			return err
+		}
+		names = appendContainerNames(names, list)
+	}
+
+	policies := make([]types.ContainerJSON, 0, len(names))
+	for _, name := range names {
+		c, err := apiClient.ContainerInspect(ctx, name)
+		if err != nil {
+			return err
+		}
+		policies = append(policies, c)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return sortorder.NaturalLess(policies[i].Name, policies[j].Name)
+	})
+
+	format := options.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	return policiesFormatWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newPoliciesFormat(format),
+	}, policies)
+}
@@ -0,0 +1,72 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestPolicies(t *testing.T) {
+	oomKillDisable := true
+	pidsLimit := int64(200)
+	containers := map[string]types.ContainerJSON{
+		"web": {ContainerJSONBase: &types.ContainerJSONBase{
+			Name: "/web",
+			HostConfig: &container.HostConfig{
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyAlways},
+				Resources: container.Resources{
+					Memory:    512 * 1024 * 1024,
+					NanoCPUs:  1500000000,
+					PidsLimit: &pidsLimit,
+				},
+			},
+		}},
+		"db": {ContainerJSONBase: &types.ContainerJSONBase{
+			Name: "/db",
+			HostConfig: &container.HostConfig{
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+				OomScoreAdj:   -500,
+				Resources:     container.Resources{CPUQuota: 50000, OomKillDisable: &oomKillDisable},
+			},
+		}},
+		"cache": {ContainerJSONBase: &types.ContainerJSONBase{
+			Name:       "/cache",
+			HostConfig: &container.HostConfig{},
+		}},
+	}
+	var listOptions container.ListOptions
+	fakeCli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+			listOptions = options
+			return []types.Container{{ID: "id-web", Names: []string{"/web"}}, {ID: "id-db", Names: []string{"/db"}}}, nil
+		},
+		inspectFunc: func(name string) (types.ContainerJSON, error) {
+			return containers[name], nil
+		},
+	})
+
+	cmd := newPoliciesCommand(fakeCli)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, !listOptions.All)
+	golden.Assert(t, fakeCli.OutBuffer().String(), "container-policies.golden")
+
+	fakeCli.OutBuffer().Reset()
+	cmd = newPoliciesCommand(fakeCli)
+	cmd.SetArgs([]string{"--format", "{{.Name}} {{.MemorySwap}} {{.CPUShares}}", "cache", "web"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "cache unlimited 1024\nweb 1GiB 1024\n"))
+
+	fakeCli.OutBuffer().Reset()
+	cmd = newPoliciesCommand(fakeCli)
+	cmd.SetArgs([]string{"--all", "--filter", "label=env=prod", "--format", "{{.Name}}", "cache"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, listOptions.All)
+	assert.Check(t, is.DeepEqual(listOptions.Filters.Get("label"), []string{"env=prod"}))
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "cache\ndb\nweb\n"))
+}
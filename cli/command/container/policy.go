@@ -0,0 +1,203 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli-plugins/scanner"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/trust"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// imagePolicy is the policy that "docker run --verify-policy" verifies the
+// image of the container against, before creating the container.
+type imagePolicy struct {
+	// Registries are the registries, or repositories of registries, that
+	// the image may be pulled from, such as "registry.example.com" or
+	// "docker.io/library". Any registry is allowed if it's empty.
+	Registries []string `yaml:"registries,omitempty"`
+	// RequireSignature requires a Notation signature of the image that's
+	// valid against the trust policy of the CLI.
+	RequireSignature bool `yaml:"requireSignature,omitempty"`
+	// MaxSeverity is the highest severity of the vulnerabilities that the
+	// image may have, as found by the scanner plugin. Images aren't scanned
+	// if it's empty.
+	MaxSeverity string `yaml:"maxSeverity,omitempty"`
+	// Scanner is the scanner plugin that scans the image. Optional; the
+	// scanner is selected as with "docker image scan" if it's empty.
+	Scanner string `yaml:"scanner,omitempty"`
+	// IgnoreVulnerabilities are the IDs of the vulnerabilities that are
+	// allowed whatever their severity.
+	IgnoreVulnerabilities []string `yaml:"ignoreVulnerabilities,omitempty"`
+}
+
+func loadImagePolicy(file string) (*imagePolicy, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var policy imagePolicy
+	if err := yaml.UnmarshalStrict(b, &policy); err != nil {
+		return nil, errors.Wrapf(err, "invalid policy %s", file)
+	}
+	if policy.MaxSeverity != "" {
+		if policy.MaxSeverity, err = scanner.ParseSeverity(policy.MaxSeverity); err != nil {
+			return nil, errors.Wrapf(err, "invalid policy %s", file)
+		}
+	}
+	if len(policy.Registries) == 0 && !policy.RequireSignature && policy.MaxSeverity == "" {
+		return nil, errors.Errorf("invalid policy %s: it doesn't set registries, requireSignature, or maxSeverity", file)
+	}
+	return &policy, nil
+}
+
+// policyCheck is the result of a check of an image policy.
+type policyCheck struct {
+	name   string
+	passed bool
+	detail string
+}
+
+// policyError is the error of an image that doesn't satisfy a policy, with
+// the results of all the checks of the policy.
+type policyError struct {
+	image  string
+	file   string
+	checks []policyCheck
+}
+
+func (e *policyError) Error() string {
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "image %s doesn't satisfy the policy %s:\n", e.image, e.file)
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, c := range e.checks {
+		result := "FAIL"
+		if c.passed {
+			result = "PASS"
+		}
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", c.name, result, c.detail)
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// verifyImagePolicy verifies the image of the container against the policy of
+// the --verify-policy option. The image is pulled by pull, as with the --pull
+// option, once the policy allows its registry. It returns the reference of the
+// image by digest, if it has one, so that the container runs the image that
+// was verified rather than an image that's tagged later.
+func verifyImagePolicy(ctx context.Context, dockerCli command.Cli, img string, namedRef reference.Named, options *createOptions, pull func() error) (reference.Canonical, error) {
+	policy, err := loadImagePolicy(options.verifyPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if namedRef == nil {
+		return nil, errors.Errorf("image %s can't be verified against the policy %s: it isn't a reference to an image of a registry", img, options.verifyPolicy)
+	}
+
+	// Images of registries that the policy doesn't allow aren't pulled.
+	var checks []policyCheck
+	if len(policy.Registries) > 0 {
+		check := checkRegistry(policy, namedRef)
+		if !check.passed {
+			return nil, &policyError{image: reference.FamiliarString(namedRef), file: options.verifyPolicy, checks: []policyCheck{check}}
+		}
+		checks = append(checks, check)
+	}
+	if options.pull == PullImageAlways {
+		if err := pull(); err != nil {
+			return nil, err
+		}
+	}
+
+	digested, digestErr := resolveImageDigest(img, namedRef, func() (types.ImageInspect, error) {
+		return inspectOrPullImage(ctx, dockerCli, img, namedRef, options, pull)
+	})
+	if digestErr != nil && errdefs.IsNotFound(digestErr) {
+		return nil, digestErr
+	}
+	if policy.RequireSignature {
+		checks = append(checks, checkSignature(ctx, dockerCli, digested, digestErr))
+	}
+	if policy.MaxSeverity != "" {
+		scanned := img
+		if digested != nil {
+			scanned = reference.FamiliarString(digested)
+		}
+		check, err := checkVulnerabilities(ctx, dockerCli, policy, scanned, options)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	for _, c := range checks {
+		if !c.passed {
+			return nil, &policyError{image: reference.FamiliarString(namedRef), file: options.verifyPolicy, checks: checks}
+		}
+	}
+	if !options.quiet {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Image %s satisfies the policy %s\n", reference.FamiliarString(namedRef), options.verifyPolicy)
+	}
+	return digested, nil
+}
+
+func checkRegistry(policy *imagePolicy, ref reference.Named) policyCheck {
+	for _, r := range policy.Registries {
+		r = strings.TrimSuffix(r, "/")
+		if ref.Name() == r || strings.HasPrefix(ref.Name(), r+"/") {
+			return policyCheck{name: "registry", passed: true, detail: fmt.Sprintf("%s is in %s", ref.Name(), r)}
+		}
+	}
+	return policyCheck{name: "registry", detail: fmt.Sprintf("%s isn't in an allowed registry (%s)", ref.Name(), strings.Join(policy.Registries, ", "))}
+}
+
+func checkSignature(ctx context.Context, dockerCli command.Cli, digested reference.Canonical, digestErr error) policyCheck {
+	if digestErr != nil {
+		return policyCheck{name: "signature", detail: digestErr.Error()}
+	}
+	v, err := image.VerifyNotationSignature(ctx, dockerCli, digested, digested.Digest())
+	switch {
+	case err != nil:
+		return policyCheck{name: "signature", detail: err.Error()}
+	case v.Failure != "":
+		return policyCheck{name: "signature", detail: v.Failure}
+	case v.Level == trust.VerificationSkip:
+		return policyCheck{name: "signature", passed: true, detail: fmt.Sprintf("trust policy %q skips the signature verification", v.Policy)}
+	}
+	return policyCheck{name: "signature", passed: true, detail: fmt.Sprintf("signed by %q", v.Signer)}
+}
+
+func checkVulnerabilities(ctx context.Context, dockerCli command.Cli, policy *imagePolicy, img string, options *createOptions) (policyCheck, error) {
+	report, err := image.ScanImage(ctx, dockerCli, options.rootCmd, policy.Scanner, img, options.platform)
+	if err != nil {
+		return policyCheck{}, err
+	}
+	ignored := make(map[string]bool, len(policy.IgnoreVulnerabilities))
+	for _, id := range policy.IgnoreVulnerabilities {
+		ignored[id] = true
+	}
+	var found []string
+	for _, v := range report.Vulnerabilities {
+		if !ignored[v.ID] && v.Severity != policy.MaxSeverity && scanner.AtLeast(v.Severity, policy.MaxSeverity) {
+			found = append(found, fmt.Sprintf("%s (%s)", v.ID, v.Severity))
+		}
+	}
+	switch len(found) {
+	case 0:
+		return policyCheck{name: "vulnerabilities", passed: true, detail: fmt.Sprintf("no vulnerabilities more severe than %s", policy.MaxSeverity)}, nil
+	case 1:
+		return policyCheck{name: "vulnerabilities", detail: fmt.Sprintf("1 vulnerability more severe than %s: %s", policy.MaxSeverity, found[0])}, nil
+	}
+	return policyCheck{name: "vulnerabilities", detail: fmt.Sprintf("%d vulnerabilities more severe than %s: %s", len(found), policy.MaxSeverity, strings.Join(found, ", "))}, nil
+}
@@ -0,0 +1,147 @@
+package container
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestLoadImagePolicy(t *testing.T) {
+	dir := fs.NewDir(t, "policy",
+		fs.WithFile("valid.yaml", "registries: [registry.example.com]\nmaxSeverity: HIGH\nignoreVulnerabilities: [CVE-2024-0727]\n"),
+		fs.WithFile("unknown.yaml", "registry: registry.example.com\n"),
+		fs.WithFile("severity.yaml", "maxSeverity: severe\n"),
+		fs.WithFile("empty.yaml", "scanner: grype\n"),
+	)
+
+	policy, err := loadImagePolicy(dir.Join("valid.yaml"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(policy, &imagePolicy{
+		Registries:            []string{"registry.example.com"},
+		MaxSeverity:           "high",
+		IgnoreVulnerabilities: []string{"CVE-2024-0727"},
+	}))
+
+	_, err = loadImagePolicy(dir.Join("unknown.yaml"))
+	assert.Check(t, is.ErrorContains(err, "field registry not found"))
+	_, err = loadImagePolicy(dir.Join("severity.yaml"))
+	assert.Check(t, is.ErrorContains(err, `invalid severity "severe"`))
+	_, err = loadImagePolicy(dir.Join("empty.yaml"))
+	assert.Check(t, is.ErrorContains(err, "it doesn't set registries, requireSignature, or maxSeverity"))
+}
+
+func TestCreateContainerVerifyPolicy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	const digest = "sha256:4b8e6b4a4ba0bb5a8f1e5e4e1c3f8a4e9b51f0c2b1a8e4f3f1e2d9c7a5b3e1d0"
+	plugins := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "docker-cli-plugin-metadata" ]; then
+	echo '{"SchemaVersion":"0.1.0","Vendor":"Example","Scanner":true}'
+	exit
+fi
+echo '{"SchemaVersion":"0.1.0","Image":"alpine","Vulnerabilities":[{"ID":"CVE-2024-0727","Severity":"high"},{"ID":"CVE-2023-42363","Severity":"medium"}]}'
+`
+	assert.NilError(t, os.WriteFile(filepath.Join(plugins, "docker-scanner"), []byte(script), 0o755))
+
+	cases := []struct {
+		doc           string
+		policy        string
+		pull          string
+		repoDigests   []string
+		expectedImage string
+		expectedErr   string
+		expectedPulls int
+	}{
+		{
+			doc:           "allowed registry",
+			policy:        "registries: [docker.io/library]\n",
+			repoDigests:   []string{"alpine@" + digest},
+			expectedImage: "alpine@" + digest,
+		},
+		{
+			doc:    "registry not allowed",
+			policy: "registries: [registry.example.com/]\n",
+			pull:   PullImageAlways,
+			expectedErr: "image alpine:latest doesn't satisfy the policy POLICY:\n" +
+				"  registry  FAIL  docker.io/library/alpine isn't in an allowed registry (registry.example.com/)",
+		},
+		{
+			doc:           "pull always",
+			policy:        "registries: [docker.io]\n",
+			pull:          PullImageAlways,
+			expectedImage: "alpine",
+			expectedPulls: 1,
+		},
+		{
+			doc:           "vulnerabilities allowed",
+			policy:        "maxSeverity: high\n",
+			expectedImage: "alpine",
+		},
+		{
+			doc:           "vulnerability ignored",
+			policy:        "maxSeverity: medium\nignoreVulnerabilities: [CVE-2024-0727]\n",
+			expectedImage: "alpine",
+		},
+		{
+			doc:    "too severe vulnerabilities",
+			policy: "registries: [docker.io]\nrequireSignature: true\nmaxSeverity: low\n",
+			expectedErr: "image alpine:latest doesn't satisfy the policy POLICY:\n" +
+				"  registry         PASS  docker.io/library/alpine is in docker.io\n" +
+				"  signature        FAIL  image alpine:latest has no digest: only images that were pulled from, or pushed to, a registry have one\n" +
+				"  vulnerabilities  FAIL  2 vulnerabilities more severe than low: CVE-2024-0727 (high), CVE-2023-42363 (medium)",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.doc, func(t *testing.T) {
+			policy := filepath.Join(t.TempDir(), "policy.yaml")
+			assert.NilError(t, os.WriteFile(policy, []byte(tc.policy), 0o644))
+
+			var createdConfig *container.Config
+			pulls := 0
+			fakeCLI := test.NewFakeCli(&fakeClient{
+				imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+					return types.ImageInspect{RepoDigests: tc.repoDigests}, nil, nil
+				},
+				imageCreateFunc: func(string, image.CreateOptions) (io.ReadCloser, error) {
+					pulls++
+					return io.NopCloser(strings.NewReader("")), nil
+				},
+				createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+					createdConfig = config
+					return container.CreateResponse{ID: "abcdef"}, nil
+				},
+			})
+			fakeCLI.ConfigFile().CLIPluginsExtraDirs = []string{plugins}
+			options := createOptions{untrusted: true, pull: tc.pull, verifyPolicy: policy, rootCmd: &cobra.Command{}}
+			_, err := createContainer(context.Background(), fakeCLI, &containerConfig{
+				Config:     &container.Config{Image: "alpine"},
+				HostConfig: &container.HostConfig{},
+			}, &options)
+			assert.Check(t, is.Equal(pulls, tc.expectedPulls))
+			if tc.expectedErr != "" {
+				assert.Check(t, is.Error(err, strings.ReplaceAll(tc.expectedErr, "POLICY", policy)))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(createdConfig.Image, tc.expectedImage))
+			assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "Image alpine:latest satisfies the policy "+policy+"\n"))
+		})
+	}
+}
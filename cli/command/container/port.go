@@ -3,7 +3,6 @@ package container
 import (
 	"context"
 	"fmt"
-	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,6 +10,9 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
 	"github.com/fvbommel/sortorder"
 	"github.com/pkg/errors"
@@ -20,7 +22,10 @@ import (
 type portOptions struct {
 	container string
 
-	port string
+	port     string
+	format   string
+	hostPort string
+	check    bool
 }
 
 // NewPortCommand creates a new cobra.Command for `docker port`
@@ -28,10 +33,18 @@ func NewPortCommand(dockerCli command.Cli) *cobra.Command {
 	var opts portOptions
 
 	cmd := &cobra.Command{
-		Use:   "port CONTAINER [PRIVATE_PORT[/PROTO]]",
+		Use:   "port [OPTIONS] CONTAINER [PRIVATE_PORT[/PROTO]]",
 		Short: "List port mappings or a specific mapping for the container",
-		Args:  cli.RequiresRangeArgs(1, 2),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.hostPort != "" {
+				return cli.NoArgs(cmd, args)
+			}
+			return cli.RequiresRangeArgs(1, 2)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.hostPort != "" {
+				return runPortHost(cmd.Context(), dockerCli, &opts)
+			}
 			opts.container = args[0]
 			if len(args) > 1 {
 				opts.port = args[1]
@@ -43,6 +56,11 @@ func NewPortCommand(dockerCli command.Cli) *cobra.Command {
 		},
 		ValidArgsFunction: completion.ContainerNames(dockerCli, false),
 	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&opts.hostPort, "host", "", "Show the containers that publish the given port of the host (HOST_PORT[/PROTO])")
+	flags.BoolVar(&opts.check, "check", false, "Check that the published ports accept connections")
 	return cmd
 }
 
@@ -58,7 +76,7 @@ func runPort(ctx context.Context, dockerCli command.Cli, opts *portOptions) erro
 		return err
 	}
 
-	var out []string
+	var mappings []portMapping
 	if opts.port != "" {
 		port, proto, _ := strings.Cut(opts.port, "/")
 		if proto == "" {
@@ -72,22 +90,130 @@ func runPort(ctx context.Context, dockerCli command.Cli, opts *portOptions) erro
 			return errors.Errorf("Error: No public port '%s' published for %s", opts.port, opts.container)
 		}
 		for _, frontend := range frontends {
-			out = append(out, net.JoinHostPort(frontend.HostIP, frontend.HostPort))
+			mappings = append(mappings, portMapping{port: nat.Port(port + "/" + proto), binding: frontend})
 		}
+		sortPortMappings(mappings)
 	} else {
-		for from, frontends := range c.NetworkSettings.Ports {
-			for _, frontend := range frontends {
-				out = append(out, fmt.Sprintf("%s -> %s", from, net.JoinHostPort(frontend.HostIP, frontend.HostPort)))
+		mappings = portMappings(c.NetworkSettings.Ports)
+	}
+
+	if opts.check {
+		return writeCheckedPorts(dockerCli, opts, defaultPortTableFormat, mappings)
+	}
+	if opts.format != "" {
+		return portFormatWrite(formatter.Context{
+			Output: dockerCli.Out(),
+			Format: NewPortFormat(opts.format),
+		}, mappings)
+	}
+
+	if len(mappings) > 0 {
+		out := make([]string, 0, len(mappings))
+		for _, m := range mappings {
+			if opts.port != "" {
+				out = append(out, m.hostAddress())
+			} else {
+				out = append(out, m.String())
 			}
 		}
+		_, _ = fmt.Fprintln(dockerCli.Out(), strings.Join(out, "\n"))
 	}
 
-	if len(out) > 0 {
-		sort.Slice(out, func(i, j int) bool {
-			return sortorder.NaturalLess(out[i], out[j])
-		})
-		_, _ = fmt.Fprintln(dockerCli.Out(), strings.Join(out, "\n"))
+	return nil
+}
+
+// runPortHost shows the mappings of the running containers that publish a
+// port of the host.
+func runPortHost(ctx context.Context, dockerCli command.Cli, opts *portOptions) error {
+	port, proto, _ := strings.Cut(opts.hostPort, "/")
+	if proto == "" {
+		proto = "tcp"
+	}
+	hostPort, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return errors.Wrapf(err, "invalid host port (%s)", port)
 	}
 
+	containers, err := dockerCli.Client().ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var mappings []portMapping
+	for _, c := range containers {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		for _, p := range c.Ports {
+			if uint64(p.PublicPort) != hostPort || p.Type != proto {
+				continue
+			}
+			mappings = append(mappings, portMapping{
+				container: name,
+				port:      nat.Port(strconv.Itoa(int(p.PrivatePort)) + "/" + p.Type),
+				binding:   nat.PortBinding{HostIP: p.IP, HostPort: strconv.Itoa(int(p.PublicPort))},
+			})
+		}
+	}
+	if len(mappings) == 0 {
+		return errors.Errorf("no running container publishes host port %d/%s", hostPort, proto)
+	}
+	sort.Slice(mappings, func(i, j int) bool {
+		if mappings[i].container != mappings[j].container {
+			return sortorder.NaturalLess(mappings[i].container, mappings[j].container)
+		}
+		return sortorder.NaturalLess(mappings[i].String(), mappings[j].String())
+	})
+
+	if opts.check {
+		return writeCheckedPorts(dockerCli, opts, hostPortTableFormat, mappings)
+	}
+	format := opts.format
+	if format == "" || format == formatter.TableFormatKey {
+		format = hostPortTableFormat
+	}
+	return portFormatWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewPortFormat(format),
+	}, mappings)
+}
+
+// writeCheckedPorts checks that the published ports of mappings accept
+// connections, and writes the mappings with the result, with tableFormat
+// followed by the status if no format is specified. It returns an error if
+// a port doesn't accept connections.
+func writeCheckedPorts(dockerCli command.Cli, opts *portOptions, tableFormat string, mappings []portMapping) error {
+	failed := checkPorts(dockerCli.Client().DaemonHost(), mappings)
+	format := opts.format
+	if format == "" || format == formatter.TableFormatKey {
+		format = tableFormat + "\t{{.Status}}"
+	}
+	if err := portFormatWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: NewPortFormat(format),
+	}, mappings); err != nil {
+		return err
+	}
+	if failed {
+		return cli.StatusError{StatusCode: 1}
+	}
 	return nil
 }
+
+// portMappings returns the mappings of ports, sorted by container port.
+func portMappings(ports nat.PortMap) []portMapping {
+	var mappings []portMapping
+	for from, frontends := range ports {
+		for _, frontend := range frontends {
+			mappings = append(mappings, portMapping{port: from, binding: frontend})
+		}
+	}
+	sortPortMappings(mappings)
+	return mappings
+}
+
+func sortPortMappings(mappings []portMapping) {
+	sort.Slice(mappings, func(i, j int) bool {
+		return sortorder.NaturalLess(mappings[i].String(), mappings[j].String())
+	})
+}
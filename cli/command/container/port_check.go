@@ -0,0 +1,66 @@
+package container
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// portCheckTimeout is the maximum time to wait for a connection to a port.
+const portCheckTimeout = 2 * time.Second
+
+// checkPorts sets the status of mappings to the result of connecting to their
+// published port, and returns whether a port didn't accept connections. Only
+// TCP ports can be checked, as UDP is connectionless.
+func checkPorts(daemonHost string, mappings []portMapping) (failed bool) {
+	for i, m := range mappings {
+		if m.port.Proto() != "tcp" {
+			mappings[i].status = "not checked (" + m.port.Proto() + ")"
+			continue
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(dialHost(daemonHost, m.binding.HostIP), m.binding.HostPort), portCheckTimeout)
+		if err != nil {
+			failed = true
+			mappings[i].status = "unreachable: " + connectError(err)
+			continue
+		}
+		_ = conn.Close()
+		mappings[i].status = "reachable"
+	}
+	return failed
+}
+
+// dialHost returns the host to connect to for a port that's published on
+// hostIP of the daemon's host. Ports that are published on all addresses are
+// connected to through the host of the daemon, or through the loopback
+// address if the daemon is local, as it's the case for a unix socket, or for
+// Docker Desktop, which forwards published ports to the loopback address.
+func dialHost(daemonHost, hostIP string) string {
+	ip := net.ParseIP(hostIP)
+	if hostIP != "" && ip != nil && !ip.IsUnspecified() {
+		return hostIP
+	}
+	if u, err := url.Parse(daemonHost); err == nil && (u.Scheme == "tcp" || u.Scheme == "ssh") && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	if ip != nil && ip.To4() == nil {
+		return "::1"
+	}
+	return "127.0.0.1"
+}
+
+// connectError returns the reason of an error to connect, without the
+// operation and the address that are in the message of the error.
+func connectError(err error) string {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Err != nil {
+		err = opErr.Err
+	}
+	msg := err.Error()
+	if i := strings.LastIndex(msg, ": "); i >= 0 {
+		msg = msg[i+2:]
+	}
+	return msg
+}
@@ -2,12 +2,16 @@ package container
 
 import (
 	"io"
+	"net"
 	"testing"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -76,3 +80,124 @@ func TestNewPortCommandOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestPortFormat(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			ci := types.ContainerJSON{NetworkSettings: &types.NetworkSettings{}}
+			ci.NetworkSettings.Ports = nat.PortMap{
+				"80/tcp":  {{HostIP: "0.0.0.0", HostPort: "3456"}},
+				"443/udp": {{HostIP: "127.0.0.1", HostPort: "5678"}},
+			}
+			return ci, nil
+		},
+	})
+	cmd := NewPortCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Port}} {{.HostPort}}", "some_container"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "80/tcp 3456\n443/udp 5678\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = NewPortCommand(cli)
+	cmd.SetArgs([]string{"--format", "table", "some_container", "443/udp"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "container-port-format-table.golden")
+}
+
+func TestPortHost(t *testing.T) {
+	fakeCli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(container.ListOptions) ([]types.Container, error) {
+			return []types.Container{
+				{
+					ID:    "id-web",
+					Names: []string{"/web"},
+					Ports: []types.Port{
+						{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+						{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+						{IP: "0.0.0.0", PrivatePort: 53, PublicPort: 8080, Type: "udp"},
+					},
+				},
+				{
+					ID:    "id-db",
+					Names: []string{"/db"},
+					Ports: []types.Port{{IP: "127.0.0.1", PrivatePort: 5432, PublicPort: 5432, Type: "tcp"}},
+				},
+			}, nil
+		},
+	})
+	cmd := NewPortCommand(fakeCli)
+	cmd.SetArgs([]string{"--host", "8080"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, fakeCli.OutBuffer().String(), "container-port-host.golden")
+
+	fakeCli.OutBuffer().Reset()
+	cmd = NewPortCommand(fakeCli)
+	cmd.SetArgs([]string{"--host", "8080/udp", "--format", "{{.Container}} {{.Port}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "web 53/udp\n"))
+
+	cmd = NewPortCommand(fakeCli)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--host", "9090"})
+	assert.Check(t, is.Error(cmd.Execute(), "no running container publishes host port 9090/tcp"))
+
+	cmd = NewPortCommand(fakeCli)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--host", "8080", "some_container"})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "accepts no arguments"))
+}
+
+func TestPortCheck(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer l.Close()
+	_, openPort, _ := net.SplitHostPort(l.Addr().String())
+
+	// Get a port that nothing listens on.
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	_, closedPort, _ := net.SplitHostPort(closed.Addr().String())
+	assert.NilError(t, closed.Close())
+
+	ports := nat.PortMap{
+		"80/tcp": {{HostIP: "127.0.0.1", HostPort: openPort}},
+		"53/udp": {{HostIP: "0.0.0.0", HostPort: "5353"}},
+	}
+	fakeCli := test.NewFakeCli(&fakeClient{
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			ci := types.ContainerJSON{NetworkSettings: &types.NetworkSettings{}}
+			ci.NetworkSettings.Ports = ports
+			return ci, nil
+		},
+	})
+	cmd := NewPortCommand(fakeCli)
+	cmd.SetArgs([]string{"--check", "--format", "{{.Port}} {{.Status}}", "some_container"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "53/udp not checked (udp)\n80/tcp reachable\n"))
+
+	ports["443/tcp"] = []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: closedPort}}
+	fakeCli.OutBuffer().Reset()
+	cmd = NewPortCommand(fakeCli)
+	cmd.SetArgs([]string{"--check", "--format", "{{.Port}} {{.Status}}", "some_container", "443"})
+	assert.Check(t, is.DeepEqual(cmd.Execute(), cli.StatusError{StatusCode: 1}))
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "443/tcp unreachable: connection refused\n"))
+}
+
+func TestDialHost(t *testing.T) {
+	testCases := []struct {
+		daemonHost string
+		hostIP     string
+		expected   string
+	}{
+		{daemonHost: "unix:///var/run/docker.sock", hostIP: "0.0.0.0", expected: "127.0.0.1"},
+		{daemonHost: "unix:///var/run/docker.sock", hostIP: "", expected: "127.0.0.1"},
+		{daemonHost: "unix:///var/run/docker.sock", hostIP: "::", expected: "::1"},
+		{daemonHost: "unix:///var/run/docker.sock", hostIP: "192.168.1.10", expected: "192.168.1.10"},
+		{daemonHost: "tcp://docker.example.com:2376", hostIP: "0.0.0.0", expected: "docker.example.com"},
+		{daemonHost: "ssh://me@docker.example.com", hostIP: "::", expected: "docker.example.com"},
+		{daemonHost: "tcp://docker.example.com:2376", hostIP: "10.0.0.5", expected: "10.0.0.5"},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Equal(dialHost(tc.daemonHost, tc.hostIP), tc.expected), "%s %s", tc.daemonHost, tc.hostIP)
+	}
+}
@@ -0,0 +1,219 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/internal/diskspace"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Values for the "resourceChecks" property of the CLI configuration file.
+const (
+	resourceChecksWarn  = "warn"
+	resourceChecksBlock = "block"
+)
+
+// defaultMinFreeDiskSpace is the free disk space under which a warning is
+// printed if the "minFreeDiskSpace" property is not set.
+const defaultMinFreeDiskSpace = 2 * units.GiB
+
+// checkResources checks that the host has enough resources to start a
+// container with the given host config, if enabled by the "resourceChecks"
+// property of the configuration file. Problems are printed as warnings, or
+// returned as an error if "resourceChecks" is set to "block".
+//
+// The checks are best-effort; if the information they need cannot be
+// obtained, they are skipped.
+func checkResources(ctx context.Context, dockerCli command.Cli, hostConfig *container.HostConfig) error {
+	cfg := dockerCli.ConfigFile()
+	switch cfg.ResourceChecks {
+	case "":
+		return nil
+	case resourceChecksWarn, resourceChecksBlock:
+	default:
+		return errors.Errorf("invalid resourceChecks %q in the configuration file: must be %q or %q", cfg.ResourceChecks, resourceChecksWarn, resourceChecksBlock)
+	}
+	minFree := int64(defaultMinFreeDiskSpace)
+	if cfg.MinFreeDiskSpace != "" {
+		var err error
+		if minFree, err = units.RAMInBytes(cfg.MinFreeDiskSpace); err != nil {
+			return errors.Wrapf(err, "invalid minFreeDiskSpace %q in the configuration file", cfg.MinFreeDiskSpace)
+		}
+	}
+
+	info, err := dockerCli.Client().Info(ctx)
+	if err != nil {
+		logrus.Debugf("Skipping resource checks: %v", err)
+		return nil
+	}
+	var problems []string
+	if hostConfig.Memory > 0 && info.MemTotal > 0 {
+		if used, err := containersMemoryUsage(ctx, dockerCli); err != nil {
+			logrus.Debugf("Skipping memory check: %v", err)
+		} else if used+hostConfig.Memory > info.MemTotal {
+			problems = append(problems, fmt.Sprintf(
+				"the requested memory (%s) and the memory used by running containers (%s) exceed the memory of the host (%s)",
+				units.BytesSize(float64(hostConfig.Memory)), units.BytesSize(float64(used)), units.BytesSize(float64(info.MemTotal)),
+			))
+		}
+	}
+	// The free disk space can only be checked if the daemon runs on the
+	// same host as the CLI.
+	if info.DockerRootDir != "" && strings.HasPrefix(dockerCli.Client().DaemonHost(), "unix://") {
+		if usage, err := diskspace.Get(info.DockerRootDir); err != nil {
+			logrus.Debugf("Skipping disk space check: %v", err)
+		} else if usage.Free < uint64(minFree) {
+			problems = append(problems, fmt.Sprintf(
+				"the free disk space of %s (%s) is below %s",
+				info.DockerRootDir, units.BytesSize(float64(usage.Free)), units.BytesSize(float64(minFree)),
+			))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	if cfg.ResourceChecks == resourceChecksBlock {
+		return errors.Errorf("not enough resources to start the container: %s\nSet \"resourceChecks\" to %q in the configuration file to start it anyway", strings.Join(problems, "; "), resourceChecksWarn)
+	}
+	for _, p := range problems {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", p)
+	}
+	return nil
+}
+
+// containersMemoryUsage returns the memory used by running containers,
+// excluding the page cache.
+func containersMemoryUsage(ctx context.Context, dockerCli command.Cli) (int64, error) {
+	containers, err := dockerCli.Client().ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, c := range containers {
+		usage, err := containerMemoryUsage(ctx, dockerCli, c.ID)
+		if err != nil {
+			return 0, err
+		}
+		total += usage
+	}
+	return total, nil
+}
+
+func containerMemoryUsage(ctx context.Context, dockerCli command.Cli, containerID string) (int64, error) {
+	response, err := dockerCli.Client().ContainerStatsOneShot(ctx, containerID)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	var v container.StatsResponse
+	if err := json.NewDecoder(response.Body).Decode(&v); err != nil {
+		return 0, err
+	}
+	if response.OSType == "windows" {
+		return int64(v.MemoryStats.PrivateWorkingSet), nil
+	}
+	return int64(calculateMemUsageUnixNoCache(v.MemoryStats)), nil
+}
+
+// checkHostPaths checks the host paths of the bind mounts of a container
+// before it's created, if the daemon runs on the same host as the CLI. If
+// createHostDirs is set, missing host directories are created. Problems are
+// printed as warnings, after which the user is asked whether to create the
+// container anyway if stdin is a terminal.
+func checkHostPaths(ctx context.Context, dockerCli command.Cli, hostConfig *container.HostConfig, createHostDirs bool) error {
+	daemonHost := dockerCli.Client().DaemonHost()
+	if !strings.HasPrefix(daemonHost, "unix://") && !strings.HasPrefix(daemonHost, "npipe://") {
+		return nil
+	}
+	problems, err := hostPathProblems(dockerCli.Err(), hostConfig, createHostDirs)
+	if err != nil || len(problems) == 0 {
+		return err
+	}
+	for _, p := range problems {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", p)
+	}
+	if !dockerCli.In().IsTerminal() {
+		return nil
+	}
+	ok, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), "Create the container anyway?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errdefs.Cancelled(errors.New("creating the container has been cancelled"))
+	}
+	return nil
+}
+
+// hostPathProblems returns the problems with the host paths of the bind
+// mounts of hostConfig, after creating the missing host directories if
+// createHostDirs is set.
+func hostPathProblems(stderr io.Writer, hostConfig *container.HostConfig, createHostDirs bool) ([]string, error) {
+	type bindMount struct {
+		source, target string
+		// createSource is whether the daemon creates the source if it
+		// doesn't exist, as it does for "--volume".
+		createSource bool
+	}
+	var binds []bindMount
+	for _, bind := range hostConfig.Binds {
+		parsed, err := loader.ParseVolume(bind)
+		if err != nil || parsed.Type != string(mount.TypeBind) {
+			continue
+		}
+		binds = append(binds, bindMount{source: parsed.Source, target: parsed.Target, createSource: true})
+	}
+	for _, m := range hostConfig.Mounts {
+		if m.Type != mount.TypeBind {
+			continue
+		}
+		binds = append(binds, bindMount{
+			source:       m.Source,
+			target:       m.Target,
+			createSource: m.BindOptions != nil && m.BindOptions.CreateMountpoint,
+		})
+	}
+
+	var problems []string
+	for _, b := range binds {
+		fi, err := os.Stat(b.source)
+		switch {
+		case err == nil:
+			if !fi.IsDir() && strings.HasSuffix(b.target, "/") {
+				problems = append(problems, fmt.Sprintf("the host path %s is a file, but the target %s of its bind mount is a directory", b.source, b.target))
+			}
+		case !os.IsNotExist(err):
+			// The daemon may be able to access paths that the user can't.
+			continue
+		case path.Ext(b.target) != "" && !strings.HasSuffix(b.target, "/"):
+			// Creating a directory wouldn't help, as a file is likely
+			// expected in the container.
+			problems = append(problems, fmt.Sprintf("the host path %s does not exist, and the target %s of its bind mount looks like a file: create the file on the host to mount it", b.source, b.target))
+		case createHostDirs:
+			if err := os.MkdirAll(b.source, 0o755); err != nil {
+				return nil, errors.Wrap(err, "failed to create the host directory of a bind mount")
+			}
+			_, _ = fmt.Fprintf(stderr, "Created the host directory %s\n", b.source)
+		case b.createSource:
+			problems = append(problems, fmt.Sprintf("the host path %s does not exist, and is created as an empty directory owned by the daemon: use --create-host-dirs to create it", b.source))
+		default:
+			problems = append(problems, fmt.Sprintf("the host path %s of the bind mount on %s does not exist: use --create-host-dirs to create it", b.source, b.target))
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,135 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/system"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestCheckResources(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	testCases := []struct {
+		name           string
+		resourceChecks string
+		memory         int64
+		expectedErr    string
+		expectedStderr string
+	}{
+		{
+			name:   "disabled",
+			memory: 4 * gib,
+		},
+		{
+			name:           "enough memory",
+			resourceChecks: "block",
+			memory:         gib,
+		},
+		{
+			name:           "no memory requested",
+			resourceChecks: "block",
+		},
+		{
+			name:           "warn",
+			resourceChecks: "warn",
+			memory:         3 * gib,
+			expectedStderr: "WARNING: the requested memory (3GiB) and the memory used by running containers (2GiB) exceed the memory of the host (4GiB)\n",
+		},
+		{
+			name:           "block",
+			resourceChecks: "block",
+			memory:         3 * gib,
+			expectedErr:    "not enough resources to start the container: the requested memory (3GiB) and the memory used by running containers (2GiB) exceed the memory of the host (4GiB)",
+		},
+		{
+			name:           "invalid mode",
+			resourceChecks: "yes",
+			expectedErr:    `invalid resourceChecks "yes" in the configuration file`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				infoFunc: func() (system.Info, error) {
+					if tc.resourceChecks == "" {
+						t.Error("unexpected API call when resource checks are disabled")
+					}
+					return system.Info{MemTotal: 4 * gib}, nil
+				},
+				containerListFunc: func(container.ListOptions) ([]types.Container, error) {
+					return []types.Container{{ID: "one"}, {ID: "two"}}, nil
+				},
+				containerStatsFunc: func(string) (container.StatsResponseReader, error) {
+					return container.StatsResponseReader{
+						Body: io.NopCloser(strings.NewReader(`{"memory_stats":{"usage":1342177280,"stats":{"inactive_file":268435456}}}`)),
+					}, nil
+				},
+			})
+			cli.SetConfigFile(&configfile.ConfigFile{ResourceChecks: tc.resourceChecks})
+
+			err := checkResources(context.Background(), cli, &container.HostConfig{
+				Resources: container.Resources{Memory: tc.memory},
+			})
+			if tc.expectedErr != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			} else {
+				assert.Check(t, err)
+			}
+			assert.Check(t, is.Equal(cli.ErrBuffer().String(), tc.expectedStderr))
+		})
+	}
+}
+
+func TestHostPathProblems(t *testing.T) {
+	dir := fs.NewDir(t, "host-paths", fs.WithFile("app.conf", ""), fs.WithDir("data"))
+	defer dir.Remove()
+
+	hostConfig := &container.HostConfig{
+		Binds: []string{
+			dir.Join("data") + ":/data",
+			dir.Join("app.conf") + ":/etc/app/",
+			dir.Join("missing") + ":/missing",
+			dir.Join("nginx.conf") + ":/etc/nginx/nginx.conf:ro",
+			"named-volume:/volume",
+		},
+		Mounts: []mount.Mount{
+			{Type: mount.TypeBind, Source: dir.Join("missing-mount"), Target: "/mnt"},
+			{Type: mount.TypeVolume, Source: "other-volume", Target: "/other"},
+		},
+	}
+	var stderr bytes.Buffer
+	problems, err := hostPathProblems(&stderr, hostConfig, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(problems, []string{
+		"the host path " + dir.Join("app.conf") + " is a file, but the target /etc/app/ of its bind mount is a directory",
+		"the host path " + dir.Join("missing") + " does not exist, and is created as an empty directory owned by the daemon: use --create-host-dirs to create it",
+		"the host path " + dir.Join("nginx.conf") + " does not exist, and the target /etc/nginx/nginx.conf of its bind mount looks like a file: create the file on the host to mount it",
+		"the host path " + dir.Join("missing-mount") + " of the bind mount on /mnt does not exist: use --create-host-dirs to create it",
+	}))
+	assert.Check(t, is.Equal(stderr.String(), ""))
+
+	problems, err = hostPathProblems(&stderr, hostConfig, true)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(problems, 2))
+	assert.Check(t, is.Equal(stderr.String(), "Created the host directory "+dir.Join("missing")+"\nCreated the host directory "+dir.Join("missing-mount")+"\n"))
+	for _, p := range []string{"missing", "missing-mount"} {
+		fi, err := os.Stat(dir.Join(p))
+		assert.NilError(t, err)
+		assert.Check(t, fi.IsDir())
+	}
+	_, err = os.Stat(dir.Join("nginx.conf"))
+	assert.Check(t, os.IsNotExist(err))
+}
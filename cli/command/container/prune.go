@@ -3,20 +3,38 @@ package container
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/errdefs"
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+// Values for the --keep-by flag.
+const (
+	keepByImage   = "image"
+	keepByProject = "project"
+)
+
+// composeProjectLabel is the label that docker compose sets on containers to
+// identify the project they belong to.
+const composeProjectLabel = "com.docker.compose.project"
+
 type pruneOptions struct {
-	force  bool
-	filter opts.FilterOpt
+	force    bool
+	filter   opts.FilterOpt
+	keepLast int
+	keepBy   string
 }
 
 // NewPruneCommand returns a new cobra prune command for containers
@@ -45,6 +63,8 @@ func NewPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "until=<timestamp>")`)
+	flags.IntVar(&options.keepLast, "keep-last", 0, "Keep the N most recently created stopped containers per image or project")
+	flags.StringVar(&options.keepBy, "keep-by", keepByImage, `Group containers for "--keep-last" by "`+keepByImage+`" or compose "`+keepByProject+`"`)
 
 	return cmd
 }
@@ -55,6 +75,13 @@ Are you sure you want to continue?`
 func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint64, output string, err error) {
 	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value())
 
+	if options.keepLast < 0 {
+		return 0, "", errors.Errorf("invalid value for --keep-last: %d: must be a positive number", options.keepLast)
+	}
+	if options.keepBy != keepByImage && options.keepBy != keepByProject {
+		return 0, "", errors.Errorf("invalid value for --keep-by: %q: must be %q or %q", options.keepBy, keepByImage, keepByProject)
+	}
+
 	if !options.force {
 		r, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), warning)
 		if err != nil {
@@ -65,7 +92,12 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		}
 	}
 
-	report, err := dockerCli.Client().ContainersPrune(ctx, pruneFilters)
+	var report container.PruneReport
+	if options.keepLast > 0 {
+		report, err = pruneKeepLast(ctx, dockerCli, pruneFilters, options)
+	} else {
+		report, err = dockerCli.Client().ContainersPrune(ctx, pruneFilters)
+	}
 	if err != nil {
 		return 0, "", err
 	}
@@ -81,6 +113,115 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 	return spaceReclaimed, output, nil
 }
 
+// keepLastFilters are the prune filters that are supported with "--keep-last".
+var keepLastFilters = map[string]bool{
+	"label":  true,
+	"label!": true,
+	"until":  true,
+}
+
+// pruneKeepLast removes stopped containers that match the prune filters, but
+// keeps the options.keepLast most recently created containers of each image or
+// compose project. The API does not provide a filter for this, so containers
+// are selected and removed client-side. Containers that are not part of a
+// compose project are grouped by image when grouping by project.
+func pruneKeepLast(ctx context.Context, dockerCli command.Cli, pruneFilters filters.Args, options pruneOptions) (container.PruneReport, error) {
+	var report container.PruneReport
+
+	// Reject the filters that the prune API endpoint supports, but that
+	// are not implemented here, instead of ignoring them.
+	if err := pruneFilters.Validate(keepLastFilters); err != nil {
+		return report, err
+	}
+	until, err := getUntilFromPruneFilters(pruneFilters)
+	if err != nil {
+		return report, err
+	}
+	containers, err := dockerCli.Client().ContainerList(ctx, container.ListOptions{All: true, Size: true})
+	if err != nil {
+		return report, err
+	}
+
+	groups := map[string][]types.Container{}
+	for _, c := range containers {
+		if !isPrunable(c, pruneFilters, until) {
+			continue
+		}
+		key := "image:" + c.ImageID
+		if project := c.Labels[composeProjectLabel]; options.keepBy == keepByProject && project != "" {
+			key = "project:" + project
+		}
+		groups[key] = append(groups[key], c)
+	}
+
+	var remove []types.Container
+	for _, group := range groups {
+		if len(group) <= options.keepLast {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Created > group[j].Created
+		})
+		remove = append(remove, group[options.keepLast:]...)
+	}
+	// remove oldest containers first
+	sort.SliceStable(remove, func(i, j int) bool {
+		return remove[i].Created < remove[j].Created
+	})
+
+	for _, c := range remove {
+		if err := dockerCli.Client().ContainerRemove(ctx, c.ID, container.RemoveOptions{}); err != nil {
+			// Like the prune endpoint, continue with the other containers.
+			_, _ = fmt.Fprintln(dockerCli.Err(), err)
+			continue
+		}
+		report.ContainersDeleted = append(report.ContainersDeleted, c.ID)
+		report.SpaceReclaimed += uint64(c.SizeRw)
+	}
+	return report, nil
+}
+
+// isPrunable returns whether the container is stopped, and matches the
+// "label", "label!", and "until" prune filters in the same way as the
+// container prune API endpoint.
+func isPrunable(c types.Container, pruneFilters filters.Args, until time.Time) bool {
+	switch c.State {
+	case "created", "exited", "dead":
+	default:
+		return false
+	}
+	if !until.IsZero() && time.Unix(c.Created, 0).After(until) {
+		return false
+	}
+	if !pruneFilters.MatchKVList("label", c.Labels) {
+		return false
+	}
+	// MatchKVList returns true if the "label!" filter is not set.
+	if pruneFilters.Contains("label!") && pruneFilters.MatchKVList("label!", c.Labels) {
+		return false
+	}
+	return true
+}
+
+func getUntilFromPruneFilters(pruneFilters filters.Args) (time.Time, error) {
+	until := pruneFilters.Get("until")
+	if len(until) == 0 {
+		return time.Time{}, nil
+	}
+	if len(until) > 1 {
+		return time.Time{}, errdefs.InvalidParameter(errors.New("more than one until filter specified"))
+	}
+	ts, err := timetypes.GetTimestamp(until[0], time.Now())
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
+
 // RunPrune calls the Container Prune API
 // This returns the amount of space reclaimed and a detailed output string
 func RunPrune(ctx context.Context, dockerCli command.Cli, _ bool, filter opts.FilterOpt) (uint64, string, error) {
@@ -2,14 +2,45 @@ package container
 
 import (
 	"context"
+	"io"
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
+func TestContainerPruneKeepLastInvalid(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--force", "--keep-last=-1"},
+			expectedError: "invalid value for --keep-last: -1: must be a positive number",
+		},
+		{
+			args:          []string{"--force", "--keep-last=1", "--keep-by=volume"},
+			expectedError: `invalid value for --keep-by: "volume": must be "image" or "project"`,
+		},
+		{
+			args:          []string{"--force", "--keep-last=1", "--filter=foo=bar"},
+			expectedError: "invalid filter 'foo'",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := NewPruneCommand(test.NewFakeCli(&fakeClient{}))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(tc.args)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
+
 func TestContainerPrunePromptTermination(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -22,3 +53,77 @@ func TestContainerPrunePromptTermination(t *testing.T) {
 	cmd := NewPruneCommand(cli)
 	test.TerminatePrompt(ctx, t, cmd, cli)
 }
+
+func TestContainerPruneKeepLast(t *testing.T) {
+	containers := []types.Container{
+		{ID: "running", ImageID: "img1", State: "running", Created: 100},
+		{ID: "img1-old", ImageID: "img1", State: "exited", Created: 10, SizeRw: 1},
+		{ID: "img1-new", ImageID: "img1", State: "exited", Created: 30, SizeRw: 2},
+		{ID: "img1-mid", ImageID: "img1", State: "created", Created: 20, SizeRw: 4},
+		{ID: "img2-old", ImageID: "img2", State: "dead", Created: 5, SizeRw: 8, Labels: map[string]string{"com.docker.compose.project": "proj"}},
+		{ID: "img2-new", ImageID: "img2", State: "exited", Created: 40, SizeRw: 16, Labels: map[string]string{"com.docker.compose.project": "proj"}},
+		{ID: "img3-only", ImageID: "img3", State: "exited", Created: 50, SizeRw: 32, Labels: map[string]string{"com.docker.compose.project": "proj", "keep": "true"}},
+		{ID: "img4-only", ImageID: "img4", State: "exited", Created: 60, SizeRw: 64},
+	}
+	testCases := []struct {
+		name            string
+		args            []string
+		expectedRemoved []string
+		expectedSpace   string
+	}{
+		{
+			name:            "keep last per image",
+			args:            []string{"--force", "--keep-last=1"},
+			expectedRemoved: []string{"img2-old", "img1-old", "img1-mid"},
+			expectedSpace:   "13B",
+		},
+		{
+			name:            "keep last two per image",
+			args:            []string{"--force", "--keep-last=2"},
+			expectedRemoved: []string{"img1-old"},
+			expectedSpace:   "1B",
+		},
+		{
+			name:            "keep last per project",
+			args:            []string{"--force", "--keep-last=1", "--keep-by=project"},
+			expectedRemoved: []string{"img2-old", "img1-old", "img1-mid", "img2-new"},
+			expectedSpace:   "29B",
+		},
+		{
+			name:            "with label filter",
+			args:            []string{"--force", "--keep-last=1", "--keep-by=project", "--filter=label!=keep"},
+			expectedRemoved: []string{"img2-old", "img1-old", "img1-mid"},
+			expectedSpace:   "13B",
+		},
+		{
+			name:            "with until filter",
+			args:            []string{"--force", "--keep-last=1", "--filter=until=1970-01-01T00:00:25Z"},
+			expectedRemoved: []string{"img1-old"},
+			expectedSpace:   "1B",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var removed []string
+			cli := test.NewFakeCli(&fakeClient{
+				containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+					assert.Check(t, options.All)
+					return containers, nil
+				},
+				containerRemoveFunc: func(ctx context.Context, containerID string, options container.RemoveOptions) error {
+					removed = append(removed, containerID)
+					return nil
+				},
+				containerPruneFunc: func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error) {
+					return container.PruneReport{}, errors.New("fakeClient containerPruneFunc should not be called")
+				},
+			})
+			cmd := NewPruneCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.DeepEqual(removed, tc.expectedRemoved))
+			assert.Check(t, is.Contains(cli.OutBuffer().String(), "Total reclaimed space: "+tc.expectedSpace))
+		})
+	}
+}
@@ -71,7 +71,7 @@ func runRm(ctx context.Context, dockerCli command.Cli, opts *rmOptions) error {
 			errs = append(errs, err.Error())
 			continue
 		}
-		fmt.Fprintln(dockerCli.Out(), name)
+		dockerCli.Out().Infof("%s\n", name)
 	}
 	if len(errs) > 0 {
 		return errors.New(strings.Join(errs, "\n"))
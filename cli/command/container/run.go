@@ -11,6 +11,8 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/mountpath"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/container"
 	"github.com/moby/sys/signal"
@@ -26,6 +28,10 @@ type runOptions struct {
 	detach     bool
 	sigProxy   bool
 	detachKeys string
+	loadDotenv bool
+	// notifyEvents reports the events that affect the container while
+	// attached to it.
+	notifyEvents bool
 }
 
 // NewRunCommand create a new `docker run` command
@@ -36,12 +42,24 @@ func NewRunCommand(dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run [OPTIONS] IMAGE [COMMAND] [ARG...]",
 		Short: "Create and run a new container from an image",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if copts.gpus.List() {
+				return cli.NoArgs(cmd, args)
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if copts.gpus.List() {
+				return RunGPUs(cmd.Context(), dockerCli, GPUsOptions{})
+			}
 			copts.Image = args[0]
 			if len(args) > 1 {
 				copts.Args = args[1:]
 			}
+			options.rootCmd = cmd.Root()
+			if dockerCli.Out().Level() == streams.LevelQuiet {
+				options.quiet = true
+			}
 			return runRun(cmd.Context(), dockerCli, cmd.Flags(), &options, copts)
 		},
 		ValidArgsFunction: completion.ImageNames(dockerCli),
@@ -59,8 +77,11 @@ func NewRunCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.sigProxy, "sig-proxy", true, "Proxy received signals to the process")
 	flags.StringVar(&options.name, "name", "", "Assign a name to the container")
 	flags.StringVar(&options.detachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
-	flags.StringVar(&options.pull, "pull", PullImageMissing, `Pull image before running ("`+PullImageAlways+`", "`+PullImageMissing+`", "`+PullImageNever+`")`)
-	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the pull output")
+	addPullFlags(flags, &options.createOptions, "running")
+	flags.StringVar(&options.verifyPolicy, "verify-policy", "", "Verify the image against a policy file before creating the container")
+	flags.BoolVar(&options.createHostDirs, "create-host-dirs", false, "Create the missing host directories of bind mounts")
+	flags.BoolVar(&options.loadDotenv, "load-dotenv", false, "Load the .env file of the current directory as an env file")
+	flags.BoolVar(&options.notifyEvents, "notify-events", false, "Report events that affect the container, such as an OOM kill, on STDERR while attached")
 
 	// Add an explicit help that doesn't have a `-h` to prevent the conflict
 	// with hostname
@@ -70,22 +91,7 @@ func NewRunCommand(dockerCli command.Cli) *cobra.Command {
 	command.AddTrustVerificationFlags(flags, &options.untrusted, dockerCli.ContentTrustEnabled())
 	copts = addFlags(flags)
 
-	cmd.RegisterFlagCompletionFunc(
-		"env",
-		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			return os.Environ(), cobra.ShellCompDirectiveNoFileComp
-		},
-	)
-	cmd.RegisterFlagCompletionFunc(
-		"env-file",
-		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			return nil, cobra.ShellCompDirectiveDefault
-		},
-	)
-	cmd.RegisterFlagCompletionFunc(
-		"network",
-		completion.NetworkNames(dockerCli),
-	)
+	addCompletions(cmd, dockerCli)
 	return cmd
 }
 
@@ -104,12 +110,42 @@ func runRun(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet, ro
 		}
 	}
 	copts.env = *opts.NewListOptsRef(&newEnv, nil)
+	loadDotenv := dockerCli.ConfigFile().LoadDotenv
+	if flags.Changed("load-dotenv") {
+		loadDotenv = ropts.loadDotenv
+	}
+	if loadDotenv {
+		if err := addDotenv(dockerCli.Err(), copts); err != nil {
+			reportError(dockerCli.Err(), "run", err.Error(), true)
+			return cli.StatusError{StatusCode: 125}
+		}
+	}
+	if !flags.Changed("notify-events") {
+		// The notifications are written to STDERR, so they're only enabled
+		// by the configuration file if it's a terminal, so that they don't
+		// break scripts that parse it.
+		ropts.notifyEvents = dockerCli.ConfigFile().NotifyEvents && dockerCli.Err().IsTerminal()
+	}
 	containerCfg, err := parse(flags, copts, dockerCli.ServerInfo().OSType)
 	// just in case the parse does not exit
 	if err != nil {
 		reportError(dockerCli.Err(), "run", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
 	}
+	addHostGateway(dockerCli, flags, copts, containerCfg)
+	translateMountPaths(dockerCli.Err(), mountpath.CurrentHost(), dockerCli.ServerInfo().OSType, containerCfg.HostConfig)
+	if err := checkHostPaths(ctx, dockerCli, containerCfg.HostConfig, ropts.createHostDirs); err != nil {
+		reportError(dockerCli.Err(), "run", err.Error(), false)
+		return cli.StatusError{StatusCode: 125}
+	}
+	if err := checkResources(ctx, dockerCli, containerCfg.HostConfig); err != nil {
+		reportError(dockerCli.Err(), "run", err.Error(), false)
+		return cli.StatusError{StatusCode: 125}
+	}
+	if err := checkGPURuntime(ctx, dockerCli, copts); err != nil {
+		reportError(dockerCli.Err(), "run", err.Error(), false)
+		return cli.StatusError{StatusCode: 125}
+	}
 	if err = validateAPIVersion(containerCfg, dockerCli.CurrentVersion()); err != nil {
 		reportError(dockerCli.Err(), "run", err.Error(), true)
 		return cli.StatusError{StatusCode: 125}
@@ -117,6 +153,71 @@ func runRun(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet, ro
 	return runContainer(ctx, dockerCli, ropts, copts, containerCfg)
 }
 
+// dotenvFile is the env file in the current directory that is loaded with
+// the "--load-dotenv" option, or the "loadDotenv" setting in the configuration
+// file.
+const dotenvFile = ".env"
+
+// addDotenv adds the .env file of the current directory, if it exists, as the
+// first env file, so that the other env files and "--env" override it, and
+// prints the names of its variables.
+func addDotenv(stderr io.Writer, copts *containerOptions) error {
+	if _, err := os.Stat(dotenvFile); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	vars, err := opts.ParseEnvFile(dotenvFile)
+	if err != nil {
+		return err
+	}
+
+	envFiles := append([]string{dotenvFile}, copts.envFile.GetAll()...)
+	copts.envFile = *opts.NewListOptsRef(&envFiles, nil)
+
+	names := make([]string, 0, len(vars))
+	for _, v := range vars {
+		name, _, _ := strings.Cut(v, "=")
+		names = append(names, name)
+	}
+	_, _ = fmt.Fprintf(stderr, "Loaded %d variables from %s: %s\n", len(names), dotenvFile, strings.Join(names, ", "))
+	return nil
+}
+
+// hasAutoHostPort returns whether any of the "--publish" mappings has an
+// "auto" host port.
+func hasAutoHostPort(specs []string) bool {
+	specs, err := convertToStandardNotation(specs)
+	if err != nil {
+		return false
+	}
+	for _, spec := range specs {
+		rawPort, _, _ := strings.Cut(spec, "/")
+		parts := strings.Split(rawPort, ":")
+		if n := len(parts); n >= 2 && parts[n-2] == autoHostPort {
+			return true
+		}
+	}
+	return false
+}
+
+// printPublishedPorts prints the port mappings of a started container, which
+// include the host ports that were picked for it.
+func printPublishedPorts(ctx context.Context, dockerCli command.Cli, containerID string, lineEnd string) {
+	c, err := dockerCli.Client().ContainerInspect(ctx, containerID)
+	if err != nil {
+		logrus.Debugf("Error inspecting the published ports of container %s: %s", containerID, err)
+		return
+	}
+	if c.NetworkSettings == nil {
+		return
+	}
+	for _, m := range portMappings(c.NetworkSettings.Ports) {
+		_, _ = fmt.Fprint(dockerCli.Err(), m.String()+lineEnd)
+	}
+}
+
 //nolint:gocyclo
 func runContainer(ctx context.Context, dockerCli command.Cli, runOpts *runOptions, copts *containerOptions, containerCfg *containerConfig) error {
 	config := containerCfg.Config
@@ -191,6 +292,17 @@ func runContainer(ctx context.Context, dockerCli command.Cli, runOpts *runOption
 		defer closeFn()
 	}
 
+	if runOpts.notifyEvents && (config.AttachStdout || config.AttachStderr) {
+		// Report events that explain what happens to the container, such
+		// as an OOM kill, which would otherwise go unnoticed in its output.
+		lineEnd := "\n"
+		if config.Tty {
+			// the terminal may be in raw mode.
+			lineEnd = "\r\n"
+		}
+		notifyContainerEvents(ctx, apiClient, containerID, stderr, lineEnd)
+	}
+
 	// New context here because we don't to cancel waiting on container exit/remove
 	// when we cancel attach, etc.
 	statusCtx, cancelStatusCtx := context.WithCancel(context.WithoutCancel(ctx))
@@ -215,12 +327,44 @@ func runContainer(ctx context.Context, dockerCli command.Cli, runOpts *runOption
 		return runStartContainerErr(err)
 	}
 
+	if hasAutoHostPort(copts.publish.GetAll()) {
+		lineEnd := "\n"
+		if attach && config.Tty {
+			// the terminal may be in raw mode.
+			lineEnd = "\r\n"
+		}
+		printPublishedPorts(ctx, dockerCli, containerID, lineEnd)
+	}
+
 	if (config.AttachStdin || config.AttachStdout || config.AttachStderr) && config.Tty && dockerCli.Out().IsTerminal() {
 		if err := MonitorTtySize(ctx, dockerCli, containerID, false); err != nil {
 			_, _ = fmt.Fprintln(stderr, "Error monitoring TTY size:", err)
 		}
 	}
 
+	var setupStatus int
+	if cmds := copts.setupCmds.GetAll(); len(cmds) > 0 {
+		if waitDisplayID != nil {
+			// print the container ID before the output of the setup commands.
+			<-waitDisplayID
+		}
+		if err := runSetupCommands(ctx, dockerCli, containerID, dockerCli.ServerInfo().OSType, cmds); err != nil {
+			if !config.AttachStdout && !config.AttachStderr {
+				return err
+			}
+			// Report the failure, but continue streaming the container's
+			// output; the setup command's exit code is returned if the
+			// container exits successfully.
+			setupStatus = 125
+			msg := err.Error()
+			var sErr cli.StatusError
+			if errors.As(err, &sErr) {
+				setupStatus, msg = sErr.StatusCode, sErr.Status
+			}
+			reportError(stderr, "run", msg, false)
+		}
+	}
+
 	if errCh != nil {
 		if err := <-errCh; err != nil {
 			if _, ok := err.(term.EscapeError); ok {
@@ -241,6 +385,9 @@ func runContainer(ctx context.Context, dockerCli command.Cli, runOpts *runOption
 	}
 
 	status := <-statusChan
+	if status == 0 {
+		status = setupStatus
+	}
 	if status != 0 {
 		return cli.StatusError{StatusCode: status}
 	}
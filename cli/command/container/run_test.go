@@ -5,6 +5,7 @@ import (
 	"errors"
 	"io"
 	"net"
+	"os"
 	"os/signal"
 	"syscall"
 	"testing"
@@ -17,11 +18,15 @@ import (
 	"github.com/docker/cli/internal/test/notary"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/pflag"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 )
 
 func TestRunLabel(t *testing.T) {
@@ -100,6 +105,153 @@ func TestRunAttachTermination(t *testing.T) {
 	}
 }
 
+func TestRunSetupCommands(t *testing.T) {
+	testCases := []struct {
+		name           string
+		exitCode       int
+		expectedStatus string
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:           "failure",
+			exitCode:       3,
+			expectedStatus: `setup command "echo setup" failed with exit code 3`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var execCmd []string
+			fakeCLI := test.NewFakeCli(&fakeClient{
+				createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+					_, ok := config.Labels["com.docker.cli.setup-cmd"]
+					assert.Check(t, !ok, "setup commands must not be recorded without --setup-cmd-on-start")
+					return container.CreateResponse{ID: "id"}, nil
+				},
+				execCreateFunc: func(containerID string, options container.ExecOptions) (types.IDResponse, error) {
+					assert.Check(t, is.Equal(containerID, "id"))
+					execCmd = options.Cmd
+					return types.IDResponse{ID: "exec-id"}, nil
+				},
+				containerExecAttachFunc: func(context.Context, string, container.ExecAttachOptions) (types.HijackedResponse, error) {
+					server, client := net.Pipe()
+					go func() {
+						_, _ = stdcopy.NewStdWriter(server, stdcopy.Stdout).Write([]byte("setup\n"))
+						_ = server.Close()
+					}()
+					return types.NewHijackedResponse(client, types.MediaTypeMultiplexedStream), nil
+				},
+				execInspectFunc: func(string) (container.ExecInspect, error) {
+					return container.ExecInspect{ExitCode: tc.exitCode}, nil
+				},
+				Version: "1.36",
+			})
+			cmd := NewRunCommand(fakeCLI)
+			cmd.SetArgs([]string{"--detach", "--setup-cmd", "echo setup", "busybox"})
+			err := cmd.Execute()
+			if tc.expectedStatus != "" {
+				assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: tc.exitCode, Status: tc.expectedStatus}))
+			} else {
+				assert.Check(t, err)
+			}
+			assert.Check(t, is.DeepEqual(execCmd, []string{"/bin/sh", "-c", "echo setup"}))
+			assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), "id\nsetup\n"))
+		})
+	}
+}
+
+func TestRunSetupCommandsOnStart(t *testing.T) {
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+			assert.Check(t, is.Equal(config.Labels["com.docker.cli.setup-cmd"], `["echo setup"]`))
+			return container.CreateResponse{ID: "id"}, nil
+		},
+		Version: "1.36",
+	})
+	cmd := NewCreateCommand(fakeCLI)
+	cmd.SetArgs([]string{"--setup-cmd", "echo setup", "--setup-cmd-on-start", "busybox"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+}
+
+func TestCreateSetupCommandsRecorded(t *testing.T) {
+	var labels map[string]string
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+			labels = config.Labels
+			return container.CreateResponse{ID: "id"}, nil
+		},
+		Version: "1.36",
+	})
+	cmd := NewCreateCommand(fakeCLI)
+	cmd.SetArgs([]string{"--setup-cmd", "echo setup", "busybox"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(labels["com.docker.cli.setup-cmd"], `["echo setup"]`))
+}
+
+func TestSetupCommandsFromLabels(t *testing.T) {
+	cmds, err := setupCommandsFromLabels(map[string]string{"com.docker.cli.setup-cmd": `["one","two"]`})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(cmds, []string{"one", "two"}))
+
+	cmds, err = setupCommandsFromLabels(map[string]string{})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(cmds, 0))
+
+	_, err = setupCommandsFromLabels(map[string]string{"com.docker.cli.setup-cmd": "not-json"})
+	assert.Check(t, is.ErrorContains(err, "invalid com.docker.cli.setup-cmd label"))
+}
+
+func TestRunNotifyEvents(t *testing.T) {
+	testCases := []struct {
+		doc          string
+		args         []string
+		notifyEvents bool
+		expected     bool
+	}{
+		{doc: "default"},
+		{doc: "flag", args: []string{"--notify-events"}, expected: true},
+		{doc: "config without terminal", notifyEvents: true},
+		{doc: "flag overrides config", args: []string{"--notify-events=false"}, notifyEvents: true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			var subscribed bool
+			fakeCLI := test.NewFakeCli(&fakeClient{
+				createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, *specs.Platform, string) (container.CreateResponse, error) {
+					return container.CreateResponse{ID: "id"}, nil
+				},
+				containerAttachFunc: func(context.Context, string, container.AttachOptions) (types.HijackedResponse, error) {
+					server, client := net.Pipe()
+					_ = server.Close()
+					return types.NewHijackedResponse(client, types.MediaTypeMultiplexedStream), nil
+				},
+				waitFunc: func(string) (<-chan container.WaitResponse, <-chan error) {
+					resC := make(chan container.WaitResponse, 1)
+					resC <- container.WaitResponse{}
+					return resC, make(chan error)
+				},
+				eventsFunc: func(context.Context, events.ListOptions) (<-chan events.Message, <-chan error) {
+					subscribed = true
+					errs := make(chan error, 1)
+					errs <- io.EOF
+					return make(chan events.Message), errs
+				},
+				Version: "1.36",
+			})
+			fakeCLI.ConfigFile().NotifyEvents = tc.notifyEvents
+			cmd := NewRunCommand(fakeCLI)
+			cmd.SetArgs(append(tc.args, "busybox"))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(subscribed, tc.expected))
+		})
+	}
+}
+
 func TestRunCommandWithContentTrustErrors(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -180,3 +332,64 @@ func TestRunContainerImagePullPolicyInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestRunLoadDotenv(t *testing.T) {
+	dir := fs.NewDir(t, "dotenv", fs.WithFile(".env", "FOO=bar\nBAZ=qux\n"))
+	defer dir.Remove()
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	assert.NilError(t, os.Chdir(dir.Path()))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	var env []string
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(config *container.Config, _ *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+			env = config.Env
+			return container.CreateResponse{ID: "id"}, nil
+		},
+		Version: "1.36",
+	})
+
+	cmd := NewRunCommand(fakeCLI)
+	cmd.SetArgs([]string{"--detach", "--load-dotenv", "--env", "BAZ=override", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(env, []string{"FOO=bar", "BAZ=override"}))
+	assert.Check(t, is.Contains(fakeCLI.ErrBuffer().String(), "Loaded 2 variables from .env: FOO, BAZ\n"))
+
+	// The .env file is loaded if it's enabled in the configuration file,
+	// unless it's disabled with the option.
+	fakeCLI.ConfigFile().LoadDotenv = true
+	cmd = NewRunCommand(fakeCLI)
+	cmd.SetArgs([]string{"--detach", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(env, []string{"FOO=bar", "BAZ=qux"}))
+
+	cmd = NewRunCommand(fakeCLI)
+	cmd.SetArgs([]string{"--detach", "--load-dotenv=false", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(env, 0))
+}
+
+func TestRunPublishAuto(t *testing.T) {
+	var portBindings nat.PortMap
+	fakeCLI := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(_ *container.Config, hostConfig *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+			portBindings = hostConfig.PortBindings
+			return container.CreateResponse{ID: "id"}, nil
+		},
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			ci := types.ContainerJSON{NetworkSettings: &types.NetworkSettings{}}
+			ci.NetworkSettings.Ports = nat.PortMap{
+				"80/tcp": {{HostIP: "0.0.0.0", HostPort: "49153"}, {HostIP: "::", HostPort: "49153"}},
+			}
+			return ci, nil
+		},
+		Version: "1.36",
+	})
+
+	cmd := NewRunCommand(fakeCLI)
+	cmd.SetArgs([]string{"--detach", "-p", "auto:80", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(portBindings, nat.PortMap{"80/tcp": {{HostPort: ""}}}))
+	assert.Check(t, is.Equal(fakeCLI.ErrBuffer().String(), "80/tcp -> 0.0.0.0:49153\n80/tcp -> [::]:49153\n"))
+}
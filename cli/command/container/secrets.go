@@ -0,0 +1,123 @@
+package container
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/pkg/errors"
+)
+
+// secretsDir is the directory of the secrets of a container, as for the
+// secrets of services.
+const secretsDir = "/run/secrets"
+
+// stagedSecret is a secret that is copied to a container once it's created.
+type stagedSecret struct {
+	target  string
+	content []byte
+}
+
+// prepareSecrets adds a read-only bind mount to hostConfig for each secret
+// that is read from a file, if bindFiles is set, and returns the secrets that
+// must be copied to the container instead. Files can only be bind-mounted if
+// the daemon runs on the same host as the CLI.
+func prepareSecrets(secrets []opts.RunSecret, hostConfig *container.HostConfig, bindFiles bool) ([]stagedSecret, error) {
+	var staged []stagedSecret
+	for _, s := range secrets {
+		target := s.Target
+		if !path.IsAbs(target) {
+			target = path.Join(secretsDir, target)
+		}
+		switch {
+		case s.Env != "":
+			v, ok := os.LookupEnv(s.Env)
+			if !ok {
+				return nil, errors.Errorf("secret %s: environment variable %s is not set", s.ID, s.Env)
+			}
+			staged = append(staged, stagedSecret{target: target, content: []byte(v)})
+		case bindFiles:
+			source, err := filepath.Abs(s.Source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "secret %s", s.ID)
+			}
+			if _, err := os.Stat(source); err != nil {
+				return nil, errors.Wrapf(err, "secret %s", s.ID)
+			}
+			hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   source,
+				Target:   target,
+				ReadOnly: true,
+			})
+		default:
+			content, err := os.ReadFile(s.Source)
+			if err != nil {
+				return nil, errors.Wrapf(err, "secret %s", s.ID)
+			}
+			staged = append(staged, stagedSecret{target: target, content: content})
+		}
+	}
+	return staged, nil
+}
+
+// copySecrets copies the staged secrets to the container with the given ID,
+// before it's started.
+func copySecrets(ctx context.Context, dockerCli command.Cli, containerID string, staged []stagedSecret) error {
+	if len(staged) == 0 {
+		return nil
+	}
+	archive, err := secretsArchive(staged)
+	if err != nil {
+		return err
+	}
+	err = dockerCli.Client().CopyToContainer(ctx, containerID, "/", archive, container.CopyToContainerOptions{})
+	return errors.Wrap(err, "failed to copy secrets to the container")
+}
+
+// secretsArchive returns an archive of the staged secrets, relative to the
+// root of the container, with the directories that contain them.
+func secretsArchive(staged []stagedSecret) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	dirs := map[string]bool{}
+	for _, s := range staged {
+		var parents []string
+		for dir := path.Dir(s.target); dir != "/" && !dirs[dir]; dir = path.Dir(dir) {
+			parents = append([]string{dir}, parents...)
+			dirs[dir] = true
+		}
+		for _, dir := range parents {
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     strings.TrimPrefix(dir, "/") + "/",
+				Mode:     0o755,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(s.target, "/"),
+			Mode:     0o444,
+			Size:     int64(len(s.content)),
+		}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(s.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,110 @@
+package container
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/google/go-cmp/cmp"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPrepareSecrets(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "key.txt")
+	assert.NilError(t, os.WriteFile(secretFile, []byte("key"), 0o600))
+	t.Setenv("API_TOKEN", "s3cr3t")
+	secrets := []opts.RunSecret{
+		{ID: "key", Source: secretFile, Target: "key"},
+		{ID: "token", Env: "API_TOKEN", Target: "/etc/app/token"},
+	}
+
+	t.Run("local", func(t *testing.T) {
+		hostConfig := &container.HostConfig{}
+		staged, err := prepareSecrets(secrets, hostConfig, true)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(hostConfig.Mounts, []mount.Mount{
+			{Type: mount.TypeBind, Source: secretFile, Target: "/run/secrets/key", ReadOnly: true},
+		}))
+		assert.Check(t, is.DeepEqual(staged, []stagedSecret{
+			{target: "/etc/app/token", content: []byte("s3cr3t")},
+		}, cmp.AllowUnexported(stagedSecret{})))
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		hostConfig := &container.HostConfig{}
+		staged, err := prepareSecrets(secrets, hostConfig, false)
+		assert.NilError(t, err)
+		assert.Check(t, is.Len(hostConfig.Mounts, 0))
+		assert.Check(t, is.DeepEqual(staged, []stagedSecret{
+			{target: "/run/secrets/key", content: []byte("key")},
+			{target: "/etc/app/token", content: []byte("s3cr3t")},
+		}, cmp.AllowUnexported(stagedSecret{})))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := prepareSecrets([]opts.RunSecret{{ID: "key", Env: "NOT_SET_ANYWHERE"}}, &container.HostConfig{}, false)
+		assert.Check(t, is.Error(err, "secret key: environment variable NOT_SET_ANYWHERE is not set"))
+		_, err = prepareSecrets([]opts.RunSecret{{ID: "key", Source: "does-not-exist"}}, &container.HostConfig{}, true)
+		assert.Check(t, is.ErrorContains(err, "secret key: stat "))
+	})
+}
+
+func TestCreateContainerCopiesSecrets(t *testing.T) {
+	t.Setenv("API_TOKEN", "s3cr3t")
+	var (
+		dstPath string
+		entries []string
+	)
+	fakeCli := test.NewFakeCli(&fakeClient{
+		createContainerFunc: func(*container.Config, *container.HostConfig, *network.NetworkingConfig, *specs.Platform, string) (container.CreateResponse, error) {
+			return container.CreateResponse{ID: "abcdef"}, nil
+		},
+		copyToContainerFunc: func(containerID, path string, content io.Reader) error {
+			dstPath = path
+			tr := tar.NewReader(content)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				b, err := io.ReadAll(tr)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, strings.TrimSpace(hdr.Name+" "+hdr.FileInfo().Mode().String()+" "+string(b)))
+			}
+		},
+	})
+	id, err := createContainer(context.Background(), fakeCli, &containerConfig{
+		Config:     &container.Config{Image: "busybox"},
+		HostConfig: &container.HostConfig{},
+		secrets: []opts.RunSecret{
+			{ID: "token", Env: "API_TOKEN", Target: "token"},
+			{ID: "db", Env: "API_TOKEN", Target: "db/password"},
+		},
+	}, &createOptions{untrusted: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(id, "abcdef"))
+	assert.Check(t, is.Equal(dstPath, "/"))
+	assert.Check(t, is.DeepEqual(entries, []string{
+		"run/ drwxr-xr-x",
+		"run/secrets/ drwxr-xr-x",
+		"run/secrets/token -r--r--r-- s3cr3t",
+		"run/secrets/db/ drwxr-xr-x",
+		"run/secrets/db/password -r--r--r-- s3cr3t",
+	}))
+}
@@ -0,0 +1,167 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// setupCmdLabel is the label on which the commands passed through "--setup-cmd"
+// are recorded by "docker create", or by "docker run" if "--setup-cmd-on-start"
+// is set, so that they are executed each time the container is started with
+// "docker start".
+const setupCmdLabel = "com.docker.cli.setup-cmd"
+
+// setupCommandsFromLabels returns the setup commands recorded on a container.
+func setupCommandsFromLabels(labels map[string]string) ([]string, error) {
+	v, ok := labels[setupCmdLabel]
+	if !ok || v == "" {
+		return nil, nil
+	}
+	var cmds []string
+	if err := json.Unmarshal([]byte(v), &cmds); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s label", setupCmdLabel)
+	}
+	return cmds, nil
+}
+
+// recordedSetupCommands returns the setup commands that are recorded on the
+// given containers, keyed by the name or ID through which they were passed.
+// Containers without setup commands are omitted.
+func recordedSetupCommands(ctx context.Context, apiClient client.ContainerAPIClient, containers []string) (map[string][]string, error) {
+	// Look up all containers with setup commands at once, instead of
+	// inspecting each container, as most containers don't have any.
+	list, err := apiClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", setupCmdLabel)),
+	})
+	if err != nil {
+		// Listing containers may not be allowed, such as by an
+		// authorization plugin, so inspect the given containers instead.
+		logrus.Debugf("Failed to list containers with setup commands, inspecting them instead: %v", err)
+		return inspectSetupCommands(ctx, apiClient, containers)
+	}
+	recorded := make(map[string][]string)
+	for _, ctr := range containers {
+		c, ok := findContainer(list, ctr)
+		if !ok {
+			continue
+		}
+		cmds, err := setupCommandsFromLabels(c.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if len(cmds) > 0 {
+			recorded[ctr] = cmds
+		}
+	}
+	return recorded, nil
+}
+
+// inspectSetupCommands returns the setup commands that are recorded on the
+// given containers by inspecting each of them. Containers that can't be
+// inspected are omitted, and fail to start instead.
+func inspectSetupCommands(ctx context.Context, apiClient client.ContainerAPIClient, containers []string) (map[string][]string, error) {
+	recorded := make(map[string][]string)
+	for _, ctr := range containers {
+		c, err := apiClient.ContainerInspect(ctx, ctr)
+		if err != nil || c.Config == nil {
+			continue
+		}
+		cmds, err := setupCommandsFromLabels(c.Config.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if len(cmds) > 0 {
+			recorded[ctr] = cmds
+		}
+	}
+	return recorded, nil
+}
+
+// findContainer returns the container of list that ref refers to, as the
+// daemon resolves it: by full ID, by name, and then by unique ID prefix.
+func findContainer(list []types.Container, ref string) (types.Container, bool) {
+	for _, c := range list {
+		if c.ID == ref {
+			return c, true
+		}
+	}
+	for _, c := range list {
+		for _, name := range c.Names {
+			if strings.TrimPrefix(name, "/") == ref {
+				return c, true
+			}
+		}
+	}
+	var (
+		found   types.Container
+		matches int
+	)
+	for _, c := range list {
+		if strings.HasPrefix(c.ID, ref) {
+			found = c
+			matches++
+		}
+	}
+	if matches != 1 {
+		return types.Container{}, false
+	}
+	return found, true
+}
+
+// runRecordedSetupCommands executes the setup commands that were recorded on
+// the container when it was created.
+func runRecordedSetupCommands(ctx context.Context, dockerCli command.Cli, c types.ContainerJSON) error {
+	if c.Config == nil || c.ContainerJSONBase == nil {
+		return nil
+	}
+	cmds, err := setupCommandsFromLabels(c.Config.Labels)
+	if err != nil || len(cmds) == 0 {
+		return err
+	}
+	return runSetupCommands(ctx, dockerCli, c.ID, c.Platform, cmds)
+}
+
+// runSetupCommands executes the given commands in the container, one by one,
+// and streams their output. It returns a cli.StatusError with the exit code
+// of the first command that fails, in which case the remaining commands are
+// not executed.
+func runSetupCommands(ctx context.Context, dockerCli command.Cli, containerID string, osType string, cmds []string) error {
+	shell := []string{"/bin/sh", "-c"}
+	if osType == "windows" {
+		shell = []string{"cmd", "/S", "/C"}
+	}
+	for _, cmd := range cmds {
+		execOptions := &container.ExecOptions{
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          append(shell[:len(shell):len(shell)], cmd),
+		}
+		response, err := dockerCli.Client().ContainerExecCreate(ctx, containerID, *execOptions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to run setup command %q", cmd)
+		}
+		if err := interactiveExec(ctx, dockerCli, execOptions, response.ID); err != nil {
+			var sErr cli.StatusError
+			if errors.As(err, &sErr) {
+				return cli.StatusError{
+					StatusCode: sErr.StatusCode,
+					Status:     fmt.Sprintf("setup command %q failed with exit code %d", cmd, sErr.StatusCode),
+				}
+			}
+			return errors.Wrapf(err, "failed to run setup command %q", cmd)
+		}
+	}
+	return nil
+}
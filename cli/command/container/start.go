@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli"
@@ -11,6 +12,7 @@ import (
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/moby/sys/signal"
 	"github.com/moby/term"
 	"github.com/pkg/errors"
@@ -24,6 +26,7 @@ type StartOptions struct {
 	DetachKeys    string
 	Checkpoint    string
 	CheckpointDir string
+	PreviousLogs  int
 
 	Containers []string
 }
@@ -52,6 +55,7 @@ func NewStartCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.Attach, "attach", "a", false, "Attach STDOUT/STDERR and forward signals")
 	flags.BoolVarP(&opts.OpenStdin, "interactive", "i", false, "Attach container's STDIN")
 	flags.StringVar(&opts.DetachKeys, "detach-keys", "", "Override the key sequence for detaching a container")
+	flags.IntVar(&opts.PreviousLogs, "previous-logs", 10, "Number of log lines of the previous run to print when attaching to a container that failed (0 to disable)")
 
 	flags.StringVar(&opts.Checkpoint, "checkpoint", "", "Restore from this checkpoint")
 	flags.SetAnnotation("checkpoint", "experimental", nil)
@@ -105,6 +109,12 @@ func RunStart(ctx context.Context, dockerCli command.Cli, opts *StartOptions) er
 			DetachKeys: detachKeys,
 		}
 
+		// Print the end of the logs of the previous run if it failed, before
+		// the output of the new run, so that it's clear why it failed.
+		if opts.PreviousLogs > 0 && previousRunFailed(c) {
+			printPreviousLogs(ctx, dockerCli, c, opts.PreviousLogs)
+		}
+
 		var in io.ReadCloser
 
 		if options.Stdin {
@@ -164,6 +174,18 @@ func RunStart(ctx context.Context, dockerCli command.Cli, opts *StartOptions) er
 				fmt.Fprintln(dockerCli.Err(), "Error monitoring TTY size:", err)
 			}
 		}
+
+		// 6. Run the setup commands that were recorded on the container.
+		var setupStatus int
+		if err := runRecordedSetupCommands(ctx, dockerCli, c); err != nil {
+			setupStatus = 125
+			var sErr cli.StatusError
+			if errors.As(err, &sErr) {
+				setupStatus = sErr.StatusCode
+				err = errors.New(sErr.Status)
+			}
+			fmt.Fprintln(dockerCli.Err(), err)
+		}
 		if attachErr := <-cErr; attachErr != nil {
 			if _, ok := attachErr.(term.EscapeError); ok {
 				// The user entered the detach escape sequence.
@@ -172,7 +194,11 @@ func RunStart(ctx context.Context, dockerCli command.Cli, opts *StartOptions) er
 			return attachErr
 		}
 
-		if status := <-statusChan; status != 0 {
+		status := <-statusChan
+		if status == 0 {
+			status = setupStatus
+		}
+		if status != 0 {
 			return cli.StatusError{StatusCode: status}
 		}
 		return nil
@@ -192,8 +218,48 @@ func RunStart(ctx context.Context, dockerCli command.Cli, opts *StartOptions) er
 	}
 }
 
+// previousRunFailed returns whether the previous run of the container
+// exited with a non-zero status, or if it's being restarted by its
+// restart policy.
+func previousRunFailed(c types.ContainerJSON) bool {
+	if c.State == nil {
+		return false
+	}
+	return c.State.Restarting || (c.State.ExitCode != 0 && !c.State.Running)
+}
+
+// printPreviousLogs prints the last lines of the logs of the container,
+// followed by a separator. Errors are ignored, as not all logging drivers
+// support reading logs, and the logs are only informational.
+func printPreviousLogs(ctx context.Context, dockerCli command.Cli, c types.ContainerJSON, lines int) {
+	responseBody, err := dockerCli.Client().ContainerLogs(ctx, c.ID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(lines),
+	})
+	if err != nil {
+		return
+	}
+	defer responseBody.Close()
+
+	if c.Config.Tty {
+		_, err = io.Copy(dockerCli.Out(), responseBody)
+	} else {
+		_, err = stdcopy.StdCopy(dockerCli.Out(), dockerCli.Err(), responseBody)
+	}
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(dockerCli.Err(), "--- previous run exited with code %d; output of the new run follows ---\n", c.State.ExitCode)
+}
+
 func startContainersWithoutAttachments(ctx context.Context, dockerCli command.Cli, containers []string) error {
-	var failedContainers []string
+	setupCmds, err := recordedSetupCommands(ctx, dockerCli.Client(), containers)
+	if err != nil {
+		return err
+	}
+
+	var failedContainers, failedSetup []string
 	for _, ctr := range containers {
 		if err := dockerCli.Client().ContainerStart(ctx, ctr, container.StartOptions{}); err != nil {
 			fmt.Fprintln(dockerCli.Err(), err)
@@ -201,10 +267,28 @@ func startContainersWithoutAttachments(ctx context.Context, dockerCli command.Cl
 			continue
 		}
 		fmt.Fprintln(dockerCli.Out(), ctr)
+
+		cmds, ok := setupCmds[ctr]
+		if !ok {
+			continue
+		}
+		if err := runSetupCommands(ctx, dockerCli, ctr, dockerCli.ServerInfo().OSType, cmds); err != nil {
+			var sErr cli.StatusError
+			if errors.As(err, &sErr) {
+				err = errors.New(sErr.Status)
+			}
+			fmt.Fprintln(dockerCli.Err(), err)
+			failedSetup = append(failedSetup, ctr)
+		}
 	}
 
-	if len(failedContainers) > 0 {
+	switch {
+	case len(failedContainers) > 0 && len(failedSetup) > 0:
+		return errors.Errorf("Error: failed to start containers: %s; setup commands failed in containers: %s", strings.Join(failedContainers, ", "), strings.Join(failedSetup, ", "))
+	case len(failedContainers) > 0:
 		return errors.Errorf("Error: failed to start containers: %s", strings.Join(failedContainers, ", "))
+	case len(failedSetup) > 0:
+		return errors.Errorf("Error: setup commands failed in containers: %s", strings.Join(failedSetup, ", "))
 	}
 	return nil
 }
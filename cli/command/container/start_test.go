@@ -0,0 +1,138 @@
+package container
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPreviousRunFailed(t *testing.T) {
+	testCases := []struct {
+		doc      string
+		state    *types.ContainerState
+		expected bool
+	}{
+		{doc: "created", state: &types.ContainerState{Status: "created"}},
+		{doc: "exited", state: &types.ContainerState{Status: "exited"}},
+		{doc: "failed", state: &types.ContainerState{Status: "exited", ExitCode: 1}, expected: true},
+		{doc: "restarting", state: &types.ContainerState{Status: "restarting", Running: true, Restarting: true, ExitCode: 1}, expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			c := types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{State: tc.state}}
+			assert.Check(t, is.Equal(previousRunFailed(c), tc.expected))
+		})
+	}
+}
+
+func TestPrintPreviousLogs(t *testing.T) {
+	c := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{ID: "id", State: &types.ContainerState{ExitCode: 2}},
+		Config:            &container.Config{Tty: true},
+	}
+
+	t.Run("logs", func(t *testing.T) {
+		cli := test.NewFakeCli(&fakeClient{
+			logFunc: func(containerID string, options container.LogsOptions) (io.ReadCloser, error) {
+				assert.Check(t, is.Equal(containerID, "id"))
+				assert.Check(t, is.Equal(options.Tail, "5"))
+				return logFn("connection refused\n")(containerID, options)
+			},
+		})
+		printPreviousLogs(context.TODO(), cli, c, 5)
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "connection refused\n"))
+		assert.Check(t, is.Equal(cli.ErrBuffer().String(), "--- previous run exited with code 2; output of the new run follows ---\n"))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		cli := test.NewFakeCli(&fakeClient{
+			logFunc: func(string, container.LogsOptions) (io.ReadCloser, error) {
+				return nil, errors.New(`configured logging driver does not support reading`)
+			},
+		})
+		printPreviousLogs(context.TODO(), cli, c, 5)
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+		assert.Check(t, is.Equal(cli.ErrBuffer().String(), ""))
+	})
+}
+
+func TestStartRecordedSetupCommands(t *testing.T) {
+	var execContainers []string
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+			assert.Check(t, is.Equal(options.Filters.Get("label")[0], "com.docker.cli.setup-cmd"))
+			return []types.Container{
+				{ID: "abc123", Names: []string{"/db"}, Labels: map[string]string{"com.docker.cli.setup-cmd": `["exit 3"]`}},
+			}, nil
+		},
+		inspectFunc: func(string) (types.ContainerJSON, error) {
+			t.Fatal("containers must not be inspected")
+			return types.ContainerJSON{}, nil
+		},
+		execCreateFunc: func(containerID string, _ container.ExecOptions) (types.IDResponse, error) {
+			execContainers = append(execContainers, containerID)
+			return types.IDResponse{ID: "exec-id"}, nil
+		},
+		containerExecAttachFunc: func(context.Context, string, container.ExecAttachOptions) (types.HijackedResponse, error) {
+			server, client := net.Pipe()
+			_ = server.Close()
+			return types.NewHijackedResponse(client, types.MediaTypeMultiplexedStream), nil
+		},
+		execInspectFunc: func(string) (container.ExecInspect, error) {
+			return container.ExecInspect{ExitCode: 3}, nil
+		},
+	})
+	err := RunStart(context.TODO(), cli, &StartOptions{Containers: []string{"db", "web"}})
+	assert.Check(t, is.Error(err, "Error: setup commands failed in containers: db"))
+	assert.Check(t, is.DeepEqual(execContainers, []string{"db"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db\nweb\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "setup command \"exit 3\" failed with exit code 3\n"))
+}
+
+func TestStartSetupCommandsListError(t *testing.T) {
+	var inspected []string
+	cli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(container.ListOptions) ([]types.Container, error) {
+			return nil, errors.New("listing containers is not allowed")
+		},
+		inspectFunc: func(ctr string) (types.ContainerJSON, error) {
+			inspected = append(inspected, ctr)
+			return types.ContainerJSON{Config: &container.Config{}}, nil
+		},
+	})
+	err := RunStart(context.TODO(), cli, &StartOptions{Containers: []string{"db", "web"}})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(inspected, []string{"db", "web"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db\nweb\n"))
+}
+
+func TestFindContainer(t *testing.T) {
+	list := []types.Container{
+		{ID: "abc123", Names: []string{"/web"}},
+		{ID: "def456", Names: []string{"/abc"}},
+		{ID: "abd789", Names: []string{"/db"}},
+	}
+	for _, tc := range []struct {
+		ref        string
+		expectedID string
+	}{
+		{ref: "abc123", expectedID: "abc123"},
+		{ref: "web", expectedID: "abc123"},
+		{ref: "abc", expectedID: "def456"},
+		{ref: "abd", expectedID: "abd789"},
+		{ref: "ab"},
+		{ref: "unknown"},
+	} {
+		c, ok := findContainer(list, tc.ref)
+		assert.Check(t, is.Equal(ok, tc.expectedID != ""), tc.ref)
+		assert.Check(t, is.Equal(c.ID, tc.expectedID), tc.ref)
+	}
+}
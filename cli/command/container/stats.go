@@ -12,6 +12,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/watch"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
@@ -36,7 +37,7 @@ type StatsOptions struct {
 	NoTrunc bool
 
 	// Format is a custom template to use for presenting the stats.
-	// Refer to [flagsHelper.FormatHelp] for accepted formats.
+	// Refer to [flagsHelper.DelimitedFormatHelp] for accepted formats.
 	Format string
 
 	// Containers is the list of container names or IDs to include in the stats.
@@ -84,7 +85,7 @@ func NewStatsCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.All, "all", "a", false, "Show all containers (default shows just running)")
 	flags.BoolVar(&options.NoStream, "no-stream", false, "Disable streaming stats and only pull the first result")
 	flags.BoolVar(&options.NoTrunc, "no-trunc", false, "Do not truncate output")
-	flags.StringVar(&options.Format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.Format, "format", "", flagsHelper.DelimitedFormatHelp)
 	return cmd
 }
 
@@ -163,8 +164,9 @@ func RunStats(ctx context.Context, dockerCLI command.Cli, options *StatsOptions)
 			// is not valid for filtering containers.
 			f := options.Filters.Clone()
 			f.Add("type", string(events.ContainerEventType))
-			eventChan, errChan := apiClient.Events(ctx, events.ListOptions{
-				Filters: f,
+			eventChan, errChan := watch.Events(ctx, apiClient, watch.Options{
+				Filters:     f,
+				OnReconnect: watch.PrintReconnect(dockerCLI.Err()),
 			})
 
 			// Whether we successfully subscribed to eventChan or not, we can now
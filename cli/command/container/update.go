@@ -9,6 +9,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -30,6 +31,7 @@ type updateOptions struct {
 	restartPolicy      string
 	pidsLimit          int64
 	cpus               opts.NanoCPUs
+	filter             opts.FilterOpt
 
 	nFlag int
 
@@ -38,15 +40,23 @@ type updateOptions struct {
 
 // NewUpdateCommand creates a new cobra.Command for `docker update`
 func NewUpdateCommand(dockerCli command.Cli) *cobra.Command {
-	var options updateOptions
+	options := updateOptions{filter: opts.NewFilterOpt()}
 
 	cmd := &cobra.Command{
 		Use:   "update [OPTIONS] CONTAINER [CONTAINER...]",
 		Short: "Update configuration of one or more containers",
-		Args:  cli.RequiresMinArgs(1),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("filter") {
+				return nil
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.containers = args
 			options.nFlag = cmd.Flags().NFlag()
+			if cmd.Flags().Changed("filter") {
+				options.nFlag--
+			}
 			return runUpdate(cmd.Context(), dockerCli, &options)
 		},
 		Annotations: map[string]string{
@@ -83,6 +93,8 @@ func NewUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.Var(&options.cpus, "cpus", "Number of CPUs")
 	flags.SetAnnotation("cpus", "version", []string{"1.29"})
 
+	flags.VarP(&options.filter, "filter", "f", `Also update the containers that match the filter (same filters as "docker ps")`)
+
 	return cmd
 }
 
@@ -126,11 +138,19 @@ func runUpdate(ctx context.Context, dockerCli command.Cli, options *updateOption
 		RestartPolicy: restartPolicy,
 	}
 
+	containers := options.containers
+	if options.filter.Value().Len() > 0 {
+		containers, err = filteredContainers(ctx, dockerCli, containers, options.filter)
+		if err != nil {
+			return err
+		}
+	}
+
 	var (
 		warns []string
 		errs  []string
 	)
-	for _, container := range options.containers {
+	for _, container := range containers {
 		r, err := dockerCli.Client().ContainerUpdate(ctx, container, updateConfig)
 		if err != nil {
 			errs = append(errs, err.Error())
@@ -147,3 +167,41 @@ func runUpdate(ctx context.Context, dockerCli command.Cli, options *updateOption
 	}
 	return nil
 }
+
+// filteredContainers returns containers, followed by the names of the
+// containers that match filter, whether they're running or not. It returns an
+// error if no container matches filter.
+func filteredContainers(ctx context.Context, dockerCli command.Cli, containers []string, filter opts.FilterOpt) ([]string, error) {
+	list, err := dockerCli.Client().ContainerList(ctx, containertypes.ListOptions{
+		All:     true,
+		Filters: filter.Value(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, errors.New("no containers match the filter")
+	}
+
+	return appendContainerNames(containers, list), nil
+}
+
+// appendContainerNames appends the names of the containers of list to
+// containers, skipping the containers that it already holds.
+func appendContainerNames(containers []string, list []types.Container) []string {
+	seen := make(map[string]bool, len(containers)+len(list))
+	for _, c := range containers {
+		seen[c] = true
+	}
+	for _, c := range list {
+		name := c.ID
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		if !seen[name] && !seen[c.ID] {
+			seen[name] = true
+			containers = append(containers, name)
+		}
+	}
+	return containers
+}
@@ -0,0 +1,74 @@
+package container
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestUpdateFilter(t *testing.T) {
+	var (
+		listOptions container.ListOptions
+		updated     []string
+	)
+	fakeCli := test.NewFakeCli(&fakeClient{
+		containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+			listOptions = options
+			return []types.Container{
+				{ID: "id-web", Names: []string{"/web"}},
+				{ID: "id-db", Names: []string{"/db"}},
+			}, nil
+		},
+		containerUpdateFunc: func(containerID string, updateConfig container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
+			assert.Check(t, is.Equal(updateConfig.RestartPolicy.Name, container.RestartPolicyUnlessStopped))
+			updated = append(updated, containerID)
+			return container.ContainerUpdateOKBody{}, nil
+		},
+	})
+	cmd := NewUpdateCommand(fakeCli)
+	cmd.SetArgs([]string{"--restart", "unless-stopped", "--filter", "label=env=prod", "cache", "db"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, listOptions.All)
+	assert.Check(t, is.DeepEqual(listOptions.Filters.Get("label"), []string{"env=prod"}))
+	assert.Check(t, is.DeepEqual(updated, []string{"cache", "db", "web"}))
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "cache\ndb\nweb\n"))
+}
+
+func TestUpdateFilterErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "no-match",
+			args:          []string{"--restart", "always", "--filter", "label=env=prod"},
+			expectedError: "no containers match the filter",
+		},
+		{
+			name:          "filter-only",
+			args:          []string{"--filter", "label=env=prod"},
+			expectedError: "you must provide one or more flags when using this command",
+		},
+		{
+			name:          "no-containers",
+			args:          []string{"--restart", "always"},
+			expectedError: `"update" requires at least 1 argument.`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := NewUpdateCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
@@ -24,6 +24,9 @@ func NewContextCommand(dockerCli command.Cli) *cobra.Command {
 		newUpdateCommand(dockerCli),
 		newInspectCommand(dockerCli),
 		newShowCommand(dockerCli),
+		newTestCommand(dockerCli),
+		newExecCommand(dockerCli),
+		newRulesCommand(dockerCli),
 	)
 	return cmd
 }
@@ -80,7 +80,7 @@ func RunCreate(dockerCLI command.Cli, o *CreateOptions) error {
 	}
 	if err == nil {
 		fmt.Fprintln(dockerCLI.Out(), o.Name)
-		fmt.Fprintf(dockerCLI.Err(), "Successfully created context %q\n", o.Name)
+		dockerCLI.Err().Infof("Successfully created context %q\n", o.Name)
 	}
 	return err
 }
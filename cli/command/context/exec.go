@@ -0,0 +1,105 @@
+package context
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newExecCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exec CONTEXT COMMAND [ARG...]",
+		Short: "Run a command with another context as the current context",
+		Args:  cli.RequiresMinArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, args := args[0], args[1:]
+			// Allow separating the command with "--", as in "docker context
+			// exec CONTEXT -- COMMAND"; it's only removed by the flag parser
+			// if it's before the context.
+			if args[0] == "--" {
+				args = args[1:]
+			}
+			if len(args) == 0 {
+				return errors.New("no command to run")
+			}
+			return runExec(cmd.Context(), dockerCli, name, args)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.ContextNames(dockerCli)(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
+	}
+	// Flags after the context are flags of the command.
+	cmd.Flags().SetInterspersed(false)
+	return cmd
+}
+
+// runExec runs a command with the environment set to use the given context,
+// without changing the current context in the configuration file.
+func runExec(_ context.Context, dockerCli command.Cli, name string, args []string) error {
+	if name != command.DefaultContextName {
+		if err := store.ValidateContextName(name); err != nil {
+			return err
+		}
+		if _, err := dockerCli.ContextStore().GetMetadata(name); err != nil {
+			return err
+		}
+	}
+
+	// The command isn't cancelled through the context: the signals of the
+	// terminal are sent to it as well, and it handles them itself.
+	c := exec.Command(args[0], args[1:]...)
+	c.Env = execEnv(os.Environ(), name, config.Dir())
+	c.Stdin = dockerCli.In()
+	c.Stdout = dockerCli.Out()
+	c.Stderr = dockerCli.Err()
+	if err := c.Run(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return err
+		}
+		statusCode := 1
+		if ws, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			statusCode = ws.ExitStatus()
+		}
+		return cli.StatusError{StatusCode: statusCode}
+	}
+	return nil
+}
+
+// execEnv returns the environment of a command that is run with the given
+// context. DOCKER_HOST is unset for contexts other than "default", as it
+// overrides DOCKER_CONTEXT, and DOCKER_CONFIG is set to the configuration
+// directory that is used, so that it's also used if it's set with --config.
+func execEnv(environ []string, name, configDir string) []string {
+	env := make([]string, 0, len(environ)+2)
+	for _, kv := range environ {
+		k, _, _ := strings.Cut(kv, "=")
+		switch k {
+		case command.EnvOverrideContext, config.EnvOverrideConfigDir:
+			continue
+		case client.EnvOverrideHost:
+			if name != command.DefaultContextName {
+				continue
+			}
+		}
+		env = append(env, kv)
+	}
+	return append(env,
+		command.EnvOverrideContext+"="+name,
+		config.EnvOverrideConfigDir+"="+configDir,
+	)
+}
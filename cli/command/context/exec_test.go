@@ -0,0 +1,40 @@
+package context
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestExecEnv(t *testing.T) {
+	environ := []string{"PATH=/usr/bin", "DOCKER_HOST=tcp://example.com:2376", "DOCKER_CONTEXT=other", "DOCKER_CONFIG=/old"}
+
+	env := execEnv(environ, "test", "/config")
+	assert.Check(t, is.DeepEqual(env, []string{"PATH=/usr/bin", "DOCKER_CONTEXT=test", "DOCKER_CONFIG=/config"}))
+
+	env = execEnv(environ, command.DefaultContextName, "/config")
+	assert.Check(t, is.DeepEqual(env, []string{"PATH=/usr/bin", "DOCKER_HOST=tcp://example.com:2376", "DOCKER_CONTEXT=default", "DOCKER_CONFIG=/config"}))
+}
+
+func TestExecErrors(t *testing.T) {
+	fakeCli := makeFakeCli(t)
+	cmd := newExecCommand(fakeCli)
+	cmd.SetArgs([]string{"missing", "docker", "ps"})
+	cmd.SetOut(fakeCli.OutBuffer())
+	cmd.SetErr(fakeCli.ErrBuffer())
+	assert.ErrorContains(t, cmd.Execute(), `context "missing": context not found`)
+
+	cmd = newExecCommand(fakeCli)
+	cmd.SetArgs([]string{"missing"})
+	cmd.SetOut(fakeCli.OutBuffer())
+	cmd.SetErr(fakeCli.ErrBuffer())
+	assert.ErrorContains(t, cmd.Execute(), "requires at least 2 arguments")
+
+	cmd = newExecCommand(fakeCli)
+	cmd.SetArgs([]string{"missing", "--"})
+	cmd.SetOut(fakeCli.OutBuffer())
+	cmd.SetErr(fakeCli.ErrBuffer())
+	assert.ErrorContains(t, cmd.Execute(), "no command to run")
+}
@@ -1,14 +1,20 @@
 package context
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/moby/term"
 	"github.com/spf13/cobra"
 )
 
@@ -16,17 +22,23 @@ import (
 type ExportOptions struct {
 	ContextName string
 	Dest        string
+	// Redact removes the TLS material, and the password of the proxy of the
+	// Docker endpoint, from the exported context.
+	Redact bool
+	// Encrypt encrypts the TLS material with a passphrase, which is read
+	// from stdin if PassphraseStdin is set, or prompted for otherwise.
+	Encrypt         bool
+	PassphraseStdin bool
 }
 
 func newExportCommand(dockerCli command.Cli) *cobra.Command {
-	return &cobra.Command{
+	opts := &ExportOptions{}
+	cmd := &cobra.Command{
 		Use:   "export [OPTIONS] CONTEXT [FILE|-]",
 		Short: "Export a context to a tar archive FILE or a tar stream on STDOUT.",
 		Args:  cli.RequiresRangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			opts := &ExportOptions{
-				ContextName: args[0],
-			}
+			opts.ContextName = args[0]
 			if len(args) == 2 {
 				opts.Dest = args[1]
 			} else {
@@ -34,7 +46,19 @@ func newExportCommand(dockerCli command.Cli) *cobra.Command {
 			}
 			return RunExport(dockerCli, opts)
 		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.ContextNames(dockerCli)(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveDefault
+		},
 	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.Redact, "redact", false, "Do not export the TLS material, and the password of the proxy")
+	flags.BoolVar(&opts.Encrypt, "encrypt", false, "Encrypt the TLS material with a passphrase")
+	flags.BoolVar(&opts.PassphraseStdin, "passphrase-stdin", false, "Take the passphrase to encrypt the TLS material from stdin")
+	return cmd
 }
 
 func writeTo(dockerCli command.Cli, reader io.Reader, dest string) error {
@@ -58,7 +82,7 @@ func writeTo(dockerCli command.Cli, reader io.Reader, dest string) error {
 		return err
 	}
 	if printDest {
-		fmt.Fprintf(dockerCli.Err(), "Written file %q\n", dest)
+		dockerCli.Err().Infof("Written file %q\n", dest)
 	}
 	return nil
 }
@@ -68,7 +92,105 @@ func RunExport(dockerCli command.Cli, opts *ExportOptions) error {
 	if err := store.ValidateContextName(opts.ContextName); err != nil && opts.ContextName != command.DefaultContextName {
 		return err
 	}
-	reader := store.Export(opts.ContextName, dockerCli.ContextStore())
+	encrypt := opts.Encrypt || opts.PassphraseStdin
+	if opts.Redact && encrypt {
+		return errors.New("conflicting options: a redacted context has no TLS material to encrypt")
+	}
+
+	var s store.Reader = dockerCli.ContextStore()
+	if opts.Redact {
+		s = &redactDecorator{Reader: s}
+	}
+	var exportOpts store.ExportOptions
+	if encrypt {
+		passphrase, err := readPassphrase(dockerCli, opts.PassphraseStdin, true)
+		if err != nil {
+			return err
+		}
+		exportOpts.Passphrase = passphrase
+	}
+	reader := store.ExportWithOptions(opts.ContextName, s, exportOpts)
 	defer reader.Close()
 	return writeTo(dockerCli, reader, opts.Dest)
 }
+
+// redactDecorator removes the TLS material of a context, and the password of
+// the proxy of its Docker endpoint.
+type redactDecorator struct {
+	store.Reader
+}
+
+func (d *redactDecorator) GetMetadata(name string) (store.Metadata, error) {
+	c, err := d.Reader.GetMetadata(name)
+	if err != nil {
+		return c, err
+	}
+	if ep, ok := c.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta); ok && ep.Proxy != "" {
+		if u, err := url.Parse(ep.Proxy); err == nil && u.User != nil {
+			u.User = url.User(u.User.Username())
+			ep.Proxy = u.String()
+			c.Endpoints[docker.DockerEndpoint] = ep
+		}
+	}
+	return c, nil
+}
+
+func (*redactDecorator) ListTLSFiles(string) (map[string]store.EndpointFiles, error) {
+	return nil, nil
+}
+
+// readPassphrase reads the passphrase of the TLS material of a context from
+// stdin, or prompts for it; to be typed twice if confirm is set.
+func readPassphrase(dockerCli command.Cli, fromStdin, confirm bool) ([]byte, error) {
+	if fromStdin {
+		contents, err := io.ReadAll(dockerCli.In())
+		if err != nil {
+			return nil, err
+		}
+		passphrase := strings.TrimSuffix(strings.TrimSuffix(string(contents), "\n"), "\r")
+		if passphrase == "" {
+			return nil, errors.New("the passphrase is empty")
+		}
+		return []byte(passphrase), nil
+	}
+
+	if !dockerCli.In().IsTerminal() {
+		return nil, errors.New("cannot prompt for a passphrase from a non-TTY device: use --passphrase-stdin")
+	}
+	oldState, err := term.SaveState(dockerCli.In().FD())
+	if err != nil {
+		return nil, err
+	}
+	_ = term.DisableEcho(dockerCli.In().FD(), oldState)
+	defer func() {
+		_ = term.RestoreTerminal(dockerCli.In().FD(), oldState)
+	}()
+
+	in := bufio.NewReader(dockerCli.In())
+	prompt := func(message string) (string, error) {
+		_, _ = fmt.Fprint(dockerCli.Err(), message)
+		line, err := in.ReadString('\n')
+		_, _ = fmt.Fprintln(dockerCli.Err())
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	passphrase, err := prompt("Passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, errors.New("the passphrase is empty")
+	}
+	if confirm {
+		again, err := prompt("Confirm passphrase: ")
+		if err != nil {
+			return nil, err
+		}
+		if again != passphrase {
+			return nil, errors.New("the passphrases don't match")
+		}
+	}
+	return []byte(passphrase), nil
+}
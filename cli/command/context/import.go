@@ -1,39 +1,78 @@
 package context
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/context/store"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
+// downloadHTTPClient is the client that downloads the contexts that are
+// imported from a URL.
+var downloadHTTPClient = &http.Client{Timeout: time.Minute}
+
+type importOptions struct {
+	name            string
+	source          string
+	passphraseStdin bool
+}
+
 func newImportCommand(dockerCli command.Cli) *cobra.Command {
+	var opts importOptions
 	cmd := &cobra.Command{
-		Use:   "import CONTEXT FILE|-",
+		Use:   "import [OPTIONS] CONTEXT FILE|URL|-",
 		Short: "Import a context from a tar or zip file",
 		Args:  cli.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return RunImport(dockerCli, args[0], args[1])
+			opts.name = args[0]
+			opts.source = args[1]
+			return runImport(cmd.Context(), dockerCli, opts)
 		},
 	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.passphraseStdin, "passphrase-stdin", false, "Take the passphrase to decrypt the TLS material from stdin")
 	return cmd
 }
 
 // RunImport imports a Docker context
 func RunImport(dockerCli command.Cli, name string, source string) error {
-	if err := checkContextNameForCreation(dockerCli.ContextStore(), name); err != nil {
+	return runImport(context.Background(), dockerCli, importOptions{name: name, source: source})
+}
+
+func runImport(ctx context.Context, dockerCli command.Cli, opts importOptions) error {
+	if err := checkContextNameForCreation(dockerCli.ContextStore(), opts.name); err != nil {
 		return err
 	}
+	if opts.passphraseStdin && opts.source == "-" {
+		return errors.New("cannot read both the context and the passphrase from stdin")
+	}
 
 	var reader io.Reader
-	if source == "-" {
+	switch {
+	case opts.source == "-":
 		reader = dockerCli.In()
-	} else {
-		f, err := os.Open(source)
+	case strings.HasPrefix(opts.source, "http://"):
+		// An exported context holds credentials, such as TLS keys.
+		return errors.Errorf("refusing to download the context from %s over an insecure connection: use an https:// URL", opts.source)
+	case strings.HasPrefix(opts.source, "https://"):
+		body, err := download(ctx, opts.source)
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		reader = body
+	default:
+		f, err := os.Open(opts.source)
 		if err != nil {
 			return err
 		}
@@ -41,11 +80,33 @@ func RunImport(dockerCli command.Cli, name string, source string) error {
 		reader = f
 	}
 
-	if err := store.Import(name, dockerCli.ContextStore(), reader); err != nil {
+	if err := store.ImportWithOptions(opts.name, dockerCli.ContextStore(), reader, store.ImportOptions{
+		Passphrase: func() ([]byte, error) {
+			return readPassphrase(dockerCli, opts.passphraseStdin, false)
+		},
+	}); err != nil {
 		return err
 	}
 
-	fmt.Fprintln(dockerCli.Out(), name)
-	fmt.Fprintf(dockerCli.Err(), "Successfully imported context %q\n", name)
+	fmt.Fprintln(dockerCli.Out(), opts.name)
+	dockerCli.Err().Infof("Successfully imported context %q\n", opts.name)
 	return nil
 }
+
+// download returns the body of the response to a GET request of an exported
+// context at url.
+func download(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := downloadHTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download the context")
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, errors.Errorf("failed to download the context from %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/inspect"
 	"github.com/docker/cli/cli/context/store"
 	flagsHelper "github.com/docker/cli/cli/flags"
@@ -14,8 +15,9 @@ import (
 )
 
 type inspectOptions struct {
-	format string
-	refs   []string
+	format   string
+	refs     []string
+	security bool
 }
 
 // newInspectCommand creates a new cobra.Command for `docker context inspect`
@@ -35,15 +37,21 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 			}
 			return runInspect(dockerCli, opts)
 		},
+		ValidArgsFunction: completion.ContextNames(dockerCli),
 	}
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	flags.BoolVar(&opts.security, "security", false, "Display the effective TLS material and proxy settings of the Docker endpoint")
 	return cmd
 }
 
 func runInspect(dockerCli command.Cli, opts inspectOptions) error {
 	getRefFunc := func(ref string) (any, []byte, error) {
+		if opts.security {
+			sec, err := getContextSecurity(dockerCli, ref)
+			return sec, nil, err
+		}
 		c, err := dockerCli.ContextStore().GetMetadata(ref)
 		if err != nil {
 			return nil, nil, err
@@ -18,6 +18,7 @@ const (
 	keyCert          = "cert"
 	keyKey           = "key"
 	keySkipTLSVerify = "skip-tls-verify"
+	keyProxy         = "proxy"
 )
 
 type configKeyDescription struct {
@@ -33,6 +34,7 @@ var (
 		keyCert:          {},
 		keyKey:           {},
 		keySkipTLSVerify: {},
+		keyProxy:         {},
 	}
 	dockerConfigKeysDescriptions = []configKeyDescription{
 		{
@@ -59,6 +61,10 @@ var (
 			name:        keySkipTLSVerify,
 			description: "Skip TLS certificate validation",
 		},
+		{
+			name:        keyProxy,
+			description: "Proxy to connect to the endpoint through",
+		},
 	}
 )
 
@@ -110,18 +116,27 @@ func getDockerEndpoint(contextStore store.Reader, config map[string]string) (doc
 		EndpointMeta: docker.EndpointMeta{
 			Host:          config[keyHost],
 			SkipTLSVerify: skipTLSVerify,
+			Proxy:         config[keyProxy],
 		},
 		TLSData: tlsData,
 	}
-	// try to resolve a docker client, validating the configuration
+	if err := validateDockerEndpoint(ep); err != nil {
+		return docker.Endpoint{}, err
+	}
+	return ep, nil
+}
+
+// validateDockerEndpoint validates the configuration of a Docker endpoint, by
+// resolving a docker client for it.
+func validateDockerEndpoint(ep docker.Endpoint) error {
 	opts, err := ep.ClientOpts()
 	if err != nil {
-		return docker.Endpoint{}, errors.Wrap(err, "invalid docker endpoint options")
+		return errors.Wrap(err, "invalid docker endpoint options")
 	}
 	if _, err := client.NewClientWithOpts(opts...); err != nil {
-		return docker.Endpoint{}, errors.Wrap(err, "unable to apply docker endpoint options")
+		return errors.Wrap(err, "unable to apply docker endpoint options")
 	}
-	return ep, nil
+	return nil
 }
 
 func getDockerEndpointMetadataAndTLS(contextStore store.Reader, config map[string]string) (docker.EndpointMeta, *store.EndpointTLSData, error) {
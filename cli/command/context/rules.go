@@ -0,0 +1,248 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/context/store"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultContextRuleTableFormat = "table {{.Rule}}{{if .Active}} *{{end}}\t{{.Directory}}\t{{.GitRepo}}\t{{.Context}}"
+
+	contextRuleHeader          = "RULE"
+	contextRuleDirectoryHeader = "DIRECTORY"
+	contextRuleGitRepoHeader   = "GIT REPOSITORY"
+	contextRuleContextHeader   = "CONTEXT"
+)
+
+func newRulesCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Manage the rules that select the context to use in directories",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newRulesAddCommand(dockerCli),
+		newRulesListCommand(dockerCli),
+		newRulesRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+type rulesAddOptions struct {
+	context   string
+	directory string
+	gitRepo   string
+}
+
+func newRulesAddCommand(dockerCli command.Cli) *cobra.Command {
+	var opts rulesAddOptions
+	cmd := &cobra.Command{
+		Use:   "add [OPTIONS] CONTEXT",
+		Short: "Add a rule to use a context in a directory or Git repository",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.context = args[0]
+			return runRulesAdd(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.directory, "dir", "", "Use the context in this directory and its subdirectories")
+	flags.StringVar(&opts.gitRepo, "git-repo", "", `Use the context in Git repositories with a remote matching this pattern, such as "github.com/org/*"`)
+	return cmd
+}
+
+func runRulesAdd(dockerCli command.Cli, opts rulesAddOptions) error {
+	if (opts.directory == "") == (opts.gitRepo == "") {
+		return errors.New("either --dir or --git-repo must be set")
+	}
+	if opts.context != command.DefaultContextName {
+		if err := store.ValidateContextName(opts.context); err != nil {
+			return err
+		}
+		if _, err := dockerCli.ContextStore().GetMetadata(opts.context); err != nil {
+			return err
+		}
+	}
+
+	rule := configfile.ContextRule{Context: opts.context}
+	if opts.directory != "" {
+		dir, err := filepath.Abs(opts.directory)
+		if err != nil {
+			return err
+		}
+		rule.Directory = dir
+	} else {
+		rule.GitRepo = command.NormalizeGitURL(opts.gitRepo)
+		if _, err := path.Match(rule.GitRepo, ""); err != nil {
+			return errors.Wrapf(err, "invalid pattern %q", opts.gitRepo)
+		}
+	}
+
+	cfg := dockerCli.ConfigFile()
+	cfg.ContextRules = append(cfg.ContextRules, rule)
+	if err := cfg.Save(); err != nil {
+		return errors.Wrap(err, "failed to save the context rule in the configuration file")
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), len(cfg.ContextRules))
+	return nil
+}
+
+type rulesListOptions struct {
+	format string
+}
+
+func newRulesListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts rulesListOptions
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the context rules",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesList(dockerCli, opts)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runRulesList(dockerCli command.Cli, opts rulesListOptions) error {
+	rules := dockerCli.ConfigFile().ContextRules
+	active := -1
+	if wd, err := os.Getwd(); err == nil {
+		active = command.MatchContextRule(rules, wd)
+	}
+
+	format := opts.format
+	if format == "" {
+		format = formatter.TableFormatKey
+	}
+	return contextRuleWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newContextRuleFormat(format),
+	}, rules, active)
+}
+
+func newRulesRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm RULE [RULE...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more context rules",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRulesRemove(dockerCli, args)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	return cmd
+}
+
+func runRulesRemove(dockerCli command.Cli, args []string) error {
+	cfg := dockerCli.ConfigFile()
+	remove := make(map[int]bool)
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(cfg.ContextRules) {
+			return errors.Errorf("no such context rule: %s", arg)
+		}
+		remove[n-1] = true
+	}
+	var rules []configfile.ContextRule
+	for i, rule := range cfg.ContextRules {
+		if !remove[i] {
+			rules = append(rules, rule)
+		}
+	}
+	cfg.ContextRules = rules
+	if err := cfg.Save(); err != nil {
+		return errors.Wrap(err, "failed to remove the context rule from the configuration file")
+	}
+
+	removed := make([]int, 0, len(remove))
+	for i := range remove {
+		removed = append(removed, i+1)
+	}
+	sort.Ints(removed)
+	for _, n := range removed {
+		_, _ = fmt.Fprintln(dockerCli.Out(), n)
+	}
+	return nil
+}
+
+func newContextRuleFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultContextRuleTableFormat
+	}
+	return formatter.Format(source)
+}
+
+func contextRuleWrite(ctx formatter.Context, rules []configfile.ContextRule, active int) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for i, rule := range rules {
+			if err := format(&contextRuleContext{n: i + 1, rule: rule, active: i == active}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	ruleCtx := contextRuleContext{}
+	ruleCtx.Header = formatter.SubHeaderContext{
+		"Rule":      contextRuleHeader,
+		"Directory": contextRuleDirectoryHeader,
+		"GitRepo":   contextRuleGitRepoHeader,
+		"Context":   contextRuleContextHeader,
+	}
+	return ctx.Write(&ruleCtx, render)
+}
+
+type contextRuleContext struct {
+	formatter.HeaderContext
+	n      int
+	rule   configfile.ContextRule
+	active bool
+}
+
+func (c *contextRuleContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *contextRuleContext) Rule() int {
+	return c.n
+}
+
+// Active is whether the rule selects the context in the working directory.
+func (c *contextRuleContext) Active() bool {
+	return c.active
+}
+
+func (c *contextRuleContext) Directory() string {
+	return c.rule.Directory
+}
+
+func (c *contextRuleContext) GitRepo() string {
+	return c.rule.GitRepo
+}
+
+func (c *contextRuleContext) Context() string {
+	return c.rule.Context
+}
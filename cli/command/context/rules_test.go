@@ -0,0 +1,77 @@
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestContextRules(t *testing.T) {
+	cli := makeFakeCli(t)
+	cli.ConfigFile().Filename = filepath.Join(t.TempDir(), "config.json")
+	createTestContext(t, cli, "test", nil)
+	wd, err := os.Getwd()
+	assert.NilError(t, err)
+	cli.OutBuffer().Reset()
+
+	cmd := newRulesAddCommand(cli)
+	cmd.SetArgs([]string{"--git-repo", "https://github.com/acme/*.git", "test"})
+	assert.NilError(t, cmd.Execute())
+	cmd = newRulesAddCommand(cli)
+	cmd.SetArgs([]string{"--dir", ".", "default"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "1\n2\n"))
+	assert.Check(t, is.DeepEqual(cli.ConfigFile().ContextRules, []configfile.ContextRule{
+		{GitRepo: "github.com/acme/*", Context: "test"},
+		{Directory: wd, Context: "default"},
+	}))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runRulesList(cli, rulesListOptions{format: "{{.Rule}}|{{.Active}}|{{.Directory}}|{{.GitRepo}}|{{.Context}}"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "1|false||github.com/acme/*|test\n2|true|"+wd+"||default\n"))
+
+	assert.Check(t, is.ErrorContains(runRulesRemove(cli, []string{"1", "3"}), "no such context rule: 3"))
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runRulesRemove(cli, []string{"1"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "1\n"))
+	assert.Check(t, is.DeepEqual(cli.ConfigFile().ContextRules, []configfile.ContextRule{
+		{Directory: wd, Context: "default"},
+	}))
+}
+
+func TestContextRulesAddErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"test"},
+			expectedError: "either --dir or --git-repo must be set",
+		},
+		{
+			args:          []string{"--dir", ".", "--git-repo", "github.com/acme/app", "test"},
+			expectedError: "either --dir or --git-repo must be set",
+		},
+		{
+			args:          []string{"--dir", ".", "missing"},
+			expectedError: `context "missing": context not found`,
+		},
+		{
+			args:          []string{"--git-repo", "github.com/acme/[", "test"},
+			expectedError: "invalid pattern",
+		},
+	}
+	for _, tc := range testCases {
+		cli := makeFakeCli(t)
+		createTestContext(t, cli, "test", nil)
+		cmd := newRulesAddCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(cli.OutBuffer())
+		cmd.SetErr(cli.ErrBuffer())
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
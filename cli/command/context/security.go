@@ -0,0 +1,186 @@
+package context
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/docker/client"
+)
+
+const (
+	proxySourceContext     = "context"
+	proxySourceEnvironment = "environment"
+)
+
+// contextSecurity is the effective TLS material and proxy settings of the
+// Docker endpoint of a context.
+type contextSecurity struct {
+	Name          string
+	Host          string `json:",omitempty"`
+	SkipTLSVerify bool
+	TLS           *tlsMaterial `json:",omitempty"`
+	Proxy         proxySettings
+}
+
+type tlsMaterial struct {
+	CA   []certificateInfo `json:",omitempty"`
+	Cert *certificateInfo  `json:",omitempty"`
+	Key  *keyInfo          `json:",omitempty"`
+}
+
+type certificateInfo struct {
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	Expired   bool
+	Error     string `json:",omitempty"`
+}
+
+// keyInfo is the type of the TLS key, or the error of loading it with the
+// TLS certificate, such as if they don't match.
+type keyInfo struct {
+	Type  string `json:",omitempty"`
+	Error string `json:",omitempty"`
+}
+
+type proxySettings struct {
+	// Connection is the proxy that the CLI connects to the endpoint through,
+	// and Source where it's set: in the context, or in the environment.
+	Connection string `json:",omitempty"`
+	Source     string `json:",omitempty"`
+	// Env is the proxy environment variables that are set in the containers
+	// and builds of the endpoint, from the "proxies" of the configuration
+	// file.
+	Env map[string]string `json:",omitempty"`
+}
+
+func getContextSecurity(dockerCli command.Cli, name string) (contextSecurity, error) {
+	s := dockerCli.ContextStore()
+	c, err := s.GetMetadata(name)
+	if err != nil {
+		return contextSecurity{}, err
+	}
+	sec := contextSecurity{Name: c.Name}
+	meta, err := docker.EndpointFromContext(c)
+	if err != nil {
+		return sec, nil
+	}
+	ep, err := docker.WithTLSData(s, name, meta)
+	if err != nil {
+		return contextSecurity{}, err
+	}
+	sec.Host = ep.Host
+	sec.SkipTLSVerify = ep.SkipTLSVerify
+	if ep.TLSData != nil {
+		sec.TLS = describeTLSData(ep.TLSData.CA, ep.TLSData.Cert, ep.TLSData.Key)
+	}
+	sec.Proxy.Connection, sec.Proxy.Source = connectionProxy(ep)
+
+	host := ep.Host
+	if host == "" {
+		host = client.DefaultDockerHost
+	}
+	for k, v := range dockerCli.ConfigFile().ParseProxyConfig(host, nil) {
+		// The variables are set in both upper and lower case.
+		if k != strings.ToUpper(k) {
+			continue
+		}
+		if sec.Proxy.Env == nil {
+			sec.Proxy.Env = map[string]string{}
+		}
+		sec.Proxy.Env[k] = *v
+	}
+	return sec, nil
+}
+
+// connectionProxy returns the proxy that the CLI connects to the endpoint
+// through, which is only used for TCP hosts.
+func connectionProxy(ep docker.Endpoint) (proxy, source string) {
+	if ep.Host == "" {
+		return "", ""
+	}
+	if helper, err := connhelper.GetConnectionHelper(ep.Host); err != nil || helper != nil {
+		return "", ""
+	}
+	hostURL, err := client.ParseHostURL(ep.Host)
+	if err != nil || hostURL.Scheme != "tcp" {
+		return "", ""
+	}
+	if ep.Proxy != "" {
+		return ep.Proxy, proxySourceContext
+	}
+	scheme := "http"
+	if ep.TLSData != nil || ep.SkipTLSVerify {
+		scheme = "https"
+	}
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: &url.URL{Scheme: scheme, Host: hostURL.Host}})
+	if err != nil || proxyURL == nil {
+		return "", ""
+	}
+	return proxyURL.Redacted(), proxySourceEnvironment
+}
+
+func describeTLSData(ca, cert, key []byte) *tlsMaterial {
+	var m tlsMaterial
+	for rest := ca; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			m.CA = append(m.CA, describeCertificate(block.Bytes))
+		}
+	}
+	if block, _ := pem.Decode(cert); block != nil {
+		info := describeCertificate(block.Bytes)
+		m.Cert = &info
+	}
+	if key != nil {
+		m.Key = describeKey(cert, key)
+	}
+	return &m
+}
+
+func describeCertificate(der []byte) certificateInfo {
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		return certificateInfo{Error: err.Error()}
+	}
+	return certificateInfo{
+		Subject:   c.Subject.String(),
+		Issuer:    c.Issuer.String(),
+		NotBefore: c.NotBefore,
+		NotAfter:  c.NotAfter,
+		Expired:   time.Now().After(c.NotAfter),
+	}
+}
+
+func describeKey(cert, key []byte) *keyInfo {
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return &keyInfo{Error: err.Error()}
+	}
+	switch pair.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		return &keyInfo{Type: "RSA"}
+	case *ecdsa.PrivateKey:
+		return &keyInfo{Type: "ECDSA"}
+	case ed25519.PrivateKey:
+		return &keyInfo{Type: "Ed25519"}
+	default:
+		return &keyInfo{}
+	}
+}
@@ -0,0 +1,255 @@
+package context
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/context/docker"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/client"
+	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultContextTestTableFormat = "table {{.Name}}\t{{.DockerEndpoint}}\t{{.Status}}\t{{.Latency}}\t{{.ServerVersion}}\t{{.Error}}"
+
+	contextTestNameHeader     = "NAME"
+	contextTestEndpointHeader = "DOCKER ENDPOINT"
+	contextTestStatusHeader   = "STATUS"
+	contextTestLatencyHeader  = "LATENCY"
+	contextTestVersionHeader  = "SERVER VERSION"
+	contextTestErrorHeader    = "ERROR"
+)
+
+type testOptions struct {
+	format  string
+	timeout time.Duration
+}
+
+func newTestCommand(dockerCli command.Cli) *cobra.Command {
+	var opts testOptions
+	cmd := &cobra.Command{
+		Use:   "test [OPTIONS] [CONTEXT...]",
+		Short: "Test the connection to the Docker endpoint of contexts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTest(cmd.Context(), dockerCli, opts, args)
+		},
+		ValidArgsFunction: completion.ContextNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "Maximum time to wait for each Docker endpoint")
+	return cmd
+}
+
+// contextTestResult is the result of testing the connection to the Docker
+// endpoint of a context.
+type contextTestResult struct {
+	Name           string
+	DockerEndpoint string
+	OK             bool
+	Latency        time.Duration
+	ServerVersion  string
+	APIVersion     string
+	Error          string
+}
+
+func runTest(ctx context.Context, dockerCli command.Cli, opts testOptions, names []string) error {
+	s := dockerCli.ContextStore()
+	if len(names) == 0 {
+		contexts, err := s.List()
+		if err != nil {
+			return err
+		}
+		for _, c := range contexts {
+			names = append(names, c.Name)
+		}
+		sort.Slice(names, func(i, j int) bool { return sortorder.NaturalLess(names[i], names[j]) })
+	}
+
+	results := make([]contextTestResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		i, name := i, name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = testContext(ctx, dockerCli, name, opts.timeout)
+		}()
+	}
+	wg.Wait()
+
+	format := opts.format
+	if format == "" {
+		format = formatter.TableFormatKey
+	}
+	if err := contextTestWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newContextTestFormat(format),
+	}, results); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if !r.OK {
+			return cli.StatusError{StatusCode: 1}
+		}
+	}
+	return nil
+}
+
+// testContext connects to the Docker endpoint of a context, and gets the
+// version of the daemon. The latency is the duration of the ping.
+func testContext(ctx context.Context, dockerCli command.Cli, name string, timeout time.Duration) contextTestResult {
+	result := contextTestResult{Name: name}
+	s := dockerCli.ContextStore()
+	c, err := s.GetMetadata(name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	meta, err := docker.EndpointFromContext(c)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.DockerEndpoint = meta.Host
+	ep, err := docker.WithTLSData(s, name, meta)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	clientOpts, err := ep.ClientOpts()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	apiClient, err := client.NewClientWithOpts(append(clientOpts, client.WithUserAgent(command.UserAgent()))...)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer apiClient.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	if _, err := apiClient.Ping(ctx); err != nil {
+		result.Error = describeConnectionError(err, timeout)
+		return result
+	}
+	result.Latency = time.Since(start)
+	version, err := apiClient.ServerVersion(ctx)
+	if err != nil {
+		result.Error = describeConnectionError(err, timeout)
+		return result
+	}
+	result.OK = true
+	result.ServerVersion = version.Version
+	result.APIVersion = version.APIVersion
+	return result
+}
+
+// describeConnectionError returns the message of an error to connect to a
+// Docker endpoint, which is prefixed for TLS errors.
+func describeConnectionError(err error, timeout time.Duration) string {
+	var (
+		recordHeaderErr tls.RecordHeaderError
+		unknownAuthErr  x509.UnknownAuthorityError
+		certInvalidErr  x509.CertificateInvalidError
+		hostnameErr     x509.HostnameError
+	)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out after " + timeout.String()
+	case errors.As(err, &recordHeaderErr), errors.As(err, &unknownAuthErr), errors.As(err, &certInvalidErr), errors.As(err, &hostnameErr):
+		return "TLS error: " + err.Error()
+	default:
+		return err.Error()
+	}
+}
+
+func newContextTestFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultContextTestTableFormat
+	}
+	return formatter.Format(source)
+}
+
+func contextTestWrite(ctx formatter.Context, results []contextTestResult) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, r := range results {
+			if err := format(&contextTestContext{r: r}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	testCtx := contextTestContext{}
+	testCtx.Header = formatter.SubHeaderContext{
+		"Name":           contextTestNameHeader,
+		"DockerEndpoint": contextTestEndpointHeader,
+		"Status":         contextTestStatusHeader,
+		"Latency":        contextTestLatencyHeader,
+		"ServerVersion":  contextTestVersionHeader,
+		"Error":          contextTestErrorHeader,
+	}
+	return ctx.Write(&testCtx, render)
+}
+
+type contextTestContext struct {
+	formatter.HeaderContext
+	r contextTestResult
+}
+
+func (c *contextTestContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *contextTestContext) Name() string {
+	return c.r.Name
+}
+
+func (c *contextTestContext) DockerEndpoint() string {
+	return c.r.DockerEndpoint
+}
+
+func (c *contextTestContext) Status() string {
+	if c.r.OK {
+		return "ok"
+	}
+	return "error"
+}
+
+func (c *contextTestContext) Latency() string {
+	if !c.r.OK {
+		return ""
+	}
+	if c.r.Latency < time.Millisecond {
+		return c.r.Latency.Round(time.Microsecond).String()
+	}
+	return c.r.Latency.Round(time.Millisecond).String()
+}
+
+func (c *contextTestContext) ServerVersion() string {
+	return c.r.ServerVersion
+}
+
+func (c *contextTestContext) APIVersion() string {
+	return c.r.APIVersion
+}
+
+func (c *contextTestContext) Error() string {
+	return c.r.Error
+}
@@ -0,0 +1,63 @@
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestContextTest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Api-Version", "1.45")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_ping"):
+			_, _ = w.Write([]byte("OK"))
+		case strings.HasSuffix(r.URL.Path, "/version"):
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(types.Version{Version: "27.0.0", APIVersion: "1.45"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	// A listener that is closed right away, so its address refuses
+	// connections.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	fakeCli := makeFakeCli(t)
+	for name, host := range map[string]string{
+		"ok":     "tcp://" + srv.Listener.Addr().String(),
+		"broken": "tcp://" + closed.Listener.Addr().String(),
+	} {
+		assert.NilError(t, RunCreate(fakeCli, &CreateOptions{Name: name, Docker: map[string]string{keyHost: host}}))
+	}
+	fakeCli.OutBuffer().Reset()
+
+	err := runTest(context.Background(), fakeCli, testOptions{format: "{{.Name}}|{{.Status}}|{{.ServerVersion}}|{{.APIVersion}}", timeout: 5 * time.Second}, []string{"ok", "broken"})
+	assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: 1}))
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), "ok|ok|27.0.0|1.45\nbroken|error||\n"))
+
+	fakeCli.OutBuffer().Reset()
+	assert.NilError(t, runTest(context.Background(), fakeCli, testOptions{format: "json", timeout: 5 * time.Second}, []string{"ok"}))
+	var result map[string]string
+	assert.NilError(t, json.Unmarshal(fakeCli.OutBuffer().Bytes(), &result))
+	assert.Check(t, is.Equal(result["Status"], "ok"))
+	assert.Check(t, result["Latency"] != "")
+}
+
+func TestContextTestErrors(t *testing.T) {
+	fakeCli := makeFakeCli(t)
+	err := runTest(context.Background(), fakeCli, testOptions{format: "{{.Name}}|{{.Error}}", timeout: time.Second}, []string{"missing"})
+	assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: 1}))
+	assert.Check(t, is.Contains(fakeCli.OutBuffer().String(), `missing|context "missing": context not found`))
+}
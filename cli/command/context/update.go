@@ -3,10 +3,13 @@ package context
 import (
 	"bytes"
 	"fmt"
+	"os"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/cli/context"
 	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/pkg/errors"
@@ -18,6 +21,15 @@ type UpdateOptions struct {
 	Name        string
 	Description string
 	Docker      map[string]string
+
+	// TLSCA, TLSCert, and TLSKey, if set, are the paths of the files whose
+	// content replaces the CA, TLS certificate, and TLS key of the Docker
+	// endpoint; an empty path removes them. Proxy, if set, replaces the
+	// proxy of the Docker endpoint.
+	TLSCA   *string
+	TLSCert *string
+	TLSKey  *string
+	Proxy   *string
 }
 
 func longUpdateDescription() string {
@@ -35,19 +47,43 @@ func longUpdateDescription() string {
 
 func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	opts := &UpdateOptions{}
+	var tlsCA, tlsCert, tlsKey, proxy string
 	cmd := &cobra.Command{
 		Use:   "update [OPTIONS] CONTEXT",
 		Short: "Update a context",
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Name = args[0]
+			flags := cmd.Flags()
+			if flags.Changed("tls-ca") {
+				opts.TLSCA = &tlsCA
+			}
+			if flags.Changed("tls-cert") {
+				opts.TLSCert = &tlsCert
+			}
+			if flags.Changed("tls-key") {
+				opts.TLSKey = &tlsKey
+			}
+			if flags.Changed("proxy") {
+				opts.Proxy = &proxy
+			}
 			return RunUpdate(dockerCli, opts)
 		},
 		Long: longUpdateDescription(),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.ContextNames(dockerCli)(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
 	}
 	flags := cmd.Flags()
 	flags.StringVar(&opts.Description, "description", "", "Description of the context")
 	flags.StringToStringVar(&opts.Docker, "docker", nil, "set the docker endpoint")
+	flags.StringVar(&tlsCA, "tls-ca", "", "Trust certs signed only by this CA for the Docker endpoint (set to an empty value to remove it)")
+	flags.StringVar(&tlsCert, "tls-cert", "", "Path to the TLS certificate file of the Docker endpoint (set to an empty value to remove it)")
+	flags.StringVar(&tlsKey, "tls-key", "", "Path to the TLS key file of the Docker endpoint (set to an empty value to remove it)")
+	flags.StringVar(&proxy, "proxy", "", "Proxy to connect to the Docker endpoint through (set to an empty value to use the proxy of the environment)")
 	return cmd
 }
 
@@ -73,13 +109,28 @@ func RunUpdate(dockerCLI command.Cli, o *UpdateOptions) error {
 
 	tlsDataToReset := make(map[string]*store.EndpointTLSData)
 
+	var dockerTLS *context.TLSData
 	if o.Docker != nil {
-		dockerEP, dockerTLS, err := getDockerEndpointMetadataAndTLS(s, o.Docker)
+		dockerEP, err := getDockerEndpoint(s, o.Docker)
 		if err != nil {
 			return errors.Wrap(err, "unable to create docker endpoint config")
 		}
-		c.Endpoints[docker.DockerEndpoint] = dockerEP
-		tlsDataToReset[docker.DockerEndpoint] = dockerTLS
+		c.Endpoints[docker.DockerEndpoint] = dockerEP.EndpointMeta
+		dockerTLS = dockerEP.TLSData
+		tlsDataToReset[docker.DockerEndpoint] = dockerTLS.ToStoreTLSData()
+	}
+	if o.TLSCA != nil || o.TLSCert != nil || o.TLSKey != nil || o.Proxy != nil {
+		if o.Docker == nil {
+			if dockerTLS, err = context.LoadTLSData(s, o.Name, docker.DockerEndpoint); err != nil {
+				return err
+			}
+		}
+		dockerEP, err := updateDockerEndpoint(c, dockerTLS, o)
+		if err != nil {
+			return errors.Wrap(err, "unable to update docker endpoint config")
+		}
+		c.Endpoints[docker.DockerEndpoint] = dockerEP.EndpointMeta
+		tlsDataToReset[docker.DockerEndpoint] = dockerEP.TLSData.ToStoreTLSData()
 	}
 	if err := validateEndpoints(c); err != nil {
 		return err
@@ -94,10 +145,56 @@ func RunUpdate(dockerCLI command.Cli, o *UpdateOptions) error {
 	}
 
 	fmt.Fprintln(dockerCLI.Out(), o.Name)
-	fmt.Fprintf(dockerCLI.Err(), "Successfully updated context %q\n", o.Name)
+	dockerCLI.Err().Infof("Successfully updated context %q\n", o.Name)
 	return nil
 }
 
+// updateDockerEndpoint returns the Docker endpoint of the context, with the
+// TLS material and proxy of the options.
+func updateDockerEndpoint(c store.Metadata, tlsData *context.TLSData, o *UpdateOptions) (docker.Endpoint, error) {
+	var ep docker.Endpoint
+	if meta, ok := c.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta); ok {
+		ep.EndpointMeta = meta
+	}
+	var data context.TLSData
+	if tlsData != nil {
+		data = *tlsData
+	}
+	for _, f := range []struct {
+		path *string
+		data *[]byte
+	}{
+		{path: o.TLSCA, data: &data.CA},
+		{path: o.TLSCert, data: &data.Cert},
+		{path: o.TLSKey, data: &data.Key},
+	} {
+		switch {
+		case f.path == nil:
+		case *f.path == "":
+			*f.data = nil
+		default:
+			b, err := os.ReadFile(*f.path)
+			if err != nil {
+				return docker.Endpoint{}, err
+			}
+			*f.data = b
+		}
+	}
+	if (data.Cert == nil) != (data.Key == nil) {
+		return docker.Endpoint{}, errors.New("the Docker endpoint must have both a TLS certificate and key, or neither")
+	}
+	if data.CA != nil || data.Cert != nil {
+		ep.TLSData = &data
+	}
+	if o.Proxy != nil {
+		ep.Proxy = *o.Proxy
+	}
+	if err := validateDockerEndpoint(ep); err != nil {
+		return docker.Endpoint{}, err
+	}
+	return ep, nil
+}
+
 func validateEndpoints(c store.Metadata) error {
 	_, err := command.GetDockerContext(c)
 	return err
@@ -1,10 +1,22 @@
 package context
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 )
@@ -65,3 +77,93 @@ func TestUpdateInvalidDockerHost(t *testing.T) {
 	})
 	assert.ErrorContains(t, err, "unable to parse docker host")
 }
+
+// writeTestCertificate writes a self-signed certificate, and its key, to dir.
+func writeTestCertificate(t *testing.T, dir string) (certPath, keyPath string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	certPath, keyPath = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	assert.NilError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644))
+	assert.NilError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certPath, keyPath
+}
+
+func TestUpdateTLSAndProxy(t *testing.T) {
+	cli := makeFakeCli(t)
+	createTestContext(t, cli, "test", nil)
+	certPath, keyPath := writeTestCertificate(t, t.TempDir())
+
+	cmd := newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--tls-ca", certPath, "--tls-cert", certPath, "--tls-key", keyPath, "--proxy", "http://proxy.example.com:3128", "test"})
+	assert.NilError(t, cmd.Execute())
+
+	c, err := cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	ep := c.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta)
+	assert.Check(t, is.Equal(ep.Host, "https://someswarmserver.example.com"))
+	assert.Check(t, is.Equal(ep.Proxy, "http://proxy.example.com:3128"))
+	files, err := cli.ContextStore().ListTLSFiles("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(files[docker.DockerEndpoint], store.EndpointFiles{"ca.pem", "cert.pem", "key.pem"}))
+
+	sec, err := getContextSecurity(cli, "test")
+	assert.NilError(t, err)
+	assert.Assert(t, sec.TLS != nil)
+	assert.Check(t, is.Len(sec.TLS.CA, 1))
+	assert.Check(t, is.Equal(sec.TLS.Cert.Subject, "CN=test"))
+	assert.Check(t, !sec.TLS.Cert.Expired)
+	assert.Check(t, is.DeepEqual(sec.TLS.Key, &keyInfo{Type: "ECDSA"}))
+
+	// Only the TLS material of the options that are set is changed.
+	cmd = newUpdateCommand(cli)
+	cmd.SetArgs([]string{"--tls-ca=", "--proxy=", "test"})
+	assert.NilError(t, cmd.Execute())
+	c, err = cli.ContextStore().GetMetadata("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(c.Endpoints[docker.DockerEndpoint].(docker.EndpointMeta).Proxy, ""))
+	files, err = cli.ContextStore().ListTLSFiles("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(files[docker.DockerEndpoint], store.EndpointFiles{"cert.pem", "key.pem"}))
+}
+
+func TestUpdateTLSAndProxyErrors(t *testing.T) {
+	certPath, _ := writeTestCertificate(t, t.TempDir())
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"--tls-cert", certPath, "test"},
+			expectedError: "must have both a TLS certificate and key, or neither",
+		},
+		{
+			args:          []string{"--tls-ca", "no-such-file.pem", "test"},
+			expectedError: "no such file or directory",
+		},
+		{
+			args:          []string{"--proxy", "ftp://proxy.example.com", "test"},
+			expectedError: "the scheme must be http, https, or socks5",
+		},
+	}
+	for _, tc := range testCases {
+		cli := makeFakeCli(t)
+		createTestContext(t, cli, "test", nil)
+		cmd := newUpdateCommand(cli)
+		cmd.SetArgs(tc.args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
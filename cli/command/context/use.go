@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/context/store"
 	"github.com/docker/docker/client"
 	"github.com/spf13/cobra"
@@ -19,6 +20,12 @@ func newUseCommand(dockerCli command.Cli) *cobra.Command {
 			name := args[0]
 			return RunUse(dockerCli, name)
 		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return completion.ContextNames(dockerCli)(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
 	}
 	return cmd
 }
@@ -47,10 +54,16 @@ func RunUse(dockerCli command.Cli, name string) error {
 		}
 	}
 	fmt.Fprintln(dockerCli.Out(), name)
-	fmt.Fprintf(dockerCli.Err(), "Current context is now %q\n", name)
+	dockerCli.Err().Infof("Current context is now %q\n", name)
 	if name != command.DefaultContextName && os.Getenv(client.EnvOverrideHost) != "" {
 		fmt.Fprintf(dockerCli.Err(), "Warning: %[1]s environment variable overrides the active context. "+
 			"To use %[2]q, either set the global --context flag, or unset %[1]s environment variable.\n", client.EnvOverrideHost, name)
 	}
+	if wd, err := os.Getwd(); err == nil {
+		if i := command.MatchContextRule(dockerConfig.ContextRules, wd); i >= 0 && dockerConfig.ContextRules[i].Context != name {
+			fmt.Fprintf(dockerCli.Err(), "Warning: context rule %d selects the %q context in this directory, instead of the current context. "+
+				"Use \"docker context rules ls\" to list the rules.\n", i+1, dockerConfig.ContextRules[i].Context)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,145 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+// MatchContextRule returns the index of the first of rules that matches dir,
+// or -1 if none of them match. A rule matches if dir is its directory or a
+// subdirectory of it, or if dir is in a Git repository that has a remote of
+// which the URL matches the pattern of the rule.
+func MatchContextRule(rules []configfile.ContextRule, dir string) int {
+	var (
+		remotes       []string
+		remotesLoaded bool
+	)
+	for i, rule := range rules {
+		switch {
+		case rule.Directory != "":
+			if isSubdirectory(dir, rule.Directory) {
+				return i
+			}
+		case rule.GitRepo != "":
+			if !remotesLoaded {
+				remotes = gitRemoteURLs(dir)
+				remotesLoaded = true
+			}
+			pattern := NormalizeGitURL(rule.GitRepo)
+			for _, remote := range remotes {
+				if ok, _ := path.Match(pattern, remote); ok {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+func isSubdirectory(dir, parent string) bool {
+	dir, parent = filepath.Clean(dir), filepath.Clean(parent)
+	if dir == parent {
+		return true
+	}
+	if !strings.HasSuffix(parent, string(filepath.Separator)) {
+		parent += string(filepath.Separator)
+	}
+	return strings.HasPrefix(dir, parent)
+}
+
+// NormalizeGitURL returns the host and path of the URL of a Git remote, such
+// as "github.com/docker/cli" for "git@github.com:docker/cli.git" and
+// "https://github.com/docker/cli".
+func NormalizeGitURL(remote string) string {
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" && u.Host != "" {
+		remote = u.Hostname() + u.Path
+	} else if i := strings.Index(remote, ":"); i > 0 && !strings.Contains(remote[:i], "/") {
+		// scp-like syntax: [user@]host:path
+		host := remote[:i]
+		if j := strings.LastIndex(host, "@"); j >= 0 {
+			host = host[j+1:]
+		}
+		remote = host + "/" + strings.TrimPrefix(remote[i+1:], "/")
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+}
+
+// gitRemoteURLs returns the normalized URLs of the remotes of the Git
+// repository that dir is in, which are read from its configuration without
+// running git.
+func gitRemoteURLs(dir string) []string {
+	gitDir := findGitDir(dir)
+	if gitDir == "" {
+		return nil
+	}
+	// The configuration of a worktree is the one of its main repository.
+	if commonDir, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		p := strings.TrimSpace(string(commonDir))
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(gitDir, p)
+		}
+		gitDir = p
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return nil
+	}
+
+	var remotes []string
+	var inRemote bool
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inRemote = strings.HasPrefix(line, "[remote ")
+			continue
+		}
+		if !inRemote {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && strings.EqualFold(strings.TrimSpace(k), "url") {
+			remotes = append(remotes, NormalizeGitURL(strings.Trim(strings.TrimSpace(v), `"`)))
+		}
+	}
+	return remotes
+}
+
+// findGitDir returns the Git directory of the repository that dir is in, or
+// an empty string if it's not in a repository.
+func findGitDir(dir string) string {
+	for {
+		p := filepath.Join(dir, ".git")
+		if fi, err := os.Stat(p); err == nil {
+			if fi.IsDir() {
+				return p
+			}
+			// The ".git" of a worktree or submodule is a file with the
+			// path of its Git directory.
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return ""
+			}
+			gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+			if !ok {
+				return ""
+			}
+			gitDir = strings.TrimSpace(gitDir)
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+			return gitDir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,63 @@
+package command
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestNormalizeGitURL(t *testing.T) {
+	for _, remote := range []string{
+		"git@github.com:docker/cli.git",
+		"https://github.com/docker/cli",
+		"https://user@github.com/docker/cli.git",
+		"ssh://git@github.com:22/docker/cli.git",
+		"github.com/docker/cli/",
+	} {
+		assert.Check(t, is.Equal(NormalizeGitURL(remote), "github.com/docker/cli"), remote)
+	}
+}
+
+func TestMatchContextRule(t *testing.T) {
+	dir := fs.NewDir(t, "context-rules",
+		fs.WithDir("work",
+			fs.WithDir("app",
+				fs.WithDir(".git", fs.WithFile("config", `[core]
+	bare = false
+[remote "origin"]
+	url = git@github.com:acme/app.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`)),
+				fs.WithDir("src"),
+			),
+			fs.WithDir("worktree", fs.WithFile(".git", "gitdir: ../app/.git\n")),
+		),
+		fs.WithDir("other"),
+	)
+	defer dir.Remove()
+	work := filepath.Join(dir.Path(), "work")
+
+	rules := []configfile.ContextRule{
+		{Directory: filepath.Join(work, "app", "src"), Context: "src"},
+		{GitRepo: "github.com/acme/*", Context: "acme"},
+		{Directory: work, Context: "work"},
+	}
+	testCases := []struct {
+		dir      string
+		expected int
+	}{
+		{dir: filepath.Join(work, "app", "src"), expected: 0},
+		{dir: filepath.Join(work, "app"), expected: 1},
+		{dir: filepath.Join(work, "worktree"), expected: 1},
+		{dir: work, expected: 2},
+		{dir: filepath.Join(dir.Path(), "other"), expected: -1},
+		{dir: work + "-other", expected: -1},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Equal(MatchContextRule(rules, tc.dir), tc.expected), tc.dir)
+	}
+}
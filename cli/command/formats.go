@@ -0,0 +1,58 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// FormatPresetPrefix is the prefix used to refer to a format preset that's
+// stored in the CLI configuration file, as value for a "--format" flag (for
+// example, "--format preset:minimal").
+const FormatPresetPrefix = "preset:"
+
+// FormatNamePrefix is an alternative to [FormatPresetPrefix] (for example,
+// "--format name=minimal"). Unlike "preset:", it is only recognized if the
+// rest of the value is not a template, so that templates such as
+// "name={{.Names}}" keep working.
+const FormatNamePrefix = "name="
+
+// formatPresetName returns the name of the format preset that format refers
+// to, if any.
+func formatPresetName(format string) (string, bool) {
+	if name, ok := strings.CutPrefix(format, FormatPresetPrefix); ok {
+		return name, true
+	}
+	name, ok := strings.CutPrefix(format, FormatNamePrefix)
+	if !ok || strings.Contains(name, "{{") {
+		return "", false
+	}
+	return name, true
+}
+
+// IsFormatPreset returns whether format refers to a format preset.
+func IsFormatPreset(format string) bool {
+	_, ok := formatPresetName(format)
+	return ok
+}
+
+// ResolveFormatPreset replaces the value of the "--format" flag in flags
+// with the format preset it refers to, if any. It returns an error if the
+// preset does not exist.
+func ResolveFormatPreset(flags *pflag.FlagSet, configFile *configfile.ConfigFile) error {
+	f := flags.Lookup("format")
+	if f == nil || !f.Changed {
+		return nil
+	}
+	name, ok := formatPresetName(f.Value.String())
+	if !ok {
+		return nil
+	}
+	format, ok := configFile.Formats[name]
+	if !ok {
+		return errors.Errorf("format preset %q not found: use \"docker config format save\" to create it", name)
+	}
+	return f.Value.Set(format)
+}
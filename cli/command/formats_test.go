@@ -0,0 +1,73 @@
+package command_test
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/spf13/pflag"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestResolveFormatPreset(t *testing.T) {
+	cfg := configfile.New("")
+	cfg.Formats = map[string]string{"minimal": `table {{.Names}}\t{{.Status}}`}
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name:     "template",
+			args:     []string{"--format", "{{.ID}}"},
+			expected: "{{.ID}}",
+		},
+		{
+			name:     "preset",
+			args:     []string{"--format", "preset:minimal"},
+			expected: `table {{.Names}}\t{{.Status}}`,
+		},
+		{
+			name:     "preset by name",
+			args:     []string{"--format", "name=minimal"},
+			expected: `table {{.Names}}\t{{.Status}}`,
+		},
+		{
+			name:     "template starting with name=",
+			args:     []string{"--format", "name={{.Names}}"},
+			expected: "name={{.Names}}",
+		},
+		{
+			name:          "unknown preset",
+			args:          []string{"--format", "preset:nosuchpreset"},
+			expectedError: `format preset "nosuchpreset" not found`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var format string
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.StringVar(&format, "format", "", "")
+			assert.NilError(t, flags.Parse(tc.args))
+
+			err := command.ResolveFormatPreset(flags, cfg)
+			if tc.expectedError != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedError))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(format, tc.expected))
+		})
+	}
+}
+
+func TestResolveFormatPresetNoFormatFlag(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	assert.Check(t, command.ResolveFormatPreset(flags, configfile.New("")))
+}
@@ -0,0 +1,111 @@
+package formatter
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// columnFieldRegexp matches the first field used by a column of a table
+// format, for example "Size" in "{{if .Size}}{{.Size}}{{else}}N/A{{end}}".
+var columnFieldRegexp = regexp.MustCompile(`{{-?\s*(?:if\s+)?\.([A-Za-z0-9_]+)`)
+
+// selectColumns replaces the columns of the table format c.Format with the
+// columns in c.Columns. Columns are identified by their field name or their
+// header, ignoring case. If a column is prefixed with "+", it is added to the
+// columns of c.Format, and if it's prefixed with "-", it is removed from
+// them. Otherwise, only the listed columns are shown.
+func (c *Context) selectColumns(sub SubContext) error {
+	header, ok := sub.FullHeader().(SubHeaderContext)
+	if !ok || !c.Format.IsTable() {
+		return errors.New("--columns can only be used with a table format")
+	}
+
+	// Find the field of each column. Field names take precedence over
+	// headers, as headers are not unique (for example, "Size" and the
+	// deprecated "VirtualSize" fields of images both use "SIZE").
+	fieldNames := make([]string, 0, len(header))
+	for field := range header {
+		fieldNames = append(fieldNames, field)
+	}
+	sort.Strings(fieldNames)
+	fields := make(map[string]string, 2*len(header))
+	for _, field := range fieldNames {
+		fields[normalizeColumn(field)] = field
+	}
+	for _, field := range fieldNames {
+		if _, exists := fields[normalizeColumn(header[field])]; !exists {
+			fields[normalizeColumn(header[field])] = field
+		}
+	}
+
+	var columns []string
+	replace := false
+	for _, col := range c.Columns {
+		if col = strings.TrimSpace(col); col != "" && col[0] != '+' && col[0] != '-' {
+			replace = true
+			break
+		}
+	}
+	if !replace {
+		columns = tableColumns(string(c.Format))
+	}
+
+	for _, col := range c.Columns {
+		col = strings.TrimSpace(col)
+		op := byte('+')
+		if col != "" && (col[0] == '+' || col[0] == '-') {
+			op, col = col[0], col[1:]
+		}
+		if col == "" {
+			continue
+		}
+		field, ok := fields[normalizeColumn(col)]
+		if !ok {
+			return errors.Errorf("unknown column %q: valid columns are %s", col, strings.Join(fieldNames, ", "))
+		}
+		idx := -1
+		for i, column := range columns {
+			if columnField(column) == field {
+				idx = i
+				break
+			}
+		}
+		switch {
+		case op == '-' && idx >= 0:
+			columns = append(columns[:idx], columns[idx+1:]...)
+		case op == '+' && idx < 0:
+			columns = append(columns, "{{."+field+"}}")
+		}
+	}
+	if len(columns) == 0 {
+		return errors.New("--columns must select at least one column")
+	}
+	c.Format = Format(TableFormatKey + " " + strings.Join(columns, "\t"))
+	return nil
+}
+
+// tableColumns returns the template of each column of a table format.
+func tableColumns(format string) []string {
+	format = strings.TrimSpace(strings.TrimPrefix(format, TableFormatKey))
+	format = strings.ReplaceAll(format, `\t`, "\t")
+	if format == "" {
+		return nil
+	}
+	return strings.Split(format, "\t")
+}
+
+// columnField returns the name of the field used by a column of a table
+// format, or an empty string if the column doesn't use a field.
+func columnField(column string) string {
+	if m := columnFieldRegexp.FindStringSubmatch(column); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func normalizeColumn(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name))
+}
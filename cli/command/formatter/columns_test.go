@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestContainerColumns(t *testing.T) {
+	containers := []types.Container{
+		{ID: "containerID1", Names: []string{"/foobar_baz"}, Image: "ubuntu", State: "running", Status: "Up 5 seconds", SizeRw: 1024},
+	}
+
+	testCases := []struct {
+		doc      string
+		format   Format
+		columns  []string
+		expected string
+	}{
+		{
+			doc:     "replace default columns",
+			format:  NewContainerFormat(TableFormatKey, false, false),
+			columns: []string{"NAMES", "status"},
+			expected: `NAMES        STATUS
+foobar_baz   Up 5 seconds
+`,
+		},
+		{
+			doc:     "match header with spaces",
+			format:  NewContainerFormat(TableFormatKey, false, false),
+			columns: []string{"container-id", "State"},
+			expected: `CONTAINER ID   STATE
+containerID1   running
+`,
+		},
+		{
+			doc:     "add and remove columns",
+			format:  Format(`table {{.ID}}\t{{.Image}}\t{{.Names}}`),
+			columns: []string{"-image", "+state", "+Names"},
+			expected: `CONTAINER ID   NAMES        STATE
+containerID1   foobar_baz   running
+`,
+		},
+		{
+			doc:     "add and remove columns of default format",
+			format:  NewContainerFormat(TableFormatKey, false, true),
+			columns: []string{"-Command", "-CreatedAt", "-RunningFor", "-Ports", "-Image", "+state"},
+			expected: `CONTAINER ID   STATUS         NAMES        SIZE      STATE
+containerID1   Up 5 seconds   foobar_baz   1.02kB    running
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			var out bytes.Buffer
+			ctx := Context{Format: tc.format, Output: &out, Columns: tc.columns}
+			assert.NilError(t, ContainerWrite(ctx, containers))
+			assert.Check(t, is.Equal(out.String(), tc.expected))
+		})
+	}
+}
+
+func TestContainerColumnsErrors(t *testing.T) {
+	var out bytes.Buffer
+	ctx := Context{Format: NewContainerFormat(TableFormatKey, false, false), Output: &out, Columns: []string{"ID", "nosuchcolumn"}}
+	err := ContainerWrite(ctx, nil)
+	assert.Check(t, is.ErrorContains(err, `unknown column "nosuchcolumn": valid columns are Command, CreatedAt, ID,`))
+
+	ctx = Context{Format: NewContainerFormat(TableFormatKey, false, false), Output: &out, Columns: []string{"-ID", "-Image", "-Command", "-RunningFor", "-Status", "-Ports", "-Names"}}
+	err = ContainerWrite(ctx, nil)
+	assert.Check(t, is.Error(err, "--columns must select at least one column"))
+
+	ctx = Context{Format: NewContainerFormat("{{.ID}}", false, false), Output: &out, Columns: []string{"ID"}}
+	err = ContainerWrite(ctx, nil)
+	assert.Check(t, is.Error(err, "--columns can only be used with a table format"))
+	assert.Check(t, is.Equal(out.String(), ""))
+}
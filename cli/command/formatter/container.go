@@ -39,6 +39,8 @@ func NewContainerFormat(source string, quiet bool, size bool) Format {
 			format += `\t{{.Size}}`
 		}
 		return Format(format)
+	case CSVFormatKey, TSVFormatKey:
+		return NewContainerFormat(TableFormatKey, quiet, size).Delimited(source)
 	case RawFormatKey:
 		if quiet {
 			return `container_id: {{.ID}}`
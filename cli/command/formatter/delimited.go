@@ -0,0 +1,129 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/docker/cli/templates"
+	"github.com/pkg/errors"
+)
+
+// Format keys of the formats that print the columns of a table as
+// delimiter-separated values.
+const (
+	CSVFormatKey = "csv"
+	TSVFormatKey = "tsv"
+)
+
+// IsCSV returns true if the format is a csv format, either "csv", or "csv"
+// followed by the columns to print.
+func (f Format) IsCSV() bool {
+	return f.isDelimited(CSVFormatKey)
+}
+
+// IsTSV returns true if the format is a tsv format, either "tsv", or "tsv"
+// followed by the columns to print.
+func (f Format) IsTSV() bool {
+	return f.isDelimited(TSVFormatKey)
+}
+
+func (f Format) isDelimited(key string) bool {
+	return string(f) == key || strings.HasPrefix(string(f), key+" ")
+}
+
+// Delimited returns the csv or tsv format (depending on key) that prints the
+// columns of the table format f. It is used to expand the "csv" and "tsv"
+// formats to the columns of the default table format of a command.
+func (f Format) Delimited(key string) Format {
+	return Format(key + " " + strings.TrimSpace(strings.TrimPrefix(string(f), TableFormatKey)))
+}
+
+// writeDelimited writes the columns of a csv or tsv format, starting with
+// a header. Each column is rendered separately, so that it can be quoted or
+// escaped as needed.
+func (c *Context) writeDelimited(sub SubContext, f SubFormat) error {
+	key, format, _ := strings.Cut(string(c.Format), " ")
+	columns := tableColumns(format)
+	if len(columns) == 0 {
+		return errors.Errorf(`the %[1]s format requires columns for this command, for example: '%[1]s {{.ID}}\t{{.Names}}'`, key)
+	}
+	tmpls := make([]*template.Template, 0, len(columns))
+	for _, col := range columns {
+		tmpl, err := templates.Parse(strings.TrimSpace(col))
+		if err != nil {
+			return errors.Wrap(err, "template parsing error")
+		}
+		tmpls = append(tmpls, tmpl)
+	}
+
+	var records [][]string
+	err := f(func(subContext SubContext) error {
+		record, err := renderColumns(tmpls, subContext)
+		if err != nil {
+			return errors.Wrap(err, "template parsing error")
+		}
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Render the header last, as the header functions replace the functions
+	// of the templates.
+	for _, tmpl := range tmpls {
+		tmpl.Funcs(templates.HeaderFunctions)
+	}
+	header, err := renderColumns(tmpls, sub.FullHeader())
+	if err != nil {
+		return errors.Wrap(err, "template parsing error")
+	}
+	records = append([][]string{header}, records...)
+
+	if key == TSVFormatKey {
+		return writeTSV(c.Output, records)
+	}
+	w := csv.NewWriter(c.Output)
+	if err := w.WriteAll(records); err != nil {
+		return errors.Wrap(err, "failed to write csv")
+	}
+	return nil
+}
+
+func renderColumns(tmpls []*template.Template, data any) ([]string, error) {
+	record := make([]string, 0, len(tmpls))
+	var buf bytes.Buffer
+	for _, tmpl := range tmpls {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		record = append(record, buf.String())
+	}
+	return record, nil
+}
+
+// tsvEscaper escapes the characters that cannot be part of a field of
+// tab-separated values.
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// writeTSV writes records as tab-separated values. Instead of quoting
+// fields, backslashes, tabs and newlines in fields are escaped, so that
+// each line holds one record, and each tab separates two fields.
+func writeTSV(out io.Writer, records [][]string) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		for i, field := range record {
+			if i > 0 {
+				buf.WriteByte('\t')
+			}
+			buf.WriteString(tsvEscaper.Replace(field))
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := buf.WriteTo(out)
+	return err
+}
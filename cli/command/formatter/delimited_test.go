@@ -0,0 +1,85 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestContainerDelimited(t *testing.T) {
+	containers := []types.Container{
+		{ID: "containerID1", Names: []string{"/foobar_baz"}, Image: "ubuntu", Command: `sh -c "echo hello"`, Status: "Up 5 seconds", Labels: map[string]string{"a": "1\t2"}, Created: time.Now().Add(-10 * time.Minute).Unix()},
+		{ID: "containerID2", Names: []string{"/foobar_bar"}, Image: "ubuntu", Command: "top", Status: "Exited (0) 1 minute ago", Created: time.Now().Add(-10 * time.Minute).Unix()},
+	}
+
+	testCases := []struct {
+		doc      string
+		format   Format
+		expected string
+	}{
+		{
+			doc:    "csv",
+			format: NewContainerFormat(CSVFormatKey, false, false),
+			expected: `CONTAINER ID,IMAGE,COMMAND,CREATED,STATUS,PORTS,NAMES
+containerID1,ubuntu,"""sh -c \""echo hello\""""",10 minutes ago,Up 5 seconds,,foobar_baz
+containerID2,ubuntu,"""top""",10 minutes ago,Exited (0) 1 minute ago,,foobar_bar
+`,
+		},
+		{
+			doc:    "csv with columns",
+			format: `csv {{.Names}}\t{{.Status}}`,
+			expected: `NAMES,STATUS
+foobar_baz,Up 5 seconds
+foobar_bar,Exited (0) 1 minute ago
+`,
+		},
+		{
+			doc:    "quiet",
+			format: NewContainerFormat(CSVFormatKey, true, false),
+			expected: `CONTAINER ID
+containerID1
+containerID2
+`,
+		},
+		{
+			doc:    "tsv",
+			format: `tsv {{.ID}}\t{{.Command}}\t{{.Labels}}`,
+			expected: "CONTAINER ID\tCOMMAND\tLABELS\n" +
+				"containerID1\t\"sh -c \\\\\"echo hello\\\\\"\"\ta=1\\t2\n" +
+				"containerID2\t\"top\"\t\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			var out bytes.Buffer
+			ctx := Context{Format: tc.format, Output: &out}
+			assert.NilError(t, ContainerWrite(ctx, containers))
+			assert.Check(t, is.Equal(out.String(), tc.expected))
+		})
+	}
+}
+
+func TestVolumeDelimited(t *testing.T) {
+	volumes := []*volume.Volume{{Name: "foo", Driver: "local"}, {Name: "bar,baz", Driver: "local"}}
+
+	var out bytes.Buffer
+	ctx := Context{Format: NewVolumeFormat(CSVFormatKey, false), Output: &out}
+	assert.NilError(t, VolumeWrite(ctx, volumes))
+	assert.Check(t, is.Equal(out.String(), "DRIVER,VOLUME NAME\nlocal,foo\nlocal,\"bar,baz\"\n"))
+}
+
+func TestDelimitedErrors(t *testing.T) {
+	var out bytes.Buffer
+	err := ContainerWrite(Context{Format: CSVFormatKey, Output: &out}, nil)
+	assert.Check(t, is.ErrorContains(err, "the csv format requires columns for this command"))
+
+	err = ContainerWrite(Context{Format: `tsv {{.ID}}\t{{.Nosuchfield}}`, Output: &out}, []types.Container{{ID: "containerID1"}})
+	assert.Check(t, is.ErrorContains(err, "template parsing error"))
+}
@@ -1,6 +1,7 @@
 package formatter
 
 import (
+	"strings"
 	"unicode/utf8"
 
 	"golang.org/x/text/width"
@@ -59,3 +60,10 @@ func Ellipsis(s string, maxDisplayWidth int) string {
 	}
 	return s
 }
+
+// FirstLine returns the first line of s, without leading and trailing
+// white space, such as to display a multi-line error in a single line.
+func FirstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
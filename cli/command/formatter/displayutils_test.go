@@ -29,3 +29,9 @@ func TestEllipsis(t *testing.T) {
 		assert.Check(t, is.Equal(testcase.expected, Ellipsis(testcase.source, testcase.width)))
 	}
 }
+
+func TestFirstLine(t *testing.T) {
+	assert.Check(t, is.Equal(FirstLine(""), ""))
+	assert.Check(t, is.Equal(FirstLine("task: non-zero exit (1)"), "task: non-zero exit (1)"))
+	assert.Check(t, is.Equal(FirstLine("\n  no suitable node\nmore details\n"), "no suitable node"))
+}
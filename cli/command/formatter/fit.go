@@ -0,0 +1,149 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package formatter
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Settings of the tabwriter that aligns the columns of tables.
+const (
+	tableMinWidth = 10
+	tablePadding  = 3
+)
+
+// tableWidth returns the width to fit tables in, or zero if tables should
+// not be fitted: tables are fitted if columns are truncated or wrapped, and
+// if the width is known, either from c.Width or from the terminal that
+// c.Output is connected to.
+func (c *Context) tableWidth() int {
+	if !c.Trunc && !c.Wrap {
+		return 0
+	}
+	if c.Width > 0 {
+		return c.Width
+	}
+	if out, ok := c.Output.(interface{ GetTtySize() (uint, uint) }); ok {
+		_, width := out.GetTtySize()
+		return int(width)
+	}
+	return 0
+}
+
+// fitTable fits the tab-separated rows of table in width columns, once
+// aligned by the tabwriter. The widest columns are truncated, or wrapped
+// over multiple lines if wrap is set, but never made narrower than their
+// header (the first row). If the table already fits, it's returned as-is.
+func fitTable(table *bytes.Buffer, width int, wrap bool) *bytes.Buffer {
+	lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+	rows := make([][]string, 0, len(lines))
+	var widths []int
+	for _, line := range lines {
+		cells := strings.Split(line, "\t")
+		for i, cell := range cells {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], runewidth.StringWidth(cell))
+		}
+		rows = append(rows, cells)
+	}
+	if len(widths) == 0 || alignedWidth(widths) <= width {
+		return table
+	}
+
+	minWidths := make([]int, len(widths))
+	for i := range minWidths {
+		minWidths[i] = min(widths[i], 1)
+	}
+	for i, cell := range rows[0] {
+		minWidths[i] = min(widths[i], max(runewidth.StringWidth(cell), 1))
+	}
+	for alignedWidth(widths) > width {
+		widest := -1
+		for i := range widths {
+			if widths[i] > minWidths[i] && (widest < 0 || widths[i] > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+	}
+
+	fitted := bytes.NewBufferString("")
+	for _, cells := range rows {
+		if !wrap {
+			for i, cell := range cells {
+				if runewidth.StringWidth(cell) > widths[i] {
+					cells[i] = Ellipsis(cell, widths[i])
+				}
+			}
+			fitted.WriteString(strings.Join(cells, "\t") + "\n")
+			continue
+		}
+		wrapped := make([][]string, len(cells))
+		height := 1
+		for i := range cells {
+			wrapped[i] = wrapCell(cells[i], widths[i])
+			height = max(height, len(wrapped[i]))
+		}
+		for l := 0; l < height; l++ {
+			// Lines keep all cells, even if empty, as the tabwriter only
+			// aligns a column over consecutive lines that have it.
+			line := make([]string, len(cells))
+			for i := range wrapped {
+				if l < len(wrapped[i]) {
+					line[i] = wrapped[i][l]
+				}
+			}
+			fitted.WriteString(strings.Join(line, "\t") + "\n")
+		}
+	}
+	return fitted
+}
+
+// alignedWidth returns the width of a table with the given column widths,
+// once aligned by the tabwriter. The last column isn't padded.
+func alignedWidth(widths []int) int {
+	total := widths[len(widths)-1]
+	for _, w := range widths[:len(widths)-1] {
+		total += max(w+tablePadding, tableMinWidth)
+	}
+	return total
+}
+
+// wrapCell splits s into lines of at most width columns. Lines are broken
+// after a space or a comma if possible, so that lists (such as the ports or
+// mounts of a container) are wrapped between their items.
+func wrapCell(s string, width int) []string {
+	if runewidth.StringWidth(s) <= width {
+		return []string{s}
+	}
+	var lines []string
+	for s != "" {
+		end, w, lastBreak := 0, 0, -1
+		for i, r := range s {
+			rw := runewidth.RuneWidth(r)
+			if w+rw > width && end > 0 {
+				break
+			}
+			w += rw
+			end = i + len(string(r))
+			if r == ' ' || r == ',' {
+				lastBreak = end
+			}
+		}
+		if end < len(s) && lastBreak > 0 {
+			end = lastBreak
+		}
+		lines = append(lines, strings.TrimRight(s[:end], " "))
+		s = strings.TrimLeft(s[end:], " ")
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestContainerFitWidth(t *testing.T) {
+	containers := []types.Container{
+		{ID: "containerID1", Names: []string{"/web"}, Image: "ubuntu", Command: "nginx -g 'daemon off;'", Mounts: []types.MountPoint{{Name: "data"}, {Name: "logs"}, {Name: "config"}}},
+		{ID: "containerID2", Names: []string{"/db"}, Image: "postgres", Command: "docker-entrypoint.sh postgres"},
+	}
+	format := "table {{.ID}}\t{{.Command}}\t{{.Mounts}}\t{{.Names}}"
+
+	testCases := []struct {
+		doc      string
+		trunc    bool
+		wrap     bool
+		width    int
+		expected string
+	}{
+		{
+			doc:   "fits",
+			trunc: true,
+			width: 120,
+			expected: `CONTAINER ID   COMMAND                  MOUNTS             NAMES
+containerID1   "nginx -g 'daemon of…"   data,logs,config   web
+containerID2   "docker-entrypoint.s…"                      db
+`,
+		},
+		{
+			doc:   "truncate",
+			trunc: true,
+			width: 55,
+			expected: `CONTAINER ID   COMMAND          MOUNTS            NAMES
+containerID1   "nginx -g 'da…   data,logs,conf…   web
+containerID2   "docker-entry…                     db
+`,
+		},
+		{
+			doc:   "no-trunc",
+			width: 55,
+			expected: `CONTAINER ID   COMMAND                           MOUNTS             NAMES
+containerID1   "nginx -g 'daemon off;'"          data,logs,config   web
+containerID2   "docker-entrypoint.sh postgres"                      db
+`,
+		},
+		{
+			doc:   "wrap",
+			wrap:  true,
+			width: 55,
+			expected: "CONTAINER ID   COMMAND          MOUNTS       NAMES\n" +
+				"containerID1   \"nginx -g        data,logs,   web\n" +
+				"               'daemon off;'\"   config       \n" +
+				"containerID2   \"docker-entryp                db\n" +
+				"               oint.sh                       \n" +
+				"               postgres\"                     \n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			out := bytes.NewBufferString("")
+			ctx := Context{Output: out, Format: Format(format), Trunc: tc.trunc, Wrap: tc.wrap, Width: tc.width}
+			assert.NilError(t, ContainerWrite(ctx, containers))
+			assert.Check(t, is.Equal(out.String(), tc.expected))
+		})
+	}
+}
+
+func TestWrapCell(t *testing.T) {
+	assert.Check(t, is.DeepEqual(wrapCell("short", 10), []string{"short"}))
+	assert.Check(t, is.DeepEqual(wrapCell("0.0.0.0:80->80/tcp, :::80->80/tcp", 20), []string{"0.0.0.0:80->80/tcp,", ":::80->80/tcp"}))
+	assert.Check(t, is.DeepEqual(wrapCell("sha256:0123456789abcdef", 10), []string{"sha256:012", "3456789abc", "def"}))
+}
@@ -20,9 +20,11 @@ const (
 	RawFormatKey    = "raw"
 	PrettyFormatKey = "pretty"
 	JSONFormatKey   = "json"
+	YAMLFormatKey   = "yaml"
 
 	DefaultQuietFormat = "{{.ID}}"
 	JSONFormat         = "{{json .}}"
+	YAMLFormat         = "---\n{{yaml .}}"
 )
 
 // Format is the format string rendered using the Context
@@ -38,6 +40,16 @@ func (f Format) IsJSON() bool {
 	return string(f) == JSONFormatKey
 }
 
+// IsYAML returns true if the format is the yaml format
+func (f Format) IsYAML() bool {
+	return string(f) == YAMLFormatKey
+}
+
+// IsJSONPath returns true if the format is a JSONPath expression
+func (f Format) IsJSONPath() bool {
+	return strings.HasPrefix(string(f), JSONPathFormatPrefix)
+}
+
 // Contains returns true if the format contains the substring
 func (f Format) Contains(sub string) bool {
 	return strings.Contains(string(f), sub)
@@ -49,8 +61,18 @@ type Context struct {
 	Output io.Writer
 	// Format is used to choose raw, table or custom format for the output.
 	Format Format
-	// Trunc when set to true will truncate the output of certain fields such as Container ID.
+	// Trunc when set to true will truncate the output of certain fields such as Container ID,
+	// and truncate the columns of tables that don't fit the width of the terminal.
 	Trunc bool
+	// Wrap when set to true will wrap the columns of tables that don't fit the width of the
+	// terminal over multiple lines, instead of truncating them.
+	Wrap bool
+	// Width, if set, is used as the width of the terminal to fit tables in. By default,
+	// the width of the terminal that Output is connected to is used.
+	Width int
+	// Columns, if set, selects the columns of a table format (see the
+	// "--columns" option of list commands).
+	Columns []string
 
 	// internal element
 	finalFormat string
@@ -60,12 +82,18 @@ type Context struct {
 
 func (c *Context) preFormat() {
 	c.finalFormat = string(c.Format)
+	if c.Format.IsJSONPath() {
+		// JSONPath expressions use string literals for special characters.
+		return
+	}
 	// TODO: handle this in the Format type
 	switch {
 	case c.Format.IsTable():
 		c.finalFormat = c.finalFormat[len(TableFormatKey):]
 	case c.Format.IsJSON():
 		c.finalFormat = JSONFormat
+	case c.Format.IsYAML():
+		c.finalFormat = YAMLFormat
 	}
 
 	c.finalFormat = strings.Trim(c.finalFormat, " ")
@@ -74,7 +102,11 @@ func (c *Context) preFormat() {
 }
 
 func (c *Context) parseFormat() (*template.Template, error) {
-	tmpl, err := templates.Parse(c.finalFormat)
+	format, err := ExpandJSONPath(c.finalFormat)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := templates.Parse(format)
 	if err != nil {
 		return tmpl, errors.Wrap(err, "template parsing error")
 	}
@@ -83,12 +115,15 @@ func (c *Context) parseFormat() (*template.Template, error) {
 
 func (c *Context) postFormat(tmpl *template.Template, subContext SubContext) {
 	if c.Format.IsTable() {
-		t := tabwriter.NewWriter(c.Output, 10, 1, 3, ' ', 0)
+		t := tabwriter.NewWriter(c.Output, tableMinWidth, 1, tablePadding, ' ', 0)
 		buffer := bytes.NewBufferString("")
 		tmpl.Funcs(templates.HeaderFunctions).Execute(buffer, subContext.FullHeader())
+		buffer.WriteString("\n")
+		c.buffer.WriteTo(buffer)
+		if width := c.tableWidth(); width > 0 {
+			buffer = fitTable(buffer, width, c.Wrap)
+		}
 		buffer.WriteTo(t)
-		t.Write([]byte("\n"))
-		c.buffer.WriteTo(t)
 		t.Flush()
 	} else {
 		c.buffer.WriteTo(c.Output)
@@ -112,6 +147,14 @@ type SubFormat func(func(SubContext) error) error
 // Write the template to the buffer using this Context
 func (c *Context) Write(sub SubContext, f SubFormat) error {
 	c.buffer = bytes.NewBufferString("")
+	if len(c.Columns) > 0 {
+		if err := c.selectColumns(sub); err != nil {
+			return err
+		}
+	}
+	if c.Format.IsCSV() || c.Format.IsTSV() {
+		return c.writeDelimited(sub, f)
+	}
 	c.preFormat()
 
 	tmpl, err := c.parseFormat()
@@ -19,6 +19,11 @@ func TestFormat(t *testing.T) {
 	assert.Assert(t, !f.IsJSON())
 	assert.Assert(t, f.IsTable())
 
+	f = Format("yaml")
+	assert.Assert(t, f.IsYAML())
+	assert.Assert(t, !f.IsJSON())
+	assert.Assert(t, !f.IsTable())
+
 	f = Format("other")
 	assert.Assert(t, !f.IsJSON())
 	assert.Assert(t, !f.IsTable())
@@ -49,6 +54,19 @@ func TestContext(t *testing.T) {
 			format: `table {{.Name}}`,
 			expected: `NAME
 test
+`,
+		},
+		{
+			name:   "yaml format",
+			format: YAMLFormatKey,
+			expected: `---
+Name: test
+`,
+		},
+		{
+			name:   "jsonpath format",
+			format: `jsonpath={"name: "}{.Name}`,
+			expected: `name: test
 `,
 		},
 	}
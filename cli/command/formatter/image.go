@@ -45,6 +45,8 @@ func NewImageFormat(source string, quiet bool, digest bool) Format {
 		default:
 			return defaultImageTableFormat
 		}
+	case CSVFormatKey, TSVFormatKey:
+		return NewImageFormat(TableFormatKey, quiet, digest).Delimited(source)
 	case RawFormatKey:
 		switch {
 		case quiet:
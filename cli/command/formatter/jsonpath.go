@@ -0,0 +1,295 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// JSONPathFormatPrefix is the prefix of a format that uses a JSONPath
+// expression instead of a Go template, for example
+// "jsonpath={.State.Health.Status}".
+const JSONPathFormatPrefix = "jsonpath="
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ExpandJSONPath returns the Go template that is equivalent to format if it
+// is a JSONPath format (see [JSONPathFormatPrefix]). Other formats are
+// returned unchanged.
+//
+// The supported JSONPath syntax is that of kubectl's "--output jsonpath"
+// option, without filters, slices, unions, and recursive descent:
+//
+//	{.Config.Image}                       a field
+//	{.Config.Labels['com.example.name']}  a field that is not an identifier
+//	{.Mounts[0].Source}                   an element of an array
+//	{.Mounts[*].Source}                   all elements, separated by spaces
+//	{range .Mounts[*]}{.Source}{"\n"}{end} iterate over elements
+//	{"text"}                              a literal string
+func ExpandJSONPath(format string) (string, error) {
+	expr, ok := strings.CutPrefix(format, JSONPathFormatPrefix)
+	if !ok {
+		return format, nil
+	}
+	tmpl, err := (&jsonPathParser{}).parse(expr)
+	if err != nil {
+		return "", errors.Wrap(err, "jsonpath parsing error")
+	}
+	return tmpl, nil
+}
+
+type jsonPathParser struct {
+	sb     strings.Builder
+	ranges int // number of currently open "range" actions
+	vars   int // number of variables declared for wildcards
+}
+
+func (p *jsonPathParser) parse(expr string) (string, error) {
+	for expr != "" {
+		start := strings.IndexByte(expr, '{')
+		if start < 0 {
+			p.writeText(expr)
+			break
+		}
+		p.writeText(expr[:start])
+		end, err := actionEnd(expr, start+1)
+		if err != nil {
+			return "", err
+		}
+		if err := p.action(strings.TrimSpace(expr[start+1 : end])); err != nil {
+			return "", err
+		}
+		expr = expr[end+1:]
+	}
+	if p.ranges > 0 {
+		return "", errors.New(`unclosed range: missing "{end}"`)
+	}
+	return p.sb.String(), nil
+}
+
+// actionEnd returns the position of the "}" that closes the action
+// starting at pos, ignoring braces in quoted strings.
+func actionEnd(expr string, pos int) (int, error) {
+	var quote byte
+	for i := pos; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("unclosed action: %q", expr[pos-1:])
+}
+
+// writeText writes literal text, escaping the delimiters of Go templates.
+func (p *jsonPathParser) writeText(text string) {
+	p.sb.WriteString(strings.ReplaceAll(text, "{{", `{{"{{"}}`))
+}
+
+func (p *jsonPathParser) action(action string) error {
+	switch {
+	case action == "end":
+		if p.ranges == 0 {
+			return errors.New(`unexpected "{end}" outside of a range`)
+		}
+		p.ranges--
+		p.sb.WriteString("{{end}}")
+		return nil
+	case strings.HasPrefix(action, "range "):
+		segments, root, err := parsePath(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+		if err != nil {
+			return err
+		}
+		// ranging over "{.Mounts[*]}" and "{.Mounts}" is equivalent.
+		if n := len(segments); n > 0 && segments[n-1].wildcard {
+			segments = segments[:n-1]
+		}
+		for _, s := range segments {
+			if s.wildcard {
+				return errors.Errorf("invalid range %q: wildcards are only supported at the end", action)
+			}
+		}
+		p.ranges++
+		p.sb.WriteString("{{range " + pathExpr(root, segments) + "}}")
+		return nil
+	case strings.HasPrefix(action, `"`):
+		text, err := strconv.Unquote(action)
+		if err != nil {
+			return errors.Errorf("invalid string literal %s", action)
+		}
+		p.writeText(text)
+		return nil
+	}
+
+	segments, root, err := parsePath(action)
+	if err != nil {
+		return err
+	}
+	// Each wildcard iterates over the elements it matches, and prints the
+	// results separated by spaces.
+	var closing string
+	for {
+		i := wildcardIndex(segments)
+		if i < 0 {
+			break
+		}
+		sep, elem := fmt.Sprintf("$s%d", p.vars), fmt.Sprintf("$v%d", p.vars)
+		p.vars++
+		fmt.Fprintf(&p.sb, `{{%s := ""}}{{range %s := %s}}{{%s}}{{%s = " "}}`, sep, elem, pathExpr(root, segments[:i]), sep, sep)
+		closing += "{{end}}"
+		root, segments = elem, segments[i+1:]
+	}
+	p.sb.WriteString("{{" + pathExpr(root, segments) + "}}" + closing)
+	return nil
+}
+
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func wildcardIndex(segments []pathSegment) int {
+	for i, s := range segments {
+		if s.wildcard {
+			return i
+		}
+	}
+	return -1
+}
+
+// parsePath parses a JSONPath expression, and returns its segments and the
+// template expression of the object it starts from.
+func parsePath(path string) ([]pathSegment, string, error) {
+	root := "."
+	rest := path
+	switch {
+	case strings.HasPrefix(rest, "$"):
+		root, rest = "$", rest[1:]
+	case strings.HasPrefix(rest, "@"):
+		rest = rest[1:]
+	case rest == ".":
+		return nil, root, nil
+	case !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "["):
+		return nil, "", errors.Errorf("invalid expression %q", path)
+	}
+
+	var segments []pathSegment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			if strings.HasPrefix(rest, "..") {
+				return nil, "", errors.Errorf("invalid expression %q: recursive descent is not supported", path)
+			}
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			rest = rest[end:]
+			switch key {
+			case "":
+				return nil, "", errors.Errorf("invalid expression %q: missing field name", path)
+			case "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			default:
+				segments = append(segments, pathSegment{key: key})
+			}
+		case '[':
+			end, err := subscriptEnd(rest)
+			if err != nil {
+				return nil, "", errors.Errorf("invalid expression %q: %v", path, err)
+			}
+			segment, err := parseSubscript(strings.TrimSpace(rest[1:end]))
+			if err != nil {
+				return nil, "", errors.Errorf("invalid expression %q: %v", path, err)
+			}
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+		default:
+			return nil, "", errors.Errorf("invalid expression %q", path)
+		}
+	}
+	return segments, root, nil
+}
+
+// subscriptEnd returns the position of the "]" that closes the subscript
+// at the start of s, ignoring brackets in quoted strings.
+func subscriptEnd(s string) (int, error) {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ']':
+			return i, nil
+		}
+	}
+	return 0, errors.New(`missing "]"`)
+}
+
+func parseSubscript(s string) (pathSegment, error) {
+	switch {
+	case s == "*":
+		return pathSegment{wildcard: true}, nil
+	case strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2:
+		return pathSegment{key: s[1 : len(s)-1]}, nil
+	case strings.HasPrefix(s, `"`):
+		key, err := strconv.Unquote(s)
+		if err != nil {
+			return pathSegment{}, errors.Errorf("invalid key %s", s)
+		}
+		return pathSegment{key: key}, nil
+	case strings.HasPrefix(s, "?"):
+		return pathSegment{}, errors.New("filters are not supported")
+	case strings.ContainsAny(s, ":,"):
+		return pathSegment{}, errors.New("slices and unions are not supported")
+	}
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return pathSegment{}, errors.Errorf("invalid index %q", s)
+	}
+	return pathSegment{index: index, isIndex: true}, nil
+}
+
+// pathExpr returns the template expression that evaluates segments on root.
+// Fields that are valid identifiers are accessed as fields, so that the
+// expression works on both structs and maps; other keys and indexes use
+// the "index" function.
+func pathExpr(root string, segments []pathSegment) string {
+	expr := root
+	for _, s := range segments {
+		switch {
+		case s.isIndex:
+			expr = fmt.Sprintf("(index %s %d)", expr, s.index)
+		case identifierRegexp.MatchString(s.key):
+			if expr == "." {
+				expr = ""
+			}
+			expr += "." + s.key
+		default:
+			expr = fmt.Sprintf("(index %s %s)", expr, strconv.Quote(s.key))
+		}
+	}
+	return expr
+}
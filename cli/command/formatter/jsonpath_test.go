@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/cli/templates"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+type jsonPathMount struct {
+	Source string
+}
+
+type jsonPathObject struct {
+	Name   string
+	State  map[string]any
+	Labels map[string]string
+	Mounts []jsonPathMount
+}
+
+func TestExpandJSONPath(t *testing.T) {
+	obj := jsonPathObject{
+		Name:   "/web",
+		State:  map[string]any{"Health": map[string]any{"Status": "healthy"}},
+		Labels: map[string]string{"com.example.tier": "web"},
+		Mounts: []jsonPathMount{{Source: "/data"}, {Source: "/logs"}},
+	}
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{format: `jsonpath={.Name}`, expected: `/web`},
+		{format: `jsonpath={.State.Health.Status}`, expected: `healthy`},
+		{format: `jsonpath={$.State['Health'].Status}`, expected: `healthy`},
+		{format: `jsonpath={.Labels['com.example.tier']}`, expected: `web`},
+		{format: `jsonpath={.Labels["com.example.tier"]}`, expected: `web`},
+		{format: `jsonpath={.Mounts[1].Source}`, expected: `/logs`},
+		{format: `jsonpath={.Mounts[*].Source}`, expected: `/data /logs`},
+		{format: `jsonpath={.Labels.*}`, expected: `web`},
+		{format: `jsonpath={.Mounts.*.Source}|{.Mounts[*].Source}`, expected: `/data /logs|/data /logs`},
+		{format: `jsonpath={range .Mounts[*]}{.Source}{"\n"}{end}`, expected: "/data\n/logs\n"},
+		{format: `jsonpath={range .Mounts}{@.Source},{$.Name};{end}`, expected: "/data,/web;/logs,/web;"},
+		{format: `jsonpath=name={.Name} {"{{literal}}"}`, expected: `name=/web {{literal}}`},
+		{format: `{{.Name}}`, expected: `/web`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			format, err := ExpandJSONPath(tc.format)
+			assert.NilError(t, err)
+			tmpl, err := templates.Parse(format)
+			assert.NilError(t, err, format)
+			var out bytes.Buffer
+			assert.NilError(t, tmpl.Execute(&out, obj))
+			assert.Check(t, is.Equal(out.String(), tc.expected))
+		})
+	}
+}
+
+// TestExpandJSONPathRaw verifies that expressions also work on the raw JSON
+// of an object, as used by the inspect commands.
+func TestExpandJSONPathRaw(t *testing.T) {
+	var obj map[string]any
+	err := json.Unmarshal([]byte(`{"State":{"Health":{"Status":"starting"}},"Mounts":[{"Source":"/data"}]}`), &obj)
+	assert.NilError(t, err)
+
+	format, err := ExpandJSONPath(`jsonpath={.State.Health.Status} {.Mounts[0].Source}`)
+	assert.NilError(t, err)
+	tmpl, err := templates.Parse(format)
+	assert.NilError(t, err)
+	var out bytes.Buffer
+	assert.NilError(t, tmpl.Execute(&out, obj))
+	assert.Check(t, is.Equal(out.String(), "starting /data"))
+}
+
+func TestExpandJSONPathErrors(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{format: `jsonpath={.Name`, expected: `jsonpath parsing error: unclosed action: "{.Name"`},
+		{format: `jsonpath={range .Mounts[*]}{.Source}`, expected: `jsonpath parsing error: unclosed range: missing "{end}"`},
+		{format: `jsonpath={end}`, expected: `jsonpath parsing error: unexpected "{end}" outside of a range`},
+		{format: `jsonpath={Name}`, expected: `jsonpath parsing error: invalid expression "Name"`},
+		{format: `jsonpath={..Name}`, expected: `jsonpath parsing error: invalid expression "..Name": recursive descent is not supported`},
+		{format: `jsonpath={.Mounts[?(@.Source)]}`, expected: `jsonpath parsing error: invalid expression ".Mounts[?(@.Source)]": filters are not supported`},
+		{format: `jsonpath={.Mounts[0:1]}`, expected: `jsonpath parsing error: invalid expression ".Mounts[0:1]": slices and unions are not supported`},
+		{format: `jsonpath={.Mounts[-1]}`, expected: `jsonpath parsing error: invalid expression ".Mounts[-1]": invalid index "-1"`},
+		{format: `jsonpath={.Mounts[0}`, expected: `jsonpath parsing error: invalid expression ".Mounts[0": missing "]"`},
+		{format: `jsonpath={range .Mounts[*].Source}{end}`, expected: `jsonpath parsing error: invalid range "range .Mounts[*].Source": wildcards are only supported at the end`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			_, err := ExpandJSONPath(tc.format)
+			assert.Check(t, is.Error(err, tc.expected))
+		})
+	}
+}
@@ -30,6 +30,8 @@ func NewVolumeFormat(source string, quiet bool) Format {
 			return defaultVolumeQuietFormat
 		}
 		return defaultVolumeTableFormat
+	case CSVFormatKey, TSVFormatKey:
+		return NewVolumeFormat(TableFormatKey, quiet).Delimited(source)
 	case RawFormatKey:
 		if quiet {
 			return `name: {{.Name}}`
@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/helper"
+	"github.com/spf13/cobra"
+)
+
+// NewHelperCommand returns a cobra command for `helper` subcommands
+func NewHelperCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "helper",
+		Short: "Manage background helpers",
+		Long: `Manage the helpers that the CLI runs in the background, for features that
+keep state between commands. Helpers are started when they're first used, and
+exit once they're idle.`,
+		Args: cli.NoArgs,
+		RunE: command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newListCommand(dockerCli),
+		newServeCommand(),
+		newStopCommand(dockerCli),
+	)
+	return cmd
+}
+
+// newServeCommand returns the command that the CLI runs helpers with.
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:    "serve NAME",
+		Short:  "Run a helper in the foreground",
+		Args:   cli.ExactArgs(1),
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return helper.Serve(cmd.Context(), args[0])
+		},
+	}
+}
+
+// completeRunning offers completion for the names of the running helpers
+func completeRunning(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	states, err := helper.List(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(states))
+	for _, s := range states {
+		names = append(names, s.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/helper"
+	"github.com/docker/go-units"
+)
+
+const (
+	defaultHelperTableFormat = "table {{.Name}}\t{{.PID}}\t{{.Started}}\t{{.Description}}"
+
+	helperNameHeader        = "NAME"
+	helperPIDHeader         = "PID"
+	helperStartedHeader     = "STARTED"
+	helperDescriptionHeader = "DESCRIPTION"
+)
+
+func newFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return `{{.Name}}`
+		}
+		return defaultHelperTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\npid: {{.PID}}\nstarted: {{.Started}}\ndescription: {{.Description}}\n`
+	}
+	return formatter.Format(source)
+}
+
+func formatWrite(ctx formatter.Context, states []helper.State) error {
+	descriptions := map[string]string{}
+	for _, h := range helper.Registered() {
+		descriptions[h.Name] = h.Description
+	}
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, s := range states {
+			if err := format(&helperContext{s: s, description: descriptions[s.Name]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	helperCtx := helperContext{}
+	helperCtx.Header = formatter.SubHeaderContext{
+		"Name":        helperNameHeader,
+		"PID":         helperPIDHeader,
+		"Started":     helperStartedHeader,
+		"Description": helperDescriptionHeader,
+	}
+	return ctx.Write(&helperCtx, render)
+}
+
+type helperContext struct {
+	formatter.HeaderContext
+	s           helper.State
+	description string
+}
+
+func (c *helperContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *helperContext) Name() string {
+	return c.s.Name
+}
+
+func (c *helperContext) PID() string {
+	return strconv.Itoa(c.s.PID)
+}
+
+func (c *helperContext) Started() string {
+	return units.HumanDuration(time.Now().UTC().Sub(c.s.StartedAt)) + " ago"
+}
+
+func (c *helperContext) Socket() string {
+	return c.s.Socket
+}
+
+func (c *helperContext) Description() string {
+	return c.description
+}
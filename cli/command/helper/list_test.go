@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"context"
+	"io"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/helper"
+	"github.com/docker/cli/internal/test"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/poll"
+)
+
+func init() {
+	helper.Register(helper.Helper{
+		Name:        "test",
+		Description: "Test helper",
+		Serve:       func(context.Context, net.Conn) {},
+	})
+}
+
+// startHelper serves the test helper in the test process, until the test
+// ends.
+func startHelper(t *testing.T) <-chan error {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("helpers are stopped with signals")
+	}
+	config.SetDir(t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- helper.Serve(ctx, "test") }()
+	t.Cleanup(cancel)
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		states, err := helper.List(ctx)
+		if err != nil {
+			return poll.Error(err)
+		}
+		if len(states) == 0 {
+			return poll.Continue("helper isn't running")
+		}
+		return poll.Success()
+	}, poll.WithTimeout(10*time.Second))
+	return errCh
+}
+
+func runCommand(cmd *cobra.Command, args ...string) error {
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+func TestList(t *testing.T) {
+	startHelper(t)
+	cli := test.NewFakeCli(nil)
+
+	assert.NilError(t, runCommand(newListCommand(cli), "--format", "{{.Name}}: {{.Description}}"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "test: Test helper\n"))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runCommand(newListCommand(cli)))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "NAME      PID"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), " ago   Test helper\n"))
+}
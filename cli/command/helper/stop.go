@@ -0,0 +1,86 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/helper"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// stopTimeout is the time that helpers may take to stop.
+const stopTimeout = 10 * time.Second
+
+type stopOptions struct {
+	names []string
+	all   bool
+}
+
+func newStopCommand(dockerCli command.Cli) *cobra.Command {
+	var opts stopOptions
+
+	cmd := &cobra.Command{
+		Use:   "stop [OPTIONS] [HELPER...]",
+		Short: "Stop one or more running helpers",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if opts.all {
+				return cli.NoArgs(cmd, args)
+			}
+			return cli.RequiresMinArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runStop(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completeRunning,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.all, "all", "a", false, "Stop all running helpers")
+
+	return cmd
+}
+
+func runStop(ctx context.Context, dockerCli command.Cli, opts stopOptions) error {
+	states, err := helper.List(ctx)
+	if err != nil {
+		return err
+	}
+	running := make(map[string]helper.State, len(states))
+	for _, s := range states {
+		running[s.Name] = s
+	}
+
+	var errs []string
+	var list []helper.State
+	for _, name := range opts.names {
+		s, ok := running[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("helper %q is not running", name))
+			continue
+		}
+		list = append(list, s)
+	}
+	if opts.all {
+		list = states
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, stopTimeout)
+	defer cancel()
+	for _, s := range list {
+		if err := helper.Stop(ctx, s); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		_, _ = fmt.Fprintln(dockerCli.Out(), s.Name)
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
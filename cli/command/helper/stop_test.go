@@ -0,0 +1,24 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStop(t *testing.T) {
+	errCh := startHelper(t)
+	cli := test.NewFakeCli(nil)
+
+	err := runCommand(newStopCommand(cli), "test", "unknown")
+	assert.Check(t, is.Error(err, `helper "unknown" is not running`))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "test\n"))
+	assert.NilError(t, <-errCh)
+
+	err = runCommand(newStopCommand(cli))
+	assert.Check(t, is.ErrorContains(err, `"stop" requires at least 1 argument.`))
+	assert.Check(t, is.ErrorContains(runCommand(newStopCommand(cli), "--all", "test"), `"stop" accepts no arguments.`))
+	assert.NilError(t, runCommand(newStopCommand(cli), "--all"))
+}
@@ -0,0 +1,224 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// Labels with the keys of the OCI pre-defined annotations, as defined in the
+// image-spec, which image builders set as labels on images:
+// https://github.com/opencontainers/image-spec/blob/v1.1.0/annotations.md#pre-defined-annotation-keys
+const (
+	labelCreated       = "org.opencontainers.image.created"
+	labelAuthors       = "org.opencontainers.image.authors"
+	labelURL           = "org.opencontainers.image.url"
+	labelDocumentation = "org.opencontainers.image.documentation"
+	labelSource        = "org.opencontainers.image.source"
+	labelVersion       = "org.opencontainers.image.version"
+	labelRevision      = "org.opencontainers.image.revision"
+	labelVendor        = "org.opencontainers.image.vendor"
+	labelLicenses      = "org.opencontainers.image.licenses"
+	labelTitle         = "org.opencontainers.image.title"
+	labelDescription   = "org.opencontainers.image.description"
+)
+
+type aboutOptions struct {
+	image  string
+	format string
+}
+
+// imageAbout is the information presented by "docker image about".
+type imageAbout struct {
+	Image         string
+	ID            string
+	Title         string
+	Description   string
+	Version       string
+	Vendor        string
+	Authors       string
+	Licenses      string
+	URL           string
+	Documentation string
+	Source        string
+	Revision      string
+	Created       string
+	Platform      string
+	Size          int64
+	User          string
+	WorkingDir    string
+	Entrypoint    []string
+	Cmd           []string
+	ExposedPorts  []string
+	Warnings      []string
+}
+
+// newAboutCommand creates a new cobra.Command for `docker image about`
+func newAboutCommand(dockerCli command.Cli) *cobra.Command {
+	var opts aboutOptions
+
+	cmd := &cobra.Command{
+		Use:   "about [OPTIONS] IMAGE",
+		Short: "Show license, provenance, and other metadata of an image",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runAbout(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.ImageNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	return cmd
+}
+
+func runAbout(ctx context.Context, dockerCli command.Cli, opts aboutOptions) error {
+	img, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, opts.image)
+	if err != nil {
+		return err
+	}
+	about := newImageAbout(opts.image, img)
+
+	if opts.format != "" {
+		format := opts.format
+		switch format {
+		case formatter.JSONFormatKey:
+			format = formatter.JSONFormat
+		case formatter.YAMLFormatKey:
+			format = formatter.YAMLFormat
+		}
+		format, err := formatter.ExpandJSONPath(format)
+		if err != nil {
+			return cli.StatusError{StatusCode: 64, Status: err.Error()}
+		}
+		tmpl, err := templates.Parse(format)
+		if err != nil {
+			return cli.StatusError{
+				StatusCode: 64,
+				Status:     "template parsing error: " + err.Error(),
+			}
+		}
+		if err := tmpl.Execute(dockerCli.Out(), about); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(dockerCli.Out())
+		return nil
+	}
+
+	prettyPrintAbout(dockerCli.Out(), about)
+	for _, w := range about.Warnings {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "WARNING:", w)
+	}
+	return nil
+}
+
+func newImageAbout(ref string, img types.ImageInspect) imageAbout {
+	var labels map[string]string
+	about := imageAbout{
+		Image:        ref,
+		ID:           img.ID,
+		Size:         img.Size,
+		Platform:     img.Os + "/" + img.Architecture,
+		Created:      img.Created,
+		Entrypoint:   []string{},
+		Cmd:          []string{},
+		ExposedPorts: []string{},
+		Warnings:     []string{},
+	}
+	if img.Variant != "" {
+		about.Platform += "/" + img.Variant
+	}
+	if img.Config != nil {
+		labels = img.Config.Labels
+		about.User = img.Config.User
+		about.WorkingDir = img.Config.WorkingDir
+		about.Entrypoint = append(about.Entrypoint, img.Config.Entrypoint...)
+		about.Cmd = append(about.Cmd, img.Config.Cmd...)
+		for p := range img.Config.ExposedPorts {
+			about.ExposedPorts = append(about.ExposedPorts, string(p))
+		}
+		sort.Strings(about.ExposedPorts)
+	}
+
+	about.Title = labels[labelTitle]
+	about.Description = labels[labelDescription]
+	about.Version = labels[labelVersion]
+	about.Vendor = labels[labelVendor]
+	about.Authors = labels[labelAuthors]
+	about.Licenses = labels[labelLicenses]
+	about.URL = labels[labelURL]
+	about.Documentation = labels[labelDocumentation]
+	about.Source = labels[labelSource]
+	about.Revision = labels[labelRevision]
+	if created := labels[labelCreated]; created != "" {
+		about.Created = created
+	}
+
+	if about.Source == "" {
+		about.Warnings = append(about.Warnings, "no source repository ("+labelSource+") label; the origin of this image cannot be determined")
+	}
+	if about.Revision == "" {
+		about.Warnings = append(about.Warnings, "no source revision ("+labelRevision+") label; this image cannot be traced to a specific commit")
+	}
+	if about.Licenses == "" {
+		about.Warnings = append(about.Warnings, "no license ("+labelLicenses+") label")
+	}
+	return about
+}
+
+func prettyPrintAbout(out io.Writer, about imageAbout) {
+	line := func(a ...any) {
+		_, _ = fmt.Fprintln(out, a...)
+	}
+	optional := func(label, value string) {
+		if value != "" {
+			line(label, value)
+		}
+	}
+
+	line("Image:", about.Image)
+	line(" ID:           ", about.ID)
+	optional(" Title:        ", about.Title)
+	optional(" Description:  ", about.Description)
+	optional(" Version:      ", about.Version)
+	optional(" Vendor:       ", about.Vendor)
+	optional(" Authors:      ", about.Authors)
+	optional(" Licenses:     ", about.Licenses)
+	optional(" URL:          ", about.URL)
+	optional(" Documentation:", about.Documentation)
+	line()
+	line("Provenance:")
+	line(" Source:       ", valueOrNone(about.Source))
+	line(" Revision:     ", valueOrNone(about.Revision))
+	optional(" Created:      ", about.Created)
+	line()
+	line("Config:")
+	line(" Platform:     ", about.Platform)
+	line(" Size:         ", units.HumanSizeWithPrecision(float64(about.Size), 3))
+	optional(" User:         ", about.User)
+	optional(" Working Dir:  ", about.WorkingDir)
+	optional(" Entrypoint:   ", strings.Join(about.Entrypoint, " "))
+	optional(" Cmd:          ", strings.Join(about.Cmd, " "))
+	optional(" Exposed Ports:", strings.Join(about.ExposedPorts, ", "))
+}
+
+// valueOrNone returns v, or "<none>" if v is empty.
+func valueOrNone(v string) string {
+	if v == "" {
+		return "<none>"
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestNewAboutCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedError    string
+		imageInspectFunc func(image string) (types.ImageInspect, []byte, error)
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{},
+			expectedError: "requires exactly 1 argument",
+		},
+		{
+			name:          "inspect-error",
+			args:          []string{"image"},
+			expectedError: "no such image",
+			imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+				return types.ImageInspect{}, nil, fmt.Errorf("no such image")
+			},
+		},
+		{
+			name:          "invalid-format",
+			args:          []string{"--format", "{{invalid", "image"},
+			expectedError: "template parsing error",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newAboutCommand(test.NewFakeCli(&fakeClient{imageInspectFunc: tc.imageInspectFunc}))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestNewAboutCommandSuccess(t *testing.T) {
+	annotated := types.ImageInspect{
+		ID:           "sha256:0ab2c7e0e26c2b6ee43ad3bd0cf2a3d5e2b5773e1f1cda97d2b8e0dee0c938dc",
+		Created:      "2024-06-01T10:00:00Z",
+		Os:           "linux",
+		Architecture: "arm64",
+		Variant:      "v8",
+		Size:         12345678,
+		Config: &container.Config{
+			User:         "nobody",
+			WorkingDir:   "/app",
+			Entrypoint:   []string{"/usr/bin/app"},
+			Cmd:          []string{"--serve"},
+			ExposedPorts: nat.PortSet{"8080/tcp": {}, "443/tcp": {}},
+			Labels: map[string]string{
+				"org.opencontainers.image.title":         "app",
+				"org.opencontainers.image.description":   "An example application",
+				"org.opencontainers.image.version":       "1.2.3",
+				"org.opencontainers.image.vendor":        "Example Inc.",
+				"org.opencontainers.image.licenses":      "Apache-2.0",
+				"org.opencontainers.image.documentation": "https://example.com/docs",
+				"org.opencontainers.image.source":        "https://github.com/example/app",
+				"org.opencontainers.image.revision":      "5b4f1bbf0c3e",
+				"org.opencontainers.image.created":       "2024-06-01T09:59:00Z",
+			},
+		},
+	}
+	bare := types.ImageInspect{
+		ID:           "sha256:a0f1b7e8e0a2b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d",
+		Created:      "2024-01-01T00:00:00Z",
+		Os:           "linux",
+		Architecture: "amd64",
+		Size:         1000,
+	}
+	testCases := []struct {
+		name     string
+		args     []string
+		image    types.ImageInspect
+		warnings bool
+	}{
+		{
+			name:  "annotated",
+			args:  []string{"example/app:1.2.3"},
+			image: annotated,
+		},
+		{
+			name:     "missing-provenance",
+			args:     []string{"bare"},
+			image:    bare,
+			warnings: true,
+		},
+		{
+			name:  "format",
+			args:  []string{"--format", "{{.Licenses}} {{.Source}}@{{.Revision}}", "example/app:1.2.3"},
+			image: annotated,
+		},
+		{
+			name:  "format-json",
+			args:  []string{"--format", "json", "bare"},
+			image: bare,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageInspectFunc: func(img string) (types.ImageInspect, []byte, error) {
+					assert.Check(t, is.Equal(img, tc.args[len(tc.args)-1]))
+					return tc.image, nil, nil
+				},
+			})
+			cmd := newAboutCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("about-command-success.%s.golden", tc.name))
+			if tc.warnings {
+				golden.Assert(t, cli.ErrBuffer().String(), fmt.Sprintf("about-command-success.%s.stderr.golden", tc.name))
+			} else {
+				assert.Check(t, is.Equal(cli.ErrBuffer().String(), ""))
+			}
+		})
+	}
+}
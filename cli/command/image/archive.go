@@ -0,0 +1,361 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxArchiveMetadataSize is the maximum size of the files of an image
+	// archive that are read as metadata (indexes, manifests, and configs).
+	maxArchiveMetadataSize = 4 << 20
+
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerImageConfig  = "application/vnd.docker.container.image.v1+json"
+
+	// Annotations of the attestation manifests that BuildKit attaches to the
+	// images of an image index.
+	annotationReferenceType   = "vnd.docker.reference.type"
+	annotationReferenceDigest = "vnd.docker.reference.digest"
+	attestationManifestType   = "attestation-manifest"
+)
+
+// archiveFilter selects the content of an image archive (as produced by
+// "docker save") to keep.
+type archiveFilter struct {
+	// platform, if set, is the only platform of multi-platform images that
+	// is kept.
+	platform *ocispec.Platform
+	// excludeLayers are the layers that are excluded, either by the digest
+	// of their blob, or by the digest of their uncompressed content (their
+	// "diff ID").
+	excludeLayers map[digest.Digest]bool
+}
+
+func (f archiveFilter) empty() bool {
+	return f.platform == nil && len(f.excludeLayers) == 0
+}
+
+// archiveManifestEntry is an entry of the "manifest.json" file of an image
+// archive, which lists the configs and layers of the images it contains.
+type archiveManifestEntry struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// imageConfig is the part of the config of an image that's needed to filter
+// its layers and platform.
+type imageConfig struct {
+	ocispec.Platform
+	RootFS ocispec.RootFS `json:"rootfs"`
+}
+
+// archiveContent is the metadata of an image archive.
+type archiveContent struct {
+	files map[string][]byte
+	// drop are the files that are removed from the archive.
+	drop map[string]bool
+	// manifest is the rewritten "manifest.json" file, if it has changed.
+	manifest []byte
+}
+
+// filterArchive copies the image archive src to dst, without the content that
+// f excludes: the manifests of the other platforms than f.platform, and their
+// configs and layers; and the excluded layers, which are still listed in the
+// manifests and configs of the images, so that the archive can be loaded by a
+// daemon that already has these layers.
+func filterArchive(src io.ReadSeeker, dst io.Writer, f archiveFilter) error {
+	c, err := readArchiveMetadata(src)
+	if err != nil {
+		return err
+	}
+	if err := c.filter(f); err != nil {
+		return err
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read image archive")
+		}
+		name := path.Clean(hdr.Name)
+		if c.drop[name] {
+			continue
+		}
+		if name == "manifest.json" && c.manifest != nil {
+			hdr.Size = int64(len(c.manifest))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(c.manifest); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// readArchiveMetadata reads the JSON files of the archive src that could be
+// metadata: files larger than maxArchiveMetadataSize, or that aren't JSON
+// objects, are layers.
+func readArchiveMetadata(src io.Reader) (*archiveContent, error) {
+	c := &archiveContent{files: map[string][]byte{}, drop: map[string]bool{}}
+	tr := tar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read image archive")
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > maxArchiveMetadataSize {
+			continue
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read image archive")
+		}
+		if b = bytes.TrimSpace(b); len(b) > 0 && (b[0] == '{' || b[0] == '[') {
+			c.files[path.Clean(hdr.Name)] = b
+		}
+	}
+}
+
+// filter marks the files that f excludes.
+func (c *archiveContent) filter(f archiveFilter) error {
+	// kept and excluded are the blobs of the manifests that are kept, and
+	// of the manifests of the other platforms, which are dropped unless a
+	// kept manifest shares them.
+	kept, excluded := map[string]bool{}, map[string]bool{}
+
+	if b, ok := c.files["index.json"]; ok {
+		var index ocispec.Index
+		if err := json.Unmarshal(b, &index); err != nil {
+			return errors.Wrap(err, "invalid index.json in image archive")
+		}
+		for _, desc := range index.Manifests {
+			found, err := c.walk(desc, f, kept, excluded, true)
+			if err != nil {
+				return err
+			}
+			if !found && f.platform != nil {
+				return errors.Errorf("image %s has no variant for platform %s", descriptorName(desc), platforms.Format(*f.platform))
+			}
+		}
+	}
+
+	if b, ok := c.files["manifest.json"]; ok {
+		// Entries are decoded for their configs and layers, but the entries
+		// that are kept are written as-is, with their other fields.
+		var entries []json.RawMessage
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return errors.Wrap(err, "invalid manifest.json in image archive")
+		}
+		filtered := make([]json.RawMessage, 0, len(entries))
+		for _, raw := range entries {
+			var e archiveManifestEntry
+			if err := json.Unmarshal(raw, &e); err != nil {
+				return errors.Wrap(err, "invalid manifest.json in image archive")
+			}
+			if excluded[path.Clean(e.Config)] && !kept[path.Clean(e.Config)] {
+				continue
+			}
+			config, err := c.config(e.Config)
+			if err != nil {
+				return err
+			}
+			if f.platform != nil && config.OS != "" && !platforms.NewMatcher(*f.platform).Match(config.Platform) {
+				return errors.Errorf("image %s is for platform %s, not %s", archiveEntryName(e), platforms.Format(config.Platform), platforms.Format(*f.platform))
+			}
+			for i, l := range e.Layers {
+				var diffID digest.Digest
+				if i < len(config.RootFS.DiffIDs) {
+					diffID = config.RootFS.DiffIDs[i]
+				}
+				if f.excludeLayers[diffID] || f.excludeLayers[blobDigest(l)] {
+					c.drop[path.Clean(l)] = true
+				}
+			}
+			filtered = append(filtered, raw)
+		}
+		if len(filtered) != len(entries) {
+			m, err := json.Marshal(filtered)
+			if err != nil {
+				return err
+			}
+			c.manifest = m
+		}
+	}
+
+	for name := range excluded {
+		if !kept[name] {
+			c.drop[name] = true
+		}
+	}
+	return nil
+}
+
+// walk walks the manifests of desc, and adds the blobs of the manifests that
+// f keeps to kept, and the others to excluded. It returns whether a manifest
+// of desc is kept.
+func (c *archiveContent) walk(desc ocispec.Descriptor, f archiveFilter, kept, excluded map[string]bool, keep bool) (bool, error) {
+	name := blobPath(desc.Digest)
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageIndex, mediaTypeDockerManifestList:
+		b, ok := c.files[name]
+		if !ok {
+			// The index isn't in the archive, so neither are its manifests.
+			return true, nil
+		}
+		var index ocispec.Index
+		if err := json.Unmarshal(b, &index); err != nil {
+			return false, errors.Wrapf(err, "invalid image index %s in image archive", desc.Digest)
+		}
+		markBlob(name, keep, kept, excluded)
+
+		var found bool
+		keptManifests := map[string]bool{}
+		for _, m := range index.Manifests {
+			if m.Annotations[annotationReferenceType] == attestationManifestType {
+				continue
+			}
+			k := keep && (f.platform == nil || m.Platform == nil || platforms.NewMatcher(*f.platform).Match(*m.Platform))
+			if k {
+				keptManifests[m.Digest.String()] = true
+			}
+			ok, err := c.walk(m, f, kept, excluded, k)
+			if err != nil {
+				return false, err
+			}
+			found = found || (k && ok)
+		}
+		// Attestations are kept with the manifests that they're attached to.
+		for _, m := range index.Manifests {
+			if m.Annotations[annotationReferenceType] == attestationManifestType {
+				if _, err := c.walk(m, f, kept, excluded, keep && keptManifests[m.Annotations[annotationReferenceDigest]]); err != nil {
+					return false, err
+				}
+			}
+		}
+		return found, nil
+	case ocispec.MediaTypeImageManifest, mediaTypeDockerManifest:
+		b, ok := c.files[name]
+		if !ok {
+			// Manifests of other platforms than the ones the daemon has may
+			// be missing.
+			return false, nil
+		}
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(b, &manifest); err != nil {
+			return false, errors.Wrapf(err, "invalid image manifest %s in image archive", desc.Digest)
+		}
+		configName := blobPath(manifest.Config.Digest)
+		config, err := c.config(configName)
+		if err != nil {
+			return false, err
+		}
+		if keep && f.platform != nil && desc.Platform == nil && config.OS != "" && !platforms.NewMatcher(*f.platform).Match(config.Platform) {
+			// Single-platform images only have the platform in their config.
+			keep = false
+		}
+		markBlob(name, keep, kept, excluded)
+		markBlob(configName, keep, kept, excluded)
+
+		for i, l := range manifest.Layers {
+			var diffID digest.Digest
+			if i < len(config.RootFS.DiffIDs) {
+				diffID = config.RootFS.DiffIDs[i]
+			}
+			if keep && (f.excludeLayers[l.Digest] || f.excludeLayers[diffID]) {
+				c.drop[blobPath(l.Digest)] = true
+				continue
+			}
+			markBlob(blobPath(l.Digest), keep, kept, excluded)
+		}
+		return keep, nil
+	default:
+		return true, nil
+	}
+}
+
+// config returns the config at name in the archive, or an empty config if
+// the archive doesn't have it.
+func (c *archiveContent) config(name string) (imageConfig, error) {
+	var config imageConfig
+	b, ok := c.files[path.Clean(name)]
+	if !ok {
+		return config, nil
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return config, errors.Wrapf(err, "invalid image config %s in image archive", name)
+	}
+	return config, nil
+}
+
+func markBlob(name string, keep bool, kept, excluded map[string]bool) {
+	if keep {
+		kept[name] = true
+	} else {
+		excluded[name] = true
+	}
+}
+
+func blobPath(dgst digest.Digest) string {
+	return path.Join("blobs", dgst.Algorithm().String(), dgst.Encoded())
+}
+
+// blobDigest returns the digest of the blob at name in the archive, or an
+// empty digest if name isn't a blob.
+func blobDigest(name string) digest.Digest {
+	parts := strings.Split(path.Clean(name), "/")
+	if len(parts) != 3 || parts[0] != "blobs" {
+		return ""
+	}
+	return digest.NewDigestFromEncoded(digest.Algorithm(parts[1]), parts[2])
+}
+
+// descriptorName returns the name of the image of a descriptor of the index
+// of an image archive, or its digest if it has no name.
+func descriptorName(desc ocispec.Descriptor) string {
+	if name := desc.Annotations["io.containerd.image.name"]; name != "" {
+		return name
+	}
+	if name := desc.Annotations[ocispec.AnnotationRefName]; name != "" {
+		return name
+	}
+	return desc.Digest.String()
+}
+
+func archiveEntryName(e archiveManifestEntry) string {
+	if len(e.RepoTags) > 0 {
+		return e.RepoTags[0]
+	}
+	return e.Config
+}
@@ -0,0 +1,220 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testArchive is an image archive with a multi-platform image, as saved from
+// the containerd image store.
+type testArchive struct {
+	files map[string][]byte
+	// blobs are the paths of the blobs of the archive, by name.
+	blobs map[string]string
+}
+
+func (a *testArchive) addBlob(name string, content []byte) ocispec.Descriptor {
+	dgst := digest.FromBytes(content)
+	a.files[blobPath(dgst)] = content
+	a.blobs[name] = blobPath(dgst)
+	return ocispec.Descriptor{Digest: dgst, Size: int64(len(content))}
+}
+
+func (a *testArchive) addJSON(t *testing.T, name string, v any) ocispec.Descriptor {
+	t.Helper()
+	b, err := json.Marshal(v)
+	assert.NilError(t, err)
+	return a.addBlob(name, b)
+}
+
+// addManifest adds a manifest for platform, with a base layer that's shared
+// by all platforms, and a layer of its own.
+func (a *testArchive) addManifest(t *testing.T, platform ocispec.Platform) ocispec.Descriptor {
+	t.Helper()
+	base := a.addBlob("base-layer", []byte("compressed base layer"))
+	own := a.addBlob(platform.Architecture+"-layer", []byte("compressed "+platform.Architecture+" layer"))
+	config := a.addJSON(t, platform.Architecture+"-config", ocispec.Image{
+		Platform: platform,
+		RootFS: ocispec.RootFS{Type: "layers", DiffIDs: []digest.Digest{
+			digest.FromString("base layer"),
+			digest.FromString(platform.Architecture + " layer"),
+		}},
+	})
+	config.MediaType = ocispec.MediaTypeImageConfig
+	base.MediaType, own.MediaType = ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerGzip
+	manifest := a.addJSON(t, platform.Architecture+"-manifest", ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{base, own},
+	})
+	manifest.MediaType = ocispec.MediaTypeImageManifest
+	manifest.Platform = &platform
+	return manifest
+}
+
+func newTestArchive(t *testing.T) *testArchive {
+	t.Helper()
+	a := &testArchive{files: map[string][]byte{}, blobs: map[string]string{}}
+	amd64 := a.addManifest(t, ocispec.Platform{OS: "linux", Architecture: "amd64"})
+	arm64 := a.addManifest(t, ocispec.Platform{OS: "linux", Architecture: "arm64"})
+	attestation := a.addJSON(t, "arm64-attestation", ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    a.addBlob("arm64-attestation-config", []byte("{}")),
+	})
+	attestation.MediaType = ocispec.MediaTypeImageManifest
+	attestation.Platform = &ocispec.Platform{OS: "unknown", Architecture: "unknown"}
+	attestation.Annotations = map[string]string{
+		annotationReferenceType:   attestationManifestType,
+		annotationReferenceDigest: arm64.Digest.String(),
+	}
+
+	index := a.addJSON(t, "index", ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{amd64, arm64, attestation},
+	})
+	index.MediaType = ocispec.MediaTypeImageIndex
+	index.Annotations = map[string]string{"io.containerd.image.name": "docker.io/library/example:latest"}
+	b, err := json.Marshal(ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{index}})
+	assert.NilError(t, err)
+	a.files["index.json"] = b
+
+	b, err = json.Marshal([]map[string]any{{
+		"Config":       a.blobs["amd64-config"],
+		"RepoTags":     []string{"example:latest"},
+		"Layers":       []string{a.blobs["base-layer"], a.blobs["amd64-layer"]},
+		"LayerSources": map[string]any{},
+	}})
+	assert.NilError(t, err)
+	a.files["manifest.json"] = b
+	a.files["oci-layout"] = []byte(`{"imageLayoutVersion": "1.0.0"}`)
+	return a
+}
+
+func (a *testArchive) tar(t *testing.T) *bytes.Reader {
+	t.Helper()
+	names := make([]string, 0, len(a.files))
+	for name := range a.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(a.files[name])), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(a.files[name])
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+// names returns the names of the blobs of the archive that are in the
+// filtered archive b, and its manifest.json.
+func (a *testArchive) names(t *testing.T, b []byte) ([]string, string) {
+	t.Helper()
+	files := map[string]string{}
+	var manifest string
+	tr := tar.NewReader(bytes.NewReader(b))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = string(content)
+		if hdr.Name == "manifest.json" {
+			manifest = string(content)
+		}
+	}
+	var names []string
+	for name, p := range a.blobs {
+		if _, ok := files[p]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, manifest
+}
+
+func TestFilterArchive(t *testing.T) {
+	a := newTestArchive(t)
+	testCases := []struct {
+		doc              string
+		filter           archiveFilter
+		expectedBlobs    []string
+		expectedManifest string
+		expectedError    string
+	}{
+		{
+			doc:    "platform",
+			filter: archiveFilter{platform: &ocispec.Platform{OS: "linux", Architecture: "arm64"}},
+			expectedBlobs: []string{
+				"arm64-attestation", "arm64-attestation-config", "arm64-config", "arm64-layer", "arm64-manifest",
+				"base-layer", "index",
+			},
+			expectedManifest: "[]",
+		},
+		{
+			doc:    "platform of manifest.json",
+			filter: archiveFilter{platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"}},
+			expectedBlobs: []string{
+				"amd64-config", "amd64-layer", "amd64-manifest",
+				"base-layer", "index",
+			},
+			expectedManifest: string(a.files["manifest.json"]),
+		},
+		{
+			doc:           "missing platform",
+			filter:        archiveFilter{platform: &ocispec.Platform{OS: "windows", Architecture: "amd64"}},
+			expectedError: "image docker.io/library/example:latest has no variant for platform windows/amd64",
+		},
+		{
+			doc:    "exclude layer by diff ID",
+			filter: archiveFilter{excludeLayers: map[digest.Digest]bool{digest.FromString("base layer"): true}},
+			expectedBlobs: []string{
+				"amd64-config", "amd64-layer", "amd64-manifest",
+				"arm64-attestation", "arm64-attestation-config", "arm64-config", "arm64-layer", "arm64-manifest",
+				"index",
+			},
+			expectedManifest: string(a.files["manifest.json"]),
+		},
+		{
+			doc: "exclude layer by digest",
+			filter: archiveFilter{
+				platform:      &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+				excludeLayers: map[digest.Digest]bool{digest.FromString("compressed amd64 layer"): true},
+			},
+			expectedBlobs: []string{
+				"amd64-config", "amd64-manifest",
+				"base-layer", "index",
+			},
+			expectedManifest: string(a.files["manifest.json"]),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			var out bytes.Buffer
+			err := filterArchive(a.tar(t), &out, tc.filter)
+			if tc.expectedError != "" {
+				assert.Check(t, is.Error(err, tc.expectedError))
+				return
+			}
+			assert.NilError(t, err)
+			blobs, manifest := a.names(t, out.Bytes())
+			assert.Check(t, is.DeepEqual(blobs, tc.expectedBlobs))
+			assert.Check(t, is.Equal(manifest, tc.expectedManifest))
+		})
+	}
+}
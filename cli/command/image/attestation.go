@@ -0,0 +1,209 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// annotationPredicateType is the annotation of the layers of attestation
+	// manifests with the predicate type of their in-toto statement.
+	annotationPredicateType = "in-toto.io/predicate-type"
+	// artifactTypeInToto is the artifact type of the attestations that are
+	// attached to images as referrers.
+	artifactTypeInToto = "application/vnd.in-toto+json"
+
+	attestationProvenance = "provenance"
+	attestationSBOM       = "sbom"
+)
+
+// attestation is an in-toto attestation attached to an image.
+type attestation struct {
+	// Platform is the platform of the image that the attestation is attached
+	// to, if the image is part of a multi-platform image.
+	Platform string
+	// Subject is the digest of the manifest of the image that the
+	// attestation is attached to.
+	Subject digest.Digest
+	// Kind is "provenance" or "sbom", or empty for other attestations.
+	Kind string
+	// PredicateType is the predicate type of the in-toto statement.
+	PredicateType string
+	// Statement is the descriptor of the blob of the in-toto statement.
+	Statement ocispec.Descriptor `json:"-"`
+}
+
+// attestedImage is the manifest of an image, and the attestations attached
+// to it.
+type attestedImage struct {
+	Platform     string
+	Digest       digest.Digest
+	Attestations []attestation
+}
+
+// attestationKind returns the kind of the attestations with a predicate type.
+func attestationKind(predicateType string) string {
+	switch {
+	case strings.HasPrefix(predicateType, "https://slsa.dev/provenance/"):
+		return attestationProvenance
+	case strings.HasPrefix(predicateType, "https://spdx.dev/Document"), strings.HasPrefix(predicateType, "https://cyclonedx.org/bom"):
+		return attestationSBOM
+	}
+	return ""
+}
+
+func isIndex(mediaType string) bool {
+	return mediaType == ocispec.MediaTypeImageIndex || mediaType == mediaTypeDockerManifestList
+}
+
+func isAttestationManifest(desc ocispec.Descriptor) bool {
+	return desc.Annotations[annotationReferenceType] == attestationManifestType
+}
+
+// imageAttestations returns the descriptor of the manifest, or image index,
+// that ref refers to in the registry, and the images of that manifest with
+// their attestations. Attestations are read from the attestation manifests of
+// the image index, as attached by BuildKit, or, for the images that have none,
+// from the referrers of their manifest.
+func imageAttestations(ctx context.Context, rc client.RegistryClient, ref reference.Named) (ocispec.Descriptor, []attestedImage, error) {
+	desc, payload, err := rc.GetRawManifest(ctx, ref)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+
+	var images []attestedImage
+	if !isIndex(desc.MediaType) {
+		images = []attestedImage{{Digest: desc.Digest}}
+	} else {
+		var index ocispec.Index
+		if err := json.Unmarshal(payload, &index); err != nil {
+			return desc, nil, errors.Wrapf(err, "invalid image index for %s", reference.FamiliarString(ref))
+		}
+		byDigest := map[digest.Digest]int{}
+		for _, m := range index.Manifests {
+			if isAttestationManifest(m) {
+				continue
+			}
+			var platform string
+			if m.Platform != nil {
+				platform = platforms.Format(*m.Platform)
+			}
+			byDigest[m.Digest] = len(images)
+			images = append(images, attestedImage{Platform: platform, Digest: m.Digest})
+		}
+		for _, m := range index.Manifests {
+			if !isAttestationManifest(m) {
+				continue
+			}
+			i, ok := byDigest[digest.Digest(m.Annotations[annotationReferenceDigest])]
+			if !ok {
+				continue
+			}
+			statements, err := attestationStatements(ctx, rc, ref, m.Digest)
+			if err != nil {
+				return desc, nil, err
+			}
+			images[i].Attestations = append(images[i].Attestations, statements...)
+		}
+	}
+
+	for i := range images {
+		if len(images[i].Attestations) > 0 {
+			continue
+		}
+		named, err := reference.WithDigest(reference.TrimNamed(ref), images[i].Digest)
+		if err != nil {
+			return desc, nil, err
+		}
+		referrers, err := rc.GetReferrers(ctx, named)
+		if err != nil {
+			return desc, nil, err
+		}
+		for _, r := range referrers {
+			if r.ArtifactType != artifactTypeInToto {
+				continue
+			}
+			statements, err := attestationStatements(ctx, rc, ref, r.Digest)
+			if err != nil {
+				return desc, nil, err
+			}
+			images[i].Attestations = append(images[i].Attestations, statements...)
+		}
+	}
+
+	for i := range images {
+		for j := range images[i].Attestations {
+			images[i].Attestations[j].Platform = images[i].Platform
+			images[i].Attestations[j].Subject = images[i].Digest
+		}
+	}
+	return desc, images, nil
+}
+
+// attestationStatements returns the in-toto statements of the attestation
+// manifest with the given digest.
+func attestationStatements(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest) ([]attestation, error) {
+	payload, err := getManifestByDigest(ctx, rc, ref, dgst)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get attestation manifest %s", dgst)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "invalid attestation manifest %s", dgst)
+	}
+	var statements []attestation
+	for _, l := range manifest.Layers {
+		predicateType := l.Annotations[annotationPredicateType]
+		if predicateType == "" {
+			continue
+		}
+		statements = append(statements, attestation{
+			Kind:          attestationKind(predicateType),
+			PredicateType: predicateType,
+			Statement:     l,
+		})
+	}
+	return statements, nil
+}
+
+// getManifestByDigest returns the content of a manifest of the repository of
+// ref, after checking that it matches its digest.
+func getManifestByDigest(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	named, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return nil, err
+	}
+	desc, payload, err := rc.GetRawManifest(ctx, named)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Digest != dgst {
+		return nil, errors.Errorf("content of manifest %s doesn't match its digest", dgst)
+	}
+	return payload, nil
+}
+
+// getBlob returns the content of a blob of the repository of ref, after
+// checking that it matches its digest.
+func getBlob(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest) ([]byte, error) {
+	named, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return nil, err
+	}
+	b, err := rc.GetBlob(ctx, named)
+	if err != nil {
+		return nil, err
+	}
+	if dgst.Algorithm().Available() && dgst.Algorithm().FromBytes(b) != dgst {
+		return nil, errors.Errorf("content of blob %s doesn't match its digest", dgst)
+	}
+	return b, nil
+}
@@ -0,0 +1,234 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	attestationsFormatSPDX      = "spdx"
+	attestationsFormatCycloneDX = "cyclonedx"
+
+	predicateTypeSPDX      = "https://spdx.dev/Document"
+	predicateTypeCycloneDX = "https://cyclonedx.org/bom"
+)
+
+type attestationsOptions struct {
+	image    string
+	platform string
+	format   string
+}
+
+// inTotoStatement is an in-toto attestation statement.
+type inTotoStatement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// attestationOutput is an attestation, as printed by --format=json.
+type attestationOutput struct {
+	attestation
+	Statement json.RawMessage
+}
+
+// newAttestationsCommand creates a new cobra.Command for `docker image attestations`
+func newAttestationsCommand(dockerCli command.Cli) *cobra.Command {
+	var opts attestationsOptions
+
+	cmd := &cobra.Command{
+		Use:   "attestations [OPTIONS] IMAGE",
+		Short: "Show the SBOM and provenance attestations of an image in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runAttestations(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.ImageNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.platform, "platform", "", `Only show the attestations of the given platform of multi-platform images ('os[/arch[/variant]]')`)
+	flags.StringVar(&opts.format, "format", "table", `Output format ("table", "json", or "spdx" or "cyclonedx" to print the SBOM of the image)`)
+	return cmd
+}
+
+func runAttestations(ctx context.Context, dockerCli command.Cli, opts attestationsOptions) error {
+	switch opts.format {
+	case "", formatter.TableFormatKey, formatter.JSONFormatKey, attestationsFormatSPDX, attestationsFormatCycloneDX:
+	default:
+		return cli.StatusError{StatusCode: 64, Status: fmt.Sprintf("invalid format %q: must be one of table, json, spdx, or cyclonedx", opts.format)}
+	}
+
+	ref, err := reference.ParseNormalizedNamed(opts.image)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	rc := dockerCli.RegistryClient(false)
+	_, images, err := imageAttestations(ctx, rc, ref)
+	if err != nil {
+		return err
+	}
+	if opts.platform != "" {
+		p, err := platforms.Parse(opts.platform)
+		if err != nil {
+			return errors.Wrap(err, "invalid platform")
+		}
+		images = filterAttestedImages(images, p)
+		if len(images) == 0 {
+			return errors.Errorf("image %s has no variant for platform %s", reference.FamiliarString(ref), platforms.Format(p))
+		}
+	}
+
+	var attestations []attestation
+	for _, img := range images {
+		attestations = append(attestations, img.Attestations...)
+	}
+	statements := make([]json.RawMessage, len(attestations))
+	for i, a := range attestations {
+		statements[i], err = getBlob(ctx, rc, ref, a.Statement.Digest)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get attestation %s", a.Statement.Digest)
+		}
+	}
+
+	switch opts.format {
+	case formatter.JSONFormatKey:
+		out := make([]attestationOutput, 0, len(attestations))
+		for i, a := range attestations {
+			out = append(out, attestationOutput{attestation: a, Statement: statements[i]})
+		}
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(out)
+	case attestationsFormatSPDX:
+		return printSBOM(dockerCli.Out(), ref, attestations, statements, predicateTypeSPDX)
+	case attestationsFormatCycloneDX:
+		return printSBOM(dockerCli.Out(), ref, attestations, statements, predicateTypeCycloneDX)
+	default:
+		printAttestations(dockerCli.Out(), attestations, statements)
+		return nil
+	}
+}
+
+// filterAttestedImages returns the images that match platform. Images that
+// have no platform (single-platform images) always match.
+func filterAttestedImages(images []attestedImage, platform ocispec.Platform) []attestedImage {
+	m := platforms.NewMatcher(platform)
+	var filtered []attestedImage
+	for _, img := range images {
+		if img.Platform != "" {
+			p, err := platforms.Parse(img.Platform)
+			if err != nil || !m.Match(p) {
+				continue
+			}
+		}
+		filtered = append(filtered, img)
+	}
+	return filtered
+}
+
+// printSBOM prints the SBOM document, with the given predicate type, of the
+// attestations. The attestations must be of a single platform.
+func printSBOM(out io.Writer, ref reference.Named, attestations []attestation, statements []json.RawMessage, predicateType string) error {
+	var (
+		predicate json.RawMessage
+		platform  string
+	)
+	for i, a := range attestations {
+		if a.Kind != attestationSBOM || !hasPredicateType(a, predicateType) {
+			continue
+		}
+		if predicate != nil {
+			if a.Platform != platform {
+				return errors.Errorf("image %s has SBOMs for multiple platforms: use --platform to select one", reference.FamiliarString(ref))
+			}
+			// Only the first SBOM of the platform is printed, which is the
+			// SBOM of the image's final stage.
+			continue
+		}
+		var s inTotoStatement
+		if err := json.Unmarshal(statements[i], &s); err != nil {
+			return errors.Wrapf(err, "invalid attestation %s", a.Statement.Digest)
+		}
+		predicate, platform = s.Predicate, a.Platform
+	}
+	if predicate == nil {
+		return errors.Errorf("image %s has no %s SBOM attestation", reference.FamiliarString(ref), sbomFormatName(predicateType))
+	}
+	_, err := fmt.Fprintln(out, string(predicate))
+	return err
+}
+
+func hasPredicateType(a attestation, predicateType string) bool {
+	return a.PredicateType == predicateType || strings.HasPrefix(a.PredicateType, predicateType+"/")
+}
+
+func sbomFormatName(predicateType string) string {
+	if predicateType == predicateTypeCycloneDX {
+		return "CycloneDX"
+	}
+	return "SPDX"
+}
+
+func printAttestations(out io.Writer, attestations []attestation, statements []json.RawMessage) {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PLATFORM\tTYPE\tPREDICATE TYPE\tSUMMARY")
+	for i, a := range attestations {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", valueOrNone(a.Platform), valueOrNone(a.Kind), a.PredicateType, attestationSummary(a, statements[i]))
+	}
+	_ = w.Flush()
+}
+
+// attestationSummary returns a one-line summary of an attestation: the
+// builder of provenance attestations, and the number of packages of SBOMs.
+func attestationSummary(a attestation, statement json.RawMessage) string {
+	var s struct {
+		Predicate struct {
+			// SLSA provenance v0.2.
+			Builder struct {
+				ID string `json:"id"`
+			} `json:"builder"`
+			// SLSA provenance v1.
+			RunDetails struct {
+				Builder struct {
+					ID string `json:"id"`
+				} `json:"builder"`
+			} `json:"runDetails"`
+			// SPDX.
+			Packages []json.RawMessage `json:"packages"`
+			// CycloneDX.
+			Components []json.RawMessage `json:"components"`
+		} `json:"predicate"`
+	}
+	if err := json.Unmarshal(statement, &s); err != nil {
+		return ""
+	}
+	switch {
+	case a.Kind == attestationProvenance && s.Predicate.RunDetails.Builder.ID != "":
+		return "builder: " + s.Predicate.RunDetails.Builder.ID
+	case a.Kind == attestationProvenance && s.Predicate.Builder.ID != "":
+		return "builder: " + s.Predicate.Builder.ID
+	case a.Kind == attestationSBOM && hasPredicateType(a, predicateTypeCycloneDX):
+		return fmt.Sprintf("%d components", len(s.Predicate.Components))
+	case a.Kind == attestationSBOM:
+		return fmt.Sprintf("%d packages", len(s.Predicate.Packages))
+	}
+	return ""
+}
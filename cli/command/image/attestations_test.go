@@ -0,0 +1,155 @@
+package image
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newTestAttestedImage adds an image for linux/amd64 and linux/arm64 to r,
+// with a provenance attestation and an SPDX SBOM for amd64, and a CycloneDX
+// SBOM for arm64.
+func newTestAttestedImage(t *testing.T, r *registry.Registry) {
+	t.Helper()
+	statements := map[string][]inTotoStatement{
+		"amd64": {
+			{Type: "https://in-toto.io/Statement/v0.1", PredicateType: "https://slsa.dev/provenance/v0.2", Predicate: json.RawMessage(`{"builder":{"id":"https://github.com/example/app/actions/runs/1"}}`)},
+			{Type: "https://in-toto.io/Statement/v0.1", PredicateType: predicateTypeSPDX, Predicate: json.RawMessage(`{"spdxVersion":"SPDX-2.3","packages":[{"name":"musl"},{"name":"busybox"}]}`)},
+		},
+		"arm64": {
+			{Type: "https://in-toto.io/Statement/v0.1", PredicateType: predicateTypeCycloneDX + "/v1.5", Predicate: json.RawMessage(`{"bomFormat":"CycloneDX","components":[{"name":"musl"}]}`)},
+		},
+	}
+
+	var manifests []ocispec.Descriptor
+	for _, arch := range []string{"amd64", "arm64"} {
+		platform := ocispec.Platform{OS: "linux", Architecture: arch}
+		m := r.AddManifest(t, testImageName, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, ocispec.Image{Platform: platform}),
+		})
+		m.Platform = &platform
+
+		var layers []ocispec.Descriptor
+		for _, s := range statements[arch] {
+			l := r.AddBlob(t, "application/vnd.in-toto+json", s)
+			l.Annotations = map[string]string{annotationPredicateType: s.PredicateType}
+			layers = append(layers, l)
+		}
+		att := r.AddManifest(t, testImageName, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, []byte("{}")),
+			Layers:    layers,
+		})
+		att.Platform = &ocispec.Platform{OS: "unknown", Architecture: "unknown"}
+		att.Annotations = map[string]string{
+			annotationReferenceType:   attestationManifestType,
+			annotationReferenceDigest: m.Digest.String(),
+		}
+		manifests = append(manifests, m, att)
+	}
+	r.AddManifest(t, testImageName, "latest", ocispec.MediaTypeImageIndex, ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: manifests,
+	})
+}
+
+func TestNewAttestationsCommand(t *testing.T) {
+	r := registry.New()
+	newTestAttestedImage(t, r)
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedOut   string
+		expectedError string
+	}{
+		{
+			name: "table",
+			expectedOut: `PLATFORM      TYPE         PREDICATE TYPE                     SUMMARY
+linux/amd64   provenance   https://slsa.dev/provenance/v0.2   builder: https://github.com/example/app/actions/runs/1
+linux/amd64   sbom         https://spdx.dev/Document          2 packages
+linux/arm64   sbom         https://cyclonedx.org/bom/v1.5     1 components
+`,
+		},
+		{
+			name:        "spdx",
+			args:        []string{"--format", "spdx"},
+			expectedOut: `{"spdxVersion":"SPDX-2.3","packages":[{"name":"musl"},{"name":"busybox"}]}` + "\n",
+		},
+		{
+			name:        "cyclonedx",
+			args:        []string{"--format", "cyclonedx", "--platform", "linux/arm64"},
+			expectedOut: `{"bomFormat":"CycloneDX","components":[{"name":"musl"}]}` + "\n",
+		},
+		{
+			name:          "cyclonedx of other platform",
+			args:          []string{"--format", "cyclonedx", "--platform", "linux/amd64"},
+			expectedError: "image example:latest has no CycloneDX SBOM attestation",
+		},
+		{
+			name:          "missing platform",
+			args:          []string{"--platform", "windows/amd64"},
+			expectedError: "image example:latest has no variant for platform windows/amd64",
+		},
+		{
+			name:          "invalid format",
+			args:          []string{"--format", "xml"},
+			expectedError: `invalid format "xml": must be one of table, json, spdx, or cyclonedx`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.SetRegistryClient(r)
+			cmd := newAttestationsCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(append(tc.args, "example"))
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedError))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expectedOut))
+		})
+	}
+}
+
+func TestNewAttestationsCommandJSON(t *testing.T) {
+	r := registry.New()
+	newTestAttestedImage(t, r)
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(r)
+	cmd := newAttestationsCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--format", "json", "--platform", "linux/arm64", "example"})
+	assert.NilError(t, cmd.Execute())
+
+	var out []struct {
+		Platform      string
+		Subject       digest.Digest
+		Kind          string
+		PredicateType string
+		Statement     inTotoStatement
+	}
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &out))
+	assert.Assert(t, is.Len(out, 1))
+	assert.Check(t, is.Equal(out[0].Platform, "linux/arm64"))
+	assert.Check(t, is.Equal(out[0].Kind, attestationSBOM))
+	assert.Check(t, out[0].Subject != "")
+	assert.Check(t, is.Equal(out[0].Statement.PredicateType, "https://cyclonedx.org/bom/v1.5"))
+	var predicate bytes.Buffer
+	assert.NilError(t, json.Compact(&predicate, out[0].Statement.Predicate))
+	assert.Check(t, is.Equal(predicate.String(), `{"bomFormat":"CycloneDX","components":[{"name":"musl"}]}`))
+}
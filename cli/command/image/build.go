@@ -17,18 +17,22 @@ import (
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/image/build"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/builder/remotecontext/urlutil"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/pkg/progress"
 	"github.com/docker/docker/pkg/streamformatter"
+	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -52,12 +56,14 @@ type buildOptions struct {
 	cgroupParent   string
 	isolation      string
 	quiet          bool
+	progress       string
 	noCache        bool
 	rm             bool
 	forceRm        bool
 	pull           bool
 	cacheFrom      []string
 	compress       bool
+	contextFilters []string
 	securityOpt    []string
 	networkMode    string
 	squash         bool
@@ -100,6 +106,9 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 		Args:  cli.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.context = args[0]
+			if dockerCli.Out().Level() == streams.LevelQuiet {
+				options.quiet = true
+			}
 			return runBuild(cmd.Context(), dockerCli, options)
 		},
 		Annotations: map[string]string{
@@ -132,9 +141,11 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.rm, "rm", true, "Remove intermediate containers after a successful build")
 	flags.BoolVar(&options.forceRm, "force-rm", false, "Always remove intermediate containers")
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress the build output and print image ID on success")
+	command.AddProgressFlag(flags, &options.progress)
 	flags.BoolVar(&options.pull, "pull", false, "Always attempt to pull a newer version of the image")
 	flags.StringSliceVar(&options.cacheFrom, "cache-from", []string{}, "Images to consider as cache sources")
 	flags.BoolVar(&options.compress, "compress", false, "Compress the build context using gzip")
+	flags.StringArrayVar(&options.contextFilters, "context-filter", nil, "Exclude files that match a pattern from the build context, in addition to the .dockerignore file")
 	flags.StringSliceVar(&options.securityOpt, "security-opt", []string{}, "Security options")
 	flags.StringVar(&options.networkMode, "network", "default", "Set the networking mode for the RUN instructions during build")
 	flags.SetAnnotation("network", "version", []string{"1.25"})
@@ -151,6 +162,11 @@ func NewBuildCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("squash", "experimental", nil)
 	flags.SetAnnotation("squash", "version", []string{"1.25"})
 
+	_ = cmd.RegisterFlagCompletionFunc("network", completion.NetworkNames(dockerCli))
+	_ = cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// The argument is the build context, not an image.
+		return completion.Platforms(dockerCli)(cmd, nil, toComplete)
+	})
 	return cmd
 }
 
@@ -170,6 +186,65 @@ func (out *lastProgressOutput) WriteProgress(prog progress.Progress) error {
 	return out.output.WriteProgress(prog)
 }
 
+// countingReader counts the bytes that are read from it, and calls onEOF with
+// the count once it's read to the end.
+type countingReader struct {
+	io.ReadCloser
+	size  int64
+	onEOF func(size int64)
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.size += int64(n)
+	if err == io.EOF && r.onEOF != nil {
+		r.onEOF(r.size)
+		r.onEOF = nil
+	}
+	return n, err
+}
+
+// zstdAPIVersion is the API version of the daemons that decompress build
+// contexts that are compressed with zstd.
+const zstdAPIVersion = "1.41"
+
+// daemonSupportsCompression returns whether the daemon decompresses build
+// contexts with the given compression.
+func daemonSupportsCompression(dockerCli command.Cli, compression archive.Compression) bool {
+	if compression == archive.Zstd {
+		return !versions.LessThan(dockerCli.CurrentVersion(), zstdAPIVersion)
+	}
+	return true
+}
+
+// contextSummary summarizes the size of the build context that was sent to
+// the daemon, such as "Sent build context to Docker daemon: 12.3MB (gzip,
+// 45.6MB uncompressed)".
+func contextSummary(size int64, compression archive.Compression, uncompressed *countingReader) string {
+	summary := "Sent build context to Docker daemon: " + units.HumanSizeWithPrecision(float64(size), 3)
+	switch {
+	case uncompressed != nil:
+		summary += fmt.Sprintf(" (%s, %s uncompressed)", compressionName(compression), units.HumanSizeWithPrecision(float64(uncompressed.size), 3))
+	case compression != archive.Uncompressed:
+		summary += fmt.Sprintf(" (%s)", compressionName(compression))
+	}
+	return summary
+}
+
+func compressionName(compression archive.Compression) string {
+	switch compression {
+	case archive.Bzip2:
+		return "bzip2"
+	case archive.Gzip:
+		return "gzip"
+	case archive.Xz:
+		return "xz"
+	case archive.Zstd:
+		return "zstd"
+	}
+	return "uncompressed"
+}
+
 //nolint:gocyclo
 func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions) error {
 	var (
@@ -197,6 +272,10 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 	if options.quiet {
 		progBuff = bytes.NewBuffer(nil)
 		buildBuff = bytes.NewBuffer(nil)
+	} else if options.progress == command.ProgressJSON {
+		// Only the progress of the build is written as JSON, not the
+		// progress of sending the build context.
+		progBuff = io.Discard
 	}
 	if options.imageIDFile != "" {
 		// Avoid leaving a stale file if we eventually fail
@@ -239,12 +318,40 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		contextDir = tempDir
 	}
 
+	compression := archive.Uncompressed
+	if buildCtx != nil {
+		// The archive is sent as it is, if the daemon supports its compression,
+		// unless the CLI filters or rewrites it.
+		buildCtx, compression, err = build.DetectCompression(buildCtx)
+		if err != nil {
+			return err
+		}
+		rewritten := len(options.contextFilters) > 0 || dockerfileCtx != nil || !options.untrusted
+		if compression != archive.Uncompressed && (rewritten || !daemonSupportsCompression(dockerCli, compression)) {
+			if buildCtx, err = build.Decompress(buildCtx); err != nil {
+				return err
+			}
+			compression = archive.Uncompressed
+		}
+		if len(options.contextFilters) > 0 {
+			dockerfile := relDockerfile
+			if dockerfile == "" {
+				dockerfile = build.DefaultDockerfileName
+			}
+			excludes := build.TrimBuildFilesFromExcludes(options.contextFilters, dockerfile, false)
+			if buildCtx, err = build.FilterContext(buildCtx, excludes); err != nil {
+				return err
+			}
+		}
+	}
+
 	// read from a directory into tar archive
 	if buildCtx == nil {
 		excludes, err := build.ReadDockerignore(contextDir)
 		if err != nil {
 			return err
 		}
+		excludes = append(excludes, options.contextFilters...)
 
 		if err := build.ValidateContextDirectory(contextDir, excludes); err != nil {
 			return errors.Wrap(err, "error checking context")
@@ -294,11 +401,15 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		}
 	}
 
-	if options.compress {
-		buildCtx, err = build.Compress(buildCtx)
+	// Contexts that are compressed already aren't compressed twice.
+	var uncompressed *countingReader
+	if options.compress && compression == archive.Uncompressed {
+		uncompressed = &countingReader{ReadCloser: buildCtx}
+		buildCtx, err = build.Compress(uncompressed)
 		if err != nil {
 			return err
 		}
+		compression = archive.Gzip
 	}
 
 	// Setup an upload progress bar
@@ -315,7 +426,12 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 
 	var body io.Reader
 	if buildCtx != nil {
-		body = progress.NewProgressReader(buildCtx, progressOutput, 0, "", "Sending build context to Docker daemon")
+		body = &countingReader{
+			ReadCloser: progress.NewProgressReader(buildCtx, progressOutput, 0, "", "Sending build context to Docker daemon"),
+			onEOF: func(size int64) {
+				_, _ = fmt.Fprintln(progBuff, contextSummary(size, compression, uncompressed))
+			},
+		}
 	}
 
 	configFile := dockerCli.ConfigFile()
@@ -350,7 +466,12 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 		}
 	}
 
-	err = jsonmessage.DisplayJSONMessagesStream(response.Body, buildBuff, dockerCli.Out().FD(), dockerCli.Out().IsTerminal(), aux)
+	if options.progress == command.ProgressJSON && !options.quiet {
+		err = command.DisplayProgress(response.Body, dockerCli.Out(), options.progress, aux)
+	} else {
+		isTerminal := dockerCli.Out().IsTerminal() && options.progress != command.ProgressPlain
+		err = jsonmessage.DisplayJSONMessagesStream(response.Body, buildBuff, dockerCli.Out().FD(), isTerminal, aux)
+	}
 	if err != nil {
 		if jerr, ok := err.(*jsonmessage.JSONError); ok {
 			// If no error code is set, default to 1
@@ -367,7 +488,7 @@ func runBuild(ctx context.Context, dockerCli command.Cli, options buildOptions)
 
 	// Windows: show error message about modified file permissions if the
 	// daemon isn't running Windows.
-	if response.OSType != "windows" && runtime.GOOS == "windows" && !options.quiet {
+	if response.OSType != "windows" && runtime.GOOS == "windows" && !options.quiet && options.progress != command.ProgressJSON {
 		fmt.Fprintln(dockerCli.Out(), "SECURITY WARNING: You are building a Docker "+
 			"image from Windows against a non-Windows Docker host. All files and "+
 			"directories added to build context will have '-rwxr-xr-x' permissions. "+
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -445,3 +446,77 @@ func Compress(buildCtx io.ReadCloser) (io.ReadCloser, error) {
 
 	return pipeReader, nil
 }
+
+// DetectCompression detects the compression of an archive build context and
+// returns a buffered version of buildCtx, safe to consume in lieu of buildCtx.
+func DetectCompression(buildCtx io.ReadCloser) (io.ReadCloser, archive.Compression, error) {
+	buf := bufio.NewReader(buildCtx)
+	magic, err := buf.Peek(archiveHeaderSize)
+	if err != nil && err != io.EOF {
+		return nil, archive.Uncompressed, errors.Errorf("failed to peek context header: %v", err)
+	}
+	return ioutils.NewReadCloserWrapper(buf, buildCtx.Close), archive.DetectCompression(magic), nil
+}
+
+// Decompress returns the tar archive of a build context that's compressed
+// with any of the compression algorithms of DetectCompression, or buildCtx
+// itself if it isn't compressed.
+func Decompress(buildCtx io.ReadCloser) (io.ReadCloser, error) {
+	rc, err := archive.DecompressStream(buildCtx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress context")
+	}
+	return ioutils.NewReadCloserWrapper(rc, func() error {
+		_ = rc.Close()
+		return buildCtx.Close()
+	}), nil
+}
+
+// FilterContext removes the files that match the exclude patterns from the
+// tar archive of a build context, as the patterns of a .dockerignore file
+// exclude them from a context directory.
+func FilterContext(buildCtx io.ReadCloser, excludes []string) (io.ReadCloser, error) {
+	pm, err := patternmatcher.New(excludes)
+	if err != nil {
+		return nil, err
+	}
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		defer buildCtx.Close()
+		tarReader := tar.NewReader(buildCtx)
+		tarWriter := tar.NewWriter(pipeWriter)
+		for {
+			hdr, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				pipeWriter.CloseWithError(errors.Wrap(err, "failed to filter context"))
+				return
+			}
+			name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+			if skip, err := filepathMatches(pm, filepath.FromSlash(name)); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			} else if skip {
+				continue
+			}
+			if err := tarWriter.WriteHeader(hdr); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+			if _, err := pools.Copy(tarWriter, tarReader); err != nil {
+				pipeWriter.CloseWithError(err)
+				return
+			}
+		}
+		if err := tarWriter.Close(); err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		pipeWriter.Close()
+	}()
+
+	return pipeReader, nil
+}
@@ -326,6 +326,40 @@ func TestDetectArchiveReader(t *testing.T) {
 	}
 }
 
+func TestFilterContext(t *testing.T) {
+	contextDir := createTestTempDir(t)
+	createTestTempFile(t, contextDir, DefaultDockerfileName, dockerfileContents)
+	createTestTempFile(t, contextDir, "app.go", "package main")
+	createTestTempFile(t, contextDir, "debug.log", "debug")
+	assert.NilError(t, os.MkdirAll(filepath.Join(contextDir, "tmp", "cache"), 0o755))
+	createTestTempFile(t, filepath.Join(contextDir, "tmp"), "keep.log", "keep")
+	createTestTempFile(t, filepath.Join(contextDir, "tmp", "cache"), "blob", "blob")
+
+	tarStream, err := archive.Tar(contextDir, archive.Gzip)
+	assert.NilError(t, err)
+
+	buildCtx, compression, err := DetectCompression(tarStream)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(compression, archive.Gzip))
+	buildCtx, err = Decompress(buildCtx)
+	assert.NilError(t, err)
+	buildCtx, err = FilterContext(buildCtx, []string{"*.log", "tmp/cache", "!tmp/keep.log"})
+	assert.NilError(t, err)
+	defer buildCtx.Close()
+
+	var names []string
+	tarReader := tar.NewReader(buildCtx)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		names = append(names, header.Name)
+	}
+	assert.Check(t, is.DeepEqual(names, []string{DefaultDockerfileName, "app.go", "tmp/", "tmp/keep.log"}))
+}
+
 func mustPatternMatcher(t *testing.T, patterns []string) *patternmatcher.PatternMatcher {
 	t.Helper()
 	pm, err := patternmatcher.New(patterns)
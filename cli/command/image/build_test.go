@@ -5,16 +5,19 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/pkg/archive"
+	units "github.com/docker/go-units"
 	"github.com/google/go-cmp/cmp"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/fs"
@@ -57,6 +60,79 @@ func TestRunBuildDockerfileFromStdinWithCompress(t *testing.T) {
 	assert.Equal(t, archive.Gzip, archive.DetectCompression(header))
 }
 
+func TestRunBuildCompressedContextFromStdin(t *testing.T) {
+	t.Setenv("DOCKER_BUILDKIT", "0")
+	var sent []byte
+	fakeImageBuild := func(_ context.Context, buildContext io.Reader, _ types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+		var err error
+		sent, err = io.ReadAll(buildContext)
+		assert.NilError(t, err)
+		return types.ImageBuildResponse{Body: io.NopCloser(new(bytes.Buffer))}, nil
+	}
+
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM alpine:frozen\n"))
+	tarball, err := archive.Tar(dir.Path(), archive.Gzip)
+	assert.NilError(t, err)
+
+	cli := test.NewFakeCli(&fakeClient{imageBuildFunc: fakeImageBuild})
+	cli.SetIn(streams.NewIn(tarball))
+
+	options := newBuildOptions()
+	options.compress = true
+	options.context = "-"
+	options.untrusted = true
+	assert.NilError(t, runBuild(context.TODO(), cli, options))
+
+	// The context is sent as it is, rather than compressed twice.
+	gzipReader, err := gzip.NewReader(bytes.NewReader(sent))
+	assert.NilError(t, err)
+	header, err := tar.NewReader(gzipReader).Next()
+	assert.NilError(t, err)
+	assert.Equal(t, header.Name, "Dockerfile")
+
+	summary := fmt.Sprintf("Sent build context to Docker daemon: %s (gzip)\n", units.HumanSizeWithPrecision(float64(len(sent)), 3))
+	assert.Check(t, strings.HasSuffix(cli.OutBuffer().String(), summary), cli.OutBuffer().String())
+}
+
+func TestRunBuildContextFilter(t *testing.T) {
+	t.Setenv("DOCKER_BUILDKIT", "0")
+	dir := fs.NewDir(t, t.Name(),
+		fs.WithFile("Dockerfile", "FROM alpine:frozen\nCOPY . /\n"),
+		fs.WithFile("app.go", "package main"),
+		fs.WithFile("debug.log", "debug"),
+		fs.WithDir("tmp", fs.WithFile("cache", "cache")))
+
+	t.Run("directory", func(t *testing.T) {
+		fakeBuild := newFakeBuild()
+		cli := test.NewFakeCli(&fakeClient{imageBuildFunc: fakeBuild.build})
+
+		options := newBuildOptions()
+		options.context = dir.Path()
+		options.contextFilters = []string{"*.log", "tmp"}
+		options.untrusted = true
+		assert.NilError(t, runBuild(context.TODO(), cli, options))
+		assert.DeepEqual(t, fakeBuild.filenames(t), []string{"Dockerfile", "app.go"})
+	})
+
+	t.Run("compressed archive", func(t *testing.T) {
+		tarball, err := archive.Tar(dir.Path(), archive.Gzip)
+		assert.NilError(t, err)
+
+		fakeBuild := newFakeBuild()
+		cli := test.NewFakeCli(&fakeClient{imageBuildFunc: fakeBuild.build})
+		cli.SetIn(streams.NewIn(tarball))
+
+		options := newBuildOptions()
+		options.context = "-"
+		options.contextFilters = []string{"*.log", "tmp", "Dockerfile"}
+		options.untrusted = true
+		assert.NilError(t, runBuild(context.TODO(), cli, options))
+		// The Dockerfile is kept, as with .dockerignore files.
+		assert.DeepEqual(t, fakeBuild.filenames(t), []string{"Dockerfile", "app.go"})
+	})
+}
+
 func TestRunBuildResetsUidAndGidInContext(t *testing.T) {
 	skip.If(t, os.Getuid() != 0, "root is required to chown files")
 	t.Setenv("DOCKER_BUILDKIT", "0")
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/system"
@@ -15,19 +16,21 @@ import (
 
 type fakeClient struct {
 	client.Client
-	imageTagFunc     func(string, string) error
-	imageSaveFunc    func(images []string) (io.ReadCloser, error)
-	imageRemoveFunc  func(image string, options image.RemoveOptions) ([]image.DeleteResponse, error)
-	imagePushFunc    func(ref string, options image.PushOptions) (io.ReadCloser, error)
-	infoFunc         func() (system.Info, error)
-	imagePullFunc    func(ref string, options image.PullOptions) (io.ReadCloser, error)
-	imagesPruneFunc  func(pruneFilter filters.Args) (image.PruneReport, error)
-	imageLoadFunc    func(input io.Reader, quiet bool) (image.LoadResponse, error)
-	imageListFunc    func(options image.ListOptions) ([]image.Summary, error)
-	imageInspectFunc func(image string) (types.ImageInspect, []byte, error)
-	imageImportFunc  func(source image.ImportSource, ref string, options image.ImportOptions) (io.ReadCloser, error)
-	imageHistoryFunc func(image string) ([]image.HistoryResponseItem, error)
-	imageBuildFunc   func(context.Context, io.Reader, types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	imageTagFunc      func(string, string) error
+	imageSaveFunc     func(images []string) (io.ReadCloser, error)
+	imageRemoveFunc   func(image string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	imagePushFunc     func(ref string, options image.PushOptions) (io.ReadCloser, error)
+	infoFunc          func() (system.Info, error)
+	imagePullFunc     func(ref string, options image.PullOptions) (io.ReadCloser, error)
+	imagesPruneFunc   func(pruneFilter filters.Args) (image.PruneReport, error)
+	imageLoadFunc     func(input io.Reader, quiet bool) (image.LoadResponse, error)
+	imageListFunc     func(options image.ListOptions) ([]image.Summary, error)
+	imageInspectFunc  func(image string) (types.ImageInspect, []byte, error)
+	imageImportFunc   func(source image.ImportSource, ref string, options image.ImportOptions) (io.ReadCloser, error)
+	imageHistoryFunc  func(image string) ([]image.HistoryResponseItem, error)
+	imageBuildFunc    func(context.Context, io.Reader, types.ImageBuildOptions) (types.ImageBuildResponse, error)
+	containerListFunc func(options container.ListOptions) ([]types.Container, error)
+	serverVersionFunc func() (types.Version, error)
 }
 
 func (cli *fakeClient) ImageTag(_ context.Context, img, ref string) error {
@@ -69,7 +72,7 @@ func (cli *fakeClient) Info(_ context.Context) (system.Info, error) {
 
 func (cli *fakeClient) ImagePull(_ context.Context, ref string, options image.PullOptions) (io.ReadCloser, error) {
 	if cli.imagePullFunc != nil {
-		cli.imagePullFunc(ref, options)
+		return cli.imagePullFunc(ref, options)
 	}
 	return io.NopCloser(strings.NewReader("")), nil
 }
@@ -124,3 +127,17 @@ func (cli *fakeClient) ImageBuild(ctx context.Context, buildContext io.Reader, o
 	}
 	return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(""))}, nil
 }
+
+func (cli *fakeClient) ContainerList(_ context.Context, options container.ListOptions) ([]types.Container, error) {
+	if cli.containerListFunc != nil {
+		return cli.containerListFunc(options)
+	}
+	return []types.Container{}, nil
+}
+
+func (cli *fakeClient) ServerVersion(context.Context) (types.Version, error) {
+	if cli.serverVersionFunc != nil {
+		return cli.serverVersionFunc()
+	}
+	return types.Version{}, nil
+}
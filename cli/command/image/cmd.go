@@ -15,14 +15,22 @@ func NewImageCommand(dockerCli command.Cli) *cobra.Command {
 		RunE:  command.ShowHelp(dockerCli.Err()),
 	}
 	cmd.AddCommand(
+		newAboutCommand(dockerCli),
+		newAttestationsCommand(dockerCli),
 		NewBuildCommand(dockerCli),
+		newGCCommand(dockerCli),
 		NewHistoryCommand(dockerCli),
 		NewImportCommand(dockerCli),
+		newLayersCommand(dockerCli),
 		NewLoadCommand(dockerCli),
 		NewPullCommand(dockerCli),
 		NewPushCommand(dockerCli),
+		newRetagCommand(dockerCli),
 		NewSaveCommand(dockerCli),
+		newScanCommand(dockerCli),
 		NewTagCommand(dockerCli),
+		newTagsCommand(dockerCli),
+		newVerifyCommand(dockerCli),
 		newListCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		newInspectCommand(dockerCli),
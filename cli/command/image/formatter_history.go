@@ -18,6 +18,8 @@ const (
 	historyIDHeader = "IMAGE"
 	createdByHeader = "CREATED BY"
 	commentHeader   = "COMMENT"
+
+	historyLayerHeader = "LAYER"
 )
 
 // NewHistoryFormat returns a format for rendering an HistoryContext
@@ -38,9 +40,16 @@ func NewHistoryFormat(source string, quiet bool, human bool) formatter.Format {
 
 // HistoryWrite writes the context
 func HistoryWrite(ctx formatter.Context, human bool, histories []image.HistoryResponseItem) error {
+	return historyWrite(ctx, human, histories, nil)
+}
+
+// historyWrite writes the context, with the layers of the entries of the
+// history, if the image's layers (its diff IDs) are known.
+func historyWrite(ctx formatter.Context, human bool, histories []image.HistoryResponseItem, layers []string) error {
+	entryLayers := historyEntryLayers(histories, layers)
 	render := func(format func(subContext formatter.SubContext) error) error {
-		for _, history := range histories {
-			historyCtx := &historyContext{trunc: ctx.Trunc, h: history, human: human}
+		for i, history := range histories {
+			historyCtx := &historyContext{trunc: ctx.Trunc, h: history, human: human, layer: entryLayers[i]}
 			if err := format(historyCtx); err != nil {
 				return err
 			}
@@ -55,15 +64,36 @@ func HistoryWrite(ctx formatter.Context, human bool, histories []image.HistoryRe
 		"CreatedBy":    createdByHeader,
 		"Size":         formatter.SizeHeader,
 		"Comment":      commentHeader,
+		"Layer":        historyLayerHeader,
 	}
 	return ctx.Write(historyCtx, render)
 }
 
+// historyEntryLayers returns the layer that each entry of the history created,
+// or an empty string for the entries that created no layer. Like
+// layerHistory, entries with a size create a layer.
+func historyEntryLayers(histories []image.HistoryResponseItem, layers []string) []string {
+	entryLayers := make([]string, len(histories))
+	if layerHistory(histories, len(layers)) == nil {
+		return entryLayers
+	}
+	// The history is ordered from the most recent entry.
+	n := 0
+	for i := len(histories) - 1; i >= 0; i-- {
+		if histories[i].Size > 0 {
+			entryLayers[i] = layers[n]
+			n++
+		}
+	}
+	return entryLayers
+}
+
 type historyContext struct {
 	formatter.HeaderContext
 	trunc bool
 	human bool
 	h     image.HistoryResponseItem
+	layer string
 }
 
 func (c *historyContext) MarshalJSON() ([]byte, error) {
@@ -113,3 +143,12 @@ func (c *historyContext) Size() string {
 func (c *historyContext) Comment() string {
 	return c.h.Comment
 }
+
+// Layer returns the digest of the layer (its diff ID) that the entry created,
+// if any.
+func (c *historyContext) Layer() string {
+	if c.trunc {
+		return stringid.TruncateID(c.layer)
+	}
+	return c.layer
+}
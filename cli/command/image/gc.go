@@ -0,0 +1,277 @@
+package image
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type gcOptions struct {
+	force           bool
+	dryRun          bool
+	generation      time.Duration
+	keepGenerations int
+	maxTotalSize    opts.MemBytes
+	pins            []string
+	lockfiles       []string
+}
+
+func newGCCommand(dockerCli command.Cli) *cobra.Command {
+	options := gcOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "gc [OPTIONS]",
+		Short: "Remove the least recently used images",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGC(cmd.Context(), dockerCli, options)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Only show which images would be removed")
+	flags.DurationVar(&options.generation, "generation", 7*24*time.Hour, "Duration of a generation of images")
+	flags.IntVar(&options.keepGenerations, "keep-generations", 4, "Number of generations of the most recently used images to keep")
+	flags.Var(&options.maxTotalSize, "max-total-size", "Also remove the least recently used images until the total size of the images is at most this size")
+	flags.StringSliceVar(&options.pins, "pin", nil, "Never remove this image")
+	flags.StringSliceVar(&options.lockfiles, "lockfile", nil, "Never remove the images listed in this file")
+	return cmd
+}
+
+// gcAction is what "docker image gc" does with an image.
+type gcAction string
+
+const (
+	gcKeep   gcAction = "keep"
+	gcRemove gcAction = "remove"
+	gcPinned gcAction = "pinned"
+	gcInUse  gcAction = "in use"
+)
+
+type gcImage struct {
+	image.Summary
+	lastUsed   time.Time
+	recorded   bool
+	generation int
+	action     gcAction
+}
+
+func runGC(ctx context.Context, dockerCli command.Cli, options gcOptions) error {
+	if options.generation <= 0 {
+		return errors.New("invalid generation: must be a positive duration")
+	}
+	if options.keepGenerations < 0 {
+		return errors.New("invalid number of generations to keep: must be zero or more")
+	}
+
+	images, err := dockerCli.Client().ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return err
+	}
+	containers, err := dockerCli.Client().ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return err
+	}
+	inUse := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		inUse[c.ImageID] = true
+	}
+	pinned, err := pinnedImages(ctx, dockerCli, options)
+	if err != nil {
+		return err
+	}
+	var usage map[string]time.Time
+	if fileName := usageFile(dockerCli); fileName != "" {
+		if usage, err = loadUsage(fileName); err != nil {
+			return errors.Wrap(err, "failed to load image usage history")
+		}
+	}
+
+	now := time.Now()
+	gcImages := classifyImages(images, usage, now, options.generation)
+	for _, img := range gcImages {
+		switch {
+		case inUse[img.ID]:
+			img.action = gcInUse
+		case pinned[img.ID]:
+			img.action = gcPinned
+		case img.generation >= options.keepGenerations:
+			img.action = gcRemove
+		default:
+			img.action = gcKeep
+		}
+	}
+	if options.maxTotalSize > 0 {
+		removeUntilSize(gcImages, int64(options.maxTotalSize))
+	}
+
+	var remove []*gcImage
+	var removeSize int64
+	for _, img := range gcImages {
+		if img.action == gcRemove {
+			remove = append(remove, img)
+			removeSize += img.Size
+		}
+	}
+	if err := writeGCReport(dockerCli.Out(), gcImages, now); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Out(), "\n%d of %d images to remove (%s)\n", len(remove), len(gcImages), units.HumanSize(float64(removeSize)))
+	if options.dryRun || len(remove) == 0 {
+		return nil
+	}
+
+	if !options.force {
+		r, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), fmt.Sprintf("WARNING! This will remove %d images.\nAre you sure you want to continue?", len(remove)))
+		if err != nil {
+			return err
+		}
+		if !r {
+			return errdefs.Cancelled(errors.New("image gc has been cancelled"))
+		}
+	}
+
+	var errs []string
+	for _, img := range remove {
+		dels, err := dockerCli.Client().ImageRemove(ctx, img.ID, image.RemoveOptions{Force: true, PruneChildren: true})
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		for _, del := range dels {
+			if del.Deleted != "" {
+				_, _ = fmt.Fprintln(dockerCli.Out(), "Deleted:", del.Deleted)
+			} else {
+				_, _ = fmt.Fprintln(dockerCli.Out(), "Untagged:", del.Untagged)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// classifyImages classifies images into generations by the time they were
+// last used: images that were used less than one generation ago are in the
+// first generation (0), and so on. The last use of images that the CLI has
+// no record of is the time they were created. Images are returned from the
+// most recently used.
+func classifyImages(images []image.Summary, usage map[string]time.Time, now time.Time, generation time.Duration) []*gcImage {
+	gcImages := make([]*gcImage, 0, len(images))
+	for _, img := range images {
+		gcImg := &gcImage{Summary: img, lastUsed: lastUse(usage, img), recorded: true}
+		if gcImg.lastUsed.IsZero() {
+			gcImg.lastUsed, gcImg.recorded = time.Unix(img.Created, 0), false
+		}
+		if age := now.Sub(gcImg.lastUsed); age > 0 {
+			gcImg.generation = int(age / generation)
+		}
+		gcImages = append(gcImages, gcImg)
+	}
+	sort.SliceStable(gcImages, func(i, j int) bool { return gcImages[i].lastUsed.After(gcImages[j].lastUsed) })
+	return gcImages
+}
+
+// removeUntilSize marks the least recently used images that are kept for
+// removal, until the total size of the images that are not removed is at
+// most maxSize. images are ordered from the most recently used.
+func removeUntilSize(images []*gcImage, maxSize int64) {
+	var total int64
+	for _, img := range images {
+		if img.action != gcRemove {
+			total += img.Size
+		}
+	}
+	for i := len(images) - 1; i >= 0 && total > maxSize; i-- {
+		if images[i].action == gcKeep {
+			images[i].action = gcRemove
+			total -= images[i].Size
+		}
+	}
+}
+
+// pinnedImages returns the IDs of the images that must not be removed: the
+// images that are pinned with the "--pin" option and the "pinnedImages"
+// option of the CLI configuration file, and the images listed in the
+// lockfiles of the "--lockfile" option. Images that don't exist locally are
+// ignored.
+func pinnedImages(ctx context.Context, dockerCli command.Cli, options gcOptions) (map[string]bool, error) {
+	refs := append(append([]string{}, dockerCli.ConfigFile().PinnedImages...), options.pins...)
+	for _, lockfile := range options.lockfiles {
+		lockRefs, err := readLockfile(lockfile)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, lockRefs...)
+	}
+
+	pinned := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		img, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, ref)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		pinned[img.ID] = true
+	}
+	return pinned, nil
+}
+
+// readLockfile reads the image references listed in a lockfile, one per
+// line. Empty lines, and lines starting with "#", are ignored.
+func readLockfile(fileName string) ([]string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read lockfile")
+	}
+	defer f.Close()
+
+	var refs []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read lockfile %s", fileName)
+	}
+	return refs, nil
+}
+
+func writeGCReport(out io.Writer, images []*gcImage, now time.Time) error {
+	w := tabwriter.NewWriter(out, 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "IMAGE\tID\tSIZE\tLAST USED\tGENERATION\tACTION")
+	for _, img := range images {
+		lastUsed := units.HumanDuration(now.Sub(img.lastUsed)) + " ago"
+		if !img.recorded {
+			lastUsed = "never (created " + lastUsed + ")"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", imageName(img.Summary), shortID(img.ID, true), units.HumanSize(float64(img.Size)), lastUsed, img.generation, img.action)
+	}
+	return w.Flush()
+}
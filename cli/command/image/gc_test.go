@@ -0,0 +1,150 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/golden"
+)
+
+func TestNewGCCommand(t *testing.T) {
+	const day = 24 * time.Hour
+	now := time.Now()
+	images := []image.Summary{
+		{ID: "sha256:1111111111111111111111111111111111111111111111111111111111111111", RepoTags: []string{"app:latest"}, Size: 90_000_000, Created: now.Add(-60 * day).Unix()},
+		{ID: "sha256:2222222222222222222222222222222222222222222222222222222222222222", RepoTags: []string{"app:old"}, Size: 80_000_000, Created: now.Add(-90 * day).Unix()},
+		{ID: "sha256:3333333333333333333333333333333333333333333333333333333333333333", RepoTags: []string{"postgres:16"}, Size: 400_000_000, Created: now.Add(-100 * day).Unix()},
+		{ID: "sha256:4444444444444444444444444444444444444444444444444444444444444444", RepoTags: []string{"redis:7"}, Size: 100_000_000, Created: now.Add(-200 * day).Unix()},
+		{ID: "sha256:5555555555555555555555555555555555555555555555555555555555555555", RepoTags: []string{"<none>:<none>"}, Size: 70_000_000, Created: now.Add(-3 * day).Unix()},
+		{ID: "sha256:6666666666666666666666666666666666666666666666666666666666666666", RepoTags: []string{"nginx:1.25"}, Size: 180_000_000, Created: now.Add(-300 * day).Unix()},
+	}
+	usage := fmt.Sprintf(`{"docker.io/library/app:latest":%q,"docker.io/library/app:old":%q,"sha256:333333333333":%q}`,
+		now.Add(-2*day).Format(time.RFC3339), now.Add(-40*day).Format(time.RFC3339), now.Add(-10*day).Format(time.RFC3339))
+	dir := fs.NewDir(t, "gc",
+		fs.WithFile(usageFileName, usage),
+		fs.WithFile("images.lock", "# images of the project\nredis:7\n\nmissing:1.0\n"),
+	)
+	defer dir.Remove()
+
+	testCases := []struct {
+		name    string
+		args    []string
+		removed []string
+	}{
+		{
+			name: "dry-run",
+			args: []string{"--dry-run"},
+		},
+		{
+			name:    "force",
+			args:    []string{"--force"},
+			removed: []string{images[1].ID, images[3].ID},
+		},
+		{
+			name: "pins",
+			args: []string{"--force", "--pin", "app:old", "--lockfile", dir.Join("images.lock")},
+		},
+		{
+			name: "max-total-size",
+			args: []string{"--dry-run", "--max-total-size", "300m"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var removed []string
+			cli := test.NewFakeCli(&fakeClient{
+				imageListFunc: func(options image.ListOptions) ([]image.Summary, error) {
+					return images, nil
+				},
+				containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+					assert.Check(t, options.All)
+					return []types.Container{{ImageID: images[5].ID}}, nil
+				},
+				imageInspectFunc: func(ref string) (types.ImageInspect, []byte, error) {
+					for _, img := range images {
+						if img.RepoTags[0] == ref {
+							return types.ImageInspect{ID: img.ID}, nil, nil
+						}
+					}
+					return types.ImageInspect{}, nil, errdefs.NotFound(errors.New("no such image"))
+				},
+				imageRemoveFunc: func(img string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+					assert.Check(t, options.Force)
+					removed = append(removed, img)
+					return []image.DeleteResponse{{Deleted: img}}, nil
+				},
+			})
+			cli.SetConfigFile(&configfile.ConfigFile{Filename: dir.Join("config.json")})
+			cmd := newGCCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("gc-command.%s.golden", tc.name))
+			assert.Check(t, is.DeepEqual(removed, tc.removed))
+		})
+	}
+}
+
+func TestNewGCCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "invalid-generation",
+			args:          []string{"--generation", "0s"},
+			expectedError: "invalid generation: must be a positive duration",
+		},
+		{
+			name:          "missing-lockfile",
+			args:          []string{"--lockfile", "no-such-file.lock"},
+			expectedError: "failed to read lockfile",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newGCCommand(test.NewFakeCli(&fakeClient{}))
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
+
+func TestRecordUse(t *testing.T) {
+	dir := fs.NewDir(t, "usage")
+	defer dir.Remove()
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(&configfile.ConfigFile{Filename: filepath.Join(dir.Path(), "config.json")})
+
+	RecordUse(cli, "ubuntu")
+	RecordUse(cli, "1234567890ab")
+
+	usage, err := loadUsage(filepath.Join(dir.Path(), usageFileName))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(usage, 2))
+
+	img := image.Summary{ID: "sha256:1234567890abcdef", RepoTags: []string{"ubuntu:latest"}}
+	assert.Check(t, !lastUse(usage, img).IsZero())
+	assert.Check(t, lastUse(usage, image.Summary{ID: "sha256:ffff", RepoTags: []string{"ubuntu:22.04"}}).IsZero())
+
+	// Without a configuration file, the use of images isn't recorded.
+	RecordUse(test.NewFakeCli(&fakeClient{}), "ubuntu")
+	_, err = os.Stat(filepath.Join(dir.Path(), "..", usageFileName))
+	assert.Check(t, os.IsNotExist(err))
+}
@@ -3,20 +3,25 @@ package image
 import (
 	"context"
 
+	"errors"
+
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
 	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types"
 	"github.com/spf13/cobra"
 )
 
 type historyOptions struct {
 	image string
 
-	human   bool
-	quiet   bool
-	noTrunc bool
-	format  string
+	human      bool
+	quiet      bool
+	noTrunc    bool
+	format     string
+	dockerfile bool
 }
 
 // NewHistoryCommand creates a new `docker history` command
@@ -34,6 +39,7 @@ func NewHistoryCommand(dockerCli command.Cli) *cobra.Command {
 		Annotations: map[string]string{
 			"aliases": "docker image history, docker history",
 		},
+		ValidArgsFunction: completion.ImageNames(dockerCli),
 	}
 
 	flags := cmd.Flags()
@@ -42,11 +48,15 @@ func NewHistoryCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only show image IDs")
 	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&opts.dockerfile, "dockerfile", false, "Print a Dockerfile reconstructed from the history and config of the image")
 
 	return cmd
 }
 
 func runHistory(ctx context.Context, dockerCli command.Cli, opts historyOptions) error {
+	if opts.dockerfile && (opts.format != "" || opts.quiet) {
+		return errors.New("--dockerfile can't be used with --format or --quiet")
+	}
 	history, err := dockerCli.Client().ImageHistory(ctx, opts.image)
 	if err != nil {
 		return err
@@ -57,10 +67,25 @@ func runHistory(ctx context.Context, dockerCli command.Cli, opts historyOptions)
 		format = formatter.TableFormatKey
 	}
 
+	// The layers of the entries are only shown with --format, as the
+	// image must be inspected to get them.
+	var img types.ImageInspect
+	if opts.dockerfile || format != formatter.TableFormatKey {
+		img, _, err = dockerCli.Client().ImageInspectWithRaw(ctx, opts.image)
+		if err != nil {
+			return err
+		}
+	}
+	defer dockerCli.Out().StartPager()()
+	if opts.dockerfile {
+		return writeDockerfile(dockerCli.Out(), opts.image, img, history)
+	}
+
 	historyCtx := formatter.Context{
 		Output: dockerCli.Out(),
 		Format: NewHistoryFormat(format, opts.quiet, opts.human),
-		Trunc:  !opts.noTrunc,
+		// The JSON format is meant for scripts, so it's never truncated.
+		Trunc: !opts.noTrunc && format != formatter.JSONFormatKey,
 	}
-	return HistoryWrite(historyCtx, opts.human, history)
+	return historyWrite(historyCtx, opts.human, history, img.RootFS.Layers)
 }
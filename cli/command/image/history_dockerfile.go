@@ -0,0 +1,145 @@
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+)
+
+var (
+	// buildArgsPattern matches the build args that prefix the commands of
+	// RUN instructions in the history, such as "|2 A=1 B=2 ".
+	buildArgsPattern = regexp.MustCompile(`^\|\d+ (\S+=\S* )*`)
+	// copyPattern matches the sources of ADD and COPY instructions that the
+	// classic builder records, such as "file:<hash> in /app".
+	copyPattern = regexp.MustCompile(`^(ADD|COPY) (\S+) in (.+)$`)
+	// exposePattern matches the ports of EXPOSE instructions that BuildKit
+	// records, such as "map[80/tcp:{} 443/tcp:{}]".
+	exposePattern = regexp.MustCompile(`^EXPOSE map\[(.*)\]$`)
+)
+
+// writeDockerfile writes a Dockerfile reconstructed from the history and the
+// config of an image. The reconstruction is a best effort: the history
+// doesn't record the sources of files added with ADD and COPY, nor the
+// instructions of the stages of multi-stage builds that precede the final
+// stage, nor, for images built with the classic builder, the instructions
+// that didn't change the image, such as ARG.
+func writeDockerfile(out io.Writer, ref string, img types.ImageInspect, history []image.HistoryResponseItem) error {
+	lines := []string{
+		"# Dockerfile reconstructed from the history of " + ref + ".",
+		"# It is a best effort: instructions, and the sources of ADD and COPY instructions, may be missing.",
+	}
+
+	// The history is ordered from the most recent entry. Entries that are
+	// tagged are the images that the image was built from, so the
+	// instructions start after the most recent of them.
+	start := len(history) - 1
+	from := "scratch"
+	for i := 1; i < len(history); i++ {
+		if len(history[i].Tags) > 0 {
+			start, from = i-1, history[i].Tags[0]
+			break
+		}
+	}
+	lines = append(lines, "FROM "+from)
+
+	seen := map[string]bool{}
+	for i := start; i >= 0; i-- {
+		instruction := historyInstruction(history[i].CreatedBy)
+		if instruction == "" {
+			continue
+		}
+		keyword, _, _ := strings.Cut(instruction, " ")
+		seen[keyword] = true
+		lines = append(lines, instruction)
+	}
+
+	// The config has the metadata that the base image set, and that the
+	// history of images imported or committed may lack.
+	if cfg := img.Config; cfg != nil {
+		if !seen["USER"] && cfg.User != "" {
+			lines = append(lines, "USER "+cfg.User)
+		}
+		if !seen["WORKDIR"] && cfg.WorkingDir != "" {
+			lines = append(lines, "WORKDIR "+cfg.WorkingDir)
+		}
+		if !seen["EXPOSE"] && len(cfg.ExposedPorts) > 0 {
+			ports := make([]string, 0, len(cfg.ExposedPorts))
+			for p := range cfg.ExposedPorts {
+				ports = append(ports, string(p))
+			}
+			sort.Strings(ports)
+			lines = append(lines, "EXPOSE "+strings.Join(ports, " "))
+		}
+		if !seen["ENTRYPOINT"] && len(cfg.Entrypoint) > 0 {
+			lines = append(lines, "ENTRYPOINT "+execForm(cfg.Entrypoint))
+		}
+		if !seen["CMD"] && len(cfg.Cmd) > 0 {
+			lines = append(lines, "CMD "+execForm(cfg.Cmd))
+		}
+	}
+
+	_, err := fmt.Fprintln(out, strings.Join(lines, "\n"))
+	return err
+}
+
+// historyInstruction returns the Dockerfile instruction of the "created by"
+// of an entry of the history of an image, as recorded by BuildKit or by the
+// classic builder, or an empty string if it has none.
+func historyInstruction(createdBy string) string {
+	createdBy = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(createdBy), "# buildkit"))
+	if createdBy == "" {
+		return ""
+	}
+
+	// The classic builder records instructions that don't run a command as
+	// "/bin/sh -c #(nop) INSTRUCTION", and RUN instructions as their command.
+	isRun := true
+	if s, ok := strings.CutPrefix(createdBy, "RUN "); ok {
+		createdBy = s
+	} else if i := strings.Index(createdBy, "#(nop) "); i >= 0 {
+		createdBy, isRun = strings.TrimSpace(createdBy[i+len("#(nop) "):]), false
+	} else if keyword, _, _ := strings.Cut(createdBy, " "); isInstruction(keyword) {
+		isRun = false
+	}
+
+	if isRun {
+		createdBy = buildArgsPattern.ReplaceAllString(createdBy, "")
+		for _, shell := range []string{"/bin/sh -c ", "cmd /S /C "} {
+			if s, ok := strings.CutPrefix(createdBy, shell); ok {
+				createdBy = s
+				break
+			}
+		}
+		return "RUN " + createdBy
+	}
+	if m := copyPattern.FindStringSubmatch(createdBy); m != nil {
+		return strings.TrimSpace(m[1] + " " + m[2] + " " + m[3])
+	}
+	if m := exposePattern.FindStringSubmatch(createdBy); m != nil {
+		return "EXPOSE " + strings.ReplaceAll(m[1], ":{}", "")
+	}
+	return createdBy
+}
+
+func isInstruction(keyword string) bool {
+	switch keyword {
+	case "ADD", "ARG", "CMD", "COPY", "ENTRYPOINT", "ENV", "EXPOSE", "HEALTHCHECK",
+		"LABEL", "MAINTAINER", "ONBUILD", "SHELL", "STOPSIGNAL", "USER", "VOLUME", "WORKDIR":
+		return true
+	}
+	return false
+}
+
+// execForm returns the exec form (JSON array) of the arguments of an
+// instruction.
+func execForm(args []string) string {
+	b, _ := json.Marshal(args)
+	return string(b)
+}
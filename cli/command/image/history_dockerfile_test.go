@@ -0,0 +1,30 @@
+package image
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestHistoryInstruction(t *testing.T) {
+	testCases := []struct {
+		createdBy string
+		expected  string
+	}{
+		{createdBy: "", expected: ""},
+		{createdBy: `/bin/sh -c #(nop)  CMD ["/bin/sh"]`, expected: `CMD ["/bin/sh"]`},
+		{createdBy: "/bin/sh -c #(nop) ADD file:5758b97d8301c84a204a6e5 in / ", expected: "ADD file:5758b97d8301c84a204a6e5 /"},
+		{createdBy: "/bin/sh -c #(nop) COPY dir:0e2a1c3 in /app ", expected: "COPY dir:0e2a1c3 /app"},
+		{createdBy: "/bin/sh -c apt-get update", expected: "RUN apt-get update"},
+		{createdBy: "|2 A=1 B= /bin/sh -c make", expected: "RUN make"},
+		{createdBy: `cmd /S /C powershell -Command "Install-Module x"`, expected: `RUN powershell -Command "Install-Module x"`},
+		{createdBy: "RUN /bin/sh -c go build ./... # buildkit", expected: "RUN go build ./..."},
+		{createdBy: "COPY . /src # buildkit", expected: "COPY . /src"},
+		{createdBy: "ENV PATH=/usr/local/bin:/usr/bin", expected: "ENV PATH=/usr/local/bin:/usr/bin"},
+		{createdBy: "EXPOSE map[443/tcp:{} 80/tcp:{}]", expected: "EXPOSE 443/tcp 80/tcp"},
+	}
+	for _, tc := range testCases {
+		assert.Check(t, is.Equal(historyInstruction(tc.createdBy), tc.expected), tc.createdBy)
+	}
+}
@@ -1,15 +1,19 @@
 package image
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"testing"
 	"time"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -103,3 +107,72 @@ func TestNewHistoryCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+// testBuildHistory is the history of an image built with BuildKit from the
+// "alpine:3.20" image.
+func testBuildHistory(string) ([]image.HistoryResponseItem, error) {
+	return []image.HistoryResponseItem{
+		{ID: "sha256:app", CreatedBy: `CMD ["app", "--serve"]`, Tags: []string{"example/app:latest"}},
+		{ID: "<missing>", CreatedBy: "EXPOSE map[8080/tcp:{}]"},
+		{ID: "<missing>", CreatedBy: "RUN |1 VERSION=1.2.3 /bin/sh -c apk add --no-cache ca-certificates && echo \"all done\" # buildkit", Size: 2048},
+		{ID: "<missing>", CreatedBy: "COPY ./app /usr/local/bin/app # buildkit", Size: 4096},
+		{ID: "<missing>", CreatedBy: "ARG VERSION=1.2.3"},
+		{ID: "<missing>", CreatedBy: "WORKDIR /app"},
+		{ID: "sha256:alpine", CreatedBy: `/bin/sh -c #(nop)  CMD ["/bin/sh"]`, Tags: []string{"alpine:3.20"}},
+		{ID: "<missing>", CreatedBy: "/bin/sh -c #(nop) ADD file:5758b97d8301c84a204a6e5 in / ", Size: 1024},
+	}, nil
+}
+
+func TestNewHistoryCommandJSON(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageHistoryFunc: testBuildHistory,
+		imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{RootFS: types.RootFS{Layers: []string{"sha256:base", "sha256:copy", "sha256:run"}}}, nil, nil
+		},
+	})
+	cmd := NewHistoryCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--format", "json", "example/app"})
+	assert.NilError(t, cmd.Execute())
+
+	type entry struct {
+		ID        string
+		CreatedBy string
+		Layer     string
+	}
+	var entries []entry
+	dec := json.NewDecoder(cli.OutBuffer())
+	for dec.More() {
+		var e entry
+		assert.NilError(t, dec.Decode(&e))
+		entries = append(entries, e)
+	}
+	assert.Assert(t, is.Len(entries, 8))
+	assert.Check(t, is.Equal(entries[0].ID, "sha256:app"))
+	assert.Check(t, is.Equal(entries[2].CreatedBy, "RUN |1 VERSION=1.2.3 /bin/sh -c apk add --no-cache ca-certificates && echo \"all done\" # buildkit"))
+	var layers []string
+	for _, e := range entries {
+		layers = append(layers, e.Layer)
+	}
+	assert.Check(t, is.DeepEqual(layers, []string{"", "", "sha256:run", "sha256:copy", "", "", "", "sha256:base"}))
+}
+
+func TestNewHistoryCommandDockerfile(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageHistoryFunc: testBuildHistory,
+		imageInspectFunc: func(string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{Config: &container.Config{User: "nobody", Cmd: []string{"app", "--serve"}}}, nil, nil
+		},
+	})
+	cmd := NewHistoryCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--dockerfile", "example/app"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "history-command-dockerfile.golden")
+
+	cmd = NewHistoryCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--dockerfile", "--quiet", "example/app"})
+	assert.Check(t, is.Error(cmd.Execute(), "--dockerfile can't be used with --format or --quiet"))
+}
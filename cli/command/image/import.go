@@ -9,7 +9,6 @@ import (
 	"github.com/docker/cli/cli/command"
 	dockeropts "github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/image"
-	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +18,7 @@ type importOptions struct {
 	changes   dockeropts.ListOpts
 	message   string
 	platform  string
+	progress  string
 }
 
 // NewImportCommand creates a new `docker import` command
@@ -47,6 +47,7 @@ func NewImportCommand(dockerCli command.Cli) *cobra.Command {
 	flags.VarP(&options.changes, "change", "c", "Apply Dockerfile instruction to the created image")
 	flags.StringVarP(&options.message, "message", "m", "", "Set commit message for imported image")
 	command.AddPlatformFlag(flags, &options.platform)
+	command.AddProgressFlag(flags, &options.progress)
 
 	return cmd
 }
@@ -88,5 +89,5 @@ func runImport(ctx context.Context, dockerCli command.Cli, options importOptions
 	}
 	defer responseBody.Close()
 
-	return jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Out(), nil)
+	return command.DisplayProgress(responseBody, dockerCli.Out(), options.progress, nil)
 }
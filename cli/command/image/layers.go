@@ -0,0 +1,166 @@
+package image
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLayersTableFormat = "table {{.Digest}}\t{{.Size}}\t{{.CreatedBy}}\t{{.Local}}"
+
+	layerDigestHeader = "DIGEST"
+	layerLocalHeader  = "LOCAL"
+)
+
+type layersOptions struct {
+	image string
+
+	noTrunc bool
+	format  string
+}
+
+func newLayersCommand(dockerCli command.Cli) *cobra.Command {
+	var opts layersOptions
+
+	cmd := &cobra.Command{
+		Use:   "layers [OPTIONS] IMAGE",
+		Short: "List the layers of an image",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runLayers(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.ImageNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	return cmd
+}
+
+// layer is a layer of an image, with the entry of the image's history that
+// created it, if it's known.
+type layer struct {
+	diffID  string
+	history *image.HistoryResponseItem
+}
+
+func runLayers(ctx context.Context, dockerCli command.Cli, opts layersOptions) error {
+	inspect, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, opts.image)
+	if err != nil {
+		return err
+	}
+	history, err := dockerCli.Client().ImageHistory(ctx, opts.image)
+	if err != nil {
+		return err
+	}
+
+	entries := layerHistory(history, len(inspect.RootFS.Layers))
+	layers := make([]layer, 0, len(inspect.RootFS.Layers))
+	for i, diffID := range inspect.RootFS.Layers {
+		l := layer{diffID: diffID}
+		if entries != nil {
+			l.history = &entries[i]
+		}
+		layers = append(layers, l)
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	layersCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newLayersFormat(format),
+		Trunc:  !opts.noTrunc,
+	}
+	return layersWrite(layersCtx, layers)
+}
+
+// newLayersFormat returns a format for use with a layers Context
+func newLayersFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultLayersTableFormat
+	case formatter.CSVFormatKey, formatter.TSVFormatKey:
+		return formatter.Format(defaultLayersTableFormat).Delimited(source)
+	}
+	return formatter.Format(source)
+}
+
+// layersWrite writes formatted layers using the Context
+func layersWrite(ctx formatter.Context, layers []layer) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, l := range layers {
+			if err := format(&layerContext{trunc: ctx.Trunc, l: l}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	layerCtx := layerContext{}
+	layerCtx.Header = formatter.SubHeaderContext{
+		"Digest":    layerDigestHeader,
+		"Size":      formatter.SizeHeader,
+		"CreatedBy": createdByHeader,
+		"Local":     layerLocalHeader,
+	}
+	return ctx.Write(&layerCtx, render)
+}
+
+type layerContext struct {
+	formatter.HeaderContext
+	trunc bool
+	l     layer
+}
+
+func (c *layerContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *layerContext) Digest() string {
+	if c.trunc {
+		return stringid.TruncateID(c.l.diffID)
+	}
+	return c.l.diffID
+}
+
+func (c *layerContext) Size() string {
+	if c.l.history == nil {
+		return "N/A"
+	}
+	return units.HumanSizeWithPrecision(float64(c.l.history.Size), 3)
+}
+
+func (c *layerContext) CreatedBy() string {
+	if c.l.history == nil {
+		return "N/A"
+	}
+	createdBy := strings.ReplaceAll(c.l.history.CreatedBy, "\t", " ")
+	if c.trunc {
+		return formatter.Ellipsis(createdBy, 45)
+	}
+	return createdBy
+}
+
+// Local returns whether the image that the layer was created in exists
+// locally, which is the case for the layers of images that were built
+// locally, but not for the layers of images that were pulled.
+func (c *layerContext) Local() string {
+	if c.l.history == nil {
+		return "N/A"
+	}
+	return strconv.FormatBool(c.l.history.ID != "<missing>")
+}
@@ -0,0 +1,98 @@
+package image
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestNewLayersCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedError    string
+		imageInspectFunc func(img string) (types.ImageInspect, []byte, error)
+	}{
+		{
+			name:          "wrong-args",
+			args:          []string{},
+			expectedError: "requires exactly 1 argument.",
+		},
+		{
+			name:          "client-error",
+			args:          []string{"image:tag"},
+			expectedError: "something went wrong",
+			imageInspectFunc: func(img string) (types.ImageInspect, []byte, error) {
+				return types.ImageInspect{}, nil, errors.Errorf("something went wrong")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newLayersCommand(test.NewFakeCli(&fakeClient{imageInspectFunc: tc.imageInspectFunc}))
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestNewLayersCommandSuccess(t *testing.T) {
+	layers := []string{
+		"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+	}
+	history := []image.HistoryResponseItem{
+		{ID: "sha256:2222222222222222222222222222222222222222222222222222222222222222", CreatedBy: "COPY app /usr/local/bin/app # buildkit", Size: 8_000_000},
+		{ID: "<missing>", CreatedBy: `/bin/sh -c #(nop)  CMD ["bash"]`},
+		{ID: "<missing>", CreatedBy: "/bin/sh -c #(nop) ADD file:0123456789abcdef0123456789abcdef in / ", Size: 77_000_000},
+	}
+
+	testCases := []struct {
+		name    string
+		args    []string
+		history []image.HistoryResponseItem
+	}{
+		{
+			name:    "simple",
+			args:    []string{"image:tag"},
+			history: history,
+		},
+		{
+			name:    "no-trunc",
+			args:    []string{"--no-trunc", "image:tag"},
+			history: history,
+		},
+		{
+			name:    "format",
+			args:    []string{"--format", "{{.Digest}} {{.Local}}", "image:tag"},
+			history: history,
+		},
+		{
+			name: "unknown-history",
+			args: []string{"image:tag"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageInspectFunc: func(img string) (types.ImageInspect, []byte, error) {
+					return types.ImageInspect{RootFS: types.RootFS{Type: "layers", Layers: layers}}, nil, nil
+				},
+				imageHistoryFunc: func(img string) ([]image.HistoryResponseItem, error) {
+					return tc.history, nil
+				},
+			})
+			cmd := newLayersCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), fmt.Sprintf("layers-command-success.%s.golden", tc.name))
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/pkg/jsonmessage"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ociLayoutPrefix is the prefix of the references to OCI image layouts,
+	// such as "oci:./layout:v1".
+	ociLayoutPrefix = "oci:"
+
+	annotationImageName = "io.containerd.image.name"
+)
+
+// registryPortRegexp matches the rest of references to images of a registry
+// named "oci", such as "oci:5000/image", which aren't OCI image layouts.
+var registryPortRegexp = regexp.MustCompile(`^[0-9]+/`)
+
+// ParseOCILayoutReference parses a reference to an OCI image layout, in the
+// form "oci:PATH[:TAG]". PATH is a directory, or a tar archive, and TAG is
+// the reference name of an image of the layout.
+func ParseOCILayoutReference(ref string) (layoutPath, tag string, ok bool) {
+	layoutPath, ok = strings.CutPrefix(ref, ociLayoutPrefix)
+	if !ok || layoutPath == "" || registryPortRegexp.MatchString(layoutPath) {
+		return "", "", false
+	}
+	// A colon at index 1 is the drive letter of a Windows path, such as
+	// "C:\layout".
+	if i := strings.LastIndex(layoutPath, ":"); i > 1 && !strings.ContainsAny(layoutPath[i+1:], `/\`) {
+		layoutPath, tag = layoutPath[:i], layoutPath[i+1:]
+	}
+	return layoutPath, tag, true
+}
+
+// LoadOCILayout loads the images of the OCI image layout at layoutPath, or
+// only the image with the given tag if set, and returns the names of the
+// images that were loaded, or their IDs if they have no name.
+func LoadOCILayout(ctx context.Context, dockerCli command.Cli, layoutPath, tag string) ([]string, error) {
+	archive, err := openOCILayout(layoutPath, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	response, err := dockerCli.Client().ImageLoad(ctx, archive, true)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var lines []string
+	if response.JSON {
+		dec := json.NewDecoder(response.Body)
+		for {
+			var jm jsonmessage.JSONMessage
+			if err := dec.Decode(&jm); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			if jm.Error != nil {
+				return nil, jm.Error
+			}
+			lines = append(lines, strings.Split(jm.Stream, "\n")...)
+		}
+	} else {
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var loaded []string
+	for _, line := range lines {
+		if name, ok := strings.CutPrefix(line, "Loaded image: "); ok {
+			loaded = append(loaded, strings.TrimSpace(name))
+		} else if id, ok := strings.CutPrefix(line, "Loaded image ID: "); ok {
+			loaded = append(loaded, strings.TrimSpace(id))
+		}
+	}
+	return loaded, nil
+}
+
+// openOCILayout returns a tar archive of the OCI image layout at layoutPath,
+// a directory or a tar archive. If tag is set, the index of the archive only
+// has the image of the layout with that reference name.
+func openOCILayout(layoutPath, tag string) (io.ReadCloser, error) {
+	fi, err := os.Stat(layoutPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open OCI image layout")
+	}
+	if !fi.IsDir() && tag == "" {
+		return os.Open(layoutPath)
+	}
+
+	var index []byte
+	if tag != "" {
+		index, err = selectLayoutImage(layoutPath, fi.IsDir(), tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		var err error
+		if fi.IsDir() {
+			err = writeLayoutDirectory(tw, layoutPath, index)
+		} else {
+			err = copyLayoutArchive(tw, layoutPath, index)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// selectLayoutImage returns the index of the OCI image layout at layoutPath
+// with only the image with the given tag.
+func selectLayoutImage(layoutPath string, isDir bool, tag string) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	if isDir {
+		if b, err = os.ReadFile(filepath.Join(layoutPath, "index.json")); err != nil {
+			return nil, errors.Wrapf(err, "invalid OCI image layout %s", layoutPath)
+		}
+	} else if b, err = readLayoutArchiveIndex(layoutPath); err != nil {
+		return nil, err
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, errors.Wrapf(err, "invalid index.json in OCI image layout %s", layoutPath)
+	}
+	for _, m := range index.Manifests {
+		if layoutImageHasTag(m, tag) {
+			index.Manifests = []ocispec.Descriptor{m}
+			return json.Marshal(index)
+		}
+	}
+	return nil, errors.Errorf("OCI image layout %s has no image %s", layoutPath, tag)
+}
+
+func readLayoutArchiveIndex(layoutPath string) ([]byte, error) {
+	f, err := os.Open(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("invalid OCI image layout %s: no index.json", layoutPath)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read OCI image layout %s", layoutPath)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Clean(hdr.Name) == "index.json" {
+			return io.ReadAll(io.LimitReader(tr, maxArchiveMetadataSize))
+		}
+	}
+}
+
+// layoutImageHasTag returns whether the image of an index of an OCI image
+// layout has the given reference name, or is an image with that tag.
+func layoutImageHasTag(desc ocispec.Descriptor, tag string) bool {
+	name := desc.Annotations[ocispec.AnnotationRefName]
+	return name == tag || strings.HasSuffix(name, ":"+tag) || strings.HasSuffix(desc.Annotations[annotationImageName], ":"+tag)
+}
+
+// writeLayoutDirectory writes the OCI image layout in dir to tw, with index
+// instead of its index if set.
+func writeLayoutDirectory(tw *tar.Writer, dir string, index []byte) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil || name == "." {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		if !fi.Mode().IsRegular() && !fi.IsDir() {
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if fi.IsDir() {
+			hdr.Name += "/"
+			return tw.WriteHeader(hdr)
+		}
+		if name == "index.json" && index != nil {
+			hdr.Size = int64(len(index))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err := tw.Write(index)
+			return err
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// copyLayoutArchive copies the OCI image layout in the tar archive at
+// layoutPath to tw, with index instead of its index.
+func copyLayoutArchive(tw *tar.Writer, layoutPath string, index []byte) error {
+	f, err := os.Open(layoutPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read OCI image layout %s", layoutPath)
+		}
+		if path.Clean(hdr.Name) == "index.json" {
+			hdr.Size = int64(len(index))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(index); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// writeOCILayout writes the images of the image archive src, as produced by
+// "docker save", to the OCI image layout in the directory dir, which is
+// created if it doesn't exist. The images are added to the index of the
+// layout, replacing the images with the same reference name. If tag is set,
+// it's the reference name of the images.
+func writeOCILayout(src io.Reader, dir, tag string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrap(err, "failed to create OCI image layout")
+	}
+
+	var index ocispec.Index
+	if b, err := os.ReadFile(filepath.Join(dir, "index.json")); err == nil {
+		if err := json.Unmarshal(b, &index); err != nil {
+			return errors.Wrapf(err, "invalid index.json in OCI image layout %s", dir)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var saved *ocispec.Index
+	tr := tar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read image archive")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Other files than the index and the blobs, such as "manifest.json",
+		// are specific to image archives.
+		switch name := path.Clean(hdr.Name); {
+		case name == "index.json":
+			b, err := io.ReadAll(io.LimitReader(tr, maxArchiveMetadataSize))
+			if err != nil {
+				return errors.Wrap(err, "failed to read image archive")
+			}
+			saved = &ocispec.Index{}
+			if err := json.Unmarshal(b, saved); err != nil {
+				return errors.Wrap(err, "invalid index.json in image archive")
+			}
+		case blobDigest(name).Validate() == nil:
+			if err := writeLayoutBlob(filepath.Join(dir, filepath.FromSlash(name)), tr); err != nil {
+				return err
+			}
+		}
+	}
+	if saved == nil {
+		return errors.New("the daemon doesn't save images as OCI image layouts")
+	}
+
+	for _, m := range saved.Manifests {
+		if tag != "" {
+			annotations := map[string]string{}
+			for k, v := range m.Annotations {
+				annotations[k] = v
+			}
+			annotations[ocispec.AnnotationRefName] = tag
+			m.Annotations = annotations
+		}
+		name := m.Annotations[ocispec.AnnotationRefName]
+		manifests := index.Manifests[:0]
+		for _, existing := range index.Manifests {
+			if existing.Digest == m.Digest && existing.Annotations[ocispec.AnnotationRefName] == name {
+				continue
+			}
+			if name != "" && existing.Annotations[ocispec.AnnotationRefName] == name {
+				continue
+			}
+			manifests = append(manifests, existing)
+		}
+		index.Manifests = append(manifests, m)
+	}
+	index.SchemaVersion = 2
+	index.MediaType = ocispec.MediaTypeImageIndex
+
+	b, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), b, 0o644); err != nil {
+		return err
+	}
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), layout, 0o644)
+}
+
+// writeLayoutBlob writes a blob to p, unless the layout already has it.
+func writeLayoutBlob(p string, r io.Reader) error {
+	if _, err := os.Stat(p); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), p)
+}
@@ -0,0 +1,203 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/image"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseOCILayoutReference(t *testing.T) {
+	testCases := []struct {
+		ref          string
+		expectedPath string
+		expectedTag  string
+		expectedOK   bool
+	}{
+		{ref: "alpine:latest"},
+		{ref: "oci:5000/alpine:latest"},
+		{ref: "oci:"},
+		{ref: "oci:./layout", expectedPath: "./layout", expectedOK: true},
+		{ref: "oci:/tmp/layout:v1", expectedPath: "/tmp/layout", expectedTag: "v1", expectedOK: true},
+		{ref: "oci:C:\\layout", expectedPath: "C:\\layout", expectedOK: true},
+		{ref: "oci:./host:5000/layout", expectedPath: "./host:5000/layout", expectedOK: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.ref, func(t *testing.T) {
+			layoutPath, tag, ok := ParseOCILayoutReference(tc.ref)
+			assert.Check(t, is.Equal(layoutPath, tc.expectedPath))
+			assert.Check(t, is.Equal(tag, tc.expectedTag))
+			assert.Check(t, is.Equal(ok, tc.expectedOK))
+		})
+	}
+}
+
+// savedArchive returns an image archive, as produced by "docker save", of an
+// image with the given reference name, whose manifest is the given content.
+func savedArchive(t *testing.T, refName, content string) ([]byte, digest.Digest) {
+	t.Helper()
+	manifest := []byte(content)
+	dgst := digest.FromBytes(manifest)
+	index, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{{
+			MediaType:   ocispec.MediaTypeImageManifest,
+			Digest:      dgst,
+			Size:        int64(len(manifest)),
+			Annotations: map[string]string{ocispec.AnnotationRefName: refName},
+		}},
+	})
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{
+		{name: "blobs/sha256/" + dgst.Encoded(), content: manifest},
+		{name: "index.json", content: index},
+		{name: "manifest.json", content: []byte("[]")},
+		{name: "oci-layout", content: []byte(`{"imageLayoutVersion":"1.0.0"}`)},
+	} {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(f.content)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return buf.Bytes(), dgst
+}
+
+func readLayoutIndex(t *testing.T, dir string) ocispec.Index {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	assert.NilError(t, err)
+	var index ocispec.Index
+	assert.NilError(t, json.Unmarshal(b, &index))
+	return index
+}
+
+func TestSaveToOCILayout(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "layout")
+	archives := map[string][]byte{}
+	digests := map[string]digest.Digest{}
+	for _, c := range []string{"one", "two"} {
+		archives[c], digests[c] = savedArchive(t, "latest", `{"content":"`+c+`"}`)
+	}
+
+	for _, tc := range []struct {
+		image  string
+		output string
+	}{
+		{image: "one", output: "oci:" + dir},
+		{image: "one", output: "oci:" + dir + ":v1"},
+		{image: "two", output: "oci:" + dir},
+	} {
+		cli := test.NewFakeCli(&fakeClient{
+			imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(archives[images[0]])), nil
+			},
+		})
+		cmd := NewSaveCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs([]string{"-o", tc.output, tc.image})
+		assert.NilError(t, cmd.Execute())
+	}
+
+	// The image tagged "latest" was replaced, and the one tagged "v1" kept.
+	index := readLayoutIndex(t, dir)
+	assert.Assert(t, is.Len(index.Manifests, 2))
+	assert.Check(t, is.Equal(index.Manifests[0].Annotations[ocispec.AnnotationRefName], "v1"))
+	assert.Check(t, is.Equal(index.Manifests[0].Digest, digests["one"]))
+	assert.Check(t, is.Equal(index.Manifests[1].Annotations[ocispec.AnnotationRefName], "latest"))
+	assert.Check(t, is.Equal(index.Manifests[1].Digest, digests["two"]))
+
+	_, err := os.Stat(filepath.Join(dir, "manifest.json"))
+	assert.Check(t, os.IsNotExist(err))
+	for _, dgst := range digests {
+		_, err := os.Stat(filepath.Join(dir, "blobs", "sha256", dgst.Encoded()))
+		assert.Check(t, err)
+	}
+}
+
+func TestPullFromOCILayout(t *testing.T) {
+	dir := t.TempDir()
+	for _, tag := range []string{"v1", "v2"} {
+		archive, _ := savedArchive(t, tag, `{"content":"`+tag+`"}`)
+		assert.NilError(t, writeOCILayout(bytes.NewReader(archive), dir, ""))
+	}
+
+	var loadedIndex ocispec.Index
+	cli := test.NewFakeCli(&fakeClient{
+		imageLoadFunc: func(input io.Reader, quiet bool) (image.LoadResponse, error) {
+			assert.Check(t, quiet)
+			tr := tar.NewReader(input)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				assert.NilError(t, err)
+				if hdr.Name == "index.json" {
+					assert.NilError(t, json.NewDecoder(tr).Decode(&loadedIndex))
+				}
+			}
+			return image.LoadResponse{
+				Body: io.NopCloser(strings.NewReader(`{"stream":"Loaded image ID: sha256:abc\n"}`)),
+				JSON: true,
+			}, nil
+		},
+	})
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"oci:" + dir + ":v2"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "sha256:abc\n"))
+	assert.Assert(t, is.Len(loadedIndex.Manifests, 1))
+	assert.Check(t, is.Equal(loadedIndex.Manifests[0].Annotations[ocispec.AnnotationRefName], "v2"))
+}
+
+func TestPullFromOCILayoutErrors(t *testing.T) {
+	dir := t.TempDir()
+	archive, _ := savedArchive(t, "v1", `{}`)
+	assert.NilError(t, writeOCILayout(bytes.NewReader(archive), dir, ""))
+
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"oci:" + dir + ":v2"},
+			expectedError: "has no image v2",
+		},
+		{
+			args:          []string{"oci:" + filepath.Join(dir, "missing")},
+			expectedError: "failed to open OCI image layout",
+		},
+		{
+			args:          []string{"--platform", "linux/arm64", "oci:" + dir},
+			expectedError: "can't be used with an OCI image layout",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cmd := NewPullCommand(cli)
+		cmd.SetOut(io.Discard)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
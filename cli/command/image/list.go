@@ -7,10 +7,12 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/image"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -22,6 +24,9 @@ type imagesOptions struct {
 	noTrunc     bool
 	showDigests bool
 	format      string
+	columns     []string
+	wrap        bool
+	tree        bool
 	filter      opts.FilterOpt
 	calledAs    string
 }
@@ -48,6 +53,7 @@ func NewImagesCommand(dockerCLI command.Cli) *cobra.Command {
 			"category-top": "7",
 			"aliases":      "docker image ls, docker image list, docker images",
 		},
+		ValidArgsFunction: completion.ImageNames(dockerCLI),
 	}
 
 	flags := cmd.Flags()
@@ -56,7 +62,10 @@ func NewImagesCommand(dockerCLI command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.all, "all", "a", false, "Show all images (default hides intermediate images)")
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.BoolVar(&options.showDigests, "digests", false, "Show digests")
-	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	flags.StringSliceVar(&options.columns, "columns", nil, flagsHelper.ColumnsHelp)
+	flags.BoolVar(&options.wrap, "wrap", false, flagsHelper.WrapHelp)
+	flags.BoolVar(&options.tree, "tree", false, "List images as a tree of the layers they share")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 
 	return cmd
@@ -70,18 +79,28 @@ func newListCommand(dockerCLI command.Cli) *cobra.Command {
 }
 
 func runImages(ctx context.Context, dockerCLI command.Cli, options imagesOptions) error {
+	if len(options.columns) > 0 && (options.format != "" || options.quiet) {
+		return errors.New("conflicting options: --columns cannot be used together with --format or --quiet")
+	}
+	if options.tree && (options.format != "" || options.quiet || len(options.columns) > 0 || options.showDigests) {
+		return errors.New("conflicting options: --tree cannot be used together with --format, --quiet, --columns, or --digests")
+	}
 	filters := options.filter.Value()
 	if options.matchName != "" {
 		filters.Add("reference", options.matchName)
 	}
 
 	images, err := dockerCLI.Client().ImageList(ctx, image.ListOptions{
-		All:     options.all,
-		Filters: filters,
+		All:        options.all,
+		Filters:    filters,
+		SharedSize: options.tree,
 	})
 	if err != nil {
 		return err
 	}
+	if options.tree {
+		return runImagesTree(ctx, dockerCLI, options, images)
+	}
 
 	format := options.format
 	if len(format) == 0 {
@@ -94,9 +113,11 @@ func runImages(ctx context.Context, dockerCLI command.Cli, options imagesOptions
 
 	imageCtx := formatter.ImageContext{
 		Context: formatter.Context{
-			Output: dockerCLI.Out(),
-			Format: formatter.NewImageFormat(format, options.quiet, options.showDigests),
-			Trunc:  !options.noTrunc,
+			Output:  dockerCLI.Out(),
+			Format:  formatter.NewImageFormat(format, options.quiet, options.showDigests),
+			Trunc:   !options.noTrunc,
+			Columns: options.columns,
+			Wrap:    options.wrap,
 		},
 		Digest: options.showDigests,
 	}
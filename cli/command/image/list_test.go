@@ -33,6 +33,11 @@ func TestNewImagesCommandErrors(t *testing.T) {
 				return []image.Summary{}, errors.Errorf("something went wrong")
 			},
 		},
+		{
+			name:          "tree-format",
+			args:          []string{"--tree", "--format", "{{.ID}}"},
+			expectedError: "conflicting options: --tree cannot be used together with --format, --quiet, --columns, or --digests",
+		},
 	}
 	for _, tc := range testCases {
 		cmd := NewImagesCommand(test.NewFakeCli(&fakeClient{imageListFunc: tc.imageListFunc}))
@@ -7,15 +7,17 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
-	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/moby/sys/sequential"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type loadOptions struct {
-	input string
-	quiet bool
+	input    string
+	quiet    bool
+	progress string
 }
 
 // NewLoadCommand creates a new `docker load` command
@@ -37,15 +39,24 @@ func NewLoadCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 
-	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, instead of STDIN")
+	flags.StringVarP(&opts.input, "input", "i", "", "Read from tar archive file, or OCI image layout (\"oci:PATH[:TAG]\"), instead of STDIN")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the load output")
+	command.AddProgressFlag(flags, &opts.progress)
 
 	return cmd
 }
 
 func runLoad(ctx context.Context, dockerCli command.Cli, opts loadOptions) error {
 	var input io.Reader = dockerCli.In()
-	if opts.input != "" {
+	var size int64
+	if layoutPath, tag, ok := ParseOCILayoutReference(opts.input); ok {
+		archive, err := openOCILayout(layoutPath, tag)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+		input = archive
+	} else if opts.input != "" {
 		// We use sequential.Open to use sequential file access on Windows, avoiding
 		// depleting the standby list un-necessarily. On Linux, this equates to a regular os.Open.
 		file, err := sequential.Open(opts.input)
@@ -54,6 +65,9 @@ func runLoad(ctx context.Context, dockerCli command.Cli, opts loadOptions) error
 		}
 		defer file.Close()
 		input = file
+		if fi, err := file.Stat(); err == nil {
+			size = fi.Size()
+		}
 	}
 
 	// To avoid getting stuck, verify that a tar file is given either in
@@ -62,7 +76,12 @@ func runLoad(ctx context.Context, dockerCli command.Cli, opts loadOptions) error
 		return errors.Errorf("requested load from stdin, but stdin is empty")
 	}
 
-	if !dockerCli.Out().IsTerminal() {
+	if !dockerCli.Out().IsTerminal() && opts.progress != command.ProgressJSON {
+		// The daemon only reports the progress of the load to terminals, so
+		// report the progress of sending the archive to the daemon instead.
+		if !opts.quiet && dockerCli.Err().IsTerminal() {
+			input = progress.NewProgressReader(io.NopCloser(input), streamformatter.NewProgressOutput(dockerCli.Err()), size, "", "Loading")
+		}
 		opts.quiet = true
 	}
 	response, err := dockerCli.Client().ImageLoad(ctx, input, opts.quiet)
@@ -72,7 +91,7 @@ func runLoad(ctx context.Context, dockerCli command.Cli, opts loadOptions) error
 	defer response.Body.Close()
 
 	if response.Body != nil && response.JSON {
-		return jsonmessage.DisplayJSONMessagesToStream(response.Body, dockerCli.Out(), nil)
+		return command.DisplayProgress(response.Body, dockerCli.Out(), opts.progress, nil)
 	}
 
 	_, err = io.Copy(dockerCli.Out(), response.Body)
@@ -0,0 +1,85 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/cli/trust"
+	"github.com/docker/docker/api/types/image"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/sirupsen/logrus"
+)
+
+// pullFromMirrors pulls the image of ref from the mirrors of its registry in
+// the configuration file, in order, and tags it as ref. It returns false if
+// the registry has no mirrors, or if none of them could pull the image, in
+// which case a warning is printed for each mirror.
+func pullFromMirrors(ctx context.Context, dockerCLI command.Cli, ref reference.NamedTagged, opts PullOptions) (bool, error) {
+	mirrors := dockerCLI.ConfigFile().Registries[reference.Domain(ref)].Mirrors
+	if len(mirrors) == 0 {
+		return false, nil
+	}
+	out := dockerCLI.Out()
+	if opts.quiet {
+		out = streams.NewOut(io.Discard)
+	}
+
+	for _, mirror := range mirrors {
+		mirrorRef, err := mirrorReference(mirror, ref)
+		if err == nil {
+			err = pullFromMirror(ctx, dockerCLI, mirrorRef, opts, out)
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			_, _ = fmt.Fprintf(dockerCLI.Err(), "Failed to pull %s from mirror %s: %v\n", reference.FamiliarString(ref), mirror, err)
+			continue
+		}
+
+		if err := dockerCLI.Client().ImageTag(ctx, mirrorRef.String(), ref.String()); err != nil {
+			return false, err
+		}
+		// Only untag the image, which is now also tagged as ref.
+		if _, err := dockerCLI.Client().ImageRemove(ctx, mirrorRef.String(), image.RemoveOptions{}); err != nil {
+			logrus.Debugf("failed to untag %s: %v", mirrorRef, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// mirrorReference returns the reference of the image of ref on a mirror,
+// which has the same path and tag on the host of the mirror.
+func mirrorReference(mirror string, ref reference.NamedTagged) (reference.NamedTagged, error) {
+	mirrorURL, err := url.Parse(mirror)
+	if err != nil {
+		return nil, err
+	}
+	name, err := reference.WithName(mirrorURL.Host + "/" + reference.Path(ref))
+	if err != nil {
+		return nil, err
+	}
+	return reference.WithTag(name, ref.Tag())
+}
+
+func pullFromMirror(ctx context.Context, dockerCLI command.Cli, mirrorRef reference.Named, opts PullOptions, out *streams.Out) error {
+	imgRefAndAuth, err := trust.GetImageReferencesAndAuth(ctx, AuthResolver(dockerCLI), mirrorRef.String())
+	if err != nil {
+		return err
+	}
+	encodedAuth, err := registrytypes.EncodeAuthConfig(*imgRefAndAuth.AuthConfig())
+	if err != nil {
+		return err
+	}
+	options := image.PullOptions{
+		RegistryAuth: encodedAuth,
+		Platform:     opts.platform,
+	}
+	return imagePullOnce(ctx, dockerCLI, mirrorRef.String(), options, out, opts.progress, newResumeTracker())
+}
@@ -0,0 +1,114 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/trust"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// signingConfigPath resolves a path of the "signing" configuration of the
+// CLI, which is relative to the configuration directory unless absolute.
+func signingConfigPath(p string) string {
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(config.Dir(), p)
+}
+
+// notationSigner returns the signer of the key and certificate of the
+// "signing" configuration of the CLI.
+func notationSigner(dockerCLI command.Cli) (*trust.NotationSigner, error) {
+	cfg := dockerCLI.ConfigFile().Signing
+	if cfg == nil || cfg.Key == "" || cfg.Certificate == "" {
+		return nil, errors.New(`no signing key: set the "key" and "certificate" of the "signing" configuration of the CLI to sign images`)
+	}
+	return trust.LoadNotationSigner(signingConfigPath(cfg.Key), signingConfigPath(cfg.Certificate))
+}
+
+// notationTrustPolicy loads the trust policy of the "signing" configuration
+// of the CLI, or the default trust policy.
+func notationTrustPolicy(dockerCLI command.Cli) (*trust.NotationTrustPolicy, error) {
+	filename := trust.NotationTrustPolicyFile()
+	if cfg := dockerCLI.ConfigFile().Signing; cfg != nil && cfg.TrustPolicy != "" {
+		filename = signingConfigPath(cfg.TrustPolicy)
+	}
+	return trust.LoadNotationTrustPolicy(filename)
+}
+
+// signPushed signs the manifest that ref refers to in its registry, once
+// it's pushed, and pushes the signature next to it.
+func signPushed(ctx context.Context, dockerCLI command.Cli, signer *trust.NotationSigner, ref reference.Named, quiet bool) error {
+	rc := dockerCLI.RegistryClient(false)
+	desc, _, err := rc.GetRawManifest(ctx, ref)
+	if err != nil {
+		return errors.Wrapf(err, "failed to sign %s", reference.FamiliarString(ref))
+	}
+	sig, err := signer.Sign(ctx, rc, ref, desc)
+	if err != nil {
+		return errors.Wrapf(err, "failed to sign %s", reference.FamiliarString(ref))
+	}
+	if !quiet {
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Signed %s@%s: %s\n", reference.FamiliarName(ref), desc.Digest, sig.Digest)
+	}
+	return nil
+}
+
+// VerifyNotationSignature verifies the Notation signatures of the manifest
+// with the given digest in the repository of ref against the trust policy of
+// the "signing" configuration of the CLI, or the default trust policy.
+func VerifyNotationSignature(ctx context.Context, dockerCLI command.Cli, ref reference.Named, dgst digest.Digest) (trust.NotationVerification, error) {
+	policy, err := notationTrustPolicy(dockerCLI)
+	if err != nil {
+		return trust.NotationVerification{}, err
+	}
+	return policy.Verify(ctx, dockerCLI.RegistryClient(false), ref, dgst)
+}
+
+// verifiedPull verifies the Notation signatures of the manifest that the
+// reference of imgRefAndAuth refers to against the trust policy, and pulls
+// the manifest by its digest, so that the image that is pulled is the image
+// that was verified.
+func verifiedPull(ctx context.Context, dockerCLI command.Cli, imgRefAndAuth trust.ImageRefAndAuth, opts PullOptions) error {
+	ref := imgRefAndAuth.Reference()
+	desc, _, err := dockerCLI.RegistryClient(false).GetRawManifest(ctx, ref)
+	if err != nil {
+		return err
+	}
+	v, err := VerifyNotationSignature(ctx, dockerCLI, ref, desc.Digest)
+	if err != nil {
+		return err
+	}
+	switch {
+	case v.Failure != "":
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: signature verification failed for %s: %s (trust policy %q only audits signatures)\n", reference.FamiliarString(ref), v.Failure, v.Policy)
+	case v.Level == trust.VerificationSkip:
+		_, _ = fmt.Fprintf(dockerCLI.Err(), "WARNING: trust policy %q skips the signature verification of %s\n", v.Policy, reference.FamiliarString(ref))
+	case !opts.quiet:
+		_, _ = fmt.Fprintf(dockerCLI.Out(), "Verified the signature of %s@%s by %q\n", reference.FamiliarName(ref), desc.Digest, v.Signer)
+	}
+
+	verifiedRef, err := reference.WithDigest(reference.TrimNamed(ref), desc.Digest)
+	if err != nil {
+		return err
+	}
+	verifiedImgRefAndAuth, err := trust.GetImageReferencesAndAuth(ctx, AuthResolver(dockerCLI), verifiedRef.String())
+	if err != nil {
+		return err
+	}
+	if err := imagePullPrivileged(ctx, dockerCLI, verifiedImgRefAndAuth, opts); err != nil {
+		return err
+	}
+	if _, isCanonical := ref.(reference.Canonical); !isCanonical {
+		if tagged, ok := ref.(reference.NamedTagged); ok {
+			return TagTrusted(ctx, dockerCLI, verifiedRef, tagged)
+		}
+	}
+	return nil
+}
@@ -3,13 +3,21 @@ package image
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/errdefs"
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
@@ -20,6 +28,10 @@ type pruneOptions struct {
 	force  bool
 	all    bool
 	filter opts.FilterOpt
+
+	keepTagged int
+	minAge     time.Duration
+	usedWithin time.Duration
 }
 
 // NewPruneCommand returns a new cobra prune command for images
@@ -49,6 +61,9 @@ func NewPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images, not just dangling ones")
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "until=<timestamp>")`)
+	flags.IntVar(&options.keepTagged, "keep-tagged", 0, "Keep the images of the N most recently created tags of each repository")
+	flags.DurationVar(&options.minAge, "min-age", 0, "Only remove images that were created at least this long ago")
+	flags.DurationVar(&options.usedWithin, "used-within", 0, "Keep images that were used by a container, or pulled, within this duration")
 
 	return cmd
 }
@@ -61,6 +76,13 @@ Are you sure you want to continue?`
 )
 
 func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint64, output string, err error) {
+	if options.keepTagged < 0 || options.minAge < 0 || options.usedWithin < 0 {
+		return 0, "", errors.New("--keep-tagged, --min-age, and --used-within must not be negative")
+	}
+	if options.keepTagged > 0 && !options.all {
+		return 0, "", errors.New("--keep-tagged can only be used with --all, as dangling images have no tags")
+	}
+
 	pruneFilters := options.filter.Value().Clone()
 	pruneFilters.Add("dangling", strconv.FormatBool(!options.all))
 	pruneFilters = command.PruneFilters(dockerCli, pruneFilters)
@@ -79,7 +101,12 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 		}
 	}
 
-	report, err := dockerCli.Client().ImagesPrune(ctx, pruneFilters)
+	var report image.PruneReport
+	if options.keepTagged > 0 || options.minAge > 0 || options.usedWithin > 0 {
+		report, err = pruneSelected(ctx, dockerCli, options, pruneFilters)
+	} else {
+		report, err = dockerCli.Client().ImagesPrune(ctx, pruneFilters)
+	}
 	if err != nil {
 		return 0, "", err
 	}
@@ -105,6 +132,165 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 	return spaceReclaimed, output, nil
 }
 
+// pruneSelected removes the images that the image prune API would remove,
+// except the images that the --keep-tagged, --min-age, and --used-within
+// options keep, which the API has no filters for. The images are selected by
+// the CLI, and removed one by one.
+func pruneSelected(ctx context.Context, dockerCli command.Cli, options pruneOptions, pruneFilters filters.Args) (image.PruneReport, error) {
+	listFilters := pruneFilters.Clone()
+	if options.all {
+		// For the prune API, "dangling=false" selects dangling images as well
+		// as tagged images, but only tagged images for the image list API.
+		listFilters.Del("dangling", "false")
+	}
+	// The image list API has no "until" and "label!" filters, which are
+	// applied to the listed images.
+	until, notLabels := listFilters.Get("until"), listFilters.Get("label!")
+	for _, v := range until {
+		listFilters.Del("until", v)
+	}
+	for _, v := range notLabels {
+		listFilters.Del("label!", v)
+	}
+	images, err := dockerCli.Client().ImageList(ctx, image.ListOptions{Filters: listFilters})
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+	images, err = filterPruneImages(images, until, notLabels)
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+	containers, err := dockerCli.Client().ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+	var usage map[string]time.Time
+	if options.usedWithin > 0 {
+		if fileName := usageFile(dockerCli); fileName != "" {
+			if usage, err = loadUsage(fileName); err != nil {
+				return image.PruneReport{}, errors.Wrap(err, "failed to load image usage history")
+			}
+		}
+	}
+
+	var report image.PruneReport
+	for _, img := range selectPruneImages(images, containers, usage, options, time.Now()) {
+		dels, err := dockerCli.Client().ImageRemove(ctx, img.ID, image.RemoveOptions{Force: true, PruneChildren: true})
+		if err != nil {
+			if errdefs.IsConflict(err) || errdefs.IsNotFound(err) {
+				// The image is the parent of another image, or was removed
+				// with one of its children, as the prune API does.
+				continue
+			}
+			return report, err
+		}
+		report.ImagesDeleted = append(report.ImagesDeleted, dels...)
+		report.SpaceReclaimed += uint64(img.Size)
+	}
+	return report, nil
+}
+
+// filterPruneImages returns the images that were created before the "until"
+// filters, and that don't have the labels of the "label!" filters.
+func filterPruneImages(images []image.Summary, until, notLabels []string) ([]image.Summary, error) {
+	var untilSeconds int64
+	for _, v := range until {
+		ts, err := timetypes.GetTimestamp(v, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		seconds, _, err := timetypes.ParseTimestamps(ts, 0)
+		if err != nil {
+			return nil, err
+		}
+		if untilSeconds == 0 || seconds < untilSeconds {
+			untilSeconds = seconds
+		}
+	}
+
+	filtered := images[:0:0]
+	for _, img := range images {
+		if untilSeconds != 0 && img.Created >= untilSeconds {
+			continue
+		}
+		if hasAnyLabel(img.Labels, notLabels) {
+			continue
+		}
+		filtered = append(filtered, img)
+	}
+	return filtered, nil
+}
+
+// hasAnyLabel returns whether labels match one of filters, which are label
+// names, or "name=value" pairs.
+func hasAnyLabel(labels map[string]string, filters []string) bool {
+	for _, f := range filters {
+		name, value, hasValue := strings.Cut(f, "=")
+		if v, ok := labels[name]; ok && (!hasValue || v == value) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectPruneImages returns the images to remove: images that no container
+// uses, that were created at least options.minAge ago, that weren't used
+// within options.usedWithin (by a container, or by a pull or run recorded in
+// usage), and that don't have one of the options.keepTagged most recent tags
+// of their repository.
+func selectPruneImages(images []image.Summary, containers []types.Container, usage map[string]time.Time, options pruneOptions, now time.Time) []image.Summary {
+	inUse := make(map[string]bool, len(containers))
+	lastUsed := make(map[string]time.Time, len(containers))
+	for _, c := range containers {
+		inUse[c.ImageID] = true
+		if t := time.Unix(c.Created, 0); t.After(lastUsed[c.ImageID]) {
+			lastUsed[c.ImageID] = t
+		}
+	}
+
+	keep := map[string]bool{}
+	if options.keepTagged > 0 {
+		// The images of each repository, from the most recently created.
+		byRepo := map[string][]image.Summary{}
+		for _, img := range images {
+			for _, tag := range img.RepoTags {
+				ref, err := reference.ParseNormalizedNamed(tag)
+				if err != nil {
+					continue
+				}
+				byRepo[ref.Name()] = append(byRepo[ref.Name()], img)
+			}
+		}
+		for _, repoImages := range byRepo {
+			sort.SliceStable(repoImages, func(i, j int) bool { return repoImages[i].Created > repoImages[j].Created })
+			for i := 0; i < len(repoImages) && i < options.keepTagged; i++ {
+				keep[repoImages[i].ID] = true
+			}
+		}
+	}
+
+	var selected []image.Summary
+	for _, img := range images {
+		if inUse[img.ID] || keep[img.ID] {
+			continue
+		}
+		if options.minAge > 0 && now.Sub(time.Unix(img.Created, 0)) < options.minAge {
+			continue
+		}
+		if options.usedWithin > 0 {
+			last := lastUsed[img.ID]
+			if t := lastUse(usage, img); t.After(last) {
+				last = t
+			}
+			if now.Sub(last) < options.usedWithin {
+				continue
+			}
+		}
+		selected = append(selected, img)
+	}
+	return selected
+}
+
 // RunPrune calls the Image Prune API
 // This returns the amount of space reclaimed and a detailed output string
 func RunPrune(ctx context.Context, dockerCli command.Cli, all bool, filter opts.FilterOpt) (uint64, string, error) {
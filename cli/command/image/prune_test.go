@@ -6,14 +6,19 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/docker/cli/cli/config/configfile"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 	"gotest.tools/v3/golden"
 )
 
@@ -29,6 +34,16 @@ func TestNewPruneCommandErrors(t *testing.T) {
 			args:          []string{"something"},
 			expectedError: "accepts no arguments.",
 		},
+		{
+			name:          "negative-keep-tagged",
+			args:          []string{"--force", "--all", "--keep-tagged", "-1"},
+			expectedError: "--keep-tagged, --min-age, and --used-within must not be negative",
+		},
+		{
+			name:          "keep-tagged-without-all",
+			args:          []string{"--force", "--keep-tagged", "2"},
+			expectedError: "--keep-tagged can only be used with --all, as dangling images have no tags",
+		},
 		{
 			name:          "prune-error",
 			args:          []string{"--force"},
@@ -121,3 +136,76 @@ func TestPrunePromptTermination(t *testing.T) {
 	cmd := NewPruneCommand(cli)
 	test.TerminatePrompt(ctx, t, cmd, cli)
 }
+
+func TestNewPruneCommandSelected(t *testing.T) {
+	now := time.Now()
+	images := []image.Summary{
+		{ID: "sha256:app-v3", RepoTags: []string{"example/app:v3"}, Created: now.Add(-1 * time.Hour).Unix(), Size: 10},
+		{ID: "sha256:app-v2", RepoTags: []string{"example/app:v2"}, Created: now.Add(-48 * time.Hour).Unix(), Size: 10},
+		{ID: "sha256:app-v1", RepoTags: []string{"example/app:v1", "example/app:stable"}, Labels: map[string]string{"com.example.keep": "true"}, Created: now.Add(-72 * time.Hour).Unix(), Size: 10},
+		{ID: "sha256:db", RepoTags: []string{"example/db:latest"}, Created: now.Add(-96 * time.Hour).Unix(), Size: 10},
+		{ID: "sha256:dangling", RepoTags: []string{"<none>:<none>"}, Created: now.Add(-96 * time.Hour).Unix(), Size: 10},
+	}
+	containers := []types.Container{
+		{ImageID: "sha256:db", Created: now.Add(-96 * time.Hour).Unix()},
+	}
+	dir := fs.NewDir(t, "prune", fs.WithFile(usageFileName, fmt.Sprintf(`{"docker.io/example/app:v2":%q}`, now.Add(-2*time.Hour).Format(time.RFC3339))))
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "keep-tagged",
+			args:     []string{"--all", "--keep-tagged", "2"},
+			expected: []string{"sha256:app-v1", "sha256:dangling"},
+		},
+		{
+			name:     "min-age",
+			args:     []string{"--all", "--min-age", "60h"},
+			expected: []string{"sha256:app-v1", "sha256:dangling"},
+		},
+		{
+			name:     "used-within",
+			args:     []string{"--all", "--keep-tagged", "1", "--used-within", "24h"},
+			expected: []string{"sha256:app-v1", "sha256:dangling"},
+		},
+		{
+			name:     "filters",
+			args:     []string{"--all", "--min-age", "2h", "--filter", "until=60h", "--filter", "label!=com.example.keep"},
+			expected: []string{"sha256:dangling"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var removed []string
+			cli := test.NewFakeCli(&fakeClient{
+				imageListFunc: func(options image.ListOptions) ([]image.Summary, error) {
+					assert.Check(t, !options.Filters.Contains("dangling"))
+					assert.Check(t, !options.Filters.Contains("until"))
+					assert.Check(t, !options.Filters.Contains("label!"))
+					return images, nil
+				},
+				containerListFunc: func(options container.ListOptions) ([]types.Container, error) {
+					assert.Check(t, options.All)
+					return containers, nil
+				},
+				imageRemoveFunc: func(img string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+					removed = append(removed, img)
+					return []image.DeleteResponse{{Deleted: img}}, nil
+				},
+				imagesPruneFunc: func(filters.Args) (image.PruneReport, error) {
+					return image.PruneReport{}, errors.New("unexpected prune")
+				},
+			})
+			cli.SetConfigFile(&configfile.ConfigFile{Filename: dir.Join("config.json")})
+			cmd := NewPruneCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(append(tc.args, "--force"))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.DeepEqual(removed, tc.expected))
+			assert.Check(t, is.Contains(cli.OutBuffer().String(), fmt.Sprintf("Total reclaimed space: %dB", 10*len(tc.expected))))
+		})
+	}
+}
@@ -1,26 +1,43 @@
 package image
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/containerd/platforms"
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/cli/trust"
+	"github.com/docker/docker/api/types/image"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 // PullOptions defines what and how to pull
 type PullOptions struct {
-	remote    string
-	all       bool
-	platform  string
-	quiet     bool
-	untrusted bool
+	remote        string
+	all           bool
+	allPlatforms  bool
+	platform      string
+	quiet         bool
+	progress      string
+	untrusted     bool
+	maxConcurrent int
+	retries       int
+	retryDelay    time.Duration
+
+	verifySignature bool
 }
 
 // NewPullCommand creates a new `docker pull` command
@@ -28,10 +45,16 @@ func NewPullCommand(dockerCli command.Cli) *cobra.Command {
 	var opts PullOptions
 
 	cmd := &cobra.Command{
-		Use:   "pull [OPTIONS] NAME[:TAG|@DIGEST]",
+		Use:   "pull [OPTIONS] NAME[:TAG|@DIGEST] [NAME[:TAG|@DIGEST]...]",
 		Short: "Download an image from a registry",
-		Args:  cli.ExactArgs(1),
+		Args:  cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if dockerCli.Out().Level() == streams.LevelQuiet {
+				opts.quiet = true
+			}
+			if len(args) > 1 {
+				return runPullMultiple(cmd.Context(), dockerCli, opts, args)
+			}
 			opts.remote = args[0]
 			return RunPull(cmd.Context(), dockerCli, opts)
 		},
@@ -45,47 +68,287 @@ func NewPullCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 
 	flags.BoolVarP(&opts.all, "all-tags", "a", false, "Download all tagged images in the repository")
+	flags.BoolVar(&opts.allPlatforms, "all-platforms", false, "Download the images of all the platforms of a multi-platform image")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress verbose output")
+	flags.IntVar(&opts.maxConcurrent, "max-concurrent", defaultMaxConcurrent, "Maximum number of images to pull at the same time")
+	flags.IntVar(&opts.retries, "retries", 0, "Number of times to retry a pull that failed because of a network error")
+	flags.DurationVar(&opts.retryDelay, "retry-delay", defaultRetryDelay, "Delay before the first retry of a failed pull, doubled after each retry")
 
+	command.AddProgressFlag(flags, &opts.progress)
 	command.AddPlatformFlag(flags, &opts.platform)
 	command.AddTrustVerificationFlags(flags, &opts.untrusted, dockerCli.ContentTrustEnabled())
+	flags.BoolVar(&opts.verifySignature, "verify-signature", false, "Verify the Notation signature of the image against the trust policy")
 
+	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Platforms(dockerCli))
 	return cmd
 }
 
 // RunPull performs a pull against the engine based on the specified options
 func RunPull(ctx context.Context, dockerCLI command.Cli, opts PullOptions) error {
+	if layoutPath, tag, ok := ParseOCILayoutReference(opts.remote); ok {
+		return pullOCILayout(ctx, dockerCLI, opts, layoutPath, tag)
+	}
+	distributionRef, err := pullReference(dockerCLI, opts)
+	if err != nil {
+		return err
+	}
+
+	imgRefAndAuth, err := trust.GetImageReferencesAndAuth(ctx, AuthResolver(dockerCLI), distributionRef.String())
+	if err != nil {
+		return err
+	}
+
+	if opts.allPlatforms {
+		err = pullAllPlatforms(ctx, dockerCLI, imgRefAndAuth, opts)
+	} else {
+		err = pullImage(ctx, dockerCLI, imgRefAndAuth, opts)
+		if err != nil && isNoMatchingPlatform(err) && opts.platform == "" && !opts.quiet && dockerCLI.In().IsTerminal() && dockerCLI.Out().IsTerminal() {
+			// The image has no variant for the platform of the daemon;
+			// let the user pick one of the platforms that it has.
+			opts.platform, err = selectPlatform(ctx, dockerCLI, imgRefAndAuth.Reference())
+			if err == nil {
+				err = pullImage(ctx, dockerCLI, imgRefAndAuth, opts)
+			}
+		}
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "when fetching 'plugin'") {
+			return errors.New(err.Error() + " - Use `docker plugin install`")
+		}
+		return err
+	}
+	if !opts.all {
+		RecordUse(dockerCLI, distributionRef.String())
+	}
+	if opts.progress != command.ProgressJSON {
+		fmt.Fprintln(dockerCLI.Out(), imgRefAndAuth.Reference().String())
+	}
+	return nil
+}
+
+// pullOCILayout loads the images of an OCI image layout, or only the image
+// with the given tag if set.
+func pullOCILayout(ctx context.Context, dockerCLI command.Cli, opts PullOptions, layoutPath, tag string) error {
+	if opts.all || opts.allPlatforms || opts.platform != "" {
+		return errors.New("--all-tags, --all-platforms, and --platform can't be used with an OCI image layout")
+	}
+	loaded, err := LoadOCILayout(ctx, dockerCLI, layoutPath, tag)
+	if err != nil {
+		return err
+	}
+	for _, name := range loaded {
+		fmt.Fprintln(dockerCLI.Out(), name)
+	}
+	return nil
+}
+
+// pullReference parses the reference of the image to pull, and validates it
+// against the options.
+func pullReference(dockerCLI command.Cli, opts PullOptions) (reference.Named, error) {
 	distributionRef, err := reference.ParseNormalizedNamed(opts.remote)
 	switch {
 	case err != nil:
-		return err
+		return nil, err
 	case opts.all && !reference.IsNameOnly(distributionRef):
-		return errors.New("tag can't be used with --all-tags/-a")
+		return nil, errors.New("tag can't be used with --all-tags/-a")
+	case opts.allPlatforms && opts.all:
+		return nil, errors.New("--all-platforms can't be used with --all-tags/-a")
+	case opts.allPlatforms && opts.platform != "":
+		return nil, errors.New("--all-platforms can't be used with --platform")
+	case opts.verifySignature && opts.all:
+		return nil, errors.New("--verify-signature can't be used with --all-tags/-a")
 	case !opts.all && reference.IsNameOnly(distributionRef):
 		distributionRef = reference.TagNameOnly(distributionRef)
-		if tagged, ok := distributionRef.(reference.Tagged); ok && !opts.quiet {
+		if tagged, ok := distributionRef.(reference.Tagged); ok && !opts.quiet && opts.progress != command.ProgressJSON {
 			fmt.Fprintf(dockerCLI.Out(), "Using default tag: %s\n", tagged.Tag())
 		}
 	}
+	return distributionRef, nil
+}
 
-	imgRefAndAuth, err := trust.GetImageReferencesAndAuth(ctx, AuthResolver(dockerCLI), distributionRef.String())
-	if err != nil {
-		return err
+// runPullMultiple pulls multiple images concurrently, with a combined
+// display of their progress.
+func runPullMultiple(ctx context.Context, dockerCLI command.Cli, opts PullOptions, remotes []string) error {
+	if opts.allPlatforms {
+		return errors.New("--all-platforms can't be used with multiple images")
+	}
+	sequential := !opts.untrusted || opts.verifySignature
+	for _, remote := range remotes {
+		if _, _, ok := ParseOCILayoutReference(remote); ok {
+			sequential = true
+		} else if ref, err := reference.ParseNormalizedNamed(remote); err == nil && len(dockerCLI.ConfigFile().Registries[reference.Domain(ref)].Mirrors) > 0 {
+			sequential = true
+		}
+	}
+	if sequential {
+		// Trusted and verified pulls resolve, pull, and tag the signed
+		// images of each reference, so images are pulled one after
+		// another, as are OCI image layouts, which are loaded, and images
+		// whose registry has mirrors, which are tried in turn.
+		for _, remote := range remotes {
+			opts.remote = remote
+			if err := RunPull(ctx, dockerCLI, opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	refs := make([]reference.Named, 0, len(remotes))
+	transfers := make([]transfer, 0, len(remotes))
+	for _, remote := range remotes {
+		opts.remote = remote
+		distributionRef, err := pullReference(dockerCLI, opts)
+		if err != nil {
+			return err
+		}
+		imgRefAndAuth, err := trust.GetImageReferencesAndAuth(ctx, AuthResolver(dockerCLI), distributionRef.String())
+		if err != nil {
+			return err
+		}
+		encodedAuth, err := registrytypes.EncodeAuthConfig(*imgRefAndAuth.AuthConfig())
+		if err != nil {
+			return err
+		}
+		// Images are pulled without a PrivilegeFunc, as concurrent pulls
+		// can't prompt for credentials at the same time.
+		name, options := reference.FamiliarString(imgRefAndAuth.Reference()), image.PullOptions{
+			RegistryAuth: encodedAuth,
+			All:          opts.all,
+			Platform:     opts.platform,
+		}
+		refs = append(refs, distributionRef)
+		transfers = append(transfers, transfer{
+			name: name,
+			start: func(ctx context.Context) (io.ReadCloser, error) {
+				return dockerCLI.Client().ImagePull(ctx, name, options)
+			},
+			retries:    opts.retries,
+			retryDelay: opts.retryDelay,
+		})
 	}
 
-	// Check if reference has a digest
-	_, isCanonical := distributionRef.(reference.Canonical)
+	out := dockerCLI.Out()
+	if opts.quiet {
+		out = streams.NewOut(io.Discard)
+	}
+	errs := runTransfers(ctx, out, opts.progress, transfers, opts.maxConcurrent, nil)
+	for i, err := range errs {
+		if err == nil {
+			if !opts.all {
+				RecordUse(dockerCLI, refs[i].String())
+			}
+			if opts.progress != command.ProgressJSON {
+				fmt.Fprintln(dockerCLI.Out(), refs[i].String())
+			}
+		}
+	}
+	return transferErrors("pull", transfers, errs)
+}
+
+// pullImage pulls the image of imgRefAndAuth, verifying its signature unless
+// the pull is untrusted or the reference has a digest. Notation signatures
+// are verified instead of Docker Content Trust with "--verify-signature".
+// Untrusted pulls of tags are first tried from the mirrors of the registry,
+// if it has any.
+func pullImage(ctx context.Context, dockerCLI command.Cli, imgRefAndAuth trust.ImageRefAndAuth, opts PullOptions) error {
+	if opts.verifySignature {
+		return verifiedPull(ctx, dockerCLI, imgRefAndAuth, opts)
+	}
+	_, isCanonical := imgRefAndAuth.Reference().(reference.Canonical)
 	if !opts.untrusted && !isCanonical {
-		err = trustedPull(ctx, dockerCLI, imgRefAndAuth, opts)
-	} else {
-		err = imagePullPrivileged(ctx, dockerCLI, imgRefAndAuth, opts)
+		return trustedPull(ctx, dockerCLI, imgRefAndAuth, opts)
 	}
-	if err != nil {
-		if strings.Contains(err.Error(), "when fetching 'plugin'") {
-			return errors.New(err.Error() + " - Use `docker plugin install`")
+	if tagged, ok := imgRefAndAuth.Reference().(reference.NamedTagged); ok && !isCanonical && !opts.all {
+		if pulled, err := pullFromMirrors(ctx, dockerCLI, tagged, opts); pulled || err != nil {
+			return err
 		}
+	}
+	return imagePullPrivileged(ctx, dockerCLI, imgRefAndAuth, opts)
+}
+
+// pullAllPlatforms pulls the image of each platform of a multi-platform
+// image. The platform of the daemon is pulled last, so that the reference
+// refers to it on image stores that store a single platform per reference.
+func pullAllPlatforms(ctx context.Context, dockerCLI command.Cli, imgRefAndAuth trust.ImageRefAndAuth, opts PullOptions) error {
+	imagePlatforms, err := indexPlatforms(ctx, dockerCLI, imgRefAndAuth.Reference())
+	if err != nil {
 		return err
 	}
-	fmt.Fprintln(dockerCLI.Out(), imgRefAndAuth.Reference().String())
+	if len(imagePlatforms) == 0 {
+		// Not a multi-platform image.
+		return pullImage(ctx, dockerCLI, imgRefAndAuth, opts)
+	}
+	if v, err := dockerCLI.Client().ServerVersion(ctx); err == nil {
+		native := platforms.Only(platforms.Normalize(ocispec.Platform{OS: v.Os, Architecture: v.Arch}))
+		sort.SliceStable(imagePlatforms, func(i, j int) bool {
+			return !native.Match(imagePlatforms[i]) && native.Match(imagePlatforms[j])
+		})
+	}
+	for i, p := range imagePlatforms {
+		opts.platform = platforms.Format(p)
+		if !opts.quiet && opts.progress != command.ProgressJSON {
+			_, _ = fmt.Fprintf(dockerCLI.Out(), "Pull (%d of %d): %s\n", i+1, len(imagePlatforms), opts.platform)
+		}
+		if err := pullImage(ctx, dockerCLI, imgRefAndAuth, opts); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// indexPlatforms returns the platforms of the images of the image index
+// that ref refers to, or nothing if ref refers to a single image. Platforms
+// of other manifests, such as the attestations of the images, are omitted.
+func indexPlatforms(ctx context.Context, dockerCLI command.Cli, ref reference.Named) ([]ocispec.Platform, error) {
+	manifests, err := dockerCLI.RegistryClient(false).GetManifestList(ctx, ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the platforms of the image")
+	}
+	var imagePlatforms []ocispec.Platform
+	seen := map[string]bool{}
+	for _, m := range manifests {
+		p := m.Descriptor.Platform
+		if p == nil || p.OS == "unknown" || p.Architecture == "unknown" || seen[platforms.Format(*p)] {
+			continue
+		}
+		seen[platforms.Format(*p)] = true
+		imagePlatforms = append(imagePlatforms, *p)
+	}
+	return imagePlatforms, nil
+}
+
+// isNoMatchingPlatform returns whether err is the error of the daemon when
+// an image index has no image for the requested platform.
+func isNoMatchingPlatform(err error) bool {
+	return strings.Contains(err.Error(), "no matching manifest for") || strings.Contains(err.Error(), "no match for platform in manifest")
+}
+
+// selectPlatform prompts the user to select one of the platforms of the
+// image index that ref refers to.
+func selectPlatform(ctx context.Context, dockerCLI command.Cli, ref reference.Named) (string, error) {
+	imagePlatforms, err := indexPlatforms(ctx, dockerCLI, ref)
+	if err != nil {
+		return "", err
+	}
+	if len(imagePlatforms) == 0 {
+		return "", errors.Errorf("%s has no image for the platform of the daemon", reference.FamiliarString(ref))
+	}
+
+	out := dockerCLI.Out()
+	_, _ = fmt.Fprintf(out, "%s has no image for the platform of the daemon. Available platforms:\n", reference.FamiliarString(ref))
+	for i, p := range imagePlatforms {
+		_, _ = fmt.Fprintf(out, "  %d) %s\n", i+1, platforms.Format(p))
+	}
+	_, _ = fmt.Fprintf(out, "Select a platform to pull [1-%d]: ", len(imagePlatforms))
+
+	line, _, err := bufio.NewReader(dockerCLI.In()).ReadLine()
+	if err != nil {
+		return "", errors.Wrap(err, "error while reading input")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(string(line)))
+	if err != nil || n < 1 || n > len(imagePlatforms) {
+		return "", errors.Errorf("invalid platform selection: %q", strings.TrimSpace(string(line)))
+	}
+	return platforms.Format(imagePlatforms[n-1]), nil
+}
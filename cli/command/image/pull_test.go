@@ -1,17 +1,32 @@
 package image
 
 import (
+	"context"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/notary"
+	"github.com/docker/cli/internal/test/registry"
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 	"gotest.tools/v3/golden"
 )
 
@@ -23,7 +38,7 @@ func TestNewPullCommandErrors(t *testing.T) {
 	}{
 		{
 			name:          "wrong-args",
-			expectedError: "requires exactly 1 argument.",
+			expectedError: "requires at least 1 argument.",
 			args:          []string{},
 		},
 		{
@@ -36,6 +51,16 @@ func TestNewPullCommandErrors(t *testing.T) {
 			expectedError: "tag can't be used with --all-tags/-a",
 			args:          []string{"--all-tags", "image:tag"},
 		},
+		{
+			name:          "all-platforms-with-all-tags",
+			expectedError: "--all-platforms can't be used with --all-tags/-a",
+			args:          []string{"--all-platforms", "--all-tags", "image"},
+		},
+		{
+			name:          "all-platforms-with-platform",
+			expectedError: "--all-platforms can't be used with --platform",
+			args:          []string{"--all-platforms", "--platform", "linux/arm64", "image"},
+		},
 	}
 	for _, tc := range testCases {
 		cli := test.NewFakeCli(&fakeClient{})
@@ -124,3 +149,266 @@ func TestNewPullCommandWithContentTrustErrors(t *testing.T) {
 		assert.ErrorContains(t, err, tc.expectedError)
 	}
 }
+
+type fakeRegistryClient struct {
+	client.RegistryClient
+	getManifestListFunc func(ref reference.Named) ([]types.ImageManifest, error)
+	getRawManifestFunc  func(ref reference.Named) (ocispec.Descriptor, []byte, error)
+	getBlobFunc         func(ref reference.Canonical) ([]byte, error)
+	getReferrersFunc    func(ref reference.Canonical) ([]ocispec.Descriptor, error)
+	getTagsFunc         func(ref reference.Named) ([]string, error)
+}
+
+func (c *fakeRegistryClient) GetManifestList(_ context.Context, ref reference.Named) ([]types.ImageManifest, error) {
+	return c.getManifestListFunc(ref)
+}
+
+func (c *fakeRegistryClient) GetRawManifest(_ context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	return c.getRawManifestFunc(ref)
+}
+
+func (c *fakeRegistryClient) GetBlob(_ context.Context, ref reference.Canonical) ([]byte, error) {
+	return c.getBlobFunc(ref)
+}
+
+func (c *fakeRegistryClient) GetReferrers(_ context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error) {
+	if c.getReferrersFunc == nil {
+		return nil, nil
+	}
+	return c.getReferrersFunc(ref)
+}
+
+func (c *fakeRegistryClient) GetTags(_ context.Context, ref reference.Named) ([]string, error) {
+	return c.getTagsFunc(ref)
+}
+
+func indexManifests(imagePlatforms ...string) func(reference.Named) ([]types.ImageManifest, error) {
+	return func(reference.Named) ([]types.ImageManifest, error) {
+		var manifests []types.ImageManifest
+		for _, p := range imagePlatforms {
+			platform := platforms.MustParse(p)
+			manifests = append(manifests, types.ImageManifest{Descriptor: ocispec.Descriptor{Platform: &platform}})
+		}
+		return manifests, nil
+	}
+}
+
+func TestNewPullCommandAllPlatforms(t *testing.T) {
+	var pulled []string
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			pulled = append(pulled, options.Platform)
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+		serverVersionFunc: func() (dockertypes.Version, error) {
+			return dockertypes.Version{Os: "linux", Arch: "amd64"}, nil
+		},
+	})
+	cli.SetRegistryClient(&fakeRegistryClient{getManifestListFunc: indexManifests("linux/amd64", "linux/arm64/v8", "unknown/unknown")})
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--all-platforms", "image:tag"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(pulled, []string{"linux/arm64/v8", "linux/amd64"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Pull (1 of 2): linux/arm64/v8\nPull (2 of 2): linux/amd64\ndocker.io/library/image:tag\n"))
+}
+
+func TestSelectPlatform(t *testing.T) {
+	ref, err := reference.ParseNormalizedNamed("image:tag")
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		input         string
+		expected      string
+		expectedError string
+	}{
+		{input: "2\n", expected: "windows/amd64"},
+		{input: "3\n", expectedError: `invalid platform selection: "3"`},
+		{input: "\n", expectedError: `invalid platform selection: ""`},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(tc.input))))
+		cli.SetRegistryClient(&fakeRegistryClient{getManifestListFunc: indexManifests("linux/arm64", "windows/amd64")})
+		p, err := selectPlatform(context.Background(), cli, ref)
+		if tc.expectedError != "" {
+			assert.Check(t, is.Error(err, tc.expectedError))
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(p, tc.expected))
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "image:tag has no image for the platform of the daemon. Available platforms:\n  1) linux/arm64\n  2) windows/amd64\nSelect a platform to pull [1-2]: "))
+	}
+}
+
+func TestNewPullCommandMultiple(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int
+	var pulled []string
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			mu.Lock()
+			pulled = append(pulled, ref)
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+	})
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--quiet", "--max-concurrent", "2", "one", "two:tag", "three", "four"})
+	assert.NilError(t, cmd.Execute())
+	sort.Strings(pulled)
+	assert.Check(t, is.DeepEqual(pulled, []string{"four:latest", "one:latest", "three:latest", "two:tag"}))
+	assert.Check(t, maxRunning <= 2, "pulled %d images at the same time", maxRunning)
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "docker.io/library/one:latest\ndocker.io/library/two:tag\ndocker.io/library/three:latest\ndocker.io/library/four:latest\n"))
+}
+
+func TestNewPullCommandMultipleErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--all-platforms", "one", "two"})
+	assert.Check(t, is.Error(cmd.Execute(), "--all-platforms can't be used with multiple images"))
+}
+
+func TestNewPullCommandRetries(t *testing.T) {
+	var attempts int
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			attempts++
+			if attempts == 1 {
+				return io.NopCloser(strings.NewReader(`{"status":"Downloading","id":"0123456789ab","progressDetail":{"current":4000000,"total":10000000}}
+{"errorDetail":{"message":"unexpected EOF"},"error":"unexpected EOF"}`)), nil
+			}
+			return io.NopCloser(strings.NewReader(`{"status":"Downloading","id":"0123456789ab","progressDetail":{"current":4000000,"total":10000000}}
+{"status":"Pull complete","id":"0123456789ab"}`)), nil
+		},
+	})
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--retries", "2", "--retry-delay", "1ms", "image:tag"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(attempts, 2))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Pull failed: unexpected EOF; retrying in 1ms (retry 1 of 2)\n"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "0123456789ab: Resuming download at 4MB\n0123456789ab: Pull complete\ndocker.io/library/image:tag\n"))
+}
+
+func TestNewPullCommandRetriesErrors(t *testing.T) {
+	var attempts int
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			attempts++
+			return nil, errors.New("manifest for image:tag not found: manifest unknown")
+		},
+	})
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--retries", "2", "--retry-delay", "1ms", "image:tag"})
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "manifest unknown"))
+	assert.Check(t, is.Equal(attempts, 1))
+}
+
+func TestNewPullCommandMirrors(t *testing.T) {
+	var pulled []string
+	var tagged, removed string
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			pulled = append(pulled, ref)
+			if strings.HasPrefix(ref, "mirror1.example.com/") {
+				return nil, errors.New("connection refused")
+			}
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+		imageTagFunc: func(img, ref string) error {
+			tagged = img + " " + ref
+			return nil
+		},
+		imageRemoveFunc: func(img string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+			removed = img
+			return nil, nil
+		},
+	})
+	cli.ConfigFile().Registries = map[string]configfile.RegistryConfig{
+		"docker.io": {Mirrors: []string{"https://mirror1.example.com/", "http://mirror2.example.com:5000/"}},
+	}
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"image:tag"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(pulled, []string{"mirror1.example.com/library/image:tag", "mirror2.example.com:5000/library/image:tag"}))
+	assert.Check(t, is.Equal(tagged, "mirror2.example.com:5000/library/image:tag docker.io/library/image:tag"))
+	assert.Check(t, is.Equal(removed, "mirror2.example.com:5000/library/image:tag"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Failed to pull image:tag from mirror https://mirror1.example.com/: connection refused\n"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "docker.io/library/image:tag\n"))
+}
+
+func TestNewPullCommandMirrorsFallback(t *testing.T) {
+	var pulled []string
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			pulled = append(pulled, ref)
+			if strings.HasPrefix(ref, "mirror.example.com/") {
+				return nil, errors.New("not found")
+			}
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+	})
+	cli.ConfigFile().Registries = map[string]configfile.RegistryConfig{
+		"docker.io": {Mirrors: []string{"https://mirror.example.com/"}},
+	}
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"image@sha256:" + strings.Repeat("a", 64), "other:tag"})
+	assert.NilError(t, cmd.Execute())
+
+	// Digests aren't pulled from mirrors.
+	assert.Check(t, is.DeepEqual(pulled, []string{"image@sha256:" + strings.Repeat("a", 64), "mirror.example.com/library/other:tag", "other:tag"}))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Failed to pull other:tag from mirror https://mirror.example.com/: not found"))
+}
+
+func TestNewPullCommandVerifySignature(t *testing.T) {
+	signerKey := newTestKey(t)
+	ca, cert := newTestCertificates(t, signerKey, "signer@example.com")
+	r := registry.New()
+	dgst := newTestImage(t, r)
+	dir := fs.NewDir(t, "notation",
+		fs.WithFile("trustpolicy.json", `{"version":"1.0","trustPolicies":[{"name":"example","registryScopes":["docker.io/library/example"],"signatureVerification":{"level":"strict"},"trustStores":["ca:test"],"trustedIdentities":["*"]}]}`),
+		fs.WithDir("truststore", fs.WithDir("x509", fs.WithDir("ca", fs.WithDir("test",
+			fs.WithFile("ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca}))))))))
+
+	var pulled, tagged string
+	cli := test.NewFakeCli(&fakeClient{
+		imagePullFunc: func(ref string, options image.PullOptions) (io.ReadCloser, error) {
+			pulled = ref
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+		imageTagFunc: func(img, ref string) error {
+			tagged = img + " " + ref
+			return nil
+		},
+	})
+	cli.SetRegistryClient(r)
+	cli.ConfigFile().Signing = &configfile.SigningConfig{TrustPolicy: dir.Join("trustpolicy.json")}
+
+	cmd := NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--verify-signature", "example"})
+	assert.Check(t, is.Error(cmd.Execute(), "signature verification failed for example:latest: no signature found"))
+	assert.Check(t, is.Equal(pulled, ""))
+
+	addNotationSignature(t, r, dgst, signerKey, cert)
+	cmd = NewPullCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--verify-signature", "example"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(pulled, "example@"+dgst.String()))
+	assert.Check(t, is.Equal(tagged, "example@"+dgst.String()+" example:latest"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), `Verified the signature of example@`+dgst.String()+` by "signer@example.com"`))
+}
@@ -13,26 +13,30 @@ import (
 	"github.com/containerd/platforms"
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/color"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/cli/trust"
 	"github.com/docker/docker/api/types/auxprogress"
 	"github.com/docker/docker/api/types/image"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/registry"
-	"github.com/morikuni/aec"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type pushOptions struct {
-	all       bool
-	remote    string
-	untrusted bool
-	quiet     bool
-	platform  string
+	all           bool
+	remote        string
+	untrusted     bool
+	quiet         bool
+	progress      string
+	platform      string
+	maxConcurrent int
+	sign          bool
 }
 
 // NewPushCommand creates a new `docker push` command
@@ -40,10 +44,21 @@ func NewPushCommand(dockerCli command.Cli) *cobra.Command {
 	var opts pushOptions
 
 	cmd := &cobra.Command{
-		Use:   "push [OPTIONS] NAME[:TAG]",
+		Use:   "push [OPTIONS] NAME[:TAG] [NAME[:TAG]...|oci:PATH[:TAG]]",
 		Short: "Upload an image to a registry",
-		Args:  cli.ExactArgs(1),
+		Args:  cli.RequiresMinArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if dockerCli.Out().Level() == streams.LevelQuiet {
+				opts.quiet = true
+			}
+			if len(args) == 2 {
+				if layoutPath, tag, ok := ParseOCILayoutReference(args[1]); ok {
+					return pushOCILayout(cmd.Context(), dockerCli, opts, args[0], layoutPath, tag)
+				}
+			}
+			if len(args) > 1 {
+				return runPushMultiple(cmd.Context(), dockerCli, opts, args)
+			}
 			opts.remote = args[0]
 			return RunPush(cmd.Context(), dockerCli, opts)
 		},
@@ -57,7 +72,10 @@ func NewPushCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&opts.all, "all-tags", "a", false, "Push all tags of an image to the repository")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress verbose output")
+	flags.IntVar(&opts.maxConcurrent, "max-concurrent", defaultMaxConcurrent, "Maximum number of images to push at the same time")
+	command.AddProgressFlag(flags, &opts.progress)
 	command.AddTrustSigningFlags(flags, &opts.untrusted, dockerCli.ContentTrustEnabled())
+	flags.BoolVar(&opts.sign, "sign", false, "Sign the image with Notation after pushing it")
 	flags.StringVar(&opts.platform, "platform", os.Getenv("DOCKER_DEFAULT_PLATFORM"),
 		`Push a platform-specific manifest as a single-platform image to the registry.
 'os[/arch[/variant]]': Explicit platform (eg. linux/amd64)`)
@@ -70,32 +88,25 @@ func NewPushCommand(dockerCli command.Cli) *cobra.Command {
 //
 //nolint:gocyclo
 func RunPush(ctx context.Context, dockerCli command.Cli, opts pushOptions) error {
-	var platform *ocispec.Platform
-	if opts.platform != "" {
-		p, err := platforms.Parse(opts.platform)
-		if err != nil {
-			_, _ = fmt.Fprintf(dockerCli.Err(), "Invalid platform %s", opts.platform)
+	var signer *trust.NotationSigner
+	if opts.sign {
+		if opts.all {
+			return errors.New("--sign can't be used with --all-tags/-a")
+		}
+		// Images are signed with Notation instead of Docker Content Trust.
+		var err error
+		if signer, err = notationSigner(dockerCli); err != nil {
 			return err
 		}
-		platform = &p
-
-		printNote(dockerCli, `Selecting a single platform will only push one matching image manifest from a multi-platform image index.
-This means that any other components attached to the multi-platform image index (like Buildkit attestations) won't be pushed.
-If you want to only push a single platform image while preserving the attestations, please use 'docker convert\n'
-`)
+		opts.untrusted = true
 	}
-
-	ref, err := reference.ParseNormalizedNamed(opts.remote)
-	switch {
-	case err != nil:
+	platform, err := pushPlatform(dockerCli, opts)
+	if err != nil {
+		return err
+	}
+	ref, err := pushReference(dockerCli, opts)
+	if err != nil {
 		return err
-	case opts.all && !reference.IsNameOnly(ref):
-		return errors.New("tag can't be used with --all-tags/-a")
-	case !opts.all && reference.IsNameOnly(ref):
-		ref = reference.TagNameOnly(ref)
-		if tagged, ok := ref.(reference.Tagged); ok && !opts.quiet {
-			_, _ = fmt.Fprintf(dockerCli.Out(), "Using default tag: %s\n", tagged.Tag())
-		}
 	}
 
 	// Resolve the Repository name from fqn to RepositoryInfo
@@ -132,18 +143,155 @@ If you want to only push a single platform image while preserving the attestatio
 
 	defer responseBody.Close()
 	if !opts.untrusted {
-		// TODO PushTrustedReference currently doesn't respect `--quiet`
+		// TODO PushTrustedReference currently doesn't respect `--quiet` and `--progress`
 		return PushTrustedReference(dockerCli, repoInfo, ref, authConfig, responseBody)
 	}
 
 	if opts.quiet {
 		err = jsonmessage.DisplayJSONMessagesToStream(responseBody, streams.NewOut(io.Discard), handleAux(dockerCli))
-		if err == nil {
-			fmt.Fprintln(dockerCli.Out(), ref.String())
+	} else {
+		err = command.DisplayProgress(responseBody, dockerCli.Out(), opts.progress, handleAux(dockerCli))
+	}
+	if err == nil && signer != nil {
+		err = signPushed(ctx, dockerCli, signer, ref, opts.quiet)
+	}
+	if err == nil && opts.quiet {
+		fmt.Fprintln(dockerCli.Out(), ref.String())
+	}
+	return err
+}
+
+// pushOCILayout writes the image img to the OCI image layout in the
+// directory layoutPath, with the given tag, or the reference name that the
+// daemon gives it.
+func pushOCILayout(ctx context.Context, dockerCli command.Cli, opts pushOptions, img, layoutPath, tag string) error {
+	if opts.all {
+		return errors.New("--all-tags can't be used with an OCI image layout")
+	}
+	output := ociLayoutPrefix + layoutPath
+	if tag != "" {
+		output += ":" + tag
+	}
+	err := RunSave(ctx, dockerCli, saveOptions{
+		images:   []string{img},
+		output:   output,
+		platform: opts.platform,
+		quiet:    opts.quiet,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(dockerCli.Out(), output)
+	return nil
+}
+
+// pushPlatform parses the platform to push, if any.
+func pushPlatform(dockerCli command.Cli, opts pushOptions) (*ocispec.Platform, error) {
+	if opts.platform == "" {
+		return nil, nil
+	}
+	p, err := platforms.Parse(opts.platform)
+	if err != nil {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "Invalid platform %s", opts.platform)
+		return nil, err
+	}
+
+	printNote(dockerCli, `Selecting a single platform will only push one matching image manifest from a multi-platform image index.
+This means that any other components attached to the multi-platform image index (like Buildkit attestations) won't be pushed.
+If you want to only push a single platform image while preserving the attestations, please use 'docker convert\n'
+`)
+	return &p, nil
+}
+
+// pushReference parses the reference of the image to push, and validates it
+// against the options.
+func pushReference(dockerCli command.Cli, opts pushOptions) (reference.Named, error) {
+	ref, err := reference.ParseNormalizedNamed(opts.remote)
+	switch {
+	case err != nil:
+		return nil, err
+	case opts.all && !reference.IsNameOnly(ref):
+		return nil, errors.New("tag can't be used with --all-tags/-a")
+	case !opts.all && reference.IsNameOnly(ref):
+		ref = reference.TagNameOnly(ref)
+		if tagged, ok := ref.(reference.Tagged); ok && !opts.quiet && opts.progress != command.ProgressJSON {
+			_, _ = fmt.Fprintf(dockerCli.Out(), "Using default tag: %s\n", tagged.Tag())
+		}
+	}
+	return ref, nil
+}
+
+// runPushMultiple pushes multiple images concurrently, with a combined
+// display of their progress.
+func runPushMultiple(ctx context.Context, dockerCli command.Cli, opts pushOptions, remotes []string) error {
+	if !opts.untrusted || opts.sign {
+		// Trusted and signed pushes sign each image once it's pushed, so
+		// images are pushed one after another.
+		for _, remote := range remotes {
+			opts.remote = remote
+			if err := RunPush(ctx, dockerCli, opts); err != nil {
+				return err
+			}
 		}
+		return nil
+	}
+
+	platform, err := pushPlatform(dockerCli, opts)
+	if err != nil {
 		return err
 	}
-	return jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Out(), handleAux(dockerCli))
+	refs := make([]reference.Named, 0, len(remotes))
+	transfers := make([]transfer, 0, len(remotes))
+	for _, remote := range remotes {
+		opts.remote = remote
+		ref, err := pushReference(dockerCli, opts)
+		if err != nil {
+			return err
+		}
+		repoInfo, err := registry.ParseRepositoryInfo(ref)
+		if err != nil {
+			return err
+		}
+		encodedAuth, err := registrytypes.EncodeAuthConfig(command.ResolveAuthConfig(dockerCli.ConfigFile(), repoInfo.Index))
+		if err != nil {
+			return err
+		}
+		// Images are pushed without a PrivilegeFunc, as concurrent pushes
+		// can't prompt for credentials at the same time.
+		name, options := reference.FamiliarString(ref), image.PushOptions{
+			All:          opts.all,
+			RegistryAuth: encodedAuth,
+			Platform:     platform,
+		}
+		refs = append(refs, ref)
+		transfers = append(transfers, transfer{
+			name: name,
+			start: func(ctx context.Context) (io.ReadCloser, error) {
+				return dockerCli.Client().ImagePush(ctx, name, options)
+			},
+		})
+	}
+
+	defer func() {
+		for _, note := range notes {
+			fmt.Fprintln(dockerCli.Err(), "")
+			printNote(dockerCli, note)
+		}
+	}()
+
+	out := dockerCli.Out()
+	if opts.quiet {
+		out = streams.NewOut(io.Discard)
+	}
+	errs := runTransfers(ctx, out, opts.progress, transfers, opts.maxConcurrent, handleAux(dockerCli))
+	if opts.quiet {
+		for i, err := range errs {
+			if err == nil {
+				fmt.Fprintln(dockerCli.Out(), refs[i].String())
+			}
+		}
+	}
+	return transferErrors("push", transfers, errs)
 }
 
 var notes []string
@@ -156,8 +304,8 @@ func handleAux(dockerCli command.Cli) func(jm jsonmessage.JSONMessage) {
 		err := json.Unmarshal(b, &stripped)
 		if err == nil && stripped.ManifestPushedInsteadOfIndex {
 			note := fmt.Sprintf("Not all multiplatform-content is present and only the available single-platform image was pushed\n%s -> %s",
-				aec.RedF.Apply(stripped.OriginalIndex.Digest.String()),
-				aec.GreenF.Apply(stripped.SelectedManifest.Digest.String()),
+				color.Apply(dockerCli.Err(), color.Error, stripped.OriginalIndex.Digest.String()),
+				color.Apply(dockerCli.Err(), color.Success, stripped.SelectedManifest.Digest.String()),
 			)
 			notes = append(notes, note)
 		}
@@ -178,10 +326,6 @@ func handleAux(dockerCli command.Cli) func(jm jsonmessage.JSONMessage) {
 }
 
 func printNote(dockerCli command.Cli, format string, args ...any) {
-	if dockerCli.Err().IsTerminal() {
-		_, _ = fmt.Fprint(dockerCli.Err(), aec.WhiteF.Apply(aec.CyanB.Apply("[ NOTE ]"))+" ")
-	} else {
-		_, _ = fmt.Fprint(dockerCli.Err(), "[ NOTE ] ")
-	}
-	_, _ = fmt.Fprintf(dockerCli.Err(), aec.Bold.Apply(format)+"\n", args...)
+	_, _ = fmt.Fprint(dockerCli.Err(), color.Apply(dockerCli.Err(), color.Notice, "[ NOTE ]")+" ")
+	_, _ = fmt.Fprintf(dockerCli.Err(), color.Apply(dockerCli.Err(), color.Heading, format)+"\n", args...)
 }
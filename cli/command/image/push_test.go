@@ -21,7 +21,7 @@ func TestNewPushCommandErrors(t *testing.T) {
 		{
 			name:          "wrong-args",
 			args:          []string{},
-			expectedError: "requires exactly 1 argument.",
+			expectedError: "requires at least 1 argument.",
 		},
 		{
 			name:          "invalid-name",
@@ -81,3 +81,22 @@ func TestNewPushCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPushCommandMultiple(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imagePushFunc: func(ref string, options image.PushOptions) (io.ReadCloser, error) {
+			switch ref {
+			case "denied:latest":
+				return io.NopCloser(strings.NewReader(`{"errorDetail":{"message":"access denied"},"error":"access denied"}`)), nil
+			case "unreachable:latest":
+				return nil, errors.New("registry unreachable")
+			}
+			return io.NopCloser(strings.NewReader(`{"status":"Pushed","id":"0123456789ab"}`)), nil
+		},
+	})
+	cmd := NewPushCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--quiet", "one", "denied", "two", "unreachable"})
+	assert.Error(t, cmd.Execute(), "failed to push 2 of 4 images:\ndenied:latest: access denied\nunreachable:latest: registry unreachable")
+	assert.Equal(t, cli.OutBuffer().String(), "docker.io/library/one:latest\ndocker.io/library/two:latest\n")
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types/image"
 	"github.com/pkg/errors"
@@ -130,3 +131,17 @@ func TestNewRemoveCommandSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestNewRemoveCommandQuiet(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageRemoveFunc: func(img string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+			return []image.DeleteResponse{{Untagged: img}, {Deleted: "sha256:abc"}}, nil
+		},
+	})
+	cli.Out().SetLevel(streams.LevelQuiet)
+	cmd := NewRemoveCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"image1"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal("", cli.OutBuffer().String()))
+}
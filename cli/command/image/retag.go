@@ -0,0 +1,183 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/api/types/image"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type retagOptions struct {
+	fromPrefix    string
+	toPrefix      string
+	filter        opts.FilterOpt
+	push          bool
+	dryRun        bool
+	maxConcurrent int
+}
+
+// retag is a tag of a local image to rewrite.
+type retag struct {
+	source string
+	target string
+	// previous is the ID of the image that target referred to before it was
+	// tagged, if any, to restore it if the operation fails.
+	previous string
+}
+
+// newRetagCommand creates a new `docker image retag` command
+func newRetagCommand(dockerCli command.Cli) *cobra.Command {
+	options := retagOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "retag [OPTIONS] --from-prefix PREFIX --to-prefix PREFIX",
+		Short: "Tag the images whose name starts with a prefix with another prefix",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRetag(cmd.Context(), dockerCli, options)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.fromPrefix, "from-prefix", "", "Prefix of the tags to rewrite (for example, the registry and namespace of the images)")
+	flags.StringVar(&options.toPrefix, "to-prefix", "", "Prefix that replaces --from-prefix in the new tags")
+	flags.VarP(&options.filter, "filter", "f", "Only rewrite the tags of the images that match the filter (same filters as \"docker image ls\")")
+	flags.BoolVar(&options.push, "push", false, "Push the new tags once all images are tagged")
+	flags.BoolVar(&options.dryRun, "dry-run", false, "Show the new tags without creating them")
+	flags.IntVar(&options.maxConcurrent, "max-concurrent", defaultMaxConcurrent, "Maximum number of images to push at the same time")
+	_ = cmd.MarkFlagRequired("from-prefix")
+	_ = cmd.MarkFlagRequired("to-prefix")
+	return cmd
+}
+
+func runRetag(ctx context.Context, dockerCli command.Cli, options retagOptions) error {
+	images, err := dockerCli.Client().ImageList(ctx, image.ListOptions{Filters: options.filter.Value()})
+	if err != nil {
+		return err
+	}
+	retags, err := retagsForImages(images, options.fromPrefix, options.toPrefix)
+	if err != nil {
+		return err
+	}
+	if len(retags) == 0 {
+		return errors.Errorf("no image tags start with %s", options.fromPrefix)
+	}
+
+	if options.dryRun {
+		for _, r := range retags {
+			_, _ = fmt.Fprintf(dockerCli.Out(), "Would tag %s as %s\n", r.source, r.target)
+		}
+		return nil
+	}
+
+	// The images are tagged as a single operation: if one of the tags fails,
+	// the tags that were already created are reverted.
+	for i, r := range retags {
+		if err := dockerCli.Client().ImageTag(ctx, r.source, r.target); err != nil {
+			if rerr := revertRetags(ctx, dockerCli, retags[:i]); rerr != nil {
+				return errors.Errorf("failed to tag %s as %s: %v; failed to revert the tags that were created: %v", r.source, r.target, err, rerr)
+			}
+			return errors.Errorf("failed to tag %s as %s: %v; no tags were changed", r.source, r.target, err)
+		}
+	}
+	for _, r := range retags {
+		_, _ = fmt.Fprintf(dockerCli.Out(), "Tagged %s as %s\n", r.source, r.target)
+	}
+
+	if !options.push {
+		return nil
+	}
+	targets := make([]string, 0, len(retags))
+	for _, r := range retags {
+		targets = append(targets, r.target)
+	}
+	// Pushed images can't be reverted, so the new tags are kept when a push
+	// fails, so that it can be retried.
+	return runPushMultiple(ctx, dockerCli, pushOptions{
+		untrusted:     !dockerCli.ContentTrustEnabled(),
+		maxConcurrent: options.maxConcurrent,
+	}, targets)
+}
+
+// retagsForImages returns the tags of images that start with fromPrefix, with
+// the tag that replaces fromPrefix with toPrefix. Tags are matched either in
+// their familiar form (as shown by "docker image ls"), or in their fully
+// qualified form, so that "docker.io/library/" matches official images.
+func retagsForImages(images []image.Summary, fromPrefix, toPrefix string) ([]retag, error) {
+	existing := map[string]string{}
+	for _, img := range images {
+		for _, t := range img.RepoTags {
+			existing[t] = img.ID
+		}
+	}
+
+	var retags []retag
+	seen := map[string]string{}
+	for _, img := range images {
+		for _, t := range img.RepoTags {
+			ref, err := reference.ParseNormalizedNamed(t)
+			if err != nil {
+				// Such as "<none>:<none>".
+				continue
+			}
+			var rest string
+			switch {
+			case strings.HasPrefix(t, fromPrefix):
+				rest = strings.TrimPrefix(t, fromPrefix)
+			case strings.HasPrefix(ref.String(), fromPrefix):
+				rest = strings.TrimPrefix(ref.String(), fromPrefix)
+			default:
+				continue
+			}
+			targetRef, err := reference.ParseNormalizedNamed(toPrefix + rest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid tag %s for %s", toPrefix+rest, t)
+			}
+			target := reference.FamiliarString(reference.TagNameOnly(targetRef))
+			if target == reference.FamiliarString(ref) || existing[target] == img.ID {
+				// The image already has the new tag.
+				continue
+			}
+			if source, ok := seen[target]; ok {
+				return nil, errors.Errorf("both %s and %s would be tagged as %s", source, t, target)
+			}
+			seen[target] = t
+			retags = append(retags, retag{source: t, target: target, previous: existing[target]})
+		}
+	}
+	sort.Slice(retags, func(i, j int) bool { return retags[i].source < retags[j].source })
+	return retags, nil
+}
+
+// revertRetags reverts the tags that were created: tags that referred to
+// another image are restored, and the others are removed.
+func revertRetags(ctx context.Context, dockerCli command.Cli, retags []retag) error {
+	var failed []string
+	for _, r := range retags {
+		var err error
+		if r.previous != "" {
+			err = dockerCli.Client().ImageTag(ctx, r.previous, r.target)
+		} else {
+			// The source tag still refers to the image, so removing the new
+			// tag only untags it.
+			_, err = dockerCli.Client().ImageRemove(ctx, r.target, image.RemoveOptions{})
+		}
+		if err != nil {
+			failed = append(failed, r.target)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("could not revert %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package image
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/image"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func retagTestImages(image.ListOptions) ([]image.Summary, error) {
+	return []image.Summary{
+		{ID: "sha256:app", RepoTags: []string{"old.registry.example/team/app:1.0", "old.registry.example/team/app:latest"}},
+		{ID: "sha256:db", RepoTags: []string{"old.registry.example/team/db:2.1"}},
+		{ID: "sha256:moved", RepoTags: []string{"old.registry.example/team/moved:1", "new.registry.example/team/moved:1"}},
+		{ID: "sha256:other", RepoTags: []string{"alpine:latest", "<none>:<none>"}},
+	}, nil
+}
+
+func TestNewRetagCommandDryRun(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageListFunc: retagTestImages,
+		imageTagFunc: func(string, string) error {
+			return errors.New("unexpected tag")
+		},
+	})
+	cmd := newRetagCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--from-prefix", "old.registry.example/", "--to-prefix", "new.registry.example/", "--dry-run"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Would tag old.registry.example/team/app:1.0 as new.registry.example/team/app:1.0
+Would tag old.registry.example/team/app:latest as new.registry.example/team/app:latest
+Would tag old.registry.example/team/db:2.1 as new.registry.example/team/db:2.1
+`))
+}
+
+func TestNewRetagCommandPush(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		tagged []string
+		pushed []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		imageListFunc: retagTestImages,
+		imageTagFunc: func(source, target string) error {
+			tagged = append(tagged, source+" "+target)
+			return nil
+		},
+		imagePushFunc: func(ref string, _ image.PushOptions) (io.ReadCloser, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			pushed = append(pushed, ref)
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+	})
+	cmd := newRetagCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--from-prefix", "docker.io/library/", "--to-prefix", "mirror.example/library/", "--push"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(tagged, []string{"alpine:latest mirror.example/library/alpine:latest"}))
+	sort.Strings(pushed)
+	assert.Check(t, is.DeepEqual(pushed, []string{"mirror.example/library/alpine:latest"}))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Tagged alpine:latest as mirror.example/library/alpine:latest\n"))
+}
+
+func TestNewRetagCommandRevert(t *testing.T) {
+	var tagged, removed []string
+	cli := test.NewFakeCli(&fakeClient{
+		imageListFunc: func(image.ListOptions) ([]image.Summary, error) {
+			return []image.Summary{
+				{ID: "sha256:app", RepoTags: []string{"old.registry.example/app:1"}},
+				{ID: "sha256:db", RepoTags: []string{"old.registry.example/db:1"}},
+				{ID: "sha256:previous", RepoTags: []string{"new.registry.example/app:1"}},
+				{ID: "sha256:web", RepoTags: []string{"old.registry.example/web:1"}},
+			}, nil
+		},
+		imageTagFunc: func(source, target string) error {
+			if source == "old.registry.example/web:1" {
+				return errors.New("no space left on device")
+			}
+			tagged = append(tagged, source+" "+target)
+			return nil
+		},
+		imageRemoveFunc: func(ref string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+			assert.Check(t, !options.Force)
+			removed = append(removed, ref)
+			return []image.DeleteResponse{{Untagged: ref}}, nil
+		},
+	})
+	cmd := newRetagCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--from-prefix", "old.registry.example/", "--to-prefix", "new.registry.example/"})
+	assert.Check(t, is.Error(cmd.Execute(), "failed to tag old.registry.example/web:1 as new.registry.example/web:1: no space left on device; no tags were changed"))
+	assert.Check(t, is.DeepEqual(tagged, []string{
+		"old.registry.example/app:1 new.registry.example/app:1",
+		"old.registry.example/db:1 new.registry.example/db:1",
+		"sha256:previous new.registry.example/app:1",
+	}))
+	assert.Check(t, is.DeepEqual(removed, []string{"new.registry.example/db:1"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+}
+
+func TestNewRetagCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		images        []image.Summary
+		expectedError string
+	}{
+		{
+			name:          "missing prefix",
+			args:          []string{"--from-prefix", "old.registry.example/"},
+			expectedError: `required flag(s) "to-prefix" not set`,
+		},
+		{
+			name:          "no matching tags",
+			args:          []string{"--from-prefix", "other.registry.example/", "--to-prefix", "new.registry.example/"},
+			images:        []image.Summary{{ID: "sha256:app", RepoTags: []string{"old.registry.example/app:1"}}},
+			expectedError: "no image tags start with other.registry.example/",
+		},
+		{
+			name:          "invalid tag",
+			args:          []string{"--from-prefix", "old.registry.example/", "--to-prefix", "new registry/"},
+			images:        []image.Summary{{ID: "sha256:app", RepoTags: []string{"old.registry.example/app:1"}}},
+			expectedError: "invalid tag new registry/app:1 for old.registry.example/app:1",
+		},
+		{
+			name: "conflicting tags",
+			args: []string{"--from-prefix", "old.registry.example/", "--to-prefix", "new.registry.example/"},
+			images: []image.Summary{
+				{ID: "sha256:app", RepoTags: []string{"old.registry.example/app:latest"}},
+				{ID: "sha256:other", RepoTags: []string{"old.registry.example/app"}},
+			},
+			expectedError: "both old.registry.example/app:latest and old.registry.example/app would be tagged as new.registry.example/app:latest",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageListFunc: func(image.ListOptions) ([]image.Summary, error) {
+					return tc.images, nil
+				},
+			})
+			cmd := newRetagCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
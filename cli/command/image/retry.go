@@ -0,0 +1,149 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-units"
+)
+
+const (
+	// defaultRetryDelay is the default delay before the first retry of a
+	// failed pull.
+	defaultRetryDelay = time.Second
+	// maxRetryDelay caps the delay between retries, which doubles after each
+	// retry.
+	maxRetryDelay = time.Minute
+)
+
+// retryableErrors are the errors of pulls that failed because of the
+// connection to the registry, and that may succeed if retried.
+var retryableErrors = []string{
+	"unexpected EOF",
+	"connection reset by peer",
+	"broken pipe",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"net/http: request canceled",
+	"received unexpected HTTP status: 5",
+}
+
+// isRetryable returns whether a pull that failed with err may succeed if
+// retried. Pulls that were canceled, or that failed for other reasons than a
+// network error (such as an image that doesn't exist, or missing
+// credentials), aren't retried.
+func isRetryable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	for _, e := range retryableErrors {
+		if strings.Contains(err.Error(), e) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns the delay before the given retry (starting at 1): the
+// delay is doubled after each retry, up to maxRetryDelay.
+func retryDelay(delay time.Duration, retry int) time.Duration {
+	for i := 1; i < retry && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// waitRetry waits for the delay before a retry, or until ctx is canceled.
+func waitRetry(ctx context.Context, delay time.Duration) error {
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// resumeTracker tracks the download progress of the layers of an image over
+// the attempts of a pull, to show the offsets at which the downloads that
+// were interrupted by a failed attempt resume.
+type resumeTracker struct {
+	// offsets are the offsets that the downloads of the layers reached in
+	// the previous attempts.
+	offsets map[string]int64
+	// resumed are the offsets at which the downloads of the layers resumed
+	// in the current attempt. Layers whose download started over are set to
+	// zero.
+	resumed map[string]int64
+}
+
+func newResumeTracker() *resumeTracker {
+	return &resumeTracker{offsets: map[string]int64{}, resumed: map[string]int64{}}
+}
+
+// retry starts a new attempt of the pull.
+func (r *resumeTracker) retry() {
+	r.resumed = map[string]int64{}
+}
+
+// update records the progress of jm, and marks the progress of downloads
+// that resumed where a previous attempt interrupted them. It returns a message
+// to display before jm when a download resumes, as terminals that aren't
+// interactive don't display progress.
+func (r *resumeTracker) update(jm *jsonmessage.JSONMessage) *jsonmessage.JSONMessage {
+	if jm.ID == "" || jm.Status != "Downloading" || jm.Progress == nil {
+		return nil
+	}
+	var notice *jsonmessage.JSONMessage
+	offset, ok := r.resumed[jm.ID]
+	if !ok {
+		// The first progress of the download in this attempt tells whether
+		// it resumed: the daemon keeps the data that interrupted downloads
+		// already fetched if its image store supports it. Downloads that
+		// resume first report about the offset that the previous attempts
+		// reached, while downloads that start over report a fraction of it.
+		offset = 0
+		if prev := r.offsets[jm.ID]; prev > 0 && jm.Progress.Current >= prev/2 {
+			offset = jm.Progress.Current
+			notice = &jsonmessage.JSONMessage{ID: jm.ID, Status: "Resuming download at " + units.HumanSize(float64(offset))}
+		}
+		r.resumed[jm.ID] = offset
+	}
+	r.offsets[jm.ID] = max(r.offsets[jm.ID], jm.Progress.Current)
+	if offset > 0 {
+		jm.Status = "Downloading (resumed at " + units.HumanSize(float64(offset)) + ")"
+	}
+	return notice
+}
+
+// track returns the progress messages of body, updated by r.
+func (r *resumeTracker) track(body io.Reader) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		dec := json.NewDecoder(body)
+		enc := json.NewEncoder(pw)
+		for {
+			var jm jsonmessage.JSONMessage
+			if err := dec.Decode(&jm); err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if notice := r.update(&jm); notice != nil {
+				_ = enc.Encode(notice)
+			}
+			if err := enc.Encode(jm); err != nil {
+				// The reader was closed.
+				return
+			}
+		}
+	}()
+	return pr
+}
@@ -0,0 +1,64 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Check(t, is.Equal(retryDelay(time.Second, 1), time.Second))
+	assert.Check(t, is.Equal(retryDelay(time.Second, 2), 2*time.Second))
+	assert.Check(t, is.Equal(retryDelay(time.Second, 4), 8*time.Second))
+	assert.Check(t, is.Equal(retryDelay(time.Second, 100), maxRetryDelay))
+}
+
+func TestIsRetryable(t *testing.T) {
+	ctx := context.Background()
+	assert.Check(t, isRetryable(ctx, errors.New("read tcp 10.0.0.2:51234->10.0.0.1:443: read: connection reset by peer")))
+	assert.Check(t, isRetryable(ctx, errors.New("unexpected EOF")))
+	assert.Check(t, isRetryable(ctx, errors.New("received unexpected HTTP status: 503 Service Unavailable")))
+	assert.Check(t, !isRetryable(ctx, nil))
+	assert.Check(t, !isRetryable(ctx, errors.New("manifest for image:tag not found: manifest unknown")))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Check(t, !isRetryable(canceled, errors.New("unexpected EOF")))
+}
+
+func TestResumeTracker(t *testing.T) {
+	progress := func(id string, current int64) *jsonmessage.JSONMessage {
+		return &jsonmessage.JSONMessage{ID: id, Status: "Downloading", Progress: &jsonmessage.JSONProgress{Current: current, Total: 10_000_000}}
+	}
+
+	r := newResumeTracker()
+	for _, jm := range []*jsonmessage.JSONMessage{progress("resumed", 4_000_000), progress("restarted", 6_000_000)} {
+		assert.Check(t, is.Nil(r.update(jm)))
+		assert.Check(t, is.Equal(jm.Status, "Downloading"), "first attempt")
+	}
+
+	r.retry()
+	resumed := progress("resumed", 4_000_000)
+	notice := r.update(resumed)
+	assert.Check(t, is.DeepEqual(notice, &jsonmessage.JSONMessage{ID: "resumed", Status: "Resuming download at 4MB"}))
+	assert.Check(t, is.Equal(resumed.Status, "Downloading (resumed at 4MB)"))
+	resumed = progress("resumed", 5_000_000)
+	assert.Check(t, is.Nil(r.update(resumed)))
+	assert.Check(t, is.Equal(resumed.Status, "Downloading (resumed at 4MB)"))
+
+	restarted := progress("restarted", 100_000)
+	assert.Check(t, is.Nil(r.update(restarted)))
+	assert.Check(t, is.Equal(restarted.Status, "Downloading"))
+	restarted = progress("restarted", 5_000_000)
+	assert.Check(t, is.Nil(r.update(restarted)))
+	assert.Check(t, is.Equal(restarted.Status, "Downloading"))
+
+	started := progress("started", 5_000_000)
+	assert.Check(t, is.Nil(r.update(started)))
+	assert.Check(t, is.Equal(started.Status, "Downloading"))
+}
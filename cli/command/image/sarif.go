@@ -0,0 +1,117 @@
+package image
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli-plugins/scanner"
+)
+
+// sarifLog is a SARIF 2.1.0 log, with the subset of its properties that
+// describes the vulnerabilities of a scan report, so that the report can be
+// uploaded to tools that consume static analysis results.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription *sarifText   `json:"shortDescription,omitempty"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+	Properties       sarifMapping `json:"properties,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+	Kind               string `json:"kind"`
+}
+
+type sarifMapping map[string]any
+
+// sarifLevels are the SARIF levels of the results of vulnerabilities, by
+// severity.
+var sarifLevels = map[string]string{
+	scanner.SeverityCritical:   "error",
+	scanner.SeverityHigh:       "error",
+	scanner.SeverityMedium:     "warning",
+	scanner.SeverityLow:        "note",
+	scanner.SeverityNegligible: "note",
+	scanner.SeverityUnknown:    "none",
+}
+
+// newSARIFLog returns the SARIF log of a scan report, with a rule for each
+// vulnerability, and a result for each vulnerable package.
+func newSARIFLog(report *scanner.Report) sarifLog {
+	driver := sarifDriver{
+		Name:           report.Scanner.Name,
+		Version:        report.Scanner.Version,
+		InformationURI: report.Scanner.URL,
+		Rules:          []sarifRule{},
+	}
+	image := report.Image
+	if report.Digest != "" {
+		image += "@" + report.Digest
+	}
+	results := []sarifResult{}
+	rules := map[string]bool{}
+	for _, v := range report.Vulnerabilities {
+		if !rules[v.ID] {
+			rules[v.ID] = true
+			rule := sarifRule{ID: v.ID, HelpURI: v.URL, Properties: sarifMapping{"severity": v.Severity}}
+			if v.Title != "" {
+				rule.ShortDescription = &sarifText{Text: v.Title}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+		msg := fmt.Sprintf("Package %s %s is vulnerable to %s (%s)", v.Package, v.Version, v.ID, v.Severity)
+		if v.FixedVersion != "" {
+			msg += ", fixed in " + v.FixedVersion
+		}
+		results = append(results, sarifResult{
+			RuleID:  v.ID,
+			Level:   sarifLevels[v.Severity],
+			Message: sarifText{Text: msg},
+			Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{
+				{Name: v.Package, FullyQualifiedName: image + "/" + v.Package, Kind: "package"},
+			}}},
+		})
+	}
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+}
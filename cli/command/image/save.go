@@ -3,17 +3,26 @@ package image
 import (
 	"context"
 	"io"
+	"os"
 
+	"github.com/containerd/platforms"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type saveOptions struct {
-	images []string
-	output string
+	images        []string
+	output        string
+	platform      string
+	excludeLayers []string
+	base          string
+	quiet         bool
 }
 
 // NewSaveCommand creates a new `docker save` command
@@ -36,7 +45,11 @@ func NewSaveCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 
-	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, or OCI image layout (\"oci:PATH[:TAG]\"), instead of STDOUT")
+	flags.StringVar(&opts.platform, "platform", "", `Only save the given platform of multi-platform images ('os[/arch[/variant]]')`)
+	flags.StringSliceVar(&opts.excludeLayers, "exclude-layer", nil, "Exclude a layer from the archive, by digest")
+	flags.StringVar(&opts.base, "base", "", "Exclude the layers of a base image from the archive")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output")
 
 	return cmd
 }
@@ -47,8 +60,19 @@ func RunSave(ctx context.Context, dockerCli command.Cli, opts saveOptions) error
 		return errors.New("cowardly refusing to save to a terminal. Use the -o flag or redirect")
 	}
 
-	if err := command.ValidateOutputPath(opts.output); err != nil {
-		return errors.Wrap(err, "failed to save image")
+	layoutPath, tag, toLayout := ParseOCILayoutReference(opts.output)
+	switch {
+	case toLayout && tag != "" && len(opts.images) > 1:
+		return errors.New("a tag can't be used when saving multiple images to an OCI image layout")
+	case !toLayout:
+		if err := command.ValidateOutputPath(opts.output); err != nil {
+			return errors.Wrap(err, "failed to save image")
+		}
+	}
+
+	filter, err := saveFilter(ctx, dockerCli, opts)
+	if err != nil {
+		return err
 	}
 
 	responseBody, err := dockerCli.Client().ImageSave(ctx, opts.images)
@@ -57,10 +81,76 @@ func RunSave(ctx context.Context, dockerCli command.Cli, opts saveOptions) error
 	}
 	defer responseBody.Close()
 
+	var archive io.Reader = responseBody
+	if !opts.quiet && dockerCli.Err().IsTerminal() {
+		archive = progress.NewProgressReader(responseBody, streamformatter.NewProgressOutput(dockerCli.Err()), 0, "", "Saving")
+	}
+
+	if !filter.empty() {
+		// The metadata of the images may come after their layers, so the
+		// archive is only filtered once it's fully received.
+		tmpFile, err := os.CreateTemp("", "docker-save-")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = tmpFile.Close()
+			_ = os.Remove(tmpFile.Name())
+		}()
+		if _, err := io.Copy(tmpFile, archive); err != nil {
+			return err
+		}
+		if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		pr, pw := io.Pipe()
+		go func() {
+			_ = pw.CloseWithError(filterArchive(tmpFile, pw, filter))
+		}()
+		defer pr.Close()
+		archive = pr
+	}
+
+	if toLayout {
+		return writeOCILayout(archive, layoutPath, tag)
+	}
 	if opts.output == "" {
-		_, err := io.Copy(dockerCli.Out(), responseBody)
+		_, err := io.Copy(dockerCli.Out(), archive)
 		return err
 	}
 
-	return command.CopyToFile(opts.output, responseBody)
+	return command.CopyToFile(opts.output, archive)
+}
+
+// saveFilter returns the filter of the content of the archive.
+func saveFilter(ctx context.Context, dockerCli command.Cli, opts saveOptions) (archiveFilter, error) {
+	var filter archiveFilter
+	if opts.platform != "" {
+		p, err := platforms.Parse(opts.platform)
+		if err != nil {
+			return filter, errors.Wrap(err, "invalid platform")
+		}
+		filter.platform = &p
+	}
+	if len(opts.excludeLayers) > 0 || opts.base != "" {
+		filter.excludeLayers = map[digest.Digest]bool{}
+	}
+	for _, l := range opts.excludeLayers {
+		dgst, err := digest.Parse(l)
+		if err != nil {
+			return filter, errors.Wrapf(err, "invalid layer digest %q", l)
+		}
+		filter.excludeLayers[dgst] = true
+	}
+	if opts.base != "" {
+		base, _, err := dockerCli.Client().ImageInspectWithRaw(ctx, opts.base)
+		if err != nil {
+			return filter, err
+		}
+		for _, l := range base.RootFS.Layers {
+			filter.excludeLayers[digest.Digest(l)] = true
+		}
+	}
+	return filter, nil
 }
@@ -7,9 +7,12 @@ import (
 	"testing"
 
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
 )
 
 func TestNewSaveCommandErrors(t *testing.T) {
@@ -45,6 +48,16 @@ func TestNewSaveCommandErrors(t *testing.T) {
 			args:          []string{"-o", "fakedir/out.tar", "arg1"},
 			expectedError: "failed to save image: invalid output path: directory \"fakedir\" does not exist",
 		},
+		{
+			name:          "invalid platform",
+			args:          []string{"--platform", "linux/AMD64/", "arg1"},
+			expectedError: "invalid platform",
+		},
+		{
+			name:          "invalid layer digest",
+			args:          []string{"--exclude-layer", "0123456789ab", "arg1"},
+			expectedError: `invalid layer digest "0123456789ab"`,
+		},
 		{
 			name:          "output file is irregular",
 			args:          []string{"-o", "/dev/null", "arg1"},
@@ -105,3 +118,26 @@ func TestNewSaveCommandSuccess(t *testing.T) {
 		}
 	}
 }
+
+func TestNewSaveCommandBase(t *testing.T) {
+	a := newTestArchive(t)
+	dir := fs.NewDir(t, "test-save-base")
+	cli := test.NewFakeCli(&fakeClient{
+		imageInspectFunc: func(img string) (types.ImageInspect, []byte, error) {
+			assert.Check(t, is.Equal(img, "base"))
+			return types.ImageInspect{RootFS: types.RootFS{Layers: []string{digest.FromString("base layer").String()}}}, nil, nil
+		},
+		imageSaveFunc: func(images []string) (io.ReadCloser, error) {
+			return io.NopCloser(a.tar(t)), nil
+		},
+	})
+	cmd := NewSaveCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--base", "base", "--platform", "linux/amd64", "-o", dir.Join("out.tar"), "example"})
+	assert.NilError(t, cmd.Execute())
+
+	b, err := os.ReadFile(dir.Join("out.tar"))
+	assert.NilError(t, err)
+	blobs, _ := a.names(t, b)
+	assert.Check(t, is.DeepEqual(blobs, []string{"amd64-config", "amd64-layer", "amd64-manifest", "index"}))
+}
@@ -0,0 +1,262 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli-plugins/scanner"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultScanTableFormat = "table {{.ID}}\t{{.Severity}}\t{{.Package}}\t{{.Version}}\t{{.FixedVersion}}"
+
+	sarifFormatKey = "sarif"
+
+	vulnerabilityIDHeader       = "VULNERABILITY"
+	vulnerabilitySeverityHeader = "SEVERITY"
+	packageHeader               = "PACKAGE"
+	packageVersionHeader        = "VERSION"
+	fixedVersionHeader          = "FIXED IN"
+	vulnerabilityTitleHeader    = "TITLE"
+	vulnerabilityURLHeader      = "URL"
+)
+
+type scanOptions struct {
+	image    string
+	scanner  string
+	platform string
+	severity string
+	format   string
+}
+
+func newScanCommand(dockerCli command.Cli) *cobra.Command {
+	var opts scanOptions
+
+	cmd := &cobra.Command{
+		Use:   "scan [OPTIONS] IMAGE",
+		Short: "Scan an image for vulnerabilities with a scanner plugin",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runScan(cmd.Context(), dockerCli, cmd.Root(), opts)
+		},
+		ValidArgsFunction: completion.ImageNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.scanner, "scanner", "", "Scanner plugin to scan the image with")
+	flags.StringVar(&opts.platform, "platform", "", "Platform of the image to scan, of a multi-platform image")
+	flags.StringVar(&opts.severity, "severity", "", "Only show vulnerabilities at least as severe as this one ("+strings.Join(scanner.Severities(), ", ")+")")
+	flags.StringVar(&opts.format, "format", "", `Format output using a custom template:
+'table':            Print output in table format with column headers (default)
+'table TEMPLATE':   Print output in table format using the given Go template
+'json':             Print the scan report in JSON format
+'sarif':            Print the scan report in SARIF format
+'TEMPLATE':         Print output using the given Go template.
+Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`)
+
+	_ = cmd.RegisterFlagCompletionFunc("scanner", func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		scanners, err := manager.ListScanners(dockerCli, cmd.Root())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		names := make([]string, 0, len(scanners))
+		for _, p := range scanners {
+			names = append(names, p.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = cmd.RegisterFlagCompletionFunc("severity", cobra.FixedCompletions(scanner.Severities(), cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{formatter.TableFormatKey, formatter.JSONFormatKey, sarifFormatKey}, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+func runScan(ctx context.Context, dockerCli command.Cli, rootCmd *cobra.Command, opts scanOptions) error {
+	minimum := scanner.SeverityUnknown
+	if opts.severity != "" {
+		var err error
+		if minimum, err = scanner.ParseSeverity(opts.severity); err != nil {
+			return err
+		}
+	}
+	report, err := ScanImage(ctx, dockerCli, rootCmd, opts.scanner, opts.image, opts.platform)
+	if err != nil {
+		return err
+	}
+	report.Vulnerabilities = report.Filter(minimum)
+
+	switch opts.format {
+	case formatter.JSONFormatKey:
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "    ")
+		return enc.Encode(report)
+	case sarifFormatKey:
+		enc := json.NewEncoder(dockerCli.Out())
+		enc.SetIndent("", "  ")
+		return enc.Encode(newSARIFLog(report))
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	scanCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newScanFormat(format),
+	}
+	if err := scanWrite(scanCtx, report.Vulnerabilities); err != nil {
+		return err
+	}
+	if format == formatter.TableFormatKey {
+		_, _ = fmt.Fprintf(dockerCli.Out(), "\n%s\n", scanSummary(report))
+	}
+	return nil
+}
+
+// ScanImage scans an image with a scanner plugin: the plugin with the given
+// name, or the scanner of the CLI configuration, or the only scanner plugin
+// that's installed.
+func ScanImage(ctx context.Context, dockerCli command.Cli, rootCmd *cobra.Command, name, img, platform string) (*scanner.Report, error) {
+	p, err := selectScanner(dockerCli, rootCmd, name)
+	if err != nil {
+		return nil, err
+	}
+	report, err := p.RunScanner(ctx, dockerCli.Err(), scanner.Request{Image: img, Platform: platform})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to scan %s with %s", img, p.Name)
+	}
+	if report.Scanner.Name == "" {
+		report.Scanner.Name = p.Name
+	}
+	return report, nil
+}
+
+func selectScanner(dockerCli command.Cli, rootCmd *cobra.Command, name string) (*manager.Plugin, error) {
+	scanners, err := manager.ListScanners(dockerCli, rootCmd)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = dockerCli.ConfigFile().Scanner
+	}
+	if name != "" {
+		for i := range scanners {
+			if scanners[i].Name == name {
+				return &scanners[i], nil
+			}
+		}
+		return nil, errors.Errorf("no scanner plugin named %q is installed", name)
+	}
+	switch len(scanners) {
+	case 0:
+		return nil, errors.New("no scanner plugin is installed: install a CLI plugin that scans images to use docker image scan")
+	case 1:
+		return &scanners[0], nil
+	}
+	names := make([]string, 0, len(scanners))
+	for _, p := range scanners {
+		names = append(names, p.Name)
+	}
+	return nil, errors.Errorf("more than one scanner plugin is installed (%s): use --scanner to select one, or set the scanner of the CLI configuration", strings.Join(names, ", "))
+}
+
+// scanSummary summarizes a report, such as "3 vulnerabilities found in
+// alpine:latest by grype 0.74.0 (1 critical, 2 high)".
+func scanSummary(report *scanner.Report) string {
+	by := report.Scanner.Name
+	if report.Scanner.Version != "" {
+		by += " " + report.Scanner.Version
+	}
+	if len(report.Vulnerabilities) == 0 {
+		return fmt.Sprintf("No vulnerabilities found in %s by %s", report.Image, by)
+	}
+	noun := "vulnerabilities"
+	if len(report.Vulnerabilities) == 1 {
+		noun = "vulnerability"
+	}
+	counts := report.Counts()
+	var severities []string
+	for _, s := range scanner.Severities() {
+		if counts[s] > 0 {
+			severities = append(severities, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+	return fmt.Sprintf("%d %s found in %s by %s (%s)", len(report.Vulnerabilities), noun, report.Image, by, strings.Join(severities, ", "))
+}
+
+// newScanFormat returns a format for use with a scan Context
+func newScanFormat(source string) formatter.Format {
+	if source == formatter.TableFormatKey {
+		return defaultScanTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// scanWrite writes formatted vulnerabilities using the Context
+func scanWrite(ctx formatter.Context, vulns []scanner.Vulnerability) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, v := range vulns {
+			if err := format(&vulnerabilityContext{v: v}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	vulnCtx := vulnerabilityContext{}
+	vulnCtx.Header = formatter.SubHeaderContext{
+		"ID":           vulnerabilityIDHeader,
+		"Severity":     vulnerabilitySeverityHeader,
+		"Package":      packageHeader,
+		"Version":      packageVersionHeader,
+		"FixedVersion": fixedVersionHeader,
+		"Title":        vulnerabilityTitleHeader,
+		"URL":          vulnerabilityURLHeader,
+	}
+	return ctx.Write(&vulnCtx, render)
+}
+
+type vulnerabilityContext struct {
+	formatter.HeaderContext
+	v scanner.Vulnerability
+}
+
+func (c *vulnerabilityContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *vulnerabilityContext) ID() string {
+	return c.v.ID
+}
+
+func (c *vulnerabilityContext) Severity() string {
+	return c.v.Severity
+}
+
+func (c *vulnerabilityContext) Package() string {
+	return c.v.Package
+}
+
+func (c *vulnerabilityContext) Version() string {
+	return c.v.Version
+}
+
+func (c *vulnerabilityContext) FixedVersion() string {
+	return c.v.FixedVersion
+}
+
+func (c *vulnerabilityContext) Title() string {
+	return c.v.Title
+}
+
+func (c *vulnerabilityContext) URL() string {
+	return c.v.URL
+}
@@ -0,0 +1,122 @@
+package image
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+const testScanReport = `{
+	"SchemaVersion": "0.1.0",
+	"Scanner": {"Name": "grype", "Version": "0.74.0", "URL": "https://github.com/anchore/grype"},
+	"Image": "alpine:3.19",
+	"Vulnerabilities": [
+		{"ID": "CVE-2024-0727", "Severity": "high", "Package": "libcrypto3", "Version": "3.1.4-r2", "FixedVersion": "3.1.4-r5", "Title": "OpenSSL: denial of service via null dereference"},
+		{"ID": "CVE-2023-42363", "Severity": "medium", "Package": "busybox", "Version": "1.36.1-r15"}
+	]
+}`
+
+// addScannerPlugin adds a scanner plugin that reports report to the plugin
+// directories of cli.
+func addScannerPlugin(t *testing.T, cli *test.FakeCli, name, report string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$1" = "docker-cli-plugin-metadata" ]; then
+	echo '{"SchemaVersion":"0.1.0","Vendor":"Example","Scanner":true}'
+	exit
+fi
+cat <<'REPORT'
+` + report + `
+REPORT
+`
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "docker-"+name), []byte(script), 0o755))
+	cli.ConfigFile().CLIPluginsExtraDirs = append(cli.ConfigFile().CLIPluginsExtraDirs, dir)
+}
+
+func TestNewScanCommand(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	addScannerPlugin(t, cli, "grype", testScanReport)
+
+	cmd := newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"alpine:3.19"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "VULNERABILITY    SEVERITY   PACKAGE      VERSION      FIXED IN\n"+
+		"CVE-2024-0727    high       libcrypto3   3.1.4-r2     3.1.4-r5\n"+
+		"CVE-2023-42363   medium     busybox      1.36.1-r15   \n"+
+		"\n"+
+		"2 vulnerabilities found in alpine:3.19 by grype 0.74.0 (1 high, 1 medium)\n"))
+
+	cli.OutBuffer().Reset()
+	cmd = newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--severity", "high", "--format", "{{.ID}}: {{.Title}}", "alpine:3.19"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "CVE-2024-0727: OpenSSL: denial of service via null dereference\n"))
+}
+
+func TestNewScanCommandSARIF(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	addScannerPlugin(t, cli, "grype", testScanReport)
+
+	cmd := newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--format", "sarif", "alpine:3.19"})
+	assert.NilError(t, cmd.Execute())
+
+	var log sarifLog
+	assert.NilError(t, json.Unmarshal(cli.OutBuffer().Bytes(), &log))
+	assert.Assert(t, is.Len(log.Runs, 1))
+	assert.Check(t, is.Equal(log.Runs[0].Tool.Driver.Name, "grype"))
+	assert.Check(t, is.Len(log.Runs[0].Tool.Driver.Rules, 2))
+	assert.Assert(t, is.Len(log.Runs[0].Results, 2))
+	assert.Check(t, is.Equal(log.Runs[0].Results[0].Level, "error"))
+	assert.Check(t, is.Equal(log.Runs[0].Results[0].Message.Text, "Package libcrypto3 3.1.4-r2 is vulnerable to CVE-2024-0727 (high), fixed in 3.1.4-r5"))
+	assert.Check(t, is.Equal(log.Runs[0].Results[1].Level, "warning"))
+}
+
+func TestNewScanCommandErrors(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"alpine"})
+	assert.Check(t, is.Error(cmd.Execute(), "no scanner plugin is installed: install a CLI plugin that scans images to use docker image scan"))
+
+	addScannerPlugin(t, cli, "grype", testScanReport)
+	addScannerPlugin(t, cli, "trivy", testScanReport)
+	cmd = newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"alpine"})
+	assert.Check(t, is.Error(cmd.Execute(), "more than one scanner plugin is installed (grype, trivy): use --scanner to select one, or set the scanner of the CLI configuration"))
+
+	cli.ConfigFile().Scanner = "trivy"
+	cmd = newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--format", "json", "alpine"})
+	assert.NilError(t, cmd.Execute())
+
+	cmd = newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--scanner", "scout", "alpine"})
+	assert.Check(t, is.Error(cmd.Execute(), `no scanner plugin named "scout" is installed`))
+
+	cmd = newScanCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs([]string{"--severity", "severe", "alpine"})
+	assert.Check(t, is.Error(cmd.Execute(), `invalid severity "severe": must be one of critical, high, medium, low, negligible, unknown`))
+}
@@ -0,0 +1,266 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package image
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// version is a semantic version, such as the version of a tag like "v1.2.3"
+// or "1.25-alpine". Its build metadata is ignored.
+type version struct {
+	core [3]int
+	// parts is the number of parts of the core that were set, so that
+	// constraints such as "~1.2" can treat the missing parts as wildcards.
+	parts      int
+	prerelease []string
+}
+
+// parseVersion parses a tag as a semantic version. Missing minor and patch
+// versions are zero, so that "1.25" is "1.25.0". The suffix of a tag such
+// as "1.25.3-alpine" is a pre-release.
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(s, "v")
+	s, _, _ = strings.Cut(s, "+")
+	s, prerelease, hasPrerelease := strings.Cut(s, "-")
+
+	var v version
+	for i, p := range strings.Split(s, ".") {
+		n, ok := versionNumber(p)
+		if !ok || i == len(v.core) {
+			return version{}, false
+		}
+		v.core[i], v.parts = n, i+1
+	}
+	if hasPrerelease {
+		v.prerelease = strings.Split(prerelease, ".")
+		for _, id := range v.prerelease {
+			if id == "" {
+				return version{}, false
+			}
+		}
+	}
+	return v, true
+}
+
+func versionNumber(s string) (int, bool) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// compareVersions compares versions by their precedence, as defined by the
+// semantic versioning spec: a pre-release precedes its version.
+func compareVersions(a, b version) int {
+	if c := compareCores(a, b); c != 0 {
+		return c
+	}
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		if c := comparePrereleaseIDs(a.prerelease[i], b.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(a.prerelease), len(b.prerelease))
+}
+
+func compareCores(a, b version) int {
+	for i := range a.core {
+		if c := compareInts(a.core[i], b.core[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// comparePrereleaseIDs compares identifiers of pre-releases: numeric
+// identifiers are compared numerically, and precede the others.
+func comparePrereleaseIDs(a, b string) int {
+	na, aNumeric := versionNumber(a)
+	nb, bNumeric := versionNumber(b)
+	switch {
+	case aNumeric && bNumeric:
+		return compareInts(na, nb)
+	case aNumeric:
+		return -1
+	case bNumeric:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// versionComparator is a comparison of versions with a version, such as
+// ">=1.2".
+type versionComparator struct {
+	op string
+	v  version
+}
+
+// versionConstraint is a list of comparators that versions must all match,
+// such as ">=1.2, <2".
+type versionConstraint []versionComparator
+
+// parseVersionConstraint parses a constraint of comparators, separated by
+// commas or spaces. Comparators are a version, with an optional operator:
+//
+//   - "=", or no operator, matches the version, or, if the version is
+//     partial, such as "1.2" or "1.2.x", the versions that it starts with.
+//   - "!=" matches the versions that "=" doesn't match.
+//   - ">", ">=", "<", and "<=" compare versions.
+//   - "~" matches the patch versions of the version ("~1.2.3" is ">=1.2.3,
+//     <1.3"), or the minor versions if only the major version is set.
+//   - "^" matches the versions that don't change the first non-zero part of
+//     the version ("^1.2.3" is ">=1.2.3, <2", and "^0.2.3" is ">=0.2.3, <0.3").
+func parseVersionConstraint(s string) (versionConstraint, error) {
+	var constraint versionConstraint
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.Fields(part)
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			if isVersionOperator(field) && i+1 < len(fields) {
+				// An operator separated from its version, such as ">= 1.2".
+				i++
+				field += fields[i]
+			}
+			c, err := parseVersionComparator(field)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid version constraint %q", s)
+			}
+			constraint = append(constraint, c)
+		}
+	}
+	if len(constraint) == 0 {
+		return nil, errors.Errorf("invalid version constraint %q: no comparators", s)
+	}
+	return constraint, nil
+}
+
+var versionOperators = []string{"!=", ">=", "<=", "=", ">", "<", "~", "^"}
+
+func isVersionOperator(s string) bool {
+	for _, op := range versionOperators {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseVersionComparator(s string) (versionComparator, error) {
+	var op string
+	for _, o := range versionOperators {
+		if strings.HasPrefix(s, o) {
+			op, s = o, strings.TrimPrefix(s, o)
+			break
+		}
+	}
+
+	// Wildcards end partial versions, such as "1.2.x".
+	versionString := s
+	for _, wildcard := range []string{".x", ".X", ".*"} {
+		if i := strings.Index(versionString, wildcard); i >= 0 {
+			versionString = versionString[:i]
+		}
+	}
+	v, ok := parseVersion(versionString)
+	if !ok || (len(v.prerelease) > 0 && v.parts < len(v.core)) {
+		return versionComparator{}, errors.Errorf("invalid version %q", s)
+	}
+	return versionComparator{op: op, v: v}, nil
+}
+
+// matches returns whether v matches all the comparators of the constraint.
+func (c versionConstraint) matches(v version) bool {
+	for _, comparator := range c {
+		if !comparator.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c versionComparator) matches(v version) bool {
+	partial := c.v.parts < len(c.v.core)
+	switch c.op {
+	case "", "=":
+		if !partial {
+			return compareVersions(v, c.v) == 0
+		}
+		return compareCores(v, c.v) >= 0 && compareCores(v, c.v.next(c.v.parts)) < 0
+	case "!=":
+		return !versionComparator{v: c.v}.matches(v)
+	case ">":
+		if !partial {
+			return compareVersions(v, c.v) > 0
+		}
+		return compareCores(v, c.v.next(c.v.parts)) >= 0
+	case ">=":
+		return c.lowerBound(v)
+	case "<":
+		if !partial {
+			return compareVersions(v, c.v) < 0
+		}
+		return compareCores(v, c.v) < 0
+	case "<=":
+		if !partial {
+			return compareVersions(v, c.v) <= 0
+		}
+		return compareCores(v, c.v.next(c.v.parts)) < 0
+	case "~":
+		parts := min(c.v.parts, 2)
+		return c.lowerBound(v) && compareCores(v, c.v.next(parts)) < 0
+	case "^":
+		parts := c.v.parts
+		for i := 0; i < c.v.parts; i++ {
+			if c.v.core[i] != 0 {
+				parts = i + 1
+				break
+			}
+		}
+		return c.lowerBound(v) && compareCores(v, c.v.next(parts)) < 0
+	}
+	return false
+}
+
+// lowerBound returns whether v is at least the version of the comparator.
+// The pre-releases of partial versions, such as "1.2.0-alpine" for "1.2",
+// match.
+func (c versionComparator) lowerBound(v version) bool {
+	if c.v.parts < len(c.v.core) {
+		return compareCores(v, c.v) >= 0
+	}
+	return compareVersions(v, c.v) >= 0
+}
+
+// next returns the version that follows the versions that start with the
+// first parts of v, such as "1.3.0" for the first two parts of "1.2.3".
+func (v version) next(parts int) version {
+	var n version
+	copy(n.core[:], v.core[:parts])
+	n.core[parts-1]++
+	n.parts = len(n.core)
+	return n
+}
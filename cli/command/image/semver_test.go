@@ -0,0 +1,77 @@
+package image
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseVersion(t *testing.T) {
+	for _, s := range []string{"1", "v1.2", "1.2.3", "1.25.3-alpine", "2.0.0-rc.1+build.5"} {
+		_, ok := parseVersion(s)
+		assert.Check(t, ok, s)
+	}
+	for _, s := range []string{"", "latest", "1.2.3.4", "1..2", "1.x", "stable-alpine", "1.2-"} {
+		_, ok := parseVersion(s)
+		assert.Check(t, !ok, s)
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		matches    []string
+		others     []string
+	}{
+		{constraint: "1.2.3", matches: []string{"1.2.3", "v1.2.3"}, others: []string{"1.2.4", "1.2.3-alpine"}},
+		{constraint: "1.25", matches: []string{"1.25", "1.25.3", "1.25.3-alpine"}, others: []string{"1.24.9", "1.26.0-rc1", "1.26"}},
+		{constraint: "1.2.x", matches: []string{"1.2.0", "1.2.9"}, others: []string{"1.3.0"}},
+		{constraint: "!=1.2", matches: []string{"1.1.9", "1.3.0"}, others: []string{"1.2.5"}},
+		{constraint: ">=1.2, <2", matches: []string{"1.2.0", "1.9.9"}, others: []string{"1.1.9", "2.0.0", "2.0.0-rc1"}},
+		{constraint: ">= 1.2 < 2", matches: []string{"1.5"}, others: []string{"2.1"}},
+		{constraint: ">1.2", matches: []string{"1.3.0"}, others: []string{"1.2.9"}},
+		{constraint: ">1.2.3", matches: []string{"1.2.4"}, others: []string{"1.2.3", "1.2.3-rc1"}},
+		{constraint: "<=1.2", matches: []string{"1.2.9"}, others: []string{"1.3.0"}},
+		{constraint: "<1.2.3", matches: []string{"1.2.3-rc.1", "1.2.2"}, others: []string{"1.2.3"}},
+		{constraint: "~1.2.3", matches: []string{"1.2.3", "1.2.9"}, others: []string{"1.2.2", "1.3.0"}},
+		{constraint: "~1", matches: []string{"1.0.0", "1.9.0"}, others: []string{"2.0.0"}},
+		{constraint: "^1.2.3", matches: []string{"1.2.3", "1.9.0"}, others: []string{"1.2.2", "2.0.0"}},
+		{constraint: "^0.2.3", matches: []string{"0.2.3", "0.2.9"}, others: []string{"0.3.0"}},
+		{constraint: "^0.0.3", matches: []string{"0.0.3"}, others: []string{"0.0.4"}},
+		{constraint: "1.0.0-rc.2", matches: []string{"1.0.0-rc.2"}, others: []string{"1.0.0-rc.10", "1.0.0"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.constraint, func(t *testing.T) {
+			c, err := parseVersionConstraint(tc.constraint)
+			assert.NilError(t, err)
+			for _, s := range tc.matches {
+				v, ok := parseVersion(s)
+				assert.Assert(t, ok, s)
+				assert.Check(t, c.matches(v), s)
+			}
+			for _, s := range tc.others {
+				v, ok := parseVersion(s)
+				assert.Assert(t, ok, s)
+				assert.Check(t, !c.matches(v), s)
+			}
+		})
+	}
+}
+
+func TestVersionConstraintInvalid(t *testing.T) {
+	for _, s := range []string{"", ",", ">=", "latest", "1.2-rc1", "=>1.2"} {
+		_, err := parseVersionConstraint(s)
+		assert.Check(t, is.ErrorContains(err, "invalid version constraint"), s)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1", "2"}
+	for i := 1; i < len(ordered); i++ {
+		a, _ := parseVersion(ordered[i-1])
+		b, _ := parseVersion(ordered[i])
+		assert.Check(t, is.Equal(compareVersions(a, b), -1), "%s < %s", ordered[i-1], ordered[i])
+		assert.Check(t, is.Equal(compareVersions(b, a), 1), "%s > %s", ordered[i], ordered[i-1])
+	}
+}
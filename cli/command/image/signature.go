@@ -0,0 +1,399 @@
+package image
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/cli/trust"
+	"github.com/docker/docker/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	signatureCosign   = "cosign"
+	signatureNotation = "notation"
+
+	// Media type and annotations of the layers of the signature manifests
+	// that cosign attaches to images, with the "<algorithm>-<digest>.sig"
+	// tag of the manifest of the image.
+	mediaTypeCosignSimpleSigning = "application/vnd.dev.cosign.simplesigning.v1+json"
+	annotationCosignSignature    = "dev.cosignproject.cosign/signature"
+	annotationCosignCertificate  = "dev.sigstore.cosign/certificate"
+	annotationCosignChain        = "dev.sigstore.cosign/chain"
+)
+
+// signaturePolicy is what signatures are verified against.
+type signaturePolicy struct {
+	// key is the public key of key-based signatures.
+	key crypto.PublicKey
+	// roots are the certificates that the certificates of certificate-based
+	// signatures must chain to.
+	roots *x509.CertPool
+	// identity, if set, is the identity that the certificate of
+	// certificate-based signatures must have.
+	identity string
+}
+
+// signature is a signature of an image, and the result of its verification.
+type signature struct {
+	Type     string
+	Digest   digest.Digest
+	Verified bool
+	// Signer is the identity of the certificate that verified the
+	// signature, or "key" if the signature was verified with the key.
+	Signer string `json:",omitempty"`
+	Error  string `json:",omitempty"`
+}
+
+// SignatureOptions are the keys and certificates that the signatures of an
+// image are verified with, as set with the "--key", "--certificate-chain",
+// and "--certificate-identity" options of "docker image verify".
+type SignatureOptions struct {
+	// Key is the path of a PEM-encoded public key, or certificate.
+	Key string
+	// CertificateChain is the path of a file of PEM-encoded certificates.
+	CertificateChain string
+	// CertificateIdentity is the identity that the certificate of the
+	// signature must have. It requires CertificateChain.
+	CertificateIdentity string
+}
+
+func newSignaturePolicy(opts SignatureOptions) (signaturePolicy, error) {
+	var policy signaturePolicy
+	if opts.CertificateIdentity != "" && opts.CertificateChain == "" {
+		return policy, errors.New("--certificate-identity requires --certificate-chain")
+	}
+	if opts.Key != "" {
+		key, err := loadPublicKey(opts.Key)
+		if err != nil {
+			return policy, err
+		}
+		policy.key = key
+	}
+	if opts.CertificateChain != "" {
+		roots, err := loadCertificates(opts.CertificateChain)
+		if err != nil {
+			return policy, err
+		}
+		policy.roots, policy.identity = roots, opts.CertificateIdentity
+	}
+	return policy, nil
+}
+
+// VerifySignature checks that the manifest, or image index, with the given
+// digest in the repository of ref has a cosign or Notation signature that is
+// verified with the key or certificates of opts.
+func VerifySignature(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest, opts SignatureOptions) error {
+	policy, err := newSignaturePolicy(opts)
+	if err != nil {
+		return err
+	}
+	signatures, err := imageSignatures(ctx, rc, ref, dgst, policy)
+	if err != nil {
+		return err
+	}
+	for _, s := range signatures {
+		if s.Verified {
+			return nil
+		}
+	}
+	if len(signatures) == 0 {
+		return errors.Errorf("no signature found for %s", reference.FamiliarString(ref))
+	}
+	return errors.Errorf("no valid signature found for %s", reference.FamiliarString(ref))
+}
+
+// loadPublicKey loads a PEM-encoded public key, or the key of a PEM-encoded
+// certificate, from a file.
+func loadPublicKey(filename string) (crypto.PublicKey, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("invalid key %s: no PEM-encoded key found", filename)
+	}
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %s", filename)
+		}
+		return cert.PublicKey, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid key %s", filename)
+	}
+	return key, nil
+}
+
+// loadCertificates loads the PEM-encoded certificates of a file.
+func loadCertificates(filename string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errors.Errorf("invalid certificate chain %s: no PEM-encoded certificate found", filename)
+	}
+	return pool, nil
+}
+
+// imageSignatures returns the cosign and Notation signatures of the manifest
+// with the given digest, verified against policy.
+func imageSignatures(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest, policy signaturePolicy) ([]signature, error) {
+	signatures, err := cosignSignatures(ctx, rc, ref, dgst, policy)
+	if err != nil {
+		return nil, err
+	}
+	named, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := rc.GetReferrers(ctx, named)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range referrers {
+		if r.ArtifactType != trust.ArtifactTypeNotation {
+			continue
+		}
+		sig := signature{Type: signatureNotation, Digest: r.Digest}
+		if err := verifyNotationSignature(ctx, rc, ref, r.Digest, dgst, policy, &sig); err != nil {
+			sig.Error = err.Error()
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+// cosignPayload is the payload that cosign signs.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest digest.Digest `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// cosignSignatures returns the cosign signatures of the manifest with the
+// given digest, verified against policy.
+func cosignSignatures(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest, policy signaturePolicy) ([]signature, error) {
+	tagged, err := reference.WithTag(reference.TrimNamed(ref), dgst.Algorithm().String()+"-"+dgst.Encoded()+".sig")
+	if err != nil {
+		return nil, err
+	}
+	_, payload, err := rc.GetRawManifest(ctx, tagged)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get cosign signatures")
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return nil, errors.Wrap(err, "invalid cosign signature manifest")
+	}
+
+	var signatures []signature
+	for _, l := range manifest.Layers {
+		if l.MediaType != mediaTypeCosignSimpleSigning {
+			continue
+		}
+		sig := signature{Type: signatureCosign, Digest: l.Digest}
+		if err := verifyCosignSignature(ctx, rc, ref, l, dgst, policy, &sig); err != nil {
+			sig.Error = err.Error()
+		}
+		signatures = append(signatures, sig)
+	}
+	return signatures, nil
+}
+
+func verifyCosignSignature(ctx context.Context, rc client.RegistryClient, ref reference.Named, layer ocispec.Descriptor, dgst digest.Digest, policy signaturePolicy, sig *signature) error {
+	payload, err := getBlob(ctx, rc, ref, layer.Digest)
+	if err != nil {
+		return err
+	}
+	var p cosignPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return errors.Wrap(err, "invalid signature payload")
+	}
+	if p.Critical.Image.DockerManifestDigest != dgst {
+		return errors.Errorf("signature is for %s, not for %s", p.Critical.Image.DockerManifestDigest, dgst)
+	}
+	signed, err := base64.StdEncoding.DecodeString(layer.Annotations[annotationCosignSignature])
+	if err != nil || len(signed) == 0 {
+		return errors.New("invalid signature")
+	}
+
+	if policy.key != nil {
+		if err := verifySignature(policy.key, crypto.SHA256, payload, signed); err == nil {
+			sig.Verified, sig.Signer = true, "key"
+			return nil
+		} else if layer.Annotations[annotationCosignCertificate] == "" {
+			return err
+		}
+	}
+	if layer.Annotations[annotationCosignCertificate] == "" {
+		return errors.New("signature was made with a key; use --key to verify it")
+	}
+	if policy.roots == nil {
+		return errors.New("signature was made with a certificate; use --certificate-chain to verify it")
+	}
+	cert, err := parsePEMCertificate(layer.Annotations[annotationCosignCertificate])
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(layer.Annotations[annotationCosignChain]))
+	// Certificates issued for keyless signing are only valid for a few
+	// minutes, so they're verified at the time they were issued.
+	signer, err := verifyCertificate(cert, intermediates, cert.NotBefore, policy)
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(cert.PublicKey, crypto.SHA256, payload, signed); err != nil {
+		return err
+	}
+	sig.Verified, sig.Signer = true, signer
+	return nil
+}
+
+func verifyNotationSignature(ctx context.Context, rc client.RegistryClient, ref reference.Named, sigDigest, dgst digest.Digest, policy signaturePolicy, sig *signature) error {
+	payload, err := getManifestByDigest(ctx, rc, ref, sigDigest)
+	if err != nil {
+		return err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return errors.Wrap(err, "invalid signature manifest")
+	}
+	if len(manifest.Layers) != 1 {
+		return errors.New("invalid signature manifest")
+	}
+	if manifest.Layers[0].MediaType != trust.MediaTypeJWSEnvelope {
+		return errors.Errorf("unsupported signature envelope %s", manifest.Layers[0].MediaType)
+	}
+	b, err := getBlob(ctx, rc, ref, manifest.Layers[0].Digest)
+	if err != nil {
+		return err
+	}
+
+	envelope, err := trust.ParseNotationEnvelope(b)
+	if err != nil {
+		return err
+	}
+	if envelope.Payload.TargetArtifact.Digest != dgst {
+		return errors.Errorf("signature is for %s, not for %s", envelope.Payload.TargetArtifact.Digest, dgst)
+	}
+	if policy.roots == nil {
+		return errors.New("signature was made with a certificate; use --certificate-chain to verify it")
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range envelope.Certificates[1:] {
+		intermediates.AddCert(c)
+	}
+	signer, err := verifyCertificate(envelope.Certificates[0], intermediates, time.Now(), policy)
+	if err != nil {
+		return err
+	}
+	if err := envelope.Verify(); err != nil {
+		return err
+	}
+	sig.Verified, sig.Signer = true, signer
+	return nil
+}
+
+func parsePEMCertificate(s string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("invalid certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate")
+	}
+	return cert, nil
+}
+
+// verifyCertificate verifies that cert chains to the roots of policy at the
+// given time, and that it has the identity of policy, if set. It returns the
+// identity of the certificate.
+func verifyCertificate(cert *x509.Certificate, intermediates *x509.CertPool, at time.Time, policy signaturePolicy) (string, error) {
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         policy.roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return "", errors.Wrap(err, "invalid certificate")
+	}
+	identities := certificateIdentities(cert)
+	if policy.identity == "" {
+		if len(identities) == 0 {
+			return "", nil
+		}
+		return identities[0], nil
+	}
+	for _, id := range identities {
+		if id == policy.identity {
+			return id, nil
+		}
+	}
+	return "", errors.Errorf("certificate identity %s doesn't match %s", strings.Join(identities, ", "), policy.identity)
+}
+
+// certificateIdentities returns the identities of a certificate: its email
+// addresses, URIs, and DNS names, and its subject.
+func certificateIdentities(cert *x509.Certificate) []string {
+	identities := append([]string{}, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		identities = append(identities, u.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	if subject := cert.Subject.String(); subject != "" {
+		identities = append(identities, subject)
+	}
+	return identities
+}
+
+// verifySignature verifies the signature of message with key.
+func verifySignature(key crypto.PublicKey, hash crypto.Hash, message, sig []byte) error {
+	h := hash.New()
+	h.Write(message)
+	hashed := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(k, hashed, sig) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(k, hash, hashed, sig) == nil || rsa.VerifyPSS(k, hash, hashed, sig, nil) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(k, message, sig) {
+			return nil
+		}
+	default:
+		return errors.Errorf("unsupported key type %T", key)
+	}
+	return errors.New("invalid signature")
+}
@@ -0,0 +1,368 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/opts"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultTagsTableFormat = "table {{.Tag}}\t{{.Digest}}\t{{.Platforms}}\t{{.CreatedSince}}"
+
+	tagHeader       = "TAG"
+	tagDigestHeader = "DIGEST"
+	platformsHeader = "PLATFORMS"
+
+	// defaultTagsLimit is the default number of tags that are shown, as the
+	// details of each tag are fetched from the registry.
+	defaultTagsLimit = 25
+	// maxConcurrentTagRequests is the number of tags whose details are
+	// fetched from the registry at the same time.
+	maxConcurrentTagRequests = 8
+)
+
+type tagsOptions struct {
+	repository string
+
+	filter  opts.FilterOpt
+	limit   int
+	last    string
+	quiet   bool
+	noTrunc bool
+	format  string
+}
+
+// remoteTag is a tag of a repository in a registry, with the details of the
+// manifest, or image index, that it refers to, where available.
+type remoteTag struct {
+	tag       string
+	digest    digest.Digest
+	platforms []string
+	created   *time.Time
+}
+
+// newTagsCommand creates a new `docker image tags` command
+func newTagsCommand(dockerCli command.Cli) *cobra.Command {
+	options := tagsOptions{filter: opts.NewFilterOpt()}
+
+	cmd := &cobra.Command{
+		Use:   "tags [OPTIONS] REPOSITORY",
+		Short: "List the tags of a repository in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.repository = args[0]
+			return runTags(cmd.Context(), dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
+	flags.IntVar(&options.limit, "limit", defaultTagsLimit, "Maximum number of tags to show, or 0 to show all tags")
+	flags.StringVar(&options.last, "last", "", "Only show the tags that the registry lists after this tag")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only show tags")
+	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runTags(ctx context.Context, dockerCli command.Cli, options tagsOptions) error {
+	if options.limit < 0 {
+		return errors.New("--limit must not be negative")
+	}
+	ref, err := reference.ParseNormalizedNamed(options.repository)
+	if err != nil {
+		return err
+	}
+	if !reference.IsNameOnly(ref) {
+		return errors.Errorf("invalid repository %s: must not have a tag or digest", options.repository)
+	}
+	if err := options.filter.Value().Validate(map[string]bool{"semver": true, "name": true}); err != nil {
+		return err
+	}
+	match, err := tagsFilter(options.filter.Value().Get("semver"), options.filter.Value().Get("name"))
+	if err != nil {
+		return err
+	}
+
+	rc := dockerCli.RegistryClient(false)
+	all, err := rc.GetTags(ctx, ref)
+	if err != nil {
+		return err
+	}
+	tags := pageTags(all, options.last, match)
+	if options.limit > 0 && len(tags) > options.limit {
+		dockerCli.Err().Infof("Showing %d of %d tags; use --last %s to show the next tags\n", options.limit, len(tags), tags[options.limit-1])
+		tags = tags[:options.limit]
+	}
+
+	if options.quiet {
+		for _, t := range tags {
+			_, _ = fmt.Fprintln(dockerCli.Out(), t)
+		}
+		return nil
+	}
+
+	remoteTags := make([]remoteTag, len(tags))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrentTagRequests)
+	for i, t := range tags {
+		i, t := i, t
+		eg.Go(func() error {
+			var err error
+			remoteTags[i], err = remoteTagDetails(egCtx, rc, ref, t)
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	format := options.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	tagsCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newTagsFormat(format),
+		Trunc:  !options.noTrunc,
+	}
+	return tagsWrite(tagsCtx, remoteTags)
+}
+
+// tagsFilter returns a function that returns whether a tag matches one of
+// the version constraints, if any, and one of the name patterns, if any.
+// Tags that aren't versions don't match version constraints.
+func tagsFilter(constraints, patterns []string) (func(string) bool, error) {
+	parsed := make([]versionConstraint, 0, len(constraints))
+	for _, c := range constraints {
+		constraint, err := parseVersionConstraint(c)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, constraint)
+	}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, errors.Errorf("invalid name filter %q: %v", p, err)
+		}
+	}
+
+	return func(tag string) bool {
+		if len(parsed) > 0 {
+			v, ok := parseVersion(tag)
+			if !ok {
+				return false
+			}
+			matched := false
+			for _, c := range parsed {
+				if c.matches(v) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+		if len(patterns) > 0 {
+			for _, p := range patterns {
+				if ok, _ := path.Match(p, tag); ok {
+					return true
+				}
+			}
+			return false
+		}
+		return true
+	}, nil
+}
+
+// pageTags returns the tags that match, and that the registry lists after
+// last. If last isn't one of the tags, the tags that sort after it are
+// returned, as the registry API does.
+func pageTags(tags []string, last string, match func(string) bool) []string {
+	start := 0
+	if last != "" {
+		start = len(tags)
+		for i, t := range tags {
+			if t == last {
+				start = i + 1
+				break
+			}
+			if t > last {
+				start = i
+				break
+			}
+		}
+	}
+	var matched []string
+	for _, t := range tags[start:] {
+		if match(t) {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
+
+// remoteTagDetails returns the digest, the platforms, and the creation date
+// of the manifest, or image index, that a tag refers to. The creation date of
+// an image index is the one of the image of its first platform. Tags that
+// were removed since they were listed are returned without details.
+func remoteTagDetails(ctx context.Context, rc client.RegistryClient, ref reference.Named, tag string) (remoteTag, error) {
+	t := remoteTag{tag: tag}
+	tagged, err := reference.WithTag(ref, tag)
+	if err != nil {
+		return t, err
+	}
+	desc, payload, err := rc.GetRawManifest(ctx, tagged)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return t, nil
+		}
+		return t, errors.Wrapf(err, "failed to get manifest of %s", reference.FamiliarString(tagged))
+	}
+	t.digest = desc.Digest
+
+	if isIndex(desc.MediaType) {
+		var index ocispec.Index
+		if err := json.Unmarshal(payload, &index); err != nil {
+			return t, errors.Wrapf(err, "invalid image index for %s", reference.FamiliarString(tagged))
+		}
+		var first digest.Digest
+		for _, m := range index.Manifests {
+			if isAttestationManifest(m) || m.Platform == nil {
+				continue
+			}
+			if first == "" {
+				first = m.Digest
+			}
+			t.platforms = append(t.platforms, platforms.Format(*m.Platform))
+		}
+		if first == "" {
+			return t, nil
+		}
+		payload, err = getManifestByDigest(ctx, rc, ref, first)
+		if err != nil {
+			return t, errors.Wrapf(err, "failed to get manifest of %s", reference.FamiliarString(tagged))
+		}
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return t, errors.Wrapf(err, "invalid manifest for %s", reference.FamiliarString(tagged))
+	}
+	if manifest.Config.MediaType != ocispec.MediaTypeImageConfig && manifest.Config.MediaType != mediaTypeDockerImageConfig {
+		// Such as artifacts, which have no platform nor creation date.
+		return t, nil
+	}
+	b, err := getBlob(ctx, rc, ref, manifest.Config.Digest)
+	if err != nil {
+		return t, errors.Wrapf(err, "failed to get config of %s", reference.FamiliarString(tagged))
+	}
+	var cfg ocispec.Image
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return t, errors.Wrapf(err, "invalid config for %s", reference.FamiliarString(tagged))
+	}
+	if len(t.platforms) == 0 && cfg.OS != "" {
+		t.platforms = []string{platforms.Format(cfg.Platform)}
+	}
+	t.created = cfg.Created
+	return t, nil
+}
+
+// newTagsFormat returns a format for use with a tags Context
+func newTagsFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultTagsTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// tagsWrite writes formatted tags using the Context
+func tagsWrite(ctx formatter.Context, tags []remoteTag) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, t := range tags {
+			if err := format(&tagContext{trunc: ctx.Trunc, t: t}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tagCtx := tagContext{}
+	tagCtx.Header = formatter.SubHeaderContext{
+		"Tag":          tagHeader,
+		"Digest":       tagDigestHeader,
+		"Platforms":    platformsHeader,
+		"CreatedSince": formatter.CreatedSinceHeader,
+		"CreatedAt":    formatter.CreatedAtHeader,
+	}
+	return ctx.Write(&tagCtx, render)
+}
+
+type tagContext struct {
+	formatter.HeaderContext
+	trunc bool
+	t     remoteTag
+}
+
+func (c *tagContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *tagContext) Tag() string {
+	return c.t.tag
+}
+
+func (c *tagContext) Digest() string {
+	if c.t.digest == "" {
+		return "N/A"
+	}
+	if c.trunc {
+		return stringid.TruncateID(c.t.digest.String())
+	}
+	return c.t.digest.String()
+}
+
+func (c *tagContext) Platforms() string {
+	if len(c.t.platforms) == 0 {
+		return "N/A"
+	}
+	p := append([]string(nil), c.t.platforms...)
+	sort.Strings(p)
+	return strings.Join(p, ", ")
+}
+
+func (c *tagContext) CreatedSince() string {
+	if c.t.created == nil || c.t.created.IsZero() {
+		return "N/A"
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(*c.t.created)) + " ago"
+}
+
+func (c *tagContext) CreatedAt() string {
+	if c.t.created == nil || c.t.created.IsZero() {
+		return "N/A"
+	}
+	return c.t.created.String()
+}
@@ -0,0 +1,174 @@
+package image
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// newTestTaggedRepository adds the tags "1.24.0", "1.25.0", "1.25.1-alpine",
+// "latest", and "stable" to r, and returns the digests of their manifests.
+func newTestTaggedRepository(t *testing.T, r *registry.Registry) map[string]string {
+	t.Helper()
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	addImage := func(tag string, platform ocispec.Platform) ocispec.Descriptor {
+		return r.AddManifest(t, testImageName, tag, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, ocispec.Image{Created: &created, Platform: platform}),
+		})
+	}
+
+	digests := map[string]string{}
+	for _, tag := range []string{"1.24.0", "stable"} {
+		digests[tag] = addImage(tag, ocispec.Platform{OS: "linux", Architecture: "amd64"}).Digest.String()
+	}
+	amd64 := addImage("", ocispec.Platform{OS: "linux", Architecture: "amd64"})
+	amd64.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+	arm64 := addImage("", ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"})
+	arm64.Platform = &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}
+	att := r.AddManifest(t, testImageName, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest})
+	att.Platform = &ocispec.Platform{OS: "unknown", Architecture: "unknown"}
+	att.Annotations = map[string]string{
+		annotationReferenceType:   attestationManifestType,
+		annotationReferenceDigest: amd64.Digest.String(),
+	}
+	for _, tag := range []string{"1.25.0", "latest"} {
+		digests[tag] = r.AddManifest(t, testImageName, tag, ocispec.MediaTypeImageIndex, ocispec.Index{
+			MediaType: ocispec.MediaTypeImageIndex,
+			Manifests: []ocispec.Descriptor{amd64, arm64, att},
+		}).Digest.String()
+	}
+	digests["1.25.1-alpine"] = addImage("1.25.1-alpine", ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}).Digest.String()
+	return digests
+}
+
+func TestNewTagsCommand(t *testing.T) {
+	r := registry.New()
+	digests := newTestTaggedRepository(t, r)
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(r)
+	cmd := newTagsCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--no-trunc", "--format", "{{.Tag}} {{.Digest}} {{.Platforms}} {{.CreatedAt}}", "example"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), strings.Join([]string{
+		"1.24.0 " + digests["1.24.0"] + " linux/amd64 2024-06-01 12:00:00 +0000 UTC",
+		"1.25.0 " + digests["1.25.0"] + " linux/amd64, linux/arm64/v8 2024-06-01 12:00:00 +0000 UTC",
+		"1.25.1-alpine " + digests["1.25.1-alpine"] + " linux/arm64/v8 2024-06-01 12:00:00 +0000 UTC",
+		"latest " + digests["latest"] + " linux/amd64, linux/arm64/v8 2024-06-01 12:00:00 +0000 UTC",
+		"stable " + digests["stable"] + " linux/amd64 2024-06-01 12:00:00 +0000 UTC",
+	}, "\n")+"\n"))
+}
+
+func TestNewTagsCommandQuiet(t *testing.T) {
+	r := registry.New()
+	newTestTaggedRepository(t, r)
+
+	testCases := []struct {
+		name        string
+		args        []string
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name:        "all",
+			expectedOut: "1.24.0\n1.25.0\n1.25.1-alpine\nlatest\nstable\n",
+		},
+		{
+			name:        "semver",
+			args:        []string{"--filter", "semver=1.25"},
+			expectedOut: "1.25.0\n1.25.1-alpine\n",
+		},
+		{
+			name:        "semver and name",
+			args:        []string{"--filter", "semver=>=1.24, <2", "--filter", "name=*-alpine"},
+			expectedOut: "1.25.1-alpine\n",
+		},
+		{
+			name:        "multiple semver",
+			args:        []string{"--filter", "semver=~1.24", "--filter", "semver=1.25.1-alpine"},
+			expectedOut: "1.24.0\n1.25.1-alpine\n",
+		},
+		{
+			name:        "limit",
+			args:        []string{"--limit", "2"},
+			expectedOut: "1.24.0\n1.25.0\n",
+			expectedErr: "Showing 2 of 5 tags; use --last 1.25.0 to show the next tags\n",
+		},
+		{
+			name:        "last",
+			args:        []string{"--limit", "2", "--last", "1.25.0"},
+			expectedOut: "1.25.1-alpine\nlatest\n",
+			expectedErr: "Showing 2 of 3 tags; use --last latest to show the next tags\n",
+		},
+		{
+			name:        "last that isn't a tag",
+			args:        []string{"--last", "2"},
+			expectedOut: "latest\nstable\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.SetRegistryClient(r)
+			cmd := newTagsCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(append(tc.args, "--quiet", "example"))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expectedOut))
+			assert.Check(t, is.Equal(cli.ErrBuffer().String(), tc.expectedErr))
+		})
+	}
+}
+
+func TestNewTagsCommandErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "tagged repository",
+			args:          []string{"example:latest"},
+			expectedError: "invalid repository example:latest: must not have a tag or digest",
+		},
+		{
+			name:          "negative limit",
+			args:          []string{"--limit", "-1", "example"},
+			expectedError: "--limit must not be negative",
+		},
+		{
+			name:          "invalid filter",
+			args:          []string{"--filter", "label=foo", "example"},
+			expectedError: "invalid filter 'label'",
+		},
+		{
+			name:          "invalid constraint",
+			args:          []string{"--filter", "semver=>=latest", "example"},
+			expectedError: `invalid version constraint ">=latest"`,
+		},
+		{
+			name:          "invalid pattern",
+			args:          []string{"--filter", "name=[", "example"},
+			expectedError: `invalid name filter "["`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cmd := newTagsCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
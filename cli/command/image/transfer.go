@@ -0,0 +1,161 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+)
+
+// defaultMaxConcurrent is the default number of images that are pulled or
+// pushed at the same time when pulling or pushing multiple images.
+const defaultMaxConcurrent = 3
+
+// transfer is the pull or push of one of the images of a multi-image pull or
+// push.
+type transfer struct {
+	// name is the name of the image, which prefixes its progress messages.
+	name string
+	// start starts the transfer, and returns the stream of its progress.
+	start func(ctx context.Context) (io.ReadCloser, error)
+	// retries is the number of times the transfer is retried if it fails
+	// because of a network error, after retryDelay, doubled after each retry.
+	retries    int
+	retryDelay time.Duration
+}
+
+// runTransfers runs transfers concurrently, at most maxConcurrent at a time,
+// and displays their progress on out, with the given type of progress output,
+// combined into a single display in which each message is prefixed by the
+// name of its image. It returns the error of
+// each transfer, which is nil if the transfer succeeded. auxCallback, if set,
+// is called with the auxiliary messages of the transfers, one at a time.
+func runTransfers(ctx context.Context, out *streams.Out, progress string, transfers []transfer, maxConcurrent int, auxCallback func(jsonmessage.JSONMessage)) []error {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	pr, pw := io.Pipe()
+	displayed := make(chan struct{})
+	go func() {
+		defer close(displayed)
+		// Transfer errors are reported as messages, so the display only stops
+		// at the end of the stream, or if writing to out fails; the rest of
+		// the stream is then discarded so that transfers don't block.
+		_ = command.DisplayProgress(pr, out, progress, nil)
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	var mu sync.Mutex
+	enc := json.NewEncoder(pw)
+	send := func(jm jsonmessage.JSONMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		if jm.Aux != nil {
+			if auxCallback != nil {
+				auxCallback(jm)
+			}
+			return
+		}
+		_ = enc.Encode(jm)
+	}
+
+	errs := make([]error, len(transfers))
+	sem := make(chan struct{}, maxConcurrent)
+	var wg sync.WaitGroup
+	for i, t := range transfers {
+		send(jsonmessage.JSONMessage{ID: t.name, Status: "Waiting"})
+		wg.Add(1)
+		go func(i int, t transfer) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			resume := newResumeTracker()
+			for retry := 1; ; retry++ {
+				errs[i] = relayTransfer(ctx, t, func(jm jsonmessage.JSONMessage) {
+					if notice := resume.update(&jm); notice != nil {
+						send(*notice)
+					}
+					send(jm)
+				})
+				if retry > t.retries || !isRetryable(ctx, errs[i]) {
+					break
+				}
+				delay := retryDelay(t.retryDelay, retry)
+				send(jsonmessage.JSONMessage{ID: t.name, Status: fmt.Sprintf("Failed: %v; retrying in %s (retry %d of %d)", errs[i], delay, retry, t.retries)})
+				if err := waitRetry(ctx, delay); err != nil {
+					errs[i] = err
+					break
+				}
+				resume.retry()
+			}
+			if errs[i] != nil {
+				send(jsonmessage.JSONMessage{ID: t.name, Status: "Error: " + errs[i].Error()})
+			}
+		}(i, t)
+	}
+	wg.Wait()
+	_ = pw.Close()
+	<-displayed
+	return errs
+}
+
+// relayTransfer runs transfer t, and sends its progress messages, prefixed by
+// the name of its image so that the messages of different images don't
+// update the same line of the display.
+func relayTransfer(ctx context.Context, t transfer, send func(jsonmessage.JSONMessage)) error {
+	body, err := t.start(ctx)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if jm.Error != nil {
+			return jm.Error
+		}
+		if jm.ErrorMessage != "" {
+			return errors.New(jm.ErrorMessage)
+		}
+		if jm.ID != "" {
+			jm.ID = t.name + " " + jm.ID
+		} else {
+			jm.ID = t.name
+		}
+		send(jm)
+	}
+}
+
+// transferErrors combines the errors of the transfers that failed, or returns
+// nil if all the transfers succeeded.
+func transferErrors(action string, transfers []transfer, errs []error) error {
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", transfers[i].name, err))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("failed to %s %d of %d images:", action, len(failed), len(transfers))
+	for _, f := range failed {
+		msg += "\n" + f
+	}
+	return errors.New(msg)
+}
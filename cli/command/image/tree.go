@@ -0,0 +1,255 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/go-units"
+)
+
+// layerNode is a node of the tree of layers of the images. The path from
+// the root of the tree to a node is the chain of layers of the images that
+// end at that node; images that share layers share the start of their path.
+type layerNode struct {
+	diffID    string
+	size      int64
+	createdBy string
+	children  []*layerNode
+	images    []image.Summary
+}
+
+func (n *layerNode) child(diffID string) *layerNode {
+	for _, c := range n.children {
+		if c.diffID == diffID {
+			return c
+		}
+	}
+	c := &layerNode{diffID: diffID, size: -1}
+	n.children = append(n.children, c)
+	return c
+}
+
+// shown returns whether the node is printed as an entry of the tree: nodes
+// are printed if they are the last layer of an image, or if their layers
+// are shared by images that diverge after it.
+func (n *layerNode) shown() bool {
+	return len(n.images) > 0 || len(n.children) > 1
+}
+
+// treeEntry is an image, or a group of shared layers, printed in the tree.
+type treeEntry struct {
+	image    *image.Summary
+	layers   []*layerNode
+	children []*treeEntry
+}
+
+// runImagesTree prints the images as a tree of the layers they share.
+func runImagesTree(ctx context.Context, dockerCLI command.Cli, options imagesOptions, images []image.Summary) error {
+	root := &layerNode{}
+	for _, img := range images {
+		inspect, _, err := dockerCLI.Client().ImageInspectWithRaw(ctx, img.ID)
+		if err != nil {
+			return err
+		}
+		history, err := dockerCLI.Client().ImageHistory(ctx, img.ID)
+		if err != nil {
+			return err
+		}
+		layers := layerHistory(history, len(inspect.RootFS.Layers))
+
+		node := root
+		for i, diffID := range inspect.RootFS.Layers {
+			node = node.child(diffID)
+			if layers != nil {
+				node.size, node.createdBy = layers[i].Size, layers[i].CreatedBy
+			}
+		}
+		node.images = append(node.images, img)
+	}
+
+	entries := treeEntries(root, nil)
+	w := tabwriter.NewWriter(dockerCLI.Out(), 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "IMAGE\tID\tSIZE\tSHARED SIZE\tUNIQUE SIZE")
+	for _, e := range entries {
+		writeTreeEntry(w, e, "", "", "", !options.noTrunc)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var diskUsage, totalSize int64
+	var layers int
+	var unknown bool
+	walkLayers(root, func(n *layerNode) {
+		layers++
+		if n.size < 0 {
+			unknown = true
+			return
+		}
+		diskUsage += n.size
+	})
+	for _, img := range images {
+		totalSize += img.Size
+	}
+	usage := units.HumanSize(float64(diskUsage))
+	if unknown {
+		usage = "at least " + usage
+	}
+	_, _ = fmt.Fprintf(dockerCLI.Out(), "\n%d images and %d layers use %s of disk space; without shared layers, they would use %s.\n",
+		len(images), layers, usage, units.HumanSize(float64(totalSize)))
+	return nil
+}
+
+// layerHistory returns the entries of the history of an image that created
+// its n layers, oldest first. It returns nil if the layers cannot be matched
+// with the history: the history doesn't mark the entries that didn't create
+// a layer, so entries are matched with layers if as many entries as there
+// are layers have a non-zero size.
+func layerHistory(history []image.HistoryResponseItem, n int) []image.HistoryResponseItem {
+	layers := make([]image.HistoryResponseItem, 0, n)
+	// The history is ordered from the most recent entry.
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Size > 0 {
+			layers = append(layers, history[i])
+		}
+	}
+	if len(layers) != n {
+		return nil
+	}
+	return layers
+}
+
+// treeEntries returns the entries of the tree for the descendants of node.
+// layers are the layers since the closest shown ancestor of node.
+func treeEntries(node *layerNode, layers []*layerNode) []*treeEntry {
+	var entries []*treeEntry
+	for _, c := range node.children {
+		l := append(layers[:len(layers):len(layers)], c)
+		if !c.shown() {
+			entries = append(entries, treeEntries(c, l)...)
+			continue
+		}
+		e := &treeEntry{layers: l, children: treeEntries(c, nil)}
+		if len(c.images) > 0 {
+			sort.Slice(c.images, func(i, j int) bool { return nameLess(imageName(c.images[i]), imageName(c.images[j])) })
+			e.image = &c.images[0]
+			// Images with the same layers as the first one are listed as
+			// its children, without layers of their own.
+			var same []*treeEntry
+			for i := range c.images[1:] {
+				same = append(same, &treeEntry{image: &c.images[i+1]})
+			}
+			e.children = append(same, e.children...)
+		}
+		entries = append(entries, e)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return nameLess(entryName(entries[i]), entryName(entries[j])) })
+	return entries
+}
+
+// writeTreeEntry writes e and its descendants to w. branch is drawn before
+// e, and indent before its descendants, after the prefix of its parent.
+func writeTreeEntry(w io.Writer, e *treeEntry, prefix, branch, indent string, trunc bool) {
+	if e.image != nil {
+		shared, unique := "N/A", "N/A"
+		if e.image.SharedSize >= 0 {
+			shared = units.HumanSize(float64(e.image.SharedSize))
+			unique = units.HumanSize(float64(e.image.Size - e.image.SharedSize))
+		}
+		_, _ = fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\t%s\n", prefix+branch, entryName(e), shortID(e.image.ID, trunc), units.HumanSize(float64(e.image.Size)), shared, unique)
+	} else {
+		_, _ = fmt.Fprintf(w, "%s%s\t\t%s\t\t\n", prefix+branch, entryName(e), humanLayersSize(e.layers))
+	}
+
+	prefix += indent
+	for i, l := range e.layers {
+		layerBranch := "├─ "
+		if i == len(e.layers)-1 && len(e.children) == 0 {
+			layerBranch = "└─ "
+		}
+		_, _ = fmt.Fprintf(w, "%s%s\t%s\t%s\t\t\n", prefix+layerBranch, layerName(l, trunc), shortID(l.diffID, trunc), humanLayersSize([]*layerNode{l}))
+	}
+	for i, c := range e.children {
+		if i == len(e.children)-1 {
+			writeTreeEntry(w, c, prefix, "└─ ", "   ", trunc)
+		} else {
+			writeTreeEntry(w, c, prefix, "├─ ", "│  ", trunc)
+		}
+	}
+}
+
+func entryName(e *treeEntry) string {
+	if e.image == nil {
+		return "<shared layers>"
+	}
+	return imageName(*e.image)
+}
+
+// nameLess sorts entries by name, with the untagged images and the shared
+// layers ("<none>" and "<shared layers>") after the tagged images.
+func nameLess(a, b string) bool {
+	if untaggedA, untaggedB := strings.HasPrefix(a, "<"), strings.HasPrefix(b, "<"); untaggedA != untaggedB {
+		return untaggedB
+	}
+	return a < b
+}
+
+func imageName(img image.Summary) string {
+	var names []string
+	for _, t := range img.RepoTags {
+		if t != "<none>:<none>" {
+			names = append(names, t)
+		}
+	}
+	if len(names) == 0 {
+		return "<none>"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func layerName(l *layerNode, trunc bool) string {
+	if l.createdBy == "" {
+		return "layer"
+	}
+	createdBy := strings.ReplaceAll(l.createdBy, "\t", " ")
+	if trunc {
+		return "layer: " + formatter.Ellipsis(createdBy, 45)
+	}
+	return "layer: " + createdBy
+}
+
+func shortID(id string, trunc bool) string {
+	if trunc {
+		return stringid.TruncateID(id)
+	}
+	return id
+}
+
+// humanLayersSize returns the total size of layers, or "N/A" if the size of
+// a layer is not known.
+func humanLayersSize(layers []*layerNode) string {
+	var total int64
+	for _, l := range layers {
+		if l.size < 0 {
+			return "N/A"
+		}
+		total += l.size
+	}
+	return units.HumanSize(float64(total))
+}
+
+func walkLayers(n *layerNode, fn func(*layerNode)) {
+	for _, c := range n.children {
+		fn(c)
+		walkLayers(c, fn)
+	}
+}
@@ -0,0 +1,92 @@
+package image
+
+import (
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestNewImagesCommandTree(t *testing.T) {
+	images := map[string]struct {
+		summary image.Summary
+		layers  []string
+		history []image.HistoryResponseItem
+	}{
+		"sha256:1111111111111111111111111111111111111111111111111111111111111111": {
+			summary: image.Summary{RepoTags: []string{"ubuntu:22.04"}, Size: 77_000_000, SharedSize: 77_000_000},
+			layers:  []string{"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+			history: []image.HistoryResponseItem{
+				{CreatedBy: `/bin/sh -c #(nop)  CMD ["bash"]`},
+				{CreatedBy: "/bin/sh -c #(nop) ADD file:0123456789abcdef in / ", Size: 77_000_000},
+			},
+		},
+		"sha256:2222222222222222222222222222222222222222222222222222222222222222": {
+			summary: image.Summary{RepoTags: []string{"app:1.0"}, Size: 90_000_000, SharedSize: 82_000_000},
+			layers: []string{
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				"sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+				"sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc",
+			},
+			history: []image.HistoryResponseItem{
+				{CreatedBy: "COPY app /usr/local/bin/app # buildkit", Size: 8_000_000},
+				{CreatedBy: "RUN /bin/sh -c apt-get update && apt-get install -y ca-certificates # buildkit", Size: 5_000_000},
+				{CreatedBy: `/bin/sh -c #(nop)  CMD ["bash"]`},
+				{CreatedBy: "/bin/sh -c #(nop) ADD file:0123456789abcdef in / ", Size: 77_000_000},
+			},
+		},
+		"sha256:3333333333333333333333333333333333333333333333333333333333333333": {
+			summary: image.Summary{RepoTags: []string{"app:latest", "app:2.0"}, Size: 91_000_000, SharedSize: 82_000_000},
+			layers: []string{
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				"sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+				"sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd",
+			},
+			history: []image.HistoryResponseItem{
+				{CreatedBy: "COPY app /usr/local/bin/app # buildkit", Size: 9_000_000},
+				{CreatedBy: "RUN /bin/sh -c apt-get update && apt-get install -y ca-certificates # buildkit", Size: 5_000_000},
+				{CreatedBy: `/bin/sh -c #(nop)  CMD ["bash"]`},
+				{CreatedBy: "/bin/sh -c #(nop) ADD file:0123456789abcdef in / ", Size: 77_000_000},
+			},
+		},
+		"sha256:4444444444444444444444444444444444444444444444444444444444444444": {
+			summary: image.Summary{RepoTags: []string{"<none>:<none>"}, Size: 91_000_000, SharedSize: 91_000_000},
+			layers: []string{
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				"sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+				"sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd",
+			},
+		},
+		"sha256:5555555555555555555555555555555555555555555555555555555555555555": {
+			summary: image.Summary{RepoTags: []string{"alpine:latest"}, Size: 7_000_000, SharedSize: -1},
+			layers:  []string{"sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"},
+		},
+	}
+
+	cli := test.NewFakeCli(&fakeClient{
+		imageListFunc: func(options image.ListOptions) ([]image.Summary, error) {
+			assert.Check(t, options.SharedSize)
+			var summaries []image.Summary
+			for id, img := range images {
+				img.summary.ID = id
+				summaries = append(summaries, img.summary)
+			}
+			return summaries, nil
+		},
+		imageInspectFunc: func(id string) (types.ImageInspect, []byte, error) {
+			return types.ImageInspect{ID: id, RootFS: types.RootFS{Type: "layers", Layers: images[id].layers}}, nil, nil
+		},
+		imageHistoryFunc: func(id string) ([]image.HistoryResponseItem, error) {
+			return images[id].history, nil
+		},
+	})
+	cmd := NewImagesCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--tree"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "list-command-tree.golden")
+}
@@ -192,10 +192,13 @@ func trustedPull(ctx context.Context, cli command.Cli, imgRefAndAuth trust.Image
 			return err
 		}
 		if err := imagePullPrivileged(ctx, cli, updatedImgRefAndAuth, PullOptions{
-			all:      false,
-			platform: opts.platform,
-			quiet:    opts.quiet,
-			remote:   opts.remote,
+			all:        false,
+			platform:   opts.platform,
+			quiet:      opts.quiet,
+			progress:   opts.progress,
+			remote:     opts.remote,
+			retries:    opts.retries,
+			retryDelay: opts.retryDelay,
 		}); err != nil {
 			return err
 		}
@@ -268,22 +271,46 @@ func imagePullPrivileged(ctx context.Context, cli command.Cli, imgRefAndAuth tru
 		return err
 	}
 	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(cli, imgRefAndAuth.RepoInfo().Index, "pull")
-	responseBody, err := cli.Client().ImagePull(ctx, reference.FamiliarString(imgRefAndAuth.Reference()), image.PullOptions{
+	options := image.PullOptions{
 		RegistryAuth:  encodedAuth,
 		PrivilegeFunc: requestPrivilege,
 		All:           opts.all,
 		Platform:      opts.platform,
-	})
-	if err != nil {
-		return err
 	}
-	defer responseBody.Close()
 
 	out := cli.Out()
 	if opts.quiet {
 		out = streams.NewOut(io.Discard)
 	}
-	return jsonmessage.DisplayJSONMessagesToStream(responseBody, out, nil)
+	resume := newResumeTracker()
+	for retry := 1; ; retry++ {
+		err = imagePullOnce(ctx, cli, reference.FamiliarString(imgRefAndAuth.Reference()), options, out, opts.progress, resume)
+		if retry > opts.retries || !isRetryable(ctx, err) {
+			return err
+		}
+		delay := retryDelay(opts.retryDelay, retry)
+		if !opts.quiet {
+			_, _ = fmt.Fprintf(cli.Err(), "Pull failed: %v; retrying in %s (retry %d of %d)\n", err, delay, retry, opts.retries)
+		}
+		if err := waitRetry(ctx, delay); err != nil {
+			return err
+		}
+		resume.retry()
+	}
+}
+
+// imagePullOnce makes one attempt to pull an image, and displays its
+// progress on out, with the given type of progress output.
+func imagePullOnce(ctx context.Context, cli command.Cli, ref string, options image.PullOptions, out *streams.Out, progressType string, resume *resumeTracker) error {
+	responseBody, err := cli.Client().ImagePull(ctx, ref, options)
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+
+	progress := resume.track(responseBody)
+	defer progress.Close()
+	return command.DisplayProgress(progress, out, progressType, nil)
 }
 
 // TrustedReference returns the canonical trusted reference for an image reference
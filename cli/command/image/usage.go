@@ -0,0 +1,109 @@
+package image
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/sirupsen/logrus"
+)
+
+// usageFileName is the name of the file, next to the CLI configuration file,
+// in which the CLI records when images were last used, for "docker image gc".
+const usageFileName = "image-usage.json"
+
+// usageFile returns the path of the file in which the last use of images is
+// recorded, or an empty string if the CLI has no configuration file.
+func usageFile(dockerCLI command.Cli) string {
+	configFile := dockerCLI.ConfigFile().GetFilename()
+	if configFile == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(configFile), usageFileName)
+}
+
+// RecordUse records that the image with reference ref, as passed by the
+// user, was used now, for example to create a container. Errors are only
+// logged, as the usage history is only used to prioritize the removal of
+// images with "docker image gc".
+func RecordUse(dockerCLI command.Cli, ref string) {
+	fileName := usageFile(dockerCLI)
+	if fileName == "" {
+		return
+	}
+	usage, err := loadUsage(fileName)
+	if err != nil {
+		logrus.Debugf("failed to load image usage history: %v", err)
+		usage = map[string]time.Time{}
+	}
+	usage[usageKey(ref)] = time.Now().UTC()
+	data, err := json.Marshal(usage)
+	if err == nil {
+		err = ioutils.AtomicWriteFile(fileName, data, 0o600)
+	}
+	if err != nil {
+		logrus.Debugf("failed to record image usage: %v", err)
+	}
+}
+
+// loadUsage loads the time at which images were last used, by the key of
+// their reference or ID.
+func loadUsage(fileName string) (map[string]time.Time, error) {
+	usage := map[string]time.Time{}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// idPattern matches full and truncated image IDs.
+var idPattern = regexp.MustCompile(`^(sha256:)?[a-f0-9]{12,64}$`)
+
+// usageKey returns the key by which the use of the image with reference ref
+// is recorded: the ID, with the "sha256:" prefix, if ref looks like a (full
+// or truncated) image ID, and the normalized reference otherwise.
+func usageKey(ref string) string {
+	if idPattern.MatchString(ref) {
+		return "sha256:" + strings.TrimPrefix(ref, "sha256:")
+	}
+	if named, err := reference.ParseNormalizedNamed(ref); err == nil {
+		return reference.TagNameOnly(named).String()
+	}
+	return ref
+}
+
+// lastUse returns the last time that img was used by any of its references
+// or its ID, or the zero time if its use was never recorded.
+func lastUse(usage map[string]time.Time, img image.Summary) time.Time {
+	var last time.Time
+	for key, t := range usage {
+		if !t.After(last) {
+			continue
+		}
+		if strings.HasPrefix(key, "sha256:") && strings.HasPrefix(img.ID, key) {
+			last = t
+			continue
+		}
+		for _, tag := range img.RepoTags {
+			if usageKey(tag) == key {
+				last = t
+				break
+			}
+		}
+	}
+	return last
+}
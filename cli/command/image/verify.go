@@ -0,0 +1,232 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/formatter/tabwriter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/templates"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type verifyOptions struct {
+	image string
+
+	key                 string
+	certificateIdentity string
+	certificateChain    string
+	requireProvenance   bool
+	requireSBOM         bool
+	format              string
+}
+
+// imageVerification is the result of "docker image verify".
+type imageVerification struct {
+	Image        string
+	Digest       string
+	Verified     bool
+	Signatures   []signature
+	Attestations []attestation
+	// Failures are the reasons why the image doesn't satisfy the policy.
+	Failures []string
+}
+
+// newVerifyCommand creates a new cobra.Command for `docker image verify`
+func newVerifyCommand(dockerCli command.Cli) *cobra.Command {
+	var opts verifyOptions
+
+	cmd := &cobra.Command{
+		Use:   "verify [OPTIONS] IMAGE",
+		Short: "Verify the signatures and attestations of an image in a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.image = args[0]
+			return runVerify(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.ImageNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.key, "key", "", "Require a signature made with the private key of this PEM-encoded public key")
+	flags.StringVar(&opts.certificateChain, "certificate-chain", "", "Require a signature made with a certificate issued by one of the PEM-encoded certificates of this file")
+	flags.StringVar(&opts.certificateIdentity, "certificate-identity", "", "Require the certificate of the signature to have this identity (email address, URI, or subject)")
+	flags.BoolVar(&opts.requireProvenance, "require-provenance", false, "Require a provenance attestation for each platform of the image")
+	flags.BoolVar(&opts.requireSBOM, "require-sbom", false, "Require an SBOM attestation for each platform of the image")
+	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	return cmd
+}
+
+func runVerify(ctx context.Context, dockerCli command.Cli, opts verifyOptions) error {
+	if opts.certificateIdentity != "" && opts.certificateChain == "" {
+		return errors.New("--certificate-identity requires --certificate-chain")
+	}
+	if opts.key == "" && opts.certificateChain == "" && !opts.requireProvenance && !opts.requireSBOM {
+		return errors.New("nothing to verify: use --key or --certificate-chain to verify signatures, or --require-provenance or --require-sbom to verify attestations")
+	}
+
+	policy, err := newSignaturePolicy(SignatureOptions{
+		Key:                 opts.key,
+		CertificateChain:    opts.certificateChain,
+		CertificateIdentity: opts.certificateIdentity,
+	})
+	if err != nil {
+		return err
+	}
+
+	ref, err := reference.ParseNormalizedNamed(opts.image)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	rc := dockerCli.RegistryClient(false)
+	desc, images, err := imageAttestations(ctx, rc, ref)
+	if err != nil {
+		return err
+	}
+	v := imageVerification{
+		Image:        reference.FamiliarString(ref),
+		Digest:       desc.Digest.String(),
+		Signatures:   []signature{},
+		Attestations: []attestation{},
+		Failures:     []string{},
+	}
+	if policy.key != nil || policy.roots != nil {
+		signatures, err := imageSignatures(ctx, rc, ref, desc.Digest, policy)
+		if err != nil {
+			return err
+		}
+		v.Signatures = append(v.Signatures, signatures...)
+	}
+	for _, img := range images {
+		v.Attestations = append(v.Attestations, img.Attestations...)
+	}
+	v.Failures = verificationFailures(opts, v, images)
+	v.Verified = len(v.Failures) == 0
+
+	if opts.format != "" {
+		if err := formatVerification(dockerCli.Out(), opts.format, v); err != nil {
+			return err
+		}
+	} else {
+		prettyPrintVerification(dockerCli.Out(), v)
+	}
+	if !v.Verified {
+		return cli.StatusError{StatusCode: 1, Status: "verification of " + v.Image + " failed"}
+	}
+	return nil
+}
+
+// verificationFailures returns the reasons why the image doesn't satisfy the
+// policy of opts.
+func verificationFailures(opts verifyOptions, v imageVerification, images []attestedImage) []string {
+	failures := []string{}
+	if opts.key != "" || opts.certificateChain != "" {
+		var verified bool
+		for _, s := range v.Signatures {
+			verified = verified || s.Verified
+		}
+		switch {
+		case verified:
+		case len(v.Signatures) == 0:
+			failures = append(failures, "no signature found")
+		default:
+			failures = append(failures, "no valid signature found")
+		}
+	}
+	for _, img := range images {
+		name := img.Platform
+		if name == "" {
+			name = img.Digest.String()
+		}
+		if opts.requireProvenance && !hasAttestation(img, attestationProvenance) {
+			failures = append(failures, "no provenance attestation for "+name)
+		}
+		if opts.requireSBOM && !hasAttestation(img, attestationSBOM) {
+			failures = append(failures, "no SBOM attestation for "+name)
+		}
+	}
+	return failures
+}
+
+func hasAttestation(img attestedImage, kind string) bool {
+	for _, a := range img.Attestations {
+		if a.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func formatVerification(out io.Writer, format string, v imageVerification) error {
+	switch format {
+	case formatter.JSONFormatKey:
+		format = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		format = formatter.YAMLFormat
+	}
+	format, err := formatter.ExpandJSONPath(format)
+	if err != nil {
+		return cli.StatusError{StatusCode: 64, Status: err.Error()}
+	}
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return cli.StatusError{
+			StatusCode: 64,
+			Status:     "template parsing error: " + err.Error(),
+		}
+	}
+	if err := tmpl.Execute(out, v); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(out)
+	return nil
+}
+
+func prettyPrintVerification(out io.Writer, v imageVerification) {
+	_, _ = fmt.Fprintln(out, "Image: ", v.Image)
+	_, _ = fmt.Fprintln(out, "Digest:", v.Digest)
+
+	if len(v.Signatures) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, "Signatures:")
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, " TYPE\tSTATUS\tSIGNER")
+		for _, s := range v.Signatures {
+			status, signer := "verified", s.Signer
+			if !s.Verified {
+				status, signer = "invalid", s.Error
+			}
+			_, _ = fmt.Fprintf(w, " %s\t%s\t%s\n", s.Type, status, signer)
+		}
+		_ = w.Flush()
+	}
+
+	if len(v.Attestations) > 0 {
+		_, _ = fmt.Fprintln(out)
+		_, _ = fmt.Fprintln(out, "Attestations:")
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, " PLATFORM\tTYPE\tPREDICATE TYPE")
+		for _, a := range v.Attestations {
+			_, _ = fmt.Fprintf(w, " %s\t%s\t%s\n", valueOrNone(a.Platform), valueOrNone(a.Kind), a.PredicateType)
+		}
+		_ = w.Flush()
+	}
+
+	_, _ = fmt.Fprintln(out)
+	if v.Verified {
+		_, _ = fmt.Fprintln(out, "Verified")
+		return
+	}
+	_, _ = fmt.Fprintln(out, "Not verified:")
+	_, _ = fmt.Fprintln(out, " -", strings.Join(v.Failures, "\n - "))
+}
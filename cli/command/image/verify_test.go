@@ -0,0 +1,285 @@
+package image
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/trust"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+const testImageName = "docker.io/library/example"
+
+// newTestImage adds a multi-platform image with a provenance attestation, as
+// pushed by BuildKit, to r. It returns the digest of its index.
+func newTestImage(t *testing.T, r *registry.Registry) digest.Digest {
+	t.Helper()
+	config := r.AddBlob(t, ocispec.MediaTypeImageConfig, ocispec.Image{Platform: ocispec.Platform{OS: "linux", Architecture: "amd64"}})
+	amd64 := r.AddManifest(t, testImageName, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+	})
+	amd64.Platform = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+
+	statement := r.AddBlob(t, "application/vnd.in-toto+json", []byte(`{"_type": "https://in-toto.io/Statement/v0.1"}`))
+	statement.Annotations = map[string]string{annotationPredicateType: "https://slsa.dev/provenance/v0.2"}
+	att := r.AddManifest(t, testImageName, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers:    []ocispec.Descriptor{statement},
+	})
+	att.Platform = &ocispec.Platform{OS: "unknown", Architecture: "unknown"}
+	att.Annotations = map[string]string{
+		annotationReferenceType:   attestationManifestType,
+		annotationReferenceDigest: amd64.Digest.String(),
+	}
+
+	index := r.AddManifest(t, testImageName, "latest", ocispec.MediaTypeImageIndex, ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{amd64, att},
+	})
+	return index.Digest
+}
+
+// cosignSignature returns the layer of a cosign signature of the manifest
+// with the given digest, signed with key, and with the certificate cert if set.
+func cosignSignature(t *testing.T, r *registry.Registry, dgst digest.Digest, key *ecdsa.PrivateKey, cert []byte) ocispec.Descriptor {
+	t.Helper()
+	payload := []byte(`{"critical":{"identity":{"docker-reference":"` + testImageName + `"},"image":{"docker-manifest-digest":"` + dgst.String() + `"},"type":"cosign container image signature"},"optional":null}`)
+	hashed := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, hashed[:])
+	assert.NilError(t, err)
+
+	layer := r.AddBlob(t, mediaTypeCosignSimpleSigning, payload)
+	layer.Annotations = map[string]string{annotationCosignSignature: base64.StdEncoding.EncodeToString(sig)}
+	if cert != nil {
+		layer.Annotations[annotationCosignCertificate] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}))
+	}
+	return layer
+}
+
+// addCosignSignatures adds the cosign signature manifest of the manifest with
+// the given digest, with the given signatures.
+func addCosignSignatures(t *testing.T, r *registry.Registry, dgst digest.Digest, signatures ...ocispec.Descriptor) {
+	t.Helper()
+	r.AddManifest(t, testImageName, "sha256-"+dgst.Encoded()+".sig", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers:    signatures,
+	})
+}
+
+// addNotationSignature adds a Notation signature, in a JWS envelope, of the
+// manifest with the given digest to r.
+func addNotationSignature(t *testing.T, r *registry.Registry, dgst digest.Digest, key *ecdsa.PrivateKey, cert []byte) {
+	t.Helper()
+	c, err := x509.ParseCertificate(cert)
+	assert.NilError(t, err)
+	signer, err := trust.NewNotationSigner(key, []*x509.Certificate{c})
+	assert.NilError(t, err)
+	envelope, err := signer.Envelope(ocispec.Descriptor{MediaType: ocispec.MediaTypeImageIndex, Digest: dgst})
+	assert.NilError(t, err)
+	r.AddManifest(t, testImageName, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: trust.ArtifactTypeNotation,
+		Config:       r.AddBlob(t, "application/vnd.oci.empty.v1+json", []byte("{}")),
+		Layers:       []ocispec.Descriptor{r.AddBlob(t, trust.MediaTypeJWSEnvelope, envelope)},
+		Subject:      &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageIndex, Digest: dgst},
+	})
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	return key
+}
+
+// newTestCertificates returns a CA certificate, and a code signing
+// certificate for email issued by the CA for key.
+func newTestCertificates(t *testing.T, key *ecdsa.PrivateKey, email string) (ca, cert []byte) {
+	t.Helper()
+	caKey := newTestKey(t)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	ca, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	assert.NilError(t, err)
+	caCert, err := x509.ParseCertificate(ca)
+	assert.NilError(t, err)
+
+	cert, err = x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:   big.NewInt(2),
+		NotBefore:      time.Now().Add(-time.Minute),
+		NotAfter:       time.Now().Add(10 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses: []string{email},
+	}, caCert, key.Public(), caKey)
+	assert.NilError(t, err)
+	return ca, cert
+}
+
+func pemPublicKey(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+	b, err := x509.MarshalPKIXPublicKey(key)
+	assert.NilError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func TestNewVerifyCommand(t *testing.T) {
+	key, otherKey, signerKey := newTestKey(t), newTestKey(t), newTestKey(t)
+	ca, cert := newTestCertificates(t, signerKey, "signer@example.com")
+
+	r := registry.New()
+	dgst := newTestImage(t, r)
+	addCosignSignatures(t, r, dgst, cosignSignature(t, r, dgst, key, nil), cosignSignature(t, r, dgst, signerKey, cert))
+
+	dir := fs.NewDir(t, "verify",
+		fs.WithFile("key.pem", pemPublicKey(t, key.Public())),
+		fs.WithFile("other.pem", pemPublicKey(t, otherKey.Public())),
+		fs.WithFile("ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca}))),
+	)
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedOut   string
+		expectedError string
+	}{
+		{
+			name:        "key",
+			args:        []string{"--key", dir.Join("key.pem"), "--format", "{{.Verified}}"},
+			expectedOut: "true\n",
+		},
+		{
+			name:          "other key",
+			args:          []string{"--key", dir.Join("other.pem"), "--format", "{{.Failures}}"},
+			expectedOut:   "[no valid signature found]\n",
+			expectedError: "verification of example:latest failed",
+		},
+		{
+			name:        "certificate identity",
+			args:        []string{"--certificate-chain", dir.Join("ca.pem"), "--certificate-identity", "signer@example.com", "--format", "{{range .Signatures}}{{.Verified}} {{.Signer}};{{end}}"},
+			expectedOut: "false ;true signer@example.com;\n",
+		},
+		{
+			name:          "other certificate identity",
+			args:          []string{"--certificate-chain", dir.Join("ca.pem"), "--certificate-identity", "other@example.com", "--format", "{{range .Signatures}}{{.Error}};{{end}}"},
+			expectedOut:   "signature was made with a key; use --key to verify it;certificate identity signer@example.com doesn't match other@example.com;\n",
+			expectedError: "verification of example:latest failed",
+		},
+		{
+			name:        "provenance",
+			args:        []string{"--require-provenance", "--format", "{{range .Attestations}}{{.Platform}} {{.Kind}} {{.PredicateType}}{{end}}"},
+			expectedOut: "linux/amd64 provenance https://slsa.dev/provenance/v0.2\n",
+		},
+		{
+			name:          "sbom",
+			args:          []string{"--require-sbom", "--format", "{{.Failures}}"},
+			expectedOut:   "[no SBOM attestation for linux/amd64]\n",
+			expectedError: "verification of example:latest failed",
+		},
+		{
+			name:          "nothing to verify",
+			expectedError: "nothing to verify: use --key or --certificate-chain to verify signatures, or --require-provenance or --require-sbom to verify attestations",
+		},
+		{
+			name:          "identity without certificate chain",
+			args:          []string{"--certificate-identity", "signer@example.com"},
+			expectedError: "--certificate-identity requires --certificate-chain",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.SetRegistryClient(r)
+			cmd := newVerifyCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(append(tc.args, "example"))
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedError))
+			} else {
+				assert.Check(t, err)
+			}
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expectedOut))
+		})
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, otherKey := newTestKey(t), newTestKey(t)
+	r := registry.New()
+	dgst := newTestImage(t, r)
+	addCosignSignatures(t, r, dgst, cosignSignature(t, r, dgst, key, nil))
+	dir := fs.NewDir(t, "verify",
+		fs.WithFile("key.pem", pemPublicKey(t, key.Public())),
+		fs.WithFile("other.pem", pemPublicKey(t, otherKey.Public())),
+	)
+	ref, err := reference.ParseNormalizedNamed(testImageName + ":latest")
+	assert.NilError(t, err)
+
+	err = VerifySignature(context.Background(), r, ref, dgst, SignatureOptions{Key: dir.Join("key.pem")})
+	assert.Check(t, err)
+	err = VerifySignature(context.Background(), r, ref, dgst, SignatureOptions{Key: dir.Join("other.pem")})
+	assert.Check(t, is.Error(err, "no valid signature found for example:latest"))
+	err = VerifySignature(context.Background(), r, ref, digest.FromString("unsigned"), SignatureOptions{Key: dir.Join("key.pem")})
+	assert.Check(t, is.Error(err, "no signature found for example:latest"))
+}
+
+func TestNewVerifyCommandNotation(t *testing.T) {
+	signerKey := newTestKey(t)
+	ca, cert := newTestCertificates(t, signerKey, "signer@example.com")
+
+	r := registry.New()
+	dgst := newTestImage(t, r)
+	addNotationSignature(t, r, dgst, signerKey, cert)
+	dir := fs.NewDir(t, "verify", fs.WithFile("ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca}))))
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetRegistryClient(r)
+	cmd := newVerifyCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--certificate-chain", dir.Join("ca.pem"), "example"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Image:  example:latest
+Digest: `+dgst.String()+`
+
+Signatures:
+ TYPE       STATUS     SIGNER
+ notation   verified   signer@example.com
+
+Attestations:
+ PLATFORM      TYPE         PREDICATE TYPE
+ linux/amd64   provenance   https://slsa.dev/provenance/v0.2
+
+Verified
+`))
+}
@@ -0,0 +1,316 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Formats supported by Diff.
+const (
+	DiffFormatUnified   = "unified"
+	DiffFormatJSONPatch = "json-patch"
+)
+
+// diffContext is the number of unchanged lines shown around changes in the
+// unified format.
+const diffContext = 3
+
+// Diff fetches two objects by reference using getRef, and writes the
+// difference between their JSON representations to out, either as a
+// unified diff or as a JSON patch (RFC 6902) that transforms the first
+// object into the second. Nothing is written for the unified format if
+// the objects are identical.
+func Diff(out io.Writer, ref1, ref2 string, format string, getRef GetRefFunc) error {
+	if format != DiffFormatUnified && format != DiffFormatJSONPatch {
+		return errors.Errorf("invalid diff format %q: must be %q or %q", format, DiffFormatUnified, DiffFormatJSONPatch)
+	}
+	raw1, err := getRaw(ref1, getRef)
+	if err != nil {
+		return err
+	}
+	raw2, err := getRaw(ref2, getRef)
+	if err != nil {
+		return err
+	}
+
+	if format == DiffFormatJSONPatch {
+		ops, err := jsonPatch(raw1, raw2)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(ops, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+
+	var indented1, indented2 bytes.Buffer
+	if err := json.Indent(&indented1, raw1, "", "    "); err != nil {
+		return err
+	}
+	if err := json.Indent(&indented2, raw2, "", "    "); err != nil {
+		return err
+	}
+	_, err = io.WriteString(out, unifiedDiff(ref1, ref2, indented1.String(), indented2.String()))
+	return err
+}
+
+func getRaw(ref string, getRef GetRefFunc) ([]byte, error) {
+	element, raw, err := getRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return json.Marshal(element)
+	}
+	return raw, nil
+}
+
+// patchOp is a JSON patch operation, as defined in RFC 6902.
+type patchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+func jsonPatch(raw1, raw2 []byte) ([]patchOp, error) {
+	v1, err := decodeJSON(raw1)
+	if err != nil {
+		return nil, err
+	}
+	v2, err := decodeJSON(raw2)
+	if err != nil {
+		return nil, err
+	}
+	ops := []patchOp{}
+	diffValues(&ops, "", v1, v2)
+	return ops, nil
+}
+
+func decodeJSON(raw []byte) (any, error) {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.Errorf("unable to read inspect data: %v", err)
+	}
+	return v, nil
+}
+
+func diffValues(ops *[]patchOp, path string, v1, v2 any) {
+	switch a := v1.(type) {
+	case map[string]any:
+		if b, ok := v2.(map[string]any); ok {
+			diffObjects(ops, path, a, b)
+			return
+		}
+	case []any:
+		if b, ok := v2.([]any); ok {
+			diffArrays(ops, path, a, b)
+			return
+		}
+	}
+	if !reflect.DeepEqual(v1, v2) {
+		*ops = append(*ops, patchOp{Op: "replace", Path: path, Value: patchValue(v2)})
+	}
+}
+
+func diffObjects(ops *[]patchOp, path string, a, b map[string]any) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := path + "/" + escapePointer(k)
+		v1, ok1 := a[k]
+		v2, ok2 := b[k]
+		switch {
+		case !ok2:
+			*ops = append(*ops, patchOp{Op: "remove", Path: p})
+		case !ok1:
+			*ops = append(*ops, patchOp{Op: "add", Path: p, Value: patchValue(v2)})
+		default:
+			diffValues(ops, p, v1, v2)
+		}
+	}
+}
+
+// diffArrays compares arrays element by element. Elements are removed from
+// the end of the array first, so that the operations can be applied in
+// sequence.
+func diffArrays(ops *[]patchOp, path string, a, b []any) {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		diffValues(ops, fmt.Sprintf("%s/%d", path, i), a[i], b[i])
+	}
+	for i := len(a) - 1; i >= n; i-- {
+		*ops = append(*ops, patchOp{Op: "remove", Path: fmt.Sprintf("%s/%d", path, i)})
+	}
+	for i := n; i < len(b); i++ {
+		*ops = append(*ops, patchOp{Op: "add", Path: fmt.Sprintf("%s/%d", path, i), Value: patchValue(b[i])})
+	}
+}
+
+// patchValue returns the value for an "add" or "replace" operation, making
+// sure that null values are not omitted.
+func patchValue(v any) any {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+	return v
+}
+
+// escapePointer escapes a key for use in a JSON pointer (RFC 6901).
+func escapePointer(k string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+}
+
+type diffLine struct {
+	kind byte // ' ', '-', or '+'
+	text string
+}
+
+// unifiedDiff returns the line-based difference between a and b in the
+// unified format.
+func unifiedDiff(name1, name2, a, b string) string {
+	lines := diffLines(strings.Split(a, "\n"), strings.Split(b, "\n"))
+	if !slices.ContainsFunc(lines, func(l diffLine) bool { return l.kind != ' ' }) {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", name1, name2)
+	for start := 0; start < len(lines); {
+		// find the next change, and the range of lines of the hunk around it.
+		first := start
+		for first < len(lines) && lines[first].kind == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+		hunkStart := max(first-diffContext, start)
+		hunkEnd, unchanged := first, 0
+		for i := first; i < len(lines) && unchanged <= 2*diffContext; i++ {
+			if lines[i].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+				hunkEnd = i + 1
+			}
+		}
+		hunkEnd = min(hunkEnd+diffContext, len(lines))
+
+		l1, l2 := 1, 1
+		for _, l := range lines[:hunkStart] {
+			if l.kind != '+' {
+				l1++
+			}
+			if l.kind != '-' {
+				l2++
+			}
+		}
+		var n1, n2 int
+		for _, l := range lines[hunkStart:hunkEnd] {
+			if l.kind != '+' {
+				n1++
+			}
+			if l.kind != '-' {
+				n2++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(l1, n1), hunkRange(l2, n2))
+		for _, l := range lines[hunkStart:hunkEnd] {
+			sb.WriteByte(l.kind)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return sb.String()
+}
+
+func hunkRange(start, n int) string {
+	if n == 0 {
+		// an empty range starts at the line before the hunk.
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
+
+// diffLines computes the shortest edit script between a and b, based on
+// their longest common subsequence.
+func diffLines(a, b []string) []diffLine {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		result = append(result, diffLine{' ', l})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// lcs[i][j] is the length of the longest common subsequence of ma[i:]
+	// and mb[j:].
+	lcs := make([][]int32, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			result = append(result, diffLine{' ', ma[i]})
+			i++
+			j++
+		case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+			result = append(result, diffLine{'-', ma[i]})
+			i++
+		default:
+			result = append(result, diffLine{'+', mb[j]})
+			j++
+		}
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		result = append(result, diffLine{' ', l})
+	}
+	return result
+}
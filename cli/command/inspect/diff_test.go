@@ -0,0 +1,154 @@
+package inspect
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func diffRefs(objects map[string]string) GetRefFunc {
+	return func(ref string) (any, []byte, error) {
+		raw, ok := objects[ref]
+		if !ok {
+			return nil, nil, errors.Errorf("Error: No such object: %s", ref)
+		}
+		return nil, []byte(raw), nil
+	}
+}
+
+var testObjects = map[string]string{
+	"web-1": `{"Name":"/web-1","Config":{"Env":["PATH=/usr/bin","DEBUG=0"],"Labels":{"a/b":"c","tier":"web"},"User":""},"Mounts":[{"Source":"/data"},{"Source":"/logs"}],"RestartCount":0,"Extra":null}`,
+	"web-2": `{"Name":"/web-2","Config":{"Env":["PATH=/usr/bin","DEBUG=1","TZ=UTC"],"Labels":{"tier":"web"},"User":null},"Mounts":[{"Source":"/data"}],"RestartCount":3,"Added":false}`,
+}
+
+func TestDiffUnified(t *testing.T) {
+	var out bytes.Buffer
+	err := Diff(&out, "web-1", "web-2", DiffFormatUnified, diffRefs(testObjects))
+	assert.NilError(t, err)
+	const expected = `--- web-1
++++ web-2
+@@ -1,24 +1,21 @@
+ {
+-    "Name": "/web-1",
++    "Name": "/web-2",
+     "Config": {
+         "Env": [
+             "PATH=/usr/bin",
+-            "DEBUG=0"
++            "DEBUG=1",
++            "TZ=UTC"
+         ],
+         "Labels": {
+-            "a/b": "c",
+             "tier": "web"
+         },
+-        "User": ""
++        "User": null
+     },
+     "Mounts": [
+         {
+             "Source": "/data"
+-        },
+-        {
+-            "Source": "/logs"
+         }
+     ],
+-    "RestartCount": 0,
+-    "Extra": null
++    "RestartCount": 3,
++    "Added": false
+ }
+`
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestDiffUnifiedHunks(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12"
+	b := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11"
+	const expected = `--- a
++++ b
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -9,4 +10,3 @@
+ 9
+ 10
+ 11
+-12
+`
+	assert.Check(t, is.Equal(unifiedDiff("a", "b", a, b), expected))
+}
+
+func TestDiffUnifiedIdentical(t *testing.T) {
+	var out bytes.Buffer
+	err := Diff(&out, "web-1", "web-1", DiffFormatUnified, diffRefs(testObjects))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(out.String(), ""))
+}
+
+func TestDiffJSONPatch(t *testing.T) {
+	var out bytes.Buffer
+	err := Diff(&out, "web-1", "web-2", DiffFormatJSONPatch, diffRefs(testObjects))
+	assert.NilError(t, err)
+	const expected = `[
+    {
+        "op": "add",
+        "path": "/Added",
+        "value": false
+    },
+    {
+        "op": "replace",
+        "path": "/Config/Env/1",
+        "value": "DEBUG=1"
+    },
+    {
+        "op": "add",
+        "path": "/Config/Env/2",
+        "value": "TZ=UTC"
+    },
+    {
+        "op": "remove",
+        "path": "/Config/Labels/a~1b"
+    },
+    {
+        "op": "replace",
+        "path": "/Config/User",
+        "value": null
+    },
+    {
+        "op": "remove",
+        "path": "/Extra"
+    },
+    {
+        "op": "remove",
+        "path": "/Mounts/1"
+    },
+    {
+        "op": "replace",
+        "path": "/Name",
+        "value": "/web-2"
+    },
+    {
+        "op": "replace",
+        "path": "/RestartCount",
+        "value": 3
+    }
+]
+`
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
+func TestDiffErrors(t *testing.T) {
+	var out bytes.Buffer
+	err := Diff(&out, "web-1", "web-2", "side-by-side", diffRefs(testObjects))
+	assert.Check(t, is.Error(err, `invalid diff format "side-by-side": must be "unified" or "json-patch"`))
+
+	err = Diff(&out, "web-1", "nosuchobject", DiffFormatUnified, diffRefs(testObjects))
+	assert.Check(t, is.Error(err, "Error: No such object: nosuchobject"))
+	assert.Check(t, is.Equal(out.String(), ""))
+}
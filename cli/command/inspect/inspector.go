@@ -11,6 +11,7 @@ import (
 	"text/template"
 
 	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command/formatter"
 	"github.com/docker/cli/templates"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -51,6 +52,14 @@ func NewTemplateInspectorFromString(out io.Writer, tmplStr string) (Inspector, e
 		return NewJSONInspector(out), nil
 	}
 
+	if tmplStr == formatter.YAMLFormatKey {
+		return NewYAMLInspector(out), nil
+	}
+
+	tmplStr, err := formatter.ExpandJSONPath(tmplStr)
+	if err != nil {
+		return nil, err
+	}
 	tmpl, err := templates.Parse(tmplStr)
 	if err != nil {
 		return nil, errors.Errorf("template parsing error: %s", err)
@@ -62,13 +71,23 @@ func NewTemplateInspectorFromString(out io.Writer, tmplStr string) (Inspector, e
 // reference
 type GetRefFunc func(ref string) (any, []byte, error)
 
+// pager is implemented by output streams that can pipe their output to a
+// pager, such as the output stream of the CLI.
+type pager interface {
+	StartPager() (stop func())
+}
+
 // Inspect fetches objects by reference using GetRefFunc and writes the json
-// representation to the output writer.
+// representation to the output writer. The output is piped to a pager if out
+// supports it.
 func Inspect(out io.Writer, references []string, tmplStr string, getRef GetRefFunc) error {
 	inspector, err := NewTemplateInspectorFromString(out, tmplStr)
 	if err != nil {
 		return cli.StatusError{StatusCode: 64, Status: err.Error()}
 	}
+	if p, ok := out.(pager); ok {
+		defer p.StartPager()()
+	}
 
 	var inspectErrs []string
 	for _, ref := range references {
@@ -169,6 +188,24 @@ func NewJSONInspector(outputStream io.Writer) Inspector {
 	}
 }
 
+var yamlTemplate = template.Must(templates.Parse("{{yaml .}}"))
+
+// NewYAMLInspector generates a new inspector with a YAML representation
+// of elements.
+func NewYAMLInspector(outputStream io.Writer) Inspector {
+	return &elementsInspector{
+		outputStream: outputStream,
+		raw: func(dst *bytes.Buffer, src []byte) error {
+			return yamlTemplate.Execute(dst, json.RawMessage(src))
+		},
+		el: func(v any) ([]byte, error) {
+			var buf bytes.Buffer
+			err := yamlTemplate.Execute(&buf, v)
+			return buf.Bytes(), err
+		},
+	}
+}
+
 type elementsInspector struct {
 	outputStream io.Writer
 	elements     []any
@@ -222,6 +222,23 @@ func TestIndentedInspectorRawElements(t *testing.T) {
 	}
 }
 
+func TestYAMLInspectorRawElements(t *testing.T) {
+	b := new(bytes.Buffer)
+	i := NewYAMLInspector(b)
+	assert.NilError(t, i.Inspect(testElement{"0.0.0.0"}, []byte(`{"Dns": "0.0.0.0", "Node": "0", "Size": 53317}`)))
+	assert.NilError(t, i.Inspect(testElement{"1.1.1.1"}, []byte(`{"Dns": "1.1.1.1", "Node": "1", "Size": 0.5}`)))
+	assert.NilError(t, i.Flush())
+
+	expected := `- Dns: 0.0.0.0
+  Node: "0"
+  Size: 53317
+- Dns: 1.1.1.1
+  Node: "1"
+  Size: 0.5
+`
+	assert.Check(t, is.Equal(b.String(), expected))
+}
+
 // moby/moby#32235
 // This test verifies that even if `tryRawInspectFallback` is called the fields containing
 // numerical values are displayed correctly.
@@ -278,6 +295,12 @@ func TestNewTemplateInspectorFromString(t *testing.T) {
 			name:     "json specific value outputs json",
 			template: "json",
 			expected: `[{"Name":"test"}]
+`,
+		},
+		{
+			name:     "yaml specific value outputs yaml",
+			template: "yaml",
+			expected: `- Name: test
 `,
 		},
 		{
@@ -2,12 +2,14 @@ package manifest
 
 import (
 	"context"
+	"io"
 
 	"github.com/distribution/reference"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/cli/registry/client"
 	"github.com/docker/distribution"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type fakeRegistryClient struct {
@@ -15,6 +17,11 @@ type fakeRegistryClient struct {
 	getManifestListFunc func(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
 	mountBlobFunc       func(ctx context.Context, source reference.Canonical, target reference.Named) error
 	putManifestFunc     func(ctx context.Context, source reference.Named, mf distribution.Manifest) (digest.Digest, error)
+	getRawManifestFunc  func(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error)
+	getBlobFunc         func(ctx context.Context, ref reference.Canonical) ([]byte, error)
+	getReferrersFunc    func(ctx context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error)
+	getTagsFunc         func(ctx context.Context, ref reference.Named) ([]string, error)
+	putBlobFunc         func(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error
 }
 
 func (c *fakeRegistryClient) GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
@@ -38,6 +45,13 @@ func (c *fakeRegistryClient) MountBlob(ctx context.Context, source reference.Can
 	return nil
 }
 
+func (c *fakeRegistryClient) PutBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+	if c.putBlobFunc != nil {
+		return c.putBlobFunc(ctx, ref, desc, content)
+	}
+	return nil
+}
+
 func (c *fakeRegistryClient) PutManifest(ctx context.Context, ref reference.Named, mf distribution.Manifest) (digest.Digest, error) {
 	if c.putManifestFunc != nil {
 		return c.putManifestFunc(ctx, ref, mf)
@@ -45,4 +59,32 @@ func (c *fakeRegistryClient) PutManifest(ctx context.Context, ref reference.Name
 	return digest.Digest(""), nil
 }
 
+func (c *fakeRegistryClient) GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	if c.getRawManifestFunc != nil {
+		return c.getRawManifestFunc(ctx, ref)
+	}
+	return ocispec.Descriptor{}, nil, nil
+}
+
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error) {
+	if c.getBlobFunc != nil {
+		return c.getBlobFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) GetReferrers(ctx context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error) {
+	if c.getReferrersFunc != nil {
+		return c.getReferrersFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func (c *fakeRegistryClient) GetTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	if c.getTagsFunc != nil {
+		return c.getTagsFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
 var _ client.RegistryClient = &fakeRegistryClient{}
@@ -15,6 +15,7 @@ import (
 type createOpts struct {
 	amend    bool
 	insecure bool
+	push     bool
 }
 
 func newCreateListCommand(dockerCli command.Cli) *cobra.Command {
@@ -32,6 +33,7 @@ func newCreateListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.insecure, "insecure", false, "Allow communication with an insecure registry")
 	flags.BoolVarP(&opts.amend, "amend", "a", false, "Amend an existing manifest list")
+	flags.BoolVar(&opts.push, "push", false, "Push the manifest list once it's created")
 	return cmd
 }
 
@@ -62,6 +64,19 @@ func createManifestList(ctx context.Context, dockerCli command.Cli, args []strin
 	// for the constituent images:
 	manifests := args[1:]
 	for _, manifestRef := range manifests {
+		if layoutPath, tag, ok := parseLayoutReference(manifestRef); ok {
+			layoutManifests, err := layoutManifests(layoutPath, tag, targetRef)
+			if err != nil {
+				return err
+			}
+			for _, manifest := range layoutManifests {
+				if err := manifestStore.Save(targetRef, manifest.Ref, manifest); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
 		namedRef, err := normalizeReference(manifestRef)
 		if err != nil {
 			// TODO: wrap error?
@@ -77,5 +92,8 @@ func createManifestList(ctx context.Context, dockerCli command.Cli, args []strin
 		}
 	}
 	fmt.Fprintf(dockerCli.Out(), "Created manifest list %s\n", targetRef.String())
+	if opts.push {
+		return runPush(ctx, dockerCli, pushOpts{target: newRef, insecure: opts.insecure})
+	}
 	return nil
 }
@@ -2,13 +2,21 @@ package manifest
 
 import (
 	"context"
+	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/manifest/store"
 	manifesttypes "github.com/docker/cli/cli/manifest/types"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/distribution"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
@@ -112,3 +120,110 @@ func TestManifestCreateNoManifest(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err, "No such image: example.com/alpine:3.0")
 }
+
+// writeOCILayout writes an OCI image layout with a single image, tagged
+// "v1", to dir, and returns the digests of its blobs.
+func writeOCILayout(t *testing.T, dir string) []digest.Digest {
+	t.Helper()
+	var digests []digest.Digest
+	writeBlob := func(mediaType string, b []byte) ocispec.Descriptor {
+		dgst := digest.FromBytes(b)
+		assert.NilError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0o755))
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", dgst.Encoded()), b, 0o644))
+		digests = append(digests, dgst)
+		return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(b))}
+	}
+	marshal := func(v any) []byte {
+		b, err := json.Marshal(v)
+		assert.NilError(t, err)
+		return b
+	}
+
+	config := writeBlob(ocispec.MediaTypeImageConfig, marshal(ocispec.Image{
+		Platform: ocispec.Platform{OS: "linux", Architecture: "arm64"},
+		RootFS:   ocispec.RootFS{Type: "layers"},
+	}))
+	layer := writeBlob(ocispec.MediaTypeImageLayerGzip, []byte("layer"))
+	manifest := writeBlob(ocispec.MediaTypeImageManifest, marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispec.Descriptor{layer},
+	}))
+	digests = digests[:2]
+	manifest.Annotations = map[string]string{ocispec.AnnotationRefName: "v1"}
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "index.json"), marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{manifest},
+	}), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644))
+	return digests
+}
+
+func TestManifestCreateOCILayout(t *testing.T) {
+	layoutDir := t.TempDir()
+	blobs := writeOCILayout(t, layoutDir)
+
+	var pushedBlobs []digest.Digest
+	var putManifests []string
+	cli := test.NewFakeCli(nil)
+	cli.SetManifestStore(store.NewStore(t.TempDir()))
+	cli.SetRegistryClient(&fakeRegistryClient{
+		putBlobFunc: func(_ context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+			assert.Check(t, is.Equal(ref.String(), "example.com/list:v1"))
+			b, err := io.ReadAll(content)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(desc.Digest, digest.FromBytes(b)))
+			pushedBlobs = append(pushedBlobs, desc.Digest)
+			return nil
+		},
+		putManifestFunc: func(_ context.Context, ref reference.Named, _ distribution.Manifest) (digest.Digest, error) {
+			putManifests = append(putManifests, ref.String())
+			return "sha256:1111111111111111111111111111111111111111111111111111111111111111", nil
+		},
+	})
+
+	cmd := newCreateListCommand(cli)
+	cmd.SetArgs([]string{"--push", "example.com/list:v1", "oci-layout://" + layoutDir + ":v1"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(pushedBlobs, blobs))
+	assert.Check(t, is.Len(putManifests, 2))
+	for _, m := range putManifests {
+		assert.Check(t, strings.HasPrefix(m, "example.com/list"), m)
+	}
+
+	list, err := cli.ManifestStore().GetList(ref(t, "list:v1"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(list, 1))
+	assert.Check(t, is.Equal(list[0].Descriptor.Platform.Architecture, "arm64"))
+}
+
+func TestManifestCreateOCILayoutErrors(t *testing.T) {
+	layoutDir := t.TempDir()
+	writeOCILayout(t, layoutDir)
+
+	testCases := []struct {
+		manifest      string
+		expectedError string
+	}{
+		{
+			manifest:      "oci-layout://" + layoutDir + ":v2",
+			expectedError: "has no image v2",
+		},
+		{
+			manifest:      "oci-layout://" + filepath.Join(layoutDir, "missing"),
+			expectedError: "failed to open OCI image layout",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(nil)
+		cli.SetManifestStore(store.NewStore(t.TempDir()))
+		cmd := newCreateListCommand(cli)
+		cmd.SetArgs([]string{"example.com/list:v1", tc.manifest})
+		cmd.SetOut(io.Discard)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
@@ -0,0 +1,251 @@
+package manifest
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/distribution/manifest/ocischema"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ociLayoutPrefix is the prefix of the manifests that are created from
+	// an OCI image layout, such as "oci-layout://./image.tar:v1".
+	ociLayoutPrefix = "oci-layout://"
+
+	// maxLayoutMetadataSize is the maximum size of the indexes, manifests,
+	// and configs that are read from an OCI image layout.
+	maxLayoutMetadataSize = 4 << 20
+
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	annotationImageName         = "io.containerd.image.name"
+	annotationReferenceType     = "vnd.docker.reference.type"
+	attestationManifestType     = "attestation-manifest"
+)
+
+// ociLayout is an OCI image layout, in a directory, or in a tar archive such
+// as the ones that "docker save" writes.
+type ociLayout struct {
+	path  string
+	isDir bool
+}
+
+// parseLayoutReference parses a reference to the manifests of an OCI image
+// layout, in the form "oci-layout://PATH[:TAG]". The tag selects the manifest
+// of the index of the layout with that reference name.
+func parseLayoutReference(ref string) (layoutPath, tag string, ok bool) {
+	layoutPath, ok = strings.CutPrefix(ref, ociLayoutPrefix)
+	if !ok {
+		return "", "", false
+	}
+	// A colon at index 1 is the drive letter of a Windows path, such as
+	// "C:\image".
+	if i := strings.LastIndex(layoutPath, ":"); i > 1 && !strings.ContainsAny(layoutPath[i+1:], `/\`) {
+		layoutPath, tag = layoutPath[:i], layoutPath[i+1:]
+	}
+	return layoutPath, tag, true
+}
+
+func openLayout(layoutPath string) (ociLayout, error) {
+	fi, err := os.Stat(layoutPath)
+	if err != nil {
+		return ociLayout{}, errors.Wrap(err, "failed to open OCI image layout")
+	}
+	abs, err := filepath.Abs(layoutPath)
+	if err != nil {
+		return ociLayout{}, err
+	}
+	return ociLayout{path: abs, isDir: fi.IsDir()}, nil
+}
+
+// open opens a file of the layout, by its path in the layout.
+func (l ociLayout) open(name string) (io.ReadCloser, error) {
+	if l.isDir {
+		f, err := os.Open(filepath.Join(l.path, filepath.FromSlash(name)))
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("OCI image layout %s has no %s", l.path, name)
+		}
+		return f, err
+	}
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			_ = f.Close()
+			return nil, errors.Errorf("OCI image layout %s has no %s", l.path, name)
+		}
+		if err != nil {
+			_ = f.Close()
+			return nil, errors.Wrapf(err, "failed to read OCI image layout %s", l.path)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Clean(hdr.Name) == name {
+			return struct {
+				io.Reader
+				io.Closer
+			}{tr, f}, nil
+		}
+	}
+}
+
+// openBlob opens a blob of the layout.
+func (l ociLayout) openBlob(dgst digest.Digest) (io.ReadCloser, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+	return l.open(path.Join("blobs", dgst.Algorithm().String(), dgst.Encoded()))
+}
+
+// readJSON reads a JSON file of the layout, after checking that it matches
+// dgst, if set.
+func (l ociLayout) readJSON(name string, dgst digest.Digest, v any) ([]byte, error) {
+	r, err := l.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(io.LimitReader(r, maxLayoutMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxLayoutMetadataSize {
+		return nil, errors.Errorf("%s of OCI image layout %s is too large", name, l.path)
+	}
+	if dgst != "" && dgst.Algorithm().FromBytes(b) != dgst {
+		return nil, errors.Errorf("content of %s of OCI image layout %s doesn't match its digest", name, l.path)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s in OCI image layout %s", name, l.path)
+	}
+	return b, nil
+}
+
+func (l ociLayout) readBlobJSON(desc ocispec.Descriptor, v any) ([]byte, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, err
+	}
+	return l.readJSON(path.Join("blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()), desc.Digest, v)
+}
+
+// layoutManifests returns the image manifests of an OCI image layout, with
+// targetRef's repository as their reference, as they're pushed to it. If
+// set, tag selects the manifest of the index of the layout with that
+// reference name; if not, the index must have a single manifest. The images
+// of image indexes are returned, without their attestations.
+func layoutManifests(layoutPath, tag string, targetRef reference.Named) ([]types.ImageManifest, error) {
+	l, err := openLayout(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	var index ocispec.Index
+	if _, err := l.readJSON("index.json", "", &index); err != nil {
+		return nil, err
+	}
+
+	var desc ocispec.Descriptor
+	switch {
+	case tag != "":
+		var found bool
+		for _, m := range index.Manifests {
+			name := m.Annotations[ocispec.AnnotationRefName]
+			if name == tag || strings.HasSuffix(name, ":"+tag) || strings.HasSuffix(m.Annotations[annotationImageName], ":"+tag) {
+				desc, found = m, true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("OCI image layout %s has no image %s", layoutPath, tag)
+		}
+	case len(index.Manifests) == 1:
+		desc = index.Manifests[0]
+	default:
+		return nil, errors.Errorf("OCI image layout %s has %d images: select one with %s%s:TAG", layoutPath, len(index.Manifests), ociLayoutPrefix, layoutPath)
+	}
+
+	if desc.MediaType != ocispec.MediaTypeImageIndex && desc.MediaType != mediaTypeDockerManifestList {
+		m, err := l.imageManifest(desc, targetRef)
+		if err != nil {
+			return nil, err
+		}
+		return []types.ImageManifest{m}, nil
+	}
+
+	var images ocispec.Index
+	if _, err := l.readBlobJSON(desc, &images); err != nil {
+		return nil, err
+	}
+	var manifests []types.ImageManifest
+	for _, m := range images.Manifests {
+		if m.Annotations[annotationReferenceType] == attestationManifestType {
+			continue
+		}
+		im, err := l.imageManifest(m, targetRef)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, im)
+	}
+	return manifests, nil
+}
+
+// imageManifest returns the image manifest of the layout with the given
+// descriptor, with its platform from the descriptor or, if it has none,
+// from the config of the image.
+func (l ociLayout) imageManifest(desc ocispec.Descriptor, targetRef reference.Named) (types.ImageManifest, error) {
+	var m ocispec.Manifest
+	raw, err := l.readBlobJSON(desc, &m)
+	if err != nil {
+		return types.ImageManifest{}, err
+	}
+	ref, err := reference.WithDigest(reference.TrimNamed(targetRef), desc.Digest)
+	if err != nil {
+		return types.ImageManifest{}, err
+	}
+
+	platform := desc.Platform
+	if platform == nil {
+		var cfg ocispec.Image
+		if _, err := l.readBlobJSON(m.Config, &cfg); err != nil {
+			return types.ImageManifest{}, err
+		}
+		platform = &cfg.Platform
+	}
+	manifestDesc := ocispec.Descriptor{MediaType: m.MediaType, Digest: desc.Digest, Size: int64(len(raw)), Platform: platform}
+	if manifestDesc.MediaType == "" {
+		manifestDesc.MediaType = desc.MediaType
+	}
+
+	var imageManifest types.ImageManifest
+	switch manifestDesc.MediaType {
+	case ocispec.MediaTypeImageManifest:
+		var mfst ocischema.DeserializedManifest
+		if err := mfst.UnmarshalJSON(raw); err != nil {
+			return types.ImageManifest{}, err
+		}
+		imageManifest = types.NewOCIImageManifest(ref, manifestDesc, &mfst)
+	case schema2.MediaTypeManifest:
+		var mfst schema2.DeserializedManifest
+		if err := mfst.UnmarshalJSON(raw); err != nil {
+			return types.ImageManifest{}, err
+		}
+		imageManifest = types.NewImageManifest(ref, manifestDesc, &mfst)
+	default:
+		return types.ImageManifest{}, errors.Errorf("manifest %s of OCI image layout %s has unsupported media type %q", desc.Digest, l.path, manifestDesc.MediaType)
+	}
+	imageManifest.OCILayout = l.path
+	return imageManifest, nil
+}
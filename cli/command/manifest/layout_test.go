@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseLayoutReference(t *testing.T) {
+	testCases := []struct {
+		ref          string
+		expectedPath string
+		expectedTag  string
+		expectedOK   bool
+	}{
+		{ref: "example.com/alpine:3.0"},
+		{ref: "oci-layout://./image", expectedPath: "./image", expectedOK: true},
+		{ref: "oci-layout://./image.tar:v1", expectedPath: "./image.tar", expectedTag: "v1", expectedOK: true},
+		{ref: "oci-layout://C:\\image", expectedPath: "C:\\image", expectedOK: true},
+		{ref: "oci-layout://./host:5000/image", expectedPath: "./host:5000/image", expectedOK: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.ref, func(t *testing.T) {
+			layoutPath, tag, ok := parseLayoutReference(tc.ref)
+			assert.Check(t, is.Equal(layoutPath, tc.expectedPath))
+			assert.Check(t, is.Equal(tag, tc.expectedTag))
+			assert.Check(t, is.Equal(ok, tc.expectedOK))
+		})
+	}
+}
+
+func TestLayoutManifestsFromArchive(t *testing.T) {
+	layoutDir := t.TempDir()
+	writeOCILayout(t, layoutDir)
+
+	archive := filepath.Join(t.TempDir(), "image.tar")
+	f, err := os.Create(archive)
+	assert.NilError(t, err)
+	tw := tar.NewWriter(f)
+	err = filepath.Walk(layoutDir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(layoutDir, p)
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: filepath.ToSlash(name), Mode: 0o644, Size: int64(len(b)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		_, err = tw.Write(b)
+		return err
+	})
+	assert.NilError(t, err)
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, f.Close())
+
+	manifests, err := layoutManifests(archive, "", ref(t, "list:v1"))
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(manifests, 1))
+	assert.Check(t, is.Equal(manifests[0].OCILayout, archive))
+	assert.Check(t, is.Equal(manifests[0].Descriptor.Platform.Architecture, "arm64"))
+	assert.Check(t, is.Len(manifests[0].References(), 2))
+}
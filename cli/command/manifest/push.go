@@ -16,6 +16,7 @@ import (
 	"github.com/docker/distribution/manifest/ocischema"
 	"github.com/docker/distribution/manifest/schema2"
 	"github.com/docker/docker/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -36,11 +37,18 @@ type manifestBlob struct {
 	os        string
 }
 
+// layoutBlob is a blob of an OCI image layout to upload.
+type layoutBlob struct {
+	layout string
+	desc   ocispec.Descriptor
+}
+
 type pushRequest struct {
 	targetRef     reference.Named
 	list          *manifestlist.DeserializedManifestList
 	mountRequests []mountRequest
 	manifestBlobs []manifestBlob
+	layoutBlobs   []layoutBlob
 	insecure      bool
 }
 
@@ -110,6 +118,23 @@ func buildPushRequest(manifests []types.ImageManifest, targetRef reference.Named
 	}
 
 	for _, imageManifest := range manifests {
+		if imageManifest.OCILayout != "" {
+			// The blobs and the manifest of images of OCI image layouts are
+			// uploaded to the target repository.
+			for _, desc := range imageManifest.References() {
+				req.layoutBlobs = append(req.layoutBlobs, layoutBlob{
+					layout: imageManifest.OCILayout,
+					desc:   ocispec.Descriptor{MediaType: desc.MediaType, Digest: desc.Digest, Size: desc.Size},
+				})
+			}
+			manifestPush, err := buildPutManifestRequest(imageManifest, targetRef)
+			if err != nil {
+				return req, err
+			}
+			req.mountRequests = append(req.mountRequests, manifestPush)
+			continue
+		}
+
 		manifestRepoName, err := registryclient.RepoNameForReference(imageManifest.Ref)
 		if err != nil {
 			return req, err
@@ -271,6 +296,9 @@ func buildPutManifestRequest(imageManifest types.ImageManifest, targetRef refere
 func pushList(ctx context.Context, dockerCli command.Cli, req pushRequest) error {
 	rclient := dockerCli.RegistryClient(req.insecure)
 
+	if err := uploadBlobs(ctx, dockerCli.Out(), rclient, req.targetRef, req.layoutBlobs); err != nil {
+		return err
+	}
 	if err := mountBlobs(ctx, rclient, req.targetRef, req.manifestBlobs); err != nil {
 		return err
 	}
@@ -297,6 +325,31 @@ func pushReferences(ctx context.Context, out io.Writer, client registryclient.Re
 	return nil
 }
 
+func uploadBlobs(ctx context.Context, out io.Writer, client registryclient.RegistryClient, ref reference.Named, blobs []layoutBlob) error {
+	uploaded := map[string]bool{}
+	for _, blob := range blobs {
+		if uploaded[blob.desc.Digest.String()] {
+			continue
+		}
+		l, err := openLayout(blob.layout)
+		if err != nil {
+			return err
+		}
+		r, err := l.openBlob(blob.desc.Digest)
+		if err != nil {
+			return err
+		}
+		err = client.PutBlob(ctx, ref, blob.desc, r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+		uploaded[blob.desc.Digest.String()] = true
+		fmt.Fprintf(out, "Pushed blob %s\n", blob.desc.Digest)
+	}
+	return nil
+}
+
 func mountBlobs(ctx context.Context, client registryclient.RegistryClient, ref reference.Named, blobs []manifestBlob) error {
 	for _, blob := range blobs {
 		err := client.MountBlob(ctx, blob.canonical, ref)
@@ -3,6 +3,8 @@ package network
 import (
 	"context"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
@@ -17,6 +19,7 @@ type fakeClient struct {
 	networkListFunc       func(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
 	networkPruneFunc      func(ctx context.Context, pruneFilters filters.Args) (network.PruneReport, error)
 	networkInspectFunc    func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, []byte, error)
+	containerListFunc     func(ctx context.Context, options container.ListOptions) ([]types.Container, error)
 }
 
 func (c *fakeClient) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
@@ -67,3 +70,10 @@ func (c *fakeClient) NetworksPrune(ctx context.Context, pruneFilter filters.Args
 	}
 	return network.PruneReport{}, nil
 }
+
+func (c *fakeClient) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	if c.containerListFunc != nil {
+		return c.containerListFunc(ctx, options)
+	}
+	return nil, nil
+}
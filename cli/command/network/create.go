@@ -27,6 +27,7 @@ type createOptions struct {
 	ingress    bool
 	configOnly bool
 	configFrom string
+	autoSubnet bool
 
 	ipamDriver  string
 	ipamSubnet  []string
@@ -85,6 +86,7 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.Var(&options.ipamAux, "aux-address", "Auxiliary IPv4 or IPv6 addresses used by Network driver")
 	flags.Var(&options.ipamOpt, "ipam-opt", "Set IPAM driver specific options")
+	flags.BoolVar(&options.autoSubnet, "auto-subnet", false, "Use the next free subnet if a subnet overlaps with an existing network or route")
 
 	return cmd
 }
@@ -92,6 +94,9 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 func runCreate(ctx context.Context, dockerCli command.Cli, options createOptions) error {
 	client := dockerCli.Client()
 
+	if err := resolveSubnetConflicts(ctx, dockerCli, &options); err != nil {
+		return err
+	}
 	ipamCfg, err := consolidateIpam(options.ipamSubnet, options.ipamIPRange, options.ipamGateway, options.ipamAux.GetAll())
 	if err != nil {
 		return err
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types/network"
 	"github.com/pkg/errors"
@@ -226,3 +227,106 @@ func TestNetworkCreateIPv6(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkCreateSubnetConflict(t *testing.T) {
+	listFunc := func(context.Context, network.ListOptions) ([]network.Summary, error) {
+		return []network.Summary{
+			{Name: "bridge", Scope: "local", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.17.0.0/16"}}}},
+			{Name: "frontend", Scope: "local", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16"}}}},
+			{Name: "ingress", Scope: "swarm", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.19.0.0/16"}}}},
+			{Name: "config", Scope: "local", ConfigOnly: true, IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.20.0.0/16"}}}},
+		}, nil
+	}
+
+	testCases := []struct {
+		doc            string
+		args           []string
+		stdin          string
+		expectedSubnet string
+		expectedError  string
+		expectedErr    string
+	}{
+		{
+			doc:            "no conflict",
+			args:           []string{"--subnet", "10.1.0.0/24"},
+			expectedSubnet: "10.1.0.0/24",
+		},
+		{
+			doc:           "conflict",
+			args:          []string{"--subnet", "172.18.0.0/16"},
+			expectedError: `subnet 172.18.0.0/16 overlaps with subnet 172.18.0.0/16 of network "frontend"; use --subnet 172.19.0.0/16, or --auto-subnet to use the next free subnet`,
+		},
+		{
+			doc:            "auto-subnet",
+			args:           []string{"--subnet", "172.17.5.0/24", "--auto-subnet"},
+			expectedSubnet: "172.19.0.0/24",
+			expectedErr:    "The subnet 172.17.5.0/24 overlaps with subnet 172.17.0.0/16 of network \"bridge\", using subnet 172.19.0.0/24 instead\n",
+		},
+		{
+			doc:           "conflict with gateway",
+			args:          []string{"--subnet", "172.18.0.0/16", "--gateway", "172.18.0.1", "--auto-subnet"},
+			expectedError: `subnet 172.18.0.0/16 overlaps with subnet 172.18.0.0/16 of network "frontend"; the subnet 172.19.0.0/16 is free, but the ip-range, gateway, and aux-address of the subnet must be changed as well`,
+		},
+		{
+			doc:            "swarm scope",
+			args:           []string{"--subnet", "172.18.0.0/16", "--driver", "overlay", "--auto-subnet"},
+			expectedSubnet: "172.18.0.0/16",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			var subnet string
+			cli := test.NewFakeCli(&fakeClient{
+				networkListFunc: listFunc,
+				networkCreateFunc: func(_ context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+					subnet = options.IPAM.Config[0].Subnet
+					return network.CreateResponse{ID: name}, nil
+				},
+			})
+			cmd := newCreateCommand(cli)
+			cmd.SetArgs(append(tc.args, "my-network"))
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				assert.Check(t, is.Error(err, tc.expectedError))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(subnet, tc.expectedSubnet))
+			assert.Check(t, is.Equal(cli.ErrBuffer().String(), tc.expectedErr))
+		})
+	}
+}
+
+func TestNetworkCreateSubnetConflictPrompt(t *testing.T) {
+	for _, input := range []string{"y", "n"} {
+		var subnet string
+		cli := test.NewFakeCli(&fakeClient{
+			networkListFunc: func(context.Context, network.ListOptions) ([]network.Summary, error) {
+				return []network.Summary{
+					{Name: "frontend", Scope: "local", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "192.168.0.0/24"}}}},
+				}, nil
+			},
+			networkCreateFunc: func(_ context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+				subnet = options.IPAM.Config[0].Subnet
+				return network.CreateResponse{ID: name}, nil
+			},
+		})
+		in := streams.NewIn(io.NopCloser(strings.NewReader(input)))
+		in.SetIsTerminal(true)
+		cli.SetIn(in)
+		cmd := newCreateCommand(cli)
+		cmd.SetArgs([]string{"--subnet", "192.168.0.0/24", "my-network"})
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		err := cmd.Execute()
+		if input == "n" {
+			assert.Check(t, is.ErrorContains(err, "network create has been cancelled"))
+			continue
+		}
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(subnet, "192.168.1.0/24"))
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), "The subnet 192.168.0.0/24 overlaps with subnet 192.168.0.0/24 of network \"frontend\".\nUse the free subnet 192.168.1.0/24 instead? [y/N] my-network\n"))
+	}
+}
@@ -25,6 +25,8 @@ func NewFormat(source string, quiet bool) formatter.Format {
 			return formatter.DefaultQuietFormat
 		}
 		return defaultNetworkTableFormat
+	case formatter.CSVFormatKey, formatter.TSVFormatKey:
+		return NewFormat(formatter.TableFormatKey, quiet).Delimited(source)
 	case formatter.RawFormatKey:
 		if quiet {
 			return `network_id: {{.ID}}`
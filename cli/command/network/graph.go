@@ -0,0 +1,267 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
+type graphOptions struct {
+	format string
+	names  []string
+}
+
+func newGraphCommand(dockerCli command.Cli) *cobra.Command {
+	var opts graphOptions
+
+	cmd := &cobra.Command{
+		Use:   "graph [OPTIONS] [NETWORK...]",
+		Short: "Print a graph of networks and their containers",
+		Long: `Print a graph of networks, of the containers that are connected to them, and of
+the ports that the containers publish, or of all networks if none are
+specified. The graph is printed in the DOT language of Graphviz, or in the
+syntax of Mermaid.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runGraph(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.NetworkNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", graphFormatDOT, `Format of the graph ("dot", "mermaid")`)
+	return cmd
+}
+
+func runGraph(ctx context.Context, dockerCli command.Cli, opts graphOptions) error {
+	var write func(io.Writer, topology) error
+	switch opts.format {
+	case graphFormatDOT:
+		write = writeDOT
+	case graphFormatMermaid:
+		write = writeMermaid
+	default:
+		return errors.Errorf("invalid graph format %q: must be %q or %q", opts.format, graphFormatDOT, graphFormatMermaid)
+	}
+	topo, err := getTopology(ctx, dockerCli, opts.names)
+	if err != nil {
+		return err
+	}
+	return write(dockerCli.Out(), topo)
+}
+
+// topology is the connectivity of networks, containers, and published ports.
+type topology struct {
+	networks   []network.Inspect
+	containers []graphContainer
+}
+
+type graphContainer struct {
+	id        string
+	name      string
+	endpoints []graphEndpoint
+	ports     []types.Port
+}
+
+// graphEndpoint is the connection of a container to a network.
+type graphEndpoint struct {
+	networkID string
+	address   string
+}
+
+// getTopology returns the topology of the networks with the given names, or
+// of all networks. Only running containers are connected to networks, so the
+// ports are those of running containers as well.
+func getTopology(ctx context.Context, dockerCli command.Cli, names []string) (topology, error) {
+	apiClient := dockerCli.Client()
+	if len(names) == 0 {
+		summaries, err := apiClient.NetworkList(ctx, network.ListOptions{})
+		if err != nil {
+			return topology{}, err
+		}
+		for _, n := range summaries {
+			names = append(names, n.ID)
+		}
+	}
+
+	var topo topology
+	containers := map[string]*graphContainer{}
+	for _, name := range names {
+		n, _, err := apiClient.NetworkInspectWithRaw(ctx, name, network.InspectOptions{})
+		if err != nil {
+			return topology{}, err
+		}
+		topo.networks = append(topo.networks, n)
+		for id, ep := range n.Containers {
+			c, ok := containers[id]
+			if !ok {
+				c = &graphContainer{id: id, name: ep.Name}
+				containers[id] = c
+			}
+			address := ep.IPv4Address
+			if address == "" {
+				address = ep.IPv6Address
+			}
+			address, _, _ = strings.Cut(address, "/")
+			c.endpoints = append(c.endpoints, graphEndpoint{networkID: n.ID, address: address})
+		}
+	}
+	sort.Slice(topo.networks, func(i, j int) bool {
+		return sortorder.NaturalLess(topo.networks[i].Name, topo.networks[j].Name)
+	})
+
+	if len(containers) > 0 {
+		running, err := apiClient.ContainerList(ctx, container.ListOptions{})
+		if err != nil {
+			return topology{}, err
+		}
+		for _, rc := range running {
+			c, ok := containers[rc.ID]
+			if !ok {
+				continue
+			}
+			for _, p := range rc.Ports {
+				if p.PublicPort != 0 {
+					c.ports = append(c.ports, p)
+				}
+			}
+		}
+	}
+
+	networkNames := make(map[string]string, len(topo.networks))
+	for _, n := range topo.networks {
+		networkNames[n.ID] = n.Name
+	}
+	for _, c := range containers {
+		sort.Slice(c.endpoints, func(i, j int) bool {
+			return sortorder.NaturalLess(networkNames[c.endpoints[i].networkID], networkNames[c.endpoints[j].networkID])
+		})
+		sort.Slice(c.ports, func(i, j int) bool {
+			pi, pj := c.ports[i], c.ports[j]
+			if pi.PublicPort != pj.PublicPort {
+				return pi.PublicPort < pj.PublicPort
+			}
+			if pi.Type != pj.Type {
+				return pi.Type < pj.Type
+			}
+			return pi.IP < pj.IP
+		})
+		topo.containers = append(topo.containers, *c)
+	}
+	sort.Slice(topo.containers, func(i, j int) bool {
+		return sortorder.NaturalLess(topo.containers[i].name, topo.containers[j].name)
+	})
+	return topo, nil
+}
+
+// networkLabel returns the lines of the label of a network: its name, its
+// driver, and its subnets.
+func networkLabel(n network.Inspect) []string {
+	lines := []string{n.Name, "(" + n.Driver + ")"}
+	for _, cfg := range n.IPAM.Config {
+		if cfg.Subnet != "" {
+			lines = append(lines, cfg.Subnet)
+		}
+	}
+	return lines
+}
+
+// hostAddress returns the address of the host that a port is published on,
+// such as "0.0.0.0:8080".
+func hostAddress(p types.Port) string {
+	return net.JoinHostPort(p.IP, strconv.Itoa(int(p.PublicPort)))
+}
+
+// containerPort returns the port of the container, such as "80/tcp".
+func containerPort(p types.Port) string {
+	return strconv.Itoa(int(p.PrivatePort)) + "/" + p.Type
+}
+
+// writeDOT writes the topology as an undirected graph in the DOT language.
+func writeDOT(w io.Writer, topo topology) error {
+	// quote returns a quoted string of which the lines are separated by
+	// line breaks.
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	quote := func(lines ...string) string {
+		for i, l := range lines {
+			lines[i] = escaper.Replace(l)
+		}
+		return `"` + strings.Join(lines, `\n`) + `"`
+	}
+
+	var sb strings.Builder
+	sb.WriteString("graph networks {\n")
+	for _, n := range topo.networks {
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=ellipse];\n", quote("network:"+n.ID), quote(networkLabel(n)...))
+	}
+	for _, c := range topo.containers {
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=box];\n", quote("container:"+c.id), quote(c.name))
+		for _, p := range c.ports {
+			fmt.Fprintf(&sb, "  %s [label=%s, shape=plaintext];\n", quote("port:"+hostAddress(p)+"/"+p.Type), quote(hostAddress(p)))
+		}
+	}
+	for _, c := range topo.containers {
+		for _, ep := range c.endpoints {
+			fmt.Fprintf(&sb, "  %s -- %s [label=%s];\n", quote("container:"+c.id), quote("network:"+ep.networkID), quote(ep.address))
+		}
+		for _, p := range c.ports {
+			fmt.Fprintf(&sb, "  %s -- %s [label=%s];\n", quote("port:"+hostAddress(p)+"/"+p.Type), quote("container:"+c.id), quote(containerPort(p)))
+		}
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeMermaid writes the topology as a flowchart in the syntax of Mermaid.
+// Mermaid doesn't allow arbitrary node IDs, so nodes are numbered.
+func writeMermaid(w io.Writer, topo topology) error {
+	quote := func(lines ...string) string {
+		for i, l := range lines {
+			lines[i] = strings.ReplaceAll(l, `"`, "#quot;")
+		}
+		return `"` + strings.Join(lines, "<br>") + `"`
+	}
+
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	networkIDs := make(map[string]string, len(topo.networks))
+	for i, n := range topo.networks {
+		networkIDs[n.ID] = "n" + strconv.Itoa(i+1)
+		fmt.Fprintf(&sb, "  %s([%s])\n", networkIDs[n.ID], quote(networkLabel(n)...))
+	}
+	portIdx := 0
+	for i, c := range topo.containers {
+		id := "c" + strconv.Itoa(i+1)
+		fmt.Fprintf(&sb, "  %s[%s]\n", id, quote(c.name))
+		for _, ep := range c.endpoints {
+			fmt.Fprintf(&sb, "  %s ---|%s| %s\n", id, quote(ep.address), networkIDs[ep.networkID])
+		}
+		for _, p := range c.ports {
+			portIdx++
+			portID := "p" + strconv.Itoa(portIdx)
+			fmt.Fprintf(&sb, "  %s[/%s/]\n", portID, quote(hostAddress(p)))
+			fmt.Fprintf(&sb, "  %s ---|%s| %s\n", portID, quote(containerPort(p)), id)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,100 @@
+package network
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func graphClient() *fakeClient {
+	networks := map[string]network.Inspect{
+		"frontend": {
+			ID:     "frontend-id",
+			Name:   "frontend",
+			Driver: "bridge",
+			IPAM:   network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16"}}},
+			Containers: map[string]network.EndpointResource{
+				"web-id": {Name: "web", IPv4Address: "172.18.0.2/16"},
+			},
+		},
+		"backend": {
+			ID:     "backend-id",
+			Name:   "backend",
+			Driver: "bridge",
+			IPAM:   network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.19.0.0/16"}, {Subnet: "fd00::/64"}}},
+			Containers: map[string]network.EndpointResource{
+				"web-id": {Name: "web", IPv4Address: "172.19.0.3/16"},
+				"db-id":  {Name: "db", IPv6Address: "fd00::2/64"},
+			},
+		},
+		"none": {ID: "none-id", Name: "none", Driver: "null"},
+	}
+	return &fakeClient{
+		networkListFunc: func(context.Context, network.ListOptions) ([]network.Summary, error) {
+			return []network.Summary{{ID: "none-id"}, {ID: "frontend-id"}, {ID: "backend-id"}}, nil
+		},
+		networkInspectFunc: func(_ context.Context, networkID string, _ network.InspectOptions) (network.Inspect, []byte, error) {
+			for _, n := range networks {
+				if n.ID == networkID || n.Name == networkID {
+					return n, nil, nil
+				}
+			}
+			return network.Inspect{}, nil, nil
+		},
+		containerListFunc: func(context.Context, container.ListOptions) ([]types.Container, error) {
+			return []types.Container{
+				{ID: "web-id", Ports: []types.Port{
+					{IP: "::", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+					{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+					{PrivatePort: 443, Type: "tcp"},
+				}},
+				{ID: "db-id", Ports: []types.Port{{PrivatePort: 5432, Type: "tcp"}}},
+				{ID: "other-id", Ports: []types.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 9000, Type: "tcp"}}},
+			}, nil
+		},
+	}
+}
+
+func TestNetworkGraph(t *testing.T) {
+	for _, format := range []string{"dot", "mermaid"} {
+		t.Run(format, func(t *testing.T) {
+			cli := test.NewFakeCli(graphClient())
+			cmd := newGraphCommand(cli)
+			cmd.SetArgs([]string{"--format", format})
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), "network-graph."+format+".golden")
+		})
+	}
+}
+
+func TestNetworkGraphNetworks(t *testing.T) {
+	cli := test.NewFakeCli(graphClient())
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"--graph", "frontend"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "network-graph-frontend.golden")
+}
+
+func TestNetworkGraphInvalidFormat(t *testing.T) {
+	cmd := newGraphCommand(test.NewFakeCli(graphClient()))
+	cmd.SetArgs([]string{"--format", "svg"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `invalid graph format "svg": must be "dot" or "mermaid"`))
+}
+
+func TestNetworkInspectGraphConflict(t *testing.T) {
+	cmd := newInspectCommand(test.NewFakeCli(graphClient()))
+	cmd.SetArgs([]string{"--graph", "--format", "{{.Name}}", "frontend"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "conflicting options: --graph cannot be used with --format or --verbose"))
+}
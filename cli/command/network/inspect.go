@@ -12,6 +12,7 @@ import (
 	"github.com/docker/cli/cli/command/inspect"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/docker/api/types/network"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +20,7 @@ type inspectOptions struct {
 	format  string
 	names   []string
 	verbose bool
+	graph   bool
 }
 
 func newInspectCommand(dockerCli command.Cli) *cobra.Command {
@@ -37,11 +39,19 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 
 	cmd.Flags().StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
 	cmd.Flags().BoolVarP(&opts.verbose, "verbose", "v", false, "Verbose output for diagnostics")
+	cmd.Flags().BoolVar(&opts.graph, "graph", false, "Print a graph of the networks in the DOT language, instead of their details")
 
 	return cmd
 }
 
 func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions) error {
+	if opts.graph {
+		if opts.format != "" || opts.verbose {
+			return errors.New("conflicting options: --graph cannot be used with --format or --verbose")
+		}
+		return runGraph(ctx, dockerCli, graphOptions{format: graphFormatDOT, names: opts.names})
+	}
+
 	client := dockerCli.Client()
 
 	getNetFunc := func(name string) (any, []byte, error) {
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/network"
 	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +20,8 @@ type listOptions struct {
 	quiet   bool
 	noTrunc bool
 	format  string
+	columns []string
+	wrap    bool
 	filter  opts.FilterOpt
 }
 
@@ -39,13 +42,18 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display network IDs")
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Do not truncate the output")
-	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	flags.StringSliceVar(&options.columns, "columns", nil, flagsHelper.ColumnsHelp)
+	flags.BoolVar(&options.wrap, "wrap", false, flagsHelper.WrapHelp)
 	flags.VarP(&options.filter, "filter", "f", `Provide filter values (e.g. "driver=bridge")`)
 
 	return cmd
 }
 
 func runList(ctx context.Context, dockerCli command.Cli, options listOptions) error {
+	if len(options.columns) > 0 && (options.format != "" || options.quiet) {
+		return errors.New("conflicting options: --columns cannot be used together with --format or --quiet")
+	}
 	client := dockerCli.Client()
 	networkResources, err := client.NetworkList(ctx, network.ListOptions{Filters: options.filter.Value()})
 	if err != nil {
@@ -66,9 +74,11 @@ func runList(ctx context.Context, dockerCli command.Cli, options listOptions) er
 	})
 
 	networksCtx := formatter.Context{
-		Output: dockerCli.Out(),
-		Format: NewFormat(format, options.quiet),
-		Trunc:  !options.noTrunc,
+		Output:  dockerCli.Out(),
+		Format:  NewFormat(format, options.quiet),
+		Trunc:   !options.noTrunc,
+		Columns: options.columns,
+		Wrap:    options.wrap,
 	}
 	return FormatWrite(networksCtx, networkResources)
 }
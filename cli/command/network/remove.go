@@ -64,7 +64,7 @@ func runRemove(ctx context.Context, dockerCli command.Cli, networks []string, op
 			status = 1
 			continue
 		}
-		fmt.Fprintf(dockerCli.Out(), "%s\n", name)
+		dockerCli.Out().Infof("%s\n", name)
 	}
 
 	if status != 0 {
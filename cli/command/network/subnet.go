@@ -0,0 +1,253 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+// usedSubnet is a subnet that's used by a network, or by a route of the host.
+type usedSubnet struct {
+	prefix netip.Prefix
+	// owner describes what uses the subnet, such as `network "frontend"`.
+	owner string
+}
+
+// privateRanges are the ranges of private addresses in which free subnets
+// are picked.
+var privateRanges = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// resolveSubnetConflicts checks that the subnets of the network to create
+// don't overlap with the subnets of existing networks, or, if the daemon runs
+// on the same host as the CLI, with the routes of the host. The daemon only
+// reports the first overlap, without telling with what it overlaps.
+//
+// A subnet that overlaps is replaced with the next free subnet of the same
+// size if autoSubnet is set, or if the user confirms it when stdin is a
+// terminal. Otherwise, the free subnet is suggested in the error.
+func resolveSubnetConflicts(ctx context.Context, dockerCli command.Cli, options *createOptions) error {
+	if len(options.ipamSubnet) == 0 || options.configOnly || options.ipamDriver != "default" {
+		return nil
+	}
+	used, err := usedSubnets(ctx, dockerCli, options)
+	if err != nil {
+		return err
+	}
+
+	for i, s := range options.ipamSubnet {
+		subnet, err := netip.ParsePrefix(s)
+		if err != nil {
+			// Invalid subnets are reported by the daemon.
+			continue
+		}
+		conflict, ok := findOverlap(subnet, used)
+		if !ok {
+			continue
+		}
+		msg := fmt.Sprintf("subnet %s overlaps with subnet %s of %s", s, conflict.prefix, conflict.owner)
+		free, ok := nextFreeSubnet(subnet.Masked(), used)
+		if !ok {
+			return errdefs.InvalidParameter(errors.Errorf("%s, and no free subnet of the same size was found", msg))
+		}
+		if hasSubnetAddresses(options, s) {
+			return errdefs.InvalidParameter(errors.Errorf("%s; the subnet %s is free, but the ip-range, gateway, and aux-address of the subnet must be changed as well", msg, free))
+		}
+
+		switch {
+		case options.autoSubnet:
+			_, _ = fmt.Fprintf(dockerCli.Err(), "The %s, using subnet %s instead\n", msg, free)
+		case dockerCli.In().IsTerminal():
+			_, _ = fmt.Fprintf(dockerCli.Out(), "The %s.\n", msg)
+			ok, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), fmt.Sprintf("Use the free subnet %s instead?", free))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errdefs.Cancelled(errors.New("network create has been cancelled"))
+			}
+		default:
+			return errdefs.InvalidParameter(errors.Errorf("%s; use --subnet %s, or --auto-subnet to use the next free subnet", msg, free))
+		}
+		options.ipamSubnet[i] = free.String()
+		used = append(used, usedSubnet{prefix: free, owner: "network " + options.name})
+	}
+	return nil
+}
+
+// usedSubnets returns the subnets that the network to create must not
+// overlap with: those of the existing networks of the same scope, and the
+// routes of the host for local networks.
+func usedSubnets(ctx context.Context, dockerCli command.Cli, options *createOptions) ([]usedSubnet, error) {
+	scope := options.scope
+	if scope == "" {
+		scope = "local"
+		if options.driver == "overlay" {
+			scope = "swarm"
+		}
+	}
+
+	networks, err := dockerCli.Client().NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var used []usedSubnet
+	for _, n := range networks {
+		// Config-only networks don't use their subnets.
+		if n.Scope != scope || n.ConfigOnly {
+			continue
+		}
+		for _, cfg := range n.IPAM.Config {
+			if p, err := netip.ParsePrefix(cfg.Subnet); err == nil {
+				used = append(used, usedSubnet{prefix: p.Masked(), owner: fmt.Sprintf("network %q", n.Name)})
+			}
+		}
+	}
+
+	daemonHost := dockerCli.Client().DaemonHost()
+	if scope == "local" && strings.HasPrefix(daemonHost, "unix://") {
+		// The routes of the host are only known on Linux; on other systems,
+		// the file doesn't exist, or the daemon runs in a virtual machine.
+		if f, err := os.Open("/proc/net/route"); err == nil {
+			defer f.Close()
+			used = append(used, parseRoutes(f)...)
+		}
+	}
+	return used, nil
+}
+
+// parseRoutes parses the IPv4 routes in the format of /proc/net/route, in
+// which addresses are hexadecimal, in the byte order of the host. The default
+// route is omitted, as it overlaps with every subnet.
+func parseRoutes(r io.Reader) []usedSubnet {
+	var routes []usedSubnet
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // Skip the header.
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		dst, ok1 := parseRouteAddr(fields[1])
+		mask, ok2 := parseRouteAddr(fields[7])
+		if !ok1 || !ok2 {
+			continue
+		}
+		bits := 0
+		for m := binary.BigEndian.Uint32(mask[:]); m&(1<<31) != 0; m <<= 1 {
+			bits++
+		}
+		if bits == 0 {
+			continue
+		}
+		p := netip.PrefixFrom(netip.AddrFrom4(dst), bits).Masked()
+		routes = append(routes, usedSubnet{prefix: p, owner: "a route of the host (" + fields[0] + ")"})
+	}
+	return routes
+}
+
+func parseRouteAddr(s string) ([4]byte, bool) {
+	var addr [4]byte
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 4 {
+		return addr, false
+	}
+	// The addresses are little-endian on the architectures that Docker
+	// supports.
+	for i := range addr {
+		addr[i] = b[3-i]
+	}
+	return addr, true
+}
+
+func findOverlap(subnet netip.Prefix, used []usedSubnet) (usedSubnet, bool) {
+	for _, u := range used {
+		if u.prefix.Overlaps(subnet) {
+			return u, true
+		}
+	}
+	return usedSubnet{}, false
+}
+
+// nextFreeSubnet returns the first subnet with the size of subnet that comes
+// after it and doesn't overlap with the used subnets, wrapping around within
+// the range of private addresses that subnet is in.
+func nextFreeSubnet(subnet netip.Prefix, used []usedSubnet) (netip.Prefix, bool) {
+	var block netip.Prefix
+	for _, r := range privateRanges {
+		if r.Bits() <= subnet.Bits() && r.Contains(subnet.Addr()) {
+			block = r
+			break
+		}
+	}
+	if !block.IsValid() {
+		return netip.Prefix{}, false
+	}
+	// Only look at a limited number of candidates, as a /64 in fc00::/7
+	// has more of them than can be enumerated.
+	const maxCandidates = 1 << 16
+	candidate := subnet
+	for i := 0; i < maxCandidates; i++ {
+		next, ok := nextPrefix(candidate)
+		if !ok || !block.Contains(next.Addr()) {
+			next = netip.PrefixFrom(block.Addr(), subnet.Bits())
+		}
+		if next == subnet {
+			return netip.Prefix{}, false
+		}
+		if _, overlaps := findOverlap(next, used); !overlaps {
+			return next, true
+		}
+		candidate = next
+	}
+	return netip.Prefix{}, false
+}
+
+// nextPrefix returns the prefix of the same size that follows p, or false if p
+// is the last prefix of the address space.
+func nextPrefix(p netip.Prefix) (netip.Prefix, bool) {
+	addr := p.Addr().AsSlice()
+	// Add 1 at the last bit of the prefix, with carry.
+	bit := p.Bits() - 1
+	i, inc := bit/8, byte(1)<<(7-bit%8)
+	for ; i >= 0; i, inc = i-1, 1 {
+		sum := addr[i] + inc
+		carry := sum < addr[i]
+		addr[i] = sum
+		if !carry {
+			next, _ := netip.AddrFromSlice(addr)
+			return netip.PrefixFrom(next, p.Bits()), true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// hasSubnetAddresses returns whether an ip-range, gateway, or aux-address is
+// configured for the subnet.
+func hasSubnetAddresses(options *createOptions, subnet string) bool {
+	addrs := append(append([]string{}, options.ipamIPRange...), options.ipamGateway...)
+	for _, a := range options.ipamAux.GetAll() {
+		addrs = append(addrs, a)
+	}
+	for _, a := range addrs {
+		if ok, _ := subnetMatches(subnet, a); ok {
+			return true
+		}
+	}
+	return false
+}
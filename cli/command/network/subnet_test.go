@@ -0,0 +1,59 @@
+package network
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseRoutes(t *testing.T) {
+	const routes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0102A8C0	0003	0	0	100	00000000	0	0	0
+eth0	0002A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+docker0	000011AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+`
+	var actual []string
+	for _, r := range parseRoutes(strings.NewReader(routes)) {
+		actual = append(actual, r.prefix.String()+" "+r.owner)
+	}
+	assert.Check(t, is.DeepEqual(actual, []string{
+		"192.168.2.0/24 a route of the host (eth0)",
+		"172.17.0.0/16 a route of the host (docker0)",
+	}))
+}
+
+func TestNextFreeSubnet(t *testing.T) {
+	used := func(prefixes ...string) []usedSubnet {
+		var u []usedSubnet
+		for _, p := range prefixes {
+			u = append(u, usedSubnet{prefix: netip.MustParsePrefix(p)})
+		}
+		return u
+	}
+	testCases := []struct {
+		subnet   string
+		used     []usedSubnet
+		expected string
+	}{
+		{subnet: "172.18.0.0/16", used: used("172.17.0.0/16", "172.18.0.0/16", "172.19.0.0/16"), expected: "172.20.0.0/16"},
+		{subnet: "172.31.0.0/16", used: used("172.16.0.0/16", "172.31.0.0/16"), expected: "172.17.0.0/16"},
+		{subnet: "192.168.1.0/24", used: used("192.168.0.0/23"), expected: "192.168.2.0/24"},
+		{subnet: "10.0.0.0/8", used: used("10.1.0.0/16")},
+		{subnet: "8.8.8.0/24", used: used("8.8.8.0/24")},
+		{subnet: "fd00:1::/64", used: used("fd00:1::/64"), expected: "fd00:1:0:1::/64"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.subnet, func(t *testing.T) {
+			free, ok := nextFreeSubnet(netip.MustParsePrefix(tc.subnet), tc.used)
+			if tc.expected == "" {
+				assert.Check(t, !ok, "unexpected free subnet %s", free)
+				return
+			}
+			assert.Check(t, ok)
+			assert.Check(t, is.Equal(free.String(), tc.expected))
+		})
+	}
+}
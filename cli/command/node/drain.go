@@ -0,0 +1,255 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/idresolver"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type drainOptions struct {
+	node    string
+	wait    bool
+	timeout time.Duration
+	quiet   bool
+}
+
+func newDrainCommand(dockerCli command.Cli) *cobra.Command {
+	var options drainOptions
+
+	cmd := &cobra.Command{
+		Use:   "drain [OPTIONS] NODE",
+		Short: "Drain a node, and wait for its tasks to move to other nodes",
+		Long: `Drain a node, by setting its availability to "drain". With the --wait option,
+the command waits for the tasks of the node to be rescheduled on other nodes,
+showing their progress, and reports the tasks that failed to move.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.node = args[0]
+			return runDrain(cmd.Context(), dockerCli, options)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&options.wait, "wait", "w", false, "Wait for the tasks of the node to move to other nodes")
+	flags.DurationVar(&options.timeout, "timeout", 0, "Maximum time to wait for the tasks to move (0 to wait indefinitely)")
+	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Suppress progress output")
+	return cmd
+}
+
+func runDrain(ctx context.Context, dockerCli command.Cli, options drainOptions) error {
+	apiClient := dockerCli.Client()
+
+	nodeID, err := Reference(ctx, apiClient, options.node)
+	if err != nil {
+		return err
+	}
+	node, _, err := apiClient.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+
+	// The tasks of the node are listed before it's drained, so that tasks
+	// shut down by the drain can be followed.
+	tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("node", node.ID),
+			filters.Arg("desired-state", string(swarm.TaskStateRunning)),
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	if node.Spec.Availability == swarm.NodeAvailabilityDrain {
+		dockerCli.Err().Infof("Node %s is already drained.\n", options.node)
+	} else {
+		node.Spec.Availability = swarm.NodeAvailabilityDrain
+		if err := apiClient.NodeUpdate(ctx, node.ID, node.Version, node.Spec); err != nil {
+			return err
+		}
+	}
+	if !options.wait {
+		_, _ = fmt.Fprintln(dockerCli.Out(), options.node)
+		return nil
+	}
+
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	errChan := make(chan error, 1)
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		errChan <- drainProgress(ctx, apiClient, node.ID, tasks, pipeWriter)
+	}()
+
+	if options.quiet {
+		go io.Copy(io.Discard, pipeReader)
+		return <-errChan
+	}
+	err = jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil)
+	if err == nil {
+		err = <-errChan
+	}
+	return err
+}
+
+// drainProgress outputs progress information for the tasks of a drained node
+// as they move to other nodes, until all of them moved or stopped. An error
+// listing the tasks that didn't move is returned if ctx is done first.
+func drainProgress(ctx context.Context, apiClient client.APIClient, nodeID string, tasks []swarm.Task, progressWriter io.WriteCloser) error {
+	defer progressWriter.Close()
+
+	progressOut := streamformatter.NewJSONProgressOutput(progressWriter, false)
+	if len(tasks) == 0 {
+		progress.Messagef(progressOut, "", "Node %s has no running tasks", nodeID)
+		return nil
+	}
+
+	resolver := idresolver.New(apiClient, false)
+	serviceFilter := filters.NewArgs()
+	for _, t := range tasks {
+		serviceFilter.Add("service", t.ServiceID)
+	}
+
+	moves := make([]*taskMove, 0, len(tasks))
+	for _, t := range tasks {
+		serviceName, err := resolver.Resolve(ctx, swarm.Service{}, t.ServiceID)
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%s.%d", serviceName, t.Slot)
+		if t.Slot == 0 {
+			name = serviceName + "." + t.NodeID
+		}
+		moves = append(moves, &taskMove{name: name, task: t})
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i].name < moves[j].name
+	})
+
+	for {
+		current, err := apiClient.TaskList(ctx, types.TaskListOptions{Filters: serviceFilter})
+		if err != nil {
+			if ctx.Err() != nil {
+				return drainTimeoutError(nodeID, moves)
+			}
+			return err
+		}
+
+		done := true
+		for _, m := range moves {
+			status := m.update(nodeID, current)
+			if status.moved {
+				nodeName, err := resolver.Resolve(ctx, swarm.Node{}, status.node)
+				if err != nil {
+					return err
+				}
+				status.action += " " + nodeName
+			}
+			if m.status != status {
+				progress.Update(progressOut, m.name, status.action)
+				m.status = status
+			}
+			done = done && status.done
+		}
+		if done {
+			progress.Messagef(progressOut, "", "All tasks moved off node %s", nodeID)
+			return nil
+		}
+
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-ctx.Done():
+			return drainTimeoutError(nodeID, moves)
+		}
+	}
+}
+
+// taskMove is a task of a drained node, and the status of its move to another
+// node.
+type taskMove struct {
+	name   string
+	task   swarm.Task
+	status moveStatus
+}
+
+type moveStatus struct {
+	action string
+	node   string
+	moved  bool
+	done   bool
+}
+
+// update returns the status of the move of the task, from the current tasks
+// of its service. A replicated task moved once the task that replaces it in
+// its slot runs on another node; a global task, or a task without replacement,
+// is only expected to stop.
+func (m *taskMove) update(nodeID string, current []swarm.Task) moveStatus {
+	var original *swarm.Task
+	var replacement *swarm.Task
+	for i, t := range current {
+		switch {
+		case t.ID == m.task.ID:
+			original = &current[i]
+		case m.task.Slot != 0 && t.ServiceID == m.task.ServiceID && t.Slot == m.task.Slot &&
+			t.NodeID != nodeID && t.DesiredState == swarm.TaskStateRunning:
+			if replacement == nil || t.Meta.CreatedAt.After(replacement.Meta.CreatedAt) {
+				replacement = &current[i]
+			}
+		}
+	}
+
+	stopped := original == nil || isTerminal(original.Status.State)
+	switch {
+	case replacement != nil && replacement.Status.State == swarm.TaskStateRunning:
+		return moveStatus{action: "moved to", node: replacement.NodeID, moved: true, done: true}
+	case replacement != nil && replacement.Status.Err != "":
+		return moveStatus{action: "moving: " + formatter.FirstLine(replacement.Status.Err)}
+	case replacement != nil:
+		return moveStatus{action: "moving: " + string(replacement.Status.State)}
+	case stopped:
+		return moveStatus{action: "stopped", done: true}
+	default:
+		return moveStatus{action: "stopping"}
+	}
+}
+
+func isTerminal(state swarm.TaskState) bool {
+	switch state {
+	case swarm.TaskStateComplete, swarm.TaskStateShutdown, swarm.TaskStateFailed,
+		swarm.TaskStateRejected, swarm.TaskStateRemove, swarm.TaskStateOrphaned:
+		return true
+	default:
+		return false
+	}
+}
+
+func drainTimeoutError(nodeID string, moves []*taskMove) error {
+	var failed []string
+	for _, m := range moves {
+		if !m.status.done {
+			failed = append(failed, m.name+" ("+m.status.action+")")
+		}
+	}
+	return errors.Errorf("timeout waiting for tasks to move off node %s: %s", nodeID, strings.Join(failed, ", "))
+}
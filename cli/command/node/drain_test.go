@@ -0,0 +1,124 @@
+package node
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func drainTask(id string, slot int, nodeID string, state swarm.TaskState, err string) swarm.Task {
+	return swarm.Task{
+		ID:           id,
+		ServiceID:    "service-id",
+		Slot:         slot,
+		NodeID:       nodeID,
+		DesiredState: swarm.TaskStateRunning,
+		Status:       swarm.TaskStatus{State: state, Err: err},
+	}
+}
+
+func TestTaskMoveUpdate(t *testing.T) {
+	original := drainTask("task-1", 1, "node-1", swarm.TaskStateRunning, "")
+	shutdown := original
+	shutdown.DesiredState = swarm.TaskStateShutdown
+	shutdown.Status.State = swarm.TaskStateShutdown
+
+	testCases := []struct {
+		name     string
+		task     swarm.Task
+		current  []swarm.Task
+		expected moveStatus
+	}{
+		{
+			name:     "stopping",
+			task:     original,
+			current:  []swarm.Task{original},
+			expected: moveStatus{action: "stopping"},
+		},
+		{
+			name: "pending",
+			task: original,
+			current: []swarm.Task{
+				shutdown,
+				drainTask("task-2", 1, "", swarm.TaskStatePending, "no suitable node (insufficient resources on 1 node)"),
+			},
+			expected: moveStatus{action: "moving: no suitable node (insufficient resources on 1 node)"},
+		},
+		{
+			name: "moved",
+			task: original,
+			current: []swarm.Task{
+				shutdown,
+				drainTask("task-2", 1, "node-2", swarm.TaskStateRunning, ""),
+			},
+			expected: moveStatus{action: "moved to", node: "node-2", moved: true, done: true},
+		},
+		{
+			name:     "global",
+			task:     drainTask("task-3", 0, "node-1", swarm.TaskStateRunning, ""),
+			current:  []swarm.Task{drainTask("task-4", 0, "node-2", swarm.TaskStateRunning, "")},
+			expected: moveStatus{action: "stopped", done: true},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := &taskMove{task: tc.task}
+			assert.Check(t, is.Equal(m.update("node-1", tc.current), tc.expected))
+		})
+	}
+}
+
+func TestNodeDrain(t *testing.T) {
+	var availability swarm.NodeAvailability
+	var calls int
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return swarm.Node{ID: "node-1", Description: swarm.NodeDescription{Hostname: "worker"}}, nil, nil
+		},
+		nodeUpdateFunc: func(_ string, _ swarm.Version, spec swarm.NodeSpec) error {
+			availability = spec.Availability
+			return nil
+		},
+		taskListFunc: func(types.TaskListOptions) ([]swarm.Task, error) {
+			calls++
+			if calls == 1 {
+				return []swarm.Task{drainTask("task-1", 1, "node-1", swarm.TaskStateRunning, "")}, nil
+			}
+			return []swarm.Task{drainTask("task-2", 1, "node-2", swarm.TaskStateRunning, "")}, nil
+		},
+		serviceInspectFunc: func(context.Context, string, types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web"}}}, nil, nil
+		},
+	})
+	cmd := newDrainCommand(cli)
+	cmd.SetArgs([]string{"--wait", "node-1"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(availability, swarm.NodeAvailabilityDrain))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "web.1: moved to worker"))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "All tasks moved off node node-1"))
+}
+
+func TestNodeDrainTimeout(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		nodeInspectFunc: func() (swarm.Node, []byte, error) {
+			return swarm.Node{ID: "node-1"}, nil, nil
+		},
+		taskListFunc: func(types.TaskListOptions) ([]swarm.Task, error) {
+			return []swarm.Task{drainTask("task-1", 1, "node-1", swarm.TaskStateRunning, "")}, nil
+		},
+		serviceInspectFunc: func(context.Context, string, types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "web"}}}, nil, nil
+		},
+	})
+	err := runDrain(context.Background(), cli, drainOptions{node: "node-1", wait: true, timeout: 10 * time.Millisecond, quiet: true})
+	assert.Check(t, is.Error(err, "timeout waiting for tasks to move off node node-1: web.1 (stopping)"))
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/docker/cli/cli/command/image"
 	"github.com/docker/docker/api/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
-	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/registry"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -26,11 +25,13 @@ type pluginOptions struct {
 	args            []string
 	skipRemoteCheck bool
 	untrusted       bool
+	progress        string
 }
 
 func loadPullFlags(dockerCli command.Cli, opts *pluginOptions, flags *pflag.FlagSet) {
 	flags.BoolVar(&opts.grantPerms, "grant-all-permissions", false, "Grant all permissions necessary to run the plugin")
 	command.AddTrustVerificationFlags(flags, &opts.untrusted, dockerCli.ContentTrustEnabled())
+	command.AddProgressFlag(flags, &opts.progress)
 }
 
 func newInstallCommand(dockerCli command.Cli) *cobra.Command {
@@ -129,10 +130,12 @@ func runInstall(ctx context.Context, dockerCli command.Cli, opts pluginOptions)
 		return err
 	}
 	defer responseBody.Close()
-	if err := jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Out(), nil); err != nil {
+	if err := command.DisplayProgress(responseBody, dockerCli.Out(), opts.progress, nil); err != nil {
 		return err
 	}
-	fmt.Fprintf(dockerCli.Out(), "Installed plugin %s\n", opts.remote) // todo: return proper values from the API for this result
+	if opts.progress != command.ProgressJSON {
+		fmt.Fprintf(dockerCli.Out(), "Installed plugin %s\n", opts.remote) // todo: return proper values from the API for this result
+	}
 	return nil
 }
 
@@ -8,7 +8,6 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/image"
 	registrytypes "github.com/docker/docker/api/types/registry"
-	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/docker/registry"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -17,6 +16,7 @@ import (
 type pushOptions struct {
 	name      string
 	untrusted bool
+	progress  string
 }
 
 func newPushCommand(dockerCli command.Cli) *cobra.Command {
@@ -34,6 +34,7 @@ func newPushCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 
 	command.AddTrustSigningFlags(flags, &opts.untrusted, dockerCli.ContentTrustEnabled())
+	command.AddProgressFlag(flags, &opts.progress)
 
 	return cmd
 }
@@ -69,5 +70,5 @@ func runPush(ctx context.Context, dockerCli command.Cli, opts pushOptions) error
 		return image.PushTrustedReference(dockerCli, repoInfo, named, authConfig, responseBody)
 	}
 
-	return jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Out(), nil)
+	return command.DisplayProgress(responseBody, dockerCli.Out(), opts.progress, nil)
 }
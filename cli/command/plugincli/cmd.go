@@ -0,0 +1,45 @@
+package plugincli
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// NewPluginCLICommand returns a cobra command for `plugin-cli` subcommands
+func NewPluginCLICommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plugin-cli",
+		Short: "Manage CLI plugins",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newInstallCommand(dockerCli),
+		newListCommand(dockerCli),
+		newPermissionsCommand(dockerCli),
+		newPinCommand(dockerCli),
+		newRemoveCommand(dockerCli),
+		newUnpinCommand(dockerCli),
+		newUpgradeCommand(dockerCli),
+	)
+	return cmd
+}
+
+// completeNames offers completion for the names of the plugins installed with
+// "docker plugin-cli install"
+func completeNames(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	dir, err := pluginsDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(records))
+	for _, r := range sortedRecords(records) {
+		names = append(names, r.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
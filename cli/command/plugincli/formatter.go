@@ -0,0 +1,83 @@
+package plugincli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command/formatter"
+)
+
+const (
+	defaultPluginTableFormat = "table {{.Name}}\t{{.Version}}\t{{.Source}}\t{{.Pinned}}"
+
+	pluginNameHeader    = "NAME"
+	pluginVersionHeader = "VERSION"
+	pluginSourceHeader  = "SOURCE"
+	pluginPinnedHeader  = "PINNED"
+)
+
+func newFormat(source string, quiet bool) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		if quiet {
+			return `{{.Name}}`
+		}
+		return defaultPluginTableFormat
+	case formatter.RawFormatKey:
+		if quiet {
+			return `name: {{.Name}}`
+		}
+		return `name: {{.Name}}\nversion: {{.Version}}\nsource: {{.Source}}\npinned: {{.Pinned}}\n`
+	}
+	return formatter.Format(source)
+}
+
+func formatWrite(ctx formatter.Context, records []record) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, r := range records {
+			if err := format(&pluginContext{r: r}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	pluginCtx := pluginContext{}
+	pluginCtx.Header = formatter.SubHeaderContext{
+		"Name":    pluginNameHeader,
+		"Version": pluginVersionHeader,
+		"Source":  pluginSourceHeader,
+		"Pinned":  pluginPinnedHeader,
+	}
+	return ctx.Write(&pluginCtx, render)
+}
+
+type pluginContext struct {
+	formatter.HeaderContext
+	r record
+}
+
+func (c *pluginContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *pluginContext) Name() string {
+	return c.r.Name
+}
+
+func (c *pluginContext) Version() string {
+	return displayVersion(c.r)
+}
+
+// Source is the source of the plugin, with the tag that it's installed from.
+func (c *pluginContext) Source() string {
+	s := source{repository: c.r.Source, tag: c.r.Tag, github: strings.HasPrefix(c.r.Source, githubPrefix)}
+	return s.String()
+}
+
+func (c *pluginContext) Digest() string {
+	return c.r.Digest
+}
+
+func (c *pluginContext) Pinned() string {
+	return strconv.FormatBool(c.r.Pinned)
+}
@@ -0,0 +1,302 @@
+package plugincli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+)
+
+const (
+	// githubTokenEnvVar is the name of the environment variable of the token
+	// that authenticates the requests to the GitHub API, which are rate
+	// limited otherwise.
+	githubTokenEnvVar = "GITHUB_TOKEN"
+
+	// maxAPIResponseSize is the maximum size of the responses of the GitHub
+	// API, and of checksum files.
+	maxAPIResponseSize = 4 << 20
+
+	// maxBinarySize is the maximum size of the assets that are downloaded.
+	maxBinarySize = 512 << 20
+)
+
+var (
+	// githubAPIURL is the URL of the GitHub API.
+	githubAPIURL = "https://api.github.com"
+
+	githubHTTPClient = &http.Client{Timeout: 10 * time.Minute}
+)
+
+// osNames and archNames are the names that the assets of releases use for
+// the operating systems and architectures that differ from GOOS and GOARCH.
+var (
+	osNames = map[string][]string{
+		"darwin": {"darwin", "macos"},
+	}
+	archNames = map[string][]string{
+		"amd64": {"amd64", "x86_64"},
+		"arm64": {"arm64", "aarch64"},
+		"arm":   {"armv7", "arm-v7"},
+	}
+)
+
+// metadataSuffixes are the extensions of the assets of releases that are not
+// the binary of a plugin.
+var metadataSuffixes = []string{
+	".sha256", ".sha256sum", ".sha512", ".sig", ".asc", ".pem", ".crt", ".cert",
+	".json", ".jsonl", ".sbom", ".spdx", ".txt", ".md", ".zip", ".deb", ".rpm",
+}
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type githubAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// resolveGitHub resolves a GitHub source to the asset for this platform of
+// the release with its tag, or of the latest release. The asset is a binary,
+// or a tar archive of the binary. It's verified with its checksum in the
+// SHA256 checksums files of the release ("<asset>.sha256", or checksums.txt),
+// or with the checksum that is set.
+func resolveGitHub(ctx context.Context, src source, name, checksum string) (resolved, error) {
+	repo := strings.TrimPrefix(src.repository, githubPrefix)
+	u := githubAPIURL + "/repos/" + repo + "/releases/latest"
+	if src.tag != "" {
+		u = githubAPIURL + "/repos/" + repo + "/releases/tags/" + src.tag
+	}
+	var release githubRelease
+	if err := getGitHubJSON(ctx, u, &release); err != nil {
+		return resolved{}, errors.Wrapf(err, "failed to get release of %s", src)
+	}
+	asset, err := selectAsset(release.Assets, name)
+	if err != nil {
+		return resolved{}, errors.Wrapf(err, "release %s of %s", release.TagName, src.repository)
+	}
+
+	return resolved{
+		tag:      release.TagName,
+		revision: asset.URL,
+		fetch: func(ctx context.Context) ([]byte, error) {
+			expected := checksum
+			if expected == "" {
+				sum, err := assetChecksum(ctx, release.Assets, asset.Name)
+				if err != nil {
+					return nil, err
+				}
+				if sum == "" {
+					return nil, errors.Errorf("release %s of %s has no checksum of %s: use --checksum to verify it", release.TagName, src.repository, asset.Name)
+				}
+				expected = sum
+			}
+			b, err := download(ctx, asset.URL, maxBinarySize)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to download %s", asset.Name)
+			}
+			if err := verifyChecksum(b, expected); err != nil {
+				return nil, errors.Wrap(err, asset.Name)
+			}
+			if !isArchive(b) {
+				return b, nil
+			}
+			names := binaryNames(src, name)
+			bin, err := extractBinary(b, names)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid archive %s", asset.Name)
+			}
+			if bin == nil {
+				return nil, errors.Errorf("archive %s has no %s binary", asset.Name, names[0])
+			}
+			return bin, nil
+		},
+	}, nil
+}
+
+// selectAsset returns the asset of a release that is the binary of the
+// plugin with the given name for this platform.
+func selectAsset(assets []githubAsset, name string) (githubAsset, error) {
+	var matches []githubAsset
+	for _, a := range assets {
+		if assetMatches(a.Name, runtime.GOOS, runtime.GOARCH) {
+			matches = append(matches, a)
+		}
+	}
+	if len(matches) > 1 {
+		// Releases of several binaries have an asset for each.
+		var named []githubAsset
+		for _, a := range matches {
+			if strings.Contains(strings.ToLower(a.Name), name) {
+				named = append(named, a)
+			}
+		}
+		if len(named) > 0 {
+			matches = named
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return githubAsset{}, errors.Errorf("no asset for %s/%s", runtime.GOOS, runtime.GOARCH)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, a := range matches {
+			names = append(names, a.Name)
+		}
+		return githubAsset{}, errors.Errorf("several assets for %s/%s: %s", runtime.GOOS, runtime.GOARCH, strings.Join(names, ", "))
+	}
+}
+
+// assetMatches returns whether the name of an asset is that of a binary, or
+// an archive, for the given operating system and architecture.
+func assetMatches(name, goos, goarch string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range metadataSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return false
+		}
+	}
+	if strings.HasSuffix(name, ".exe") != (goos == "windows") && !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".tgz") {
+		return false
+	}
+	return hasWord(name, aliasesOf(osNames, goos)) && hasWord(name, aliasesOf(archNames, goarch))
+}
+
+func aliasesOf(aliases map[string][]string, name string) []string {
+	if n, ok := aliases[name]; ok {
+		return n
+	}
+	return []string{name}
+}
+
+// hasWord returns whether s has one of words, delimited by separators.
+func hasWord(s string, words []string) bool {
+	for _, w := range words {
+		if regexp.MustCompile(`(^|[-_./])` + regexp.QuoteMeta(w) + `($|[-_.])`).MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// assetChecksum returns the SHA256 checksum of the asset with the given name,
+// from the "<name>.sha256" asset, or from checksums files in the format of
+// sha256sum, such as checksums.txt. It returns an empty string if the release
+// has none.
+func assetChecksum(ctx context.Context, assets []githubAsset, name string) (string, error) {
+	var files []githubAsset
+	for _, a := range assets {
+		n := strings.ToLower(a.Name)
+		switch {
+		case n == strings.ToLower(name)+".sha256", n == strings.ToLower(name)+".sha256sum":
+			files = append([]githubAsset{a}, files...)
+		case strings.Contains(n, "checksums") || strings.Contains(n, "sha256sums"):
+			files = append(files, a)
+		}
+	}
+	for _, f := range files {
+		b, err := download(ctx, f.URL, maxAPIResponseSize)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to download %s", f.Name)
+		}
+		if sum := findChecksum(b, name); sum != "" {
+			return sum, nil
+		}
+	}
+	return "", nil
+}
+
+// findChecksum returns the checksum of the file with the given name in the
+// output of sha256sum, or the checksum of a file that has a single one.
+func findChecksum(b []byte, name string) string {
+	var sums []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || len(fields[0]) != 2*sha256.Size {
+			continue
+		}
+		if len(fields) == 1 {
+			sums = append(sums, fields[0])
+			continue
+		}
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0]
+		}
+	}
+	if len(sums) == 1 {
+		return sums[0]
+	}
+	return ""
+}
+
+// verifyChecksum checks that the SHA256 checksum of b is sum, with or without
+// the "sha256:" prefix.
+func verifyChecksum(b []byte, sum string) error {
+	sum = strings.ToLower(strings.TrimPrefix(sum, "sha256:"))
+	actual := sha256.Sum256(b)
+	if hex.EncodeToString(actual[:]) != sum {
+		return errors.Errorf("checksum mismatch: expected sha256:%s, got sha256:%s", sum, hex.EncodeToString(actual[:]))
+	}
+	return nil
+}
+
+func getGitHubJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv(githubTokenEnvVar); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	b, err := doRequest(req, maxAPIResponseSize)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func download(ctx context.Context, u string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doRequest(req, maxSize)
+}
+
+func doRequest(req *http.Request, maxSize int64) ([]byte, error) {
+	req.Header.Set("User-Agent", command.UserAgent())
+	resp, err := githubHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s from %s", resp.Status, req.URL.Host)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > maxSize {
+		return nil, errors.Errorf("response from %s is larger than %d bytes", req.URL.Host, maxSize)
+	}
+	return b, nil
+}
@@ -0,0 +1,189 @@
+package plugincli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testGitHub is the GitHub API, with the releases of repositories by tag,
+// and their assets.
+type testGitHub struct {
+	*httptest.Server
+	releases map[string][]githubRelease
+	assets   map[string][]byte
+}
+
+func newTestGitHub(t *testing.T) *testGitHub {
+	t.Helper()
+	gh := &testGitHub{releases: map[string][]githubRelease{}, assets: map[string][]byte{}}
+	gh.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if b, ok := gh.assets[r.URL.Path]; ok {
+			_, _ = w.Write(b)
+			return
+		}
+		repo, tag, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/repos/"), "/releases/")
+		if !ok || len(gh.releases[repo]) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		releases := gh.releases[repo]
+		release := releases[len(releases)-1]
+		if tag != "latest" {
+			release.TagName = ""
+			for _, rel := range releases {
+				if "tags/"+rel.TagName == tag {
+					release = rel
+				}
+			}
+			if release.TagName == "" {
+				http.NotFound(w, r)
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(release)
+	}))
+	t.Cleanup(gh.Close)
+	githubAPIURL = gh.URL
+	t.Cleanup(func() { githubAPIURL = "https://api.github.com" })
+	return gh
+}
+
+// addRelease adds a release of repo with the given assets.
+func (gh *testGitHub) addRelease(repo, tag string, assets map[string][]byte) {
+	release := githubRelease{TagName: tag}
+	for name, b := range assets {
+		path := "/download/" + repo + "/" + tag + "/" + name
+		gh.assets[path] = b
+		release.Assets = append(release.Assets, githubAsset{Name: name, URL: gh.URL + path})
+	}
+	gh.releases[repo] = append(gh.releases[repo], release)
+}
+
+func sha256sum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// assetName returns the name of the asset of docker/compose for this
+// platform.
+func assetName() string {
+	return "docker-compose-" + runtime.GOOS + "-" + runtime.GOARCH + exeSuffix
+}
+
+func TestInstallFromGitHub(t *testing.T) {
+	gh := newTestGitHub(t)
+	bin := testPlugin(t, "v2.29.7")
+	gh.addRelease("docker/compose", "v2.29.7", map[string][]byte{
+		assetName():                    bin,
+		"docker-compose-plan9-386":     []byte("other"),
+		assetName() + ".sha256":        []byte(sha256sum(bin) + " *" + assetName() + "\n"),
+		"docker-compose-plan9-386.sig": []byte("signature"),
+	})
+	cli := newTestCli(t, nil)
+
+	assert.NilError(t, runCommand(newInstallCommand(cli), "github.com/docker/compose"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Installed compose v2.29.7 from github.com/docker/compose\n"))
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	installed, err := os.ReadFile(pluginPath(dir, "compose"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(installed, bin))
+}
+
+func TestInstallFromGitHubArchive(t *testing.T) {
+	gh := newTestGitHub(t)
+	bin := testPlugin(t, "v1.2.0")
+	archive := tarGz(t, map[string][]byte{"hello_1.2.0/docker-hello": bin, "hello_1.2.0/README.md": []byte("hello")})
+	name := "hello_1.2.0_" + runtime.GOOS + "_" + runtime.GOARCH + ".tar.gz"
+	gh.addRelease("example/docker-hello", "v1.2.0", map[string][]byte{
+		name:            archive,
+		"checksums.txt": []byte(sha256sum([]byte("other")) + "  hello_1.2.0_plan9_386.tar.gz\n" + sha256sum(archive) + "  " + name + "\n"),
+	})
+	gh.addRelease("example/docker-hello", "v1.3.0", map[string][]byte{})
+	cli := newTestCli(t, nil)
+
+	assert.NilError(t, runCommand(newInstallCommand(cli), "github.com/example/docker-hello@v1.2.0"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Installed hello v1.2.0 from github.com/example/docker-hello\n"))
+}
+
+func TestInstallFromGitHubChecksums(t *testing.T) {
+	gh := newTestGitHub(t)
+	bin := testPlugin(t, "v1.0.0")
+	gh.addRelease("example/hello", "v1.0.0", map[string][]byte{"docker-hello-" + runtime.GOOS + "-" + runtime.GOARCH + exeSuffix: bin})
+	gh.addRelease("example/tampered", "v1.0.0", map[string][]byte{
+		"tampered-" + runtime.GOOS + "-" + runtime.GOARCH + exeSuffix: bin,
+		"checksums.txt": []byte(sha256sum([]byte("original")) + "  tampered-" + runtime.GOOS + "-" + runtime.GOARCH + exeSuffix + "\n"),
+	})
+
+	err := runCommand(newInstallCommand(newTestCli(t, nil)), "github.com/example/hello")
+	assert.Check(t, is.ErrorContains(err, "release v1.0.0 of github.com/example/hello has no checksum of docker-hello-"))
+
+	err = runCommand(newInstallCommand(newTestCli(t, nil)), "github.com/example/tampered")
+	assert.Check(t, is.ErrorContains(err, "checksum mismatch: expected sha256:"+sha256sum([]byte("original"))))
+
+	assert.NilError(t, runCommand(newInstallCommand(newTestCli(t, nil)), "--checksum", "sha256:"+sha256sum(bin), "github.com/example/hello"))
+
+	err = runCommand(newInstallCommand(newTestCli(t, nil)), "github.com/example/hello@v2.0.0")
+	assert.Check(t, is.ErrorContains(err, "failed to get release of github.com/example/hello@v2.0.0: unexpected status 404 Not Found"))
+}
+
+func TestAssetMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		goos     string
+		goarch   string
+		expected bool
+	}{
+		{name: "docker-compose-linux-x86_64", goos: "linux", goarch: "amd64", expected: true},
+		{name: "docker-compose-linux-aarch64", goos: "linux", goarch: "arm64", expected: true},
+		{name: "docker-compose-darwin-aarch64", goos: "darwin", goarch: "arm64", expected: true},
+		{name: "docker-compose-windows-x86_64.exe", goos: "windows", goarch: "amd64", expected: true},
+		{name: "buildx-v0.17.1.linux-amd64", goos: "linux", goarch: "amd64", expected: true},
+		{name: "buildx-v0.17.1.linux-arm-v7", goos: "linux", goarch: "arm", expected: true},
+		{name: "hello_1.2.0_macos_amd64.tar.gz", goos: "darwin", goarch: "amd64", expected: true},
+		{name: "hello_1.2.0_windows_amd64.tar.gz", goos: "windows", goarch: "amd64", expected: true},
+		{name: "docker-compose-linux-x86_64.sha256", goos: "linux", goarch: "amd64"},
+		{name: "buildx-v0.17.1.linux-amd64.provenance.json", goos: "linux", goarch: "amd64"},
+		{name: "buildx-v0.17.1.linux-arm64", goos: "linux", goarch: "arm"},
+		{name: "buildx-v0.17.1.linux-arm-v6", goos: "linux", goarch: "arm"},
+		{name: "docker-compose-windows-x86_64.exe", goos: "linux", goarch: "amd64"},
+		{name: "docker-compose-windows-x86_64", goos: "windows", goarch: "amd64"},
+		{name: "docker-compose-linux-x86_64", goos: "darwin", goarch: "amd64"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name+"/"+tc.goos+"/"+tc.goarch, func(t *testing.T) {
+			assert.Check(t, is.Equal(assetMatches(tc.name, tc.goos, tc.goarch), tc.expected))
+		})
+	}
+}
+
+func TestFindChecksum(t *testing.T) {
+	sum := sha256sum([]byte("hello"))
+	other := sha256sum([]byte("other"))
+	tests := []struct {
+		doc      string
+		content  string
+		expected string
+	}{
+		{doc: "sha256sum", content: other + "  other\n" + sum + "  hello\n", expected: sum},
+		{doc: "binary mode", content: sum + " *hello\n", expected: sum},
+		{doc: "only the checksum", content: sum + "\n", expected: sum},
+		{doc: "other files", content: other + "  other\n"},
+		{doc: "invalid", content: "hello\n"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			assert.Check(t, is.Equal(findChecksum([]byte(tc.content), "hello"), tc.expected))
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package plugincli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type installOptions struct {
+	source string
+
+	name                string
+	checksum            string
+	key                 string
+	certificateChain    string
+	certificateIdentity string
+	pin                 bool
+	force               bool
+	grantPerms          bool
+}
+
+func newInstallCommand(dockerCli command.Cli) *cobra.Command {
+	var opts installOptions
+
+	cmd := &cobra.Command{
+		Use:   "install [OPTIONS] SOURCE",
+		Short: "Install a CLI plugin from a registry or a GitHub release",
+		Long: `Install a CLI plugin from an OCI artifact in a registry, such as
+"docker/buildx-bin:0.17.1", or from the assets of a GitHub release, with
+"github.com/OWNER/REPO[@TAG]".`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			return runInstall(cmd.Context(), dockerCli, cmd.Root(), opts)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.name, "name", "", "Name of the plugin (default: derived from the repository)")
+	flags.StringVar(&opts.checksum, "checksum", "", "Require the SHA256 checksum of the binary, or of the asset of the GitHub release, to match")
+	flags.StringVar(&opts.key, "key", "", "Require a signature of the OCI artifact made with the private key of this PEM-encoded public key")
+	flags.StringVar(&opts.certificateChain, "certificate-chain", "", "Require a signature of the OCI artifact made with a certificate issued by one of the PEM-encoded certificates of this file")
+	flags.StringVar(&opts.certificateIdentity, "certificate-identity", "", "Require the certificate of the signature to have this identity (email address, URI, or subject)")
+	flags.BoolVar(&opts.pin, "pin", false, "Pin the plugin to the installed version")
+	flags.BoolVarP(&opts.force, "force", "f", false, "Replace the plugin if it's already installed")
+	flags.BoolVar(&opts.grantPerms, "grant-all-permissions", false, "Grant all permissions that the plugin requires")
+	return cmd
+}
+
+func runInstall(ctx context.Context, dockerCli command.Cli, root *cobra.Command, opts installOptions) error {
+	src, err := parseSource(opts.source)
+	if err != nil {
+		return err
+	}
+	name := opts.name
+	if name == "" {
+		name = defaultName(src)
+	}
+	if err := validateName(root, name); err != nil {
+		return err
+	}
+	verify, err := signatureOptions(src, opts)
+	if err != nil {
+		return err
+	}
+
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(pluginPath(dir, name)); err == nil && !opts.force {
+		return errors.Errorf("plugin %q is already installed: use --force to replace it, or \"docker plugin-cli upgrade\" to upgrade it", name)
+	}
+
+	r, err := resolveSource(ctx, dockerCli, src, name, opts.checksum, verify)
+	if err != nil {
+		return err
+	}
+	rec, err := installPlugin(ctx, dockerCli, dir, name, src, r, opts.checksum, opts.grantPerms)
+	if err != nil {
+		return err
+	}
+	rec.Verify, rec.Pinned = verify, opts.pin
+	records[name] = rec
+	if err := saveRecords(dir, records); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Installed %s %s from %s\n", name, displayVersion(rec), src.repository)
+	return nil
+}
+
+// validateName checks that name is a valid name of a plugin that doesn't
+// conflict with the commands of root.
+func validateName(root *cobra.Command, name string) error {
+	if !pluginNameRe.MatchString(name) {
+		return errors.Errorf("invalid plugin name %q: names must match %q, use --name to set it", name, pluginNameRe.String())
+	}
+	for _, c := range root.Commands() {
+		if manager.IsPluginCommand(c) {
+			continue
+		}
+		if c.Name() == name || c.HasAlias(name) {
+			return errors.Errorf("invalid plugin name %q: it would conflict with the \"docker %s\" command", name, c.Name())
+		}
+	}
+	return nil
+}
+
+// signatureOptions returns what the signature of the OCI artifact of src is
+// verified with, or nil if it's not verified. The paths of the keys and
+// certificates are absolute, as they are recorded for upgrades.
+func signatureOptions(src source, opts installOptions) (*image.SignatureOptions, error) {
+	if opts.key == "" && opts.certificateChain == "" && opts.certificateIdentity == "" {
+		return nil, nil
+	}
+	if src.github {
+		return nil, errors.New("--key, --certificate-chain, and --certificate-identity are only supported for OCI artifacts: use --checksum to verify the assets of GitHub releases")
+	}
+	verify := &image.SignatureOptions{CertificateIdentity: opts.certificateIdentity}
+	var err error
+	if verify.Key, err = absPath(opts.key); err != nil {
+		return nil, err
+	}
+	if verify.CertificateChain, err = absPath(opts.certificateChain); err != nil {
+		return nil, err
+	}
+	return verify, nil
+}
+
+func absPath(p string) (string, error) {
+	if p == "" {
+		return "", nil
+	}
+	return filepath.Abs(p)
+}
+
+// resolveSource resolves the OCI artifact, or the GitHub release of src.
+func resolveSource(ctx context.Context, dockerCli command.Cli, src source, name, checksum string, verify *image.SignatureOptions) (resolved, error) {
+	if src.github {
+		return resolveGitHub(ctx, src, name, checksum)
+	}
+	var sigOpts image.SignatureOptions
+	if verify != nil {
+		sigOpts = *verify
+	}
+	return resolveOCI(ctx, dockerCli.RegistryClient(false), src, name, sigOpts)
+}
+
+// installPlugin checks that the permissions that the plugin with the given
+// name requires are granted, then fetches its binary, and checks that it's a
+// valid CLI plugin, before installing it in dir. The permissions are those
+// declared by the artifact of the plugin, so that the binary isn't executed
+// before they're granted. The checksum of OCI artifacts is that of the
+// binary; that of GitHub releases is verified by the fetch of r.
+func installPlugin(ctx context.Context, dockerCli command.Cli, dir, name string, src source, r resolved, checksum string, grantAll bool) (record, error) {
+	granted, err := manager.GrantedPermissions()
+	if err != nil {
+		return record{}, err
+	}
+	if err := acceptPermissions(ctx, dockerCli, name, r.permissions, granted, grantAll); err != nil {
+		return record{}, err
+	}
+
+	bin, err := r.fetch(ctx)
+	if err != nil {
+		return record{}, err
+	}
+	if checksum != "" && !src.github {
+		if err := verifyChecksum(bin, checksum); err != nil {
+			return record{}, errors.Wrap(err, src.String())
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return record{}, err
+	}
+	f, err := os.CreateTemp(dir, "."+manager.NamePrefix+name+"-*"+exeSuffix)
+	if err != nil {
+		return record{}, err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	_, err = f.Write(bin)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return record{}, err
+	}
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return record{}, err
+	}
+	meta, err := pluginMetadata(tmp)
+	if err != nil {
+		return record{}, errors.Wrapf(err, "%s is not a valid CLI plugin", src)
+	}
+	if err := os.Rename(tmp, pluginPath(dir, name)); err != nil {
+		return record{}, err
+	}
+	if r.permissions != nil {
+		granted[name] = *r.permissions
+	} else {
+		delete(granted, name)
+	}
+	if err := manager.SaveGrantedPermissions(granted); err != nil {
+		return record{}, err
+	}
+	return record{
+		Name:        name,
+		Source:      src.repository,
+		Tag:         r.tag,
+		Version:     meta.Version,
+		Revision:    r.revision,
+		Digest:      digest.FromBytes(bin).String(),
+		InstalledAt: time.Now().UTC(),
+	}, nil
+}
+
+// acceptPermissions asks the user to grant the permissions that the plugin
+// with the given name requires, unless they were granted to it already, or
+// grantAll is set.
+func acceptPermissions(ctx context.Context, dockerCli command.Cli, name string, perms *manager.Permissions, granted map[string]manager.Permissions, grantAll bool) error {
+	if perms == nil || grantAll {
+		return nil
+	}
+	if prev, ok := granted[name]; (ok && prev.Covers(*perms)) || perms.IsEmpty() {
+		return nil
+	}
+	_, _ = fmt.Fprintf(dockerCli.Out(), "Plugin %q is requesting the following permissions:\n", name)
+	for _, p := range perms.Describe() {
+		_, _ = fmt.Fprintf(dockerCli.Out(), " - %s\n", p)
+	}
+	ok, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), "Do you grant the above permissions?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.Errorf("permissions of plugin %q were not granted: use --grant-all-permissions to grant them", name)
+	}
+	return nil
+}
+
+// pluginMetadata returns the metadata of the plugin with the binary at path,
+// which it must have to be a valid CLI plugin.
+func pluginMetadata(path string) (manager.Metadata, error) {
+	var meta manager.Metadata
+	out, err := exec.Command(path, manager.MetadataSubcommandName).Output()
+	if err != nil {
+		return meta, errors.Wrap(err, "failed to get its metadata")
+	}
+	if err := json.Unmarshal(out, &meta); err != nil {
+		return meta, errors.Wrap(err, "invalid metadata")
+	}
+	if meta.SchemaVersion != "0.1.0" {
+		return meta, errors.Errorf("unsupported SchemaVersion %q of its metadata", meta.SchemaVersion)
+	}
+	if meta.Vendor == "" {
+		return meta, errors.New("its metadata doesn't define a vendor")
+	}
+	return meta, nil
+}
+
+// displayVersion returns the version of a plugin, or the tag that it was
+// installed from if it doesn't report one.
+func displayVersion(rec record) string {
+	if rec.Version != "" {
+		return rec.Version
+	}
+	return rec.Tag
+}
@@ -0,0 +1,57 @@
+package plugincli
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	quiet  bool
+	format string
+}
+
+func newListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List installed CLI plugins",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(dockerCli, opts)
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display plugin names")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+
+	return cmd
+}
+
+func runList(dockerCli command.Cli, opts listOptions) error {
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return err
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	pluginCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newFormat(format, opts.quiet),
+	}
+	return formatWrite(pluginCtx, sortedRecords(records))
+}
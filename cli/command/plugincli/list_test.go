@@ -0,0 +1,45 @@
+package plugincli
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestList(t *testing.T) {
+	cli := newTestCli(t, nil)
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	assert.NilError(t, saveRecords(dir, map[string]record{
+		"compose": {Name: "compose", Source: "github.com/docker/compose", Tag: "v2.29.7", Version: "v2.29.7"},
+		"buildx":  {Name: "buildx", Source: "docker/buildx-bin", Tag: "0.17.1", Version: "v0.17.1", Pinned: true},
+		"hello":   {Name: "hello", Source: "registry.example.com/plugins/hello", Tag: "latest"},
+	}))
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			expected: `NAME      VERSION   SOURCE                                      PINNED
+buildx    v0.17.1   docker/buildx-bin:0.17.1                    true
+compose   v2.29.7   github.com/docker/compose@v2.29.7           false
+hello     latest    registry.example.com/plugins/hello:latest   false
+`,
+		},
+		{
+			args:     []string{"--quiet"},
+			expected: "buildx\ncompose\nhello\n",
+		},
+		{
+			args:     []string{"--format", "{{.Name}}: {{.Source}}"},
+			expected: "buildx: docker/buildx-bin:0.17.1\ncompose: github.com/docker/compose@v2.29.7\nhello: registry.example.com/plugins/hello:latest\n",
+		},
+	}
+	for _, tc := range tests {
+		cli.OutBuffer().Reset()
+		assert.NilError(t, runCommand(newListCommand(cli), tc.args...))
+		assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+	}
+}
@@ -0,0 +1,163 @@
+package plugincli
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+// resolveOCI resolves the OCI artifact of a source to the manifest for this
+// platform. The binary of the plugin is the file of one of the layers of the
+// manifest, as in the "docker/buildx-bin" and "docker/compose-bin" images, or
+// a layer itself, as pushed with "oras push".
+//
+// If sigOpts has a key or certificates, the manifest, or the image index that
+// it's a manifest of, must have a signature that is verified with them.
+func resolveOCI(ctx context.Context, rc client.RegistryClient, src source, name string, sigOpts image.SignatureOptions) (resolved, error) {
+	named, err := reference.ParseNormalizedNamed(src.repository)
+	if err != nil {
+		return resolved{}, err
+	}
+	ref, err := reference.WithTag(named, src.tag)
+	if err != nil {
+		return resolved{}, err
+	}
+	desc, payload, err := rc.GetRawManifest(ctx, ref)
+	if err != nil {
+		return resolved{}, err
+	}
+	if sigOpts.Key != "" || sigOpts.CertificateChain != "" {
+		if err := image.VerifySignature(ctx, rc, ref, desc.Digest, sigOpts); err != nil {
+			return resolved{}, err
+		}
+	}
+
+	var annotations map[string]string
+	if desc.MediaType == ocispec.MediaTypeImageIndex || desc.MediaType == mediaTypeDockerManifestList {
+		var index ocispec.Index
+		if err := json.Unmarshal(payload, &index); err == nil {
+			annotations = index.Annotations
+		}
+		dgst, err := platformManifest(payload)
+		if err != nil {
+			return resolved{}, errors.Wrap(err, src.String())
+		}
+		if payload, err = getVerified(ctx, rc, ref, dgst, true); err != nil {
+			return resolved{}, err
+		}
+		desc.Digest = dgst
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return resolved{}, errors.Wrapf(err, "invalid manifest of %s", src)
+	}
+	if _, ok := manifest.Annotations[manager.PermissionsAnnotation]; ok {
+		// The manifest for this platform overrides the image index.
+		annotations = manifest.Annotations
+	}
+	perms, err := declaredPermissions(annotations)
+	if err != nil {
+		return resolved{}, errors.Wrap(err, src.String())
+	}
+
+	return resolved{
+		tag:         src.tag,
+		revision:    desc.Digest.String(),
+		permissions: perms,
+		fetch: func(ctx context.Context) ([]byte, error) {
+			names := binaryNames(src, name)
+			// Files of the upper layers override the lower layers.
+			for i := len(manifest.Layers) - 1; i >= 0; i-- {
+				layer := manifest.Layers[i]
+				title := layer.Annotations[ocispec.AnnotationTitle]
+				b, err := getVerified(ctx, rc, ref, layer.Digest, false)
+				if err != nil {
+					return nil, err
+				}
+				if !isArchive(b) {
+					if isBinaryName(names, title) || len(manifest.Layers) == 1 {
+						return b, nil
+					}
+					continue
+				}
+				bin, err := extractBinary(b, names)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid layer %s of %s", layer.Digest, src)
+				}
+				if bin != nil {
+					return bin, nil
+				}
+			}
+			return nil, errors.Errorf("%s has no %s binary for %s", src, names[0], platforms.DefaultString())
+		},
+	}, nil
+}
+
+// declaredPermissions returns the permissions that are declared in the
+// annotations of the manifest of an OCI artifact, or nil if there are none.
+func declaredPermissions(annotations map[string]string) (*manager.Permissions, error) {
+	v, ok := annotations[manager.PermissionsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var perms manager.Permissions
+	if err := json.Unmarshal([]byte(v), &perms); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s annotation", manager.PermissionsAnnotation)
+	}
+	return &perms, nil
+}
+
+// platformManifest returns the digest of the manifest for this platform of
+// an image index.
+func platformManifest(payload []byte) (digest.Digest, error) {
+	var index ocispec.Index
+	if err := json.Unmarshal(payload, &index); err != nil {
+		return "", errors.Wrap(err, "invalid image index")
+	}
+	matcher := platforms.Only(platforms.DefaultSpec())
+	var best *ocispec.Descriptor
+	for i, m := range index.Manifests {
+		if m.Platform == nil || !matcher.Match(*m.Platform) {
+			continue
+		}
+		if best == nil || matcher.Less(*m.Platform, *best.Platform) {
+			best = &index.Manifests[i]
+		}
+	}
+	if best == nil {
+		return "", errors.Errorf("no manifest for %s", platforms.DefaultString())
+	}
+	return best.Digest, nil
+}
+
+// getVerified returns the content of a manifest, or blob, of the repository
+// of ref, after checking that it matches its digest.
+func getVerified(ctx context.Context, rc client.RegistryClient, ref reference.Named, dgst digest.Digest, manifest bool) ([]byte, error) {
+	named, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	if manifest {
+		_, b, err = rc.GetRawManifest(ctx, named)
+	} else {
+		b, err = rc.GetBlob(ctx, named)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !dgst.Algorithm().Available() || dgst.Algorithm().FromBytes(b) != dgst {
+		return nil, errors.Errorf("content of %s doesn't match its digest", dgst)
+	}
+	return b, nil
+}
@@ -0,0 +1,209 @@
+package plugincli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/containerd/platforms"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testPlugin returns the binary of a plugin that reports the given version.
+func testPlugin(t *testing.T, version string) []byte {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	return []byte("#!/bin/sh\necho '{\"SchemaVersion\":\"0.1.0\",\"Vendor\":\"Example\",\"Version\":\"" + version + "\"}'\n")
+}
+
+// tarGz returns a gzip-compressed tar archive with the given files.
+func tarGz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write(content)
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	assert.NilError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// addImage adds an image index of repo with the given tag to r, with a
+// manifest for this platform and one for another, with the given layers.
+func addImage(t *testing.T, r *registry.Registry, repo, tag string, layers ...ocispec.Descriptor) {
+	t.Helper()
+	other := r.AddManifest(t, repo, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers:    []ocispec.Descriptor{r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"docker-other": []byte("other")}))},
+	})
+	other.Platform = &ocispec.Platform{OS: "plan9", Architecture: "386"}
+	native := r.AddManifest(t, repo, "", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers:    layers,
+	})
+	p := platforms.DefaultSpec()
+	native.Platform = &p
+	manifests := []ocispec.Descriptor{other, native}
+	r.AddManifest(t, repo, tag, ocispec.MediaTypeImageIndex, ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: manifests})
+}
+
+func newTestCli(t *testing.T, r *registry.Registry) *test.FakeCli {
+	t.Helper()
+	config.SetDir(t.TempDir())
+	cli := test.NewFakeCli(nil)
+	if r != nil {
+		cli.SetRegistryClient(r)
+	}
+	return cli
+}
+
+func runCommand(cmd *cobra.Command, args ...string) error {
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+func TestInstallFromOCI(t *testing.T) {
+	r := registry.New()
+	bin := testPlugin(t, "v0.17.1")
+	addImage(t, r, "docker.io/docker/buildx-bin", "0.17.1",
+		r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"LICENSE": []byte("license")})),
+		r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"buildx": bin})),
+	)
+	cli := newTestCli(t, r)
+
+	assert.NilError(t, runCommand(newInstallCommand(cli), "docker/buildx-bin:0.17.1"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Installed buildx v0.17.1 from docker/buildx-bin\n"))
+
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	installed, err := os.ReadFile(pluginPath(dir, "buildx"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(installed, bin))
+	records, err := loadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(records["buildx"].Source, "docker/buildx-bin"))
+	assert.Check(t, is.Equal(records["buildx"].Tag, "0.17.1"))
+	assert.Check(t, is.Equal(records["buildx"].Digest, digest.FromBytes(bin).String()))
+
+	err = runCommand(newInstallCommand(cli), "docker/buildx-bin:0.17.1")
+	assert.Check(t, is.ErrorContains(err, `plugin "buildx" is already installed: use --force to replace it`))
+	assert.NilError(t, runCommand(newInstallCommand(cli), "--force", "docker/buildx-bin:0.17.1"))
+}
+
+func TestInstallFromOCIArtifact(t *testing.T) {
+	r := registry.New()
+	bin := testPlugin(t, "v1.0.0")
+	layer := r.AddBlob(t, "application/octet-stream", bin)
+	layer.Annotations = map[string]string{ocispec.AnnotationTitle: "docker-hello"}
+	r.AddManifest(t, "registry.example.com/plugins/hello", "v1", ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.AddBlob(t, "application/vnd.oci.empty.v1+json", []byte("{}")),
+		Layers:    []ocispec.Descriptor{layer},
+	})
+	cli := newTestCli(t, r)
+
+	assert.NilError(t, runCommand(newInstallCommand(cli), "--checksum", digest.FromBytes(bin).String(), "registry.example.com/plugins/hello:v1"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Installed hello v1.0.0 from registry.example.com/plugins/hello\n"))
+}
+
+func TestInstallErrors(t *testing.T) {
+	r := registry.New()
+	addImage(t, r, "docker.io/library/notaplugin", "latest",
+		r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"notaplugin": []byte("#!/bin/sh\necho hello\n")})),
+	)
+	addImage(t, r, "docker.io/library/empty", "latest",
+		r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"README": []byte("hello")})),
+	)
+	addImage(t, r, "docker.io/library/hello", "latest",
+		r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"docker-hello": testPlugin(t, "v1")})),
+	)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	assert.NilError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	assert.NilError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644))
+
+	tests := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"github.com/docker"},
+			expectedError: `invalid source "github.com/docker": GitHub repositories must be in the github.com/OWNER/REPO[@TAG] format`,
+		},
+		{
+			args:          []string{"example@sha256:0123"},
+			expectedError: `invalid source "example@sha256:0123"`,
+		},
+		{
+			args:          []string{"docker/my-tool"},
+			expectedError: `invalid plugin name "my-tool"`,
+		},
+		{
+			args:          []string{"--name", "version", "hello"},
+			expectedError: `invalid plugin name "version": it would conflict with the "docker version" command`,
+		},
+		{
+			args:          []string{"--key", "key.pem", "github.com/docker/compose"},
+			expectedError: "--key, --certificate-chain, and --certificate-identity are only supported for OCI artifacts",
+		},
+		{
+			args:          []string{"--key", keyFile, "hello"},
+			expectedError: "no signature found for hello:latest",
+		},
+		{
+			args:          []string{"--checksum", "sha256:0123", "hello"},
+			expectedError: "checksum mismatch: expected sha256:0123",
+		},
+		{
+			args:          []string{"unknown"},
+			expectedError: "manifest unknown",
+		},
+		{
+			args:          []string{"empty"},
+			expectedError: "empty:latest has no docker-empty binary for " + platforms.DefaultString(),
+		},
+		{
+			args:          []string{"notaplugin"},
+			expectedError: "notaplugin:latest is not a valid CLI plugin: invalid metadata",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expectedError, func(t *testing.T) {
+			cli := newTestCli(t, r)
+			root := &cobra.Command{Use: "docker"}
+			root.AddCommand(NewPluginCLICommand(cli), &cobra.Command{Use: "version", Run: func(*cobra.Command, []string) {}})
+			err := runCommand(root, append([]string{"plugin-cli", "install"}, tc.args...)...)
+			assert.Check(t, is.ErrorContains(err, tc.expectedError))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package plugincli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newPermissionsCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "permissions [PLUGIN...]",
+		Short: "Display the permissions granted to installed CLI plugins",
+		Long: `Display the permissions granted to the given CLI plugins, or to all the
+plugins installed with "docker plugin-cli install". Plugins that don't
+declare the permissions that they require are unrestricted.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPermissions(dockerCli, args)
+		},
+		ValidArgsFunction: completeNames,
+	}
+}
+
+func runPermissions(dockerCli command.Cli, names []string) error {
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return err
+	}
+	granted, err := manager.GrantedPermissions()
+	if err != nil {
+		return err
+	}
+
+	var list []record
+	for _, name := range names {
+		rec, ok := records[name]
+		if !ok {
+			return errors.Errorf("plugin %q was not installed with \"docker plugin-cli install\"", name)
+		}
+		list = append(list, rec)
+	}
+	if len(names) == 0 {
+		list = sortedRecords(records)
+	}
+
+	w := tabwriter.NewWriter(dockerCli.Out(), 10, 1, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tCREDENTIALS\tENV\tSOCKET")
+	for _, rec := range list {
+		perms, ok := granted[rec.Name]
+		if !ok {
+			_, _ = fmt.Fprintf(w, "%s\tunrestricted\tunrestricted\tunrestricted\n", rec.Name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", rec.Name, joinOrNone(perms.Credentials), joinOrNone(perms.Env), strconv.FormatBool(perms.Socket))
+	}
+	return w.Flush()
+}
+
+func joinOrNone(s []string) string {
+	if len(s) == 0 {
+		return "none"
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,118 @@
+package plugincli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// testPluginWithMarker returns the binary of a plugin that reports the given
+// version, and creates the marker file when it's executed.
+func testPluginWithMarker(t *testing.T, version, marker string) []byte {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugins are shell scripts")
+	}
+	return []byte("#!/bin/sh\ntouch '" + marker + "'\necho '{\"SchemaVersion\":\"0.1.0\",\"Vendor\":\"Example\",\"Version\":\"" + version + "\"}'\n")
+}
+
+// addHello adds the OCI artifact of the "hello" plugin with the given tag,
+// which manifest declares the given permissions in JSON.
+func addHello(t *testing.T, r *registry.Registry, tag, version, permissions, marker string) {
+	t.Helper()
+	r.AddManifest(t, "docker.io/library/hello", tag, ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    r.AddBlob(t, ocispec.MediaTypeImageConfig, []byte("{}")),
+		Layers: []ocispec.Descriptor{
+			r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"docker-hello": testPluginWithMarker(t, version, marker)})),
+		},
+		Annotations: map[string]string{manager.PermissionsAnnotation: permissions},
+	})
+}
+
+func TestInstallPermissions(t *testing.T) {
+	r := registry.New()
+	marker := filepath.Join(t.TempDir(), "executed")
+	addHello(t, r, "v1", "v1.0.0", `{"Credentials":["ghcr.io"],"Env":["GITHUB_TOKEN"]}`, marker)
+	cli := newTestCli(t, r)
+
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("n\n"))))
+	err := runCommand(newInstallCommand(cli), "hello:v1")
+	assert.Check(t, is.Error(err, `permissions of plugin "hello" were not granted: use --grant-all-permissions to grant them`))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Plugin "hello" is requesting the following permissions:
+ - credentials: ghcr.io
+ - env: GITHUB_TOKEN
+Do you grant the above permissions? [y/N] `))
+	granted, err := manager.GrantedPermissions()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(granted, 0))
+	_, err = os.Stat(marker)
+	assert.Check(t, os.IsNotExist(err), "the plugin must not be executed before its permissions are granted")
+
+	cli.OutBuffer().Reset()
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("y\n"))))
+	assert.NilError(t, runCommand(newInstallCommand(cli), "hello:v1"))
+	granted, err = manager.GrantedPermissions()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(granted, map[string]manager.Permissions{
+		"hello": {Credentials: []string{"ghcr.io"}, Env: []string{"GITHUB_TOKEN"}},
+	}))
+
+	// Permissions that were granted already aren't requested again.
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runCommand(newInstallCommand(cli), "--force", "hello:v1"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Installed hello v1.0.0 from hello\n"))
+
+	// Upgrades that require more permissions request them.
+	addHello(t, r, "v2", "v2.0.0", `{"Credentials":["*"],"Socket":true}`, marker)
+	cli.OutBuffer().Reset()
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(""))))
+	err = runCommand(newUpgradeCommand(cli))
+	assert.Check(t, is.ErrorContains(err, `failed to upgrade hello: permissions of plugin "hello" were not granted`))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), " - credentials: all registries\n - socket: raw access to the socket of the daemon (advisory, not enforced)\n"))
+
+	assert.NilError(t, runCommand(newUpgradeCommand(cli), "--grant-all-permissions"))
+	granted, err = manager.GrantedPermissions()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(granted["hello"], manager.Permissions{Credentials: []string{"*"}, Socket: true}))
+
+	assert.NilError(t, runCommand(newRemoveCommand(cli), "hello"))
+	granted, err = manager.GrantedPermissions()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(granted, 0))
+}
+
+func TestPermissions(t *testing.T) {
+	cli := newTestCli(t, nil)
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	assert.NilError(t, saveRecords(dir, map[string]record{
+		"buildx": {Name: "buildx", Source: "docker/buildx-bin", Tag: "0.17.1"},
+		"hello":  {Name: "hello", Source: "hello", Tag: "v1"},
+		"world":  {Name: "world", Source: "world", Tag: "v1"},
+	}))
+	assert.NilError(t, manager.SaveGrantedPermissions(map[string]manager.Permissions{
+		"hello": {Credentials: []string{"docker.io", "ghcr.io"}, Env: []string{"GITHUB_*"}},
+		"world": {},
+	}))
+
+	assert.NilError(t, runCommand(newPermissionsCommand(cli)))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `NAME      CREDENTIALS         ENV            SOCKET
+buildx    unrestricted        unrestricted   unrestricted
+hello     docker.io,ghcr.io   GITHUB_*       false
+world     none                none           false
+`))
+
+	err = runCommand(newPermissionsCommand(cli), "unknown")
+	assert.Check(t, is.Error(err, `plugin "unknown" was not installed with "docker plugin-cli install"`))
+}
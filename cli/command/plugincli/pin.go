@@ -0,0 +1,72 @@
+package plugincli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newPinCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "pin PLUGIN [PLUGIN...]",
+		Short: "Pin one or more CLI plugins to their installed version",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPin(dockerCli, args, true)
+		},
+		ValidArgsFunction: completeNames,
+	}
+}
+
+func newUnpinCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unpin PLUGIN [PLUGIN...]",
+		Short: "Unpin one or more CLI plugins, to upgrade them",
+		Args:  cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPin(dockerCli, args, false)
+		},
+		ValidArgsFunction: completeNames,
+	}
+}
+
+// runPin pins, or unpins, the plugins with the given names.
+func runPin(dockerCli command.Cli, names []string, pinned bool) error {
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	var updated []string
+	for _, name := range names {
+		rec, ok := records[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("plugin %q was not installed with \"docker plugin-cli install\"", name))
+			continue
+		}
+		rec.Pinned = pinned
+		records[name] = rec
+		updated = append(updated, name)
+	}
+	if len(updated) > 0 {
+		if err := saveRecords(dir, records); err != nil {
+			return err
+		}
+		for _, name := range updated {
+			_, _ = fmt.Fprintln(dockerCli.Out(), name)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
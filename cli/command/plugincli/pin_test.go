@@ -0,0 +1,31 @@
+package plugincli
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPin(t *testing.T) {
+	cli := newTestCli(t, nil)
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	assert.NilError(t, saveRecords(dir, map[string]record{
+		"buildx":  {Name: "buildx", Source: "docker/buildx-bin", Tag: "0.17.1"},
+		"compose": {Name: "compose", Source: "github.com/docker/compose", Tag: "v2.29.7"},
+	}))
+
+	err = runCommand(newPinCommand(cli), "buildx", "scout")
+	assert.Check(t, is.Error(err, `plugin "scout" was not installed with "docker plugin-cli install"`))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "buildx\n"))
+	records, err := loadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, records["buildx"].Pinned)
+	assert.Check(t, !records["compose"].Pinned)
+
+	assert.NilError(t, runCommand(newUnpinCommand(cli), "buildx"))
+	records, err = loadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, !records["buildx"].Pinned)
+}
@@ -0,0 +1,74 @@
+package plugincli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm PLUGIN [PLUGIN...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more installed CLI plugins",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(dockerCli, args)
+		},
+		ValidArgsFunction: completeNames,
+	}
+}
+
+func runRemove(dockerCli command.Cli, names []string) error {
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	var removed []string
+	for _, name := range names {
+		if _, ok := records[name]; !ok {
+			errs = append(errs, fmt.Sprintf("plugin %q was not installed with \"docker plugin-cli install\"", name))
+			continue
+		}
+		if err := os.Remove(pluginPath(dir, name)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err.Error())
+			continue
+		}
+		delete(records, name)
+		removed = append(removed, name)
+	}
+	if len(removed) > 0 {
+		if err := saveRecords(dir, records); err != nil {
+			return err
+		}
+		granted, err := manager.GrantedPermissions()
+		if err != nil {
+			return err
+		}
+		for _, name := range removed {
+			delete(granted, name)
+		}
+		if err := manager.SaveGrantedPermissions(granted); err != nil {
+			return err
+		}
+		for _, name := range removed {
+			_, _ = fmt.Fprintln(dockerCli.Out(), name)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package plugincli
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRemove(t *testing.T) {
+	cli := newTestCli(t, nil)
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	assert.NilError(t, saveRecords(dir, map[string]record{
+		"buildx":  {Name: "buildx", Source: "docker/buildx-bin", Tag: "0.17.1"},
+		"compose": {Name: "compose", Source: "github.com/docker/compose", Tag: "v2.29.7"},
+	}))
+	for _, name := range []string{"buildx", "compose", "scout"} {
+		assert.NilError(t, os.WriteFile(pluginPath(dir, name), []byte("plugin"), 0o755))
+	}
+
+	err = runCommand(newRemoveCommand(cli), "buildx", "scout")
+	assert.Check(t, is.Error(err, `plugin "scout" was not installed with "docker plugin-cli install"`))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "buildx\n"))
+
+	_, err = os.Stat(pluginPath(dir, "buildx"))
+	assert.Check(t, os.IsNotExist(err))
+	_, err = os.Stat(pluginPath(dir, "scout"))
+	assert.Check(t, err)
+	records, err := loadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(sortedRecords(records), []record{{Name: "compose", Source: "github.com/docker/compose", Tag: "v2.29.7"}}))
+}
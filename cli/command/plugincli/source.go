@@ -0,0 +1,158 @@
+package plugincli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/pkg/errors"
+)
+
+const githubPrefix = "github.com/"
+
+// exeSuffix is the suffix of the names of executables on this platform.
+var exeSuffix = func() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+	return ""
+}()
+
+// pluginNameRe is the format of the names of CLI plugins.
+var pluginNameRe = regexp.MustCompile("^[a-z][a-z0-9]*$")
+
+// source is where a plugin is installed from: an OCI artifact in a
+// registry, or the asset of a GitHub release.
+type source struct {
+	// repository is the familiar name of the OCI repository, or the
+	// "github.com/OWNER/REPO" GitHub repository.
+	repository string
+	// tag is the tag of the OCI artifact, or of the GitHub release. It's
+	// empty for the latest release of a GitHub repository.
+	tag    string
+	github bool
+}
+
+func (s source) String() string {
+	if s.tag == "" {
+		return s.repository
+	}
+	if s.github {
+		return s.repository + "@" + s.tag
+	}
+	return s.repository + ":" + s.tag
+}
+
+// resolved is the content that a source resolved to.
+type resolved struct {
+	// tag is the tag of the OCI artifact, or of the GitHub release.
+	tag string
+	// revision identifies the content, to skip upgrades if it's unchanged.
+	revision string
+	// fetch fetches, and verifies, the binary of the plugin.
+	fetch func(ctx context.Context) ([]byte, error)
+	// permissions are the permissions that the plugin requires, as declared
+	// in the manifest of the OCI artifact, or nil if they aren't declared.
+	permissions *manager.Permissions
+}
+
+// parseSource parses the source of a plugin: "github.com/OWNER/REPO[@TAG]"
+// for the releases of a GitHub repository, or the reference of an OCI
+// artifact, such as "docker/buildx-bin:0.17.1".
+func parseSource(s string) (source, error) {
+	s = strings.TrimPrefix(s, "https://")
+	if repo, ok := strings.CutPrefix(s, githubPrefix); ok {
+		repo, tag, _ := strings.Cut(repo, "@")
+		repo = strings.TrimSuffix(repo, "/")
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return source{}, errors.Errorf("invalid source %q: GitHub repositories must be in the github.com/OWNER/REPO[@TAG] format", s)
+		}
+		return source{repository: githubPrefix + repo, tag: tag, github: true}, nil
+	}
+	ref, err := reference.ParseNormalizedNamed(s)
+	if err != nil {
+		return source{}, errors.Wrapf(err, "invalid source %q", s)
+	}
+	if _, ok := ref.(reference.Digested); ok {
+		return source{}, errors.Errorf("invalid source %q: use a tag, instead of a digest", s)
+	}
+	ref = reference.TagNameOnly(ref)
+	return source{repository: reference.FamiliarName(ref), tag: ref.(reference.Tagged).Tag()}, nil
+}
+
+// defaultName returns the name of the plugin of the repository of a source:
+// the last element of its path without the "docker-" prefix, nor a "-bin",
+// "-cli", or "-plugin" suffix. It's "compose" for "github.com/docker/compose"
+// and "docker/compose-bin".
+func defaultName(s source) string {
+	name := strings.ToLower(path.Base(s.repository))
+	name = strings.TrimPrefix(name, "docker-")
+	for _, suffix := range []string{"-bin", "-cli", "-plugin"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	return name
+}
+
+// binaryNames returns the names that the binary of the plugin with the given
+// name may have in an archive, or an OCI artifact. Plugins that are installed
+// with another name than the default one of src have their default name.
+func binaryNames(src source, name string) []string {
+	names := []string{"docker-" + name + exeSuffix, name + exeSuffix}
+	if def := defaultName(src); def != name {
+		names = append(names, "docker-"+def+exeSuffix, def+exeSuffix)
+	}
+	return names
+}
+
+func isBinaryName(names []string, s string) bool {
+	for _, n := range names {
+		if s == n {
+			return true
+		}
+	}
+	return false
+}
+
+// isArchive returns whether b is a tar archive, or a gzip-compressed one.
+func isArchive(b []byte) bool {
+	return bytes.HasPrefix(b, []byte{0x1f, 0x8b}) || (len(b) > 262 && string(b[257:262]) == "ustar")
+}
+
+// extractBinary returns the file of a tar archive, which may be
+// gzip-compressed, that has one of the given names. It returns nil if the
+// archive doesn't have one.
+func extractBinary(b []byte, names []string) ([]byte, error) {
+	var r io.Reader = bytes.NewReader(b)
+	if bytes.HasPrefix(b, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if isBinaryName(names, path.Base(hdr.Name)) {
+			return io.ReadAll(tr)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package plugincli
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		source       string
+		expected     source
+		expectedName string
+	}{
+		{
+			source:       "docker/buildx-bin",
+			expected:     source{repository: "docker/buildx-bin", tag: "latest"},
+			expectedName: "buildx",
+		},
+		{
+			source:       "docker.io/docker/compose-bin:v2.29.7",
+			expected:     source{repository: "docker/compose-bin", tag: "v2.29.7"},
+			expectedName: "compose",
+		},
+		{
+			source:       "registry.example.com:5000/plugins/docker-hello:1.0",
+			expected:     source{repository: "registry.example.com:5000/plugins/docker-hello", tag: "1.0"},
+			expectedName: "hello",
+		},
+		{
+			source:       "github.com/docker/compose",
+			expected:     source{repository: "github.com/docker/compose", github: true},
+			expectedName: "compose",
+		},
+		{
+			source:       "https://github.com/docker/scout-cli@v1.14.0",
+			expected:     source{repository: "github.com/docker/scout-cli", tag: "v1.14.0", github: true},
+			expectedName: "scout",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.source, func(t *testing.T) {
+			src, err := parseSource(tc.source)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(src, tc.expected))
+			assert.Check(t, is.Equal(defaultName(src), tc.expectedName))
+		})
+	}
+}
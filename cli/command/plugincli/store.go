@@ -0,0 +1,98 @@
+package plugincli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli-plugins/manager"
+	"github.com/docker/cli/cli/command/image"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/pkg/errors"
+)
+
+// recordsFileName is the name of the file, in the directory of the CLI
+// plugins of the user, that records the plugins that were installed with
+// "docker plugin-cli install".
+const recordsFileName = "plugins.json"
+
+// record is where an installed plugin was installed from.
+type record struct {
+	Name string
+	// Source is the OCI repository, or the GitHub repository, that the
+	// plugin was installed from.
+	Source string
+	// Tag is the tag of the OCI artifact, or of the GitHub release.
+	Tag string
+	// Version is the version that the plugin reports in its metadata.
+	Version string `json:",omitempty"`
+	// Revision identifies the content the plugin was installed from: the
+	// digest of the manifest of OCI artifacts, or the URL of the asset of
+	// GitHub releases. Upgrades are skipped if it doesn't change.
+	Revision string
+	// Digest is the digest of the binary of the plugin.
+	Digest string
+	// Verify is what the signature of the OCI artifact is verified with, to
+	// also verify it on upgrades.
+	Verify *image.SignatureOptions `json:",omitempty"`
+	// Pinned plugins are not upgraded.
+	Pinned      bool `json:",omitempty"`
+	InstalledAt time.Time
+}
+
+// pluginsDir returns the directory of the CLI plugins of the user, which is
+// where plugins are installed.
+func pluginsDir() (string, error) {
+	return config.Path("cli-plugins")
+}
+
+// pluginPath returns the path of the binary of the plugin with the given name
+// in dir.
+func pluginPath(dir, name string) string {
+	return filepath.Join(dir, manager.NamePrefix+name+exeSuffix)
+}
+
+// loadRecords loads the records of the plugins installed in dir.
+func loadRecords(dir string) (map[string]record, error) {
+	records := map[string]record{}
+	b, err := os.ReadFile(filepath.Join(dir, recordsFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return records, nil
+		}
+		return nil, err
+	}
+	var list []record
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", filepath.Join(dir, recordsFileName))
+	}
+	for _, r := range list {
+		records[r.Name] = r
+	}
+	return records, nil
+}
+
+// saveRecords saves the records of the plugins installed in dir.
+func saveRecords(dir string, records map[string]record) error {
+	list := sortedRecords(records)
+	b, err := json.MarshalIndent(list, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return ioutils.AtomicWriteFile(filepath.Join(dir, recordsFileName), b, 0o644)
+}
+
+func sortedRecords(records map[string]record) []record {
+	list := make([]record, 0, len(records))
+	for _, r := range records {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
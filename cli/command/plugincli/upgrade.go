@@ -0,0 +1,154 @@
+package plugincli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// versionTagRe is the format of the tags of OCI artifacts that are versions,
+// such as "0.17.1", or "v2.29.7". Upgrades of plugins installed from such a
+// tag install the highest version; plugins installed from other tags, such
+// as "latest", are upgraded to what that tag refers to.
+var versionTagRe = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+)*$`)
+
+type upgradeOptions struct {
+	names      []string
+	grantPerms bool
+}
+
+func newUpgradeCommand(dockerCli command.Cli) *cobra.Command {
+	var opts upgradeOptions
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [PLUGIN...]",
+		Short: "Upgrade installed CLI plugins",
+		Long: `Upgrade the given CLI plugins, or all the plugins installed with
+"docker plugin-cli install" that are not pinned.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runUpgrade(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completeNames,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.grantPerms, "grant-all-permissions", false, "Grant all permissions that the upgraded plugins require")
+	return cmd
+}
+
+func runUpgrade(ctx context.Context, dockerCli command.Cli, opts upgradeOptions) error {
+	names := opts.names
+	dir, err := pluginsDir()
+	if err != nil {
+		return err
+	}
+	records, err := loadRecords(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	var list []record
+	for _, name := range names {
+		rec, ok := records[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Sprintf("plugin %q was not installed with \"docker plugin-cli install\"", name))
+		case rec.Pinned:
+			errs = append(errs, fmt.Sprintf("plugin %q is pinned to %s: use \"docker plugin-cli unpin\" to upgrade it", name, displayVersion(rec)))
+		default:
+			list = append(list, rec)
+		}
+	}
+	if len(names) == 0 {
+		for _, rec := range sortedRecords(records) {
+			if rec.Pinned {
+				_, _ = fmt.Fprintf(dockerCli.Out(), "Skipping %s: pinned to %s\n", rec.Name, displayVersion(rec))
+				continue
+			}
+			list = append(list, rec)
+		}
+	}
+
+	for _, rec := range list {
+		upgraded, err := upgradePlugin(ctx, dockerCli, dir, rec, opts.grantPerms)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to upgrade %s: %s", rec.Name, err))
+			continue
+		}
+		if upgraded.Revision == rec.Revision && upgraded.Digest == rec.Digest {
+			_, _ = fmt.Fprintf(dockerCli.Out(), "%s is up to date (%s)\n", rec.Name, displayVersion(rec))
+			continue
+		}
+		records[rec.Name] = upgraded
+		if err := saveRecords(dir, records); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(dockerCli.Out(), "Upgraded %s from %s to %s\n", rec.Name, displayVersion(rec), displayVersion(upgraded))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// upgradePlugin installs the latest version of the plugin of rec, unless it's
+// the installed one. It returns the record of the installed plugin.
+func upgradePlugin(ctx context.Context, dockerCli command.Cli, dir string, rec record, grantAll bool) (record, error) {
+	src := source{repository: rec.Source, github: strings.HasPrefix(rec.Source, githubPrefix)}
+	if !src.github {
+		tag, err := latestTag(ctx, dockerCli, rec)
+		if err != nil {
+			return record{}, err
+		}
+		src.tag = tag
+	}
+	r, err := resolveSource(ctx, dockerCli, src, rec.Name, "", rec.Verify)
+	if err != nil {
+		return record{}, err
+	}
+	if r.revision == rec.Revision {
+		if _, err := os.Stat(pluginPath(dir, rec.Name)); err == nil {
+			return rec, nil
+		}
+	}
+	upgraded, err := installPlugin(ctx, dockerCli, dir, rec.Name, src, r, "", grantAll)
+	if err != nil {
+		return record{}, err
+	}
+	upgraded.Verify = rec.Verify
+	return upgraded, nil
+}
+
+// latestTag returns the tag of the OCI artifact to upgrade the plugin of rec
+// to: the highest version, if it was installed from a version, or the tag it
+// was installed from otherwise.
+func latestTag(ctx context.Context, dockerCli command.Cli, rec record) (string, error) {
+	if !versionTagRe.MatchString(rec.Tag) {
+		return rec.Tag, nil
+	}
+	named, err := reference.ParseNormalizedNamed(rec.Source)
+	if err != nil {
+		return "", err
+	}
+	tags, err := dockerCli.RegistryClient(false).GetTags(ctx, named)
+	if err != nil {
+		return "", err
+	}
+	latest := rec.Tag
+	for _, tag := range tags {
+		if versionTagRe.MatchString(tag) && versions.LessThan(strings.TrimPrefix(latest, "v"), strings.TrimPrefix(tag, "v")) {
+			latest = tag
+		}
+	}
+	return latest, nil
+}
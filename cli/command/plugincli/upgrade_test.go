@@ -0,0 +1,86 @@
+package plugincli
+
+import (
+	"os"
+	"testing"
+
+	"github.com/docker/cli/internal/test/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// addBuildx adds the docker/buildx-bin image with the given tag, with a
+// plugin that reports the given version.
+func addBuildx(t *testing.T, r *registry.Registry, tag, version string) {
+	t.Helper()
+	addImage(t, r, "docker.io/docker/buildx-bin", tag,
+		r.AddBlob(t, ocispec.MediaTypeImageLayerGzip, tarGz(t, map[string][]byte{"buildx": testPlugin(t, version)})),
+	)
+}
+
+func TestUpgrade(t *testing.T) {
+	r := registry.New()
+	addBuildx(t, r, "0.16.0", "v0.16.0")
+	addBuildx(t, r, "latest", "v0.16.0")
+	gh := newTestGitHub(t)
+	bin := testPlugin(t, "v2.29.6")
+	gh.addRelease("docker/compose", "v2.29.6", map[string][]byte{assetName(): bin, "checksums.txt": []byte(sha256sum(bin) + "  " + assetName() + "\n")})
+	cli := newTestCli(t, r)
+	assert.NilError(t, runCommand(newInstallCommand(cli), "docker/buildx-bin:0.16.0"))
+	assert.NilError(t, runCommand(newInstallCommand(cli), "--name", "latest", "docker/buildx-bin"))
+	assert.NilError(t, runCommand(newInstallCommand(cli), "github.com/docker/compose"))
+	cli.OutBuffer().Reset()
+
+	assert.NilError(t, runCommand(newUpgradeCommand(cli)))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `buildx is up to date (v0.16.0)
+compose is up to date (v2.29.6)
+latest is up to date (v0.16.0)
+`))
+
+	// Plugins installed from a version are upgraded to the highest one.
+	// Others are upgraded to what their tag refers to.
+	addBuildx(t, r, "0.17.1", "v0.17.1")
+	addBuildx(t, r, "0.17.0-rc1", "v0.17.0-rc1")
+	addBuildx(t, r, "latest", "v0.17.1")
+	bin = testPlugin(t, "v2.29.7")
+	gh.addRelease("docker/compose", "v2.29.7", map[string][]byte{assetName(): bin, "checksums.txt": []byte(sha256sum(bin) + "  " + assetName() + "\n")})
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runCommand(newUpgradeCommand(cli)))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `Upgraded buildx from v0.16.0 to v0.17.1
+Upgraded compose from v2.29.6 to v2.29.7
+Upgraded latest from v0.16.0 to v0.17.1
+`))
+
+	dir, err := pluginsDir()
+	assert.NilError(t, err)
+	records, err := loadRecords(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(records["buildx"].Tag, "0.17.1"))
+	assert.Check(t, is.Equal(records["latest"].Tag, "latest"))
+	assert.Check(t, is.Equal(records["compose"].Tag, "v2.29.7"))
+	installed, err := os.ReadFile(pluginPath(dir, "compose"))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(installed, bin))
+}
+
+func TestUpgradePinned(t *testing.T) {
+	r := registry.New()
+	addBuildx(t, r, "0.16.0", "v0.16.0")
+	cli := newTestCli(t, r)
+	assert.NilError(t, runCommand(newInstallCommand(cli), "--pin", "docker/buildx-bin:0.16.0"))
+	addBuildx(t, r, "0.17.1", "v0.17.1")
+	cli.OutBuffer().Reset()
+
+	assert.NilError(t, runCommand(newUpgradeCommand(cli)))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Skipping buildx: pinned to v0.16.0\n"))
+
+	err := runCommand(newUpgradeCommand(cli), "buildx", "compose")
+	assert.Check(t, is.Error(err, `plugin "buildx" is pinned to v0.16.0: use "docker plugin-cli unpin" to upgrade it
+plugin "compose" was not installed with "docker plugin-cli install"`))
+
+	assert.NilError(t, runCommand(newUnpinCommand(cli), "buildx"))
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runCommand(newUpgradeCommand(cli), "buildx"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "Upgraded buildx from v0.16.0 to v0.17.1\n"))
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// Types of progress output of the --progress option.
+const (
+	// ProgressAuto displays the progress with progress bars if the output
+	// is a terminal.
+	ProgressAuto = "auto"
+	// ProgressPlain displays the progress as plain text, without progress
+	// bars or cursor movements.
+	ProgressPlain = "plain"
+	// ProgressJSON writes a JSON progress event per line, to be consumed by
+	// other programs.
+	ProgressJSON = "json"
+)
+
+// ProgressEvent is a progress event written by the "json" type of progress
+// output.
+type ProgressEvent struct {
+	// ID is the ID of the item in progress, such as the ID of a layer.
+	ID string `json:"id,omitempty"`
+	// Status is the status of the item, such as "Downloading".
+	Status string `json:"status,omitempty"`
+	// Current is the progress of the item, for example in bytes.
+	Current int64 `json:"current,omitempty"`
+	// Total is the total of the progress of the item, if known.
+	Total int64 `json:"total,omitempty"`
+	// Stream is output of the operation, such as the output of a build.
+	Stream string `json:"stream,omitempty"`
+	// Error is the error that ended the operation.
+	Error string `json:"error,omitempty"`
+}
+
+type progressValue struct {
+	progress *string
+}
+
+func (p progressValue) String() string {
+	return *p.progress
+}
+
+func (p progressValue) Set(value string) error {
+	switch value {
+	case ProgressAuto, ProgressPlain, ProgressJSON:
+		*p.progress = value
+		return nil
+	default:
+		return errors.Errorf("invalid progress type %q: must be %q, %q, or %q", value, ProgressAuto, ProgressPlain, ProgressJSON)
+	}
+}
+
+func (progressValue) Type() string {
+	return "string"
+}
+
+// AddProgressFlag adds the --progress option to flags, which sets the type
+// of progress output.
+func AddProgressFlag(flags *pflag.FlagSet, progress *string) {
+	*progress = ProgressAuto
+	flags.Var(progressValue{progress: progress}, "progress", `Set the type of progress output ("auto", "plain", "json")`)
+}
+
+// DisplayProgress displays the stream of JSON messages of the progress of an
+// operation on out, with the given type of progress output. auxCallback, if
+// set, is called with the auxiliary messages. An error message of the stream
+// is returned as error.
+func DisplayProgress(in io.Reader, out *streams.Out, progress string, auxCallback func(jsonmessage.JSONMessage)) error {
+	switch progress {
+	case ProgressPlain:
+		return jsonmessage.DisplayJSONMessagesStream(in, out, out.FD(), false, auxCallback)
+	case ProgressJSON:
+		return displayProgressEvents(in, out, auxCallback)
+	default:
+		return jsonmessage.DisplayJSONMessagesToStream(in, out, auxCallback)
+	}
+}
+
+func displayProgressEvents(in io.Reader, out io.Writer, auxCallback func(jsonmessage.JSONMessage)) error {
+	dec := json.NewDecoder(in)
+	enc := json.NewEncoder(out)
+	for {
+		var jm jsonmessage.JSONMessage
+		if err := dec.Decode(&jm); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if jm.Aux != nil {
+			if auxCallback != nil {
+				auxCallback(jm)
+			}
+			continue
+		}
+		event := ProgressEvent{ID: jm.ID, Status: jm.Status, Stream: jm.Stream}
+		if jm.Progress != nil {
+			event.Current, event.Total = jm.Progress.Current, jm.Progress.Total
+		}
+		if jm.Error != nil {
+			event.Error = jm.Error.Message
+		}
+		if event == (ProgressEvent{}) {
+			continue
+		}
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+		if jm.Error != nil {
+			return jm.Error
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/spf13/pflag"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDisplayProgressJSON(t *testing.T) {
+	in := strings.NewReader(`{"status":"Pulling from library/busybox","id":"latest"}
+{"status":"Downloading","progressDetail":{"current":512,"total":1024},"progress":"[=====>     ]","id":"abc123"}
+{"aux":{"Tag":"latest"}}
+{}
+{"status":"Download complete","progressDetail":{},"id":"abc123"}
+`)
+	var aux []jsonmessage.JSONMessage
+	buf := new(bytes.Buffer)
+	err := DisplayProgress(in, streams.NewOut(buf), ProgressJSON, func(jm jsonmessage.JSONMessage) {
+		aux = append(aux, jm)
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(aux, 1))
+	assert.Check(t, is.Equal(buf.String(), `{"id":"latest","status":"Pulling from library/busybox"}
+{"id":"abc123","status":"Downloading","current":512,"total":1024}
+{"id":"abc123","status":"Download complete"}
+`))
+}
+
+func TestDisplayProgressJSONError(t *testing.T) {
+	in := strings.NewReader(`{"status":"Downloading","id":"abc123"}
+{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}
+{"status":"Ignored"}
+`)
+	buf := new(bytes.Buffer)
+	err := DisplayProgress(in, streams.NewOut(buf), ProgressJSON, nil)
+	assert.Check(t, is.Error(err, "manifest unknown"))
+	assert.Check(t, is.Equal(buf.String(), `{"id":"abc123","status":"Downloading"}
+{"error":"manifest unknown"}
+`))
+}
+
+func TestAddProgressFlag(t *testing.T) {
+	var progress string
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	AddProgressFlag(flags, &progress)
+	assert.Check(t, is.Equal(progress, ProgressAuto))
+
+	assert.NilError(t, flags.Parse([]string{"--progress=json"}))
+	assert.Check(t, is.Equal(progress, ProgressJSON))
+
+	err := flags.Parse([]string{"--progress=tty"})
+	assert.Check(t, is.ErrorContains(err, `invalid progress type "tty": must be "auto", "plain", or "json"`))
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// fileStoreName is the name of the credential store of the configuration
+// file, which is used when no credential helper is configured.
+const fileStoreName = "file"
+
+// NewAuthCommand returns a cobra command for `auth` subcommands
+func NewAuthCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage registry credentials",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newAuthListCommand(dockerCli),
+	)
+	return cmd
+}
+
+type authListOptions struct {
+	format string
+}
+
+// authEntry is the credentials of a registry, and the credential store or
+// helper that they're stored in.
+type authEntry struct {
+	registry string
+	store    string
+	username string
+	token    bool
+	err      error
+}
+
+func newAuthListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts authListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the registries that have credentials, and the credential stores they use",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAuthList(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runAuthList(_ context.Context, dockerCli command.Cli, opts authListOptions) error {
+	cfg := dockerCli.ConfigFile()
+
+	registries := map[string]bool{}
+	for r := range cfg.AuthConfigs {
+		registries[r] = true
+	}
+	for r := range cfg.CredentialHelpers {
+		registries[r] = true
+	}
+	if cfg.CredentialsStore != "" {
+		// The credential store may have credentials that the configuration
+		// file doesn't list, such as the ones of other tools.
+		if all, err := cfg.GetCredentialsStore("").GetAll(); err == nil {
+			for r := range all {
+				registries[r] = true
+			}
+		}
+	}
+
+	entries := make([]authEntry, 0, len(registries))
+	for r := range registries {
+		e := authEntry{registry: r, store: fileStoreName}
+		if h, ok := cfg.CredentialHelpers[r]; ok {
+			e.store = "docker-credential-" + h
+		} else if cfg.CredentialsStore != "" {
+			e.store = "docker-credential-" + cfg.CredentialsStore
+		}
+		ac, err := cfg.GetCredentialsStore(r).Get(r)
+		e.username, e.token, e.err = ac.Username, ac.IdentityToken != "", err
+		if err == nil && ac.Username == "" && ac.Password == "" && ac.IdentityToken == "" && ac.Auth == "" && ac.RegistryToken == "" {
+			e.err = errNoCredentials
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].registry < entries[j].registry })
+
+	format := opts.format
+	if format == "" {
+		format = formatter.TableFormatKey
+	}
+	return authWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newAuthFormat(format),
+	}, entries)
+}
@@ -0,0 +1,88 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestAuthList(t *testing.T) {
+	tmpFile := fs.NewFile(t, "test-auth-list")
+	defer tmpFile.Remove()
+	cli := test.NewFakeCli(&fakeClient{})
+	cfg := cli.ConfigFile()
+	cfg.Filename = tmpFile.Path()
+	for _, ac := range []configtypes.AuthConfig{
+		{ServerAddress: "reg1", Username: "u1", Password: "p1"},
+		{ServerAddress: "reg2", IdentityToken: "token"},
+	} {
+		assert.NilError(t, cfg.GetCredentialsStore(ac.ServerAddress).Store(ac))
+	}
+	cfg.CredentialHelpers = map[string]string{"reg3": "missing-helper"}
+
+	assert.NilError(t, runAuthList(context.Background(), cli, authListOptions{format: "{{.Registry}}|{{.Store}}|{{.Username}}|{{.Status}}"}))
+	lines := []string{
+		"reg1|file|u1|ok",
+		"reg2|file|<token>|ok",
+		"reg3|docker-credential-missing-helper||error: ",
+	}
+	out := cli.OutBuffer().String()
+	for _, l := range lines {
+		assert.Check(t, is.Contains(out, l))
+	}
+}
+
+func TestRunVerifyLogin(t *testing.T) {
+	testCases := []struct {
+		doc           string
+		stored        *configtypes.AuthConfig
+		opts          loginOptions
+		expectedError string
+	}{
+		{
+			doc:    "valid credentials",
+			stored: &configtypes.AuthConfig{ServerAddress: "reg1", Username: "u1", Password: "p1"},
+			opts:   loginOptions{serverAddress: "reg1", verify: true},
+		},
+		{
+			doc:           "rejected credentials",
+			stored:        &configtypes.AuthConfig{ServerAddress: "reg1", Username: "u1", Password: expiredPassword},
+			opts:          loginOptions{serverAddress: "reg1", verify: true},
+			expectedError: "failed to verify the credentials of reg1: Invalid Username or Password",
+		},
+		{
+			doc:           "no credentials",
+			opts:          loginOptions{serverAddress: "reg1", verify: true},
+			expectedError: `no credentials are stored for reg1 in file; use "docker login reg1" to log in`,
+		},
+		{
+			doc:           "with username",
+			opts:          loginOptions{serverAddress: "reg1", verify: true, user: "u1"},
+			expectedError: "--verify can't be used with --username, --password, --password-stdin, or --device-code",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			tmpFile := fs.NewFile(t, "test-verify-login")
+			defer tmpFile.Remove()
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.ConfigFile().Filename = tmpFile.Path()
+			if tc.stored != nil {
+				assert.NilError(t, cli.ConfigFile().GetCredentialsStore(tc.stored.ServerAddress).Store(*tc.stored))
+			}
+			err := runLogin(context.Background(), cli, tc.opts)
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), "Credentials are valid\n"))
+			assert.Check(t, is.Contains(cli.ErrBuffer().String(), "stored in file"))
+		})
+	}
+}
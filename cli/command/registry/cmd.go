@@ -0,0 +1,22 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+)
+
+// NewRegistryCommand returns a cobra command for `registry` subcommands
+func NewRegistryCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry COMMAND",
+		Short: "Manage registry settings",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newConfigCommand(dockerCli),
+		newMirrorCommand(dockerCli),
+	)
+	return cmd
+}
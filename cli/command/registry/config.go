@@ -0,0 +1,234 @@
+package registry
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/registry"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the settings of registries",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newConfigListCommand(dockerCli),
+		newConfigSetCommand(dockerCli),
+		newConfigRemoveCommand(dockerCli),
+	)
+	return cmd
+}
+
+// registryConfigName returns the name of the registry of the settings in
+// the configuration file, which is its domain, such as "docker.io".
+func registryConfigName(name string) (string, error) {
+	name, err := registry.ValidateIndexName(registry.ConvertToHostname(name))
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", errors.New("invalid registry: name is empty")
+	}
+	return name, nil
+}
+
+type configListOptions struct {
+	format string
+}
+
+func newConfigListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts configListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS]",
+		Aliases: []string{"list"},
+		Short:   "List the settings of registries",
+		Args:    cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigList(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runConfigList(_ context.Context, dockerCli command.Cli, opts configListOptions) error {
+	var entries []registryConfigEntry
+	for name, rc := range dockerCli.ConfigFile().Registries {
+		entries = append(entries, registryConfigEntry{registry: name, config: rc})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].registry < entries[j].registry })
+
+	format := opts.format
+	if format == "" {
+		format = formatter.TableFormatKey
+	}
+	return registryConfigWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newRegistryConfigFormat(format),
+	}, entries)
+}
+
+type configSetOptions struct {
+	registry string
+	mirrors  []string
+	insecure bool
+	caFile   string
+
+	// setMirrors, setInsecure, and setCAFile are whether the flags were
+	// set; setting a flag to its empty value unsets the setting.
+	setMirrors  bool
+	setInsecure bool
+	setCAFile   bool
+}
+
+func newConfigSetCommand(dockerCli command.Cli) *cobra.Command {
+	var opts configSetOptions
+
+	cmd := &cobra.Command{
+		Use:   "set [OPTIONS] REGISTRY",
+		Short: "Change the settings of a registry",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.registry = args[0]
+			flags := cmd.Flags()
+			opts.setMirrors = flags.Changed("mirror")
+			opts.setInsecure = flags.Changed("insecure")
+			opts.setCAFile = flags.Changed("ca-file")
+			return runConfigSet(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.mirrors, "mirror", nil, "Mirror to try before the registry when pulling, replacing the mirrors of the registry (set to an empty value to remove them)")
+	flags.BoolVar(&opts.insecure, "insecure", false, "Allow connecting to the registry over plain HTTP, or without verifying its certificate (use --insecure=false to unset)")
+	flags.StringVar(&opts.caFile, "ca-file", "", "Trust the certificate authorities in this PEM file for the registry (set to an empty value to unset)")
+	return cmd
+}
+
+func runConfigSet(_ context.Context, dockerCli command.Cli, opts configSetOptions) error {
+	if !opts.setMirrors && !opts.setInsecure && !opts.setCAFile {
+		return errors.New("no settings to change: use --mirror, --insecure, or --ca-file")
+	}
+	name, err := registryConfigName(opts.registry)
+	if err != nil {
+		return err
+	}
+
+	cfg := dockerCli.ConfigFile()
+	rc := cfg.Registries[name]
+	if opts.setMirrors {
+		rc.Mirrors = nil
+		for _, m := range opts.mirrors {
+			if m == "" {
+				continue
+			}
+			mirror, err := registry.ValidateMirror(m)
+			if err != nil {
+				return err
+			}
+			rc.Mirrors = appendUnique(rc.Mirrors, mirror)
+		}
+	}
+	if opts.setInsecure {
+		rc.Insecure = opts.insecure
+	}
+	if opts.setCAFile {
+		rc.CAFile = ""
+		if opts.caFile != "" {
+			if rc.CAFile, err = validateCAFile(opts.caFile); err != nil {
+				return err
+			}
+		}
+	}
+
+	if cfg.Registries == nil {
+		cfg.Registries = make(map[string]configfile.RegistryConfig)
+	}
+	if len(rc.Mirrors) == 0 && !rc.Insecure && rc.CAFile == "" {
+		delete(cfg.Registries, name)
+	} else {
+		cfg.Registries[name] = rc
+	}
+	if err := cfg.Save(); err != nil {
+		return errors.Wrap(err, "failed to save the settings of the registry in the configuration file")
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), name)
+	return nil
+}
+
+// validateCAFile checks that the file at path has PEM-encoded certificates,
+// and returns its absolute path, as the CA file is used from any directory.
+func validateCAFile(path string) (string, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid CA file")
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+		return "", errors.Errorf("invalid CA file %s: no PEM-encoded certificates", path)
+	}
+	return filepath.Abs(path)
+}
+
+func newConfigRemoveCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rm REGISTRY [REGISTRY...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove the settings of one or more registries",
+		Args:    cli.RequiresMinArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigRemove(cmd.Context(), dockerCli, args)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			var names []string
+			for name := range dockerCli.ConfigFile().Registries {
+				names = append(names, name)
+			}
+			return names, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+	return cmd
+}
+
+func runConfigRemove(_ context.Context, dockerCli command.Cli, registries []string) error {
+	cfg := dockerCli.ConfigFile()
+	var names []string
+	for _, r := range registries {
+		name, err := registryConfigName(r)
+		if err != nil {
+			return err
+		}
+		if _, ok := cfg.Registries[name]; !ok {
+			return errors.Errorf("no settings are stored for registry %s", name)
+		}
+		names = append(names, name)
+	}
+	for _, name := range names {
+		delete(cfg.Registries, name)
+	}
+	if err := cfg.Save(); err != nil {
+		return errors.Wrap(err, "failed to remove the settings of the registry from the configuration file")
+	}
+	for _, name := range names {
+		_, _ = fmt.Fprintln(dockerCli.Out(), name)
+	}
+	return nil
+}
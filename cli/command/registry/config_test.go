@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestRegistryConfigSet(t *testing.T) {
+	tmpFile := fs.NewFile(t, "test-registry-config")
+	defer tmpFile.Remove()
+	cli := test.NewFakeCli(&fakeClient{})
+	cfg := cli.ConfigFile()
+	cfg.Filename = tmpFile.Path()
+	caFile, err := filepath.Abs(filepath.Join("..", "testdata", "ca.pem"))
+	assert.NilError(t, err)
+
+	cmd := newConfigSetCommand(cli)
+	cmd.SetArgs([]string{"--mirror", "https://mirror1.example.com", "--mirror", "http://mirror2.example.com:5000", "--insecure", "--ca-file", caFile, "https://registry.example.com/v2/"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(cfg.Registries, map[string]configfile.RegistryConfig{
+		"registry.example.com": {
+			Mirrors:  []string{"https://mirror1.example.com/", "http://mirror2.example.com:5000/"},
+			Insecure: true,
+			CAFile:   caFile,
+		},
+	}))
+
+	// Only the settings of the options that are set are changed.
+	cmd = newConfigSetCommand(cli)
+	cmd.SetArgs([]string{"--insecure=false", "registry.example.com"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(cfg.Registries, map[string]configfile.RegistryConfig{
+		"registry.example.com": {
+			Mirrors: []string{"https://mirror1.example.com/", "http://mirror2.example.com:5000/"},
+			CAFile:  caFile,
+		},
+	}))
+
+	// Registries without settings are removed.
+	cmd = newConfigSetCommand(cli)
+	cmd.SetArgs([]string{"--mirror=", "--ca-file=", "registry.example.com"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Len(cfg.Registries, 0))
+}
+
+func TestRegistryConfigSetErrors(t *testing.T) {
+	testCases := []struct {
+		args          []string
+		expectedError string
+	}{
+		{
+			args:          []string{"registry.example.com"},
+			expectedError: "no settings to change",
+		},
+		{
+			args:          []string{"--mirror", "ftp://mirror.example.com", "registry.example.com"},
+			expectedError: "invalid mirror",
+		},
+		{
+			args:          []string{"--ca-file", "no-such-file.pem", "registry.example.com"},
+			expectedError: "invalid CA file",
+		},
+		{
+			args:          []string{"--ca-file", "config.go", "registry.example.com"},
+			expectedError: "no PEM-encoded certificates",
+		},
+	}
+	for _, tc := range testCases {
+		cli := test.NewFakeCli(&fakeClient{})
+		cmd := newConfigSetCommand(cli)
+		cmd.SetArgs(tc.args)
+		assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+	}
+}
+
+func TestRegistryConfigListAndRemove(t *testing.T) {
+	tmpFile := fs.NewFile(t, "test-registry-config")
+	defer tmpFile.Remove()
+	cli := test.NewFakeCli(&fakeClient{})
+	cfg := cli.ConfigFile()
+	cfg.Filename = tmpFile.Path()
+	cfg.Registries = map[string]configfile.RegistryConfig{
+		"docker.io":            {Mirrors: []string{"https://mirror1.example.com/", "https://mirror2.example.com/"}},
+		"registry.example.com": {Insecure: true, CAFile: "/etc/ca.pem"},
+	}
+
+	assert.NilError(t, runConfigList(context.Background(), cli, configListOptions{format: "{{.Registry}}|{{.Mirrors}}|{{.Insecure}}|{{.CAFile}}"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `docker.io|https://mirror1.example.com/, https://mirror2.example.com/|false|
+registry.example.com||true|/etc/ca.pem
+`))
+
+	assert.ErrorContains(t, runConfigRemove(context.Background(), cli, []string{"index.docker.io", "other.example.com"}), "no settings are stored for registry other.example.com")
+	assert.Check(t, is.Len(cfg.Registries, 2))
+
+	cli.OutBuffer().Reset()
+	assert.NilError(t, runConfigRemove(context.Background(), cli, []string{"index.docker.io"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "docker.io\n"))
+	assert.Check(t, is.Len(cfg.Registries, 1))
+}
@@ -0,0 +1,283 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	configtypes "github.com/docker/cli/cli/config/types"
+	"github.com/docker/distribution/registry/client/auth/challenge"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/registry"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultDeviceClientID is the OAuth client ID of the CLI, which is also
+	// the one that the daemon uses to refresh access tokens.
+	defaultDeviceClientID = "docker"
+
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// maxDeviceResponseSize is the maximum size of the responses of the
+	// authorization server.
+	maxDeviceResponseSize = 1 << 20
+)
+
+var (
+	// deviceHTTPClient is the HTTP client of the requests of device-code
+	// logins.
+	deviceHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+	// defaultDevicePollInterval is the interval at which the token endpoint
+	// is polled, if the authorization server doesn't set one.
+	defaultDevicePollInterval = 5 * time.Second
+)
+
+// authorizationServer is the metadata of an OAuth 2.0 authorization server
+// (RFC 8414) that supports the device authorization grant (RFC 8628).
+type authorizationServer struct {
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+	ScopesSupported             []string `json:"scopes_supported"`
+}
+
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type deviceToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// runDeviceCodeLogin logs in to a registry with the OAuth 2.0 device
+// authorization grant of the authorization server of its token service, and
+// stores the refresh token that it returns as identity token. The daemon,
+// and the registry client of the CLI, exchange it for access tokens on pull
+// and push.
+func runDeviceCodeLogin(ctx context.Context, dockerCli command.Cli, opts loginOptions, serverAddress string) error {
+	if opts.user != "" || opts.password != "" || opts.passwordStdin {
+		return errors.New("--device-code can't be used with --username, --password, or --password-stdin")
+	}
+
+	server, err := discoverAuthorizationServer(ctx, serverAddress)
+	if err != nil {
+		return err
+	}
+	var scope string
+	for _, s := range server.ScopesSupported {
+		if s == "offline_access" {
+			// Some servers only return refresh tokens for this scope.
+			scope = s
+		}
+	}
+
+	var authz deviceAuthorization
+	form := url.Values{"client_id": {opts.clientID}}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	if err := postForm(ctx, server.DeviceAuthorizationEndpoint, form, &authz); err != nil {
+		return errors.Wrap(err, "failed to request a device code")
+	}
+	if authz.DeviceCode == "" || authz.UserCode == "" || authz.VerificationURI == "" {
+		return errors.New("failed to request a device code: invalid response from the authorization server")
+	}
+
+	if authz.VerificationURIComplete != "" {
+		fmt.Fprintf(dockerCli.Err(), "To log in to %s, open %s\nand confirm the code %s\n\n", serverAddress, authz.VerificationURIComplete, authz.UserCode)
+	} else {
+		fmt.Fprintf(dockerCli.Err(), "To log in to %s, open %s\nand enter the code %s\n\n", serverAddress, authz.VerificationURI, authz.UserCode)
+	}
+	fmt.Fprintln(dockerCli.Err(), "Waiting for authorization...")
+
+	token, err := pollDeviceToken(ctx, server.TokenEndpoint, opts.clientID, authz)
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		return errors.New("the authorization server didn't return a refresh token")
+	}
+
+	authConfig := registrytypes.AuthConfig{
+		ServerAddress: serverAddress,
+		IdentityToken: token.RefreshToken,
+	}
+	response, err := dockerCli.Client().RegistryLogin(ctx, authConfig)
+	if err != nil && client.IsErrConnectionFailed(err) {
+		response, err = loginClientSide(ctx, authConfig)
+	}
+	if err != nil {
+		return err
+	}
+	if response.IdentityToken != "" {
+		authConfig.IdentityToken = response.IdentityToken
+	}
+
+	creds := dockerCli.ConfigFile().GetCredentialsStore(serverAddress)
+	if err := creds.Store(configtypes.AuthConfig(authConfig)); err != nil {
+		return errors.Errorf("Error saving credentials: %v", err)
+	}
+	if _, isDefault := creds.(isFileStore); !isDefault {
+		if err := verifyStoredCredentials(dockerCli, creds, serverAddress, configtypes.AuthConfig(authConfig)); err != nil {
+			return err
+		}
+	}
+	if response.Status != "" {
+		fmt.Fprintln(dockerCli.Out(), response.Status)
+	}
+	return nil
+}
+
+// discoverAuthorizationServer returns the metadata of the authorization
+// server of the token service of a registry, which is the server of the
+// realm of the Bearer challenge of the registry.
+func discoverAuthorizationServer(ctx context.Context, serverAddress string) (authorizationServer, error) {
+	var server authorizationServer
+
+	base := "https://" + registry.ConvertToHostname(serverAddress)
+	if strings.HasPrefix(serverAddress, "http://") {
+		base = "http://" + registry.ConvertToHostname(serverAddress)
+	}
+	if base == "https://index.docker.io" || base == "https://docker.io" {
+		base = registry.DefaultV2Registry.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/v2/", nil)
+	if err != nil {
+		return server, err
+	}
+	resp, err := deviceHTTPClient.Do(req)
+	if err != nil {
+		return server, errors.Wrapf(err, "failed to contact registry %s", serverAddress)
+	}
+	_ = resp.Body.Close()
+
+	var realm *url.URL
+	for _, c := range challenge.ResponseChallenges(resp) {
+		if strings.EqualFold(c.Scheme, "bearer") && c.Parameters["realm"] != "" {
+			if realm, err = url.Parse(c.Parameters["realm"]); err != nil {
+				return server, errors.Wrapf(err, "invalid token realm of registry %s", serverAddress)
+			}
+			break
+		}
+	}
+	if realm == nil {
+		return server, errors.Errorf("registry %s doesn't use token authentication, which device-code logins require", serverAddress)
+	}
+
+	for _, p := range []string{"/.well-known/oauth-authorization-server", "/.well-known/openid-configuration"} {
+		metadata := url.URL{Scheme: realm.Scheme, Host: realm.Host, Path: p}
+		err := getJSON(ctx, metadata.String(), &server)
+		if err == nil && server.DeviceAuthorizationEndpoint != "" && server.TokenEndpoint != "" {
+			return server, nil
+		}
+	}
+	return server, errors.Errorf("the authorization server of registry %s (%s) doesn't advertise a device authorization endpoint", serverAddress, realm.Host)
+}
+
+// pollDeviceToken polls the token endpoint of the authorization server until
+// the user authorizes the device, denies it, or the device code expires.
+func pollDeviceToken(ctx context.Context, endpoint, clientID string, authz deviceAuthorization) (deviceToken, error) {
+	interval := time.Duration(authz.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+	expiresIn := time.Duration(authz.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 15 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, expiresIn)
+	defer cancel()
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {authz.DeviceCode},
+		"client_id":   {clientID},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return deviceToken{}, errors.New("the device code expired before it was authorized")
+			}
+			return deviceToken{}, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var token deviceToken
+		if err := postForm(ctx, endpoint, form, &token); err != nil && token.Error == "" {
+			return deviceToken{}, errors.Wrap(err, "failed to request a token")
+		}
+		switch token.Error {
+		case "":
+			return token, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return deviceToken{}, errors.New("the authorization was denied")
+		case "expired_token":
+			return deviceToken{}, errors.New("the device code expired before it was authorized")
+		default:
+			if token.Description != "" {
+				return deviceToken{}, errors.Errorf("failed to request a token: %s: %s", token.Error, token.Description)
+			}
+			return deviceToken{}, errors.Errorf("failed to request a token: %s", token.Error)
+		}
+	}
+}
+
+func getJSON(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	return doJSON(req, v)
+}
+
+// postForm posts a form, and decodes the JSON response to v, which is also
+// decoded for error responses, as OAuth errors are returned in the body.
+func postForm(ctx context.Context, u string, form url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	return doJSON(req, v)
+}
+
+func doJSON(req *http.Request, v any) error {
+	req.Header.Set("User-Agent", command.UserAgent())
+	resp, err := deviceHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxDeviceResponseSize))
+	if err != nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(b, v)
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s from %s", resp.Status, req.URL.Host)
+	}
+	return decodeErr
+}
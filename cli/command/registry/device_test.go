@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+// newDeviceServer returns a server that is both a registry, and the
+// authorization server of its token service, which authorizes the device
+// after pending polls.
+func newDeviceServer(t *testing.T, pending int) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	writeJSON := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, authorizationServer{
+			DeviceAuthorizationEndpoint: srv.URL + "/device",
+			TokenEndpoint:               srv.URL + "/token",
+			ScopesSupported:             []string{"offline_access"},
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		assert.Check(t, is.Equal(r.FormValue("client_id"), "docker"))
+		assert.Check(t, is.Equal(r.FormValue("scope"), "offline_access"))
+		writeJSON(w, http.StatusOK, deviceAuthorization{
+			DeviceCode:      "device-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: srv.URL + "/activate",
+			ExpiresIn:       60,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Check(t, is.Equal(r.FormValue("grant_type"), deviceCodeGrantType))
+		assert.Check(t, is.Equal(r.FormValue("device_code"), "device-code"))
+		if pending > 0 {
+			pending--
+			writeJSON(w, http.StatusBadRequest, deviceToken{Error: "authorization_pending"})
+			return
+		}
+		writeJSON(w, http.StatusOK, deviceToken{AccessToken: "access-token", RefreshToken: "refresh-token"})
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRunDeviceCodeLogin(t *testing.T) {
+	defer func(interval time.Duration) { defaultDevicePollInterval = interval }(defaultDevicePollInterval)
+	defaultDevicePollInterval = time.Millisecond
+
+	srv := newDeviceServer(t, 2)
+	tmpFile := fs.NewFile(t, "test-run-login")
+	defer tmpFile.Remove()
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.ConfigFile().Filename = tmpFile.Path()
+
+	err := runLogin(context.Background(), cli, loginOptions{serverAddress: srv.URL, deviceCode: true, clientID: defaultDeviceClientID})
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "enter the code ABCD-EFGH"))
+
+	stored, err := cli.ConfigFile().GetCredentialsStore(srv.URL).Get(srv.URL)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stored.IdentityToken, "refresh-token"))
+	assert.Check(t, is.Equal(stored.Password, ""))
+}
+
+func TestRunDeviceCodeLoginErrors(t *testing.T) {
+	defer func(interval time.Duration) { defaultDevicePollInterval = interval }(defaultDevicePollInterval)
+	defaultDevicePollInterval = time.Millisecond
+
+	noToken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer noToken.Close()
+
+	testCases := []struct {
+		doc           string
+		opts          loginOptions
+		expectedError string
+	}{
+		{
+			doc:           "with username",
+			opts:          loginOptions{serverAddress: newDeviceServer(t, 0).URL, user: "user"},
+			expectedError: "--device-code can't be used with --username, --password, or --password-stdin",
+		},
+		{
+			doc:           "no token authentication",
+			opts:          loginOptions{serverAddress: noToken.URL},
+			expectedError: "doesn't use token authentication",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			tc.opts.deviceCode = true
+			tc.opts.clientID = defaultDeviceClientID
+			assert.ErrorContains(t, runLogin(context.Background(), cli, tc.opts), tc.expectedError)
+		})
+	}
+}
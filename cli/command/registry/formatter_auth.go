@@ -0,0 +1,80 @@
+package registry
+
+import (
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultAuthTableFormat = "table {{.Registry}}\t{{.Store}}\t{{.Username}}\t{{.Status}}"
+
+	registryHeader   = "REGISTRY"
+	storeHeader      = "STORE"
+	usernameHeader   = "USERNAME"
+	authStatusHeader = "STATUS"
+)
+
+// errNoCredentials is the status of registries that the configuration file
+// lists, but whose credential store has no credentials for.
+var errNoCredentials = errors.New("no credentials")
+
+func newAuthFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultAuthTableFormat
+	}
+	return formatter.Format(source)
+}
+
+func authWrite(ctx formatter.Context, entries []authEntry) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, e := range entries {
+			if err := format(&authContext{e: e}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	authCtx := authContext{}
+	authCtx.Header = formatter.SubHeaderContext{
+		"Registry": registryHeader,
+		"Store":    storeHeader,
+		"Username": usernameHeader,
+		"Status":   authStatusHeader,
+	}
+	return ctx.Write(&authCtx, render)
+}
+
+type authContext struct {
+	formatter.HeaderContext
+	e authEntry
+}
+
+func (c *authContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *authContext) Registry() string {
+	return c.e.registry
+}
+
+func (c *authContext) Store() string {
+	return c.e.store
+}
+
+func (c *authContext) Username() string {
+	if c.e.token && c.e.username == "" {
+		return "<token>"
+	}
+	return c.e.username
+}
+
+func (c *authContext) Status() string {
+	if c.e.err != nil {
+		if errors.Is(c.e.err, errNoCredentials) {
+			return c.e.err.Error()
+		}
+		return "error: " + c.e.err.Error()
+	}
+	return "ok"
+}
@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+)
+
+const (
+	defaultRegistryConfigTableFormat = "table {{.Registry}}\t{{.Mirrors}}\t{{.Insecure}}\t{{.CAFile}}"
+
+	mirrorsHeader  = "MIRRORS"
+	insecureHeader = "INSECURE"
+	caFileHeader   = "CA FILE"
+)
+
+// registryConfigEntry is the settings of a registry in the configuration
+// file.
+type registryConfigEntry struct {
+	registry string
+	config   configfile.RegistryConfig
+}
+
+func newRegistryConfigFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultRegistryConfigTableFormat
+	}
+	return formatter.Format(source)
+}
+
+func registryConfigWrite(ctx formatter.Context, entries []registryConfigEntry) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, e := range entries {
+			if err := format(&registryConfigContext{e: e}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	configCtx := registryConfigContext{}
+	configCtx.Header = formatter.SubHeaderContext{
+		"Registry": registryHeader,
+		"Mirrors":  mirrorsHeader,
+		"Insecure": insecureHeader,
+		"CAFile":   caFileHeader,
+	}
+	return ctx.Write(&configCtx, render)
+}
+
+type registryConfigContext struct {
+	formatter.HeaderContext
+	e registryConfigEntry
+}
+
+func (c *registryConfigContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *registryConfigContext) Registry() string {
+	return c.e.registry
+}
+
+func (c *registryConfigContext) Mirrors() string {
+	return strings.Join(c.e.config.Mirrors, ", ")
+}
+
+func (c *registryConfigContext) Insecure() string {
+	return strconv.FormatBool(c.e.config.Insecure)
+}
+
+func (c *registryConfigContext) CAFile() string {
+	return c.e.config.CAFile
+}
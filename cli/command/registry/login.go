@@ -9,6 +9,7 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/config/credentials"
 	configtypes "github.com/docker/cli/cli/config/types"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
@@ -28,6 +29,9 @@ type loginOptions struct {
 	user          string
 	password      string
 	passwordStdin bool
+	deviceCode    bool
+	clientID      string
+	verify        bool
 }
 
 // NewLoginCommand creates a new `docker login` command
@@ -56,6 +60,9 @@ func NewLoginCommand(dockerCli command.Cli) *cobra.Command {
 	flags.StringVarP(&opts.user, "username", "u", "", "Username")
 	flags.StringVarP(&opts.password, "password", "p", "", "Password")
 	flags.BoolVar(&opts.passwordStdin, "password-stdin", false, "Take the password from stdin")
+	flags.BoolVar(&opts.deviceCode, "device-code", false, "Log in with the OAuth device authorization flow of the registry, in a web browser")
+	flags.StringVar(&opts.clientID, "client-id", defaultDeviceClientID, "OAuth client ID of device-code logins")
+	flags.BoolVar(&opts.verify, "verify", false, "Verify the stored credentials against the registry, without logging in")
 
 	return cmd
 }
@@ -102,9 +109,6 @@ func verifyloginOptions(dockerCli command.Cli, opts *loginOptions) error {
 
 func runLogin(ctx context.Context, dockerCli command.Cli, opts loginOptions) error { //nolint:gocyclo
 	clnt := dockerCli.Client()
-	if err := verifyloginOptions(dockerCli, &opts); err != nil {
-		return err
-	}
 	var (
 		serverAddress string
 		response      registrytypes.AuthenticateOKBody
@@ -114,6 +118,15 @@ func runLogin(ctx context.Context, dockerCli command.Cli, opts loginOptions) err
 	} else {
 		serverAddress = registry.IndexServer
 	}
+	if opts.verify {
+		return runVerifyLogin(ctx, dockerCli, opts, serverAddress)
+	}
+	if opts.deviceCode {
+		return runDeviceCodeLogin(ctx, dockerCli, opts, serverAddress)
+	}
+	if err := verifyloginOptions(dockerCli, &opts); err != nil {
+		return err
+	}
 
 	isDefaultRegistry := serverAddress == registry.IndexServer
 	authConfig, err := command.GetDefaultAuthConfig(dockerCli.ConfigFile(), opts.user == "" && opts.password == "", serverAddress, isDefaultRegistry)
@@ -155,6 +168,11 @@ func runLogin(ctx context.Context, dockerCli command.Cli, opts loginOptions) err
 	if err := creds.Store(configtypes.AuthConfig(authConfig)); err != nil {
 		return errors.Errorf("Error saving credentials: %v", err)
 	}
+	if !isDefault {
+		if err := verifyStoredCredentials(dockerCli, creds, serverAddress, configtypes.AuthConfig(authConfig)); err != nil {
+			return err
+		}
+	}
 
 	if response.Status != "" {
 		fmt.Fprintln(dockerCli.Out(), response.Status)
@@ -162,6 +180,93 @@ func runLogin(ctx context.Context, dockerCli command.Cli, opts loginOptions) err
 	return nil
 }
 
+// runVerifyLogin verifies the credentials that are stored for serverAddress
+// against the registry, and reports where they're stored, without storing
+// anything.
+func runVerifyLogin(ctx context.Context, dockerCli command.Cli, opts loginOptions, serverAddress string) error {
+	if opts.user != "" || opts.password != "" || opts.passwordStdin || opts.deviceCode {
+		return errors.New("--verify can't be used with --username, --password, --password-stdin, or --device-code")
+	}
+	cfg := dockerCli.ConfigFile()
+	key := serverAddress
+	if serverAddress != registry.IndexServer {
+		key = credentials.ConvertToHostname(serverAddress)
+	}
+
+	store := fileStoreName
+	if h, ok := cfg.CredentialHelpers[key]; ok {
+		store = "docker-credential-" + h
+	} else if cfg.CredentialsStore != "" {
+		store = "docker-credential-" + cfg.CredentialsStore
+	}
+	authConfig, err := cfg.GetAuthConfig(key)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the credentials of %s from %s", key, store)
+	}
+	if authConfig.Username == "" && authConfig.Password == "" && authConfig.IdentityToken == "" {
+		return errors.Errorf("no credentials are stored for %s in %s; use \"docker login %s\" to log in", key, store, key)
+	}
+	authConfig.ServerAddress = key
+
+	user := authConfig.Username
+	if user == "" {
+		user = "<token>"
+	}
+	fmt.Fprintf(dockerCli.Err(), "Verifying the credentials of %s for %s, stored in %s...\n", user, key, store)
+	_, err = dockerCli.Client().RegistryLogin(ctx, registrytypes.AuthConfig(authConfig))
+	if err != nil && client.IsErrConnectionFailed(err) {
+		_, err = loginClientSide(ctx, registrytypes.AuthConfig(authConfig))
+	}
+	if err != nil {
+		if errdefs.IsUnauthorized(err) {
+			return errors.Errorf("the registry rejected the credentials of %s stored in %s: %v; use \"docker login %s\" to log in again", key, store, err, key)
+		}
+		return errors.Wrapf(err, "failed to verify the credentials of %s", key)
+	}
+	fmt.Fprintln(dockerCli.Out(), "Credentials are valid")
+	return nil
+}
+
+// verifyStoredCredentials verifies that the credential helper used by creds
+// returns the credentials that were just stored, so that a broken helper is
+// detected on login, and not when the credentials are used.
+func verifyStoredCredentials(dockerCli command.Cli, creds credentials.Store, serverAddress string, authConfig configtypes.AuthConfig) error {
+	stored, err := creds.Get(serverAddress)
+	if err == nil {
+		// credential helpers don't store the username for identity tokens.
+		if authConfig.IdentityToken != "" {
+			if stored.IdentityToken != authConfig.IdentityToken {
+				err = errors.New("the identity token that was returned does not match")
+			}
+		} else if stored.Username != authConfig.Username || stored.Password != authConfig.Password {
+			err = errors.New("the credentials that were returned do not match")
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	// Remove what the helper may have stored, so that a partial entry isn't
+	// used on the next pull or push.
+	erased := "The credentials were removed from the credential helper."
+	if eraseErr := creds.Erase(serverAddress); eraseErr != nil {
+		erased = fmt.Sprintf("The credentials could not be removed from the credential helper, and were kept: %v", eraseErr)
+	}
+
+	cfg := dockerCli.ConfigFile()
+	helper := cfg.CredentialsStore
+	if h, ok := cfg.CredentialHelpers[credentials.ConvertToHostname(serverAddress)]; ok {
+		helper = h
+	}
+	return errors.Errorf(`Error verifying stored credentials: credential helper "docker-credential-%[1]s" failed to return the credentials for %[2]s: %[3]v
+%[5]s
+
+Check that docker-credential-%[1]s is installed and in your PATH, and that it
+can access its backing store (for example, that the keychain is unlocked).
+To store credentials in %[4]s instead, remove the "credsStore" and
+"credHelpers" options from that file.`, helper, serverAddress, err, cfg.Filename, erased)
+}
+
 func loginWithCredStoreCreds(ctx context.Context, dockerCli command.Cli, authConfig *registrytypes.AuthConfig) (registrytypes.AuthenticateOKBody, error) {
 	fmt.Fprintf(dockerCli.Out(), "Authenticating with existing credentials...\n")
 	cliClient := dockerCli.Client()
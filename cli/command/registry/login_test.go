@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/docker/cli/cli/config/credentials"
 	configtypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
@@ -27,6 +28,14 @@ const (
 
 type fakeClient struct {
 	client.Client
+	imageSearchFunc func(term string, options registrytypes.SearchOptions) ([]registrytypes.SearchResult, error)
+}
+
+func (c *fakeClient) ImageSearch(_ context.Context, term string, options registrytypes.SearchOptions) ([]registrytypes.SearchResult, error) {
+	if c.imageSearchFunc != nil {
+		return c.imageSearchFunc(term, options)
+	}
+	return nil, nil
 }
 
 func (c *fakeClient) Info(context.Context) (system.Info, error) {
@@ -185,3 +194,121 @@ func TestRunLogin(t *testing.T) {
 		})
 	}
 }
+
+// fakeStore is a credentials.Store that mimics a credential helper.
+type fakeStore struct {
+	stored   configtypes.AuthConfig
+	getErr   error
+	eraseErr error
+	lossy    bool
+}
+
+var _ credentials.Store = &fakeStore{}
+
+func (s *fakeStore) Erase(string) error {
+	if s.eraseErr != nil {
+		return s.eraseErr
+	}
+	s.stored = configtypes.AuthConfig{}
+	return nil
+}
+
+func (s *fakeStore) GetAll() (map[string]configtypes.AuthConfig, error) {
+	return map[string]configtypes.AuthConfig{s.stored.ServerAddress: s.stored}, nil
+}
+
+func (s *fakeStore) Store(authConfig configtypes.AuthConfig) error {
+	s.stored = authConfig
+	return nil
+}
+
+func (s *fakeStore) Get(string) (configtypes.AuthConfig, error) {
+	if s.getErr != nil {
+		return configtypes.AuthConfig{}, s.getErr
+	}
+	if s.lossy {
+		return configtypes.AuthConfig{Username: s.stored.Username}, nil
+	}
+	return s.stored, nil
+}
+
+func TestVerifyStoredCredentials(t *testing.T) {
+	testCases := []struct {
+		doc           string
+		store         *fakeStore
+		serverAddress string
+		authConfig    configtypes.AuthConfig
+		expectedErr   string
+		expectedKept  bool
+	}{
+		{
+			doc:        "password",
+			store:      &fakeStore{},
+			authConfig: configtypes.AuthConfig{Username: "u1", Password: "p1"},
+		},
+		{
+			doc:        "identity token",
+			store:      &fakeStore{},
+			authConfig: configtypes.AuthConfig{Username: "u1", IdentityToken: useToken},
+		},
+		{
+			doc:         "credentials not returned",
+			store:       &fakeStore{lossy: true},
+			authConfig:  configtypes.AuthConfig{Username: "u1", Password: "p1"},
+			expectedErr: `credential helper "docker-credential-reg1-helper" failed to return the credentials for reg1: the credentials that were returned do not match`,
+		},
+		{
+			doc:         "identity token not returned",
+			store:       &fakeStore{lossy: true},
+			authConfig:  configtypes.AuthConfig{Username: "u1", IdentityToken: useToken},
+			expectedErr: `credential helper "docker-credential-reg1-helper" failed to return the credentials for reg1: the identity token that was returned does not match`,
+		},
+		{
+			doc:         "helper error",
+			store:       &fakeStore{getErr: errors.New("error getting credentials - err: exit status 1, out: `keychain is locked`")},
+			authConfig:  configtypes.AuthConfig{Username: "u1", Password: "p1"},
+			expectedErr: "failed to return the credentials for reg1: error getting credentials - err: exit status 1, out: `keychain is locked`",
+		},
+		{
+			doc:           "helper of the hostname of a URL",
+			store:         &fakeStore{lossy: true},
+			serverAddress: "https://reg1/v2/",
+			authConfig:    configtypes.AuthConfig{Username: "u1", Password: "p1"},
+			expectedErr:   `credential helper "docker-credential-reg1-helper" failed to return the credentials for https://reg1/v2/`,
+		},
+		{
+			doc:          "credentials not erased",
+			store:        &fakeStore{lossy: true, eraseErr: errors.New("keychain is locked")},
+			authConfig:   configtypes.AuthConfig{Username: "u1", Password: "p1"},
+			expectedErr:  "The credentials could not be removed from the credential helper, and were kept: keychain is locked",
+			expectedKept: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.doc, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cli.ConfigFile().CredentialsStore = "default-helper"
+			cli.ConfigFile().CredentialHelpers = map[string]string{"reg1": "reg1-helper"}
+
+			serverAddress := tc.serverAddress
+			if serverAddress == "" {
+				serverAddress = "reg1"
+			}
+			assert.NilError(t, tc.store.Store(tc.authConfig))
+			err := verifyStoredCredentials(cli, tc.store, serverAddress, tc.authConfig)
+			if tc.expectedErr == "" {
+				assert.Check(t, err)
+				return
+			}
+			assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+			assert.Check(t, is.ErrorContains(err, "is installed and in your PATH"))
+			if tc.expectedKept {
+				assert.Check(t, is.Equal(tc.store.stored, tc.authConfig))
+			} else {
+				assert.Check(t, is.ErrorContains(err, "The credentials were removed from the credential helper."))
+				assert.Check(t, is.Equal(tc.store.stored, configtypes.AuthConfig{}))
+			}
+		})
+	}
+}
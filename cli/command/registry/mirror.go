@@ -0,0 +1,206 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/docker/registry"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// mirroredRegistry is the registry that the daemon pulls through its registry
+// mirrors; mirrors are only used for images on Docker Hub.
+const mirroredRegistry = "docker.io"
+
+func newMirrorCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Short: "Manage registry mirrors",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newMirrorSetupCommand(dockerCli),
+	)
+	return cmd
+}
+
+type mirrorSetupOptions struct {
+	mirror       string
+	daemonConfig string
+	testImage    string
+	noTest       bool
+}
+
+func newMirrorSetupCommand(dockerCli command.Cli) *cobra.Command {
+	var opts mirrorSetupOptions
+
+	cmd := &cobra.Command{
+		Use:   "setup [OPTIONS] URL",
+		Short: "Set up a pull-through cache as a registry mirror",
+		Args:  cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.mirror = args[0]
+			return runMirrorSetup(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.daemonConfig, "daemon-config", "", "Add the mirror to this daemon configuration file instead of printing the configuration")
+	flags.StringVar(&opts.testImage, "test-image", "hello-world", "Docker Hub image to pull through the mirror to verify it")
+	flags.BoolVar(&opts.noTest, "no-test", false, "Do not verify the mirror")
+	return cmd
+}
+
+func runMirrorSetup(ctx context.Context, dockerCli command.Cli, opts mirrorSetupOptions) error {
+	mirror, err := registry.ValidateMirror(opts.mirror)
+	if err != nil {
+		return err
+	}
+	mirrorURL, err := url.Parse(mirror)
+	if err != nil {
+		return err
+	}
+	insecure := mirrorURL.Scheme == "http"
+
+	if !opts.noTest {
+		if err := testMirror(ctx, dockerCli, mirrorURL.Host, opts.testImage, insecure); err != nil {
+			return err
+		}
+	}
+
+	if opts.daemonConfig != "" {
+		if err := updateDaemonConfig(opts.daemonConfig, mirror, mirrorURL.Host, insecure); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(dockerCli.Out(), "Added %s to %s. Restart the Docker daemon to use the mirror.\n", mirror, opts.daemonConfig)
+	} else {
+		snippet := map[string][]string{"registry-mirrors": {mirror}}
+		if insecure {
+			snippet["insecure-registries"] = []string{mirrorURL.Host}
+		}
+		b, err := json.MarshalIndent(snippet, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(dockerCli.Err(), "Add the following to the daemon configuration file (daemon.json), and restart the Docker daemon:")
+		_, _ = fmt.Fprintln(dockerCli.Out(), string(b))
+	}
+
+	cfg := dockerCli.ConfigFile()
+	if cfg.Registries == nil {
+		cfg.Registries = make(map[string]configfile.RegistryConfig)
+	}
+	rc := cfg.Registries[mirroredRegistry]
+	rc.Mirrors = appendUnique(rc.Mirrors, mirror)
+	cfg.Registries[mirroredRegistry] = rc
+	return errors.Wrap(cfg.Save(), "failed to save the mirror in the configuration file")
+}
+
+// testMirror verifies that the mirror at host serves image, by fetching its
+// manifest through the mirror. For a pull-through cache, this also verifies
+// that the cache can pull from Docker Hub.
+func testMirror(ctx context.Context, dockerCli command.Cli, host, image string, insecure bool) error {
+	ref, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+	if reference.Domain(ref) != mirroredRegistry {
+		return errors.Errorf("invalid test image %q: must be an image on Docker Hub", image)
+	}
+	mirrorRef, err := reference.WithName(host + "/" + reference.Path(ref))
+	if err != nil {
+		return err
+	}
+	switch r := reference.TagNameOnly(ref).(type) {
+	case reference.Canonical:
+		mirrorRef, err = reference.WithDigest(mirrorRef, r.Digest())
+	case reference.NamedTagged:
+		mirrorRef, err = reference.WithTag(mirrorRef, r.Tag())
+	}
+	if err != nil {
+		return err
+	}
+
+	dockerCli.Err().Infof("Pulling %s through the mirror\n", reference.FamiliarString(reference.TagNameOnly(ref)))
+	manifest, err := dockerCli.RegistryClient(insecure).GetManifest(ctx, mirrorRef)
+	if err != nil {
+		return errors.Wrapf(err, "failed to pull %s through the mirror; use --no-test to set up the mirror anyway", reference.FamiliarString(ref))
+	}
+	dockerCli.Err().Infof("The mirror works: got %s\n", manifest.Descriptor.Digest)
+	return nil
+}
+
+// updateDaemonConfig adds mirror to the "registry-mirrors" of the daemon
+// configuration file at path, and host to its "insecure-registries" if the
+// mirror is insecure. The file is created if it doesn't exist.
+func updateDaemonConfig(path, mirror, host string, insecure bool) error {
+	config := map[string]any{}
+	mode := os.FileMode(0o644)
+	b, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(b, &config); err != nil {
+			return errors.Wrapf(err, "invalid daemon configuration file %s", path)
+		}
+		if fi, err := os.Stat(path); err == nil {
+			mode = fi.Mode().Perm()
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	if err := addToList(config, "registry-mirrors", mirror); err != nil {
+		return errors.Wrapf(err, "invalid daemon configuration file %s", path)
+	}
+	if insecure {
+		if err := addToList(config, "insecure-registries", host); err != nil {
+			return errors.Wrapf(err, "invalid daemon configuration file %s", path)
+		}
+	}
+
+	b, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), mode)
+}
+
+// addToList adds value to the list of strings in config[key], unless the
+// list already contains it.
+func addToList(config map[string]any, key, value string) error {
+	var list []string
+	if v, ok := config[key]; ok {
+		items, ok := v.([]any)
+		if !ok {
+			return errors.Errorf("%q must be a list", key)
+		}
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok {
+				return errors.Errorf("%q must be a list of strings", key)
+			}
+			list = append(list, s)
+		}
+	}
+	config[key] = appendUnique(list, value)
+	return nil
+}
+
+func appendUnique(list []string, value string) []string {
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/config/configfile"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/cli/cli/registry/client"
+	"github.com/docker/cli/internal/test"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+type fakeRegistryClient struct {
+	client.RegistryClient
+	getManifestFunc    func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
+	getRawManifestFunc func(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error)
+	getBlobFunc        func(ctx context.Context, ref reference.Canonical) ([]byte, error)
+}
+
+func (c *fakeRegistryClient) GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+	if c.getManifestFunc != nil {
+		return c.getManifestFunc(ctx, ref)
+	}
+	return manifesttypes.ImageManifest{}, nil
+}
+
+func (c *fakeRegistryClient) GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	if c.getRawManifestFunc != nil {
+		return c.getRawManifestFunc(ctx, ref)
+	}
+	return ocispec.Descriptor{}, nil, nil
+}
+
+func (c *fakeRegistryClient) GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error) {
+	if c.getBlobFunc != nil {
+		return c.getBlobFunc(ctx, ref)
+	}
+	return nil, nil
+}
+
+func newMirrorTestCli(t *testing.T, getManifest func(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)) (*test.FakeCli, string) {
+	t.Helper()
+	dir := fs.NewDir(t, "mirror-setup")
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetConfigFile(configfile.New(filepath.Join(dir.Path(), "config.json")))
+	cli.SetRegistryClient(&fakeRegistryClient{getManifestFunc: getManifest})
+	return cli, dir.Path()
+}
+
+func TestMirrorSetup(t *testing.T) {
+	var pulled string
+	cli, _ := newMirrorTestCli(t, func(_ context.Context, ref reference.Named) (manifesttypes.ImageManifest, error) {
+		pulled = ref.String()
+		return manifesttypes.ImageManifest{Descriptor: ocispec.Descriptor{Digest: digest.Digest("sha256:abc")}}, nil
+	})
+
+	cmd := newMirrorSetupCommand(cli)
+	cmd.SetArgs([]string{"--test-image", "alpine", "https://mirror.example.com"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(pulled, "mirror.example.com/library/alpine:latest"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "{\n  \"registry-mirrors\": [\n    \"https://mirror.example.com/\"\n  ]\n}\n"))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "The mirror works: got sha256:abc"))
+	assert.Check(t, is.DeepEqual(cli.ConfigFile().Registries, map[string]configfile.RegistryConfig{
+		"docker.io": {Mirrors: []string{"https://mirror.example.com/"}},
+	}))
+}
+
+func TestMirrorSetupDaemonConfig(t *testing.T) {
+	cli, dir := newMirrorTestCli(t, nil)
+	daemonConfig := filepath.Join(dir, "daemon.json")
+	assert.NilError(t, os.WriteFile(daemonConfig, []byte(`{"debug": true, "registry-mirrors": ["https://other.example.com/"]}`), 0o600))
+
+	cmd := newMirrorSetupCommand(cli)
+	cmd.SetArgs([]string{"--no-test", "--daemon-config", daemonConfig, "http://localhost:5000"})
+	assert.NilError(t, cmd.Execute())
+
+	b, err := os.ReadFile(daemonConfig)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), `{
+  "debug": true,
+  "insecure-registries": [
+    "localhost:5000"
+  ],
+  "registry-mirrors": [
+    "https://other.example.com/",
+    "http://localhost:5000/"
+  ]
+}
+`))
+	fi, err := os.Stat(daemonConfig)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(fi.Mode().Perm(), os.FileMode(0o600)))
+
+	// Setting up the same mirror again doesn't add it twice.
+	cmd = newMirrorSetupCommand(cli)
+	cmd.SetArgs([]string{"--no-test", "--daemon-config", daemonConfig, "http://localhost:5000"})
+	assert.NilError(t, cmd.Execute())
+	b2, err := os.ReadFile(daemonConfig)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b2), string(b)))
+	assert.Check(t, is.DeepEqual(cli.ConfigFile().Registries["docker.io"].Mirrors, []string{"http://localhost:5000/"}))
+}
+
+func TestMirrorSetupErrors(t *testing.T) {
+	tests := []struct {
+		doc           string
+		args          []string
+		daemonConfig  string
+		expectedError string
+	}{
+		{
+			doc:           "invalid scheme",
+			args:          []string{"ftp://mirror.example.com"},
+			expectedError: `invalid mirror: unsupported scheme "ftp" in "ftp://mirror.example.com"`,
+		},
+		{
+			doc:           "test image not on Docker Hub",
+			args:          []string{"--test-image", "registry.example.com/alpine", "https://mirror.example.com"},
+			expectedError: `invalid test image "registry.example.com/alpine": must be an image on Docker Hub`,
+		},
+		{
+			doc:           "test pull fails",
+			args:          []string{"--test-image", "fail", "https://mirror.example.com"},
+			expectedError: "failed to pull fail through the mirror; use --no-test to set up the mirror anyway: connection refused",
+		},
+		{
+			doc:           "invalid daemon config",
+			args:          []string{"--no-test", "https://mirror.example.com"},
+			daemonConfig:  `{"registry-mirrors": "https://other.example.com/"}`,
+			expectedError: `"registry-mirrors" must be a list`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			cli, dir := newMirrorTestCli(t, func(context.Context, reference.Named) (manifesttypes.ImageManifest, error) {
+				return manifesttypes.ImageManifest{}, errors.New("connection refused")
+			})
+			args := tc.args
+			if tc.daemonConfig != "" {
+				daemonConfig := filepath.Join(dir, "daemon.json")
+				assert.NilError(t, os.WriteFile(daemonConfig, []byte(tc.daemonConfig), 0o644))
+				args = append([]string{"--daemon-config", daemonConfig}, args...)
+			}
+			cmd := newMirrorSetupCommand(cli)
+			cmd.SetArgs(args)
+			cmd.SetOut(cli.OutBuffer())
+			cmd.SetErr(cli.ErrBuffer())
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+			assert.Check(t, is.Len(cli.ConfigFile().Registries, 0))
+		})
+	}
+}
@@ -1,24 +1,54 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
 package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	registryclient "github.com/docker/cli/cli/registry/client"
 	"github.com/docker/cli/opts"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/registry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// defaultSearchLimit is the number of results that the daemon returns if
+	// no limit is set.
+	defaultSearchLimit = 25
+	// maxSearchResults is the maximum number of results that the daemon
+	// returns, so that pages can only show the first results.
+	maxSearchResults = 100
+	// maxConcurrentPlatformRequests is the number of search results whose
+	// platforms are fetched from the registry at the same time, to filter
+	// them by architecture.
+	maxConcurrentPlatformRequests = 8
+
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
 )
 
 type searchOptions struct {
-	format  string
-	term    string
-	noTrunc bool
-	limit   int
-	filter  opts.FilterOpt
+	format   string
+	term     string
+	registry string
+	noTrunc  bool
+	limit    int
+	page     int
+	filter   opts.FilterOpt
 }
 
 // NewSearchCommand creates a new `docker search` command
@@ -43,20 +73,52 @@ func NewSearchCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&options.noTrunc, "no-trunc", false, "Don't truncate output")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.IntVar(&options.limit, "limit", 0, "Max number of search results")
-	flags.StringVar(&options.format, "format", "", "Pretty-print search using a Go template")
+	flags.IntVar(&options.page, "page", 1, "Page of search results to show, of --limit results each")
+	flags.StringVar(&options.registry, "registry", "", "Search the registry with this address instead of Docker Hub")
+	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
 
 	return cmd
 }
 
 func runSearch(ctx context.Context, dockerCli command.Cli, options searchOptions) error {
+	if options.page < 1 {
+		return errors.New("--page must be at least 1")
+	}
 	if options.filter.Value().Contains("is-automated") {
 		_, _ = fmt.Fprintln(dockerCli.Err(), `WARNING: the "is-automated" filter is deprecated, and searching for "is-automated=true" will not yield any results in future.`)
 	}
-	indexInfo, err := registry.ParseSearchIndexInfo(options.term)
+	term := options.term
+	if options.registry != "" {
+		if info, err := registry.ParseSearchIndexInfo(term); err == nil && !info.Official {
+			return errors.Errorf("--registry can't be used with a search term that has a registry (%s)", info.Name)
+		}
+		term = options.registry + "/" + term
+	}
+	indexInfo, err := registry.ParseSearchIndexInfo(term)
 	if err != nil {
 		return err
 	}
 
+	// Pages are sliced from the first results, as the search API of the
+	// daemon has no paging.
+	limit := options.limit
+	if options.page > 1 {
+		if limit == 0 {
+			limit = defaultSearchLimit
+		}
+		if limit*options.page > maxSearchResults {
+			return errors.Errorf("--page %d of %d results is beyond the first %d results, which are the only results that can be shown", options.page, limit, maxSearchResults)
+		}
+	}
+
+	// The architecture filter isn't supported by the daemon: it's applied to
+	// the results of the search.
+	architectures := options.filter.Value().Get("architecture")
+	searchFilters := options.filter.Value().Clone()
+	for _, arch := range architectures {
+		searchFilters.Del("architecture", arch)
+	}
+
 	authConfig := command.ResolveAuthConfig(dockerCli.ConfigFile(), indexInfo)
 	encodedAuth, err := registrytypes.EncodeAuthConfig(authConfig)
 	if err != nil {
@@ -64,15 +126,25 @@ func runSearch(ctx context.Context, dockerCli command.Cli, options searchOptions
 	}
 
 	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(dockerCli, indexInfo, "search")
-	results, err := dockerCli.Client().ImageSearch(ctx, options.term, registrytypes.SearchOptions{
+	results, err := dockerCli.Client().ImageSearch(ctx, term, registrytypes.SearchOptions{
 		RegistryAuth:  encodedAuth,
 		PrivilegeFunc: requestPrivilege,
-		Filters:       options.filter.Value(),
-		Limit:         options.limit,
+		Filters:       searchFilters,
+		Limit:         limit * options.page,
 	})
 	if err != nil {
 		return err
 	}
+	if len(architectures) > 0 {
+		results, err = filterSearchArchitectures(ctx, dockerCli.RegistryClient(false), indexInfo, results, architectures)
+		if err != nil {
+			return err
+		}
+	}
+	if options.page > 1 {
+		start := min(limit*(options.page-1), len(results))
+		results = results[start:min(start+limit, len(results))]
+	}
 
 	searchCtx := formatter.Context{
 		Output: dockerCli.Out(),
@@ -81,3 +153,93 @@ func runSearch(ctx context.Context, dockerCli command.Cli, options searchOptions
 	}
 	return SearchWrite(searchCtx, results)
 }
+
+// filterSearchArchitectures returns the results whose "latest" tag has an
+// image for one of the architectures. Results whose platforms can't be
+// fetched, such as repositories without a "latest" tag, don't match.
+func filterSearchArchitectures(ctx context.Context, rc registryclient.RegistryClient, indexInfo *registrytypes.IndexInfo, results []registrytypes.SearchResult, architectures []string) ([]registrytypes.SearchResult, error) {
+	matches := make([]bool, len(results))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrentPlatformRequests)
+	for i, result := range results {
+		i, result := i, result
+		eg.Go(func() error {
+			name := result.Name
+			if !indexInfo.Official {
+				name = indexInfo.Name + "/" + name
+			}
+			ref, err := reference.ParseNormalizedNamed(name)
+			if err != nil {
+				return nil
+			}
+			repoPlatforms, err := latestPlatforms(egCtx, rc, reference.TagNameOnly(ref))
+			if err != nil {
+				logrus.Debugf("failed to get the platforms of %s: %v", name, err)
+				return nil
+			}
+			for _, p := range repoPlatforms {
+				for _, arch := range architectures {
+					// Normalize architectures such as "aarch64" to "arm64".
+					if platforms.Normalize(ocispec.Platform{Architecture: arch}).Architecture == platforms.Normalize(p).Architecture {
+						matches[i] = true
+					}
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	filtered := make([]registrytypes.SearchResult, 0, len(results))
+	for i, result := range results {
+		if matches[i] {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
+
+// latestPlatforms returns the platforms of the images of the manifest, or
+// image index, that ref refers to.
+func latestPlatforms(ctx context.Context, rc registryclient.RegistryClient, ref reference.Named) ([]ocispec.Platform, error) {
+	desc, payload, err := rc.GetRawManifest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if desc.MediaType == ocispec.MediaTypeImageIndex || desc.MediaType == mediaTypeDockerManifestList {
+		var index ocispec.Index
+		if err := json.Unmarshal(payload, &index); err != nil {
+			return nil, err
+		}
+		var result []ocispec.Platform
+		for _, m := range index.Manifests {
+			if m.Platform != nil {
+				result = append(result, *m.Platform)
+			}
+		}
+		return result, nil
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return nil, err
+	}
+	configRef, err := reference.WithDigest(reference.TrimNamed(ref), manifest.Config.Digest)
+	if err != nil {
+		return nil, err
+	}
+	b, err := rc.GetBlob(ctx, configRef)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Config.Digest.Algorithm().Available() && digest.FromBytes(b) != manifest.Config.Digest {
+		return nil, errors.Errorf("content of config %s doesn't match its digest", manifest.Config.Digest)
+	}
+	var cfg ocispec.Image
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+	return []ocispec.Platform{cfg.Platform}, nil
+}
@@ -0,0 +1,220 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test"
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func searchResults(n int) []registrytypes.SearchResult {
+	results := make([]registrytypes.SearchResult, 0, n)
+	for i := 0; i < n; i++ {
+		results = append(results, registrytypes.SearchResult{Name: fmt.Sprintf("result%d", i), StarCount: n - i})
+	}
+	return results
+}
+
+func TestSearchJSON(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		imageSearchFunc: func(string, registrytypes.SearchOptions) ([]registrytypes.SearchResult, error) {
+			return []registrytypes.SearchResult{{Name: "busybox", Description: "Busybox base image.", StarCount: 3000, IsOfficial: true}}, nil
+		},
+	})
+	cmd := NewSearchCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--format", "json", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `{"Description":"Busybox base image.","IsAutomated":"false","IsOfficial":"true","Name":"busybox","StarCount":"3000"}`+"\n"))
+}
+
+func TestSearchPage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedLimit int
+		expectedOut   string
+	}{
+		{
+			name:          "first page",
+			args:          []string{"--limit", "2"},
+			expectedLimit: 2,
+			expectedOut:   "result0\nresult1\n",
+		},
+		{
+			name:          "second page",
+			args:          []string{"--limit", "2", "--page", "2"},
+			expectedLimit: 4,
+			expectedOut:   "result2\nresult3\n",
+		},
+		{
+			name:          "last page",
+			args:          []string{"--limit", "4", "--page", "2"},
+			expectedLimit: 8,
+			expectedOut:   "result4\n",
+		},
+		{
+			name:          "beyond the last page",
+			args:          []string{"--limit", "4", "--page", "3"},
+			expectedLimit: 12,
+		},
+		{
+			name:          "default limit",
+			args:          []string{"--page", "2"},
+			expectedLimit: 2 * defaultSearchLimit,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageSearchFunc: func(_ string, options registrytypes.SearchOptions) ([]registrytypes.SearchResult, error) {
+					assert.Check(t, is.Equal(options.Limit, tc.expectedLimit))
+					return searchResults(min(options.Limit, 5)), nil
+				},
+			})
+			cmd := NewSearchCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetArgs(append(tc.args, "--format", "{{.Name}}", "busybox"))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expectedOut))
+		})
+	}
+}
+
+func TestSearchRegistry(t *testing.T) {
+	var searched string
+	cli := test.NewFakeCli(&fakeClient{
+		imageSearchFunc: func(term string, _ registrytypes.SearchOptions) ([]registrytypes.SearchResult, error) {
+			searched = term
+			return nil, nil
+		},
+	})
+	cmd := NewSearchCommand(cli)
+	cmd.SetOut(io.Discard)
+	cmd.SetArgs([]string{"--registry", "registry.example.com:5000", "busybox"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(searched, "registry.example.com:5000/busybox"))
+}
+
+func TestSearchArchitecture(t *testing.T) {
+	manifests := map[string][]byte{}
+	mediaTypes := map[string]string{}
+	blobs := map[digest.Digest][]byte{}
+	add := func(ref, mediaType string, v any) {
+		b, err := json.Marshal(v)
+		assert.NilError(t, err)
+		manifests[ref], mediaTypes[ref] = b, mediaType
+	}
+	addConfig := func(arch string) ocispec.Descriptor {
+		b, err := json.Marshal(ocispec.Image{Platform: ocispec.Platform{OS: "linux", Architecture: arch}})
+		assert.NilError(t, err)
+		dgst := digest.FromBytes(b)
+		blobs[dgst] = b
+		return ocispec.Descriptor{MediaType: ocispec.MediaTypeImageConfig, Digest: dgst, Size: int64(len(b))}
+	}
+	rc := &fakeRegistryClient{
+		getRawManifestFunc: func(_ context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+			b, ok := manifests[ref.String()]
+			if !ok {
+				return ocispec.Descriptor{}, nil, errdefs.NotFound(errors.New("manifest unknown"))
+			}
+			return ocispec.Descriptor{MediaType: mediaTypes[ref.String()], Digest: digest.FromBytes(b), Size: int64(len(b))}, b, nil
+		},
+		getBlobFunc: func(_ context.Context, ref reference.Canonical) ([]byte, error) {
+			b, ok := blobs[ref.Digest()]
+			if !ok {
+				return nil, errdefs.NotFound(errors.New("blob unknown"))
+			}
+			return b, nil
+		},
+	}
+	add("docker.io/library/multi:latest", ocispec.MediaTypeImageIndex, ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{
+			{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromString("amd64"), Platform: &ocispec.Platform{OS: "linux", Architecture: "amd64"}},
+			{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromString("arm64"), Platform: &ocispec.Platform{OS: "linux", Architecture: "arm64", Variant: "v8"}},
+		},
+	})
+	add("docker.io/example/amd64:latest", ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: addConfig("amd64")})
+	add("docker.io/example/arm64:latest", ocispec.MediaTypeImageManifest, ocispec.Manifest{MediaType: ocispec.MediaTypeImageManifest, Config: addConfig("arm64")})
+
+	testCases := []struct {
+		name        string
+		arch        []string
+		expectedOut string
+	}{
+		{name: "arm64", arch: []string{"arm64"}, expectedOut: "multi\nexample/arm64\n"},
+		{name: "aarch64", arch: []string{"aarch64"}, expectedOut: "multi\nexample/arm64\n"},
+		{name: "any of", arch: []string{"amd64", "arm64"}, expectedOut: "multi\nexample/amd64\nexample/arm64\n"},
+		{name: "none", arch: []string{"s390x"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				imageSearchFunc: func(_ string, options registrytypes.SearchOptions) ([]registrytypes.SearchResult, error) {
+					assert.Check(t, !options.Filters.Contains("architecture"))
+					assert.Check(t, options.Filters.ExactMatch("is-official", "false"))
+					return []registrytypes.SearchResult{{Name: "multi"}, {Name: "example/amd64"}, {Name: "example/arm64"}, {Name: "example/untagged"}}, nil
+				},
+			})
+			cli.SetRegistryClient(rc)
+			cmd := NewSearchCommand(cli)
+			cmd.SetOut(io.Discard)
+			args := []string{"--format", "{{.Name}}", "--filter", "is-official=false"}
+			for _, arch := range tc.arch {
+				args = append(args, "--filter", "architecture="+arch)
+			}
+			cmd.SetArgs(append(args, "example"))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expectedOut))
+		})
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "invalid page",
+			args:          []string{"--page", "0", "busybox"},
+			expectedError: "--page must be at least 1",
+		},
+		{
+			name:          "page beyond the first results",
+			args:          []string{"--page", "3", "--limit", "50", "busybox"},
+			expectedError: "--page 3 of 50 results is beyond the first 100 results",
+		},
+		{
+			name:          "registry in term and flag",
+			args:          []string{"--registry", "registry.example.com", "other.example.com/busybox"},
+			expectedError: "--registry can't be used with a search term that has a registry (other.example.com)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cmd := NewSearchCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			assert.Check(t, is.ErrorContains(cmd.Execute(), tc.expectedError))
+		})
+	}
+}
@@ -14,6 +14,8 @@ type fakeClient struct {
 	secretInspectFunc func(context.Context, string) (swarm.Secret, []byte, error)
 	secretListFunc    func(context.Context, types.SecretListOptions) ([]swarm.Secret, error)
 	secretRemoveFunc  func(context.Context, string) error
+	serviceListFunc   func(context.Context, types.ServiceListOptions) ([]swarm.Service, error)
+	serviceUpdateFunc func(context.Context, string, swarm.Version, swarm.ServiceSpec, types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error)
 }
 
 func (c *fakeClient) SecretCreate(ctx context.Context, spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
@@ -43,3 +45,17 @@ func (c *fakeClient) SecretRemove(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+func (c *fakeClient) ServiceList(ctx context.Context, options types.ServiceListOptions) ([]swarm.Service, error) {
+	if c.serviceListFunc != nil {
+		return c.serviceListFunc(ctx, options)
+	}
+	return []swarm.Service{}, nil
+}
+
+func (c *fakeClient) ServiceUpdate(ctx context.Context, serviceID string, version swarm.Version, service swarm.ServiceSpec, options types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
+	if c.serviceUpdateFunc != nil {
+		return c.serviceUpdateFunc(ctx, serviceID, version, service, options)
+	}
+	return swarm.ServiceUpdateResponse{}, nil
+}
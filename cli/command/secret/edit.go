@@ -0,0 +1,89 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type editOptions struct {
+	secret         string
+	name           string
+	rotateServices bool
+}
+
+func newSecretEditCommand(dockerCli command.Cli) *cobra.Command {
+	var options editOptions
+
+	cmd := &cobra.Command{
+		Use:   "edit [OPTIONS] SECRET",
+		Short: "Edit the content of a secret in a new version of the secret",
+		Long: `Edit the content of a secret in the editor set by the VISUAL or EDITOR
+environment variables. The content of a secret can't be read back from the
+swarm, so the editor starts with an empty file, for the new content of the
+secret. As secrets can't be updated, the new content is stored in a new secret,
+named after the version of the secret ("NAME-v2", "NAME-v3", and so on), with
+the labels of the secret.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.secret = args[0]
+			return runSecretEdit(cmd.Context(), dockerCli, options)
+		},
+		ValidArgsFunction: completeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.name, "name", "", "Name of the new secret (default: the next version of the name of the secret)")
+	flags.BoolVar(&options.rotateServices, "rotate-services", false, "Update the services that use the secret to use the new secret")
+	return cmd
+}
+
+func runSecretEdit(ctx context.Context, dockerCli command.Cli, options editOptions) error {
+	apiClient := dockerCli.Client()
+
+	secret, _, err := apiClient.SecretInspectWithRaw(ctx, options.secret)
+	if err != nil {
+		return err
+	}
+	if secret.Spec.Driver != nil {
+		return errors.Errorf("secret %s is provided by the %s secret driver, and can't be edited", secret.Spec.Name, secret.Spec.Driver.Name)
+	}
+	data, err := command.EditContent(secret.Spec.Name, nil)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return errors.New("the edited secret is empty, no secret was created")
+	}
+
+	spec := secret.Spec
+	spec.Data = data
+	spec.Name = options.name
+	if spec.Name == "" {
+		spec.Name = command.NextVersionName(secret.Spec.Name)
+	}
+	r, err := apiClient.SecretCreate(ctx, spec)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), r.ID)
+
+	if !options.rotateServices {
+		return nil
+	}
+	return command.RotateServices(ctx, dockerCli, func(cspec *swarm.ContainerSpec) bool {
+		var uses bool
+		for _, ref := range cspec.Secrets {
+			if ref.SecretID == secret.ID {
+				ref.SecretID, ref.SecretName = r.ID, spec.Name
+				uses = true
+			}
+		}
+		return uses
+	})
+}
@@ -0,0 +1,78 @@
+package secret
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSecretEdit(t *testing.T) {
+	test.SetEditor(t, "s3cret")
+	var created swarm.SecretSpec
+	var updated []swarm.ServiceSpec
+	cli := test.NewFakeCli(&fakeClient{
+		secretInspectFunc: func(context.Context, string) (swarm.Secret, []byte, error) {
+			return swarm.Secret{ID: "secret-id", Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "db-password-v2"}}}, nil, nil
+		},
+		secretCreateFunc: func(_ context.Context, spec swarm.SecretSpec) (types.SecretCreateResponse, error) {
+			created = spec
+			return types.SecretCreateResponse{ID: "new-id"}, nil
+		},
+		serviceListFunc: func(context.Context, types.ServiceListOptions) ([]swarm.Service, error) {
+			return []swarm.Service{{Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{Name: "db"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{
+					Secrets: []*swarm.SecretReference{{SecretID: "secret-id", SecretName: "db-password-v2", File: &swarm.SecretReferenceFileTarget{Name: "db-password"}}},
+				}},
+			}}}, nil
+		},
+		serviceUpdateFunc: func(_ context.Context, _ string, _ swarm.Version, spec swarm.ServiceSpec, _ types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
+			updated = append(updated, spec)
+			return swarm.ServiceUpdateResponse{}, nil
+		},
+	})
+
+	cmd := newSecretEditCommand(cli)
+	cmd.SetArgs([]string{"--rotate-services", "db-password-v2"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(created.Name, "db-password-v3"))
+	assert.Check(t, is.Equal(string(created.Data), "s3cret"))
+	assert.Assert(t, is.Len(updated, 1))
+	assert.Check(t, is.DeepEqual(updated[0].TaskTemplate.ContainerSpec.Secrets, []*swarm.SecretReference{
+		{SecretID: "new-id", SecretName: "db-password-v3", File: &swarm.SecretReferenceFileTarget{Name: "db-password"}},
+	}))
+}
+
+func TestSecretEditErrors(t *testing.T) {
+	test.SetEditor(t, "")
+	testCases := []struct {
+		secret        swarm.Secret
+		expectedError string
+	}{
+		{
+			secret:        swarm.Secret{Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "token"}, Driver: &swarm.Driver{Name: "vault"}}},
+			expectedError: "secret token is provided by the vault secret driver, and can't be edited",
+		},
+		{
+			secret:        swarm.Secret{Spec: swarm.SecretSpec{Annotations: swarm.Annotations{Name: "token"}}},
+			expectedError: "the edited secret is empty, no secret was created",
+		},
+	}
+	for _, tc := range testCases {
+		cmd := newSecretEditCommand(test.NewFakeCli(&fakeClient{
+			secretInspectFunc: func(context.Context, string) (swarm.Secret, []byte, error) {
+				return tc.secret, nil, nil
+			},
+		}))
+		cmd.SetArgs([]string{"token"})
+		cmd.SilenceUsage = true
+		cmd.SetErr(io.Discard)
+		assert.Check(t, is.Error(cmd.Execute(), tc.expectedError))
+	}
+}
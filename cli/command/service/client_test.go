@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"io"
+	"strings"
 
 	"github.com/docker/cli/internal/test/builders"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/system"
@@ -20,6 +23,8 @@ type fakeClient struct {
 	infoFunc                  func(ctx context.Context) (system.Info, error)
 	networkInspectFunc        func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error)
 	nodeListFunc              func(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error)
+	serviceLogsFunc           func(ctx context.Context, serviceID string, options container.LogsOptions) (io.ReadCloser, error)
+	taskInspectWithRawFunc    func(ctx context.Context, taskID string) (swarm.Task, []byte, error)
 }
 
 func (f *fakeClient) NodeList(ctx context.Context, options types.NodeListOptions) ([]swarm.Node, error) {
@@ -74,6 +79,20 @@ func (f *fakeClient) NetworkInspect(ctx context.Context, networkID string, optio
 	return network.Inspect{}, nil
 }
 
+func (f *fakeClient) ServiceLogs(ctx context.Context, serviceID string, options container.LogsOptions) (io.ReadCloser, error) {
+	if f.serviceLogsFunc != nil {
+		return f.serviceLogsFunc(ctx, serviceID, options)
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (f *fakeClient) TaskInspectWithRaw(ctx context.Context, taskID string) (swarm.Task, []byte, error) {
+	if f.taskInspectWithRawFunc != nil {
+		return f.taskInspectWithRawFunc(ctx, taskID)
+	}
+	return swarm.Task{}, nil, nil
+}
+
 func newService(id string, name string) swarm.Service {
 	return *builders.Service(builders.ServiceID(id), builders.ServiceName(name))
 }
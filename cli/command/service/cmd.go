@@ -4,7 +4,6 @@ import (
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
-	"github.com/docker/docker/api/types"
 	"github.com/spf13/cobra"
 )
 
@@ -36,15 +35,5 @@ func NewServiceCommand(dockerCli command.Cli) *cobra.Command {
 
 // CompletionFn offers completion for swarm services
 func CompletionFn(dockerCLI completion.APIClientProvider) completion.ValidArgsFn {
-	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		list, err := dockerCLI.Client().ServiceList(cmd.Context(), types.ServiceListOptions{})
-		if err != nil {
-			return nil, cobra.ShellCompDirectiveError
-		}
-		var names []string
-		for _, service := range list {
-			names = append(names, service.ID)
-		}
-		return names, cobra.ShellCompDirectiveNoFileComp
-	}
+	return completion.ServiceNames(dockerCLI)
 }
@@ -73,6 +73,10 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation(flagHostAdd, "version", []string{"1.32"})
 
 	flags.SetInterspersed(false)
+
+	_ = cmd.RegisterFlagCompletionFunc(flagCapAdd, completion.CapabilitiesAddable)
+	_ = cmd.RegisterFlagCompletionFunc(flagCapDrop, completion.CapabilitiesDroppable)
+	_ = cmd.RegisterFlagCompletionFunc(flagNetwork, completion.NetworkNames(dockerCli))
 	return cmd
 }
 
@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
@@ -20,6 +21,7 @@ import (
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/stringid"
+	"github.com/fvbommel/sortorder"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -34,10 +36,22 @@ type logsOptions struct {
 	tail       string
 	details    bool
 	raw        bool
+	rawJSON    bool
+	groupBy    string
 
 	target string
 }
 
+// Values of the --group-by option of "docker service logs".
+const (
+	groupByTask = "task"
+	groupByNode = "node"
+)
+
+// prefixColors are the ANSI colors of the prefixes of the logs of tasks,
+// when writing to a terminal.
+var prefixColors = []int{36, 33, 32, 35, 34, 31, 96, 93, 92, 95, 94, 91}
+
 func newLogsCommand(dockerCli command.Cli) *cobra.Command {
 	var opts logsOptions
 
@@ -62,6 +76,9 @@ func newLogsCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVar(&opts.raw, "raw", false, "Do not neatly format logs")
 	flags.SetAnnotation("raw", "version", []string{"1.30"})
 	flags.BoolVar(&opts.noTaskIDs, "no-task-ids", false, "Do not include task IDs in output")
+	flags.BoolVar(&opts.rawJSON, "raw-json", false, "Print each log line as a JSON object, with the task and node it's from")
+	flags.SetAnnotation("raw-json", "version", []string{"1.30"})
+	flags.StringVar(&opts.groupBy, "group-by", "", `Group the logs by "task" or "node", instead of interleaving them`)
 	// options identical to container logs
 	flags.BoolVarP(&opts.follow, "follow", "f", false, "Follow log output")
 	flags.StringVar(&opts.since, "since", "", `Show logs since timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
@@ -73,6 +90,18 @@ func newLogsCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) error {
+	switch opts.groupBy {
+	case "", groupByTask, groupByNode:
+	default:
+		return errors.Errorf("invalid value for --group-by: %q: must be %q or %q", opts.groupBy, groupByTask, groupByNode)
+	}
+	if opts.groupBy != "" && opts.follow {
+		return errors.New("conflicting options: --group-by and --follow")
+	}
+	if opts.raw && (opts.rawJSON || opts.groupBy != "") {
+		return errors.New("--raw can't be used with --raw-json or --group-by")
+	}
+
 	apiClient := dockerCli.Client()
 
 	var (
@@ -129,7 +158,7 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 		ShowStdout: true,
 		ShowStderr: true,
 		Since:      opts.since,
-		Timestamps: opts.timestamps,
+		Timestamps: opts.timestamps || opts.rawJSON,
 		Follow:     opts.follow,
 		Tail:       opts.tail,
 		// get the details if we request it OR if we're not doing raw mode
@@ -141,6 +170,10 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	}
 	defer responseBody.Close()
 
+	if !opts.follow {
+		defer dockerCli.Out().StartPager()()
+	}
+
 	// tty logs get straight copied. they're not muxed with stdcopy
 	if tty {
 		_, err = io.Copy(dockerCli.Out(), responseBody)
@@ -152,14 +185,24 @@ func runLogs(ctx context.Context, dockerCli command.Cli, opts *logsOptions) erro
 	var stdout, stderr io.Writer
 	stdout = dockerCli.Out()
 	stderr = dockerCli.Err()
+	var groups *logGroups
 	if !opts.raw {
 		taskFormatter := newTaskFormatter(apiClient, opts, maxLength)
+		taskFormatter.color = dockerCli.Out().IsTerminal() && !opts.rawJSON
+		if opts.groupBy != "" {
+			groups = &logGroups{}
+		}
 
-		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout}
-		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr}
+		stdout = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stdout, stream: "stdout", groups: groups}
+		stderr = &logWriter{ctx: ctx, opts: opts, f: taskFormatter, w: stderr, stream: "stderr", groups: groups}
 	}
 
 	_, err = stdcopy.StdCopy(stdout, stderr, responseBody)
+	if groups != nil {
+		if flushErr := groups.flush(dockerCli.Out()); err == nil {
+			err = flushErr
+		}
+	}
 	return err
 }
 
@@ -172,11 +215,23 @@ type taskFormatter struct {
 	client  client.APIClient
 	opts    *logsOptions
 	padding int
+	// color is set if the prefixes of the logs of tasks are colored
+	color bool
 
 	r *idresolver.IDResolver
-	// cache saves a pre-cooked logContext formatted string based on a
-	// logcontext object, so we don't have to resolve names every time
-	cache map[logContext]string
+	// cache saves a pre-cooked logTask based on a logcontext object, so we
+	// don't have to resolve names every time
+	cache map[logContext]logTask
+}
+
+// logTask holds the resolved names of the task of a log context.
+type logTask struct {
+	service string
+	task    string
+	node    string
+	slot    int
+	// prefix is the formatted prefix of the log lines of the task
+	prefix string
 }
 
 func newTaskFormatter(apiClient client.APIClient, opts *logsOptions, padding int) *taskFormatter {
@@ -185,28 +240,28 @@ func newTaskFormatter(apiClient client.APIClient, opts *logsOptions, padding int
 		opts:    opts,
 		padding: padding,
 		r:       idresolver.New(apiClient, opts.noResolve),
-		cache:   make(map[logContext]string),
+		cache:   make(map[logContext]logTask),
 	}
 }
 
-func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string, error) {
+func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (logTask, error) {
 	if cached, ok := f.cache[logCtx]; ok {
 		return cached, nil
 	}
 
 	nodeName, err := f.r.Resolve(ctx, swarm.Node{}, logCtx.nodeID)
 	if err != nil {
-		return "", err
+		return logTask{}, err
 	}
 
 	serviceName, err := f.r.Resolve(ctx, swarm.Service{}, logCtx.serviceID)
 	if err != nil {
-		return "", err
+		return logTask{}, err
 	}
 
 	task, _, err := f.client.TaskInspectWithRaw(ctx, logCtx.taskID)
 	if err != nil {
-		return "", err
+		return logTask{}, err
 	}
 
 	taskName := fmt.Sprintf("%s.%d", serviceName, task.Slot)
@@ -223,9 +278,19 @@ func (f *taskFormatter) format(ctx context.Context, logCtx logContext) (string,
 	if paddingCount > 0 {
 		padding = strings.Repeat(" ", paddingCount)
 	}
-	formatted := taskName + "@" + nodeName + padding
-	f.cache[logCtx] = formatted
-	return formatted, nil
+	formatted := taskName + "@" + nodeName + padding + "    |"
+	if f.color {
+		formatted = fmt.Sprintf("\x1b[%dm%s\x1b[0m", prefixColors[len(f.cache)%len(prefixColors)], formatted)
+	}
+	t := logTask{
+		service: serviceName,
+		task:    taskName,
+		node:    nodeName,
+		slot:    task.Slot,
+		prefix:  formatted + " ",
+	}
+	f.cache[logCtx] = t
+	return t, nil
 }
 
 type logWriter struct {
@@ -233,6 +298,26 @@ type logWriter struct {
 	opts *logsOptions
 	f    *taskFormatter
 	w    io.Writer
+	// stream is the name of the stream that is written, as shown by
+	// --raw-json
+	stream string
+	// groups collects the log lines if they're grouped by task or node
+	groups *logGroups
+}
+
+// jsonLogLine is a log line, as printed by --raw-json.
+type jsonLogLine struct {
+	Timestamp string            `json:"timestamp"`
+	Stream    string            `json:"stream"`
+	Service   string            `json:"service"`
+	ServiceID string            `json:"service_id"`
+	Task      string            `json:"task"`
+	TaskID    string            `json:"task_id"`
+	Slot      int               `json:"slot,omitempty"`
+	Node      string            `json:"node"`
+	NodeID    string            `json:"node_id"`
+	Details   map[string]string `json:"details,omitempty"`
+	Message   string            `json:"message"`
 }
 
 func (lw *logWriter) Write(buf []byte) (int, error) {
@@ -247,7 +332,7 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 	// spaces. if there is a timestamp, details will be 2nd (`index 1)
 	detailsIndex := 0
 	numParts := 2
-	if lw.opts.timestamps {
+	if lw.opts.timestamps || lw.opts.rawJSON {
 		detailsIndex++
 		numParts++
 	}
@@ -270,18 +355,45 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 		return 0, err
 	}
 
+	// add the context, nice and formatted
+	task, err := lw.f.format(lw.ctx, logCtx)
+	if err != nil {
+		return 0, err
+	}
+
+	if lw.opts.rawJSON {
+		line := jsonLogLine{
+			Timestamp: string(parts[0]),
+			Stream:    lw.stream,
+			Service:   task.service,
+			ServiceID: logCtx.serviceID,
+			Task:      task.task,
+			TaskID:    logCtx.taskID,
+			Slot:      task.slot,
+			Node:      task.node,
+			NodeID:    logCtx.nodeID,
+			Message:   strings.TrimSuffix(string(parts[detailsIndex+1]), "\n"),
+		}
+		if lw.opts.details && len(details) > 0 {
+			line.Details = details
+		}
+		output, err := json.Marshal(line)
+		if err != nil {
+			return 0, err
+		}
+		if err := lw.write(logCtx, task, append(output, '\n')); err != nil {
+			return 0, err
+		}
+		return len(buf), nil
+	}
+
 	output := []byte{}
 	// if we included timestamps, add them to the front
 	if lw.opts.timestamps {
 		output = append(output, parts[0]...)
 		output = append(output, ' ')
 	}
-	// add the context, nice and formatted
-	formatted, err := lw.f.format(lw.ctx, logCtx)
-	if err != nil {
-		return 0, err
-	}
-	output = append(output, []byte(formatted+"    | ")...)
+	output = append(output, []byte(task.prefix)...)
 	// if the user asked for details, add them to be log message
 	if lw.opts.details {
 		// ugh i hate this it's basically a dupe of api/server/httputils/write_log_stream.go:stringAttrs()
@@ -308,14 +420,80 @@ func (lw *logWriter) Write(buf []byte) (int, error) {
 	// add the log message itself, finally
 	output = append(output, parts[detailsIndex+1]...)
 
-	_, err = lw.w.Write(output)
-	if err != nil {
+	if err := lw.write(logCtx, task, output); err != nil {
 		return 0, err
 	}
-
 	return len(buf), nil
 }
 
+// write writes a formatted log line, or adds it to its group if the logs are
+// grouped.
+func (lw *logWriter) write(logCtx logContext, task logTask, line []byte) error {
+	if lw.groups == nil {
+		_, err := lw.w.Write(line)
+		return err
+	}
+	key, label := logCtx.taskID, task.task+"@"+task.node
+	if lw.opts.groupBy == groupByNode {
+		key, label = logCtx.nodeID, task.node
+	}
+	lw.groups.add(key, label, lw.w, line)
+	return nil
+}
+
+// logGroups collects the log lines of tasks or nodes, to print them grouped
+// once all logs are read.
+type logGroups struct {
+	groups []*logGroup
+	byKey  map[string]*logGroup
+}
+
+type logGroup struct {
+	label string
+	lines []logGroupLine
+}
+
+type logGroupLine struct {
+	w    io.Writer
+	line []byte
+}
+
+func (g *logGroups) add(key, label string, w io.Writer, line []byte) {
+	if g.byKey == nil {
+		g.byKey = make(map[string]*logGroup)
+	}
+	group, ok := g.byKey[key]
+	if !ok {
+		group = &logGroup{label: label}
+		g.byKey[key] = group
+		g.groups = append(g.groups, group)
+	}
+	group.lines = append(group.lines, logGroupLine{w: w, line: line})
+}
+
+// flush prints the groups sorted by their label, each with a header, and
+// the lines of each group in the order they were read.
+func (g *logGroups) flush(out io.Writer) error {
+	sort.SliceStable(g.groups, func(i, j int) bool {
+		return sortorder.NaturalLess(g.groups[i].label, g.groups[j].label)
+	})
+	for i, group := range g.groups {
+		header := "==> " + group.label + " <==\n"
+		if i > 0 {
+			header = "\n" + header
+		}
+		if _, err := io.WriteString(out, header); err != nil {
+			return err
+		}
+		for _, l := range group.lines {
+			if _, err := l.w.Write(l.line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // parseContext returns a log context and REMOVES the context from the details map
 func (lw *logWriter) parseContext(details map[string]string) (logContext, error) {
 	nodeID, ok := details["com.docker.swarm.node.id"]
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// serviceLogs returns the multiplexed logs of a service, with a line of
+// "task@node message" for each log line.
+func serviceLogs(t *testing.T, timestamps bool, lines ...string) io.ReadCloser {
+	t.Helper()
+	var buf bytes.Buffer
+	w := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+	for _, l := range lines {
+		ctx, msg, _ := strings.Cut(l, " ")
+		taskID, nodeID, _ := strings.Cut(ctx, "@")
+		line := "com.docker.swarm.node.id=" + nodeID + ",com.docker.swarm.service.id=svc,com.docker.swarm.task.id=" + taskID + " " + msg + "\n"
+		if timestamps {
+			line = "2024-01-02T03:04:05.000000000Z " + line
+		}
+		_, err := w.Write([]byte(line))
+		assert.NilError(t, err)
+	}
+	return io.NopCloser(&buf)
+}
+
+func newLogsFakeCli(logs func(options container.LogsOptions) io.ReadCloser) *test.FakeCli {
+	slots := map[string]int{"task1": 1, "task2": 2, "task10": 10}
+	return test.NewFakeCli(&fakeClient{
+		serviceInspectWithRawFunc: func(context.Context, string, types.ServiceInspectOptions) (swarm.Service, []byte, error) {
+			return swarm.Service{Spec: swarm.ServiceSpec{TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{}}}}, nil, nil
+		},
+		serviceLogsFunc: func(_ context.Context, _ string, options container.LogsOptions) (io.ReadCloser, error) {
+			return logs(options), nil
+		},
+		taskInspectWithRawFunc: func(_ context.Context, taskID string) (swarm.Task, []byte, error) {
+			return swarm.Task{ID: taskID, Slot: slots[taskID]}, nil, nil
+		},
+	})
+}
+
+func TestRunLogsGroupBy(t *testing.T) {
+	testCases := []struct {
+		groupBy  string
+		expected string
+	}{
+		{
+			groupBy: groupByTask,
+			expected: `==> svc.2.task2@node2 <==
+svc.2.task2@node2    | two
+svc.2.task2@node2    | two again
+
+==> svc.10.task10@node1 <==
+svc.10.task10@node1    | ten
+`,
+		},
+		{
+			groupBy: groupByNode,
+			expected: `==> node1 <==
+svc.10.task10@node1    | ten
+svc.1.task1@node1    | one
+
+==> node2 <==
+svc.2.task2@node2    | two
+svc.2.task2@node2    | two again
+`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.groupBy, func(t *testing.T) {
+			fakeCli := newLogsFakeCli(func(container.LogsOptions) io.ReadCloser {
+				lines := []string{"task10@node1 ten", "task2@node2 two", "task2@node2 two again"}
+				if tc.groupBy == groupByNode {
+					lines = append(lines, "task1@node1 one")
+				}
+				return serviceLogs(t, false, lines...)
+			})
+			err := runLogs(context.Background(), fakeCli, &logsOptions{target: "svc", noResolve: true, groupBy: tc.groupBy})
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
+
+func TestRunLogsRawJSON(t *testing.T) {
+	fakeCli := newLogsFakeCli(func(options container.LogsOptions) io.ReadCloser {
+		assert.Check(t, options.Timestamps)
+		assert.Check(t, options.Details)
+		return serviceLogs(t, true, "task1@node1 hello world")
+	})
+	err := runLogs(context.Background(), fakeCli, &logsOptions{target: "svc", noResolve: true, rawJSON: true})
+	assert.NilError(t, err)
+	expected := `{"timestamp":"2024-01-02T03:04:05.000000000Z","stream":"stdout","service":"svc","service_id":"svc","task":"svc.1.task1","task_id":"task1","slot":1,"node":"node1","node_id":"node1","message":"hello world"}` + "\n"
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), expected))
+}
+
+func TestRunLogsInvalidOptions(t *testing.T) {
+	testCases := []struct {
+		opts     logsOptions
+		expected string
+	}{
+		{opts: logsOptions{groupBy: "container"}, expected: `invalid value for --group-by: "container": must be "task" or "node"`},
+		{opts: logsOptions{groupBy: groupByTask, follow: true}, expected: "conflicting options: --group-by and --follow"},
+		{opts: logsOptions{raw: true, rawJSON: true}, expected: "--raw can't be used with --raw-json or --group-by"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		err := runLogs(context.Background(), test.NewFakeCli(&fakeClient{}), &tc.opts)
+		assert.Check(t, is.Error(err, tc.expected))
+	}
+}
@@ -961,6 +961,7 @@ const (
 	flagEnvFile                 = "env-file"
 	flagEnvRemove               = "env-rm"
 	flagEnvAdd                  = "env-add"
+	flagEnvMerge                = "env-merge"
 	flagGenericResourcesRemove  = "generic-resource-rm"
 	flagGenericResourcesAdd     = "generic-resource-add"
 	flagGroup                   = "group"
@@ -1005,6 +1006,7 @@ const (
 	flagRollbackMonitor         = "rollback-monitor"
 	flagRollbackOrder           = "rollback-order"
 	flagRollbackParallelism     = "rollback-parallelism"
+	flagShowDiff                = "show-diff"
 	flagInit                    = "init"
 	flagSysCtl                  = "sysctl"
 	flagSysCtlAdd               = "sysctl-add"
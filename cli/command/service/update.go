@@ -2,13 +2,16 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -67,6 +70,9 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.Var(&options.labels, flagLabelAdd, "Add or update a service label")
 	flags.Var(&options.containerLabels, flagContainerLabelAdd, "Add or update a container label")
 	flags.Var(&options.env, flagEnvAdd, "Add or update an environment variable")
+	flags.Var(&options.envFile, flagEnvFile, "Add or update the environment variables of a file")
+	flags.Var(newListOptsVarWithValidator(validateEnvMerge), flagEnvMerge, `Merge an environment variable with the existing ones ("KEY=VALUE", or "KEY-" to remove it)`)
+	flags.Bool(flagShowDiff, false, "Show the changes to the service specification before updating")
 	flags.Var(newListOptsVar(), flagSecretRemove, "Remove a secret")
 	flags.SetAnnotation(flagSecretRemove, "version", []string{"1.25"})
 	flags.Var(&options.secrets, flagSecretAdd, "Add or update a secret on a service")
@@ -115,6 +121,10 @@ func newUpdateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.Var(newListOptsVarWithValidator(ValidateSingleGenericResource), flagGenericResourcesAdd, "Add a Generic resource")
 	flags.SetAnnotation(flagHostAdd, "version", []string{"1.32"})
 
+	_ = cmd.RegisterFlagCompletionFunc(flagCapAdd, completion.CapabilitiesAddable)
+	_ = cmd.RegisterFlagCompletionFunc(flagCapDrop, completion.CapabilitiesDroppable)
+	_ = cmd.RegisterFlagCompletionFunc(flagNetworkAdd, completion.NetworkNames(dockerCli))
+	_ = cmd.RegisterFlagCompletionFunc(flagNetworkRemove, completion.NetworkNames(dockerCli))
 	return cmd
 }
 
@@ -140,6 +150,16 @@ func runUpdate(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet,
 		return err
 	}
 
+	// The spec of the service is updated in place, so a copy of the original
+	// spec is kept to show the changes.
+	showDiff, _ := flags.GetBool(flagShowDiff)
+	var originalSpec []byte
+	if showDiff {
+		if originalSpec, err = json.Marshal(service.Spec); err != nil {
+			return err
+		}
+	}
+
 	// There are two ways to do user-requested rollback. The old way is
 	// client-side, but with a sufficiently recent daemon we prefer
 	// server-side, because it will honor the rollback parameters.
@@ -234,6 +254,12 @@ func runUpdate(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet,
 		updateOpts.RegistryAuthFrom = types.RegistryAuthFromSpec
 	}
 
+	if showDiff {
+		if err := printSpecDiff(dockerCli.Out(), dockerCli.Err(), originalSpec, *spec); err != nil {
+			return err
+		}
+	}
+
 	response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, *spec, updateOpts)
 	if err != nil {
 		return err
@@ -339,7 +365,9 @@ func updateService(ctx context.Context, apiClient client.NetworkAPIClient, flags
 	updateString("image", &cspec.Image)
 	updateStringToSlice(flags, "args", &cspec.Args)
 	updateStringToSlice(flags, flagEntrypoint, &cspec.Command)
-	updateEnvironment(flags, &cspec.Env)
+	if err := updateEnvironment(flags, &cspec.Env); err != nil {
+		return err
+	}
 	updateString(flagWorkdir, &cspec.Dir)
 	updateString(flagUser, &cspec.User)
 	updateString(flagHostname, &cspec.Hostname)
@@ -740,26 +768,65 @@ func updateUlimits(flags *pflag.FlagSet, ulimits []*container.Ulimit) []*contain
 	return limits
 }
 
-func updateEnvironment(flags *pflag.FlagSet, field *[]string) {
+// updateEnvironment updates the environment variables of field. The variables
+// of --env-rm are removed first, then the variables of --env-file, --env-add,
+// and --env-merge are merged, in that order. Existing variables keep their
+// position, and new variables are added in the order they're given.
+func updateEnvironment(flags *pflag.FlagSet, field *[]string) error {
 	toRemove := buildToRemoveSet(flags, flagEnvRemove)
 	*field = removeItems(*field, toRemove, envKey)
 
-	if flags.Changed(flagEnvAdd) {
-		envSet := map[string]string{}
-		for _, v := range *field {
-			envSet[envKey(v)] = v
+	var merge []string
+	if flags.Changed(flagEnvFile) {
+		files := flags.Lookup(flagEnvFile).Value.(*opts.ListOpts).GetAll()
+		fromFiles, err := opts.ReadKVEnvStrings(files, nil)
+		if err != nil {
+			return err
 		}
+		merge = append(merge, fromFiles...)
+	}
+	if flags.Changed(flagEnvAdd) {
+		merge = append(merge, flags.Lookup(flagEnvAdd).Value.(*opts.ListOpts).GetAll()...)
+	}
+	if flags.Changed(flagEnvMerge) {
+		merge = append(merge, flags.Lookup(flagEnvMerge).Value.(*opts.ListOpts).GetAll()...)
+	}
+	if len(merge) == 0 {
+		return nil
+	}
 
-		value := flags.Lookup(flagEnvAdd).Value.(*opts.ListOpts)
-		for _, v := range value.GetAll() {
-			envSet[envKey(v)] = v
+	env := append([]string{}, *field...)
+	index := make(map[string]int, len(env))
+	for i, v := range env {
+		index[envKey(v)] = i
+	}
+	removed := make(map[string]struct{})
+	for _, v := range merge {
+		if key, ok := strings.CutSuffix(v, "-"); ok && !strings.Contains(v, "=") {
+			removed[key] = struct{}{}
+			continue
 		}
-
-		*field = []string{}
-		for _, v := range envSet {
-			*field = append(*field, v)
+		key := envKey(v)
+		delete(removed, key)
+		if i, ok := index[key]; ok {
+			env[i] = v
+			continue
 		}
+		index[key] = len(env)
+		env = append(env, v)
 	}
+	*field = removeItems(env, removed, envKey)
+	return nil
+}
+
+// validateEnvMerge validates a value of --env-merge, which is an environment
+// variable as accepted by --env, or the name of a variable followed by "-" to
+// remove it.
+func validateEnvMerge(val string) (string, error) {
+	if key, ok := strings.CutSuffix(val, "-"); ok && key != "" && !strings.Contains(val, "=") {
+		return val, nil
+	}
+	return opts.ValidateEnv(val)
 }
 
 func getUpdatedSecrets(ctx context.Context, apiClient client.SecretAPIClient, flags *pflag.FlagSet, secrets []*swarm.SecretReference) ([]*swarm.SecretReference, error) {
@@ -1518,3 +1585,94 @@ func capsList(caps map[string]bool) []string {
 	sort.Strings(out)
 	return out
 }
+
+// printSpecDiff prints the differences between the original spec of a service,
+// encoded as JSON, and its updated spec, one field per line. Lists of strings,
+// such as environment variables, are compared as sets.
+func printSpecDiff(out, errOut io.Writer, original []byte, spec swarm.ServiceSpec) error {
+	updated, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	var before, after any
+	if err := json.Unmarshal(original, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(updated, &after); err != nil {
+		return err
+	}
+	oldFields, newFields := map[string]string{}, map[string]string{}
+	flattenSpec("", before, oldFields)
+	flattenSpec("", after, newFields)
+
+	paths := make([]string, 0, len(oldFields)+len(newFields))
+	for p := range oldFields {
+		paths = append(paths, p)
+	}
+	for p := range newFields {
+		if _, ok := oldFields[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var diff []string
+	for _, p := range paths {
+		oldValue, inOld := oldFields[p]
+		newValue, inNew := newFields[p]
+		switch {
+		case !inOld:
+			diff = append(diff, fmt.Sprintf("+ %s: %s", setPath(p), newValue))
+		case !inNew:
+			diff = append(diff, fmt.Sprintf("- %s: %s", setPath(p), oldValue))
+		case oldValue != newValue:
+			diff = append(diff, fmt.Sprintf("~ %s: %s -> %s", p, oldValue, newValue))
+		}
+	}
+	if len(diff) == 0 {
+		_, _ = fmt.Fprintln(errOut, "No changes to the service specification")
+		return nil
+	}
+	for _, line := range diff {
+		_, _ = fmt.Fprintln(out, line)
+	}
+	return nil
+}
+
+// flattenSpec adds the fields of the JSON value v to fields, by path. The
+// values of lists of strings are part of their path, after a "=" separator,
+// so that they're compared as sets; other lists are indexed.
+func flattenSpec(path string, v any, fields map[string]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			flattenSpec(join(key), value, fields)
+		}
+	case []any:
+		for i, value := range v {
+			if s, ok := value.(string); ok {
+				fields[path+"="+s] = s
+				continue
+			}
+			flattenSpec(fmt.Sprintf("%s[%d]", path, i), value, fields)
+		}
+	case nil:
+	case string:
+		fields[path] = v
+	default:
+		b, _ := json.Marshal(v)
+		fields[path] = string(b)
+	}
+}
+
+// setPath returns the path of a field without the value of a list of strings.
+func setPath(path string) string {
+	p, _, _ := strings.Cut(path, "=")
+	return p
+}
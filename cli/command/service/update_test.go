@@ -1,8 +1,12 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"testing"
@@ -170,6 +174,54 @@ func TestUpdateEnvironmentWithDuplicateKeys(t *testing.T) {
 	assert.Check(t, is.Equal("A=b", envs[0]))
 }
 
+func TestUpdateEnvironmentMerge(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "env")
+	assert.NilError(t, os.WriteFile(envFile, []byte("FROM_FILE=1\nB=file\n"), 0o644))
+
+	flags := newUpdateCommand(nil).Flags()
+	assert.NilError(t, flags.Set("env-file", envFile))
+	assert.NilError(t, flags.Set("env-merge", "B=merged"))
+	assert.NilError(t, flags.Set("env-merge", "C-"))
+	assert.NilError(t, flags.Set("env-merge", "D=new"))
+	assert.NilError(t, flags.Set("env-rm", "E"))
+
+	envs := []string{"A=1", "B=2", "C=3", "E=5"}
+	assert.NilError(t, updateEnvironment(flags, &envs))
+	assert.Check(t, is.DeepEqual(envs, []string{"A=1", "B=merged", "FROM_FILE=1", "D=new"}))
+}
+
+func TestUpdateEnvironmentMergeInvalid(t *testing.T) {
+	flags := newUpdateCommand(nil).Flags()
+	assert.Check(t, is.ErrorContains(flags.Set("env-merge", "=value"), "invalid environment variable"))
+}
+
+func TestPrintSpecDiff(t *testing.T) {
+	replicas := uint64(1)
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{Name: "web"},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{Image: "nginx:1.24", Env: []string{"A=1", "B=2"}},
+		},
+		Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+	}
+	original, err := json.Marshal(spec)
+	assert.NilError(t, err)
+
+	var out, errOut bytes.Buffer
+	assert.NilError(t, printSpecDiff(&out, &errOut, original, spec))
+	assert.Check(t, is.Equal(out.String(), ""))
+	assert.Check(t, is.Equal(errOut.String(), "No changes to the service specification\n"))
+
+	spec.TaskTemplate.ContainerSpec = &swarm.ContainerSpec{Image: "nginx:1.25", Env: []string{"B=2", "C=3"}}
+	out.Reset()
+	assert.NilError(t, printSpecDiff(&out, &errOut, original, spec))
+	expected := `- TaskTemplate.ContainerSpec.Env: A=1
++ TaskTemplate.ContainerSpec.Env: C=3
+~ TaskTemplate.ContainerSpec.Image: nginx:1.24 -> nginx:1.25
+`
+	assert.Check(t, is.Equal(out.String(), expected))
+}
+
 func TestUpdateGroups(t *testing.T) {
 	flags := newUpdateCommand(nil).Flags()
 	flags.Set("group-add", "wheel")
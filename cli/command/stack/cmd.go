@@ -35,6 +35,7 @@ func NewStackCommand(dockerCli command.Cli) *cobra.Command {
 		newListCommand(dockerCli),
 		newPsCommand(dockerCli),
 		newRemoveCommand(dockerCli),
+		newRolloutCommand(dockerCli),
 		newServicesCommand(dockerCli),
 		newConfigCommand(dockerCli),
 	)
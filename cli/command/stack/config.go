@@ -2,6 +2,8 @@ package stack
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -26,8 +28,14 @@ func newConfigCommand(dockerCli command.Cli) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if !cmd.Flags().Changed("profile") {
+				opts.Profiles = profilesFromEnv()
+			}
+			if opts.ValidateOnly {
+				return loader.Validate(configDetails, opts.Profiles)
+			}
 
-			cfg, err := outputConfig(configDetails, opts.SkipInterpolation)
+			cfg, err := outputConfig(configDetails, opts.SkipInterpolation, opts.Profiles)
 			if err != nil {
 				return err
 			}
@@ -41,13 +49,28 @@ func newConfigCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
 	flags.BoolVar(&opts.SkipInterpolation, "skip-interpolation", false, "Skip interpolation and output only merged config")
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Enable the services of a profile (default: the profiles of the COMPOSE_PROFILES environment variable)")
+	flags.BoolVar(&opts.ValidateOnly, "validate-only", false, "Only validate the Compose files, without printing the config")
 	return cmd
 }
 
+// profilesFromEnv returns the profiles to enable of the COMPOSE_PROFILES
+// environment variable, which is a comma-separated list of profiles.
+func profilesFromEnv() []string {
+	var profiles []string
+	for _, p := range strings.Split(os.Getenv("COMPOSE_PROFILES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
 // outputConfig returns the merged and interpolated config file
-func outputConfig(configFiles composetypes.ConfigDetails, skipInterpolation bool) (string, error) {
+func outputConfig(configFiles composetypes.ConfigDetails, skipInterpolation bool, profiles []string) (string, error) {
 	optsFunc := func(opts *composeLoader.Options) {
 		opts.SkipInterpolation = skipInterpolation
+		opts.Profiles = profiles
 	}
 	config, err := composeLoader.Load(configFiles, optsFunc)
 	if err != nil {
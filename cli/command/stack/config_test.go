@@ -90,7 +90,7 @@ services:
 				Environment: map[string]string{
 					"VERSION": "1.0",
 				},
-			}, tc.skipInterpolation)
+			}, tc.skipInterpolation, nil)
 			assert.Check(t, err)
 			assert.Equal(t, tc.expected, actual)
 		})
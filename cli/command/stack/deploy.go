@@ -22,6 +22,9 @@ func newDeployCommand(dockerCli command.Cli) *cobra.Command {
 			if err := validateStackName(opts.Namespace); err != nil {
 				return err
 			}
+			if !cmd.Flags().Changed("profile") {
+				opts.Profiles = profilesFromEnv()
+			}
 			config, err := loader.LoadComposefile(dockerCli, opts)
 			if err != nil {
 				return err
@@ -36,6 +39,7 @@ func newDeployCommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	flags.StringSliceVarP(&opts.Composefiles, "compose-file", "c", []string{}, `Path to a Compose file, or "-" to read from stdin`)
 	flags.SetAnnotation("compose-file", "version", []string{"1.25"})
+	flags.StringSliceVar(&opts.Profiles, "profile", nil, "Enable the services of a profile (default: the profiles of the COMPOSE_PROFILES environment variable)")
 	flags.BoolVar(&opts.SendRegistryAuth, "with-registry-auth", false, "Send registry authentication details to Swarm agents")
 	flags.BoolVar(&opts.Prune, "prune", false, "Prune services that are no longer referenced")
 	flags.SetAnnotation("prune", "version", []string{"1.27"})
@@ -44,5 +48,6 @@ func newDeployCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("resolve-image", "version", []string{"1.30"})
 	flags.BoolVarP(&opts.Detach, "detach", "d", true, "Exit immediately instead of waiting for the stack services to converge")
 	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress progress output")
+	flags.BoolVar(&opts.Diff, "diff", false, "Show the changes to the deployed stack, without applying them")
 	return cmd
 }
@@ -68,3 +68,92 @@ func (s *stackContext) Name() string {
 func (s *stackContext) Services() string {
 	return strconv.Itoa(s.s.Services)
 }
+
+const (
+	// SwarmStackRolloutTableFormat is the default format of the rollout status
+	// of the services of a Swarm stack
+	SwarmStackRolloutTableFormat formatter.Format = "table {{.Name}}\t{{.Mode}}\t{{.Replicas}}\t{{.State}}\t{{.Error}}"
+
+	rolloutModeHeader     = "MODE"
+	rolloutReplicasHeader = "REPLICAS"
+	rolloutStateHeader    = "STATE"
+	rolloutErrorHeader    = "ERROR"
+)
+
+// ServiceRollout contains the rollout status of a service of a stack.
+type ServiceRollout struct {
+	// Name is the name of the service
+	Name string
+	// Mode is the mode of the service
+	Mode string
+	// Ready is the number of up-to-date tasks that are running, or that
+	// completed for jobs
+	Ready uint64
+	// Total is the number of tasks the service should have
+	Total uint64
+	// State is the state of the rollout
+	State string
+	// Error is the error of the most recent failed task of the service, or
+	// the message of its update status if it failed
+	Error string
+	// Failed is set if the rollout of the service failed
+	Failed bool
+	// Done is set if the rollout of the service converged, or failed and
+	// won't make further progress
+	Done bool
+}
+
+// RolloutWrite writes the formatted rollout status of services using the
+// Context
+func RolloutWrite(ctx formatter.Context, rollouts []*ServiceRollout) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, r := range rollouts {
+			if err := format(&rolloutContext{r: r}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newRolloutContext(), render)
+}
+
+type rolloutContext struct {
+	formatter.HeaderContext
+	r *ServiceRollout
+}
+
+func newRolloutContext() *rolloutContext {
+	rolloutCtx := rolloutContext{}
+	rolloutCtx.Header = formatter.SubHeaderContext{
+		"Name":     formatter.NameHeader,
+		"Mode":     rolloutModeHeader,
+		"Replicas": rolloutReplicasHeader,
+		"State":    rolloutStateHeader,
+		"Error":    rolloutErrorHeader,
+	}
+	return &rolloutCtx
+}
+
+func (c *rolloutContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *rolloutContext) Name() string {
+	return c.r.Name
+}
+
+func (c *rolloutContext) Mode() string {
+	return c.r.Mode
+}
+
+func (c *rolloutContext) Replicas() string {
+	return strconv.FormatUint(c.r.Ready, 10) + "/" + strconv.FormatUint(c.r.Total, 10)
+}
+
+func (c *rolloutContext) State() string {
+	return c.r.State
+}
+
+func (c *rolloutContext) Error() string {
+	return c.r.Error
+}
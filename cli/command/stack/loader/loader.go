@@ -8,8 +8,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli/command"
@@ -28,14 +30,9 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 	}
 
 	dicts := getDictsFrom(configDetails.ConfigFiles)
-	config, err := loader.Load(configDetails)
+	config, err := loader.Load(configDetails, withProfiles(opts.Profiles))
 	if err != nil {
-		if fpe, ok := err.(*loader.ForbiddenPropertiesError); ok {
-			// this error is intentionally formatted multi-line
-			return nil, errors.Errorf("Compose file contains unsupported options:\n\n%s\n", propertyWarnings(fpe.Properties))
-		}
-
-		return nil, err
+		return nil, loadError(err)
 	}
 
 	unsupportedProperties := loader.GetUnsupportedProperties(dicts...)
@@ -52,6 +49,144 @@ func LoadComposefile(dockerCli command.Cli, opts options.Deploy) (*composetypes.
 	return config, nil
 }
 
+func withProfiles(profiles []string) func(*loader.Options) {
+	return func(options *loader.Options) {
+		options.Profiles = profiles
+	}
+}
+
+func loadError(err error) error {
+	if fpe, ok := err.(*loader.ForbiddenPropertiesError); ok {
+		// this error is intentionally formatted multi-line
+		return errors.Errorf("Compose file contains unsupported options:\n\n%s\n", propertyWarnings(fpe.Properties))
+	}
+	return err
+}
+
+// Validate validates each of the Compose files of configDetails, then the
+// files merged, with the given profiles enabled. Errors of the schema
+// validation of a file are prefixed with the position of the invalid option,
+// as "FILE:LINE:COLUMN: ".
+func Validate(configDetails composetypes.ConfigDetails, profiles []string) error {
+	for _, file := range configDetails.ConfigFiles {
+		details := configDetails
+		details.ConfigFiles = []composetypes.ConfigFile{file}
+		if _, err := loader.Load(details, withProfiles(profiles)); err != nil {
+			return positionError(file.Filename, loadError(err))
+		}
+	}
+	_, err := loader.Load(configDetails, withProfiles(profiles))
+	return loadError(err)
+}
+
+// positionError prefixes a schema validation error with the position of the
+// invalid field in the file. The file is read again, as the position isn't
+// kept when parsing the file, so files read from stdin have no position.
+func positionError(filename string, err error) error {
+	var fieldErr interface{ Field() string }
+	if !errors.As(err, &fieldErr) || filename == "-" {
+		return err
+	}
+	source, readErr := os.ReadFile(filename)
+	if readErr != nil {
+		return err
+	}
+	field := fieldErr.Field()
+	if field == "(root)" {
+		field = ""
+	}
+	line, column := locate(source, field)
+	return errors.Errorf("%s:%d:%d: %s", filename, line, column, err)
+}
+
+// yamlKey matches a key of a block mapping, and the separator with its value.
+var yamlKey = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s"'#:-][^:#]*?|-[^\s:#][^:#]*?)\s*:(\s|$)`)
+
+// yamlNode is a key or a list item of a YAML document in block style.
+type yamlNode struct {
+	key    string
+	item   bool
+	line   int
+	column int
+}
+
+func parseYAMLNodes(source []byte) []yamlNode {
+	var nodes []yamlNode
+	for i, l := range strings.Split(string(source), "\n") {
+		column := 0
+		for {
+			text := strings.TrimLeft(l[column:], " ")
+			column = len(l) - len(text)
+			switch {
+			case text == "" || text[0] == '#' || strings.HasPrefix(text, "---"):
+			case text == "-" || strings.HasPrefix(text, "- "):
+				nodes = append(nodes, yamlNode{item: true, line: i + 1, column: column + 1})
+				column++
+				continue
+			default:
+				if m := yamlKey.FindStringSubmatch(text); m != nil {
+					nodes = append(nodes, yamlNode{key: strings.Trim(m[1], `"'`), line: i + 1, column: column + 1})
+				}
+			}
+			break
+		}
+	}
+	return nodes
+}
+
+// locate returns the position of the element at field in a YAML document,
+// as keys and list indexes separated by dots. Keys may contain dots, such as
+// the keys of labels, so the longest key that's a prefix of field is matched.
+// Only the block style is supported: the position of the deepest element of
+// field that's found is returned.
+func locate(source []byte, field string) (line, column int) {
+	nodes := parseYAMLNodes(source)
+	line, column = 1, 1
+	parent := yamlNode{column: 0}
+	start, end := 0, len(nodes)
+	for field != "" {
+		elem, rest, _ := strings.Cut(field, ".")
+		index, err := strconv.Atoi(elem)
+		isIndex := err == nil
+
+		// The children of the parent are the nodes with the indentation of
+		// the first node of its block; a list may have the indentation of
+		// the key it's the value of.
+		childColumn, n, found, next := -1, 0, -1, ""
+		for i := start; i < end; i++ {
+			node := nodes[i]
+			if node.column < parent.column || (node.column == parent.column && (!node.item || parent.item)) {
+				end = i
+				break
+			}
+			if childColumn == -1 {
+				childColumn = node.column
+			}
+			if node.column != childColumn {
+				continue
+			}
+			switch {
+			case node.item:
+				if isIndex && n == index {
+					found, next = i, rest
+				}
+				n++
+			case node.key == field || strings.HasPrefix(field, node.key+"."):
+				if found == -1 || len(node.key) > len(nodes[found].key) {
+					found, next = i, strings.TrimPrefix(field[len(node.key):], ".")
+				}
+			}
+		}
+		if found == -1 {
+			return line, column
+		}
+		parent = nodes[found]
+		line, column = parent.line, parent.column
+		start, field = found+1, next
+	}
+	return line, column
+}
+
 func getDictsFrom(configFiles []composetypes.ConfigFile) []map[string]any {
 	dicts := []map[string]any{}
 
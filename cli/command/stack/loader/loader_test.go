@@ -77,3 +77,68 @@ func TestBuildEnvironment(t *testing.T) {
 	assert.Check(t, is.Equal("LEGIT_VALUE", env["LEGIT_VAR"]))
 	assert.Check(t, is.Equal("", env["EMPTY_VARIABLE"]))
 }
+
+func TestValidate(t *testing.T) {
+	base := fs.NewFile(t, "test-validate-base", fs.WithContent(`version: "3.8"
+services:
+  web:
+    image: busybox
+`))
+	defer base.Remove()
+	override := fs.NewFile(t, "test-validate-override", fs.WithContent(`version: "3.8"
+services:
+  web:
+    ports:
+      - "80:80"
+      - target: 443
+        published: "https"
+`))
+	defer override.Remove()
+
+	details, err := GetConfigDetails([]string{base.Path()}, nil)
+	assert.NilError(t, err)
+	assert.Check(t, Validate(details, nil))
+
+	details, err = GetConfigDetails([]string{base.Path(), override.Path()}, nil)
+	assert.NilError(t, err)
+	err = Validate(details, nil)
+	assert.Check(t, is.Error(err, override.Path()+":7:9: services.web.ports.1.published must be a integer"))
+}
+
+func TestLocate(t *testing.T) {
+	const source = `version: "3.8"
+services:
+  # the web service
+  web:
+    image: busybox
+    ports:
+    - "80:80"
+    - target: 443
+      published: 443
+  "worker":
+    image: busybox
+    deploy: {replicas: 2}
+    labels:
+      com.example: worker
+      com.example.description: "the worker"
+`
+	tests := []struct {
+		path   string
+		line   int
+		column int
+	}{
+		{path: "services.web.image", line: 5, column: 5},
+		{path: "services.web.ports.0", line: 7, column: 5},
+		{path: "services.web.ports.1.published", line: 9, column: 7},
+		{path: "services.worker", line: 10, column: 3},
+		{path: "services.worker.deploy.replicas", line: 12, column: 5},
+		{path: "services.worker.labels.com.example.description", line: 15, column: 7},
+		{path: "services.db.image", line: 2, column: 1},
+		{path: "", line: 1, column: 1},
+	}
+	for _, tc := range tests {
+		line, column := locate([]byte(source), tc.path)
+		assert.Check(t, is.Equal(line, tc.line), tc.path)
+		assert.Check(t, is.Equal(column, tc.column), tc.path)
+	}
+}
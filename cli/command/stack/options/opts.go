@@ -11,12 +11,16 @@ type Deploy struct {
 	Prune            bool
 	Detach           bool
 	Quiet            bool
+	Diff             bool
+	Profiles         []string
 }
 
 // Config holds docker stack config options
 type Config struct {
 	Composefiles      []string
 	SkipInterpolation bool
+	Profiles          []string
+	ValidateOnly      bool
 }
 
 // List holds docker stack ls options
@@ -48,3 +52,11 @@ type Services struct {
 	Filter    opts.FilterOpt
 	Namespace string
 }
+
+// RolloutStatus holds docker stack rollout status options
+type RolloutStatus struct {
+	Namespace string
+	Format    string
+	Wait      bool
+	Quiet     bool
+}
@@ -0,0 +1,51 @@
+package stack
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/command/stack/swarm"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+func newRolloutCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Manage the rollout of stacks",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(newRolloutStatusCommand(dockerCli))
+	return cmd
+}
+
+func newRolloutStatusCommand(dockerCli command.Cli) *cobra.Command {
+	var opts options.RolloutStatus
+
+	cmd := &cobra.Command{
+		Use:   "status [OPTIONS] STACK",
+		Short: "Show the rollout status of the services of a stack",
+		Long: `Show the rollout status of the services of a stack: the number of up-to-date
+tasks that are ready, the state of the rollout, and the error of the most
+recent failed task of services that haven't converged.
+
+The command exits with status 1 if the rollout of a service failed.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Namespace = args[0]
+			if err := validateStackName(opts.Namespace); err != nil {
+				return err
+			}
+			return swarm.RunRolloutStatus(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeNames(dockerCli)(cmd, args, toComplete)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVarP(&opts.Wait, "wait", "w", false, "Wait for the services to converge, showing their progress")
+	flags.BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress progress output when waiting")
+	return cmd
+}
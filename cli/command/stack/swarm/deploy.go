@@ -33,6 +33,13 @@ func RunDeploy(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet,
 		opts.ResolveImage = ResolveImageNever
 	}
 
+	if opts.Diff {
+		if err := checkDaemonIsSwarmManager(ctx, dockerCli); err != nil {
+			return err
+		}
+		return diffCompose(ctx, dockerCli, opts, cfg)
+	}
+
 	if opts.Detach && !flags.Changed("detach") {
 		fmt.Fprintln(dockerCli.Err(), "Since --detach=false was not specified, tasks will be created in the background.\n"+
 			"In a future release, --detach=false will become the default.")
@@ -2,11 +2,9 @@ package swarm
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/docker/cli/cli/command"
-	servicecli "github.com/docker/cli/cli/command/service"
 	"github.com/docker/cli/cli/command/stack/options"
 	"github.com/docker/cli/cli/compose/convert"
 	composetypes "github.com/docker/cli/cli/compose/types"
@@ -63,8 +61,7 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts *options.Dep
 		return err
 	}
 
-	serviceIDs, err := deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage)
-	if err != nil {
+	if _, err := deployServices(ctx, dockerCli, services, namespace, opts.SendRegistryAuth, opts.ResolveImage); err != nil {
 		return err
 	}
 
@@ -72,7 +69,7 @@ func deployCompose(ctx context.Context, dockerCli command.Cli, opts *options.Dep
 		return nil
 	}
 
-	return waitOnServices(ctx, dockerCli, serviceIDs, opts.Quiet)
+	return waitOnRollout(ctx, dockerCli, namespace.Name(), opts.Quiet)
 }
 
 func getServicesDeclaredNetworks(serviceConfigs []composetypes.ServiceConfig) map[string]struct{} {
@@ -284,18 +281,3 @@ func deployServices(ctx context.Context, dockerCli command.Cli, services map[str
 
 	return serviceIDs, nil
 }
-
-func waitOnServices(ctx context.Context, dockerCli command.Cli, serviceIDs []string, quiet bool) error {
-	var errs []error
-	for _, serviceID := range serviceIDs {
-		if err := servicecli.WaitOnService(ctx, dockerCli, serviceID, quiet); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", serviceID, err))
-		}
-	}
-
-	if len(errs) > 0 {
-		return errors.Join(errs...)
-	}
-
-	return nil
-}
@@ -0,0 +1,270 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+)
+
+// diffCompose prints the differences between the stack of the Compose file
+// and the deployed stack, without changing anything.
+func diffCompose(ctx context.Context, dockerCli command.Cli, opts *options.Deploy, config *composetypes.Config) error {
+	apiClient := dockerCli.Client()
+	namespace := convert.NewNamespace(opts.Namespace)
+
+	var diff []string
+
+	serviceNetworks := getServicesDeclaredNetworks(config.Services)
+	networks, externalNetworks := convert.Networks(namespace, config.Networks, serviceNetworks)
+	if err := validateExternalNetworks(ctx, apiClient, externalNetworks); err != nil {
+		return err
+	}
+	existingNetworks, err := getStackNetworks(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return err
+	}
+	existingNetworkNames := make(map[string]bool, len(existingNetworks))
+	for _, nw := range existingNetworks {
+		existingNetworkNames[nw.Name] = true
+	}
+	var networkDiff []string
+	for name := range networks {
+		if !existingNetworkNames[name] {
+			networkDiff = append(networkDiff, "+ network "+name)
+		}
+	}
+	sort.Strings(networkDiff)
+	diff = append(diff, networkDiff...)
+
+	// Secrets and configs that don't exist yet are created by the deploy, and
+	// are listed to the services as if they existed already.
+	dc := &diffClient{APIClient: apiClient}
+	secrets, err := convert.Secrets(namespace, config.Secrets)
+	if err != nil {
+		return err
+	}
+	for _, spec := range secrets {
+		_, _, err := apiClient.SecretInspectWithRaw(ctx, spec.Name)
+		switch {
+		case errdefs.IsNotFound(err):
+			diff = append(diff, "+ secret "+spec.Name)
+			dc.secrets = append(dc.secrets, spec)
+		case err != nil:
+			return err
+		}
+	}
+	configs, err := convert.Configs(namespace, config.Configs)
+	if err != nil {
+		return err
+	}
+	for _, spec := range configs {
+		_, _, err := apiClient.ConfigInspectWithRaw(ctx, spec.Name)
+		switch {
+		case errdefs.IsNotFound(err):
+			diff = append(diff, "+ config "+spec.Name)
+			dc.configs = append(dc.configs, spec)
+		case err != nil:
+			return err
+		}
+	}
+	services, err := convert.Services(ctx, namespace, config, dc)
+	if err != nil {
+		return err
+	}
+
+	allNetworks, err := apiClient.NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		return err
+	}
+	networkNames := make(map[string]string, len(allNetworks))
+	for _, nw := range allNetworks {
+		networkNames[nw.ID] = nw.Name
+	}
+
+	existingServices, err := getStackServices(ctx, apiClient, namespace.Name())
+	if err != nil {
+		return err
+	}
+	existingServiceMap := make(map[string]swarm.Service, len(existingServices))
+	for _, s := range existingServices {
+		existingServiceMap[s.Spec.Name] = s
+	}
+	internalNames := make([]string, 0, len(services))
+	for internalName := range services {
+		internalNames = append(internalNames, internalName)
+	}
+	sort.Strings(internalNames)
+	for _, internalName := range internalNames {
+		name := namespace.Scope(internalName)
+		current, exists := existingServiceMap[name]
+		if !exists {
+			diff = append(diff, "+ service "+name)
+			continue
+		}
+		if changes := serviceSpecDiff(current.Spec, services[internalName], networkNames); len(changes) > 0 {
+			diff = append(diff, "~ service "+name)
+			for _, c := range changes {
+				diff = append(diff, "    "+c)
+			}
+		}
+	}
+	sort.Slice(existingServices, sortServiceByName(existingServices))
+	for _, s := range existingServices {
+		if _, ok := services[namespace.Descope(s.Spec.Name)]; ok {
+			continue
+		}
+		if opts.Prune {
+			diff = append(diff, "- service "+s.Spec.Name)
+		} else {
+			diff = append(diff, "  service "+s.Spec.Name+" is no longer referenced; use --prune to remove it")
+		}
+	}
+
+	if len(diff) == 0 {
+		dockerCli.Err().Infof("Stack %s is up to date\n", opts.Namespace)
+		return nil
+	}
+	for _, line := range diff {
+		_, _ = fmt.Fprintln(dockerCli.Out(), line)
+	}
+	return nil
+}
+
+// serviceSpecDiff returns the differences between the current and the desired
+// spec of a service, one per line. The networks of current are attached by
+// ID, and are shown by name using networkNames.
+func serviceSpecDiff(current, desired swarm.ServiceSpec, networkNames map[string]string) []string {
+	var diff []string
+
+	// The image of a deployed service may be pinned to the digest it was
+	// resolved to, so the image of the Compose file is compared instead.
+	currentImage := current.Labels[convert.LabelImage]
+	if currentImage == "" && current.TaskTemplate.ContainerSpec != nil {
+		currentImage = current.TaskTemplate.ContainerSpec.Image
+	}
+	var desiredImage string
+	var currentEnv, desiredEnv []string
+	if current.TaskTemplate.ContainerSpec != nil {
+		currentEnv = current.TaskTemplate.ContainerSpec.Env
+	}
+	if desired.TaskTemplate.ContainerSpec != nil {
+		desiredImage = desired.TaskTemplate.ContainerSpec.Image
+		desiredEnv = desired.TaskTemplate.ContainerSpec.Env
+	}
+	if currentImage != desiredImage {
+		diff = append(diff, fmt.Sprintf("~ image: %s -> %s", currentImage, desiredImage))
+	}
+
+	diff = append(diff, stringsDiff("env", currentEnv, desiredEnv)...)
+
+	currentMode, desiredMode := serviceModeDesc(current.Mode), serviceModeDesc(desired.Mode)
+	switch {
+	case currentMode != desiredMode:
+		diff = append(diff, fmt.Sprintf("~ mode: %s -> %s", currentMode, desiredMode))
+	case current.Mode.Replicated != nil && desired.Mode.Replicated != nil:
+		currentReplicas, desiredReplicas := replicasDesc(current.Mode.Replicated), replicasDesc(desired.Mode.Replicated)
+		if currentReplicas != desiredReplicas {
+			diff = append(diff, fmt.Sprintf("~ replicas: %s -> %s", currentReplicas, desiredReplicas))
+		}
+	}
+
+	var currentNetworks, desiredNetworks []string
+	for _, n := range current.TaskTemplate.Networks {
+		if name, ok := networkNames[n.Target]; ok {
+			currentNetworks = append(currentNetworks, name)
+		} else {
+			currentNetworks = append(currentNetworks, n.Target)
+		}
+	}
+	for _, n := range desired.TaskTemplate.Networks {
+		desiredNetworks = append(desiredNetworks, n.Target)
+	}
+	diff = append(diff, stringsDiff("network", currentNetworks, desiredNetworks)...)
+	return diff
+}
+
+func serviceModeDesc(mode swarm.ServiceMode) string {
+	switch {
+	case mode.Global != nil:
+		return "global"
+	case mode.ReplicatedJob != nil:
+		return "replicated job"
+	case mode.GlobalJob != nil:
+		return "global job"
+	default:
+		return "replicated"
+	}
+}
+
+func replicasDesc(mode *swarm.ReplicatedService) string {
+	if mode.Replicas == nil {
+		return "1"
+	}
+	return strconv.FormatUint(*mode.Replicas, 10)
+}
+
+// stringsDiff returns the removed and the added values of two lists.
+func stringsDiff(kind string, current, desired []string) []string {
+	current = append([]string(nil), current...)
+	desired = append([]string(nil), desired...)
+	sort.Strings(current)
+	sort.Strings(desired)
+	in := func(v string, list []string) bool {
+		i := sort.SearchStrings(list, v)
+		return i < len(list) && list[i] == v
+	}
+	var diff []string
+	for _, v := range current {
+		if !in(v, desired) {
+			diff = append(diff, fmt.Sprintf("- %s: %s", kind, v))
+		}
+	}
+	for _, v := range desired {
+		if !in(v, current) {
+			diff = append(diff, fmt.Sprintf("+ %s: %s", kind, v))
+		}
+	}
+	return diff
+}
+
+// diffClient is the API client that renders the services of a stack for
+// "docker stack deploy --diff". The secrets and configs of the stack that
+// don't exist yet are listed as if they were created, with their name as ID.
+type diffClient struct {
+	client.APIClient
+	secrets []swarm.SecretSpec
+	configs []swarm.ConfigSpec
+}
+
+func (c *diffClient) SecretList(ctx context.Context, options types.SecretListOptions) ([]swarm.Secret, error) {
+	list, err := c.APIClient.SecretList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range c.secrets {
+		list = append(list, swarm.Secret{ID: spec.Name, Spec: spec})
+	}
+	return list, nil
+}
+
+func (c *diffClient) ConfigList(ctx context.Context, options types.ConfigListOptions) ([]swarm.Config, error) {
+	list, err := c.APIClient.ConfigList(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range c.configs {
+		list = append(list, swarm.Config{ID: spec.Name, Spec: spec})
+	}
+	return list, nil
+}
@@ -0,0 +1,86 @@
+package swarm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/cli/compose/convert"
+	composetypes "github.com/docker/cli/cli/compose/types"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDiffCompose(t *testing.T) {
+	replicas := uint64(2)
+	newReplicas := uint64(3)
+	value := "1"
+	deployed := []swarm.Service{
+		{
+			ID: "ID-mystack_web",
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{
+					Name:   "mystack_web",
+					Labels: map[string]string{convert.LabelImage: "nginx:1.24"},
+				},
+				TaskTemplate: swarm.TaskSpec{
+					ContainerSpec: &swarm.ContainerSpec{
+						Image: "nginx:1.24@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+						Env:   []string{"A=1", "OLD=x"},
+					},
+					Networks: []swarm.NetworkAttachmentConfig{{Target: "ID-mystack_old"}},
+				},
+				Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+			},
+		},
+		{ID: "ID-mystack_old", Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "mystack_old"}}},
+	}
+	config := &composetypes.Config{
+		Services: []composetypes.ServiceConfig{
+			{
+				Name:        "web",
+				Image:       "nginx:1.25",
+				Environment: composetypes.MappingWithEquals{"A": &value},
+				Deploy:      composetypes.DeployConfig{Replicas: &newReplicas},
+			},
+			{Name: "worker", Image: "busybox"},
+		},
+	}
+
+	for _, prune := range []bool{false, true} {
+		fakeCli := test.NewFakeCli(&fakeClient{
+			version: "1.45",
+			serviceListFunc: func(types.ServiceListOptions) ([]swarm.Service, error) {
+				return deployed, nil
+			},
+			networkListFunc: func(network.ListOptions) ([]network.Summary, error) {
+				return []network.Summary{{ID: "ID-mystack_old", Name: "mystack_old"}}, nil
+			},
+			serviceUpdateFunc: func(string, swarm.Version, swarm.ServiceSpec, types.ServiceUpdateOptions) (swarm.ServiceUpdateResponse, error) {
+				t.Fatal("the stack must not be updated")
+				return swarm.ServiceUpdateResponse{}, nil
+			},
+		})
+
+		err := diffCompose(context.Background(), fakeCli, &options.Deploy{Namespace: "mystack", Diff: true, Prune: prune}, config)
+		assert.NilError(t, err)
+		removed := "  service mystack_old is no longer referenced; use --prune to remove it\n"
+		if prune {
+			removed = "- service mystack_old\n"
+		}
+		expected := `+ network mystack_default
+~ service mystack_web
+    ~ image: nginx:1.24 -> nginx:1.25
+    - env: OLD=x
+    ~ replicas: 2 -> 3
+    - network: mystack_old
+    + network: mystack_default
++ service mystack_worker
+` + removed
+		assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), expected))
+	}
+}
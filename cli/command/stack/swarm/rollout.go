@@ -0,0 +1,253 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	cliformatter "github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/service"
+	"github.com/docker/cli/cli/command/stack/formatter"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/pkg/errors"
+)
+
+// States of the rollout of a service.
+const (
+	rolloutConverged      = "converged"
+	rolloutCompleted      = "completed"
+	rolloutUpdating       = "updating"
+	rolloutPaused         = "paused"
+	rolloutRollingBack    = "rolling back"
+	rolloutRolledBack     = "rolled back"
+	rolloutRollbackPaused = "rollback paused"
+)
+
+// RunRolloutStatus is the swarm implementation of docker stack rollout status
+func RunRolloutStatus(ctx context.Context, dockerCli command.Cli, opts options.RolloutStatus) error {
+	if opts.Wait {
+		return waitOnRollout(ctx, dockerCli, opts.Namespace, opts.Quiet)
+	}
+
+	rollouts, err := getRolloutStatus(ctx, dockerCli.Client(), opts.Namespace)
+	if err != nil {
+		return err
+	}
+	if len(rollouts) == 0 {
+		return fmt.Errorf("nothing found in stack: %s", opts.Namespace)
+	}
+
+	format := formatter.Format(opts.Format)
+	if format == "" || format == formatter.TableFormatKey {
+		format = formatter.SwarmStackRolloutTableFormat
+	}
+	if err := formatter.RolloutWrite(formatter.Context{Output: dockerCli.Out(), Format: format}, rollouts); err != nil {
+		return err
+	}
+	for _, r := range rollouts {
+		if r.Failed {
+			return cli.StatusError{StatusCode: 1}
+		}
+	}
+	return nil
+}
+
+// getRolloutStatus returns the rollout status of the services of the stack,
+// sorted by name.
+func getRolloutStatus(ctx context.Context, apiClient client.APIClient, namespace string) ([]*formatter.ServiceRollout, error) {
+	services, err := apiClient.ServiceList(ctx, types.ServiceListOptions{Filters: getStackFilter(namespace), Status: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(services) == 0 {
+		return nil, nil
+	}
+	services, err = service.AppendServiceStatus(ctx, apiClient, services)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only tasks of the current version of the services count towards the
+	// rollout, so that tasks of a previous version don't make an update look
+	// converged before it has started.
+	taskFilter := getStackFilter(namespace)
+	taskFilter.Add("_up-to-date", "true")
+	tasks, err := apiClient.TaskList(ctx, types.TaskListOptions{Filters: taskFilter})
+	if err != nil {
+		return nil, err
+	}
+	tasksByService := make(map[string][]swarm.Task)
+	for _, t := range tasks {
+		tasksByService[t.ServiceID] = append(tasksByService[t.ServiceID], t)
+	}
+
+	rollouts := make([]*formatter.ServiceRollout, 0, len(services))
+	for _, s := range services {
+		rollouts = append(rollouts, serviceRollout(s, tasksByService[s.ID]))
+	}
+	sort.Slice(rollouts, func(i, j int) bool {
+		return rollouts[i].Name < rollouts[j].Name
+	})
+	return rollouts, nil
+}
+
+// serviceRollout returns the rollout status of service s, from its up-to-date
+// tasks.
+func serviceRollout(s swarm.Service, tasks []swarm.Task) *formatter.ServiceRollout {
+	r := &formatter.ServiceRollout{Name: s.Spec.Name}
+	isJob := s.Spec.Mode.ReplicatedJob != nil || s.Spec.Mode.GlobalJob != nil
+	switch {
+	case s.Spec.Mode.Global != nil:
+		r.Mode = "global"
+	case s.Spec.Mode.Replicated != nil:
+		r.Mode = "replicated"
+	case s.Spec.Mode.ReplicatedJob != nil:
+		r.Mode = "replicated job"
+	case s.Spec.Mode.GlobalJob != nil:
+		r.Mode = "global job"
+	}
+
+	if s.ServiceStatus != nil {
+		r.Total = s.ServiceStatus.DesiredTasks
+	}
+	if s.Spec.Mode.ReplicatedJob != nil && s.Spec.Mode.ReplicatedJob.TotalCompletions != nil {
+		r.Total = *s.Spec.Mode.ReplicatedJob.TotalCompletions
+	}
+
+	var lastFailed *swarm.Task
+	for i, t := range tasks {
+		switch {
+		case isJob && t.Status.State == swarm.TaskStateComplete:
+			r.Ready++
+		case !isJob && t.DesiredState == swarm.TaskStateRunning && t.Status.State == swarm.TaskStateRunning:
+			r.Ready++
+		case t.Status.Err != "" && (t.Status.State == swarm.TaskStateFailed || t.Status.State == swarm.TaskStateRejected):
+			if lastFailed == nil || t.Status.Timestamp.After(lastFailed.Status.Timestamp) {
+				lastFailed = &tasks[i]
+			}
+		}
+	}
+
+	r.State = rolloutUpdating
+	if s.UpdateStatus != nil {
+		switch s.UpdateStatus.State {
+		case swarm.UpdateStatePaused:
+			r.State, r.Failed, r.Done = rolloutPaused, true, true
+		case swarm.UpdateStateRollbackStarted:
+			r.State, r.Failed = rolloutRollingBack, true
+		case swarm.UpdateStateRollbackPaused:
+			r.State, r.Failed, r.Done = rolloutRollbackPaused, true, true
+		case swarm.UpdateStateRollbackCompleted:
+			r.State, r.Failed, r.Done = rolloutRolledBack, true, true
+		}
+	}
+	if !r.Failed && (s.UpdateStatus == nil || s.UpdateStatus.State == swarm.UpdateStateCompleted) && r.Ready >= r.Total {
+		r.State, r.Done = rolloutConverged, true
+		if isJob {
+			r.State = rolloutCompleted
+		}
+	}
+
+	if !r.Done || r.Failed {
+		if lastFailed != nil {
+			r.Error = cliformatter.FirstLine(lastFailed.Status.Err)
+		} else if r.Failed {
+			r.Error = cliformatter.FirstLine(s.UpdateStatus.Message)
+		}
+	}
+	return r
+}
+
+// waitOnRollout waits for the services of the stack to converge, writing the
+// rollout status of each service as it changes. An error is returned if the
+// rollout of a service failed.
+func waitOnRollout(ctx context.Context, dockerCli command.Cli, namespace string, quiet bool) error {
+	errChan := make(chan error, 1)
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		errChan <- rolloutProgress(ctx, dockerCli.Client(), namespace, pipeWriter)
+	}()
+
+	if quiet {
+		go io.Copy(io.Discard, pipeReader)
+		return <-errChan
+	}
+
+	err := jsonmessage.DisplayJSONMessagesToStream(pipeReader, dockerCli.Out(), nil)
+	if err == nil {
+		err = <-errChan
+	}
+	return err
+}
+
+// rolloutProgress outputs progress information for the rollout of the services
+// of the stack, until all of them converged or failed.
+func rolloutProgress(ctx context.Context, apiClient client.APIClient, namespace string, progressWriter io.WriteCloser) error {
+	defer progressWriter.Close()
+
+	progressOut := streamformatter.NewJSONProgressOutput(progressWriter, false)
+
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt)
+	defer signal.Stop(sigint)
+
+	written := make(map[string]progress.Progress)
+	for {
+		rollouts, err := getRolloutStatus(ctx, apiClient, namespace)
+		if err != nil {
+			return err
+		}
+
+		done := true
+		var failed []string
+		for _, r := range rollouts {
+			action := r.State
+			if r.Error != "" {
+				action += ": " + r.Error
+			}
+			p := progress.Progress{
+				ID:      r.Name,
+				Action:  action,
+				Current: int64(r.Ready),
+				Total:   int64(r.Total),
+				Units:   "tasks",
+			}
+			if written[r.Name] != p {
+				progressOut.WriteProgress(p)
+				written[r.Name] = p
+			}
+			done = done && r.Done
+			if r.Failed && r.Done {
+				failed = append(failed, r.Name)
+			}
+		}
+		if done {
+			if len(failed) > 0 {
+				return errors.Errorf("rollout of stack %s failed for services: %s", namespace, strings.Join(failed, ", "))
+			}
+			return nil
+		}
+
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-sigint:
+			progress.Message(progressOut, "", "Operation continuing in background.")
+			progress.Messagef(progressOut, "", "Use `docker stack rollout status %s` to check progress.", namespace)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package swarm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command/stack/formatter"
+	"github.com/docker/cli/cli/command/stack/options"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func replicatedService(name string, running, desired uint64, updateState swarm.UpdateState) swarm.Service {
+	replicas := desired
+	s := swarm.Service{
+		ID: "ID-" + name,
+		Spec: swarm.ServiceSpec{
+			Annotations: swarm.Annotations{Name: name},
+			Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+		},
+		ServiceStatus: &swarm.ServiceStatus{RunningTasks: running, DesiredTasks: desired},
+	}
+	if updateState != "" {
+		s.UpdateStatus = &swarm.UpdateStatus{State: updateState, Message: "update " + string(updateState)}
+	}
+	return s
+}
+
+func rolloutTask(serviceID string, state swarm.TaskState, err string, age time.Duration) swarm.Task {
+	return swarm.Task{
+		ServiceID:    serviceID,
+		DesiredState: swarm.TaskStateRunning,
+		Status: swarm.TaskStatus{
+			State:     state,
+			Err:       err,
+			Timestamp: time.Now().Add(-age),
+		},
+	}
+}
+
+func TestServiceRollout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		service  swarm.Service
+		tasks    []swarm.Task
+		expected formatter.ServiceRollout
+	}{
+		{
+			name:    "converged",
+			service: replicatedService("web", 2, 2, ""),
+			tasks: []swarm.Task{
+				rolloutTask("ID-web", swarm.TaskStateRunning, "", 0),
+				rolloutTask("ID-web", swarm.TaskStateRunning, "", 0),
+			},
+			expected: formatter.ServiceRollout{Name: "web", Mode: "replicated", Ready: 2, Total: 2, State: rolloutConverged, Done: true},
+		},
+		{
+			name:    "updating",
+			service: replicatedService("web", 2, 2, swarm.UpdateStateUpdating),
+			tasks: []swarm.Task{
+				rolloutTask("ID-web", swarm.TaskStateRunning, "", 0),
+				rolloutTask("ID-web", swarm.TaskStateFailed, "old failure", time.Minute),
+				rolloutTask("ID-web", swarm.TaskStateRejected, "no such image", time.Second),
+			},
+			expected: formatter.ServiceRollout{Name: "web", Mode: "replicated", Ready: 1, Total: 2, State: rolloutUpdating, Error: "no such image"},
+		},
+		{
+			name:     "paused",
+			service:  replicatedService("web", 1, 2, swarm.UpdateStatePaused),
+			expected: formatter.ServiceRollout{Name: "web", Mode: "replicated", Total: 2, State: rolloutPaused, Error: "update paused", Failed: true, Done: true},
+		},
+		{
+			name:     "rolling back",
+			service:  replicatedService("web", 2, 2, swarm.UpdateStateRollbackStarted),
+			expected: formatter.ServiceRollout{Name: "web", Mode: "replicated", Total: 2, State: rolloutRollingBack, Error: "update rollback_started", Failed: true},
+		},
+		{
+			name: "job",
+			service: swarm.Service{
+				Spec: swarm.ServiceSpec{
+					Annotations: swarm.Annotations{Name: "migrate"},
+					Mode:        swarm.ServiceMode{GlobalJob: &swarm.GlobalJob{}},
+				},
+				ServiceStatus: &swarm.ServiceStatus{DesiredTasks: 1, CompletedTasks: 1},
+			},
+			tasks:    []swarm.Task{rolloutTask("", swarm.TaskStateComplete, "", 0)},
+			expected: formatter.ServiceRollout{Name: "migrate", Mode: "global job", Ready: 1, Total: 1, State: rolloutCompleted, Done: true},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Check(t, is.DeepEqual(*serviceRollout(tc.service, tc.tasks), tc.expected))
+		})
+	}
+}
+
+func TestRunRolloutStatus(t *testing.T) {
+	var taskFilters []string
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(options types.ServiceListOptions) ([]swarm.Service, error) {
+			assert.Check(t, options.Status)
+			return []swarm.Service{
+				replicatedService("mystack_web", 1, 1, ""),
+				replicatedService("mystack_api", 0, 1, swarm.UpdateStateRollbackCompleted),
+			}, nil
+		},
+		taskListFunc: func(options types.TaskListOptions) ([]swarm.Task, error) {
+			taskFilters = options.Filters.Get("_up-to-date")
+			return []swarm.Task{
+				rolloutTask("ID-mystack_web", swarm.TaskStateRunning, "", 0),
+				rolloutTask("ID-mystack_api", swarm.TaskStateFailed, "task: non-zero exit (1)", 0),
+			}, nil
+		},
+	})
+
+	err := RunRolloutStatus(context.Background(), fakeCli, options.RolloutStatus{Namespace: "mystack"})
+	assert.Check(t, is.DeepEqual(err, cli.StatusError{StatusCode: 1}))
+	assert.Check(t, is.DeepEqual(taskFilters, []string{"true"}))
+	expected := "NAME          MODE         REPLICAS   STATE         ERROR\n" +
+		"mystack_api   replicated   0/1        rolled back   task: non-zero exit (1)\n" +
+		"mystack_web   replicated   1/1        converged     \n"
+	assert.Check(t, is.Equal(fakeCli.OutBuffer().String(), expected))
+}
+
+func TestWaitOnRolloutFailed(t *testing.T) {
+	var calls int
+	fakeCli := test.NewFakeCli(&fakeClient{
+		serviceListFunc: func(types.ServiceListOptions) ([]swarm.Service, error) {
+			calls++
+			if calls == 1 {
+				return []swarm.Service{replicatedService("mystack_web", 0, 1, swarm.UpdateStateUpdating)}, nil
+			}
+			return []swarm.Service{replicatedService("mystack_web", 0, 1, swarm.UpdateStatePaused)}, nil
+		},
+	})
+
+	err := waitOnRollout(context.Background(), fakeCli, "mystack", true)
+	assert.Check(t, is.Error(err, "rollout of stack mystack failed for services: mystack_web"))
+	assert.Check(t, is.Equal(calls, 2))
+}
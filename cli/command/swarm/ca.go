@@ -2,16 +2,25 @@ package swarm
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/swarm/progress"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-units"
+	"github.com/fvbommel/sortorder"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -22,6 +31,7 @@ type caOptions struct {
 	rootCACert PEMFile
 	rootCAKey  PEMFile
 	rotate     bool
+	status     bool
 	detach     bool
 	quiet      bool
 }
@@ -46,6 +56,7 @@ func newCACommand(dockerCli command.Cli) *cobra.Command {
 	flags := cmd.Flags()
 	addSwarmCAFlags(flags, &opts.swarmCAOptions)
 	flags.BoolVar(&opts.rotate, flagRotate, false, "Rotate the swarm CA - if no certificate or key are provided, new ones will be generated")
+	flags.BoolVar(&opts.status, flagStatus, false, "Display the expiry of the root CA, and the rotation status of the nodes")
 	flags.Var(&opts.rootCACert, flagCACert, "Path to the PEM-formatted root CA certificate to use for the new cluster")
 	flags.Var(&opts.rootCAKey, flagCAKey, "Path to the PEM-formatted root CA key to use for the new cluster")
 
@@ -62,13 +73,23 @@ func runCA(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet, opt
 		return err
 	}
 
+	if opts.status && opts.rotate {
+		return fmt.Errorf("`--%s` flag can't be combined with the `--%s` flag", flagStatus, flagRotate)
+	}
 	if !opts.rotate {
 		for _, f := range []string{flagCACert, flagCAKey, flagCertExpiry, flagExternalCA} {
 			if flags.Changed(f) {
 				return fmt.Errorf("`--%s` flag requires the `--rotate` flag to update the CA", f)
 			}
 		}
-		return displayTrustRoot(dockerCli.Out(), swarmInspect)
+		if opts.status {
+			return displayCAStatus(ctx, dockerCli, swarmInspect)
+		}
+		if err := displayTrustRoot(dockerCli.Out(), swarmInspect); err != nil {
+			return err
+		}
+		printCAExpiryWarnings(dockerCli.Err(), swarmInspect, nil)
+		return nil
 	}
 
 	if flags.Changed(flagExternalCA) && len(opts.externalCA.Value()) > 0 && !flags.Changed(flagCACert) {
@@ -142,3 +163,110 @@ func displayTrustRoot(out io.Writer, info swarm.Swarm) error {
 	fmt.Fprintln(out, strings.TrimSpace(info.ClusterInfo.TLSInfo.TrustRoot))
 	return nil
 }
+
+// caExpiryWarningPeriod is the period before the expiry of the root CA
+// certificate in which swarm commands print a warning.
+const caExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// displayCAStatus prints the expiry of the root CA certificate, the progress of
+// a root rotation, and the TLS status of each node.
+func displayCAStatus(ctx context.Context, dockerCli command.Cli, info swarm.Swarm) error {
+	nodes, err := dockerCli.Client().NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return sortorder.NaturalLess(nodes[i].Description.Hostname, nodes[j].Description.Hostname)
+	})
+
+	out := dockerCli.Out()
+	fmt.Fprintln(out, "Root CA:")
+	if rootCA, err := parseTrustRoot(info); err != nil {
+		fmt.Fprintf(out, " Error: %v\n", err)
+	} else {
+		fmt.Fprintf(out, " Subject: %s\n", rootCA.Subject)
+		fmt.Fprintf(out, " Expires: %s\n", expiryDesc(rootCA.NotAfter, time.Now()))
+	}
+	var rotated int
+	for _, n := range nodes {
+		if nodeTLSStatus(n, info) == "Ready" {
+			rotated++
+		}
+	}
+	if info.ClusterInfo.RootRotationInProgress {
+		fmt.Fprintf(out, " Rotation: in progress (%d/%d nodes)\n", rotated, len(nodes))
+	} else {
+		fmt.Fprintln(out, " Rotation: none")
+	}
+	fmt.Fprintln(out, "Node certificates:")
+	fmt.Fprintf(out, " Expiry Duration: %s\n", units.HumanDuration(info.Spec.CAConfig.NodeCertExpiry))
+	fmt.Fprintln(out)
+
+	w := tabwriter.NewWriter(out, 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tHOSTNAME\tSTATUS\tTLS STATUS")
+	for _, n := range nodes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", n.ID, n.Description.Hostname, command.PrettyPrint(n.Status.State), nodeTLSStatus(n, info))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	printCAExpiryWarnings(dockerCli.Err(), info, nodes)
+	return nil
+}
+
+// nodeTLSStatus returns whether the certificate of a node is issued by the
+// current root CA, and the node trusts it, as shown by "docker node ls".
+func nodeTLSStatus(n swarm.Node, info swarm.Swarm) string {
+	if reflect.DeepEqual(info.ClusterInfo.TLSInfo, swarm.TLSInfo{}) || reflect.DeepEqual(n.Description.TLSInfo, swarm.TLSInfo{}) {
+		return "Unknown"
+	}
+	if reflect.DeepEqual(n.Description.TLSInfo, info.ClusterInfo.TLSInfo) {
+		return "Ready"
+	}
+	return "Needs Rotation"
+}
+
+func parseTrustRoot(info swarm.Swarm) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(info.ClusterInfo.TLSInfo.TrustRoot))
+	if block == nil {
+		return nil, errors.New("No CA information available")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func expiryDesc(notAfter, now time.Time) string {
+	if notAfter.Before(now) {
+		return fmt.Sprintf("%s (expired %s ago)", notAfter.UTC().Format(time.RFC3339), units.HumanDuration(now.Sub(notAfter)))
+	}
+	return fmt.Sprintf("%s (in %s)", notAfter.UTC().Format(time.RFC3339), units.HumanDuration(notAfter.Sub(now)))
+}
+
+// printCAExpiryWarnings prints a warning if the root CA certificate of the
+// swarm expires soon, and for each of nodes that has been down for longer than
+// the validity period of node certificates, as its certificate may have
+// expired.
+func printCAExpiryWarnings(out io.Writer, info swarm.Swarm, nodes []swarm.Node) {
+	for _, warning := range caExpiryWarnings(info, nodes, time.Now()) {
+		fmt.Fprintln(out, "WARNING: "+warning)
+	}
+}
+
+func caExpiryWarnings(info swarm.Swarm, nodes []swarm.Node, now time.Time) []string {
+	var warnings []string
+	if rootCA, err := parseTrustRoot(info); err == nil {
+		switch {
+		case rootCA.NotAfter.Before(now):
+			warnings = append(warnings, fmt.Sprintf("the root CA certificate of the swarm expired on %s; nodes can no longer renew their certificates. Rotate the root CA with \"docker swarm ca --rotate\"", rootCA.NotAfter.UTC().Format(time.RFC3339)))
+		case rootCA.NotAfter.Before(now.Add(caExpiryWarningPeriod)):
+			warnings = append(warnings, fmt.Sprintf("the root CA certificate of the swarm expires on %s (in %s). Rotate the root CA with \"docker swarm ca --rotate\" to prevent nodes from losing access to the swarm", rootCA.NotAfter.UTC().Format(time.RFC3339), units.HumanDuration(rootCA.NotAfter.Sub(now))))
+		}
+	}
+	if expiry := info.Spec.CAConfig.NodeCertExpiry; expiry > 0 {
+		for _, n := range nodes {
+			if n.Status.State == swarm.NodeStateDown && now.Sub(n.Meta.UpdatedAt) > expiry {
+				warnings = append(warnings, fmt.Sprintf("node %s has been down for %s, which is longer than the validity period of node certificates; it may have to rejoin the swarm", n.Description.Hostname, units.HumanDuration(now.Sub(n.Meta.UpdatedAt))))
+			}
+		}
+	}
+	return warnings
+}
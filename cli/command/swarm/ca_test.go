@@ -2,13 +2,21 @@ package swarm
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io"
+	"math/big"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/go-units"
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 )
@@ -300,3 +308,102 @@ func TestUpdateSwarmSpecCertAndKeyAndExternalCA(t *testing.T) {
 	}
 	assert.Check(t, is.DeepEqual(*expected, s.spec))
 }
+
+// rootCA returns a self-signed CA certificate that expires at notAfter.
+func rootCA(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "swarm-ca"},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	assert.NilError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCAExpiryWarnings(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	info := func(trustRoot string) swarm.Swarm {
+		return swarm.Swarm{
+			ClusterInfo: swarm.ClusterInfo{
+				Spec:    swarm.Spec{CAConfig: swarm.CAConfig{NodeCertExpiry: 90 * 24 * time.Hour}},
+				TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot},
+			},
+		}
+	}
+	down := swarm.Node{
+		Meta:        swarm.Meta{UpdatedAt: now.Add(-100 * 24 * time.Hour)},
+		Description: swarm.NodeDescription{Hostname: "worker1"},
+		Status:      swarm.NodeStatus{State: swarm.NodeStateDown},
+	}
+	recentlyDown := down
+	recentlyDown.Meta.UpdatedAt = now.Add(-time.Hour)
+
+	assert.Check(t, is.Len(caExpiryWarnings(info(cert), []swarm.Node{recentlyDown}, now), 0))
+	assert.Check(t, is.DeepEqual(caExpiryWarnings(info(rootCA(t, now.Add(10*24*time.Hour))), nil, now), []string{
+		`the root CA certificate of the swarm expires on 2024-01-11T00:00:00Z (in 10 days). Rotate the root CA with "docker swarm ca --rotate" to prevent nodes from losing access to the swarm`,
+	}))
+	assert.Check(t, is.DeepEqual(caExpiryWarnings(info(rootCA(t, now.Add(-time.Hour))), []swarm.Node{down}, now), []string{
+		`the root CA certificate of the swarm expired on 2023-12-31T23:00:00Z; nodes can no longer renew their certificates. Rotate the root CA with "docker swarm ca --rotate"`,
+		`node worker1 has been down for 3 months, which is longer than the validity period of node certificates; it may have to rejoin the swarm`,
+	}))
+}
+
+func TestDisplayCAStatus(t *testing.T) {
+	tlsInfo := swarm.TLSInfo{TrustRoot: cert, CertIssuerSubject: []byte("subject"), CertIssuerPublicKey: []byte("key")}
+	cli := test.NewFakeCli(&fakeClient{
+		swarmInspectFunc: func() (swarm.Swarm, error) {
+			return swarm.Swarm{
+				ClusterInfo: swarm.ClusterInfo{
+					Spec:                   swarm.Spec{CAConfig: swarm.CAConfig{NodeCertExpiry: 90 * 24 * time.Hour}},
+					TLSInfo:                tlsInfo,
+					RootRotationInProgress: true,
+				},
+			}, nil
+		},
+		nodeListFunc: func() ([]swarm.Node, error) {
+			return []swarm.Node{
+				{
+					ID:          "node-2",
+					Description: swarm.NodeDescription{Hostname: "worker2", TLSInfo: swarm.TLSInfo{TrustRoot: "old"}},
+					Status:      swarm.NodeStatus{State: swarm.NodeStateReady},
+				},
+				{
+					ID:          "node-1",
+					Description: swarm.NodeDescription{Hostname: "worker1", TLSInfo: tlsInfo},
+					Status:      swarm.NodeStatus{State: swarm.NodeStateReady},
+				},
+			}, nil
+		},
+	})
+	cmd := newCACommand(cli)
+	cmd.SetArgs([]string{"--status"})
+	assert.NilError(t, cmd.Execute())
+	expected := `Root CA:
+ Subject: CN=Test,OU=Docker,O=Docker,L=San Francisco,ST=CA,C=US
+ Expires: 3017-11-02T21:29:18Z (in ` + units.HumanDuration(time.Until(time.Date(3017, 11, 2, 21, 29, 18, 0, time.UTC))) + `)
+ Rotation: in progress (1/2 nodes)
+Node certificates:
+ Expiry Duration: 3 months
+
+ID       HOSTNAME   STATUS   TLS STATUS
+node-1   worker1    Ready    Ready
+node-2   worker2    Ready    Needs Rotation
+`
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), expected))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), ""))
+}
+
+func TestCAStatusWithRotate(t *testing.T) {
+	cmd := newCACommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--status", "--rotate"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "`--status` flag can't be combined with the `--rotate` flag"))
+}
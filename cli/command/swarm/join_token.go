@@ -77,6 +77,8 @@ func runJoinToken(ctx context.Context, dockerCli command.Cli, opts joinTokenOpti
 	if err != nil {
 		return err
 	}
+	// Nodes that join get a certificate that is issued by the root CA.
+	printCAExpiryWarnings(dockerCli.Err(), sw, nil)
 
 	if opts.quiet && worker {
 		fmt.Fprintln(dockerCli.Out(), sw.JoinTokens.Worker)
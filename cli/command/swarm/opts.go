@@ -27,6 +27,7 @@ const (
 	flagDefaultAddrPoolMaskLength = "default-addr-pool-mask-length"
 	flagQuiet                     = "quiet"
 	flagRotate                    = "rotate"
+	flagStatus                    = "status"
 	flagToken                     = "token"
 	flagTaskHistoryLimit          = "task-history-limit"
 	flagExternalCA                = "external-ca"
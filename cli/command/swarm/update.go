@@ -63,6 +63,7 @@ func runUpdate(ctx context.Context, dockerCli command.Cli, flags *pflag.FlagSet,
 	}
 
 	fmt.Fprintln(dockerCli.Out(), "Swarm updated.")
+	printCAExpiryWarnings(dockerCli.Err(), swarmInspect, nil)
 
 	if curAutoLock && !prevAutoLock {
 		unlockKeyResp, err := client.SwarmGetUnlockKey(ctx)
@@ -7,6 +7,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 )
@@ -14,17 +15,30 @@ import (
 type fakeClient struct {
 	client.Client
 
-	version            string
-	serverVersion      func(ctx context.Context) (types.Version, error)
-	eventsFn           func(context.Context, events.ListOptions) (<-chan events.Message, <-chan error)
-	containerPruneFunc func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error)
-	networkPruneFunc   func(ctx context.Context, pruneFilter filters.Args) (network.PruneReport, error)
+	version             string
+	serverVersion       func(ctx context.Context) (types.Version, error)
+	pingFunc            func(ctx context.Context) (types.Ping, error)
+	eventsFn            func(context.Context, events.ListOptions) (<-chan events.Message, <-chan error)
+	containerPruneFunc  func(ctx context.Context, pruneFilters filters.Args) (container.PruneReport, error)
+	networkPruneFunc    func(ctx context.Context, pruneFilter filters.Args) (network.PruneReport, error)
+	diskUsageFunc       func(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error)
+	networkListFunc     func(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
+	containerRemoveFunc func(ctx context.Context, containerID string, options container.RemoveOptions) error
+	networkRemoveFunc   func(ctx context.Context, networkID string) error
+	imageRemoveFunc     func(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
 }
 
 func (cli *fakeClient) ServerVersion(ctx context.Context) (types.Version, error) {
 	return cli.serverVersion(ctx)
 }
 
+func (cli *fakeClient) Ping(ctx context.Context) (types.Ping, error) {
+	if cli.pingFunc != nil {
+		return cli.pingFunc(ctx)
+	}
+	return types.Ping{}, nil
+}
+
 func (cli *fakeClient) ClientVersion() string {
 	return cli.version
 }
@@ -46,3 +60,38 @@ func (cli *fakeClient) NetworksPrune(ctx context.Context, pruneFilter filters.Ar
 	}
 	return network.PruneReport{}, nil
 }
+
+func (cli *fakeClient) DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	if cli.diskUsageFunc != nil {
+		return cli.diskUsageFunc(ctx, options)
+	}
+	return types.DiskUsage{}, nil
+}
+
+func (cli *fakeClient) NetworkList(ctx context.Context, options network.ListOptions) ([]network.Summary, error) {
+	if cli.networkListFunc != nil {
+		return cli.networkListFunc(ctx, options)
+	}
+	return nil, nil
+}
+
+func (cli *fakeClient) ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error {
+	if cli.containerRemoveFunc != nil {
+		return cli.containerRemoveFunc(ctx, containerID, options)
+	}
+	return nil
+}
+
+func (cli *fakeClient) NetworkRemove(ctx context.Context, networkID string) error {
+	if cli.networkRemoveFunc != nil {
+		return cli.networkRemoveFunc(ctx, networkID)
+	}
+	return nil
+}
+
+func (cli *fakeClient) ImageRemove(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+	if cli.imageRemoveFunc != nil {
+		return cli.imageRemoveFunc(ctx, imageID, options)
+	}
+	return nil, nil
+}
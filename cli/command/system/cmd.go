@@ -18,7 +18,9 @@ func NewSystemCommand(dockerCli command.Cli) *cobra.Command {
 		NewEventsCommand(dockerCli),
 		NewInfoCommand(dockerCli),
 		newDiskUsageCommand(dockerCli),
+		newGPUsCommand(dockerCli),
 		newPruneCommand(dockerCli),
+		NewDiagnoseCommand(dockerCli),
 		newDialStdioCommand(dockerCli),
 	)
 
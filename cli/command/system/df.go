@@ -2,6 +2,7 @@ package system
 
 import (
 	"context"
+	"errors"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -13,8 +14,12 @@ import (
 )
 
 type diskUsageOptions struct {
-	verbose bool
-	format  string
+	verbose   bool
+	format    string
+	byImage   bool
+	byProject bool
+	snapshot  bool
+	compare   string
 }
 
 // newDiskUsageCommand creates a new cobra.Command for `docker df`
@@ -36,11 +41,32 @@ func newDiskUsageCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags.BoolVarP(&opts.verbose, "verbose", "v", false, "Show detailed information on space usage")
 	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&opts.byImage, "by-image", false, "Show the space usage of each image and its containers")
+	flags.BoolVar(&opts.byProject, "by-project", false, "Show the space usage of each Compose project")
+	flags.BoolVar(&opts.snapshot, "snapshot", false, "Record a snapshot of the space usage, to compare with later")
+	flags.StringVar(&opts.compare, "compare", "", `Compare the space usage with a snapshot ("last", a duration, or a date)`)
 
 	return cmd
 }
 
+func (opts diskUsageOptions) validate() error {
+	n := 0
+	for _, set := range []bool{opts.verbose, opts.byImage, opts.byProject, opts.compare != ""} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return errors.New("--verbose, --by-image, --by-project, and --compare can't be used together")
+	}
+	return nil
+}
+
 func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOptions) error {
+	if err := opts.validate(); err != nil {
+		return err
+	}
+
 	// TODO expose types.DiskUsageOptions.Types as flag on the command-line and/or as separate commands (docker container df / docker container usage)
 	du, err := dockerCli.Client().DiskUsage(ctx, types.DiskUsageOptions{})
 	if err != nil {
@@ -59,19 +85,31 @@ func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts diskUsageOpti
 		}
 	}
 
-	duCtx := formatter.DiskUsageContext{
-		Context: formatter.Context{
-			Output: dockerCli.Out(),
-			Format: formatter.NewDiskUsageFormat(format, opts.verbose),
-		},
-		LayersSize:  du.LayersSize,
-		BuilderSize: bsz,
-		BuildCache:  du.BuildCache,
-		Images:      du.Images,
-		Containers:  du.Containers,
-		Volumes:     du.Volumes,
-		Verbose:     opts.verbose,
+	switch {
+	case opts.byImage:
+		err = writeDiskUsageGroups(dockerCli.Out(), format, defaultDiskUsageByImageTableFormat, imageHeader, groupByImage(du))
+	case opts.byProject:
+		err = writeDiskUsageGroups(dockerCli.Out(), format, defaultDiskUsageByProjectTableFormat, projectHeader, groupByProject(du))
+	case opts.compare != "":
+		err = compareDiskUsage(dockerCli, format, opts.compare, newDiskUsageSnapshot(dockerCli.CurrentContext(), du, bsz))
+	default:
+		duCtx := formatter.DiskUsageContext{
+			Context: formatter.Context{
+				Output: dockerCli.Out(),
+				Format: formatter.NewDiskUsageFormat(format, opts.verbose),
+			},
+			LayersSize:  du.LayersSize,
+			BuilderSize: bsz,
+			BuildCache:  du.BuildCache,
+			Images:      du.Images,
+			Containers:  du.Containers,
+			Volumes:     du.Volumes,
+			Verbose:     opts.verbose,
+		}
+		err = duCtx.Write()
 	}
-
-	return duCtx.Write()
+	if err != nil || !opts.snapshot {
+		return err
+	}
+	return recordDiskUsage(dockerCli, newDiskUsageSnapshot(dockerCli.CurrentContext(), du, bsz))
 }
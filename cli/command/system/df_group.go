@@ -0,0 +1,196 @@
+package system
+
+import (
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+)
+
+const (
+	defaultDiskUsageByImageTableFormat   = "table {{.Name}}\t{{.Containers}}\t{{.ImageSize}}\t{{.ContainersSize}}\t{{.Size}}"
+	defaultDiskUsageByProjectTableFormat = "table {{.Name}}\t{{.Containers}}\t{{.Volumes}}\t{{.ContainersSize}}\t{{.VolumesSize}}\t{{.Size}}"
+
+	imageHeader          = "IMAGE"
+	projectHeader        = "PROJECT"
+	containersHeader     = "CONTAINERS"
+	volumesHeader        = "VOLUMES"
+	imageSizeHeader      = "IMAGE SIZE"
+	containersSizeHeader = "CONTAINERS SIZE"
+	volumesSizeHeader    = "VOLUMES SIZE"
+
+	// composeProjectLabel is the label that Compose sets on the containers
+	// and volumes of a project, with the name of the project.
+	composeProjectLabel = "com.docker.compose.project"
+
+	noneGroup = "<none>"
+)
+
+// diskUsageGroup is the space usage of an image and its containers, or of
+// the containers and volumes of a Compose project.
+type diskUsageGroup struct {
+	name           string
+	imageSize      int64
+	containers     int
+	containersSize int64
+	volumes        int
+	volumesSize    int64
+}
+
+func (g diskUsageGroup) size() int64 {
+	return g.imageSize + g.containersSize + g.volumesSize
+}
+
+// groupByImage returns the space usage of each image, with the space used
+// only by the image, and by the containers that use it, sorted by the total
+// space used.
+func groupByImage(du types.DiskUsage) []diskUsageGroup {
+	groups := make([]diskUsageGroup, 0, len(du.Images))
+	byID := make(map[string]int, len(du.Images))
+	for _, img := range du.Images {
+		g := diskUsageGroup{name: imageName(img.ID, img.RepoTags), imageSize: img.Size}
+		if img.SharedSize > 0 {
+			g.imageSize -= img.SharedSize
+		}
+		byID[img.ID] = len(groups)
+		groups = append(groups, g)
+	}
+	for _, c := range du.Containers {
+		i, ok := byID[c.ImageID]
+		if !ok {
+			continue
+		}
+		groups[i].containers++
+		groups[i].containersSize += c.SizeRw
+	}
+	sortGroups(groups)
+	return groups
+}
+
+// groupByProject returns the space usage of the containers and volumes of
+// each Compose project, sorted by the total space used. Containers and
+// volumes that aren't part of a project are grouped as "<none>".
+func groupByProject(du types.DiskUsage) []diskUsageGroup {
+	var groups []diskUsageGroup
+	byName := map[string]int{}
+	group := func(labels map[string]string) *diskUsageGroup {
+		name := labels[composeProjectLabel]
+		if name == "" {
+			name = noneGroup
+		}
+		i, ok := byName[name]
+		if !ok {
+			i = len(groups)
+			byName[name] = i
+			groups = append(groups, diskUsageGroup{name: name})
+		}
+		return &groups[i]
+	}
+	for _, c := range du.Containers {
+		g := group(c.Labels)
+		g.containers++
+		g.containersSize += c.SizeRw
+	}
+	for _, v := range du.Volumes {
+		g := group(v.Labels)
+		g.volumes++
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			g.volumesSize += v.UsageData.Size
+		}
+	}
+	sortGroups(groups)
+	return groups
+}
+
+func sortGroups(groups []diskUsageGroup) {
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].size() != groups[j].size() {
+			return groups[i].size() > groups[j].size()
+		}
+		return groups[i].name < groups[j].name
+	})
+}
+
+// imageName returns the first tag of an image, or its short ID if it has no
+// tags.
+func imageName(id string, repoTags []string) string {
+	for _, tag := range repoTags {
+		if ref, err := reference.ParseNormalizedNamed(tag); err == nil {
+			return reference.FamiliarString(ref)
+		}
+	}
+	return stringid.TruncateID(id)
+}
+
+// writeDiskUsageGroups writes the space usage of groups to out, with the
+// given format, or defaultTable for the "table" format.
+func writeDiskUsageGroups(out io.Writer, format, defaultTable, nameHeader string, groups []diskUsageGroup) error {
+	if format == formatter.TableFormatKey {
+		format = defaultTable
+	}
+	ctx := formatter.Context{
+		Output: out,
+		Format: formatter.Format(format),
+	}
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, g := range groups {
+			if err := format(&diskUsageGroupContext{g: g}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	groupCtx := diskUsageGroupContext{}
+	groupCtx.Header = formatter.SubHeaderContext{
+		"Name":           nameHeader,
+		"Containers":     containersHeader,
+		"Volumes":        volumesHeader,
+		"ImageSize":      imageSizeHeader,
+		"ContainersSize": containersSizeHeader,
+		"VolumesSize":    volumesSizeHeader,
+		"Size":           formatter.SizeHeader,
+	}
+	return ctx.Write(&groupCtx, render)
+}
+
+type diskUsageGroupContext struct {
+	formatter.HeaderContext
+	g diskUsageGroup
+}
+
+func (c *diskUsageGroupContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *diskUsageGroupContext) Name() string {
+	return c.g.name
+}
+
+func (c *diskUsageGroupContext) Containers() string {
+	return strconv.Itoa(c.g.containers)
+}
+
+func (c *diskUsageGroupContext) Volumes() string {
+	return strconv.Itoa(c.g.volumes)
+}
+
+func (c *diskUsageGroupContext) ImageSize() string {
+	return units.HumanSize(float64(c.g.imageSize))
+}
+
+func (c *diskUsageGroupContext) ContainersSize() string {
+	return units.HumanSize(float64(c.g.containersSize))
+}
+
+func (c *diskUsageGroupContext) VolumesSize() string {
+	return units.HumanSize(float64(c.g.volumesSize))
+}
+
+func (c *diskUsageGroupContext) Size() string {
+	return units.HumanSize(float64(c.g.size()))
+}
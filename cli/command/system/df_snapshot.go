@@ -0,0 +1,255 @@
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/ioutils"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDiskUsageCompareTableFormat = "table {{.Type}}\t{{.Size}}\t{{.Previous}}\t{{.Change}}"
+
+	typeHeader     = "TYPE"
+	previousHeader = "PREVIOUS"
+	changeHeader   = "CHANGE"
+
+	// diskUsageFileName is the name of the file, next to the CLI
+	// configuration file, in which "docker system df --snapshot" records the
+	// snapshots of the disk usage.
+	diskUsageFileName = "disk-usage.json"
+
+	// maxDiskUsageSnapshots is the number of snapshots that are kept. The
+	// oldest snapshots are removed when more are recorded.
+	maxDiskUsageSnapshots = 100
+)
+
+// diskUsageSnapshot is the disk usage of a context at some time, in bytes.
+type diskUsageSnapshot struct {
+	Time       time.Time
+	Context    string
+	Images     int64
+	Containers int64
+	Volumes    int64
+	BuildCache int64
+}
+
+func newDiskUsageSnapshot(contextName string, du types.DiskUsage, builderSize int64) diskUsageSnapshot {
+	s := diskUsageSnapshot{
+		Time:       time.Now().UTC(),
+		Context:    contextName,
+		Images:     du.LayersSize,
+		BuildCache: builderSize,
+	}
+	for _, c := range du.Containers {
+		s.Containers += c.SizeRw
+	}
+	for _, v := range du.Volumes {
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			s.Volumes += v.UsageData.Size
+		}
+	}
+	return s
+}
+
+// diskUsageFile returns the path of the file in which the snapshots of the
+// disk usage are recorded, or an empty string if the CLI has no
+// configuration file.
+func diskUsageFile(dockerCli command.Cli) string {
+	configFile := dockerCli.ConfigFile().GetFilename()
+	if configFile == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(configFile), diskUsageFileName)
+}
+
+func loadDiskUsageSnapshots(fileName string) ([]diskUsageSnapshot, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var snapshots []diskUsageSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, errors.Wrapf(err, "invalid snapshots of the disk usage in %s", fileName)
+	}
+	return snapshots, nil
+}
+
+// recordDiskUsage records the snapshot s, and removes the oldest snapshots
+// if there are more than maxDiskUsageSnapshots.
+func recordDiskUsage(dockerCli command.Cli, s diskUsageSnapshot) error {
+	fileName := diskUsageFile(dockerCli)
+	if fileName == "" {
+		return errors.New("cannot record a snapshot of the disk usage without a configuration file")
+	}
+	snapshots, err := loadDiskUsageSnapshots(fileName)
+	if err != nil {
+		return err
+	}
+	snapshots = append(snapshots, s)
+	if len(snapshots) > maxDiskUsageSnapshots {
+		snapshots = snapshots[len(snapshots)-maxDiskUsageSnapshots:]
+	}
+	data, err := json.Marshal(snapshots)
+	if err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(fileName, data, 0o600); err != nil {
+		return errors.Wrap(err, "failed to record the snapshot of the disk usage")
+	}
+	dockerCli.Err().Infof("Recorded a snapshot of the disk usage\n")
+	return nil
+}
+
+// findSnapshot returns the snapshot of the context to compare with:
+// the most recent snapshot if previous is "last", or the most recent snapshot
+// taken at least the duration previous ago, or at or before the date
+// previous.
+func findSnapshot(snapshots []diskUsageSnapshot, contextName, previous string, now time.Time) (diskUsageSnapshot, error) {
+	before := now
+	if previous != "last" {
+		if d, err := time.ParseDuration(previous); err == nil {
+			before = now.Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, previous); err == nil {
+			before = t
+		} else if t, err := time.ParseInLocation(time.DateOnly, previous, time.Local); err == nil {
+			before = t
+		} else {
+			return diskUsageSnapshot{}, errors.Errorf(`invalid snapshot %q: must be "last", a duration, or a date`, previous)
+		}
+	}
+	var found *diskUsageSnapshot
+	for i, s := range snapshots {
+		if s.Context != contextName || s.Time.After(before) {
+			continue
+		}
+		if found == nil || s.Time.After(found.Time) {
+			found = &snapshots[i]
+		}
+	}
+	if found == nil {
+		if previous == "last" {
+			return diskUsageSnapshot{}, errors.Errorf(`no snapshot of the disk usage of context %q: use "docker system df --snapshot" to record one`, contextName)
+		}
+		return diskUsageSnapshot{}, errors.Errorf("no snapshot of the disk usage of context %q was taken before %s", contextName, before.Local().Format(time.DateTime))
+	}
+	return *found, nil
+}
+
+// compareDiskUsage writes the disk usage of current, compared with the
+// snapshot previous.
+func compareDiskUsage(dockerCli command.Cli, format, previous string, current diskUsageSnapshot) error {
+	fileName := diskUsageFile(dockerCli)
+	if fileName == "" {
+		return errors.New("cannot compare the disk usage without a configuration file")
+	}
+	snapshots, err := loadDiskUsageSnapshots(fileName)
+	if err != nil {
+		return err
+	}
+	prev, err := findSnapshot(snapshots, current.Context, previous, current.Time)
+	if err != nil {
+		return err
+	}
+	dockerCli.Err().Infof("Comparing with the snapshot of %s (%s ago)\n\n", prev.Time.Local().Format(time.DateTime), units.HumanDuration(current.Time.Sub(prev.Time)))
+
+	changes := []diskUsageChange{
+		{typ: "Images", size: current.Images, previous: prev.Images},
+		{typ: "Containers", size: current.Containers, previous: prev.Containers},
+		{typ: "Local Volumes", size: current.Volumes, previous: prev.Volumes},
+		{typ: "Build Cache", size: current.BuildCache, previous: prev.BuildCache},
+	}
+	total := diskUsageChange{typ: "Total"}
+	for _, c := range changes {
+		total.size += c.size
+		total.previous += c.previous
+	}
+	changes = append(changes, total)
+
+	if format == formatter.TableFormatKey {
+		format = defaultDiskUsageCompareTableFormat
+	}
+	ctx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: formatter.Format(format),
+	}
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, c := range changes {
+			if err := format(&diskUsageChangeContext{c: c}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	changeCtx := diskUsageChangeContext{}
+	changeCtx.Header = formatter.SubHeaderContext{
+		"Type":     typeHeader,
+		"Size":     formatter.SizeHeader,
+		"Previous": previousHeader,
+		"Change":   changeHeader,
+	}
+	return ctx.Write(&changeCtx, render)
+}
+
+// diskUsageChange is the change of the disk usage of a type of object since
+// a snapshot.
+type diskUsageChange struct {
+	typ      string
+	size     int64
+	previous int64
+}
+
+type diskUsageChangeContext struct {
+	formatter.HeaderContext
+	c diskUsageChange
+}
+
+func (c *diskUsageChangeContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *diskUsageChangeContext) Type() string {
+	return c.c.typ
+}
+
+func (c *diskUsageChangeContext) Size() string {
+	return units.HumanSize(float64(c.c.size))
+}
+
+func (c *diskUsageChangeContext) Previous() string {
+	return units.HumanSize(float64(c.c.previous))
+}
+
+func (c *diskUsageChangeContext) Change() string {
+	diff := c.c.size - c.c.previous
+	if diff == 0 {
+		return "0B"
+	}
+	sign := "+"
+	if diff < 0 {
+		sign = "-"
+	}
+	change := sign + units.HumanSize(float64(abs(diff)))
+	if c.c.previous > 0 {
+		change += fmt.Sprintf(" (%s%d%%)", sign, abs(diff)*100/c.c.previous)
+	}
+	return change
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,138 @@
+package system
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func sampleDiskUsage() types.DiskUsage {
+	return types.DiskUsage{
+		LayersSize: 3000,
+		Images: []*image.Summary{
+			{ID: "sha256:1111111111111111111111111111111111111111111111111111111111111111", RepoTags: []string{"busybox:latest"}, Size: 1000, SharedSize: 0},
+			{ID: "sha256:2222222222222222222222222222222222222222222222222222222222222222", RepoTags: []string{"myapp:v1"}, Size: 2500, SharedSize: 500},
+		},
+		Containers: []*types.Container{
+			{ID: "c1", ImageID: "sha256:2222222222222222222222222222222222222222222222222222222222222222", SizeRw: 200, State: "running", Labels: map[string]string{composeProjectLabel: "web"}},
+			{ID: "c2", ImageID: "sha256:2222222222222222222222222222222222222222222222222222222222222222", SizeRw: 100, State: "exited", Labels: map[string]string{composeProjectLabel: "web"}},
+			{ID: "c3", ImageID: "sha256:1111111111111111111111111111111111111111111111111111111111111111", SizeRw: 50, State: "exited"},
+		},
+		Volumes: []*volume.Volume{
+			{Name: "web_data", Labels: map[string]string{composeProjectLabel: "web"}, UsageData: &volume.UsageData{Size: 4000, RefCount: 1}},
+			{Name: "other", UsageData: &volume.UsageData{Size: -1}},
+		},
+	}
+}
+
+func TestDiskUsageByImage(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{diskUsageFunc: func(context.Context, types.DiskUsageOptions) (types.DiskUsage, error) {
+		return sampleDiskUsage(), nil
+	}})
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--by-image"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `IMAGE            CONTAINERS   IMAGE SIZE   CONTAINERS SIZE   SIZE
+myapp:v1         2            2kB          300B              2.3kB
+busybox:latest   1            1kB          50B               1.05kB
+`))
+}
+
+func TestDiskUsageByProject(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{diskUsageFunc: func(context.Context, types.DiskUsageOptions) (types.DiskUsage, error) {
+		return sampleDiskUsage(), nil
+	}})
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--by-project", "--format", "{{.Name}} {{.Containers}} {{.Volumes}} {{.Size}}"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "web 2 1 4.3kB\n<none> 1 1 50B\n"))
+}
+
+func TestDiskUsageSnapshot(t *testing.T) {
+	du := sampleDiskUsage()
+	cli := test.NewFakeCli(&fakeClient{diskUsageFunc: func(context.Context, types.DiskUsageOptions) (types.DiskUsage, error) {
+		return du, nil
+	}})
+	cli.SetConfigFile(configfile.New(filepath.Join(t.TempDir(), "config.json")))
+
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--compare", "last"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), `no snapshot of the disk usage of context "default"`))
+
+	cmd = newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--snapshot", "--format", "{{.Type}}"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Recorded a snapshot of the disk usage\n"))
+
+	du.LayersSize = 5000
+	du.Volumes[0].UsageData.Size = 2000
+	cli.ResetOutputBuffers()
+	cmd = newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--compare", "last"})
+	cmd.SetOut(io.Discard)
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), `TYPE            SIZE      PREVIOUS   CHANGE
+Images          5kB       3kB        +2kB (+66%)
+Containers      350B      350B       0B
+Local Volumes   2kB       4kB        -2kB (-50%)
+Build Cache     0B        0B         0B
+Total           7.35kB    7.35kB     0B
+`))
+}
+
+func TestFindSnapshot(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	snapshots := []diskUsageSnapshot{
+		{Time: now.Add(-72 * time.Hour), Context: "default", Images: 1},
+		{Time: now.Add(-48 * time.Hour), Context: "remote", Images: 2},
+		{Time: now.Add(-24 * time.Hour), Context: "default", Images: 3},
+		{Time: now.Add(-time.Hour), Context: "default", Images: 4},
+	}
+	tests := []struct {
+		previous      string
+		expected      int64
+		expectedError string
+	}{
+		{previous: "last", expected: 4},
+		{previous: "2h", expected: 3},
+		{previous: "30h", expected: 1},
+		{previous: "2024-06-09T13:00:00Z", expected: 3},
+		{previous: "100h", expectedError: `no snapshot of the disk usage of context "default" was taken before`},
+		{previous: "yesterday", expectedError: `invalid snapshot "yesterday": must be "last", a duration, or a date`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.previous, func(t *testing.T) {
+			s, err := findSnapshot(snapshots, "default", tc.previous, now)
+			if tc.expectedError != "" {
+				assert.Check(t, is.ErrorContains(err, tc.expectedError))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(s.Images, tc.expected))
+		})
+	}
+}
+
+func TestDiskUsageConflictingOptions(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--by-image", "--verbose"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "--verbose, --by-image, --by-project, and --compare can't be used together"))
+}
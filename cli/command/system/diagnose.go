@@ -0,0 +1,667 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/config/configfile"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/version"
+	"github.com/docker/cli/internal/diskspace"
+	"github.com/docker/cli/templates"
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/homedir"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stdcopy"
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// Statuses of the checks of "docker system diagnose".
+const (
+	diagnoseOK      = "ok"
+	diagnoseWarning = "warning"
+	diagnoseError   = "error"
+	diagnoseSkipped = "skipped"
+)
+
+// Names of the checks of "docker system diagnose", in the order in which
+// they're run.
+const (
+	checkSocket      = "socket"
+	checkContext     = "context"
+	checkVersion     = "version"
+	checkConfig      = "config"
+	checkCredentials = "credentials"
+	checkDisk        = "disk"
+	checkDNS         = "dns"
+)
+
+var diagnoseChecks = []string{checkSocket, checkContext, checkVersion, checkConfig, checkCredentials, checkDisk, checkDNS}
+
+const (
+	// diagnoseTimeout is the timeout of each check, except the DNS check.
+	diagnoseTimeout = 10 * time.Second
+
+	// dnsTimeout is the timeout of the DNS check, which may pull the
+	// helper image.
+	dnsTimeout = 2 * time.Minute
+
+	// dnsTestHost is the name that the DNS check resolves in a container.
+	dnsTestHost = "registry-1.docker.io"
+
+	// minFreeDiskSpace is the free disk space of the daemon under which the
+	// disk check warns.
+	minFreeDiskSpace = 2 * units.GiB
+)
+
+type diagnoseOptions struct {
+	format      string
+	skip        []string
+	helperImage string
+}
+
+// diagnosis is the result of "docker system diagnose".
+type diagnosis struct {
+	Context  string
+	Healthy  bool
+	Errors   int
+	Warnings int
+	Checks   []diagnosticCheck
+}
+
+// diagnosticCheck is the result of a check of "docker system diagnose".
+type diagnosticCheck struct {
+	Name    string
+	Status  string
+	Message string
+	// Fix is how to fix the problem that the check found.
+	Fix string `json:",omitempty"`
+}
+
+// NewDiagnoseCommand creates a new cobra.Command for `docker system diagnose`
+func NewDiagnoseCommand(dockerCli command.Cli) *cobra.Command {
+	var opts diagnoseOptions
+
+	cmd := &cobra.Command{
+		Use:   "diagnose [OPTIONS]",
+		Short: "Check the Docker environment for common problems",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiagnose(cmd.Context(), dockerCli, opts)
+		},
+		Annotations: map[string]string{
+			"aliases": "docker system diagnose, docker doctor",
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	flags.StringSliceVar(&opts.skip, "skip", nil, `Skip checks ("`+strings.Join(diagnoseChecks, `", "`)+`")`)
+	flags.StringVar(&opts.helperImage, "helper-image", "busybox", "Image to use for the helper container that checks DNS")
+
+	_ = cmd.RegisterFlagCompletionFunc("skip", cobra.FixedCompletions(diagnoseChecks, cobra.ShellCompDirectiveNoFileComp))
+	return cmd
+}
+
+// NewDoctorCommand creates a new cobra.Command for `docker doctor`, which is
+// a shorthand for `docker system diagnose`.
+func NewDoctorCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := NewDiagnoseCommand(dockerCli)
+	cmd.Use = "doctor [OPTIONS]"
+	return cmd
+}
+
+func runDiagnose(ctx context.Context, dockerCli command.Cli, opts diagnoseOptions) error {
+	skip := map[string]bool{}
+	for _, name := range opts.skip {
+		if !slices.Contains(diagnoseChecks, name) {
+			return errors.Errorf("invalid check %q: must be one of %s", name, strings.Join(diagnoseChecks, ", "))
+		}
+		skip[name] = true
+	}
+
+	d := &diagnoser{dockerCli: dockerCli, helperImage: opts.helperImage}
+	checks := map[string]func(context.Context) []diagnosticCheck{
+		checkSocket:      d.checkSocket,
+		checkContext:     d.checkContext,
+		checkVersion:     d.checkVersion,
+		checkConfig:      d.checkConfig,
+		checkCredentials: d.checkCredentials,
+		checkDisk:        d.checkDisk,
+		checkDNS:         d.checkDNS,
+	}
+	result := diagnosis{Context: dockerCli.CurrentContext(), Checks: []diagnosticCheck{}}
+	for _, name := range diagnoseChecks {
+		if skip[name] {
+			result.Checks = append(result.Checks, diagnosticCheck{Name: name, Status: diagnoseSkipped, Message: "skipped with --skip"})
+			continue
+		}
+		result.Checks = append(result.Checks, checks[name](ctx)...)
+	}
+	for _, c := range result.Checks {
+		switch c.Status {
+		case diagnoseError:
+			result.Errors++
+		case diagnoseWarning:
+			result.Warnings++
+		}
+	}
+	result.Healthy = result.Errors == 0
+
+	if opts.format != "" {
+		if err := formatDiagnosis(dockerCli.Out(), opts.format, result); err != nil {
+			return err
+		}
+	} else {
+		prettyPrintDiagnosis(dockerCli.Out(), result)
+	}
+	if !result.Healthy {
+		return cli.StatusError{StatusCode: 1}
+	}
+	return nil
+}
+
+// diagnoser runs the checks of "docker system diagnose". The checks that
+// need the daemon are skipped if the context check couldn't connect to it.
+type diagnoser struct {
+	dockerCli   command.Cli
+	helperImage string
+	reachable   bool
+}
+
+func (d *diagnoser) unreachable(name string) []diagnosticCheck {
+	return []diagnosticCheck{{Name: name, Status: diagnoseSkipped, Message: "the daemon isn't reachable"}}
+}
+
+// localSocket returns the path of the socket of the daemon, if the CLI
+// connects to it with a unix socket.
+func (d *diagnoser) localSocket() (string, bool) {
+	return strings.CutPrefix(d.dockerCli.Client().DaemonHost(), "unix://")
+}
+
+// checkSocket checks that the socket of the daemon exists, and that the
+// user has the permission to connect to it.
+func (d *diagnoser) checkSocket(context.Context) []diagnosticCheck {
+	c := diagnosticCheck{Name: checkSocket}
+	socket, ok := d.localSocket()
+	if !ok {
+		c.Status, c.Message = diagnoseSkipped, fmt.Sprintf("%s isn't a local socket", d.dockerCli.Client().DaemonHost())
+		return []diagnosticCheck{c}
+	}
+	fi, err := os.Stat(socket)
+	if err != nil {
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("the socket %s doesn't exist", socket)
+		c.Fix = `Start the Docker daemon, or use another context with "docker context use"`
+		return []diagnosticCheck{c}
+	}
+	conn, err := net.DialTimeout("unix", socket, diagnoseTimeout)
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("permission denied to connect to %s", socket)
+		group := socketGroup(fi)
+		if group == "" {
+			group = "docker"
+		}
+		c.Fix = fmt.Sprintf(`Add your user to the %q group with "sudo usermod -aG %s $USER", and log in again, or use Docker in rootless mode`, group, group)
+	case err != nil:
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("failed to connect to %s: %v", socket, err)
+		c.Fix = "Start the Docker daemon"
+	default:
+		_ = conn.Close()
+		c.Status, c.Message = diagnoseOK, fmt.Sprintf("%s is accessible", socket)
+	}
+	return []diagnosticCheck{c}
+}
+
+// checkContext checks that the daemon of the current context is reachable.
+func (d *diagnoser) checkContext(ctx context.Context) []diagnosticCheck {
+	c := diagnosticCheck{Name: checkContext}
+	name, host := d.dockerCli.CurrentContext(), d.dockerCli.Client().DaemonHost()
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+	if _, err := d.dockerCli.Client().Ping(ctx); err != nil {
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("the daemon of context %q (%s) isn't reachable: %v", name, host, err)
+		c.Fix = `Check that the daemon is running, or use another context with "docker context use"`
+		return []diagnosticCheck{c}
+	}
+	d.reachable = true
+	c.Status, c.Message = diagnoseOK, fmt.Sprintf("the daemon of context %q (%s) is reachable", name, host)
+	return []diagnosticCheck{c}
+}
+
+// checkVersion checks that the client and the daemon support the same API
+// version.
+func (d *diagnoser) checkVersion(ctx context.Context) []diagnosticCheck {
+	if !d.reachable {
+		return d.unreachable(checkVersion)
+	}
+	c := diagnosticCheck{Name: checkVersion}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+	sv, err := d.dockerCli.Client().ServerVersion(ctx)
+	if err != nil {
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("failed to get the version of the daemon: %v", err)
+		return []diagnosticCheck{c}
+	}
+	clientVersion := fmt.Sprintf("%s (API %s)", version.Version, api.DefaultVersion)
+	serverVersion := fmt.Sprintf("%s (API %s)", sv.Version, sv.APIVersion)
+	switch {
+	case versions.LessThan(sv.APIVersion, api.DefaultVersion):
+		c.Status = diagnoseWarning
+		c.Message = fmt.Sprintf("the daemon %s is older than the client %s, so some features of the client aren't available", serverVersion, clientVersion)
+		c.Fix = "Update the Docker Engine"
+	case versions.GreaterThan(sv.APIVersion, api.DefaultVersion):
+		c.Status = diagnoseWarning
+		c.Message = fmt.Sprintf("the client %s is older than the daemon %s, so some features of the daemon aren't available", clientVersion, serverVersion)
+		c.Fix = "Update the Docker CLI"
+	default:
+		c.Status, c.Message = diagnoseOK, fmt.Sprintf("the client %s and the daemon %s use the same API version", clientVersion, serverVersion)
+	}
+	return []diagnosticCheck{c}
+}
+
+// checkConfig checks the configuration file for known mistakes: unknown
+// properties, credentials that are stored unencrypted in a file that other
+// users can read, and environment variables that override the current
+// context.
+func (d *diagnoser) checkConfig(context.Context) []diagnosticCheck {
+	cfg := d.dockerCli.ConfigFile()
+	fileName := cfg.GetFilename()
+
+	var problems []diagnosticCheck
+	problem := func(msg, fix string) {
+		problems = append(problems, diagnosticCheck{Name: checkConfig, Status: diagnoseWarning, Message: msg, Fix: fix})
+	}
+
+	if data, err := os.ReadFile(fileName); err == nil {
+		var properties map[string]json.RawMessage
+		if err := json.Unmarshal(data, &properties); err != nil {
+			problems = append(problems, diagnosticCheck{
+				Name: checkConfig, Status: diagnoseError,
+				Message: fmt.Sprintf("%s is not valid JSON: %v", fileName, err),
+				Fix:     "Fix the syntax of the configuration file",
+			})
+		}
+		for _, name := range unknownConfigProperties(properties) {
+			fix := "Remove the property"
+			if s := suggestConfigProperty(name); s != "" {
+				fix = fmt.Sprintf("Rename the property to %q", s)
+			}
+			problem(fmt.Sprintf("unknown property %q in %s", name, fileName), fix)
+		}
+		if fi, err := os.Stat(fileName); err == nil && runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0 && hasStoredCredentials(cfg) {
+			problem(fmt.Sprintf("%s contains credentials, and can be read by other users", fileName), fmt.Sprintf(`Run "chmod 600 %s"`, fileName))
+		}
+	}
+	if cfg.CredentialsStore == "" && hasStoredCredentials(cfg) {
+		problem(
+			fmt.Sprintf("the credentials of registries are stored unencrypted in %s", fileName),
+			"Configure a credential helper: https://docs.docker.com/engine/reference/commandline/login/#credential-stores",
+		)
+	}
+	if os.Getenv("DOCKER_HOST") != "" && cfg.CurrentContext != "" && os.Getenv("DOCKER_CONTEXT") == "" {
+		problem(
+			fmt.Sprintf("the DOCKER_HOST environment variable overrides the context %q of %s", cfg.CurrentContext, fileName),
+			`Unset DOCKER_HOST, or remove the current context with "docker context use default"`,
+		)
+	}
+	if legacy := filepath.Join(homedir.Get(), ".dockercfg"); fileExists(legacy) {
+		problem(fmt.Sprintf("the legacy configuration file %s is ignored", legacy), fmt.Sprintf("Move the credentials of %s to %s, and remove it", legacy, fileName))
+	}
+
+	if len(problems) == 0 {
+		return []diagnosticCheck{{Name: checkConfig, Status: diagnoseOK, Message: fmt.Sprintf("no known mistakes in %s", fileName)}}
+	}
+	return problems
+}
+
+// configProperties returns the names of the properties of the configuration
+// file.
+func configProperties() []string {
+	var names []string
+	t := reflect.TypeOf(configfile.ConfigFile{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// unknownConfigProperties returns the properties that the configuration file
+// doesn't have, sorted.
+func unknownConfigProperties(properties map[string]json.RawMessage) []string {
+	known := configProperties()
+	var unknown []string
+	for name := range properties {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// suggestConfigProperty returns the property of the configuration file that
+// name is likely a misspelling of, if any.
+func suggestConfigProperty(name string) string {
+	best, bestDistance := "", 3
+	for _, known := range configProperties() {
+		if strings.EqualFold(name, known) {
+			return known
+		}
+		if d := cli.EditDistance(strings.ToLower(name), strings.ToLower(known)); d < bestDistance {
+			best, bestDistance = known, d
+		}
+	}
+	return best
+}
+
+// hasStoredCredentials returns whether the configuration file contains
+// credentials, rather than credential helpers storing them.
+func hasStoredCredentials(cfg *configfile.ConfigFile) bool {
+	for _, ac := range cfg.AuthConfigs {
+		if ac.Auth != "" || ac.Password != "" || ac.IdentityToken != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// checkCredentials checks that the credential helpers of the configuration
+// file are installed, and work.
+func (d *diagnoser) checkCredentials(ctx context.Context) []diagnosticCheck {
+	cfg := d.dockerCli.ConfigFile()
+	var helpers []string
+	for _, h := range cfg.CredentialHelpers {
+		if h != cfg.CredentialsStore && !slices.Contains(helpers, h) {
+			helpers = append(helpers, h)
+		}
+	}
+	sort.Strings(helpers)
+	if cfg.CredentialsStore != "" {
+		helpers = append([]string{cfg.CredentialsStore}, helpers...)
+	}
+	if len(helpers) == 0 {
+		return []diagnosticCheck{{Name: checkCredentials, Status: diagnoseOK, Message: "no credential helpers are configured"}}
+	}
+
+	var checks []diagnosticCheck
+	for _, h := range helpers {
+		c := diagnosticCheck{Name: checkCredentials}
+		program := "docker-credential-" + h
+		if _, err := exec.LookPath(program); err != nil {
+			c.Status, c.Message = diagnoseError, fmt.Sprintf("the credential helper %q isn't installed: %s was not found in the PATH", h, program)
+			c.Fix = fmt.Sprintf(`Install %s, or remove %q from "credsStore" and "credHelpers" in %s`, program, h, cfg.GetFilename())
+			checks = append(checks, c)
+			continue
+		}
+		c.Status, c.Message = diagnoseOK, fmt.Sprintf("the credential helper %q works", h)
+		if err := runCredentialHelper(ctx, program); err != nil {
+			c.Status, c.Message = diagnoseError, fmt.Sprintf("the credential helper %q failed: %v", h, err)
+			c.Fix = fmt.Sprintf(`Check that "%s list" works, for example that the keychain it uses is unlocked`, program)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+func runCredentialHelper(ctx context.Context, program string) error {
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, "list")
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkDisk checks that the daemon has enough free disk space, if it runs on
+// the same host as the CLI, and reports the space that a prune reclaims.
+func (d *diagnoser) checkDisk(ctx context.Context) []diagnosticCheck {
+	if !d.reachable {
+		return d.unreachable(checkDisk)
+	}
+	c := diagnosticCheck{Name: checkDisk}
+
+	ctx, cancel := context.WithTimeout(ctx, diagnoseTimeout)
+	defer cancel()
+	du, err := d.dockerCli.Client().DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("failed to get the disk usage of the daemon: %v", err)
+		return []diagnosticCheck{c}
+	}
+	reclaimable := units.HumanSize(float64(reclaimableSpace(du)))
+	c.Status, c.Message = diagnoseOK, fmt.Sprintf(`%s can be reclaimed with "docker system prune"`, reclaimable)
+
+	// The free disk space can only be checked if the daemon runs on the
+	// same host as the CLI.
+	if _, ok := d.localSocket(); !ok {
+		return []diagnosticCheck{c}
+	}
+	info, err := d.dockerCli.Client().Info(ctx)
+	if err != nil || info.DockerRootDir == "" {
+		return []diagnosticCheck{c}
+	}
+	usage, err := diskspace.Get(info.DockerRootDir)
+	if err != nil {
+		return []diagnosticCheck{c}
+	}
+	c.Message = fmt.Sprintf("%s of %s is free on %s, and %s can be reclaimed", units.BytesSize(float64(usage.Free)), units.BytesSize(float64(usage.Total)), info.DockerRootDir, reclaimable)
+	if usage.Free < minFreeDiskSpace || usage.Free < usage.Total/10 {
+		c.Status = diagnoseWarning
+		c.Fix = `Remove unused data with "docker system prune", or free up disk space`
+	}
+	return []diagnosticCheck{c}
+}
+
+// reclaimableSpace returns the space used by the images without containers,
+// the stopped containers, the unused volumes, and the unused build cache.
+func reclaimableSpace(du types.DiskUsage) int64 {
+	var size int64
+	for _, img := range du.Images {
+		if img.Containers == 0 {
+			size += img.Size - max(img.SharedSize, 0)
+		}
+	}
+	for _, c := range du.Containers {
+		if c.State != "running" && c.State != "paused" && c.State != "restarting" {
+			size += c.SizeRw
+		}
+	}
+	for _, v := range du.Volumes {
+		if v.UsageData != nil && v.UsageData.RefCount == 0 && v.UsageData.Size > 0 {
+			size += v.UsageData.Size
+		}
+	}
+	for _, bc := range du.BuildCache {
+		if !bc.InUse && !bc.Shared {
+			size += bc.Size
+		}
+	}
+	return size
+}
+
+// checkDNS checks that containers can resolve names, by resolving
+// dnsTestHost in a container.
+func (d *diagnoser) checkDNS(ctx context.Context) []diagnosticCheck {
+	if !d.reachable {
+		return d.unreachable(checkDNS)
+	}
+	c := diagnosticCheck{Name: checkDNS}
+	output, err := d.resolveInContainer(ctx, dnsTestHost)
+	switch {
+	case err != nil:
+		c.Status, c.Message = diagnoseWarning, fmt.Sprintf("failed to run a container to check DNS: %v", err)
+		c.Fix = fmt.Sprintf("Check that the %s image can be pulled, or skip the check with --skip %s", d.helperImage, checkDNS)
+	case output != "":
+		c.Status, c.Message = diagnoseError, fmt.Sprintf("containers can't resolve %s: %s", dnsTestHost, output)
+		c.Fix = `Check the DNS configuration of the host, or set the DNS servers of containers with "dns" in the daemon.json of the daemon`
+	default:
+		c.Status, c.Message = diagnoseOK, fmt.Sprintf("containers can resolve %s", dnsTestHost)
+	}
+	return []diagnosticCheck{c}
+}
+
+// resolveInContainer resolves host in a container, and returns the output
+// of the resolution if it failed.
+func (d *diagnoser) resolveInContainer(ctx context.Context, host string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, dnsTimeout)
+	defer cancel()
+	apiClient := d.dockerCli.Client()
+
+	config := &container.Config{
+		Image:  d.helperImage,
+		Cmd:    []string{"nslookup", host},
+		Labels: map[string]string{"com.docker.cli.diagnose-helper": "true"},
+	}
+	created, err := apiClient.ContainerCreate(ctx, config, &container.HostConfig{}, nil, nil, "")
+	if errdefs.IsNotFound(err) {
+		d.dockerCli.Err().Infof("Unable to find image '%s' locally\n", d.helperImage)
+		if err := d.pullHelperImage(ctx); err != nil {
+			return "", err
+		}
+		created, err = apiClient.ContainerCreate(ctx, config, &container.HostConfig{}, nil, nil, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		// The container is removed even if ctx was cancelled.
+		_ = apiClient.ContainerRemove(context.WithoutCancel(ctx), created.ID, container.RemoveOptions{Force: true})
+	}()
+
+	statusC, errC := apiClient.ContainerWait(ctx, created.ID, container.WaitConditionNextExit)
+	if err := apiClient.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return "", err
+	}
+	var status container.WaitResponse
+	select {
+	case status = <-statusC:
+	case err := <-errC:
+		return "", err
+	}
+	if status.StatusCode == 0 {
+		return "", nil
+	}
+
+	logs, err := apiClient.ContainerLogs(ctx, created.ID, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return "", err
+	}
+	defer logs.Close()
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, logs); err != nil {
+		return "", err
+	}
+	msg := strings.Join(strings.Fields(output.String()), " ")
+	if msg == "" {
+		msg = fmt.Sprintf("nslookup exited with status %d", status.StatusCode)
+	}
+	return msg, nil
+}
+
+func (d *diagnoser) pullHelperImage(ctx context.Context) error {
+	encodedAuth, err := command.RetrieveAuthTokenFromImage(d.dockerCli.ConfigFile(), d.helperImage)
+	if err != nil {
+		return err
+	}
+	responseBody, err := d.dockerCli.Client().ImageCreate(ctx, d.helperImage, image.CreateOptions{
+		RegistryAuth: encodedAuth,
+	})
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+	return jsonmessage.DisplayJSONMessagesToStream(responseBody, d.dockerCli.Err(), nil)
+}
+
+func formatDiagnosis(out io.Writer, format string, result diagnosis) error {
+	switch format {
+	case formatter.JSONFormatKey:
+		format = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		format = formatter.YAMLFormat
+	}
+	format, err := formatter.ExpandJSONPath(format)
+	if err != nil {
+		return cli.StatusError{StatusCode: 64, Status: err.Error()}
+	}
+	tmpl, err := templates.Parse(format)
+	if err != nil {
+		return cli.StatusError{
+			StatusCode: 64,
+			Status:     "template parsing error: " + err.Error(),
+		}
+	}
+	if err := tmpl.Execute(out, result); err != nil {
+		return err
+	}
+	fprintln(out)
+	return nil
+}
+
+func prettyPrintDiagnosis(out io.Writer, result diagnosis) {
+	nameWidth := 0
+	for _, c := range result.Checks {
+		nameWidth = max(nameWidth, len(c.Name))
+	}
+	for _, c := range result.Checks {
+		fprintf(out, "%-9s %-*s  %s\n", "["+strings.ToUpper(c.Status)+"]", nameWidth, c.Name, c.Message)
+		if c.Fix != "" {
+			fprintf(out, "%-9s %-*s  Fix: %s\n", "", nameWidth, "", c.Fix)
+		}
+	}
+	fprintln(out)
+	switch {
+	case result.Errors == 0 && result.Warnings == 0:
+		fprintln(out, "No problems found")
+	default:
+		fprintf(out, "Found %s and %s\n", plural(result.Errors, "error"), plural(result.Warnings, "warning"))
+	}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
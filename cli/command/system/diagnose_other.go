@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !freebsd
+
+package system
+
+import "os"
+
+func socketGroup(os.FileInfo) string {
+	return ""
+}
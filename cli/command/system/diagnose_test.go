@@ -0,0 +1,97 @@
+package system
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDiagnoseUnreachable(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{
+	"credStore": "desktop",
+	"credHelpers": {"gcr.io": "gcloud"},
+	"auths": {"https://index.docker.io/v1/": {"auth": "dXNlcjpwYXNz"}}
+}`), 0o644))
+	cfg, err := config.Load(dir)
+	assert.NilError(t, err)
+
+	fakeCLI := test.NewFakeCli(&fakeClient{pingFunc: func(context.Context) (types.Ping, error) {
+		return types.Ping{}, errors.New("connection refused")
+	}})
+	fakeCLI.SetConfigFile(cfg)
+	cmd := NewDiagnoseCommand(fakeCLI)
+	cmd.SetArgs([]string{"--skip", "socket", "--format", "{{range .Checks}}{{.Name}} {{.Status}}: {{.Message}}{{println}}{{end}}{{.Errors}} {{.Warnings}}"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.DeepEqual(cmd.Execute(), cli.StatusError{StatusCode: 1}))
+
+	configFile := filepath.Join(dir, "config.json")
+	assert.Check(t, is.Equal(fakeCLI.OutBuffer().String(), `socket skipped: skipped with --skip
+context error: the daemon of context "default" () isn't reachable: connection refused
+version skipped: the daemon isn't reachable
+config warning: unknown property "credStore" in `+configFile+`
+config warning: `+configFile+` contains credentials, and can be read by other users
+config warning: the credentials of registries are stored unencrypted in `+configFile+`
+credentials error: the credential helper "gcloud" isn't installed: docker-credential-gcloud was not found in the PATH
+disk skipped: the daemon isn't reachable
+dns skipped: the daemon isn't reachable
+2 3
+`))
+}
+
+func TestDiagnoseVersion(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		status     string
+		fix        string
+	}{
+		{apiVersion: api.DefaultVersion, status: diagnoseOK},
+		{apiVersion: "1.24", status: diagnoseWarning, fix: "Update the Docker Engine"},
+		{apiVersion: "9.99", status: diagnoseWarning, fix: "Update the Docker CLI"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.apiVersion, func(t *testing.T) {
+			d := &diagnoser{reachable: true, dockerCli: test.NewFakeCli(&fakeClient{serverVersion: func(context.Context) (types.Version, error) {
+				return types.Version{Version: "99.0.0", APIVersion: tc.apiVersion}, nil
+			}})}
+			checks := d.checkVersion(context.Background())
+			assert.Assert(t, is.Len(checks, 1))
+			assert.Check(t, is.Equal(checks[0].Status, tc.status))
+			assert.Check(t, is.Equal(checks[0].Fix, tc.fix))
+		})
+	}
+}
+
+func TestSuggestConfigProperty(t *testing.T) {
+	for name, expected := range map[string]string{
+		"credStore":    "credsStore",
+		"PSFORMAT":     "psFormat",
+		"currentContx": "currentContext",
+		"kubernetes":   "",
+	} {
+		assert.Check(t, is.Equal(suggestConfigProperty(name), expected), name)
+	}
+}
+
+func TestDiagnoseInvalidSkip(t *testing.T) {
+	cmd := NewDiagnoseCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--skip", "dsn"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), `invalid check "dsn": must be one of socket, context, version, config, credentials, disk, dns`))
+}
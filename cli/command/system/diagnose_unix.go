@@ -0,0 +1,24 @@
+//go:build linux || darwin || freebsd
+
+package system
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// socketGroup returns the name of the group that owns a socket, or an empty
+// string if it's unknown.
+func socketGroup(fi os.FileInfo) string {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	g, err := user.LookupGroupId(strconv.FormatUint(uint64(st.Gid), 10))
+	if err != nil {
+		return ""
+	}
+	return g.Name
+}
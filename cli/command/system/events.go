@@ -2,8 +2,10 @@ package system
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 	"text/template"
@@ -13,18 +15,22 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/command/watch"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/opts"
 	"github.com/docker/cli/templates"
 	"github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
 type eventsOptions struct {
-	since  string
-	until  string
-	filter opts.FilterOpt
-	format string
+	since     string
+	sinceBoot bool
+	until     string
+	filter    opts.FilterOpt
+	format    string
+	output    string
 }
 
 // NewEventsCommand creates a new cobra.Command for `docker events`
@@ -43,12 +49,15 @@ func NewEventsCommand(dockerCli command.Cli) *cobra.Command {
 		},
 		ValidArgsFunction: completion.NoComplete,
 	}
+	cmd.AddCommand(newEventsReplayCommand(dockerCli))
 
 	flags := cmd.Flags()
 	flags.StringVar(&options.since, "since", "", "Show all events created since timestamp")
+	flags.BoolVar(&options.sinceBoot, "since-boot", false, "Show all events that the daemon buffered since it started")
 	flags.StringVar(&options.until, "until", "", "Stream events until this timestamp")
 	flags.VarP(&options.filter, "filter", "f", "Filter output based on conditions provided")
 	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp) // using the same flag description as "inspect" commands for now.
+	flags.StringVarP(&options.output, "output", "o", "", "Also write events to a file, in NDJSON format")
 
 	return cmd
 }
@@ -61,11 +70,32 @@ func runEvents(ctx context.Context, dockerCli command.Cli, options *eventsOption
 			Status:     "Error parsing format: " + err.Error(),
 		}
 	}
+	since := options.since
+	if options.sinceBoot {
+		if since != "" {
+			return errors.New("conflicting options: --since and --since-boot cannot be used together")
+		}
+		// The daemon keeps a limited number of recent events in memory, which
+		// are lost when it restarts; requesting all events since the epoch
+		// returns all events that are buffered since the daemon started.
+		since = "0"
+	}
+	var capture *json.Encoder
+	if options.output != "" {
+		f, err := os.Create(options.output)
+		if err != nil {
+			return errors.Wrap(err, "failed to create output file")
+		}
+		defer f.Close()
+		capture = json.NewEncoder(f)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
-	evts, errs := dockerCli.Client().Events(ctx, events.ListOptions{
-		Since:   options.since,
-		Until:   options.until,
-		Filters: options.filter.Value(),
+	evts, errs := watch.Events(ctx, dockerCli.Client(), watch.Options{
+		Since:       since,
+		Until:       options.until,
+		Filters:     options.filter.Value(),
+		OnReconnect: watch.PrintReconnect(dockerCli.Err()),
 	})
 	defer cancel()
 
@@ -74,6 +104,11 @@ func runEvents(ctx context.Context, dockerCli command.Cli, options *eventsOption
 	for {
 		select {
 		case event := <-evts:
+			if capture != nil {
+				if err := capture.Encode(event); err != nil {
+					return errors.Wrap(err, "failed to write event to output file")
+				}
+			}
 			if err := handleEvent(out, event, tmpl); err != nil {
 				return err
 			}
@@ -100,6 +135,12 @@ func makeTemplate(format string) (*template.Template, error) {
 		return nil, nil
 	case formatter.JSONFormatKey:
 		format = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		format = formatter.YAMLFormat
+	}
+	format, err := formatter.ExpandJSONPath(format)
+	if err != nil {
+		return nil, err
 	}
 	tmpl, err := templates.Parse(format)
 	if err != nil {
@@ -0,0 +1,85 @@
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type eventsReplayOptions struct {
+	file   string
+	format string
+}
+
+// newEventsReplayCommand creates a new cobra.Command for `docker events replay`
+func newEventsReplayCommand(dockerCli command.Cli) *cobra.Command {
+	var options eventsReplayOptions
+
+	cmd := &cobra.Command{
+		Use:   "replay [OPTIONS] FILE",
+		Short: "Replay events captured with --output",
+		Long: `Replay events captured with "docker events --output", or any other stream of
+events in NDJSON format. Use "-" to read events from STDIN.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.file = args[0]
+			return runEventsReplay(cmd.Context(), dockerCli, &options)
+		},
+		Annotations: map[string]string{
+			"aliases": "docker system events replay, docker events replay",
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&options.format, "format", "", flagsHelper.InspectFormatHelp)
+
+	return cmd
+}
+
+func runEventsReplay(ctx context.Context, dockerCli command.Cli, options *eventsReplayOptions) error {
+	tmpl, err := makeTemplate(options.format)
+	if err != nil {
+		return cli.StatusError{
+			StatusCode: 64,
+			Status:     "Error parsing format: " + err.Error(),
+		}
+	}
+
+	var in io.Reader
+	if options.file == "-" {
+		in = dockerCli.In()
+	} else {
+		f, err := os.Open(options.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	out := dockerCli.Out()
+	dec := json.NewDecoder(in)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var event events.Message
+		if err := dec.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, "failed to read event")
+		}
+		if err := handleEvent(out, event, tmpl); err != nil {
+			return err
+		}
+	}
+}
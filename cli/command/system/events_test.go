@@ -1,20 +1,26 @@
 package system
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/docker/api/types/events"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
-func TestEventsFormat(t *testing.T) {
+func testEvents() []events.Message {
 	var evts []events.Message //nolint:prealloc
 	for i, action := range []events.Action{events.ActionCreate, events.ActionStart, events.ActionAttach, events.ActionDie} {
 		evts = append(evts, events.Message{
@@ -32,6 +38,11 @@ func TestEventsFormat(t *testing.T) {
 			TimeNano: int64(time.Second) * int64(i+1),
 		})
 	}
+	return evts
+}
+
+func TestEventsFormat(t *testing.T) {
+	evts := testEvents()
 	tests := []struct {
 		name, format string
 	}{
@@ -70,6 +81,8 @@ func TestEventsFormat(t *testing.T) {
 				return messages, errs
 			}})
 			cmd := NewEventsCommand(cli)
+			// The stream is only closed by the daemon when --until is set.
+			cmd.Flags().Set("until", "10")
 			if tc.format != "" {
 				cmd.Flags().Set("format", tc.format)
 			}
@@ -80,3 +93,123 @@ func TestEventsFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestEventsSinceBoot(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedSince string
+		expectedError string
+	}{
+		{
+			name:          "since-boot",
+			args:          []string{"--since-boot"},
+			expectedSince: "0",
+		},
+		{
+			name:          "since",
+			args:          []string{"--since", "10m"},
+			expectedSince: "10m",
+		},
+		{
+			name:          "conflicting options",
+			args:          []string{"--since-boot", "--since", "10m"},
+			expectedError: "conflicting options: --since and --since-boot cannot be used together",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{eventsFn: func(_ context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+				assert.Check(t, is.Equal(options.Since, tc.expectedSince))
+				errs := make(chan error, 1)
+				errs <- io.EOF
+				return nil, errs
+			}})
+			// The stream is only closed by the daemon when --until is set.
+			tc.args = append(tc.args, "--until", "10")
+			cmd := NewEventsCommand(cli)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.expectedError != "" {
+				assert.Check(t, is.Error(err, tc.expectedError))
+			} else {
+				assert.Check(t, err)
+			}
+		})
+	}
+}
+
+func TestEventsOutput(t *testing.T) {
+	evts := testEvents()
+	cli := test.NewFakeCli(&fakeClient{eventsFn: func(context.Context, events.ListOptions) (<-chan events.Message, <-chan error) {
+		messages := make(chan events.Message)
+		errs := make(chan error, 1)
+		go func() {
+			for _, msg := range evts {
+				messages <- msg
+			}
+			errs <- io.EOF
+		}()
+		return messages, errs
+	}})
+	output := filepath.Join(t.TempDir(), "events.ndjson")
+	cmd := NewEventsCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Action}}", "--output", output, "--until", "10"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "create\nstart\nattach\ndie\n"))
+
+	// The captured events are written one per line, in their original form.
+	content, err := os.ReadFile(output)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	assert.Assert(t, is.Len(lines, len(evts)))
+	for i, line := range lines {
+		var actual events.Message
+		assert.NilError(t, json.Unmarshal([]byte(line), &actual))
+		assert.Check(t, is.DeepEqual(actual, evts[i]))
+	}
+}
+
+func TestEventsReplay(t *testing.T) {
+	// Set to UTC timezone as timestamps in output are
+	// printed in the current timezone
+	t.Setenv("TZ", "UTC")
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range testEvents() {
+		assert.NilError(t, enc.Encode(e))
+	}
+	file := filepath.Join(t.TempDir(), "events.ndjson")
+	assert.NilError(t, os.WriteFile(file, buf.Bytes(), 0o644))
+
+	for _, tc := range []struct{ name, format string }{{name: "default"}, {name: "json", format: "json"}} {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cmd := NewEventsCommand(cli)
+			args := []string{"replay", file}
+			if tc.format != "" {
+				args = append(args, "--format", tc.format)
+			}
+			cmd.SetArgs(args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), "docker-events-"+tc.name+".golden")
+		})
+	}
+}
+
+func TestEventsReplayInvalid(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(`{"Type":"container","Action":"start"}
+not-json
+`))))
+	cmd := NewEventsCommand(cli)
+	cmd.SetArgs([]string{"replay", "--format", "{{.Action}}", "-"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "failed to read event"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "start\n"))
+}
@@ -0,0 +1,36 @@
+package system
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/container"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// newGPUsCommand creates a new cobra.Command for `docker system gpus`
+func newGPUsCommand(dockerCli command.Cli) *cobra.Command {
+	var opts container.GPUsOptions
+
+	cmd := &cobra.Command{
+		Use:   "gpus [OPTIONS]",
+		Short: "List the GPUs of the daemon",
+		Long: `List the NVIDIA GPUs of the host of the daemon, and the GPU runtime that makes
+them available to containers with "docker run --gpus".
+
+The GPUs are found from the device nodes of the host, through a helper
+container which is never started.`,
+		Args: cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return container.RunGPUs(cmd.Context(), dockerCli, opts)
+		},
+		Annotations:       map[string]string{"ostype": "linux"},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&opts.HelperImage, "helper-image", "busybox", "Image to use for the helper container that lists the GPUs")
+	return cmd
+}
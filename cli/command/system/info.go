@@ -17,6 +17,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/cli/cli/context/docker"
 	"github.com/docker/cli/cli/debug"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/cli/templates"
@@ -24,6 +25,7 @@ import (
 	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/registry"
 	"github.com/docker/go-units"
+	"github.com/fvbommel/sortorder"
 	"github.com/spf13/cobra"
 )
 
@@ -35,9 +37,19 @@ type clientInfo struct {
 	Debug bool
 	clientVersion
 	Plugins  []pluginmanager.Plugin
+	Contexts []contextInfo
 	Warnings []string
 }
 
+// contextInfo is the information about a context in the client information.
+type contextInfo struct {
+	Name           string
+	Description    string `json:",omitempty"`
+	DockerEndpoint string `json:",omitempty"`
+	Current        bool
+	Error          string `json:",omitempty"`
+}
+
 type dockerInfo struct {
 	// This field should/could be ServerInfo but is anonymous to
 	// preserve backwards compatibility in the JSON rendering
@@ -93,6 +105,12 @@ func runInfo(ctx context.Context, cmd *cobra.Command, dockerCli command.Cli, opt
 	}
 	if plugins, err := pluginmanager.ListPlugins(dockerCli, cmd.Root()); err == nil {
 		info.ClientInfo.Plugins = plugins
+		info.ClientInfo.Warnings = append(info.ClientInfo.Warnings, pluginWarnings(plugins)...)
+	} else {
+		info.ClientErrors = append(info.ClientErrors, err.Error())
+	}
+	if contexts, err := listContexts(dockerCli); err == nil {
+		info.ClientInfo.Contexts = contexts
 	} else {
 		info.ClientErrors = append(info.ClientErrors, err.Error())
 	}
@@ -123,6 +141,46 @@ func runInfo(ctx context.Context, cmd *cobra.Command, dockerCli command.Cli, opt
 	return formatInfo(dockerCli.Out(), info, opts.format)
 }
 
+// pluginWarnings returns the warnings about the plugins that aren't valid.
+func pluginWarnings(plugins []pluginmanager.Plugin) []string {
+	var warnings []string
+	for _, p := range plugins {
+		if p.Err != nil {
+			warnings = append(warnings, fmt.Sprintf("WARNING: Plugin %q is not valid: %s", p.Path, p.Err))
+		}
+	}
+	return warnings
+}
+
+// listContexts returns the information about the contexts of the client,
+// sorted by name.
+func listContexts(dockerCli command.Cli) ([]contextInfo, error) {
+	contextMap, err := dockerCli.ContextStore().List()
+	if err != nil {
+		return nil, err
+	}
+	curContext := dockerCli.CurrentContext()
+	contexts := make([]contextInfo, 0, len(contextMap))
+	for _, rawMeta := range contextMap {
+		c := contextInfo{Name: rawMeta.Name, Current: rawMeta.Name == curContext}
+		if meta, err := command.GetDockerContext(rawMeta); err != nil {
+			c.Error = err.Error()
+		} else {
+			c.Description = meta.Description
+			if ep, err := docker.EndpointFromContext(rawMeta); err != nil {
+				c.Error = err.Error()
+			} else {
+				c.DockerEndpoint = ep.Host
+			}
+		}
+		contexts = append(contexts, c)
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return sortorder.NaturalLess(contexts[i].Name, contexts[j].Name)
+	})
+	return contexts, nil
+}
+
 // placeHolders does a rudimentary match for possible placeholders in a
 // template, matching a '.', followed by an letter (a-z/A-Z).
 var placeHolders = regexp.MustCompile(`\.[a-zA-Z]`)
@@ -140,6 +198,11 @@ func needsServerInfo(template string, info dockerInfo) bool {
 	}
 
 	// A template is provided and has at least one field set.
+	template, err := formatter.ExpandJSONPath(template)
+	if err != nil {
+		// ignore parsing errors here, and let regular code handle them
+		return true
+	}
 	tmpl, err := templates.NewParse("", template)
 	if err != nil {
 		// ignore parsing errors here, and let regular code handle them
@@ -205,8 +268,6 @@ func prettyPrintClientInfo(streams command.Streams, info clientInfo) {
 				fprintf(streams.Out(), "  %s: %s (%s)\n", p.Name, p.ShortDescription, p.Vendor)
 				fprintlnNonEmpty(streams.Out(), "    Version: ", p.Version)
 				fprintlnNonEmpty(streams.Out(), "    Path:    ", p.Path)
-			} else {
-				info.Warnings = append(info.Warnings, fmt.Sprintf("WARNING: Plugin %q is not valid: %s", p.Path, p.Err))
 			}
 		}
 	}
@@ -458,15 +519,33 @@ func printSwarmInfo(output io.Writer, info system.Info) {
 }
 
 func formatInfo(output io.Writer, info dockerInfo, format string) error {
-	if format == formatter.JSONFormatKey {
+	switch format {
+	case formatter.JSONFormatKey:
 		format = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		format = formatter.YAMLFormat
 	}
 
-	// Ensure slice/array fields render as `[]` not `null`
-	if info.ClientInfo != nil && info.ClientInfo.Plugins == nil {
-		info.ClientInfo.Plugins = make([]pluginmanager.Plugin, 0)
+	// Ensure slice/array fields render as `[]` not `null`, so that the
+	// client information has the same fields in all cases.
+	if info.ClientInfo != nil {
+		ci := *info.ClientInfo
+		if ci.Plugins == nil {
+			ci.Plugins = make([]pluginmanager.Plugin, 0)
+		}
+		if ci.Contexts == nil {
+			ci.Contexts = make([]contextInfo, 0)
+		}
+		if ci.Warnings == nil {
+			ci.Warnings = make([]string, 0)
+		}
+		info.ClientInfo = &ci
 	}
 
+	format, err := formatter.ExpandJSONPath(format)
+	if err != nil {
+		return cli.StatusError{StatusCode: 64, Status: err.Error()}
+	}
 	tmpl, err := templates.Parse(format)
 	if err != nil {
 		return cli.StatusError{
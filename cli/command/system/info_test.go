@@ -299,6 +299,7 @@ func TestPrettyPrintInfo(t *testing.T) {
 				ClientInfo: &clientInfo{
 					clientVersion: clientVersion{Context: "default"},
 					Plugins:       samplePluginsInfo,
+					Warnings:      pluginWarnings(samplePluginsInfo),
 				},
 			},
 			prettyGolden:   "docker-info-plugins",
@@ -473,6 +474,46 @@ func TestFormatInfo(t *testing.T) {
 	}
 }
 
+func TestFormatInfoClientInfo(t *testing.T) {
+	info := dockerInfo{
+		ClientInfo: &clientInfo{
+			clientVersion: clientVersion{Context: "default"},
+			Plugins:       samplePluginsInfo,
+			Contexts: []contextInfo{
+				{Name: "default", DockerEndpoint: "unix:///var/run/docker.sock", Current: true},
+				{Name: "remote", Description: "remote host", DockerEndpoint: "ssh://remote"},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		template string
+		expected string
+	}{
+		{
+			template: "jsonpath={.ClientInfo.Plugins[*].Name}",
+			expected: "goodplugin unversionedplugin badplugin\n",
+		},
+		{
+			template: "{{range .ClientInfo.Plugins}}{{if .Err}}{{.Name}}: {{.Err}}{{end}}{{end}}",
+			expected: "badplugin: something wrong\n",
+		},
+		{
+			template: "{{range .ClientInfo.Contexts}}{{if not .Current}}{{.Name}} {{.DockerEndpoint}}{{end}}{{end}}",
+			expected: "remote ssh://remote\n",
+		},
+		{
+			template: "{{json .ClientInfo.Warnings}}",
+			expected: "[]\n",
+		},
+	} {
+		t.Run(tc.template, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			assert.NilError(t, formatInfo(cli.Out(), info, tc.template))
+			assert.Check(t, is.Equal(cli.OutBuffer().String(), tc.expected))
+		})
+	}
+}
+
 func TestNeedsServerInfo(t *testing.T) {
 	tests := []struct {
 		doc      string
@@ -514,6 +555,21 @@ func TestNeedsServerInfo(t *testing.T) {
 			template: "{{json .ClientInfo.Context}}",
 			expected: false,
 		},
+		{
+			doc:      "JSONPath (Server ID)",
+			template: "jsonpath={.ID}",
+			expected: true,
+		},
+		{
+			doc:      "JSONPath (Active context)",
+			template: "jsonpath={.ClientInfo.Context}",
+			expected: false,
+		},
+		{
+			doc:      "JSONPath (plugin names)",
+			template: "jsonpath={.ClientInfo.Plugins[*].Name}",
+			expected: false,
+		},
 	}
 
 	inf := dockerInfo{ClientInfo: &clientInfo{}}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/inspect"
 	flagsHelper "github.com/docker/cli/cli/flags"
 	"github.com/docker/docker/api/types"
@@ -23,6 +24,8 @@ type inspectOptions struct {
 	format      string
 	inspectType string
 	size        bool
+	diff        bool
+	diffFormat  string
 	ids         []string
 }
 
@@ -38,16 +41,60 @@ func NewInspectCommand(dockerCli command.Cli) *cobra.Command {
 			opts.ids = args
 			return runInspect(cmd.Context(), dockerCli, opts)
 		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeObjectNames(dockerCli, opts.inspectType)(cmd, args, toComplete)
+		},
 	}
 
 	flags := cmd.Flags()
 	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
 	flags.StringVar(&opts.inspectType, "type", "", "Return JSON for specified type")
 	flags.BoolVarP(&opts.size, "size", "s", false, "Display total file sizes if the type is container")
+	flags.BoolVar(&opts.diff, "diff", false, "Show the difference between two objects")
+	flags.StringVar(&opts.diffFormat, "diff-format", inspect.DiffFormatUnified, `Format of the difference shown with --diff ("unified", "json-patch")`)
 
 	return cmd
 }
 
+// completeObjectNames offers completion for the objects of the type
+// objectType, or for containers, images, networks, and volumes if no type is
+// given.
+func completeObjectNames(dockerCli command.Cli, objectType string) completion.ValidArgsFn {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var fns []completion.ValidArgsFn
+		switch objectType {
+		case "":
+			fns = []completion.ValidArgsFn{
+				completion.ContainerNames(dockerCli, true),
+				completion.ImageNames(dockerCli),
+				completion.NetworkNames(dockerCli),
+				completion.VolumeNames(dockerCli),
+			}
+		case "container":
+			fns = []completion.ValidArgsFn{completion.ContainerNames(dockerCli, true)}
+		case "image":
+			fns = []completion.ValidArgsFn{completion.ImageNames(dockerCli)}
+		case "network":
+			fns = []completion.ValidArgsFn{completion.NetworkNames(dockerCli)}
+		case "service":
+			fns = []completion.ValidArgsFn{completion.ServiceNames(dockerCli)}
+		case "volume":
+			fns = []completion.ValidArgsFn{completion.VolumeNames(dockerCli)}
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var names []string
+		for _, fn := range fns {
+			n, directive := fn(cmd, args, toComplete)
+			if directive == cobra.ShellCompDirectiveError {
+				return nil, directive
+			}
+			names = append(names, n...)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions) error {
 	var elementSearcher inspect.GetRefFunc
 	switch opts.inspectType {
@@ -56,6 +103,15 @@ func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions)
 	default:
 		return errors.Errorf("%q is not a valid value for --type", opts.inspectType)
 	}
+	if opts.diff {
+		if len(opts.ids) != 2 {
+			return errors.New("--diff requires exactly two objects to compare")
+		}
+		if opts.format != "" {
+			return errors.New("conflicting options: --diff and --format cannot be used together")
+		}
+		return inspect.Diff(dockerCli.Out(), opts.ids[0], opts.ids[1], opts.diffFormat, elementSearcher)
+	}
 	return inspect.Inspect(dockerCli.Out(), opts.ids, opts.format, elementSearcher)
 }
 
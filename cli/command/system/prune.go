@@ -29,6 +29,7 @@ type pruneOptions struct {
 	all             bool
 	pruneVolumes    bool
 	pruneBuildCache bool
+	interactive     bool
 	filter          opts.FilterOpt
 }
 
@@ -52,6 +53,7 @@ func newPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.BoolVarP(&options.all, "all", "a", false, "Remove all unused images not just dangling ones")
 	flags.BoolVar(&options.pruneVolumes, "volumes", false, "Prune anonymous volumes")
+	flags.BoolVarP(&options.interactive, "interactive", "i", false, "Review and select the objects to remove in a terminal")
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "label=<key>=<value>")`)
 	// "filter" flag is available in 1.28 (docker 17.04) and up
 	flags.SetAnnotation("filter", "version", []string{"1.28"})
@@ -76,6 +78,9 @@ func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions)
 	if options.pruneVolumes && options.filter.Value().Contains("until") {
 		return errors.New(`ERROR: The "until" filter is not supported with "--volumes"`)
 	}
+	if options.interactive {
+		return runInteractivePrune(ctx, dockerCli, options)
+	}
 	if !options.force {
 		r, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), confirmationMessage(dockerCli, options))
 		if err != nil {
@@ -0,0 +1,570 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stringid"
+	units "github.com/docker/go-units"
+	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
+)
+
+// anonymousVolumeLabel is the label that the daemon sets on anonymous
+// volumes, which are the only volumes that "docker system prune" removes.
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// pruneKind is the type of object that a pruneItem is, in the order in which
+// the objects are removed.
+type pruneKind int
+
+const (
+	kindContainer pruneKind = iota
+	kindNetwork
+	kindVolume
+	kindImage
+	kindBuildCache
+)
+
+var pruneKindNames = [...]string{
+	kindContainer:  "Containers",
+	kindNetwork:    "Networks",
+	kindVolume:     "Volumes",
+	kindImage:      "Images",
+	kindBuildCache: "Build cache",
+}
+
+// pruneItem is an object that "docker system prune --interactive" offers to
+// remove.
+type pruneItem struct {
+	kind     pruneKind
+	id       string
+	name     string
+	size     int64
+	selected bool
+
+	// containers are, for an image, the stopped containers that use the
+	// image, which must be removed before the image.
+	containers []string
+}
+
+// runInteractivePrune lists the objects that the prune would remove, lets
+// the user deselect the objects to keep, and removes the selected objects.
+func runInteractivePrune(ctx context.Context, dockerCli command.Cli, options pruneOptions) error {
+	if options.force {
+		return errors.New("--interactive and --force can't be used together")
+	}
+	if !dockerCli.In().IsTerminal() || !dockerCli.Out().IsTerminal() {
+		return errors.New("--interactive requires a terminal")
+	}
+
+	items, err := collectPruneItems(ctx, dockerCli, options)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		_, _ = fmt.Fprintln(dockerCli.Out(), "Nothing to prune")
+		return nil
+	}
+
+	if err := dockerCli.In().SetRawTerminal(); err != nil {
+		return err
+	}
+	height, width := dockerCli.Out().GetTtySize()
+	confirmed, err := selectPruneItems(dockerCli.In(), dockerCli.Out(), items, int(height), int(width))
+	dockerCli.In().RestoreTerminal()
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return errdefs.Cancelled(errors.New("system prune has been cancelled"))
+	}
+	return removePruneItems(ctx, dockerCli, items)
+}
+
+// collectPruneItems returns the objects that "docker system prune" would
+// remove with options, all selected, by type, and from the largest.
+func collectPruneItems(ctx context.Context, dockerCli command.Cli, options pruneOptions) ([]pruneItem, error) {
+	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value())
+	until, err := getUntil(pruneFilters)
+	if err != nil {
+		return nil, err
+	}
+	du, err := dockerCli.Client().DiskUsage(ctx, types.DiskUsageOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []pruneItem
+	pruned := map[string]bool{}
+	for _, c := range du.Containers {
+		switch c.State {
+		case "created", "exited", "dead":
+		default:
+			continue
+		}
+		if !matchPruneFilters(pruneFilters, until, c.Labels, time.Unix(c.Created, 0)) {
+			continue
+		}
+		name := stringid.TruncateID(c.ID)
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		items = append(items, pruneItem{kind: kindContainer, id: c.ID, name: name, size: c.SizeRw})
+		pruned[c.ID] = true
+	}
+
+	networks, err := dockerCli.Client().NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("dangling", "true")),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range networks {
+		if matchPruneFilters(pruneFilters, until, n.Labels, n.Created) {
+			items = append(items, pruneItem{kind: kindNetwork, id: n.ID, name: n.Name})
+		}
+	}
+
+	if options.pruneVolumes {
+		for _, v := range du.Volumes {
+			if v.Driver != "local" || v.UsageData == nil || v.UsageData.RefCount != 0 {
+				continue
+			}
+			if _, ok := v.Labels[anonymousVolumeLabel]; !ok {
+				continue
+			}
+			if matchPruneFilters(pruneFilters, time.Time{}, v.Labels, time.Time{}) {
+				items = append(items, pruneItem{kind: kindVolume, id: v.Name, name: v.Name, size: max(v.UsageData.Size, 0)})
+			}
+		}
+	}
+
+	usedBy := map[string][]string{}
+	for _, c := range du.Containers {
+		usedBy[c.ImageID] = append(usedBy[c.ImageID], c.ID)
+	}
+	for _, img := range du.Images {
+		if !options.all && !isDangling(img.RepoTags) {
+			continue
+		}
+		// Images are removed after the containers, so images that are only
+		// used by containers that are pruned are pruned as well.
+		used := false
+		for _, id := range usedBy[img.ID] {
+			used = used || !pruned[id]
+		}
+		if used || !matchPruneFilters(pruneFilters, until, img.Labels, time.Unix(img.Created, 0)) {
+			continue
+		}
+		size := img.Size
+		if img.SharedSize > 0 {
+			size -= img.SharedSize
+		}
+		items = append(items, pruneItem{kind: kindImage, id: img.ID, name: imageName(img.ID, img.RepoTags), size: size, containers: usedBy[img.ID]})
+	}
+
+	if options.pruneBuildCache {
+		for _, bc := range du.BuildCache {
+			if bc.InUse || (!options.all && (bc.Type == "internal" || bc.Type == "frontend")) {
+				continue
+			}
+			lastUsed := bc.CreatedAt
+			if bc.LastUsedAt != nil {
+				lastUsed = *bc.LastUsedAt
+			}
+			if !until.IsZero() && lastUsed.After(until) {
+				continue
+			}
+			name := stringid.TruncateID(bc.ID)
+			if bc.Description != "" {
+				name += " " + bc.Description
+			}
+			items = append(items, pruneItem{kind: kindBuildCache, id: bc.ID, name: name, size: bc.Size})
+		}
+	}
+
+	for i := range items {
+		items[i].selected = true
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].kind != items[j].kind {
+			return items[i].kind < items[j].kind
+		}
+		if items[i].size != items[j].size {
+			return items[i].size > items[j].size
+		}
+		return sortorder.NaturalLess(items[i].name, items[j].name)
+	})
+	return items, nil
+}
+
+// getUntil returns the time of the "until" prune filter, or the zero time if
+// it's not set.
+func getUntil(pruneFilters filters.Args) (time.Time, error) {
+	until := pruneFilters.Get("until")
+	if len(until) == 0 {
+		return time.Time{}, nil
+	}
+	if len(until) > 1 {
+		return time.Time{}, errdefs.InvalidParameter(errors.New("more than one until filter specified"))
+	}
+	ts, err := timetypes.GetTimestamp(until[0], time.Now())
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, errdefs.InvalidParameter(err)
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
+
+// matchPruneFilters returns whether an object with labels, created at
+// created, matches the "label", "label!", and "until" prune filters, in the
+// same way as the prune API endpoints.
+func matchPruneFilters(pruneFilters filters.Args, until time.Time, labels map[string]string, created time.Time) bool {
+	if !until.IsZero() && created.After(until) {
+		return false
+	}
+	if pruneFilters.Contains("label") && !pruneFilters.MatchKVList("label", labels) {
+		return false
+	}
+	if pruneFilters.Contains("label!") && pruneFilters.MatchKVList("label!", labels) {
+		return false
+	}
+	return true
+}
+
+// isDangling returns whether an image with repoTags has no tags.
+func isDangling(repoTags []string) bool {
+	for _, tag := range repoTags {
+		if tag != "<none>:<none>" {
+			return false
+		}
+	}
+	return true
+}
+
+// removePruneItems removes the selected items, and prints the removed
+// objects and the reclaimed space.
+func removePruneItems(ctx context.Context, dockerCli command.Cli, items []pruneItem) error {
+	var (
+		spaceReclaimed uint64
+		removed        = map[string]bool{}
+		output         [len(pruneKindNames)]strings.Builder
+		cacheIDs       = filters.NewArgs()
+	)
+	for _, item := range items {
+		if !item.selected {
+			continue
+		}
+		var err error
+		switch item.kind {
+		case kindContainer:
+			err = dockerCli.Client().ContainerRemove(ctx, item.id, container.RemoveOptions{})
+		case kindNetwork:
+			err = dockerCli.Client().NetworkRemove(ctx, item.id)
+		case kindVolume:
+			err = dockerCli.Client().VolumeRemove(ctx, item.id, false)
+		case kindImage:
+			dels := removePruneImage(ctx, dockerCli, item, removed)
+			for _, d := range dels {
+				if d.Untagged != "" {
+					output[kindImage].WriteString("untagged: " + d.Untagged + "\n")
+				} else {
+					output[kindImage].WriteString("deleted: " + d.Deleted + "\n")
+				}
+			}
+			if len(dels) > 0 {
+				spaceReclaimed += uint64(item.size)
+			}
+			continue
+		case kindBuildCache:
+			cacheIDs.Add("id", item.id)
+			continue
+		}
+		if err != nil {
+			// Like the prune endpoints, continue with the other objects.
+			_, _ = fmt.Fprintln(dockerCli.Err(), err)
+			continue
+		}
+		removed[item.id] = true
+		output[item.kind].WriteString(item.id + "\n")
+		spaceReclaimed += uint64(item.size)
+	}
+	if cacheIDs.Len() > 0 {
+		report, err := dockerCli.Client().BuildCachePrune(ctx, types.BuildCachePruneOptions{All: true, Filters: cacheIDs})
+		if err != nil {
+			return err
+		}
+		for _, id := range report.CachesDeleted {
+			output[kindBuildCache].WriteString(id + "\n")
+		}
+		spaceReclaimed += report.SpaceReclaimed
+	}
+
+	for kind, sb := range output {
+		if sb.Len() == 0 {
+			continue
+		}
+		title := "Deleted " + pruneKindNames[kind]
+		if pruneKind(kind) == kindBuildCache {
+			title = "Deleted build cache objects"
+		}
+		_, _ = fmt.Fprintf(dockerCli.Out(), "%s:\n%s\n", title, sb.String())
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), "Total reclaimed space:", units.HumanSize(float64(spaceReclaimed)))
+	return nil
+}
+
+// removePruneImage removes the image of item, unless it's used by a
+// container that wasn't removed, and returns the untagged and deleted images.
+func removePruneImage(ctx context.Context, dockerCli command.Cli, item pruneItem, removed map[string]bool) []image.DeleteResponse {
+	for _, id := range item.containers {
+		if !removed[id] {
+			_, _ = fmt.Fprintf(dockerCli.Err(), "Keeping image %s, which is used by container %s\n", item.name, stringid.TruncateID(id))
+			return nil
+		}
+	}
+	dels, err := dockerCli.Client().ImageRemove(ctx, item.id, image.RemoveOptions{Force: true, PruneChildren: true})
+	if err != nil && !errdefs.IsConflict(err) && !errdefs.IsNotFound(err) {
+		// A conflict means that the image is the parent of another image,
+		// and "not found" that it was removed with one of its children, which
+		// the prune API ignores as well.
+		_, _ = fmt.Fprintln(dockerCli.Err(), err)
+	}
+	return dels
+}
+
+// pruneKey is a key that the selection of the objects to prune responds to.
+type pruneKey int
+
+const (
+	keyOther pruneKey = iota
+	keyUp
+	keyDown
+	keyToggle
+	keyToggleAll
+	keyConfirm
+	keyCancel
+)
+
+// readPruneKey reads a key from the terminal in raw mode.
+func readPruneKey(r *bufio.Reader) (pruneKey, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return keyOther, err
+	}
+	switch b {
+	case 'k':
+		return keyUp, nil
+	case 'j':
+		return keyDown, nil
+	case ' ':
+		return keyToggle, nil
+	case 'a':
+		return keyToggleAll, nil
+	case '\r', '\n':
+		return keyConfirm, nil
+	case 'q', 3 /* Ctrl-C */, 4 /* Ctrl-D */ :
+		return keyCancel, nil
+	case 0x1b:
+		// A lone escape cancels, and the arrow keys are escape sequences,
+		// which arrive at once.
+		if r.Buffered() < 2 {
+			return keyCancel, nil
+		}
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(r, seq); err != nil {
+			return keyOther, err
+		}
+		switch string(seq) {
+		case "[A", "OA":
+			return keyUp, nil
+		case "[B", "OB":
+			return keyDown, nil
+		}
+	}
+	return keyOther, nil
+}
+
+// pruneRow is a line of the selection: the header of the objects of a type
+// if item is -1, or an object.
+type pruneRow struct {
+	kind pruneKind
+	item int
+}
+
+// pruneSelection is the state of the selection of the objects to prune.
+type pruneSelection struct {
+	items  []pruneItem
+	rows   []pruneRow
+	cursor int
+	offset int
+}
+
+func newPruneSelection(items []pruneItem) *pruneSelection {
+	s := &pruneSelection{items: items}
+	for i, item := range items {
+		if i == 0 || items[i-1].kind != item.kind {
+			s.rows = append(s.rows, pruneRow{kind: item.kind, item: -1})
+		}
+		s.rows = append(s.rows, pruneRow{kind: item.kind, item: i})
+	}
+	return s
+}
+
+// toggle selects the objects of the row at the cursor, or deselects them if
+// they're all selected.
+func (s *pruneSelection) toggle() {
+	row := s.rows[s.cursor]
+	if row.item >= 0 {
+		s.items[row.item].selected = !s.items[row.item].selected
+		return
+	}
+	s.setSelected(func(item pruneItem) bool { return item.kind == row.kind })
+}
+
+// toggleAll selects all the objects, or deselects them if they're all
+// selected.
+func (s *pruneSelection) toggleAll() {
+	s.setSelected(func(pruneItem) bool { return true })
+}
+
+func (s *pruneSelection) setSelected(match func(pruneItem) bool) {
+	all := true
+	for _, item := range s.items {
+		if match(item) && !item.selected {
+			all = false
+		}
+	}
+	for i := range s.items {
+		if match(s.items[i]) {
+			s.items[i].selected = !all
+		}
+	}
+}
+
+// render writes the rows that fit in height lines, or all the rows if
+// height is 0, with the names truncated to fit in width columns.
+func (s *pruneSelection) render(height, width int) string {
+	visible := len(s.rows)
+	if height > 0 && height-3 < visible {
+		visible = max(height-3, 1)
+	}
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	} else if s.cursor >= s.offset+visible {
+		s.offset = s.cursor - visible + 1
+	}
+
+	nameWidth := 0
+	for _, item := range s.items {
+		nameWidth = max(nameWidth, len(item.name))
+	}
+	if width > 0 {
+		nameWidth = max(min(nameWidth, width-20), 10)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Select the objects to remove: up/down to move, space to select, a to select all, enter to remove, q to cancel\r\n")
+	for i := s.offset; i < s.offset+visible; i++ {
+		cursor := "  "
+		if i == s.cursor {
+			cursor = "> "
+		}
+		row := s.rows[i]
+		if row.item < 0 {
+			count, total, size := s.summary(func(item pruneItem) bool { return item.kind == row.kind })
+			fmt.Fprintf(&sb, "%s%s (%d of %d, %s)\r\n", cursor, pruneKindNames[row.kind], count, total, units.HumanSize(float64(size)))
+			continue
+		}
+		item := s.items[row.item]
+		check := " "
+		if item.selected {
+			check = "x"
+		}
+		name := item.name
+		if len(name) > nameWidth {
+			name = name[:nameWidth-3] + "..."
+		}
+		size := ""
+		if item.kind != kindNetwork {
+			size = units.HumanSize(float64(item.size))
+		}
+		line := fmt.Sprintf("%s  [%s] %-*s  %s", cursor, check, nameWidth, name, size)
+		sb.WriteString(strings.TrimRight(line, " ") + "\r\n")
+	}
+	count, _, size := s.summary(func(pruneItem) bool { return true })
+	fmt.Fprintf(&sb, "Selected %d objects, %s\r\n", count, units.HumanSize(float64(size)))
+	return sb.String()
+}
+
+// summary returns the number of selected objects that match, the number of
+// objects that match, and the size of the selected objects that match.
+func (s *pruneSelection) summary(match func(pruneItem) bool) (count, total int, size int64) {
+	for _, item := range s.items {
+		if !match(item) {
+			continue
+		}
+		total++
+		if item.selected {
+			count++
+			size += item.size
+		}
+	}
+	return count, total, size
+}
+
+// selectPruneItems lets the user select the items to remove with the keys
+// read from in, and returns whether the user confirmed the selection.
+func selectPruneItems(in io.Reader, out io.Writer, items []pruneItem, height, width int) (bool, error) {
+	s := newPruneSelection(items)
+	r := bufio.NewReader(in)
+	lines := 0
+	for {
+		view := s.render(height, width)
+		if lines > 0 {
+			// Move to the start of the previous view, and clear it.
+			_, _ = fmt.Fprintf(out, "\x1b[%dA\x1b[J", lines)
+		}
+		_, _ = io.WriteString(out, view)
+		lines = strings.Count(view, "\n")
+
+		key, err := readPruneKey(r)
+		if err != nil {
+			if err == io.EOF {
+				return false, nil
+			}
+			return false, err
+		}
+		switch key {
+		case keyUp:
+			s.cursor = max(s.cursor-1, 0)
+		case keyDown:
+			s.cursor = min(s.cursor+1, len(s.rows)-1)
+		case keyToggle:
+			s.toggle()
+		case keyToggleAll:
+			s.toggleAll()
+		case keyConfirm:
+			return true, nil
+		case keyCancel:
+			return false, nil
+		}
+	}
+}
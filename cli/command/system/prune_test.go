@@ -2,12 +2,18 @@ package system
 
 import (
 	"context"
+	"io"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/docker/cli/cli/config/configfile"
+	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
@@ -71,3 +77,122 @@ func TestSystemPrunePromptTermination(t *testing.T) {
 	cmd := newPruneCommand(cli)
 	test.TerminatePrompt(ctx, t, cmd, cli)
 }
+
+func TestSelectPruneItems(t *testing.T) {
+	tests := []struct {
+		keys      string
+		confirmed bool
+		selected  []bool
+	}{
+		{keys: "\r", confirmed: true, selected: []bool{true, true, true}},
+		{keys: "j \r", confirmed: true, selected: []bool{false, true, true}},
+		{keys: "\x1b[B\x1b[B\x1b[B\x1b[A \r", confirmed: true, selected: []bool{true, false, true}},
+		{keys: "jjj \r", confirmed: true, selected: []bool{true, true, false}},
+		{keys: " \r", confirmed: true, selected: []bool{false, false, true}},
+		{keys: "a\r", confirmed: true, selected: []bool{false, false, false}},
+		{keys: "j a\r", confirmed: true, selected: []bool{true, true, true}},
+		{keys: " q", selected: []bool{false, false, true}},
+		{keys: "\x1b", selected: []bool{true, true, true}},
+		{keys: "", selected: []bool{true, true, true}},
+	}
+	for _, tc := range tests {
+		t.Run(strconv.Quote(tc.keys), func(t *testing.T) {
+			items := []pruneItem{
+				{kind: kindContainer, id: "c1", name: "web-1", size: 100, selected: true},
+				{kind: kindContainer, id: "c2", name: "web-2", size: 50, selected: true},
+				{kind: kindImage, id: "sha256:1111", name: "111111111111", size: 1000, selected: true},
+			}
+			confirmed, err := selectPruneItems(strings.NewReader(tc.keys), io.Discard, items, 0, 0)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(confirmed, tc.confirmed))
+			for i, item := range items {
+				assert.Check(t, is.Equal(item.selected, tc.selected[i]), item.name)
+			}
+		})
+	}
+}
+
+func TestPruneSelectionRender(t *testing.T) {
+	s := newPruneSelection([]pruneItem{
+		{kind: kindContainer, id: "c1", name: "web-1", size: 100, selected: true},
+		{kind: kindContainer, id: "c2", name: "a-very-long-container-name", size: 50},
+		{kind: kindNetwork, id: "n1", name: "web_default", selected: true},
+	})
+	s.cursor = 4
+	assert.Check(t, is.Equal(s.render(5, 30), "Select the objects to remove: up/down to move, space to select, a to select all, enter to remove, q to cancel\r\n"+
+		"  Networks (1 of 1, 0B)\r\n"+
+		">   [x] web_def...\r\n"+
+		"Selected 2 objects, 100B\r\n"))
+	s.cursor = 0
+	assert.Check(t, is.Equal(s.render(0, 30), "Select the objects to remove: up/down to move, space to select, a to select all, enter to remove, q to cancel\r\n"+
+		"> Containers (1 of 2, 100B)\r\n"+
+		"    [x] web-1       100B\r\n"+
+		"    [ ] a-very-...  50B\r\n"+
+		"  Networks (1 of 1, 0B)\r\n"+
+		"    [x] web_def...\r\n"+
+		"Selected 2 objects, 100B\r\n"))
+}
+
+func TestPruneInteractive(t *testing.T) {
+	t.Setenv("NORAW", "1")
+	var removed []string
+	cli := test.NewFakeCli(&fakeClient{
+		version: "1.30",
+		diskUsageFunc: func(context.Context, types.DiskUsageOptions) (types.DiskUsage, error) {
+			return types.DiskUsage{
+				Images: []*image.Summary{
+					{ID: "sha256:1111", RepoTags: []string{"<none>:<none>"}, Size: 1000},
+					{ID: "sha256:2222", Size: 2000},
+					{ID: "sha256:3333", RepoTags: []string{"busybox:latest"}, Size: 3000},
+				},
+				Containers: []*types.Container{
+					{ID: "c1", Names: []string{"/running"}, ImageID: "sha256:3333", State: "running"},
+					{ID: "c2", Names: []string{"/stopped"}, ImageID: "sha256:2222", State: "exited", SizeRw: 100},
+				},
+			}, nil
+		},
+		networkListFunc: func(_ context.Context, options network.ListOptions) ([]network.Summary, error) {
+			assert.Check(t, is.Equal(options.Filters.Get("dangling")[0], "true"))
+			return []network.Summary{{ID: "n1", Name: "unused"}}, nil
+		},
+		containerRemoveFunc: func(_ context.Context, id string, _ container.RemoveOptions) error {
+			removed = append(removed, id)
+			return nil
+		},
+		networkRemoveFunc: func(_ context.Context, id string) error {
+			removed = append(removed, id)
+			return nil
+		},
+		imageRemoveFunc: func(_ context.Context, id string, _ image.RemoveOptions) ([]image.DeleteResponse, error) {
+			removed = append(removed, id)
+			return []image.DeleteResponse{{Deleted: id}}, nil
+		},
+	})
+	cli.In().SetIsTerminal(true)
+	cli.Out().SetIsTerminal(true)
+	// Deselect the stopped container, which keeps its image, and the network.
+	cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader("j jj \r"))))
+	cli.In().SetIsTerminal(true)
+
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--interactive"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(removed, []string{"sha256:1111"}))
+	assert.Check(t, is.Contains(cli.OutBuffer().String(), "Deleted Images:\ndeleted: sha256:1111\n\nTotal reclaimed space: 1kB\n"))
+	assert.Check(t, is.Equal(cli.ErrBuffer().String(), "Keeping image 2222, which is used by container c2\n"))
+}
+
+func TestPruneInteractiveRequiresTerminal(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{version: "1.30"})
+	cmd := newPruneCommand(cli)
+	cmd.SetArgs([]string{"--interactive"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "--interactive requires a terminal"))
+
+	cmd = newPruneCommand(cli)
+	cmd.SetArgs([]string{"--interactive", "--force"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "--interactive and --force can't be used together"))
+}
@@ -207,9 +207,15 @@ func newVersionTemplate(templateFormat string) (*template.Template, error) {
 		templateFormat = defaultVersionTemplate
 	case formatter.JSONFormatKey:
 		templateFormat = formatter.JSONFormat
+	case formatter.YAMLFormatKey:
+		templateFormat = formatter.YAMLFormat
+	}
+	templateFormat, err := formatter.ExpandJSONPath(templateFormat)
+	if err != nil {
+		return nil, err
 	}
 	tmpl := templates.New("version").Funcs(template.FuncMap{"getDetailsOrder": getDetailsOrder})
-	tmpl, err := tmpl.Parse(templateFormat)
+	tmpl, err = tmpl.Parse(templateFormat)
 
 	return tmpl, errors.Wrap(err, "template parsing error")
 }
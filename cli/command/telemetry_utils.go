@@ -15,12 +15,17 @@ import (
 
 // BaseCommandAttributes returns an attribute.Set containing attributes to attach to metrics/traces
 func BaseCommandAttributes(cmd *cobra.Command, streams Streams) []attribute.KeyValue {
-	return append([]attribute.KeyValue{
+	attrs := append([]attribute.KeyValue{
 		attribute.String("command.name", getCommandName(cmd)),
 	}, stdioAttributes(streams)...)
+	if cmd.Flags().Lookup(disableContentTrustFlag) != nil {
+		attrs = append(attrs, attribute.Bool("command.content_trust.bypassed", contentTrustBypassed(cmd)))
+	}
+	return attrs
 }
 
 // InstrumentCobraCommands wraps all cobra commands' RunE funcs to set a command duration metric using otel.
+// It also records commands that bypass content trust in the audit log of content trust bypasses.
 //
 // Note: this should be the last func to wrap/modify the PersistentRunE/RunE funcs before command execution.
 //
@@ -54,6 +59,7 @@ func (cli *DockerCli) InstrumentCobraCommands(ctx context.Context, cmd *cobra.Co
 		cmd.RunE = func(cmd *cobra.Command, args []string) error {
 			// start the timer as the first step of every cobra command
 			stopInstrumentation := cli.StartInstrumentation(cmd)
+			cli.auditContentTrustBypass(cmd)
 			cmdErr := ogRunE(cmd, args)
 			stopInstrumentation(cmdErr)
 			return cmdErr
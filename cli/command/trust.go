@@ -1,15 +1,51 @@
 package command
 
 import (
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/docker/cli/cli/trust"
+	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
+const disableContentTrustFlag = "disable-content-trust"
+
 // AddTrustVerificationFlags adds content trust flags to the provided flagset
 func AddTrustVerificationFlags(fs *pflag.FlagSet, v *bool, trusted bool) {
-	fs.BoolVar(v, "disable-content-trust", !trusted, "Skip image verification")
+	fs.BoolVar(v, disableContentTrustFlag, !trusted, "Skip image verification")
 }
 
 // AddTrustSigningFlags adds "signing" flags to the provided flagset
 func AddTrustSigningFlags(fs *pflag.FlagSet, v *bool, trusted bool) {
-	fs.BoolVar(v, "disable-content-trust", !trusted, "Skip image signing")
+	fs.BoolVar(v, disableContentTrustFlag, !trusted, "Skip image signing")
+}
+
+// contentTrustBypassed returns whether cmd disables content trust with the
+// "--disable-content-trust" flag while content trust is enabled by the
+// DOCKER_CONTENT_TRUST environment variable, which sets the default of the
+// flag.
+func contentTrustBypassed(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup(disableContentTrustFlag)
+	return f != nil && f.Changed && f.DefValue == "false" && f.Value.String() == "true"
+}
+
+// auditContentTrustBypass records cmd in the audit log of content trust
+// bypasses if it bypasses content trust.
+func (cli *DockerCli) auditContentTrustBypass(cmd *cobra.Command) {
+	if !contentTrustBypassed(cmd) {
+		return
+	}
+	entry := trust.BypassEntry{
+		Time:    time.Now().UTC(),
+		Command: getFullCommandName(cmd),
+		Args:    cmd.Flags().Args(),
+	}
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	if err := trust.RecordBypass(entry); err != nil {
+		_, _ = fmt.Fprintln(cli.Err(), "WARNING: failed to record the content trust bypass in the audit log:", err)
+	}
 }
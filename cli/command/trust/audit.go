@@ -0,0 +1,141 @@
+package trust
+
+import (
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/cli/trust"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultAuditTableFormat = "table {{.Time}}\t{{.User}}\t{{.Command}}\t{{.Args}}"
+
+	auditTimeHeader    = "TIME"
+	auditUserHeader    = "USER"
+	auditCommandHeader = "COMMAND"
+	auditArgsHeader    = "ARGUMENTS"
+)
+
+type auditOptions struct {
+	since  string
+	format string
+}
+
+func newAuditCommand(dockerCli command.Cli) *cobra.Command {
+	var opts auditOptions
+
+	cmd := &cobra.Command{
+		Use:   "audit [OPTIONS]",
+		Short: "List commands that bypassed content trust",
+		Args:  cli.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAudit(dockerCli, opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.since, "since", "", `Only list bypasses since a timestamp (e.g. "2013-01-02T13:23:37Z") or relative (e.g. "42m" for 42 minutes)`)
+	flags.StringVar(&opts.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	return cmd
+}
+
+func runAudit(dockerCli command.Cli, opts auditOptions) error {
+	entries, err := trust.Bypasses()
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if opts.since != "" {
+		ts, err := timetypes.GetTimestamp(opts.since, time.Now())
+		if err != nil {
+			return err
+		}
+		seconds, nanoseconds, err := timetypes.ParseTimestamps(ts, 0)
+		if err != nil {
+			return err
+		}
+		since = time.Unix(seconds, nanoseconds)
+	}
+
+	// List the most recent bypasses first.
+	recent := make([]trust.BypassEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Time.Before(since) {
+			continue
+		}
+		recent = append(recent, entries[i])
+	}
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	auditCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newAuditFormat(format),
+	}
+	return auditWrite(auditCtx, recent)
+}
+
+// newAuditFormat returns a format for use with an audit Context
+func newAuditFormat(source string) formatter.Format {
+	switch source {
+	case formatter.TableFormatKey:
+		return defaultAuditTableFormat
+	case formatter.CSVFormatKey, formatter.TSVFormatKey:
+		return formatter.Format(defaultAuditTableFormat).Delimited(source)
+	}
+	return formatter.Format(source)
+}
+
+// auditWrite writes formatted audit log entries using the Context
+func auditWrite(ctx formatter.Context, entries []trust.BypassEntry) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, e := range entries {
+			if err := format(&auditContext{e: e}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	auditCtx := auditContext{}
+	auditCtx.Header = formatter.SubHeaderContext{
+		"Time":    auditTimeHeader,
+		"User":    auditUserHeader,
+		"Command": auditCommandHeader,
+		"Args":    auditArgsHeader,
+	}
+	return ctx.Write(&auditCtx, render)
+}
+
+type auditContext struct {
+	formatter.HeaderContext
+	e trust.BypassEntry
+}
+
+func (c *auditContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *auditContext) Time() string {
+	return c.e.Time.Format(time.RFC3339)
+}
+
+func (c *auditContext) User() string {
+	return c.e.User
+}
+
+func (c *auditContext) Command() string {
+	return c.e.Command
+}
+
+func (c *auditContext) Args() string {
+	return strings.Join(c.e.Args, " ")
+}
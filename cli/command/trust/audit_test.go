@@ -0,0 +1,59 @@
+package trust
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/trust"
+	"github.com/docker/cli/internal/test"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/golden"
+)
+
+func TestTrustAudit(t *testing.T) {
+	dir := fs.NewDir(t, "trust-audit")
+	configDir := config.Dir()
+	config.SetDir(dir.Path())
+	t.Cleanup(func() { config.SetDir(configDir) })
+
+	for _, e := range []trust.BypassEntry{
+		{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), User: "alice", Command: "docker pull", Args: []string{"alpine"}},
+		{Time: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), User: "bob", Command: "docker run", Args: []string{"--rm", "busybox", "true"}},
+		{Time: time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC), User: "alice", Command: "docker push", Args: []string{"example/app:1.0"}},
+	} {
+		assert.NilError(t, trust.RecordBypass(e))
+	}
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{name: "table", args: []string{}},
+		{name: "since", args: []string{"--since", "2024-05-02T00:00:00Z"}},
+		{name: "format", args: []string{"--format", "{{.Time}} {{.Command}}"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{})
+			cmd := newAuditCommand(cli)
+			cmd.SetArgs(tc.args)
+			assert.NilError(t, cmd.Execute())
+			golden.Assert(t, cli.OutBuffer().String(), "trust-audit-"+tc.name+".golden")
+		})
+	}
+}
+
+func TestTrustAuditEmpty(t *testing.T) {
+	dir := fs.NewDir(t, "trust-audit")
+	configDir := config.Dir()
+	config.SetDir(dir.Path())
+	t.Cleanup(func() { config.SetDir(configDir) })
+
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := newAuditCommand(cli)
+	cmd.SetArgs([]string{})
+	assert.NilError(t, cmd.Execute())
+	assert.Equal(t, cli.OutBuffer().String(), "TIME      USER      COMMAND   ARGUMENTS\n")
+}
@@ -20,6 +20,7 @@ func NewTrustCommand(dockerCli command.Cli) *cobra.Command {
 		newTrustKeyCommand(dockerCli),
 		newTrustSignerCommand(dockerCli),
 		newInspectCommand(dockerCli),
+		newAuditCommand(dockerCli),
 	)
 	return cmd
 }
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/trust"
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestAuditContentTrustBypass(t *testing.T) {
+	tests := []struct {
+		doc      string
+		trusted  bool
+		args     []string
+		expected bool
+	}{
+		{doc: "content trust enabled", trusted: true, args: []string{"alpine"}},
+		{doc: "content trust disabled", args: []string{"--disable-content-trust", "alpine"}},
+		{doc: "content trust explicitly enabled", trusted: true, args: []string{"--disable-content-trust=false", "alpine"}},
+		{doc: "content trust bypassed", trusted: true, args: []string{"--disable-content-trust", "alpine"}, expected: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			dir := fs.NewDir(t, "trust-audit")
+			configDir := config.Dir()
+			config.SetDir(dir.Path())
+			t.Cleanup(func() { config.SetDir(configDir) })
+
+			var untrusted bool
+			root := &cobra.Command{Use: "docker"}
+			cmd := &cobra.Command{Use: "pull"}
+			root.AddCommand(cmd)
+			AddTrustVerificationFlags(cmd.Flags(), &untrusted, tc.trusted)
+			assert.NilError(t, cmd.ParseFlags(tc.args))
+
+			cli, err := NewDockerCli()
+			assert.NilError(t, err)
+			cli.auditContentTrustBypass(cmd)
+			assert.Check(t, is.Equal(contentTrustBypassed(cmd), tc.expected))
+
+			entries, err := trust.Bypasses()
+			assert.NilError(t, err)
+			if !tc.expected {
+				assert.Check(t, is.Len(entries, 0))
+				return
+			}
+			assert.Assert(t, is.Len(entries, 1))
+			assert.Check(t, is.Equal(entries[0].Command, "docker pull"))
+			assert.Check(t, is.DeepEqual(entries[0].Args, []string{"alpine"}))
+		})
+	}
+}
@@ -9,13 +9,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/docker/cli/cli/streams"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
 	mounttypes "github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/errdefs"
 	"github.com/moby/sys/sequential"
@@ -122,6 +127,86 @@ func PromptForConfirmation(ctx context.Context, ins io.Reader, outs io.Writer, m
 	}
 }
 
+// EditContent opens content in the editor of the user, as set by the VISUAL
+// or EDITOR environment variables, and returns the edited content. The content
+// is written to a temporary file with the given name, so that the editor can
+// detect its format from the extension.
+func EditContent(name string, content []byte) ([]byte, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	args := strings.Fields(editor)
+
+	dir, err := os.MkdirTemp("", "docker-edit-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, filepath.Base(name))
+	if err := os.WriteFile(file, content, 0o600); err != nil {
+		return nil, err
+	}
+
+	// The editor is attached to the terminal directly, as it doesn't work
+	// when its streams are pipes.
+	c := exec.Command(args[0], append(args[1:], file)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return nil, errors.Wrapf(err, "editor %q failed", editor)
+	}
+	return os.ReadFile(file)
+}
+
+var versionSuffix = regexp.MustCompile(`^(.+)-v([0-9]+)$`)
+
+// NextVersionName returns the name of the next version of an object that
+// can't be updated, such as a config or a secret: "NAME-v2" for an object
+// without version, and "NAME-v<N+1>" for version N.
+func NextVersionName(name string) string {
+	if m := versionSuffix.FindStringSubmatch(name); m != nil {
+		if v, err := strconv.Atoi(m[2]); err == nil {
+			return m[1] + "-v" + strconv.Itoa(v+1)
+		}
+	}
+	return name + "-v2"
+}
+
+// RotateServices updates the services which container spec is changed by
+// rotate, such as to use the new version of a config or a secret. rotate
+// returns whether it changed the container spec.
+func RotateServices(ctx context.Context, dockerCli Cli, rotate func(*swarm.ContainerSpec) bool) error {
+	apiClient := dockerCli.Client()
+
+	services, err := apiClient.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		cspec := service.Spec.TaskTemplate.ContainerSpec
+		if cspec == nil || !rotate(cspec) {
+			continue
+		}
+		_, _ = fmt.Fprintf(dockerCli.Out(), "Updating service %s\n", service.Spec.Name)
+		response, err := apiClient.ServiceUpdate(ctx, service.ID, service.Version, service.Spec, types.ServiceUpdateOptions{
+			RegistryAuthFrom: types.RegistryAuthFromSpec,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to update service %s", service.Spec.Name)
+		}
+		for _, warning := range response.Warnings {
+			_, _ = fmt.Fprintln(dockerCli.Err(), warning)
+		}
+	}
+	return nil
+}
+
 // PruneFilters returns consolidated prune filters obtained from config.json and cli
 func PruneFilters(dockerCli Cli, pruneFilters filters.Args) filters.Args {
 	if dockerCli.ConfigFile() == nil {
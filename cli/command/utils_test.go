@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"syscall"
 	"testing"
@@ -18,6 +19,7 @@ import (
 	"github.com/docker/cli/internal/test"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func TestStringSliceReplaceAt(t *testing.T) {
@@ -80,6 +82,28 @@ func TestValidateOutputPath(t *testing.T) {
 	}
 }
 
+func TestEditContent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sed as editor")
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sed -i -e s/old/new/")
+	content, err := command.EditContent("config.yaml", []byte("value: old\n"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "value: new\n")
+
+	t.Setenv("VISUAL", "false")
+	_, err = command.EditContent("config.yaml", []byte("value: old\n"))
+	assert.ErrorContains(t, err, `editor "false" failed`)
+}
+
+func TestNextVersionName(t *testing.T) {
+	assert.Check(t, is.Equal(command.NextVersionName("app"), "app-v2"))
+	assert.Check(t, is.Equal(command.NextVersionName("app-v2"), "app-v3"))
+	assert.Check(t, is.Equal(command.NextVersionName("app-v9"), "app-v10"))
+	assert.Check(t, is.Equal(command.NextVersionName("-v1"), "-v1-v2"))
+}
+
 func TestPromptForConfirmation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
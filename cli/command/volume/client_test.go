@@ -2,10 +2,18 @@ package volume
 
 import (
 	"context"
+	"io"
+	"strings"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 type fakeClient struct {
@@ -15,6 +23,14 @@ type fakeClient struct {
 	volumeListFunc    func(filter filters.Args) (volume.ListResponse, error)
 	volumeRemoveFunc  func(volumeID string, force bool) error
 	volumePruneFunc   func(filter filters.Args) (volume.PruneReport, error)
+
+	containerCreateFunc   func(config *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error)
+	containerRemoveFunc   func(containerID string, options container.RemoveOptions) error
+	copyFromContainerFunc func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error)
+	copyToContainerFunc   func(containerID, dstPath string, content io.Reader, options container.CopyToContainerOptions) error
+	imageCreateFunc       func(parentReference string, options image.CreateOptions) (io.ReadCloser, error)
+	diskUsageFunc         func(options types.DiskUsageOptions) (types.DiskUsage, error)
+	eventsFunc            func(options events.ListOptions) (<-chan events.Message, <-chan error)
 }
 
 func (c *fakeClient) VolumeCreate(_ context.Context, options volume.CreateOptions) (volume.Volume, error) {
@@ -51,3 +67,54 @@ func (c *fakeClient) VolumeRemove(_ context.Context, volumeID string, force bool
 	}
 	return nil
 }
+
+func (c *fakeClient) ContainerCreate(_ context.Context, config *container.Config, hostConfig *container.HostConfig, _ *network.NetworkingConfig, _ *specs.Platform, _ string) (container.CreateResponse, error) {
+	if c.containerCreateFunc != nil {
+		return c.containerCreateFunc(config, hostConfig)
+	}
+	return container.CreateResponse{}, nil
+}
+
+func (c *fakeClient) ContainerRemove(_ context.Context, containerID string, options container.RemoveOptions) error {
+	if c.containerRemoveFunc != nil {
+		return c.containerRemoveFunc(containerID, options)
+	}
+	return nil
+}
+
+func (c *fakeClient) CopyFromContainer(_ context.Context, containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+	if c.copyFromContainerFunc != nil {
+		return c.copyFromContainerFunc(containerID, srcPath)
+	}
+	return io.NopCloser(strings.NewReader("")), container.PathStat{}, nil
+}
+
+func (c *fakeClient) CopyToContainer(_ context.Context, containerID, dstPath string, content io.Reader, options container.CopyToContainerOptions) error {
+	if c.copyToContainerFunc != nil {
+		return c.copyToContainerFunc(containerID, dstPath, content, options)
+	}
+	return nil
+}
+
+func (c *fakeClient) ImageCreate(_ context.Context, parentReference string, options image.CreateOptions) (io.ReadCloser, error) {
+	if c.imageCreateFunc != nil {
+		return c.imageCreateFunc(parentReference, options)
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
+func (c *fakeClient) DiskUsage(_ context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	if c.diskUsageFunc != nil {
+		return c.diskUsageFunc(options)
+	}
+	return types.DiskUsage{}, nil
+}
+
+func (c *fakeClient) Events(_ context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	if c.eventsFunc != nil {
+		return c.eventsFunc(options)
+	}
+	errs := make(chan error, 1)
+	errs <- io.EOF
+	return nil, errs
+}
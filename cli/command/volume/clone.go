@@ -0,0 +1,83 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type cloneOptions struct {
+	source      string
+	target      string
+	helperImage string
+}
+
+func newCloneCommand(dockerCli command.Cli) *cobra.Command {
+	var opts cloneOptions
+
+	cmd := &cobra.Command{
+		Use:   "clone [OPTIONS] SOURCE TARGET",
+		Short: "Copy a volume to a new volume",
+		Long: `Copy the content of a volume to a new volume, which is created with the driver
+and the labels of the source volume. The options of the driver are not copied.`,
+		Args: cli.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			opts.target = args[1]
+			return runClone(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper container that copies the volume's content")
+	return cmd
+}
+
+func runClone(ctx context.Context, dockerCli command.Cli, opts cloneOptions) error {
+	apiClient := dockerCli.Client()
+
+	src, err := apiClient.VolumeInspect(ctx, opts.source)
+	if err != nil {
+		return err
+	}
+	target, err := createTargetVolume(ctx, apiClient, src, opts.target)
+	if err != nil {
+		return err
+	}
+
+	if err := copyVolume(ctx, dockerCli, src.Name, target.Name, opts.helperImage); err != nil {
+		_ = apiClient.VolumeRemove(context.WithoutCancel(ctx), target.Name, true)
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), target.Name)
+	return nil
+}
+
+// createTargetVolume creates a volume with the given name, and with the
+// driver and the labels of src, to copy the content of src to. Unlike
+// VolumeCreate, it's an error if the volume already exists, so that the
+// content of an existing volume is never mixed with the copy.
+//
+// The options of the driver aren't copied, as they may refer to the
+// storage of src, such as the device of a "local" volume.
+func createTargetVolume(ctx context.Context, apiClient client.APIClient, src volume.Volume, name string) (volume.Volume, error) {
+	if _, err := apiClient.VolumeInspect(ctx, name); err == nil {
+		return volume.Volume{}, errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	} else if !errdefs.IsNotFound(err) {
+		return volume.Volume{}, err
+	}
+	return apiClient.VolumeCreate(ctx, volume.CreateOptions{
+		Name:   name,
+		Driver: src.Driver,
+		Labels: src.Labels,
+	})
+}
@@ -0,0 +1,115 @@
+package volume
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestVolumeClone(t *testing.T) {
+	var (
+		created volume.CreateOptions
+		mounts  []mount.Mount
+		copied  string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			if volumeID != "db" {
+				return volume.Volume{}, errdefs.NotFound(errors.Errorf("get %s: no such volume", volumeID))
+			}
+			return volume.Volume{
+				Name:    "db",
+				Driver:  "local",
+				Labels:  map[string]string{"com.example.app": "shop"},
+				Options: map[string]string{"type": "tmpfs"},
+			}, nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			created = options
+			return volume.Volume{Name: options.Name, Driver: options.Driver}, nil
+		},
+		containerCreateFunc: func(_ *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			mounts = hostConfig.Mounts
+			return container.CreateResponse{ID: "helper"}, nil
+		},
+		copyFromContainerFunc: func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			return io.NopCloser(strings.NewReader("content")), container.PathStat{}, nil
+		},
+		copyToContainerFunc: func(containerID, dstPath string, content io.Reader, options container.CopyToContainerOptions) error {
+			b, err := io.ReadAll(content)
+			copied = string(b)
+			return err
+		},
+	})
+	cmd := newCloneCommand(cli)
+	cmd.SetArgs([]string{"db", "db-copy"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.DeepEqual(created, volume.CreateOptions{
+		Name:   "db-copy",
+		Driver: "local",
+		Labels: map[string]string{"com.example.app": "shop"},
+	}))
+	assert.Check(t, is.DeepEqual(mounts, []mount.Mount{
+		{Type: mount.TypeVolume, Source: "db", Target: "/source", ReadOnly: true},
+		{Type: mount.TypeVolume, Source: "db-copy", Target: "/target"},
+	}))
+	assert.Check(t, is.Equal(copied, "content"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db-copy\n"))
+}
+
+func TestVolumeCloneTargetExists(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+		volumeCreateFunc: func(volume.CreateOptions) (volume.Volume, error) {
+			t.Error("unexpected volume create for an existing volume")
+			return volume.Volume{}, nil
+		},
+	})
+	cmd := newCloneCommand(cli)
+	cmd.SetArgs([]string{"db", "db-copy"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.Execute()
+	assert.Check(t, is.ErrorContains(err, "volume db-copy already exists"))
+	assert.Check(t, errdefs.IsConflict(err))
+}
+
+func TestVolumeCloneCopyError(t *testing.T) {
+	var removed []string
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			if volumeID != "db" {
+				return volume.Volume{}, errdefs.NotFound(errors.Errorf("get %s: no such volume", volumeID))
+			}
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			return volume.Volume{Name: options.Name}, nil
+		},
+		volumeRemoveFunc: func(volumeID string, force bool) error {
+			removed = append(removed, volumeID)
+			return nil
+		},
+		copyFromContainerFunc: func(string, string) (io.ReadCloser, container.PathStat, error) {
+			return nil, container.PathStat{}, errors.New("no space left on device")
+		},
+	})
+	cmd := newCloneCommand(cli)
+	cmd.SetArgs([]string{"db", "db-copy"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "failed to read content of volume db: no space left on device"))
+	assert.Check(t, is.DeepEqual(removed, []string{"db-copy"}))
+}
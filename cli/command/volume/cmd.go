@@ -16,12 +16,18 @@ func NewVolumeCommand(dockerCli command.Cli) *cobra.Command {
 		Annotations: map[string]string{"version": "1.21"},
 	}
 	cmd.AddCommand(
+		newCloneCommand(dockerCli),
 		newCreateCommand(dockerCli),
+		newDiskUsageCommand(dockerCli),
+		newExportCommand(dockerCli),
+		newImportCommand(dockerCli),
 		newInspectCommand(dockerCli),
 		newListCommand(dockerCli),
+		newMigrateCommand(dockerCli),
 		newRemoveCommand(dockerCli),
 		NewPruneCommand(dockerCli),
 		newUpdateCommand(dockerCli),
+		newSnapshotCommand(dockerCli),
 	)
 	return cmd
 }
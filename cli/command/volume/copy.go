@@ -0,0 +1,61 @@
+package volume
+
+import (
+	"context"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+)
+
+// defaultHelperImage is the image used for the (never started) helper
+// container that gives access to the content of volumes. The container is
+// only used as a mount-point for the archive endpoints, so any image that
+// exists for the daemon's platform will do.
+const defaultHelperImage = "busybox"
+
+const (
+	helperSourcePath = "/source"
+	helperTargetPath = "/target"
+)
+
+// copyVolume copies the content of the src volume to the dst volume,
+// preserving ownership of files.
+//
+// The copy is performed through the archive endpoints of a helper container
+// that has both volumes mounted; the helper container is never started, and
+// removed once the copy completes.
+func copyVolume(ctx context.Context, dockerCli command.Cli, src, dst, helperImage string) error {
+	apiClient := dockerCli.Client()
+	id, err := createHelperContainer(ctx, dockerCli, apiClient, helperImage, []mount.Mount{
+		{Type: mount.TypeVolume, Source: src, Target: helperSourcePath, ReadOnly: true},
+		{Type: mount.TypeVolume, Source: dst, Target: helperTargetPath},
+	})
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, apiClient, id)
+
+	content, _, err := apiClient.CopyFromContainer(ctx, id, helperSourcePath+"/.")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read content of volume %s", src)
+	}
+	defer content.Close()
+
+	err = apiClient.CopyToContainer(ctx, id, helperTargetPath, content, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write content to volume %s", dst)
+	}
+	return nil
+}
+
+// createHelperContainer creates a helper container with the given mounts on
+// the daemon of apiClient, pulling the helper image if it's not present yet.
+func createHelperContainer(ctx context.Context, dockerCli command.Cli, apiClient client.APIClient, helperImage string, mounts []mount.Mount) (string, error) {
+	return helpercontainer.Create(ctx, dockerCli, apiClient, helperImage, "com.docker.cli.volume-helper", &container.HostConfig{Mounts: mounts})
+}
@@ -100,6 +100,7 @@ func newCreateCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("topology-preferred", "version", []string{"1.42"})
 	flags.SetAnnotation("topology-preferred", "swarm", []string{"manager"})
 
+	_ = cmd.RegisterFlagCompletionFunc("driver", completion.VolumeDrivers(dockerCli))
 	return cmd
 }
 
@@ -0,0 +1,284 @@
+package volume
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	units "github.com/docker/go-units"
+	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type duOptions struct {
+	names       []string
+	format      string
+	sizeOnly    bool
+	helperImage string
+}
+
+func newDiskUsageCommand(dockerCli command.Cli) *cobra.Command {
+	var opts duOptions
+
+	cmd := &cobra.Command{
+		Use:   "du [OPTIONS] [VOLUME...]",
+		Short: "Show the disk usage of volumes",
+		Long: `Show the size, the number of files, and the time of the last modification of
+volumes, or of all volumes if none are specified.
+
+The content of each volume is read through a helper container, which is never
+started. With --size-only, only the size of volumes is shown, as reported by
+the disk usage of the daemon, which doesn't require helper containers but is
+only available for volumes of the "local" driver.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.names = args
+			return runDiskUsage(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	flags.BoolVar(&opts.sizeOnly, "size-only", false, "Only show the size of volumes, from the disk usage of the daemon")
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper container that reads the volume's content")
+	return cmd
+}
+
+// volumeUsage is the disk usage of a volume. Fields that aren't known are
+// negative, or zero for lastModified.
+type volumeUsage struct {
+	name         string
+	size         int64
+	files        int64
+	lastModified time.Time
+}
+
+func runDiskUsage(ctx context.Context, dockerCli command.Cli, opts duOptions) error {
+	var (
+		usage []volumeUsage
+		err   error
+	)
+	if opts.sizeOnly {
+		usage, err = volumeSizes(ctx, dockerCli, opts.names)
+	} else {
+		usage, err = scanVolumes(ctx, dockerCli, opts.names, opts.helperImage)
+	}
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		return sortorder.NaturalLess(usage[i].name, usage[j].name)
+	})
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	return diskUsageWrite(formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newDiskUsageFormat(format),
+	}, usage)
+}
+
+// volumeSizes returns the sizes of the volumes with the given names, or of
+// all volumes, from the disk usage of the daemon.
+func volumeSizes(ctx context.Context, dockerCli command.Cli, names []string) ([]volumeUsage, error) {
+	du, err := dockerCli.Client().DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.VolumeObject},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sizes := make(map[string]int64, len(du.Volumes))
+	for _, v := range du.Volumes {
+		sizes[v.Name] = -1
+		if v.UsageData != nil {
+			sizes[v.Name] = v.UsageData.Size
+		}
+	}
+	if len(names) == 0 {
+		for name := range sizes {
+			names = append(names, name)
+		}
+	}
+
+	usage := make([]volumeUsage, 0, len(names))
+	for _, name := range names {
+		size, ok := sizes[name]
+		if !ok {
+			return nil, errors.Errorf("no such volume: %s", name)
+		}
+		usage = append(usage, volumeUsage{name: name, size: size, files: -1})
+	}
+	return usage, nil
+}
+
+// scanVolumes returns the disk usage of the volumes with the given names, or
+// of all volumes, by reading their content through a helper container.
+func scanVolumes(ctx context.Context, dockerCli command.Cli, names []string, helperImage string) ([]volumeUsage, error) {
+	apiClient := dockerCli.Client()
+	if len(names) == 0 {
+		resp, err := apiClient.VolumeList(ctx, volume.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range resp.Volumes {
+			names = append(names, v.Name)
+		}
+	} else {
+		// Mounting a volume that doesn't exist creates it, so check that
+		// they exist first.
+		for _, name := range names {
+			if _, err := apiClient.VolumeInspect(ctx, name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	usage := make([]volumeUsage, 0, len(names))
+	for _, name := range names {
+		u, err := scanVolume(ctx, dockerCli, name, helperImage)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// scanVolume returns the disk usage of a volume, from an archive of its
+// content. The size is the sum of the sizes of its files, which may differ
+// from the disk space that they use.
+func scanVolume(ctx context.Context, dockerCli command.Cli, name, helperImage string) (volumeUsage, error) {
+	apiClient := dockerCli.Client()
+	id, err := createHelperContainer(ctx, dockerCli, apiClient, helperImage, []mount.Mount{
+		{Type: mount.TypeVolume, Source: name, Target: helperSourcePath, ReadOnly: true},
+	})
+	if err != nil {
+		return volumeUsage{}, err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, apiClient, id)
+
+	content, _, err := apiClient.CopyFromContainer(ctx, id, helperSourcePath+"/.")
+	if err != nil {
+		return volumeUsage{}, errors.Wrapf(err, "failed to read content of volume %s", name)
+	}
+	defer content.Close()
+
+	u := volumeUsage{name: name}
+	tr := tar.NewReader(content)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return volumeUsage{}, errors.Wrapf(err, "failed to read content of volume %s", name)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			u.files++
+			u.size += hdr.Size
+		}
+		if hdr.ModTime.After(u.lastModified) {
+			u.lastModified = hdr.ModTime
+		}
+	}
+	return u, nil
+}
+
+const (
+	defaultDiskUsageTableFormat = "table {{.Name}}\t{{.Size}}\t{{.Files}}\t{{.LastModified}}"
+
+	volumeNameHeader   = "VOLUME NAME"
+	filesHeader        = "FILES"
+	lastModifiedHeader = "LAST MODIFIED"
+)
+
+// newDiskUsageFormat returns a format for use with a disk usage Context
+func newDiskUsageFormat(source string) formatter.Format {
+	if source == formatter.TableFormatKey {
+		return defaultDiskUsageTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// diskUsageWrite writes the formatted disk usage of volumes using the Context
+func diskUsageWrite(ctx formatter.Context, usage []volumeUsage) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, u := range usage {
+			if err := format(&diskUsageContext{u: u}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newDiskUsageContext(), render)
+}
+
+type diskUsageContext struct {
+	formatter.HeaderContext
+	u volumeUsage
+}
+
+func newDiskUsageContext() *diskUsageContext {
+	duCtx := diskUsageContext{}
+	duCtx.Header = formatter.SubHeaderContext{
+		"Name":           volumeNameHeader,
+		"Size":           formatter.SizeHeader,
+		"Files":          filesHeader,
+		"LastModified":   lastModifiedHeader,
+		"LastModifiedAt": lastModifiedHeader,
+	}
+	return &duCtx
+}
+
+func (c *diskUsageContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *diskUsageContext) Name() string {
+	return c.u.name
+}
+
+func (c *diskUsageContext) Size() string {
+	if c.u.size < 0 {
+		return "N/A"
+	}
+	return units.HumanSize(float64(c.u.size))
+}
+
+func (c *diskUsageContext) Files() string {
+	if c.u.files < 0 {
+		return "N/A"
+	}
+	return strconv.FormatInt(c.u.files, 10)
+}
+
+// LastModified is the time since the newest modification time of the files
+// and directories of the volume.
+func (c *diskUsageContext) LastModified() string {
+	if c.u.lastModified.IsZero() {
+		return "N/A"
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(c.u.lastModified)) + " ago"
+}
+
+func (c *diskUsageContext) LastModifiedAt() string {
+	if c.u.lastModified.IsZero() {
+		return "N/A"
+	}
+	return c.u.lastModified.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,117 @@
+package volume
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func volumeArchive(t *testing.T, modTime time.Time, files map[string]string) io.ReadCloser {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	assert.NilError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "./", Mode: 0o755, ModTime: modTime}))
+	for name, content := range files {
+		assert.NilError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: modTime}))
+		_, err := tw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, tw.Close())
+	return io.NopCloser(&buf)
+}
+
+func TestVolumeDiskUsage(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var (
+		helperMounts []mount.Mount
+		removed      []string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		volumeListFunc: func(filters.Args) (volume.ListResponse, error) {
+			return volume.ListResponse{Volumes: []*volume.Volume{{Name: "logs"}, {Name: "db"}}}, nil
+		},
+		containerCreateFunc: func(_ *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			helperMounts = append(helperMounts, hostConfig.Mounts...)
+			return container.CreateResponse{ID: "helper-" + hostConfig.Mounts[0].Source}, nil
+		},
+		containerRemoveFunc: func(containerID string, _ container.RemoveOptions) error {
+			removed = append(removed, containerID)
+			return nil
+		},
+		copyFromContainerFunc: func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			assert.Check(t, is.Equal(srcPath, "/source/."))
+			if containerID == "helper-db" {
+				return volumeArchive(t, modTime, map[string]string{"data": "0123456789", "wal": "01234"}), container.PathStat{}, nil
+			}
+			return volumeArchive(t, modTime.Add(-time.Hour), nil), container.PathStat{}, nil
+		},
+	})
+
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--format", "{{.Name}} {{.Size}} {{.Files}} {{.LastModifiedAt}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db 15B 2 2024-05-01T12:00:00Z\nlogs 0B 0 2024-05-01T11:00:00Z\n"))
+	assert.Check(t, is.DeepEqual(helperMounts, []mount.Mount{
+		{Type: mount.TypeVolume, Source: "logs", Target: "/source", ReadOnly: true},
+		{Type: mount.TypeVolume, Source: "db", Target: "/source", ReadOnly: true},
+	}))
+	assert.Check(t, is.DeepEqual(removed, []string{"helper-logs", "helper-db"}))
+}
+
+func TestVolumeDiskUsageNotFound(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{}, errdefs.NotFound(errors.Errorf("get %s: no such volume", volumeID))
+		},
+		containerCreateFunc: func(*container.Config, *container.HostConfig) (container.CreateResponse, error) {
+			t.Error("unexpected helper container for a volume that doesn't exist")
+			return container.CreateResponse{}, nil
+		},
+	})
+
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"missing"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "get missing: no such volume"))
+}
+
+func TestVolumeDiskUsageSizeOnly(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		diskUsageFunc: func(options types.DiskUsageOptions) (types.DiskUsage, error) {
+			assert.Check(t, is.DeepEqual(options.Types, []types.DiskUsageObject{types.VolumeObject}))
+			return types.DiskUsage{Volumes: []*volume.Volume{
+				{Name: "db", UsageData: &volume.UsageData{Size: 2048, RefCount: 1}},
+				{Name: "remote", UsageData: &volume.UsageData{Size: -1}},
+			}}, nil
+		},
+		containerCreateFunc: func(*container.Config, *container.HostConfig) (container.CreateResponse, error) {
+			t.Error("unexpected helper container with --size-only")
+			return container.CreateResponse{}, nil
+		},
+	})
+
+	cmd := newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--size-only", "--format", "{{.Name}} {{.Size}} {{.Files}} {{.LastModified}}"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db 2.048kB N/A N/A\nremote N/A N/A N/A\n"))
+
+	cmd = newDiskUsageCommand(cli)
+	cmd.SetArgs([]string{"--size-only", "db", "missing"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.Error(cmd.Execute(), "no such volume: missing"))
+}
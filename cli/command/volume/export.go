@@ -0,0 +1,128 @@
+package volume
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	name        string
+	output      string
+	quiet       bool
+	helperImage string
+}
+
+func newExportCommand(dockerCli command.Cli) *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS] VOLUME",
+		Short: "Export the content of a volume to a tar archive",
+		Long: `Export the content of a volume to a tar archive, which is written to STDOUT
+by default. The archive is compressed with gzip or zstd if the name of the
+output file ends with ".tar.gz", ".tgz", ".tar.zst", or ".tzst".`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runExport(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "Write to a file, instead of STDOUT")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output")
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper container that reads the volume's content")
+	return cmd
+}
+
+func runExport(ctx context.Context, dockerCli command.Cli, opts exportOptions) error {
+	if opts.output == "" && dockerCli.Out().IsTerminal() {
+		return errors.New("cowardly refusing to save to a terminal. Use the -o flag or redirect")
+	}
+	if opts.output != "" {
+		if err := command.ValidateOutputPath(opts.output); err != nil {
+			return errors.Wrap(err, "failed to export volume")
+		}
+	}
+
+	// Mounting a volume that doesn't exist creates it.
+	apiClient := dockerCli.Client()
+	if _, err := apiClient.VolumeInspect(ctx, opts.name); err != nil {
+		return err
+	}
+	id, err := createHelperContainer(ctx, dockerCli, apiClient, opts.helperImage, []mount.Mount{
+		{Type: mount.TypeVolume, Source: opts.name, Target: helperSourcePath, ReadOnly: true},
+	})
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, apiClient, id)
+
+	content, _, err := apiClient.CopyFromContainer(ctx, id, helperSourcePath+"/.")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read content of volume %s", opts.name)
+	}
+	defer content.Close()
+
+	var archive io.Reader = content
+	if !opts.quiet && dockerCli.Err().IsTerminal() {
+		archive = progress.NewProgressReader(content, streamformatter.NewProgressOutput(dockerCli.Err()), 0, "", "Exporting")
+	}
+
+	if opts.output == "" {
+		_, err := io.Copy(dockerCli.Out(), archive)
+		return err
+	}
+	compressed, err := compressArchive(archive, opts.output)
+	if err != nil {
+		return err
+	}
+	defer compressed.Close()
+	return command.CopyToFile(opts.output, compressed)
+}
+
+// compressArchive returns archive, compressed with the compression of which
+// the extension matches the name of the output file.
+func compressArchive(archive io.Reader, output string) (io.ReadCloser, error) {
+	var newWriter func(io.Writer) (io.WriteCloser, error)
+	switch {
+	case strings.HasSuffix(output, ".tar.gz"), strings.HasSuffix(output, ".tgz"):
+		newWriter = func(w io.Writer) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		}
+	case strings.HasSuffix(output, ".tar.zst"), strings.HasSuffix(output, ".tzst"):
+		newWriter = func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		}
+	default:
+		return io.NopCloser(archive), nil
+	}
+
+	pr, pw := io.Pipe()
+	w, err := newWriter(pw)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_, err := io.Copy(w, archive)
+		if closeErr := w.Close(); err == nil {
+			err = closeErr
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
@@ -0,0 +1,95 @@
+package volume
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/pkg/archive"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// archiveFiles returns the names and content of the files of a tar archive.
+func archiveFiles(t *testing.T, r io.Reader) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		assert.NilError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NilError(t, err)
+		files[hdr.Name] = string(content)
+	}
+}
+
+func TestVolumeExport(t *testing.T) {
+	modTime := time.Now()
+	for _, name := range []string{"data.tar", "data.tar.gz", "data.tgz", "data.tar.zst"} {
+		t.Run(name, func(t *testing.T) {
+			var helperMounts []mount.Mount
+			cli := test.NewFakeCli(&fakeClient{
+				containerCreateFunc: func(_ *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+					helperMounts = hostConfig.Mounts
+					return container.CreateResponse{ID: "helper"}, nil
+				},
+				copyFromContainerFunc: func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+					assert.Check(t, is.Equal(srcPath, "/source/."))
+					return volumeArchive(t, modTime, map[string]string{"./config.json": "{}"}), container.PathStat{}, nil
+				},
+			})
+			output := filepath.Join(t.TempDir(), name)
+
+			cmd := newExportCommand(cli)
+			cmd.SetArgs([]string{"-o", output, "my-volume"})
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.DeepEqual(helperMounts, []mount.Mount{
+				{Type: mount.TypeVolume, Source: "my-volume", Target: "/source", ReadOnly: true},
+			}))
+
+			f, err := os.Open(output)
+			assert.NilError(t, err)
+			defer f.Close()
+			content, err := archive.DecompressStream(f)
+			assert.NilError(t, err)
+			defer content.Close()
+			assert.Check(t, is.DeepEqual(archiveFiles(t, content), map[string]string{"./": "", "./config.json": "{}"}))
+		})
+	}
+}
+
+func TestVolumeExportCompression(t *testing.T) {
+	var buf bytes.Buffer
+	compressed, err := compressArchive(bytes.NewBufferString("content"), "data.tgz")
+	assert.NilError(t, err)
+	_, err = io.Copy(&buf, compressed)
+	assert.NilError(t, err)
+
+	gz, err := gzip.NewReader(&buf)
+	assert.NilError(t, err)
+	content, err := io.ReadAll(gz)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "content"))
+}
+
+func TestVolumeExportToTerminal(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cli.Out().SetIsTerminal(true)
+	cmd := newExportCommand(cli)
+	cmd.SetArgs([]string{"my-volume"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "cowardly refusing to save to a terminal"))
+}
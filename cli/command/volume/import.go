@@ -0,0 +1,113 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type importOptions struct {
+	name        string
+	input       string
+	quiet       bool
+	helperImage string
+}
+
+func newImportCommand(dockerCli command.Cli) *cobra.Command {
+	var opts importOptions
+
+	cmd := &cobra.Command{
+		Use:   "import [OPTIONS] VOLUME [FILE|-]",
+		Short: "Import the content of a volume from a tar archive",
+		Long: `Import the content of a volume from a tar archive, which is read from STDIN if
+no file is specified, or if the file is "-". The archive can be compressed with
+gzip, bzip2, xz, or zstd. The volume is created if it doesn't exist.`,
+		Args: cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			if len(args) > 1 {
+				opts.input = args[1]
+			}
+			return runImport(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output")
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper container that writes the volume's content")
+	return cmd
+}
+
+func runImport(ctx context.Context, dockerCli command.Cli, opts importOptions) error {
+	var (
+		input io.Reader
+		size  int64
+	)
+	if opts.input == "" || opts.input == "-" {
+		if dockerCli.In().IsTerminal() {
+			return errors.New("requested import from stdin, but stdin is a terminal. Specify a file or redirect")
+		}
+		input = dockerCli.In()
+	} else {
+		f, err := os.Open(opts.input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if fi, err := f.Stat(); err == nil {
+			size = fi.Size()
+		}
+		input = f
+	}
+	if !opts.quiet && dockerCli.Err().IsTerminal() {
+		input = progress.NewProgressReader(io.NopCloser(input), streamformatter.NewProgressOutput(dockerCli.Err()), size, "", "Importing")
+	}
+	content, err := archive.DecompressStream(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to decompress the archive")
+	}
+	defer content.Close()
+
+	apiClient := dockerCli.Client()
+	if _, err := apiClient.VolumeInspect(ctx, opts.name); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		if _, err := apiClient.VolumeCreate(ctx, volume.CreateOptions{Name: opts.name}); err != nil {
+			return err
+		}
+	}
+
+	id, err := createHelperContainer(ctx, dockerCli, apiClient, opts.helperImage, []mount.Mount{
+		{Type: mount.TypeVolume, Source: opts.name, Target: helperTargetPath},
+	})
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, apiClient, id)
+
+	err = apiClient.CopyToContainer(ctx, id, helperTargetPath, content, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write content to volume %s", opts.name)
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), opts.name)
+	return nil
+}
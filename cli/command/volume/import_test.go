@@ -0,0 +1,85 @@
+package volume
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestVolumeImport(t *testing.T) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err := io.Copy(gz, volumeArchive(t, time.Now(), map[string]string{"./config.json": "{}"}))
+	assert.NilError(t, err)
+	assert.NilError(t, gz.Close())
+	dir := fs.NewDir(t, "volume-import", fs.WithFile("data.tar.gz", compressed.String()))
+	defer dir.Remove()
+
+	var (
+		created      []string
+		helperMounts []mount.Mount
+		files        map[string]string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{}, errdefs.NotFound(errors.Errorf("get %s: no such volume", volumeID))
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			created = append(created, options.Name)
+			return volume.Volume{Name: options.Name}, nil
+		},
+		containerCreateFunc: func(_ *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			helperMounts = hostConfig.Mounts
+			return container.CreateResponse{ID: "helper"}, nil
+		},
+		copyToContainerFunc: func(containerID, dstPath string, content io.Reader, options container.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal(dstPath, "/target"))
+			assert.Check(t, options.CopyUIDGID)
+			files = archiveFiles(t, content)
+			return nil
+		},
+	})
+
+	cmd := newImportCommand(cli)
+	cmd.SetArgs([]string{"my-volume", dir.Join("data.tar.gz")})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(created, []string{"my-volume"}))
+	assert.Check(t, is.DeepEqual(helperMounts, []mount.Mount{
+		{Type: mount.TypeVolume, Source: "my-volume", Target: "/target"},
+	}))
+	assert.Check(t, is.DeepEqual(files, map[string]string{"./": "", "./config.json": "{}"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "my-volume\n"))
+}
+
+func TestVolumeImportStdin(t *testing.T) {
+	var files map[string]string
+	cli := test.NewFakeCli(&fakeClient{
+		volumeCreateFunc: func(volume.CreateOptions) (volume.Volume, error) {
+			t.Error("unexpected volume create for an existing volume")
+			return volume.Volume{}, nil
+		},
+		copyToContainerFunc: func(_, _ string, content io.Reader, _ container.CopyToContainerOptions) error {
+			files = archiveFiles(t, content)
+			return nil
+		},
+	})
+	cli.SetIn(streams.NewIn(volumeArchive(t, time.Now(), map[string]string{"./data": "content"})))
+
+	cmd := newImportCommand(cli)
+	cmd.SetArgs([]string{"my-volume", "-"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(files, map[string]string{"./": "", "./data": "content"}))
+}
@@ -11,12 +11,15 @@ import (
 	"github.com/docker/cli/cli/command/completion"
 	"github.com/docker/cli/cli/command/inspect"
 	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/spf13/cobra"
 )
 
 type inspectOptions struct {
 	format string
 	names  []string
+	usage  bool
 }
 
 func newInspectCommand(dockerCli command.Cli) *cobra.Command {
@@ -33,7 +36,9 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 		ValidArgsFunction: completion.VolumeNames(dockerCli),
 	}
 
-	cmd.Flags().StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.format, "format", "f", "", flagsHelper.InspectFormatHelp)
+	flags.BoolVar(&opts.usage, "usage", false, "Include the disk usage of the volumes")
 
 	return cmd
 }
@@ -41,8 +46,25 @@ func newInspectCommand(dockerCli command.Cli) *cobra.Command {
 func runInspect(ctx context.Context, dockerCli command.Cli, opts inspectOptions) error {
 	client := dockerCli.Client()
 
+	var usage map[string]*volume.UsageData
+	if opts.usage {
+		du, err := client.DiskUsage(ctx, types.DiskUsageOptions{
+			Types: []types.DiskUsageObject{types.VolumeObject},
+		})
+		if err != nil {
+			return err
+		}
+		usage = make(map[string]*volume.UsageData, len(du.Volumes))
+		for _, v := range du.Volumes {
+			usage[v.Name] = v.UsageData
+		}
+	}
+
 	getVolFunc := func(name string) (any, []byte, error) {
 		i, err := client.VolumeInspect(ctx, name)
+		if err == nil && i.UsageData == nil {
+			i.UsageData = usage[i.Name]
+		}
 		return i, nil, err
 	}
 
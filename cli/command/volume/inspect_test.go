@@ -7,10 +7,12 @@ import (
 
 	"github.com/docker/cli/internal/test"
 	"github.com/docker/cli/internal/test/builders"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 	"gotest.tools/v3/golden"
 )
 
@@ -245,3 +247,21 @@ func TestVolumeInspectCluster(t *testing.T) {
 	assert.NilError(t, cmd.Execute())
 	golden.Assert(t, cli.OutBuffer().String(), "volume-inspect-cluster.golden")
 }
+
+func TestVolumeInspectUsage(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+		diskUsageFunc: func(types.DiskUsageOptions) (types.DiskUsage, error) {
+			return types.DiskUsage{Volumes: []*volume.Volume{
+				{Name: "db", UsageData: &volume.UsageData{Size: 2048, RefCount: 1}},
+			}}, nil
+		},
+	})
+
+	cmd := newInspectCommand(cli)
+	cmd.SetArgs([]string{"--usage", "--format", "{{.Name}} {{.UsageData.Size}} {{.UsageData.RefCount}}", "db"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db 2048 1\n"))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/docker/cli/opts"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +24,8 @@ type listOptions struct {
 	quiet   bool
 	format  string
 	cluster bool
+	columns []string
+	wrap    bool
 	filter  opts.FilterOpt
 }
 
@@ -42,7 +45,9 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 
 	flags := cmd.Flags()
 	flags.BoolVarP(&options.quiet, "quiet", "q", false, "Only display volume names")
-	flags.StringVar(&options.format, "format", "", flagsHelper.FormatHelp)
+	flags.StringVar(&options.format, "format", "", flagsHelper.DelimitedFormatHelp)
+	flags.StringSliceVar(&options.columns, "columns", nil, flagsHelper.ColumnsHelp)
+	flags.BoolVar(&options.wrap, "wrap", false, flagsHelper.WrapHelp)
 	flags.VarP(&options.filter, "filter", "f", `Provide filter values (e.g. "dangling=true")`)
 	flags.BoolVar(&options.cluster, "cluster", false, "Display only cluster volumes, and use cluster volume list formatting")
 	flags.SetAnnotation("cluster", "version", []string{"1.42"})
@@ -52,6 +57,9 @@ func newListCommand(dockerCli command.Cli) *cobra.Command {
 }
 
 func runList(ctx context.Context, dockerCli command.Cli, options listOptions) error {
+	if len(options.columns) > 0 && (options.format != "" || options.quiet) {
+		return errors.New("conflicting options: --columns cannot be used together with --format or --quiet")
+	}
 	client := dockerCli.Client()
 	volumes, err := client.VolumeList(ctx, volume.ListOptions{Filters: options.filter.Value()})
 	if err != nil {
@@ -90,8 +98,10 @@ func runList(ctx context.Context, dockerCli command.Cli, options listOptions) er
 	})
 
 	volumeCtx := formatter.Context{
-		Output: dockerCli.Out(),
-		Format: formatter.NewVolumeFormat(format, options.quiet),
+		Output:  dockerCli.Out(),
+		Format:  formatter.NewVolumeFormat(format, options.quiet),
+		Columns: options.columns,
+		Wrap:    options.wrap,
 	}
 	return formatter.VolumeWrite(volumeCtx, volumes.Volumes)
 }
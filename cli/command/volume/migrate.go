@@ -0,0 +1,146 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/internal/helpercontainer"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/streamformatter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type migrateOptions struct {
+	name        string
+	fromContext string
+	toContext   string
+	target      string
+	quiet       bool
+	helperImage string
+}
+
+func newMigrateCommand(dockerCli command.Cli) *cobra.Command {
+	var opts migrateOptions
+
+	cmd := &cobra.Command{
+		Use:   "migrate [OPTIONS] VOLUME",
+		Short: "Copy a volume to the daemon of another context",
+		Long: `Copy the content of a volume to a new volume on the daemon of another context.
+The new volume is created with the driver and the labels of the source volume,
+and the same name, unless --target is specified.
+
+The content is streamed through the CLI, from a helper container on the source
+daemon, to a helper container on the target daemon.`,
+		Args: cli.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.name = args[0]
+			return runMigrate(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.fromContext, "from-context", "", "Context of the source volume (default: the current context)")
+	flags.StringVar(&opts.toContext, "to-context", "", "Context to copy the volume to")
+	flags.StringVar(&opts.target, "target", "", "Name of the new volume (default: the name of the source volume)")
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Suppress the progress output")
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper containers that copy the volume's content")
+	_ = cmd.MarkFlagRequired("to-context")
+	return cmd
+}
+
+func runMigrate(ctx context.Context, dockerCli command.Cli, opts migrateOptions) error {
+	fromContext := dockerCli.CurrentContext()
+	if opts.fromContext != "" {
+		fromContext = opts.fromContext
+	}
+	if fromContext == opts.toContext {
+		return errors.Errorf(`the source and target contexts are the same (%s); use "docker volume clone" to copy a volume on the same daemon`, opts.toContext)
+	}
+
+	srcClient := dockerCli.Client()
+	if fromContext != dockerCli.CurrentContext() {
+		c, err := command.NewAPIClientForContext(dockerCli.ContextStore(), fromContext, dockerCli.ConfigFile())
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		srcClient = c
+	}
+	dstClient, err := command.NewAPIClientForContext(dockerCli.ContextStore(), opts.toContext, dockerCli.ConfigFile())
+	if err != nil {
+		return err
+	}
+	defer dstClient.Close()
+
+	return migrateVolume(ctx, dockerCli, srcClient, dstClient, opts)
+}
+
+// migrateVolume copies the volume of srcClient to a new volume of dstClient.
+func migrateVolume(ctx context.Context, dockerCli command.Cli, srcClient, dstClient client.APIClient, opts migrateOptions) error {
+	src, err := srcClient.VolumeInspect(ctx, opts.name)
+	if err != nil {
+		return err
+	}
+	targetName := opts.target
+	if targetName == "" {
+		targetName = src.Name
+	}
+	target, err := createTargetVolume(ctx, dstClient, src, targetName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create volume in context %s", opts.toContext)
+	}
+
+	if err := transferVolume(ctx, dockerCli, srcClient, dstClient, src.Name, target.Name, opts); err != nil {
+		_ = dstClient.VolumeRemove(context.WithoutCancel(ctx), target.Name, true)
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), target.Name)
+	return nil
+}
+
+// transferVolume streams the content of the src volume of srcClient to the
+// dst volume of dstClient, through a helper container on each daemon.
+func transferVolume(ctx context.Context, dockerCli command.Cli, srcClient, dstClient client.APIClient, src, dst string, opts migrateOptions) error {
+	srcID, err := createHelperContainer(ctx, dockerCli, srcClient, opts.helperImage, []mount.Mount{
+		{Type: mount.TypeVolume, Source: src, Target: helperSourcePath, ReadOnly: true},
+	})
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, srcClient, srcID)
+
+	dstID, err := createHelperContainer(ctx, dockerCli, dstClient, opts.helperImage, []mount.Mount{
+		{Type: mount.TypeVolume, Source: dst, Target: helperTargetPath},
+	})
+	if err != nil {
+		return err
+	}
+	defer helpercontainer.Remove(ctx, dockerCli, dstClient, dstID)
+
+	content, _, err := srcClient.CopyFromContainer(ctx, srcID, helperSourcePath+"/.")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read content of volume %s", src)
+	}
+	defer content.Close()
+
+	var archive io.Reader = content
+	if !opts.quiet && dockerCli.Err().IsTerminal() {
+		archive = progress.NewProgressReader(content, streamformatter.NewProgressOutput(dockerCli.Err()), 0, "", "Migrating")
+	}
+	err = dstClient.CopyToContainer(ctx, dstID, helperTargetPath, archive, container.CopyToContainerOptions{
+		CopyUIDGID: true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write content to volume %s", dst)
+	}
+	return nil
+}
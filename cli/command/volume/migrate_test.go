@@ -0,0 +1,111 @@
+package volume
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestVolumeMigrate(t *testing.T) {
+	var (
+		srcMounts, dstMounts []mount.Mount
+		created              volume.CreateOptions
+		copied               string
+		srcRemoved           []string
+		dstRemoved           []string
+	)
+	srcClient := &fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local", Labels: map[string]string{"env": "dev"}}, nil
+		},
+		containerCreateFunc: func(_ *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			srcMounts = hostConfig.Mounts
+			return container.CreateResponse{ID: "src-helper"}, nil
+		},
+		containerRemoveFunc: func(containerID string, _ container.RemoveOptions) error {
+			srcRemoved = append(srcRemoved, containerID)
+			return nil
+		},
+		copyFromContainerFunc: func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			assert.Check(t, is.Equal(containerID, "src-helper"))
+			assert.Check(t, is.Equal(srcPath, "/source/."))
+			return io.NopCloser(strings.NewReader("content")), container.PathStat{}, nil
+		},
+	}
+	dstClient := &fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{}, errdefs.NotFound(errors.Errorf("get %s: no such volume", volumeID))
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			created = options
+			return volume.Volume{Name: options.Name, Driver: options.Driver}, nil
+		},
+		containerCreateFunc: func(_ *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			dstMounts = hostConfig.Mounts
+			return container.CreateResponse{ID: "dst-helper"}, nil
+		},
+		containerRemoveFunc: func(containerID string, _ container.RemoveOptions) error {
+			dstRemoved = append(dstRemoved, containerID)
+			return nil
+		},
+		copyToContainerFunc: func(containerID, dstPath string, content io.Reader, options container.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal(containerID, "dst-helper"))
+			assert.Check(t, is.Equal(dstPath, "/target"))
+			assert.Check(t, options.CopyUIDGID)
+			b, err := io.ReadAll(content)
+			copied = string(b)
+			return err
+		},
+	}
+	cli := test.NewFakeCli(nil)
+
+	err := migrateVolume(context.Background(), cli, srcClient, dstClient, migrateOptions{
+		name:        "pgdata",
+		toContext:   "remote",
+		target:      "pgdata-dev",
+		helperImage: defaultHelperImage,
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(created, volume.CreateOptions{
+		Name:   "pgdata-dev",
+		Driver: "local",
+		Labels: map[string]string{"env": "dev"},
+	}))
+	assert.Check(t, is.DeepEqual(srcMounts, []mount.Mount{{Type: mount.TypeVolume, Source: "pgdata", Target: "/source", ReadOnly: true}}))
+	assert.Check(t, is.DeepEqual(dstMounts, []mount.Mount{{Type: mount.TypeVolume, Source: "pgdata-dev", Target: "/target"}}))
+	assert.Check(t, is.Equal(copied, "content"))
+	assert.Check(t, is.DeepEqual(srcRemoved, []string{"src-helper"}))
+	assert.Check(t, is.DeepEqual(dstRemoved, []string{"dst-helper"}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "pgdata-dev\n"))
+}
+
+func TestVolumeMigrateTargetExists(t *testing.T) {
+	client := &fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+	}
+	err := migrateVolume(context.Background(), test.NewFakeCli(nil), client, client, migrateOptions{
+		name:      "pgdata",
+		toContext: "remote",
+	})
+	assert.Check(t, is.Error(err, "failed to create volume in context remote: volume pgdata already exists"))
+}
+
+func TestVolumeMigrateSameContext(t *testing.T) {
+	cmd := newMigrateCommand(test.NewFakeCli(&fakeClient{}))
+	cmd.SetArgs([]string{"--from-context", "remote", "--to-context", "remote", "pgdata"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), "the source and target contexts are the same (remote)"))
+}
@@ -3,6 +3,7 @@ package volume
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -16,9 +17,12 @@ import (
 )
 
 type pruneOptions struct {
-	all    bool
-	force  bool
-	filter opts.FilterOpt
+	all         bool
+	force       bool
+	filter      opts.FilterOpt
+	largerThan  opts.MemBytes
+	smallerThan opts.MemBytes
+	unusedFor   time.Duration
 }
 
 // NewPruneCommand returns a new cobra prune command for volumes
@@ -49,6 +53,9 @@ func NewPruneCommand(dockerCli command.Cli) *cobra.Command {
 	flags.SetAnnotation("all", "version", []string{"1.42"})
 	flags.BoolVarP(&options.force, "force", "f", false, "Do not prompt for confirmation")
 	flags.Var(&options.filter, "filter", `Provide filter values (e.g. "label=<label>")`)
+	flags.Var(&options.largerThan, "larger-than", "Only remove volumes larger than the given size")
+	flags.Var(&options.smallerThan, "smaller-than", "Only remove volumes smaller than the given size")
+	flags.DurationVar(&options.unusedFor, "unused-for", 0, "Only remove volumes that were not used by a container for the given duration")
 
 	return cmd
 }
@@ -62,6 +69,9 @@ Are you sure you want to continue?`
 
 func runPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions) (spaceReclaimed uint64, output string, err error) {
 	pruneFilters := command.PruneFilters(dockerCli, options.filter.Value())
+	if options.largerThan != 0 || options.smallerThan != 0 || options.unusedFor != 0 {
+		return runFilteredPrune(ctx, dockerCli, options, pruneFilters)
+	}
 
 	warning := unusedVolumesWarning
 	if versions.GreaterThanOrEqualTo(dockerCli.CurrentVersion(), "1.42") {
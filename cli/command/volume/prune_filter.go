@@ -0,0 +1,204 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/watch"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	units "github.com/docker/go-units"
+	"github.com/fvbommel/sortorder"
+	"github.com/pkg/errors"
+)
+
+// anonymousVolumeLabel is the label that the daemon sets on anonymous volumes.
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
+// runFilteredPrune removes the unused local volumes that match the size and
+// age filters, which the prune API doesn't support. The volumes to remove are
+// selected from the disk usage of the daemon, which has the size of volumes
+// and the number of containers that use them, and removed one by one, so
+// that exactly the volumes that were confirmed are removed.
+func runFilteredPrune(ctx context.Context, dockerCli command.Cli, options pruneOptions, pruneFilters filters.Args) (spaceReclaimed uint64, output string, err error) {
+	for _, key := range pruneFilters.Keys() {
+		if key != "label" && key != "label!" && key != "all" {
+			return 0, "", errdefs.InvalidParameter(errors.Errorf("invalid filter %q: only the label and all filters can be used with --larger-than, --smaller-than, and --unused-for", key))
+		}
+	}
+	if options.all && pruneFilters.Contains("all") {
+		return 0, "", errdefs.InvalidParameter(errors.New("conflicting options: cannot specify both --all and --filter all=1"))
+	}
+	// API < v1.42 removes all volumes (anonymous and named) by default.
+	all := options.all || versions.LessThan(dockerCli.CurrentVersion(), "1.42")
+	for _, v := range pruneFilters.Get("all") {
+		if b, err := strconv.ParseBool(v); err == nil && b {
+			all = true
+		}
+	}
+
+	candidates, err := pruneCandidates(ctx, dockerCli, options, pruneFilters, all)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(candidates) == 0 {
+		return 0, "", nil
+	}
+
+	if !options.force {
+		warning := unusedVolumesWarning
+		if all {
+			warning = allVolumesWarning
+		}
+		if dockerCli.In().IsTerminal() {
+			warning = pruneListWarning(candidates)
+		}
+		r, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), warning)
+		if err != nil {
+			return 0, "", err
+		}
+		if !r {
+			return 0, "", errdefs.Cancelled(errors.New("volume prune has been cancelled"))
+		}
+	}
+
+	var (
+		deleted []string
+		errs    []string
+	)
+	for _, v := range candidates {
+		if err := dockerCli.Client().VolumeRemove(ctx, v.Name, false); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		deleted = append(deleted, v.Name)
+		if v.UsageData.Size > 0 {
+			spaceReclaimed += uint64(v.UsageData.Size)
+		}
+	}
+	if len(deleted) > 0 {
+		output = "Deleted Volumes:\n" + strings.Join(deleted, "\n") + "\n"
+	}
+	if len(errs) > 0 {
+		return spaceReclaimed, output, errors.New(strings.Join(errs, "\n"))
+	}
+	return spaceReclaimed, output, nil
+}
+
+// pruneCandidates returns the unused local volumes that match the filters,
+// sorted by name.
+func pruneCandidates(ctx context.Context, dockerCli command.Cli, options pruneOptions, pruneFilters filters.Args, all bool) ([]*volume.Volume, error) {
+	du, err := dockerCli.Client().DiskUsage(ctx, types.DiskUsageOptions{
+		Types: []types.DiskUsageObject{types.VolumeObject},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var recentlyUsed map[string]bool
+	if options.unusedFor != 0 {
+		recentlyUsed, err = recentlyUsedVolumes(ctx, dockerCli, now.Add(-options.unusedFor), now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var candidates []*volume.Volume
+	for _, v := range du.Volumes {
+		// The usage data is only available for local volumes, which are
+		// the only ones that are pruned.
+		if v.Driver != "local" || v.UsageData == nil || v.UsageData.RefCount != 0 {
+			continue
+		}
+		if _, ok := v.Labels[anonymousVolumeLabel]; !ok && !all {
+			continue
+		}
+		if !matchLabels(pruneFilters, v.Labels) {
+			continue
+		}
+		if options.largerThan != 0 && v.UsageData.Size <= options.largerThan.Value() {
+			continue
+		}
+		if options.smallerThan != 0 && (v.UsageData.Size < 0 || v.UsageData.Size >= options.smallerThan.Value()) {
+			continue
+		}
+		if options.unusedFor != 0 {
+			created, err := time.Parse(time.RFC3339, v.CreatedAt)
+			if err != nil || created.After(now.Add(-options.unusedFor)) || recentlyUsed[v.Name] {
+				continue
+			}
+		}
+		candidates = append(candidates, v)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return sortorder.NaturalLess(candidates[i].Name, candidates[j].Name)
+	})
+	return candidates, nil
+}
+
+// matchLabels returns whether labels match the label and label! filters, with
+// the same semantics as the prune API.
+func matchLabels(pruneFilters filters.Args, labels map[string]string) bool {
+	if pruneFilters.Contains("label") && !pruneFilters.MatchKVList("label", labels) {
+		return false
+	}
+	if pruneFilters.Contains("label!") && pruneFilters.MatchKVList("label!", labels) {
+		return false
+	}
+	return true
+}
+
+// recentlyUsedVolumes returns the names of the volumes that were mounted or
+// unmounted by a container between since and until, according to the events
+// of the daemon. The daemon doesn't record when volumes were last used, so
+// the events are the only way to know if a volume that isn't used anymore
+// was used recently.
+func recentlyUsedVolumes(ctx context.Context, dockerCli command.Cli, since, until time.Time) (map[string]bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	evts, errs := watch.Events(ctx, dockerCli.Client(), watch.Options{
+		Since: since.Format(time.RFC3339Nano),
+		Until: until.Format(time.RFC3339Nano),
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.VolumeEventType)),
+			filters.Arg("event", string(events.ActionMount)),
+			filters.Arg("event", string(events.ActionUnmount)),
+		),
+	})
+	used := map[string]bool{}
+	for {
+		select {
+		case e := <-evts:
+			used[e.Actor.ID] = true
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return used, nil
+			}
+			return nil, errors.Wrap(err, "failed to get the events of volumes")
+		}
+	}
+}
+
+// pruneListWarning returns the confirmation prompt that lists the volumes to
+// remove, with their size.
+func pruneListWarning(candidates []*volume.Volume) string {
+	var sb strings.Builder
+	sb.WriteString("WARNING! This will remove the following volumes:\n")
+	for _, v := range candidates {
+		_, _ = fmt.Fprintf(&sb, "  - %s (%s)\n", v.Name, units.HumanSize(float64(v.UsageData.Size)))
+	}
+	sb.WriteString("Are you sure you want to continue?")
+	return sb.String()
+}
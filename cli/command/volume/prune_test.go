@@ -7,9 +7,12 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/cli/cli/streams"
 	"github.com/docker/cli/internal/test"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/pkg/errors"
@@ -202,3 +205,104 @@ func TestVolumePrunePromptTerminate(t *testing.T) {
 	test.TerminatePrompt(ctx, t, cmd, cli)
 	golden.Assert(t, cli.OutBuffer().String(), "volume-prune-terminate.golden")
 }
+
+// pruneDiskUsageFunc returns the disk usage of unused local volumes, except
+// for "in-use", and "remote", which is not a local volume.
+func pruneDiskUsageFunc(types.DiskUsageOptions) (types.DiskUsage, error) {
+	created := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	anonymous := map[string]string{anonymousVolumeLabel: ""}
+	return types.DiskUsage{
+		Volumes: []*volume.Volume{
+			{Name: "small", Driver: "local", CreatedAt: created, Labels: anonymous, UsageData: &volume.UsageData{Size: 1000}},
+			{Name: "large", Driver: "local", CreatedAt: created, Labels: anonymous, UsageData: &volume.UsageData{Size: 5_000_000}},
+			{Name: "new", Driver: "local", CreatedAt: time.Now().Format(time.RFC3339), Labels: anonymous, UsageData: &volume.UsageData{Size: 3000}},
+			{Name: "named", Driver: "local", CreatedAt: created, UsageData: &volume.UsageData{Size: 2000}},
+			{Name: "in-use", Driver: "local", CreatedAt: created, Labels: anonymous, UsageData: &volume.UsageData{Size: 7000, RefCount: 1}},
+			{Name: "remote", Driver: "nfs", CreatedAt: created, Labels: anonymous, UsageData: &volume.UsageData{Size: -1, RefCount: -1}},
+		},
+	}, nil
+}
+
+func TestVolumePruneFiltered(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "larger-than",
+			args:     []string{"--larger-than", "2k"},
+			expected: []string{"large", "new"},
+		},
+		{
+			name:     "smaller-than",
+			args:     []string{"--smaller-than", "1m", "--all"},
+			expected: []string{"named", "new", "small"},
+		},
+		{
+			name:     "unused-for",
+			args:     []string{"--unused-for", "24h"},
+			expected: []string{"large"},
+		},
+		{
+			name:     "label",
+			args:     []string{"--larger-than", "1", "--filter", "label!=" + anonymousVolumeLabel, "--all"},
+			expected: []string{"named"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var removed []string
+			cli := test.NewFakeCli(&fakeClient{
+				diskUsageFunc: pruneDiskUsageFunc,
+				eventsFunc: func(options events.ListOptions) (<-chan events.Message, <-chan error) {
+					assert.Check(t, options.Filters.ExactMatch("type", "volume"))
+					msgs := make(chan events.Message)
+					errs := make(chan error, 1)
+					go func() {
+						msgs <- events.Message{Type: events.VolumeEventType, Action: events.ActionUnmount, Actor: events.Actor{ID: "small"}}
+						errs <- io.EOF
+					}()
+					return msgs, errs
+				},
+				volumePruneFunc: func(filters.Args) (volume.PruneReport, error) {
+					return volume.PruneReport{}, errors.New("unexpected call to the prune API")
+				},
+				volumeRemoveFunc: func(volumeID string, force bool) error {
+					assert.Check(t, !force)
+					removed = append(removed, volumeID)
+					return nil
+				},
+			})
+			cmd := NewPruneCommand(cli)
+			cmd.SetArgs(append(tc.args, "--force"))
+			assert.NilError(t, cmd.Execute())
+			assert.Check(t, is.DeepEqual(removed, tc.expected))
+		})
+	}
+}
+
+func TestVolumePruneFilteredPromptList(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		diskUsageFunc: pruneDiskUsageFunc,
+		volumeRemoveFunc: func(string, bool) error {
+			return nil
+		},
+	})
+	in := streams.NewIn(io.NopCloser(strings.NewReader("y")))
+	in.SetIsTerminal(true)
+	cli.SetIn(in)
+	cmd := NewPruneCommand(cli)
+	cmd.SetArgs([]string{"--larger-than", "2k"})
+	assert.NilError(t, cmd.Execute())
+	golden.Assert(t, cli.OutBuffer().String(), "volume-prune-filtered-prompt.golden")
+}
+
+func TestVolumePruneFilteredInvalidFilter(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{})
+	cmd := NewPruneCommand(cli)
+	cmd.SetArgs([]string{"--larger-than", "2k", "--filter", "until=24h", "--force"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Check(t, is.ErrorContains(cmd.Execute(), `invalid filter "until"`))
+}
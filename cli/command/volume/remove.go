@@ -2,7 +2,6 @@ package volume
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/docker/cli/cli"
@@ -51,7 +50,7 @@ func runRemove(ctx context.Context, dockerCli command.Cli, opts *removeOptions)
 			errs = append(errs, err.Error())
 			continue
 		}
-		fmt.Fprintf(dockerCli.Out(), "%s\n", name)
+		dockerCli.Out().Infof("%s\n", name)
 	}
 
 	if len(errs) > 0 {
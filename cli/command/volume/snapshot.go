@@ -0,0 +1,110 @@
+package volume
+
+import (
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/formatter"
+	"github.com/docker/docker/api/types/volume"
+	units "github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+// Labels set on volumes created by "docker volume snapshot create".
+const (
+	snapshotSourceLabel  = "com.docker.volume.snapshot.source"
+	snapshotCreatedLabel = "com.docker.volume.snapshot.created"
+)
+
+func newSnapshotCommand(dockerCli command.Cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage volume snapshots",
+		Args:  cli.NoArgs,
+		RunE:  command.ShowHelp(dockerCli.Err()),
+	}
+	cmd.AddCommand(
+		newSnapshotCreateCommand(dockerCli),
+		newSnapshotListCommand(dockerCli),
+		newSnapshotRestoreCommand(dockerCli),
+	)
+	return cmd
+}
+
+const (
+	defaultSnapshotTableFormat = "table {{.Name}}\t{{.Source}}\t{{.Driver}}\t{{.CreatedSince}}"
+	defaultSnapshotQuietFormat = "{{.Name}}"
+
+	snapshotNameHeader   = "SNAPSHOT NAME"
+	snapshotSourceHeader = "SOURCE"
+)
+
+// newSnapshotFormat returns a format for use with a snapshot Context
+func newSnapshotFormat(source string, quiet bool) formatter.Format {
+	if source == formatter.TableFormatKey {
+		if quiet {
+			return defaultSnapshotQuietFormat
+		}
+		return defaultSnapshotTableFormat
+	}
+	return formatter.Format(source)
+}
+
+// snapshotWrite writes formatted snapshots using the Context
+func snapshotWrite(ctx formatter.Context, snapshots []*volume.Volume) error {
+	render := func(format func(subContext formatter.SubContext) error) error {
+		for _, v := range snapshots {
+			if err := format(&snapshotContext{v: *v}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return ctx.Write(newSnapshotContext(), render)
+}
+
+type snapshotContext struct {
+	formatter.HeaderContext
+	v volume.Volume
+}
+
+func newSnapshotContext() *snapshotContext {
+	snapshotCtx := snapshotContext{}
+	snapshotCtx.Header = formatter.SubHeaderContext{
+		"Name":         snapshotNameHeader,
+		"Source":       snapshotSourceHeader,
+		"Driver":       formatter.DriverHeader,
+		"CreatedAt":    formatter.CreatedAtHeader,
+		"CreatedSince": formatter.CreatedSinceHeader,
+	}
+	return &snapshotCtx
+}
+
+func (c *snapshotContext) MarshalJSON() ([]byte, error) {
+	return formatter.MarshalJSON(c)
+}
+
+func (c *snapshotContext) Name() string {
+	return c.v.Name
+}
+
+func (c *snapshotContext) Source() string {
+	return c.v.Labels[snapshotSourceLabel]
+}
+
+func (c *snapshotContext) Driver() string {
+	return c.v.Driver
+}
+
+func (c *snapshotContext) CreatedAt() string {
+	return c.v.Labels[snapshotCreatedLabel]
+}
+
+func (c *snapshotContext) CreatedSince() string {
+	created, err := time.Parse(time.RFC3339, c.v.Labels[snapshotCreatedLabel])
+	if err != nil {
+		return ""
+	}
+	return units.HumanDuration(time.Now().UTC().Sub(created)) + " ago"
+}
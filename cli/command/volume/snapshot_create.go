@@ -0,0 +1,77 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/spf13/cobra"
+)
+
+type snapshotCreateOptions struct {
+	source      string
+	name        string
+	helperImage string
+}
+
+func newSnapshotCreateCommand(dockerCli command.Cli) *cobra.Command {
+	var opts snapshotCreateOptions
+
+	cmd := &cobra.Command{
+		Use:   "create [OPTIONS] VOLUME [SNAPSHOT]",
+		Short: "Create a snapshot of a volume",
+		Args:  cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.source = args[0]
+			if len(args) > 1 {
+				opts.name = args[1]
+			}
+			return runSnapshotCreate(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper container that copies the volume's content")
+	return cmd
+}
+
+func runSnapshotCreate(ctx context.Context, dockerCli command.Cli, opts snapshotCreateOptions) error {
+	apiClient := dockerCli.Client()
+
+	src, err := apiClient.VolumeInspect(ctx, opts.source)
+	if err != nil {
+		return err
+	}
+
+	// The Engine API does not (yet) provide a way for volume drivers to
+	// advertise native snapshot support, so snapshots are always created
+	// as a copy of the volume's content in a new volume that uses the
+	// same driver.
+	now := time.Now().UTC()
+	if opts.name == "" {
+		opts.name = src.Name + ".snapshot." + now.Format("20060102150405")
+	}
+	snapshot, err := apiClient.VolumeCreate(ctx, volume.CreateOptions{
+		Name:   opts.name,
+		Driver: src.Driver,
+		Labels: map[string]string{
+			snapshotSourceLabel:  src.Name,
+			snapshotCreatedLabel: now.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := copyVolume(ctx, dockerCli, src.Name, snapshot.Name, opts.helperImage); err != nil {
+		_ = apiClient.VolumeRemove(context.WithoutCancel(ctx), snapshot.Name, true)
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), snapshot.Name)
+	return nil
+}
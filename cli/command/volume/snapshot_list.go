@@ -0,0 +1,72 @@
+package volume
+
+import (
+	"context"
+	"sort"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/cli/cli/command/formatter"
+	flagsHelper "github.com/docker/cli/cli/flags"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/fvbommel/sortorder"
+	"github.com/spf13/cobra"
+)
+
+type snapshotListOptions struct {
+	source string
+	quiet  bool
+	format string
+}
+
+func newSnapshotListCommand(dockerCli command.Cli) *cobra.Command {
+	var opts snapshotListOptions
+
+	cmd := &cobra.Command{
+		Use:     "ls [OPTIONS] [VOLUME]",
+		Aliases: []string{"list"},
+		Short:   "List volume snapshots",
+		Args:    cli.RequiresMaxArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				opts.source = args[0]
+			}
+			return runSnapshotList(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "Only display snapshot names")
+	flags.StringVar(&opts.format, "format", "", flagsHelper.FormatHelp)
+	return cmd
+}
+
+func runSnapshotList(ctx context.Context, dockerCli command.Cli, opts snapshotListOptions) error {
+	label := snapshotSourceLabel
+	if opts.source != "" {
+		label += "=" + opts.source
+	}
+	resp, err := dockerCli.Client().VolumeList(ctx, volume.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", label)),
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(resp.Volumes, func(i, j int) bool {
+		return sortorder.NaturalLess(resp.Volumes[i].Name, resp.Volumes[j].Name)
+	})
+
+	format := opts.format
+	if len(format) == 0 {
+		format = formatter.TableFormatKey
+	}
+	snapshotCtx := formatter.Context{
+		Output: dockerCli.Out(),
+		Format: newSnapshotFormat(format, opts.quiet),
+	}
+	return snapshotWrite(snapshotCtx, resp.Volumes)
+}
@@ -0,0 +1,108 @@
+package volume
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/completion"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type snapshotRestoreOptions struct {
+	snapshot    string
+	target      string
+	force       bool
+	helperImage string
+}
+
+func newSnapshotRestoreCommand(dockerCli command.Cli) *cobra.Command {
+	var opts snapshotRestoreOptions
+
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS] SNAPSHOT [VOLUME]",
+		Short: "Restore a volume from a snapshot",
+		Long: `Restore a volume from a snapshot.
+
+The volume is replaced by a new volume with the same driver, options, and
+labels, and the content of the snapshot. If no VOLUME is given, the volume the
+snapshot was taken from is restored.`,
+		Args: cli.RequiresRangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.snapshot = args[0]
+			if len(args) > 1 {
+				opts.target = args[1]
+			}
+			return runSnapshotRestore(cmd.Context(), dockerCli, opts)
+		},
+		ValidArgsFunction: completion.VolumeNames(dockerCli),
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation")
+	flags.StringVar(&opts.helperImage, "helper-image", defaultHelperImage, "Image to use for the helper container that copies the volume's content")
+	return cmd
+}
+
+const restoreWarning = `WARNING! This will replace volume %q and all of its content with the content of snapshot %q.
+Are you sure you want to continue?`
+
+func runSnapshotRestore(ctx context.Context, dockerCli command.Cli, opts snapshotRestoreOptions) error {
+	apiClient := dockerCli.Client()
+
+	snapshot, err := apiClient.VolumeInspect(ctx, opts.snapshot)
+	if err != nil {
+		return err
+	}
+	source, ok := snapshot.Labels[snapshotSourceLabel]
+	if !ok {
+		return errors.Errorf("volume %s is not a snapshot", snapshot.Name)
+	}
+	if opts.target == "" {
+		opts.target = source
+	}
+	if opts.target == snapshot.Name {
+		return errors.New("cannot restore a snapshot onto itself")
+	}
+
+	if !opts.force {
+		r, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), fmt.Sprintf(restoreWarning, opts.target, snapshot.Name))
+		if err != nil {
+			return err
+		}
+		if !r {
+			return errdefs.Cancelled(errors.New("volume snapshot restore has been cancelled"))
+		}
+	}
+
+	createOpts := volume.CreateOptions{
+		Name:   opts.target,
+		Driver: snapshot.Driver,
+	}
+	target, err := apiClient.VolumeInspect(ctx, opts.target)
+	switch {
+	case err == nil:
+		createOpts.Driver = target.Driver
+		createOpts.DriverOpts = target.Options
+		createOpts.Labels = target.Labels
+		if err := apiClient.VolumeRemove(ctx, target.Name, false); err != nil {
+			return err
+		}
+	case errdefs.IsNotFound(err):
+	default:
+		return err
+	}
+
+	if _, err := apiClient.VolumeCreate(ctx, createOpts); err != nil {
+		return err
+	}
+	if err := copyVolume(ctx, dockerCli, snapshot.Name, opts.target, opts.helperImage); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(dockerCli.Out(), opts.target)
+	return nil
+}
@@ -0,0 +1,289 @@
+package volume
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"github.com/docker/cli/internal/test"
+	"github.com/docker/cli/internal/test/builders"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestVolumeSnapshotCreate(t *testing.T) {
+	var (
+		created volume.CreateOptions
+		mounts  []mount.Mount
+		copied  string
+		removed string
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			created = options
+			return volume.Volume{Name: options.Name, Driver: options.Driver}, nil
+		},
+		containerCreateFunc: func(config *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			assert.Check(t, is.Equal(config.Image, defaultHelperImage))
+			mounts = hostConfig.Mounts
+			return container.CreateResponse{ID: "helper"}, nil
+		},
+		copyFromContainerFunc: func(containerID, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			assert.Check(t, is.Equal(srcPath, "/source/."))
+			return io.NopCloser(strings.NewReader("content")), container.PathStat{}, nil
+		},
+		copyToContainerFunc: func(containerID, dstPath string, content io.Reader, options container.CopyToContainerOptions) error {
+			assert.Check(t, is.Equal(dstPath, "/target"))
+			assert.Check(t, options.CopyUIDGID)
+			b, err := io.ReadAll(content)
+			copied = string(b)
+			return err
+		},
+		containerRemoveFunc: func(containerID string, options container.RemoveOptions) error {
+			removed = containerID
+			return nil
+		},
+	})
+	cmd := newSnapshotCreateCommand(cli)
+	cmd.SetArgs([]string{"db", "db-backup"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(created.Name, "db-backup"))
+	assert.Check(t, is.Equal(created.Driver, "local"))
+	assert.Check(t, is.Equal(created.Labels[snapshotSourceLabel], "db"))
+	assert.Check(t, created.Labels[snapshotCreatedLabel] != "")
+	assert.Check(t, is.DeepEqual(mounts, []mount.Mount{
+		{Type: mount.TypeVolume, Source: "db", Target: "/source", ReadOnly: true},
+		{Type: mount.TypeVolume, Source: "db-backup", Target: "/target"},
+	}))
+	assert.Check(t, is.Equal(copied, "content"))
+	assert.Check(t, is.Equal(removed, "helper"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db-backup\n"))
+}
+
+func TestVolumeSnapshotCreateDefaultName(t *testing.T) {
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID, Driver: "local"}, nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			return volume.Volume{Name: options.Name}, nil
+		},
+	})
+	cmd := newSnapshotCreateCommand(cli)
+	cmd.SetArgs([]string{"db"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Regexp(`^db\.snapshot\.[0-9]{14}\n$`, cli.OutBuffer().String()))
+}
+
+func TestVolumeSnapshotCreatePullsHelperImage(t *testing.T) {
+	var pulled string
+	attempts := 0
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID}, nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			return volume.Volume{Name: options.Name}, nil
+		},
+		containerCreateFunc: func(config *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			attempts++
+			if pulled == "" {
+				return container.CreateResponse{}, errdefs.NotFound(errors.New("no such image"))
+			}
+			return container.CreateResponse{ID: "helper"}, nil
+		},
+		imageCreateFunc: func(parentReference string, options image.CreateOptions) (io.ReadCloser, error) {
+			pulled = parentReference
+			return io.NopCloser(strings.NewReader("")), nil
+		},
+	})
+	cmd := newSnapshotCreateCommand(cli)
+	cmd.SetArgs([]string{"--helper-image", "alpine", "db", "db-backup"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.Equal(pulled, "alpine"))
+	assert.Check(t, is.Equal(attempts, 2))
+	assert.Check(t, is.Contains(cli.ErrBuffer().String(), "Unable to find image 'alpine' locally"))
+}
+
+func TestVolumeSnapshotCreateCopyError(t *testing.T) {
+	var removed string
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			return volume.Volume{Name: volumeID}, nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			return volume.Volume{Name: options.Name}, nil
+		},
+		copyToContainerFunc: func(string, string, io.Reader, container.CopyToContainerOptions) error {
+			return errors.New("disk full")
+		},
+		volumeRemoveFunc: func(volumeID string, force bool) error {
+			removed = volumeID
+			return nil
+		},
+	})
+	cmd := newSnapshotCreateCommand(cli)
+	cmd.SetArgs([]string{"db", "db-backup"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	assert.Error(t, cmd.Execute(), "failed to write content to volume db-backup: disk full")
+	assert.Check(t, is.Equal(removed, "db-backup"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), ""))
+}
+
+func TestVolumeSnapshotList(t *testing.T) {
+	var labelFilter []string
+	cli := test.NewFakeCli(&fakeClient{
+		volumeListFunc: func(filter filters.Args) (volume.ListResponse, error) {
+			labelFilter = filter.Get("label")
+			return volume.ListResponse{
+				Volumes: []*volume.Volume{
+					builders.Volume(builders.VolumeName("db.snapshot.2"), builders.VolumeLabels(map[string]string{
+						snapshotSourceLabel:  "db",
+						snapshotCreatedLabel: "2024-05-02T10:00:00Z",
+					})),
+					builders.Volume(builders.VolumeName("db.snapshot.1"), builders.VolumeLabels(map[string]string{
+						snapshotSourceLabel:  "db",
+						snapshotCreatedLabel: "2024-05-01T10:00:00Z",
+					})),
+				},
+			}, nil
+		},
+	})
+	cmd := newSnapshotListCommand(cli)
+	cmd.SetArgs([]string{"--format", "table {{.Name}}\t{{.Source}}\t{{.Driver}}\t{{.CreatedAt}}", "db"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(labelFilter, []string{snapshotSourceLabel + "=db"}))
+	golden.Assert(t, cli.OutBuffer().String(), "volume-snapshot-list-with-format.golden")
+}
+
+func TestVolumeSnapshotListQuiet(t *testing.T) {
+	var labelFilter []string
+	cli := test.NewFakeCli(&fakeClient{
+		volumeListFunc: func(filter filters.Args) (volume.ListResponse, error) {
+			labelFilter = filter.Get("label")
+			return volume.ListResponse{
+				Volumes: []*volume.Volume{builders.Volume(builders.VolumeName("db.snapshot.1"))},
+			}, nil
+		},
+	})
+	cmd := newSnapshotListCommand(cli)
+	cmd.SetArgs([]string{"-q"})
+	assert.NilError(t, cmd.Execute())
+	assert.Check(t, is.DeepEqual(labelFilter, []string{snapshotSourceLabel}))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db.snapshot.1\n"))
+}
+
+func TestVolumeSnapshotRestore(t *testing.T) {
+	var (
+		removed string
+		created volume.CreateOptions
+		mounts  []mount.Mount
+	)
+	cli := test.NewFakeCli(&fakeClient{
+		volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+			if volumeID == "db.snapshot.1" {
+				return volume.Volume{
+					Name:   volumeID,
+					Driver: "local",
+					Labels: map[string]string{snapshotSourceLabel: "db"},
+				}, nil
+			}
+			return volume.Volume{
+				Name:    volumeID,
+				Driver:  "local",
+				Options: map[string]string{"type": "tmpfs"},
+				Labels:  map[string]string{"app": "web"},
+			}, nil
+		},
+		volumeRemoveFunc: func(volumeID string, force bool) error {
+			assert.Check(t, !force)
+			removed = volumeID
+			return nil
+		},
+		volumeCreateFunc: func(options volume.CreateOptions) (volume.Volume, error) {
+			created = options
+			return volume.Volume{Name: options.Name}, nil
+		},
+		containerCreateFunc: func(config *container.Config, hostConfig *container.HostConfig) (container.CreateResponse, error) {
+			mounts = hostConfig.Mounts
+			return container.CreateResponse{ID: "helper"}, nil
+		},
+	})
+	cmd := newSnapshotRestoreCommand(cli)
+	cmd.SetArgs([]string{"--force", "db.snapshot.1"})
+	assert.NilError(t, cmd.Execute())
+
+	assert.Check(t, is.Equal(removed, "db"))
+	assert.Check(t, is.DeepEqual(created, volume.CreateOptions{
+		Name:       "db",
+		Driver:     "local",
+		DriverOpts: map[string]string{"type": "tmpfs"},
+		Labels:     map[string]string{"app": "web"},
+	}))
+	assert.Check(t, is.Len(mounts, 2))
+	assert.Check(t, is.Equal(mounts[0].Source, "db.snapshot.1"))
+	assert.Check(t, is.Equal(mounts[1].Source, "db"))
+	assert.Check(t, is.Equal(cli.OutBuffer().String(), "db\n"))
+}
+
+func TestVolumeSnapshotRestoreErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		input         string
+		expectedError string
+	}{
+		{
+			name:          "not a snapshot",
+			args:          []string{"--force", "db"},
+			expectedError: "volume db is not a snapshot",
+		},
+		{
+			name:          "onto itself",
+			args:          []string{"--force", "db.snapshot.1", "db.snapshot.1"},
+			expectedError: "cannot restore a snapshot onto itself",
+		},
+		{
+			name:          "cancelled",
+			args:          []string{"db.snapshot.1"},
+			input:         "n",
+			expectedError: "volume snapshot restore has been cancelled",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cli := test.NewFakeCli(&fakeClient{
+				volumeInspectFunc: func(volumeID string) (volume.Volume, error) {
+					if strings.Contains(volumeID, ".snapshot.") {
+						return volume.Volume{Name: volumeID, Labels: map[string]string{snapshotSourceLabel: "db"}}, nil
+					}
+					return volume.Volume{Name: volumeID}, nil
+				},
+				volumeRemoveFunc: func(string, bool) error {
+					t.Error("unexpected volume removal")
+					return nil
+				},
+			})
+			cli.SetIn(streams.NewIn(io.NopCloser(strings.NewReader(tc.input))))
+			cmd := newSnapshotRestoreCommand(cli)
+			cmd.SetArgs(tc.args)
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			assert.ErrorContains(t, cmd.Execute(), tc.expectedError)
+		})
+	}
+}
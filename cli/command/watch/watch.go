@@ -0,0 +1,305 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+// Package watch provides a resilient subscription to the daemon's events
+// API, for use by commands that present a live view of objects.
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Options configures the events subscription.
+type Options struct {
+	// Since, Until, and Filters are passed to the events API for the
+	// initial subscription. When reconnecting, Since is replaced with
+	// the timestamp of the last received event.
+	Since   string
+	Until   string
+	Filters filters.Args
+
+	// MinBackoff and MaxBackoff are the minimum and maximum delay before
+	// reconnecting after the connection to the daemon was interrupted.
+	// The delay doubles on each consecutive failed attempt, and is reset
+	// once an event has been received.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnReconnect, if set, is called before each attempt to resubscribe,
+	// with the error that interrupted the previous subscription. See
+	// [PrintReconnect].
+	OnReconnect func(err error)
+}
+
+// PrintReconnect returns an OnReconnect function that prints that the
+// connection to the daemon was interrupted to w, which is usually the
+// standard error of the CLI.
+func PrintReconnect(w io.Writer) func(err error) {
+	return func(err error) {
+		_, _ = fmt.Fprintf(w, "Connection to the daemon was interrupted (%v); reconnecting...\n", err)
+	}
+}
+
+// Events subscribes to the daemon's events, and has the same semantics as
+// [client.SystemAPIClient.Events], except that interruptions of the event
+// stream are handled transparently.
+//
+// When the connection to the daemon is interrupted, Events reconnects and
+// resumes the stream from the timestamp of the last event received, dropping
+// events that were already delivered. The daemon closes the stream when it
+// shuts down, so Events also reconnects when the stream ends, unless Until is
+// set. The error channel only receives an error if the stream cannot be
+// resumed: [io.EOF] when the daemon closed the stream and Until is set
+// (because Until was reached), the context's error when
+// ctx is done, or an error that is not caused by the connection, such as an
+// invalid filter.
+//
+// If no event was received before the connection was interrupted, and no
+// Since option was given, the stream resumes from the daemon's time at
+// which the initial subscription was made.
+func Events(ctx context.Context, apiClient client.SystemAPIClient, options Options) (<-chan events.Message, <-chan error) {
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = defaultMinBackoff
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = max(defaultMaxBackoff, options.MinBackoff)
+	}
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	w := &watcher{
+		apiClient:  apiClient,
+		options:    options,
+		messages:   messages,
+		seen:       make(map[eventKey]struct{}),
+		subscribed: time.Now(),
+	}
+
+	// Subscribe before returning, so that no events are missed by callers
+	// that need to be subscribed before listing objects.
+	sub := w.subscribe(ctx, options.Since)
+	go func() {
+		errs <- w.run(ctx, sub)
+	}()
+	return messages, errs
+}
+
+// eventKey identifies an event for de-duplication. Events don't have a
+// unique ID, but events for the same action on the same object with the
+// same timestamp can safely be assumed to be identical.
+type eventKey struct {
+	timeNano int64
+	typ      events.Type
+	action   events.Action
+	actor    string
+	scope    string
+}
+
+func keyOf(e events.Message) eventKey {
+	timeNano := e.TimeNano
+	if timeNano == 0 {
+		timeNano = e.Time * int64(time.Second)
+	}
+	return eventKey{
+		timeNano: timeNano,
+		typ:      e.Type,
+		action:   e.Action,
+		actor:    e.Actor.ID,
+		scope:    e.Scope,
+	}
+}
+
+type watcher struct {
+	apiClient client.SystemAPIClient
+	options   Options
+	messages  chan<- events.Message
+
+	// since is the timestamp to resume from when reconnecting. It's
+	// empty until either an event was received, or the daemon's time of
+	// the initial subscription was determined.
+	since string
+
+	// subscribed is the (local, monotonic) time of the initial
+	// subscription, which is used to determine the daemon's time of the
+	// initial subscription when reconnecting before any event was received.
+	subscribed time.Time
+
+	// seen holds the events that were delivered with the timestamp of the
+	// last event, which are sent again by the daemon when resuming.
+	seen     map[eventKey]struct{}
+	lastNano int64
+}
+
+func (w *watcher) run(ctx context.Context, sub subscription) error {
+	var (
+		backoff  = w.options.MinBackoff
+		attempt  int
+		received bool
+	)
+	for {
+		err := w.forward(ctx, sub, &received)
+		sub.cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !retryable(err, attempt > 0 || received, w.options.Until != "") {
+			return err
+		}
+		if received {
+			backoff = w.options.MinBackoff
+			received = false
+		}
+
+		attempt++
+		logrus.WithError(err).Debugf("events stream interrupted: reconnecting in %s (attempt %d)", backoff, attempt)
+		if w.options.OnReconnect != nil {
+			w.options.OnReconnect(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, w.options.MaxBackoff)
+
+		sub = w.resubscribe(ctx)
+	}
+}
+
+// resubscribe subscribes to events since the last event that was received.
+func (w *watcher) resubscribe(ctx context.Context) subscription {
+	if w.since == "" && w.options.Since == "" {
+		// Events that happened while reconnecting must not be missed if
+		// no event was received before the connection was interrupted.
+		// The daemon's clock may differ from ours, so the timestamp of
+		// the initial subscription is derived from the daemon's time.
+		info, err := w.apiClient.Info(ctx)
+		if err != nil {
+			return failed(err)
+		}
+		daemonTime, err := time.Parse(time.RFC3339Nano, info.SystemTime)
+		if err != nil {
+			return failed(fmt.Errorf("failed to parse daemon time: %w", err))
+		}
+		w.since = formatTimestamp(daemonTime.Add(-time.Since(w.subscribed)).UnixNano())
+	}
+	since := w.since
+	if since == "" {
+		since = w.options.Since
+	}
+	return w.subscribe(ctx, since)
+}
+
+type subscription struct {
+	evts   <-chan events.Message
+	errs   <-chan error
+	cancel context.CancelFunc
+}
+
+// subscribe subscribes to events since the given timestamp.
+func (w *watcher) subscribe(ctx context.Context, since string) subscription {
+	ctx, cancel := context.WithCancel(ctx)
+	evts, errs := w.apiClient.Events(ctx, events.ListOptions{
+		Since:   since,
+		Until:   w.options.Until,
+		Filters: w.options.Filters,
+	})
+	return subscription{evts: evts, errs: errs, cancel: cancel}
+}
+
+// failed returns a subscription that ends immediately with err.
+func failed(err error) subscription {
+	errs := make(chan error, 1)
+	errs <- err
+	return subscription{errs: errs, cancel: func() {}}
+}
+
+// forward forwards events that were not delivered before until the
+// subscription ends, and returns the error that ended the subscription.
+func (w *watcher) forward(ctx context.Context, sub subscription, received *bool) error {
+	for {
+		select {
+		case e := <-sub.evts:
+			if !w.record(e) {
+				continue
+			}
+			*received = true
+			select {
+			case w.messages <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case err := <-sub.errs:
+			if err == nil {
+				// The error channel was closed without an error being
+				// sent, which means the stream ended unexpectedly.
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+}
+
+// record records the event as delivered, and returns false if it was
+// delivered before.
+func (w *watcher) record(e events.Message) bool {
+	key := keyOf(e)
+	switch {
+	case key.timeNano < w.lastNano:
+		return false
+	case key.timeNano > w.lastNano:
+		w.lastNano = key.timeNano
+		clear(w.seen)
+	default:
+		if _, ok := w.seen[key]; ok {
+			return false
+		}
+	}
+	w.seen[key] = struct{}{}
+	w.since = formatTimestamp(key.timeNano)
+	return true
+}
+
+// formatTimestamp formats a Unix timestamp in nanoseconds in the
+// "seconds.nanoseconds" format accepted by the events API.
+func formatTimestamp(timeNano int64) string {
+	return fmt.Sprintf("%d.%09d", timeNano/int64(time.Second), timeNano%int64(time.Second))
+}
+
+// retryable returns whether a new subscription should be made after the
+// previous one ended with err. Only errors caused by the connection to the
+// daemon are retried; failures to connect are only retried if the daemon
+// was reachable before, so that commands fail early if the daemon is not
+// running.
+func retryable(err error, connected, bounded bool) bool {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, io.EOF):
+		// The daemon closed the stream, either because the end of a
+		// bounded stream was reached, or because it's restarting.
+		return !bounded
+	case client.IsErrConnectionFailed(err):
+		return connected
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &netErr):
+		// The connection was interrupted.
+		return true
+	default:
+		return false
+	}
+}
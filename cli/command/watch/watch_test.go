@@ -0,0 +1,200 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// stream is a single subscription to the events API: the events to send,
+// followed by the error that ends the subscription.
+type stream struct {
+	events []events.Message
+	err    error
+}
+
+type fakeClient struct {
+	client.SystemAPIClient
+	streams    []stream
+	calls      []events.ListOptions
+	systemTime time.Time
+}
+
+func (c *fakeClient) Info(context.Context) (system.Info, error) {
+	return system.Info{SystemTime: c.systemTime.Format(time.RFC3339Nano)}, nil
+}
+
+func (c *fakeClient) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	c.calls = append(c.calls, options)
+	s := c.streams[0]
+	c.streams = c.streams[1:]
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		for _, e := range s.events {
+			select {
+			case messages <- e:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+		errs <- s.err
+	}()
+	return messages, errs
+}
+
+func event(timeNano int64, id string) events.Message {
+	return events.Message{
+		Type:     events.ContainerEventType,
+		Action:   events.ActionStart,
+		Actor:    events.Actor{ID: id},
+		TimeNano: timeNano,
+	}
+}
+
+func collect(t *testing.T, evts <-chan events.Message, errs <-chan error) ([]string, error) {
+	t.Helper()
+	var ids []string
+	for {
+		select {
+		case e := <-evts:
+			ids = append(ids, e.Actor.ID)
+		case err := <-errs:
+			return ids, err
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for events")
+		}
+	}
+}
+
+func TestEventsResume(t *testing.T) {
+	apiClient := &fakeClient{
+		streams: []stream{
+			{events: []events.Message{event(1, "one"), event(2_000000001, "two")}, err: io.ErrUnexpectedEOF},
+			{events: []events.Message{event(2_000000001, "two"), event(2_000000001, "three"), event(3, "stale"), event(4_000000000, "four")}, err: io.EOF},
+		},
+	}
+	var reconnects int
+	evts, errs := Events(context.Background(), apiClient, Options{
+		Since:      "0",
+		Until:      "5",
+		MinBackoff: time.Millisecond,
+		OnReconnect: func(err error) {
+			assert.Check(t, is.ErrorIs(err, io.ErrUnexpectedEOF))
+			reconnects++
+		},
+	})
+	ids, err := collect(t, evts, errs)
+	assert.Check(t, is.ErrorIs(err, io.EOF))
+	assert.Check(t, is.DeepEqual(ids, []string{"one", "two", "three", "four"}))
+	assert.Check(t, is.Equal(reconnects, 1))
+	assert.Assert(t, is.Len(apiClient.calls, 2))
+	assert.Check(t, is.Equal(apiClient.calls[0].Since, "0"))
+	assert.Check(t, is.Equal(apiClient.calls[1].Since, "2.000000001"))
+}
+
+func TestEventsResumeWithoutEvents(t *testing.T) {
+	// The daemon's clock is an hour behind.
+	daemonTime := time.Now().Add(-time.Hour)
+	apiClient := &fakeClient{
+		streams: []stream{
+			{err: io.ErrUnexpectedEOF},
+			{err: client.ErrorConnectionFailed("unix:///var/run/docker.sock")},
+			{events: []events.Message{event(1, "one")}, err: io.EOF},
+		},
+		systemTime: daemonTime,
+	}
+	evts, errs := Events(context.Background(), apiClient, Options{Until: "5", MinBackoff: time.Millisecond})
+	ids, err := collect(t, evts, errs)
+	assert.Check(t, is.ErrorIs(err, io.EOF))
+	assert.Check(t, is.DeepEqual(ids, []string{"one"}))
+	assert.Assert(t, is.Len(apiClient.calls, 3))
+	assert.Check(t, is.Equal(apiClient.calls[0].Since, ""))
+
+	// If no events were received, the stream resumes from the daemon's
+	// time of the initial subscription.
+	since := apiClient.calls[1].Since
+	assert.Check(t, since <= formatTimestamp(daemonTime.UnixNano()), since)
+	assert.Check(t, since > formatTimestamp(daemonTime.Add(-time.Minute).UnixNano()), since)
+	assert.Check(t, is.Equal(apiClient.calls[2].Since, since))
+}
+
+func TestEventsNotRetryable(t *testing.T) {
+	testCases := []struct {
+		name  string
+		until string
+		err   error
+	}{
+		{
+			name: "daemon not running",
+			err:  client.ErrorConnectionFailed("unix:///var/run/docker.sock"),
+		},
+		{
+			name: "invalid filter",
+			err:  errdefs.InvalidParameter(errors.New("invalid filter 'foo'")),
+		},
+		{
+			name:  "until reached",
+			until: "5",
+			err:   io.EOF,
+		},
+		{
+			name: "other error",
+			err:  errors.New("something went wrong"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiClient := &fakeClient{streams: []stream{{err: tc.err}}}
+			evts, errs := Events(context.Background(), apiClient, Options{Until: tc.until, MinBackoff: time.Millisecond})
+			_, err := collect(t, evts, errs)
+			assert.Check(t, is.ErrorIs(err, tc.err))
+			assert.Check(t, is.Len(apiClient.calls, 1))
+		})
+	}
+}
+
+func TestEventsResumeAfterEOF(t *testing.T) {
+	// Without Until, the daemon only closes the stream when it shuts down.
+	apiClient := &fakeClient{
+		streams: []stream{
+			{events: []events.Message{event(1, "one")}, err: io.EOF},
+			{events: []events.Message{event(2, "two")}, err: errdefs.InvalidParameter(errors.New("stop"))},
+		},
+	}
+	var buf bytes.Buffer
+	evts, errs := Events(context.Background(), apiClient, Options{
+		MinBackoff:  time.Millisecond,
+		OnReconnect: PrintReconnect(&buf),
+	})
+	ids, err := collect(t, evts, errs)
+	assert.Check(t, is.ErrorContains(err, "stop"))
+	assert.Check(t, is.DeepEqual(ids, []string{"one", "two"}))
+	assert.Check(t, is.Equal(buf.String(), "Connection to the daemon was interrupted (EOF); reconnecting...\n"))
+	assert.Assert(t, is.Len(apiClient.calls, 2))
+	assert.Check(t, is.Equal(apiClient.calls[1].Since, "0.000000001"))
+}
+
+func TestEventsCancel(t *testing.T) {
+	apiClient := &fakeClient{
+		streams: []stream{{err: io.ErrUnexpectedEOF}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	evts, errs := Events(ctx, apiClient, Options{MinBackoff: time.Hour})
+	cancel()
+	_, err := collect(t, evts, errs)
+	assert.Check(t, is.ErrorIs(err, context.Canceled))
+}
@@ -0,0 +1,232 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/cli/cli/compose/schema"
+	"github.com/docker/cli/cli/compose/types"
+	"github.com/pkg/errors"
+)
+
+// extendsRef is the service that a service extends, with the "extends" option.
+// The service is defined in file, or in the same file if file is empty.
+type extendsRef struct {
+	service string
+	file    string
+}
+
+// composeFile is a Compose file of which the services are resolved by an
+// extendsResolver.
+type composeFile struct {
+	filename   string
+	workingDir string
+	services   map[string]any
+	extends    map[string]extendsRef
+}
+
+// takeServiceOptions removes the "extends" and "profiles" options from the
+// services of configDict, which aren't options of the schema, and returns
+// them by service. configDict itself isn't modified.
+func takeServiceOptions(configDict map[string]any) (map[string]any, map[string]extendsRef, map[string][]string, error) {
+	services, ok := configDict["services"].(map[string]any)
+	if !ok {
+		return configDict, nil, nil, nil
+	}
+
+	extends := map[string]extendsRef{}
+	profiles := map[string][]string{}
+	newServices := make(map[string]any, len(services))
+	for name, s := range services {
+		service, ok := s.(map[string]any)
+		if !ok {
+			newServices[name] = s
+			continue
+		}
+		newService := make(map[string]any, len(service))
+		for k, v := range service {
+			newService[k] = v
+		}
+		if v, ok := newService["extends"]; ok {
+			ref, err := toExtendsRef(name, v)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			extends[name] = ref
+			delete(newService, "extends")
+		}
+		if v, ok := newService["profiles"]; ok {
+			list, ok := v.([]any)
+			if !ok {
+				return nil, nil, nil, errors.Errorf("services.%s.profiles must be a list", name)
+			}
+			profiles[name] = []string{}
+			for _, p := range list {
+				str, ok := p.(string)
+				if !ok {
+					return nil, nil, nil, errors.Errorf("services.%s.profiles contains an invalid type, it should be a string", name)
+				}
+				profiles[name] = append(profiles[name], str)
+			}
+			delete(newService, "profiles")
+		}
+		newServices[name] = newService
+	}
+
+	newConfig := make(map[string]any, len(configDict))
+	for k, v := range configDict {
+		newConfig[k] = v
+	}
+	newConfig["services"] = newServices
+	return newConfig, extends, profiles, nil
+}
+
+func toExtendsRef(name string, v any) (extendsRef, error) {
+	switch v := v.(type) {
+	case string:
+		return extendsRef{service: v}, nil
+	case map[string]any:
+		var ref extendsRef
+		for k, value := range v {
+			str, ok := value.(string)
+			if !ok {
+				return ref, errors.Errorf("services.%s.extends.%s must be a string", name, k)
+			}
+			switch k {
+			case "service":
+				ref.service = str
+			case "file":
+				ref.file = str
+			default:
+				return ref, errors.Errorf("services.%s.extends Additional property %s is not allowed", name, k)
+			}
+		}
+		if ref.service == "" {
+			return ref, errors.Errorf("services.%s.extends.service is required", name)
+		}
+		return ref, nil
+	default:
+		return extendsRef{}, errors.Errorf("services.%s.extends must be a string or a mapping", name)
+	}
+}
+
+// extendsResolver resolves the services that extend other services, in the
+// same Compose file, or in other files.
+type extendsResolver struct {
+	details types.ConfigDetails
+	options *Options
+	files   map[string]*composeFile
+}
+
+// resolve returns the service of file with the given name, merged with the
+// services it extends. The service is loaded every time, as merging changes
+// the service that is merged into.
+func (r *extendsResolver) resolve(file *composeFile, name string, chain []string) (*types.ServiceConfig, error) {
+	serviceDict, ok := file.services[name].(map[string]any)
+	if !ok {
+		return nil, errors.Errorf("service %s is not defined in %s", name, file.filename)
+	}
+	service, err := LoadService(name, serviceDict, file.workingDir, r.details.LookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	ref, ok := file.extends[name]
+	if !ok {
+		return service, nil
+	}
+
+	key := file.filename + ":" + name
+	for _, k := range chain {
+		if k == key {
+			return nil, errors.Errorf("circular reference with extends in service %s: %s", name, strings.Join(append(chain, key), " -> "))
+		}
+	}
+
+	baseFile := file
+	if ref.file != "" {
+		path := ref.file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(file.workingDir, path)
+		}
+		if baseFile, err = r.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	base, err := r.resolve(baseFile, ref.service, append(chain, key))
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot extend service %s", name)
+	}
+	base.Name = name
+	merged, err := mergeServices([]types.ServiceConfig{*base}, []types.ServiceConfig{*service})
+	if err != nil {
+		return nil, err
+	}
+	return &merged[0], nil
+}
+
+// loadFile loads a Compose file that declares a service that is extended.
+func (r *extendsResolver) loadFile(path string) (*composeFile, error) {
+	if f, ok := r.files[path]; ok {
+		return f, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	configDict, err := ParseYAML(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %s", path)
+	}
+	configDict, extends, _, err := takeServiceOptions(configDict)
+	if err != nil {
+		return nil, err
+	}
+	if !r.options.SkipInterpolation {
+		if configDict, err = interpolateConfig(configDict, *r.options.Interpolate); err != nil {
+			return nil, err
+		}
+	}
+	if !r.options.SkipValidation {
+		if err := schema.Validate(configDict, schema.Version(configDict)); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", path)
+		}
+	}
+	services, _ := configDict["services"].(map[string]any)
+	f := &composeFile{
+		filename:   path,
+		workingDir: filepath.Dir(path),
+		services:   services,
+		extends:    extends,
+	}
+	r.files[path] = f
+	return f, nil
+}
+
+// applyProfiles removes the services that are only enabled with profiles that
+// aren't active. Services without profiles are always enabled, and the "*"
+// profile enables all services.
+func applyProfiles(services []types.ServiceConfig, profiles map[string][]string, active []string) []types.ServiceConfig {
+	isActive := make(map[string]bool, len(active))
+	for _, p := range active {
+		isActive[p] = true
+	}
+	enabled := make([]types.ServiceConfig, 0, len(services))
+	for _, s := range services {
+		serviceProfiles, ok := profiles[s.Name]
+		if !ok || len(serviceProfiles) == 0 || isActive["*"] {
+			enabled = append(enabled, s)
+			continue
+		}
+		for _, p := range serviceProfiles {
+			if isActive[p] {
+				enabled = append(enabled, s)
+				break
+			}
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,175 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/docker/cli/cli/compose/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLoadExtends(t *testing.T) {
+	config, err := loadYAML(`
+version: "3.8"
+services:
+  base:
+    image: busybox
+    environment:
+      FOO: foo
+      BAR: bar
+    dns:
+      - 1.1.1.1
+  web:
+    extends: base
+    environment:
+      BAR: baz
+    dns:
+      - 8.8.8.8
+  worker:
+    extends:
+      service: web
+    image: alpine
+`)
+	assert.NilError(t, err)
+
+	foo, bar, baz := "foo", "bar", "baz"
+	services := mapByName(config.Services)
+	assert.Check(t, is.DeepEqual(services["base"].Environment, types.MappingWithEquals{"FOO": &foo, "BAR": &bar}))
+	assert.Check(t, is.Equal(services["web"].Name, "web"))
+	assert.Check(t, is.Equal(services["web"].Image, "busybox"))
+	assert.Check(t, is.DeepEqual(services["web"].Environment, types.MappingWithEquals{"FOO": &foo, "BAR": &baz}))
+	assert.Check(t, is.DeepEqual([]string(services["web"].DNS), []string{"1.1.1.1", "8.8.8.8"}))
+	assert.Check(t, is.Equal(services["worker"].Image, "alpine"))
+	assert.Check(t, is.DeepEqual(services["worker"].Environment, types.MappingWithEquals{"FOO": &foo, "BAR": &baz}))
+}
+
+func TestLoadExtendsFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "common.yml"), []byte(`
+version: "3.8"
+services:
+  app:
+    image: busybox
+    env_file: app.env
+`), 0o644))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "app.env"), []byte("FOO=foo\n"), 0o644))
+
+	dict, err := ParseYAML([]byte(`
+version: "3.8"
+services:
+  web:
+    extends:
+      file: common.yml
+      service: app
+    command: httpd
+`))
+	assert.NilError(t, err)
+	config, err := Load(types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: "docker-compose.yml", Config: dict}},
+	})
+	assert.NilError(t, err)
+
+	foo := "foo"
+	assert.Assert(t, is.Len(config.Services, 1))
+	assert.Check(t, is.Equal(config.Services[0].Name, "web"))
+	assert.Check(t, is.Equal(config.Services[0].Image, "busybox"))
+	assert.Check(t, is.DeepEqual([]string(config.Services[0].Command), []string{"httpd"}))
+	assert.Check(t, is.DeepEqual(config.Services[0].Environment, types.MappingWithEquals{"FOO": &foo}))
+}
+
+func TestLoadExtendsErrors(t *testing.T) {
+	tests := []struct {
+		doc      string
+		yaml     string
+		expected string
+	}{
+		{
+			doc: "undefined service",
+			yaml: `
+version: "3.8"
+services:
+  web:
+    extends: base
+`,
+			expected: "cannot extend service web: service base is not defined in filename.yml",
+		},
+		{
+			doc: "circular reference",
+			yaml: `
+version: "3.8"
+services:
+  web:
+    image: busybox
+    extends: worker
+  worker:
+    image: busybox
+    extends: web
+`,
+			expected: "circular reference with extends in service",
+		},
+		{
+			doc: "invalid extends",
+			yaml: `
+version: "3.8"
+services:
+  web:
+    image: busybox
+    extends:
+      name: base
+`,
+			expected: "services.web.extends Additional property name is not allowed",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			_, err := loadYAML(tc.yaml)
+			assert.Check(t, is.ErrorContains(err, tc.expected))
+		})
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	const yaml = `
+version: "3.8"
+services:
+  web:
+    image: busybox
+  debug:
+    image: busybox
+    profiles: [debug]
+  metrics:
+    image: busybox
+    profiles: [monitoring, debug]
+`
+	tests := []struct {
+		profiles []string
+		expected []string
+	}{
+		{expected: []string{"web"}},
+		{profiles: []string{"monitoring"}, expected: []string{"metrics", "web"}},
+		{profiles: []string{"debug"}, expected: []string{"debug", "metrics", "web"}},
+		{profiles: []string{"*"}, expected: []string{"debug", "metrics", "web"}},
+	}
+	for _, tc := range tests {
+		dict, err := ParseYAML([]byte(yaml))
+		assert.NilError(t, err)
+		config, err := Load(buildConfigDetails(dict, nil), func(options *Options) {
+			options.Profiles = tc.profiles
+		})
+		assert.NilError(t, err)
+
+		var names []string
+		for _, s := range config.Services {
+			names = append(names, s.Name)
+		}
+		sort.Strings(names)
+		assert.Check(t, is.DeepEqual(names, tc.expected), "profiles: %v", tc.profiles)
+	}
+}
@@ -36,6 +36,9 @@ type Options struct {
 	SkipInterpolation bool
 	// Interpolation options
 	Interpolate *interp.Options
+	// Profiles to enable; services with profiles are only loaded if one of
+	// their profiles is enabled, or if the "*" profile is enabled
+	Profiles []string
 	// Discard 'env_file' entries after resolving to 'environment' section
 	discardEnvFiles bool
 }
@@ -83,10 +86,17 @@ func Load(configDetails types.ConfigDetails, opt ...func(*Options)) (*types.Conf
 	}
 
 	configs := []*types.Config{}
-	var err error
+	profiles := map[string][]string{}
+	resolver := &extendsResolver{details: configDetails, options: options, files: map[string]*composeFile{}}
 
 	for _, file := range configDetails.ConfigFiles {
-		configDict := file.Config
+		configDict, extends, fileProfiles, err := takeServiceOptions(file.Config)
+		if err != nil {
+			return nil, err
+		}
+		for name, p := range fileProfiles {
+			profiles[name] = p
+		}
 		version := schema.Version(configDict)
 		if configDetails.Version == "" {
 			configDetails.Version = version
@@ -117,6 +127,25 @@ func Load(configDetails types.ConfigDetails, opt ...func(*Options)) (*types.Conf
 			return nil, err
 		}
 		cfg.Filename = file.Filename
+		if len(extends) > 0 {
+			services, _ := configDict["services"].(map[string]any)
+			f := &composeFile{
+				filename:   file.Filename,
+				workingDir: configDetails.WorkingDir,
+				services:   services,
+				extends:    extends,
+			}
+			for i, s := range cfg.Services {
+				if _, ok := extends[s.Name]; !ok {
+					continue
+				}
+				service, err := resolver.resolve(f, s.Name, nil)
+				if err != nil {
+					return nil, err
+				}
+				cfg.Services[i] = *service
+			}
+		}
 		if options.discardEnvFiles {
 			for i := range cfg.Services {
 				cfg.Services[i].EnvFile = nil
@@ -126,7 +155,12 @@ func Load(configDetails types.ConfigDetails, opt ...func(*Options)) (*types.Conf
 		configs = append(configs, cfg)
 	}
 
-	return merge(configs)
+	cfg, err := merge(configs)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Services = applyProfiles(cfg.Services, profiles, options.Profiles)
+	return cfg, nil
 }
 
 func validateForbidden(configDict map[string]any) error {
@@ -874,8 +874,9 @@ services:
       - /data
     volume_driver: some-driver
   bar:
-    extends:
-      service: foo
+    image: busybox
+    volumes_from:
+      - foo
 `)
 
 	assert.ErrorType(t, err, &ForbiddenPropertiesError{})
@@ -883,7 +884,7 @@ services:
 	props := err.(*ForbiddenPropertiesError).Properties
 	assert.Check(t, is.Len(props, 2))
 	assert.Check(t, is.Contains(props, "volume_driver"))
-	assert.Check(t, is.Contains(props, "extends"))
+	assert.Check(t, is.Contains(props, "volumes_from"))
 }
 
 func TestInvalidResource(t *testing.T) {
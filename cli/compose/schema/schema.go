@@ -144,6 +144,12 @@ func (err validationError) Error() string {
 	return fmt.Sprintf("%s %s", err.parent.Field(), description)
 }
 
+// Field returns the path of the invalid field, as dot-separated keys and list
+// indexes, or "(root)" for the top-level of the Compose file.
+func (err validationError) Field() string {
+	return err.parent.Field()
+}
+
 func getMostSpecificError(errs []gojsonschema.ResultError) validationError {
 	mostSpecificError := 0
 	for i, err := range errs {
@@ -41,7 +41,21 @@ type ConfigFile struct {
 	CLIPluginsExtraDirs  []string                     `json:"cliPluginsExtraDirs,omitempty"`
 	Plugins              map[string]map[string]string `json:"plugins,omitempty"`
 	Aliases              map[string]string            `json:"aliases,omitempty"`
+	Formats              map[string]string            `json:"formats,omitempty"`
 	Features             map[string]string            `json:"features,omitempty"`
+	AddHostGateway       bool                         `json:"addHostGateway,omitempty"`
+	ResourceChecks       string                       `json:"resourceChecks,omitempty"`
+	MinFreeDiskSpace     string                       `json:"minFreeDiskSpace,omitempty"`
+	ColorTheme           map[string]string            `json:"colorTheme,omitempty"`
+	Registries           map[string]RegistryConfig    `json:"registries,omitempty"`
+	PinnedImages         []string                     `json:"pinnedImages,omitempty"`
+	ContextRules         []ContextRule                `json:"contextRules,omitempty"`
+	LoadDotenv           bool                         `json:"loadDotenv,omitempty"`
+	NotifyEvents         bool                         `json:"notifyEvents,omitempty"`
+	DebugImage           string                       `json:"debugImage,omitempty"`
+	RunSuggestions       bool                         `json:"runSuggestions,omitempty"`
+	Signing              *SigningConfig               `json:"signing,omitempty"`
+	Scanner              string                       `json:"scanner,omitempty"`
 }
 
 // ProxyConfig contains proxy configuration settings
@@ -53,6 +67,36 @@ type ProxyConfig struct {
 	AllProxy   string `json:"allProxy,omitempty"`
 }
 
+// RegistryConfig contains settings for a registry
+type RegistryConfig struct {
+	Mirrors  []string `json:"mirrors,omitempty"`
+	Insecure bool     `json:"insecure,omitempty"`
+	CAFile   string   `json:"caFile,omitempty"`
+}
+
+// SigningConfig contains the settings of the Notation signatures of images,
+// which "docker push --sign" signs images with, and "docker pull
+// --verify-signature" verifies. Relative paths are relative to the
+// configuration directory.
+type SigningConfig struct {
+	// Key is the path of the PEM-encoded private key to sign with.
+	Key string `json:"key,omitempty"`
+	// Certificate is the path of the PEM-encoded certificate chain of the
+	// key, starting with the certificate of the key.
+	Certificate string `json:"certificate,omitempty"`
+	// TrustPolicy is the path of the Notation trust policy that signatures
+	// are verified against.
+	TrustPolicy string `json:"trustPolicy,omitempty"`
+}
+
+// ContextRule selects the context to use in a directory, or in a Git
+// repository that has a remote matching a pattern
+type ContextRule struct {
+	Directory string `json:"directory,omitempty"`
+	GitRepo   string `json:"gitRepo,omitempty"`
+	Context   string `json:"context"`
+}
+
 // New initializes an empty configuration file for the given filename 'fn'
 func New(fn string) *ConfigFile {
 	return &ConfigFile{
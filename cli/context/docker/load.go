@@ -6,6 +6,7 @@ import (
 	"encoding/pem"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/docker/cli/cli/connhelper"
@@ -94,10 +95,27 @@ func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
 			if err != nil {
 				return nil, err
 			}
-			result = append(result,
-				withHTTPClient(tlsConfig),
-				client.WithHost(ep.Host),
-			)
+			proxyURL, err := ParseProxy(ep.Proxy)
+			if err != nil {
+				return nil, err
+			}
+			var transport *http.Transport
+			if tlsConfig != nil || proxyURL != nil {
+				transport = newTransport(tlsConfig)
+				result = append(result, client.WithHTTPClient(&http.Client{
+					Transport:     transport,
+					CheckRedirect: client.CheckRedirect,
+				}))
+			}
+			result = append(result, client.WithHost(ep.Host))
+			if proxyURL != nil {
+				// WithHost configures the transport to use the proxy of
+				// the environment, so the proxy is set after it.
+				result = append(result, func(*client.Client) error {
+					transport.Proxy = http.ProxyURL(proxyURL)
+					return nil
+				})
+			}
 		} else {
 			result = append(result,
 				client.WithHTTPClient(&http.Client{
@@ -116,22 +134,34 @@ func (ep *Endpoint) ClientOpts() ([]client.Opt, error) {
 	return result, nil
 }
 
-func withHTTPClient(tlsConfig *tls.Config) func(*client.Client) error {
-	return func(c *client.Client) error {
-		if tlsConfig == nil {
-			// Use the default HTTPClient
-			return nil
-		}
-		return client.WithHTTPClient(&http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: tlsConfig,
-				DialContext: (&net.Dialer{
-					KeepAlive: 30 * time.Second,
-					Timeout:   30 * time.Second,
-				}).DialContext,
-			},
-			CheckRedirect: client.CheckRedirect,
-		})(c)
+// ParseProxy parses the URL of the proxy of an endpoint, which must be an
+// HTTP, HTTPS, or SOCKS5 proxy. It returns nil if proxy is empty.
+func ParseProxy(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy")
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("invalid proxy %q: the scheme must be http, https, or socks5", proxy)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("invalid proxy %q: no host", proxy)
+	}
+	return u, nil
+}
+
+func newTransport(tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		TLSClientConfig: tlsConfig,
+		DialContext: (&net.Dialer{
+			KeepAlive: 30 * time.Second,
+			Timeout:   30 * time.Second,
+		}).DialContext,
 	}
 }
 
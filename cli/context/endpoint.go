@@ -4,4 +4,7 @@ package context
 type EndpointMetaBase struct {
 	Host          string `json:",omitempty"`
 	SkipTLSVerify bool
+	// Proxy is the URL of the proxy to connect to the endpoint through,
+	// instead of the proxy of the environment.
+	Proxy string `json:",omitempty"`
 }
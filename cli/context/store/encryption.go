@@ -0,0 +1,119 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// encryptionFile is the file of an exported context with the parameters
+	// of the encryption of its TLS files, if they're encrypted.
+	encryptionFile = "encryption.json"
+
+	encryptionCipher     = "AES-256-GCM"
+	encryptionKDF        = "PBKDF2-SHA256"
+	encryptionIterations = 600000
+	encryptionSaltSize   = 16
+	encryptionKeySize    = 32
+
+	// maxEncryptionIterations caps the iterations of the key derivation of
+	// an imported context, so that a crafted context cannot hang the CLI.
+	maxEncryptionIterations = 10 * encryptionIterations
+)
+
+type encryptionInfo struct {
+	Cipher     string
+	KDF        string
+	Iterations int
+	Salt       []byte
+}
+
+func newEncryptionInfo() (encryptionInfo, error) {
+	salt := make([]byte, encryptionSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return encryptionInfo{}, err
+	}
+	return encryptionInfo{
+		Cipher:     encryptionCipher,
+		KDF:        encryptionKDF,
+		Iterations: encryptionIterations,
+		Salt:       salt,
+	}, nil
+}
+
+func parseEncryptionInfo(data []byte) (encryptionInfo, error) {
+	var info encryptionInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, errors.Wrap(err, "invalid encryption parameters")
+	}
+	if info.Cipher != encryptionCipher || info.KDF != encryptionKDF {
+		return info, errors.Errorf("unsupported encryption: cipher %q, key derivation %q", info.Cipher, info.KDF)
+	}
+	if info.Iterations <= 0 || len(info.Salt) == 0 {
+		return info, errors.New("invalid encryption parameters")
+	}
+	if info.Iterations > maxEncryptionIterations {
+		return info, errors.Errorf("invalid encryption parameters: %d iterations exceed the maximum of %d", info.Iterations, maxEncryptionIterations)
+	}
+	return info, nil
+}
+
+func (info encryptionInfo) aead(passphrase []byte) (cipher.AEAD, error) {
+	key := pbkdf2.Key(passphrase, info.Salt, info.Iterations, encryptionKeySize, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt encrypts data, and returns it prefixed with the nonce.
+func encrypt(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+func decrypt(aead cipher.AEAD, data []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("invalid encrypted data")
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// decryptTLSData decrypts the files of tlsData in place, with the passphrase
+// that is returned by passphrase.
+func decryptTLSData(tlsData *ContextTLSData, info encryptionInfo, passphrase func() ([]byte, error)) error {
+	if passphrase == nil {
+		return errdefs.InvalidParameter(errors.New("the TLS material of the context is encrypted: a passphrase is required"))
+	}
+	p, err := passphrase()
+	if err != nil {
+		return err
+	}
+	aead, err := info.aead(p)
+	if err != nil {
+		return err
+	}
+	for _, ep := range tlsData.Endpoints {
+		for fileName, data := range ep.Files {
+			plain, err := decrypt(aead, data)
+			if err != nil {
+				return errdefs.InvalidParameter(errors.New("failed to decrypt the TLS material of the context: incorrect passphrase or corrupted data"))
+			}
+			ep.Files[fileName] = plain
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"archive/zip"
 	"bufio"
 	"bytes"
+	"crypto/cipher"
 	_ "crypto/sha256" // ensure ids can be computed
 	"encoding/json"
 	"io"
@@ -228,10 +229,22 @@ func ValidateContextName(name string) error {
 	return nil
 }
 
+// ExportOptions are the options of ExportWithOptions.
+type ExportOptions struct {
+	// Passphrase is the passphrase to encrypt the TLS material with. The
+	// TLS material isn't encrypted if it's empty.
+	Passphrase []byte
+}
+
 // Export exports an existing namespace into an opaque data stream
 // This stream is actually a tarball containing context metadata and TLS materials, but it does
 // not map 1:1 the layout of the context store (don't try to restore it manually without calling store.Import)
 func Export(name string, s Reader) io.ReadCloser {
+	return ExportWithOptions(name, s, ExportOptions{})
+}
+
+// ExportWithOptions is like Export, with options to encrypt the TLS material.
+func ExportWithOptions(name string, s Reader, opts ExportOptions) io.ReadCloser {
 	reader, writer := io.Pipe()
 	go func() {
 		tw := tar.NewWriter(writer)
@@ -259,6 +272,35 @@ func Export(name string, s Reader) io.ReadCloser {
 			writer.CloseWithError(err)
 			return
 		}
+		var aead cipher.AEAD
+		if len(opts.Passphrase) > 0 {
+			info, err := newEncryptionInfo()
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			if aead, err = info.aead(opts.Passphrase); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			infoBytes, err := json.Marshal(&info)
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			if err = tw.WriteHeader(&tar.Header{
+				Name: encryptionFile,
+				Mode: 0o644,
+				Size: int64(len(infoBytes)),
+			}); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			if _, err = tw.Write(infoBytes); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
 		tlsFiles, err := s.ListTLSFiles(name)
 		if err != nil {
 			writer.CloseWithError(err)
@@ -289,6 +331,12 @@ func Export(name string, s Reader) io.ReadCloser {
 					writer.CloseWithError(err)
 					return
 				}
+				if aead != nil {
+					if data, err = encrypt(aead, data); err != nil {
+						writer.CloseWithError(err)
+						return
+					}
+				}
 				if err = tw.WriteHeader(&tar.Header{
 					Name: path.Join("tls", endpointName, fileName),
 					Mode: 0o600,
@@ -321,8 +369,20 @@ func getImportContentType(r *bufio.Reader) (string, error) {
 	return http.DetectContentType(head), nil
 }
 
+// ImportOptions are the options of ImportWithOptions.
+type ImportOptions struct {
+	// Passphrase returns the passphrase to decrypt the TLS material with.
+	// It's only called if the TLS material is encrypted.
+	Passphrase func() ([]byte, error)
+}
+
 // Import imports an exported context into a store
 func Import(name string, s Writer, reader io.Reader) error {
+	return ImportWithOptions(name, s, reader, ImportOptions{})
+}
+
+// ImportWithOptions is like Import, with options to decrypt the TLS material.
+func ImportWithOptions(name string, s Writer, reader io.Reader, opts ImportOptions) error {
 	// Buffered reader will not advance the buffer, needed to determine content type
 	r := bufio.NewReader(reader)
 
@@ -332,15 +392,15 @@ func Import(name string, s Writer, reader io.Reader) error {
 	}
 	switch importContentType {
 	case zipType:
-		return importZip(name, s, r)
+		return importZip(name, s, r, opts)
 	default:
 		// Assume it's a TAR (TAR does not have a "magic number")
-		return importTar(name, s, r)
+		return importTar(name, s, r, opts)
 	}
 }
 
 func isValidFilePath(p string) error {
-	if p != metaFile && !strings.HasPrefix(p, "tls/") {
+	if p != metaFile && p != encryptionFile && !strings.HasPrefix(p, "tls/") {
 		return errors.New("unexpected context file")
 	}
 	if path.Clean(p) != p {
@@ -352,12 +412,9 @@ func isValidFilePath(p string) error {
 	return nil
 }
 
-func importTar(name string, s Writer, reader io.Reader) error {
+func importTar(name string, s Writer, reader io.Reader, opts ImportOptions) error {
 	tr := tar.NewReader(&LimitedReader{R: reader, N: maxAllowedFileSizeToImport})
-	tlsData := ContextTLSData{
-		Endpoints: map[string]EndpointTLSData{},
-	}
-	var importedMetaFile bool
+	var im importer
 	for {
 		hdr, err := tr.Next()
 		if err == io.EOF {
@@ -373,36 +430,18 @@ func importTar(name string, s Writer, reader io.Reader) error {
 		if err := isValidFilePath(hdr.Name); err != nil {
 			return errors.Wrap(err, hdr.Name)
 		}
-		if hdr.Name == metaFile {
-			data, err := io.ReadAll(tr)
-			if err != nil {
-				return err
-			}
-			meta, err := parseMetadata(data, name)
-			if err != nil {
-				return err
-			}
-			if err := s.CreateOrUpdate(meta); err != nil {
-				return err
-			}
-			importedMetaFile = true
-		} else if strings.HasPrefix(hdr.Name, "tls/") {
-			data, err := io.ReadAll(tr)
-			if err != nil {
-				return err
-			}
-			if err := importEndpointTLS(&tlsData, hdr.Name, data); err != nil {
-				return err
-			}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := im.add(name, hdr.Name, data); err != nil {
+			return err
 		}
 	}
-	if !importedMetaFile {
-		return errdefs.InvalidParameter(errors.New("invalid context: no metadata found"))
-	}
-	return s.ResetTLSMaterial(name, &tlsData)
+	return im.finish(name, s, opts)
 }
 
-func importZip(name string, s Writer, reader io.Reader) error {
+func importZip(name string, s Writer, reader io.Reader, opts ImportOptions) error {
 	body, err := io.ReadAll(&LimitedReader{R: reader, N: maxAllowedFileSizeToImport})
 	if err != nil {
 		return err
@@ -411,11 +450,8 @@ func importZip(name string, s Writer, reader io.Reader) error {
 	if err != nil {
 		return err
 	}
-	tlsData := ContextTLSData{
-		Endpoints: map[string]EndpointTLSData{},
-	}
 
-	var importedMetaFile bool
+	var im importer
 	for _, zf := range zr.File {
 		fi := zf.FileInfo()
 		if !fi.Mode().IsRegular() {
@@ -425,45 +461,69 @@ func importZip(name string, s Writer, reader io.Reader) error {
 		if err := isValidFilePath(zf.Name); err != nil {
 			return errors.Wrap(err, zf.Name)
 		}
-		if zf.Name == metaFile {
-			f, err := zf.Open()
-			if err != nil {
-				return err
-			}
+		f, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(&LimitedReader{R: f, N: maxAllowedFileSizeToImport})
+		_ = f.Close()
+		if err != nil {
+			return err
+		}
+		if err := im.add(name, zf.Name, data); err != nil {
+			return err
+		}
+	}
+	return im.finish(name, s, opts)
+}
 
-			data, err := io.ReadAll(&LimitedReader{R: f, N: maxAllowedFileSizeToImport})
-			defer f.Close()
-			if err != nil {
-				return err
-			}
-			meta, err := parseMetadata(data, name)
-			if err != nil {
-				return err
-			}
-			if err := s.CreateOrUpdate(meta); err != nil {
-				return err
-			}
-			importedMetaFile = true
-		} else if strings.HasPrefix(zf.Name, "tls/") {
-			f, err := zf.Open()
-			if err != nil {
-				return err
-			}
-			data, err := io.ReadAll(f)
-			defer f.Close()
-			if err != nil {
-				return err
-			}
-			err = importEndpointTLS(&tlsData, zf.Name, data)
-			if err != nil {
-				return err
-			}
+// importer collects the files of an exported context, which is only written
+// to the store once all files are read, and the TLS material is decrypted.
+type importer struct {
+	meta       *Metadata
+	encryption *encryptionInfo
+	tlsData    ContextTLSData
+}
+
+func (im *importer) add(name, filePath string, data []byte) error {
+	switch {
+	case filePath == metaFile:
+		meta, err := parseMetadata(data, name)
+		if err != nil {
+			return err
+		}
+		im.meta = &meta
+	case filePath == encryptionFile:
+		info, err := parseEncryptionInfo(data)
+		if err != nil {
+			return err
+		}
+		im.encryption = &info
+	case strings.HasPrefix(filePath, "tls/"):
+		if im.tlsData.Endpoints == nil {
+			im.tlsData.Endpoints = map[string]EndpointTLSData{}
 		}
+		return importEndpointTLS(&im.tlsData, filePath, data)
 	}
-	if !importedMetaFile {
+	return nil
+}
+
+func (im *importer) finish(name string, s Writer, opts ImportOptions) error {
+	if im.meta == nil {
 		return errdefs.InvalidParameter(errors.New("invalid context: no metadata found"))
 	}
-	return s.ResetTLSMaterial(name, &tlsData)
+	if im.encryption != nil && len(im.tlsData.Endpoints) > 0 {
+		if err := decryptTLSData(&im.tlsData, *im.encryption, opts.Passphrase); err != nil {
+			return err
+		}
+	}
+	if err := s.CreateOrUpdate(*im.meta); err != nil {
+		return err
+	}
+	if im.tlsData.Endpoints == nil {
+		im.tlsData.Endpoints = map[string]EndpointTLSData{}
+	}
+	return s.ResetTLSMaterial(name, &im.tlsData)
 }
 
 func parseMetadata(data []byte, name string) (Metadata, error) {
@@ -89,6 +89,65 @@ func TestExportImport(t *testing.T) {
 	assert.DeepEqual(t, file2, destData2)
 }
 
+func TestExportImportEncrypted(t *testing.T) {
+	s := New(t.TempDir(), testCfg)
+	err := s.CreateOrUpdate(
+		Metadata{
+			Endpoints: map[string]any{
+				"ep1": endpoint{Foo: "bar"},
+			},
+			Metadata: context{Bar: "baz"},
+			Name:     "source",
+		})
+	assert.NilError(t, err)
+	file1 := make([]byte, 1500)
+	rand.Read(file1)
+	err = s.ResetEndpointTLSMaterial("source", "ep1", &EndpointTLSData{
+		Files: map[string][]byte{"file1": file1},
+	})
+	assert.NilError(t, err)
+
+	var exported bytes.Buffer
+	r := ExportWithOptions("source", s, ExportOptions{Passphrase: []byte("secret")})
+	_, err = io.Copy(&exported, r)
+	assert.NilError(t, err)
+	assert.Check(t, !bytes.Contains(exported.Bytes(), file1))
+
+	err = Import("dest", s, bytes.NewReader(exported.Bytes()))
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	assert.Check(t, is.ErrorContains(err, "a passphrase is required"))
+
+	err = ImportWithOptions("dest", s, bytes.NewReader(exported.Bytes()), ImportOptions{
+		Passphrase: func() ([]byte, error) { return []byte("wrong"), nil },
+	})
+	assert.Check(t, is.ErrorContains(err, "incorrect passphrase"))
+	_, err = s.GetMetadata("dest")
+	assert.Check(t, errdefs.IsNotFound(err), "the context must not be created if the TLS material can't be decrypted")
+
+	err = ImportWithOptions("dest", s, bytes.NewReader(exported.Bytes()), ImportOptions{
+		Passphrase: func() ([]byte, error) { return []byte("secret"), nil },
+	})
+	assert.NilError(t, err)
+	destMeta, err := s.GetMetadata("dest")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, destMeta.Endpoints, map[string]any{"ep1": endpoint{Foo: "bar"}})
+	destData1, err := s.GetTLSData("dest", "ep1", "file1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, file1, destData1)
+}
+
+func TestParseEncryptionInfo(t *testing.T) {
+	info, err := parseEncryptionInfo([]byte(`{"Cipher":"AES-256-GCM","KDF":"PBKDF2-SHA256","Iterations":600000,"Salt":"c2FsdA=="}`))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(info.Iterations, 600000))
+
+	_, err = parseEncryptionInfo([]byte(`{"Cipher":"AES-256-GCM","KDF":"PBKDF2-SHA256","Iterations":2147483647,"Salt":"c2FsdA=="}`))
+	assert.Check(t, is.Error(err, "invalid encryption parameters: 2147483647 iterations exceed the maximum of 6000000"))
+
+	_, err = parseEncryptionInfo([]byte(`{"Cipher":"AES-256-GCM","KDF":"PBKDF2-SHA256","Iterations":0,"Salt":"c2FsdA=="}`))
+	assert.Check(t, is.Error(err, "invalid encryption parameters"))
+}
+
 func TestRemove(t *testing.T) {
 	s := New(t.TempDir(), testCfg)
 	err := s.CreateOrUpdate(
@@ -39,12 +39,32 @@ const (
 'table':            Print output in table format with column headers (default)
 'table TEMPLATE':   Print output in table format using the given Go template
 'json':             Print in JSON format
-'TEMPLATE':         Print output using the given Go template.
+'yaml':             Print in YAML format
+'TEMPLATE':         Print output using the given Go template
+'jsonpath=EXPR':    Print output using the given JSONPath expression.
 Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`
+	// DelimitedFormatHelp describes the --format flag behavior for list
+	// commands that also support the csv and tsv formats
+	DelimitedFormatHelp = `Format output using a custom template:
+'table':            Print output in table format with column headers (default)
+'table TEMPLATE':   Print output in table format using the given Go template
+'csv [TEMPLATE]':   Print the table columns as comma-separated values
+'tsv [TEMPLATE]':   Print the table columns as tab-separated values
+'json':             Print in JSON format
+'yaml':             Print in YAML format
+'TEMPLATE':         Print output using the given Go template
+'jsonpath=EXPR':    Print output using the given JSONPath expression.
+Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`
+	// ColumnsHelp describes the --columns flag behavior for list commands
+	ColumnsHelp = `Columns to show in table format (e.g. "ID,STATUS"). Prefix a column with "+" to add it to the default columns, or with "-" to remove it`
+	// WrapHelp describes the --wrap flag behavior for list commands
+	WrapHelp = "Wrap long columns to fit the width of the terminal, instead of truncating them"
 	// InspectFormatHelp describes the --format flag behavior for inspect commands
 	InspectFormatHelp = `Format output using a custom template:
 'json':             Print in JSON format
-'TEMPLATE':         Print output using the given Go template.
+'yaml':             Print in YAML format
+'TEMPLATE':         Print output using the given Go template
+'jsonpath=EXPR':    Print output using the given JSONPath expression.
 Refer to https://docs.docker.com/go/formatting/ for more information about formatting output with templates`
 )
 
@@ -64,6 +84,10 @@ type ClientOptions struct {
 	TLSOptions *tlsconfig.Options
 	Context    string
 	ConfigDir  string
+	Color      string
+	NoPager    bool
+	Quiet      bool
+	Verbose    bool
 }
 
 // NewClientOptions returns a new ClientOptions.
@@ -79,8 +103,12 @@ func (o *ClientOptions) InstallFlags(flags *pflag.FlagSet) {
 	}
 
 	flags.StringVar(&o.ConfigDir, "config", configDir, "Location of client config files")
-	flags.BoolVarP(&o.Debug, "debug", "D", false, "Enable debug mode")
+	flags.BoolVarP(&o.Debug, "debug", "D", false, "Enable debug mode, and print traces of the API requests")
+	flags.BoolVarP(&o.Quiet, "quiet", "q", false, "Suppress decorative output, such as progress and success messages")
+	flags.BoolVar(&o.Verbose, "verbose", false, "Print a summary of the API requests")
 	flags.StringVarP(&o.LogLevel, "log-level", "l", "info", `Set the logging level ("debug", "info", "warn", "error", "fatal")`)
+	flags.StringVar(&o.Color, "color", "auto", `Use colors in the output ("auto", "always", "never")`)
+	flags.BoolVar(&o.NoPager, "no-pager", false, "Do not pipe long output to a pager")
 	flags.BoolVar(&o.TLS, "tls", dockerTLS, "Use TLS; implied by --tlsverify")
 	flags.BoolVar(&o.TLSVerify, FlagTLSVerify, dockerTLSVerify, "Use TLS and verify the remote")
 
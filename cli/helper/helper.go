@@ -0,0 +1,310 @@
+// Package helper implements long-lived helper processes of the CLI, such as
+// caches, that are started on their first connection, and that exit once
+// they're idle.
+//
+// Helpers are registered with [Register], and connected to with [Connect],
+// which starts the helper in the background if it isn't running: the CLI
+// re-executes itself as "docker helper serve NAME", which listens on the
+// socket of the helper in the "helpers" directory of the config directory.
+// "docker helper ls" and "docker helper stop" display, and stop, the running
+// helpers.
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/pkg/errors"
+)
+
+const (
+	helpersDirName = "helpers"
+
+	// defaultIdleTimeout is the time after which helpers that don't set
+	// theirs exit, once they have no connection.
+	defaultIdleTimeout = 5 * time.Minute
+
+	// startTimeout is the time that helpers may take to start listening.
+	startTimeout = 10 * time.Second
+)
+
+// Helper is a helper process.
+type Helper struct {
+	// Name is the name of the helper, which must be unique.
+	Name string
+	// Description is a short description of the helper.
+	Description string
+	// IdleTimeout is the time after which the helper exits, once it has no
+	// connection. The default is 5 minutes.
+	IdleTimeout time.Duration
+	// Serve handles a connection to the helper. The connection is closed
+	// once it returns. ctx is cancelled when the helper is stopped.
+	Serve func(ctx context.Context, conn net.Conn)
+}
+
+// State is the state of a running helper.
+type State struct {
+	Name      string
+	PID       int
+	Socket    string
+	StartedAt time.Time
+}
+
+var (
+	mu      sync.Mutex
+	helpers = map[string]Helper{}
+)
+
+// Register registers a helper. It panics if a helper with the same name is
+// registered already.
+func Register(h Helper) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := helpers[h.Name]; ok {
+		panic("helper " + h.Name + " is already registered")
+	}
+	helpers[h.Name] = h
+}
+
+func lookup(name string) (Helper, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	h, ok := helpers[name]
+	return h, ok
+}
+
+// Registered returns the registered helpers, sorted by name.
+func Registered() []Helper {
+	mu.Lock()
+	defer mu.Unlock()
+	list := make([]Helper, 0, len(helpers))
+	for _, h := range helpers {
+		list = append(list, h)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func helpersDir() string {
+	return filepath.Join(config.Dir(), helpersDirName)
+}
+
+func socketPath(name string) string {
+	return filepath.Join(helpersDir(), name+".sock")
+}
+
+func statePath(name string) string {
+	return filepath.Join(helpersDir(), name+".json")
+}
+
+func dial(ctx context.Context, name string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", socketPath(name))
+}
+
+// startProcess starts the helper with the given name in the background, by
+// re-executing the CLI, with the same config directory.
+var startProcess = func(name string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(helpersDir(), 0o700); err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(filepath.Join(helpersDir(), name+".log"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	cmd := exec.Command(exe, "--config", config.Dir(), "helper", "serve", name)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
+
+// Connect connects to the helper with the given name, after starting it if
+// it isn't running.
+func Connect(ctx context.Context, name string) (net.Conn, error) {
+	if _, ok := lookup(name); !ok {
+		return nil, errors.Errorf("unknown helper %q", name)
+	}
+	if conn, err := dial(ctx, name); err == nil {
+		return conn, nil
+	}
+	if err := startProcess(name); err != nil {
+		return nil, errors.Wrapf(err, "failed to start helper %q", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+	for {
+		conn, err := dial(ctx, name)
+		if err == nil {
+			return conn, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.Errorf("helper %q didn't start: see %s", name, filepath.Join(helpersDir(), name+".log"))
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// Serve runs the helper with the given name in the foreground, until it's
+// idle, stopped, or ctx is cancelled.
+func Serve(ctx context.Context, name string) error {
+	h, ok := lookup(name)
+	if !ok {
+		return errors.Errorf("unknown helper %q", name)
+	}
+	if conn, err := dial(ctx, name); err == nil {
+		_ = conn.Close()
+		return errors.Errorf("helper %q is already running", name)
+	}
+	if err := os.MkdirAll(helpersDir(), 0o700); err != nil {
+		return err
+	}
+	// The socket of a helper that didn't exit cleanly is left behind.
+	sock := socketPath(name)
+	_ = os.Remove(sock)
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	state, err := json.Marshal(State{Name: name, PID: os.Getpid(), Socket: sock, StartedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := ioutils.AtomicWriteFile(statePath(name), state, 0o600); err != nil {
+		return err
+	}
+	defer func() {
+		// Another instance of the helper replaces the state of this one if
+		// they were started concurrently.
+		if s, err := readState(statePath(name)); err == nil && s.PID == os.Getpid() {
+			_ = os.Remove(statePath(name))
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	idleTimeout := h.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+	var (
+		wg     sync.WaitGroup
+		connMu sync.Mutex
+		active int
+	)
+	idle := time.AfterFunc(idleTimeout, cancel)
+	go func() {
+		<-ctx.Done()
+		_ = l.Close()
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			break
+		}
+		connMu.Lock()
+		active++
+		idle.Stop()
+		connMu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			h.Serve(ctx, conn)
+			connMu.Lock()
+			defer connMu.Unlock()
+			if active--; active == 0 {
+				idle.Reset(idleTimeout)
+			}
+		}()
+	}
+	idle.Stop()
+	wg.Wait()
+	return nil
+}
+
+// List returns the state of the running helpers, sorted by name. The state
+// of the helpers that didn't exit cleanly is removed.
+func List(ctx context.Context) ([]State, error) {
+	matches, err := filepath.Glob(filepath.Join(helpersDir(), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var list []State
+	for _, fn := range matches {
+		s, err := readState(fn)
+		if err != nil {
+			continue
+		}
+		conn, err := dial(ctx, s.Name)
+		if err != nil {
+			_ = os.Remove(fn)
+			continue
+		}
+		_ = conn.Close()
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func readState(fn string) (State, error) {
+	var s State
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(b, &s)
+	return s, err
+}
+
+// Stop stops the running helper with the given state, and waits for it to
+// stop listening.
+func Stop(ctx context.Context, s State) error {
+	p, err := os.FindProcess(s.PID)
+	if err != nil {
+		return err
+	}
+	if err := terminate(p); err != nil {
+		return errors.Wrapf(err, "failed to stop helper %q", s.Name)
+	}
+	for {
+		conn, err := dial(ctx, s.Name)
+		if err != nil {
+			// Helpers that are killed don't remove their state.
+			if st, err := readState(statePath(s.Name)); err == nil && st.PID == s.PID {
+				_ = os.Remove(statePath(s.Name))
+			}
+			return nil
+		}
+		_ = conn.Close()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
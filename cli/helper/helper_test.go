@@ -0,0 +1,99 @@
+package helper
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func init() {
+	Register(Helper{
+		Name:        "echo",
+		Description: "Echo the lines it receives",
+		Serve: func(_ context.Context, conn net.Conn) {
+			_, _ = io.Copy(conn, conn)
+		},
+	})
+	Register(Helper{
+		Name:        "idle",
+		IdleTimeout: 100 * time.Millisecond,
+		Serve:       func(context.Context, net.Conn) {},
+	})
+}
+
+// serveInProcess replaces how helpers are started, to serve them in the test
+// process. It returns the errors of Serve.
+func serveInProcess(t *testing.T) <-chan error {
+	t.Helper()
+	errCh := make(chan error, 1)
+	orig := startProcess
+	t.Cleanup(func() { startProcess = orig })
+	startProcess = func(name string) error {
+		go func() { errCh <- Serve(context.Background(), name) }()
+		return nil
+	}
+	return errCh
+}
+
+func TestConnect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("helpers are stopped with signals")
+	}
+	config.SetDir(t.TempDir())
+	errCh := serveInProcess(t)
+
+	conn, err := Connect(context.Background(), "echo")
+	assert.NilError(t, err)
+	_, err = conn.Write([]byte("hello\n"))
+	assert.NilError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(line, "hello\n"))
+	assert.NilError(t, conn.Close())
+
+	err = Serve(context.Background(), "echo")
+	assert.Check(t, is.Error(err, `helper "echo" is already running`))
+
+	states, err := List(context.Background())
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(states, 1))
+	assert.Check(t, is.Equal(states[0].Name, "echo"))
+	assert.Check(t, is.Equal(states[0].PID, os.Getpid()))
+
+	assert.NilError(t, Stop(context.Background(), states[0]))
+	assert.NilError(t, <-errCh)
+	states, err = List(context.Background())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(states, 0))
+}
+
+func TestServeIdle(t *testing.T) {
+	config.SetDir(t.TempDir())
+	errCh := serveInProcess(t)
+
+	conn, err := Connect(context.Background(), "idle")
+	assert.NilError(t, err)
+	assert.NilError(t, conn.Close())
+	select {
+	case err := <-errCh:
+		assert.NilError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("helper didn't exit once it was idle")
+	}
+	_, err = os.Stat(statePath("idle"))
+	assert.Check(t, os.IsNotExist(err))
+}
+
+func TestConnectUnknown(t *testing.T) {
+	_, err := Connect(context.Background(), "unknown")
+	assert.Check(t, is.Error(err, `unknown helper "unknown"`))
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package helper
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr returns the attributes of helper processes, which run in
+// their own session, so that they outlive the CLI and its terminal.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
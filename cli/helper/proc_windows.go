@@ -0,0 +1,19 @@
+package helper
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachedProcAttr returns the attributes of helper processes, which are
+// detached from the console of the CLI, so that they outlive it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS}
+}
+
+// terminate kills p, as processes without a console can't be interrupted.
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
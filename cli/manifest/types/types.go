@@ -23,6 +23,9 @@ type ImageManifest struct {
 	SchemaV2Manifest *schema2.DeserializedManifest `json:",omitempty"`
 	// OCIManifest is used for inspection
 	OCIManifest *ocischema.DeserializedManifest `json:",omitempty"`
+	// OCILayout is the path of the OCI image layout that the manifest was
+	// created from, if any, which its blobs are pushed from.
+	OCILayout string `json:",omitempty"`
 }
 
 // OCIPlatform creates an OCI platform from a manifest list platform spec
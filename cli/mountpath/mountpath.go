@@ -0,0 +1,104 @@
+// Package mountpath translates the host paths of bind mounts that are written
+// in the style of another environment than the one the CLI runs in, such as
+// the paths of Git Bash or WSL on Windows, or Windows paths in WSL, into paths
+// that the daemon understands.
+package mountpath
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Style is the style of a path that is translated.
+type Style string
+
+const (
+	// Windows is the style of Windows paths, such as `C:\Users\me`.
+	Windows Style = "Windows"
+	// WSL is the style of the paths of Windows drives in the Windows
+	// Subsystem for Linux, such as "/mnt/c/Users/me".
+	WSL Style = "WSL"
+	// GitBash is the style of the paths of Windows drives in Git Bash and
+	// other MSYS shells, such as "/c/Users/me".
+	GitBash Style = "Git Bash"
+)
+
+// Host is the environment that the CLI runs in.
+type Host struct {
+	// OS is the operating system of the host, as in runtime.GOOS.
+	OS string
+	// WSL is set if the CLI runs in the Windows Subsystem for Linux.
+	WSL bool
+}
+
+// CurrentHost returns the environment that the CLI runs in.
+func CurrentHost() Host {
+	return Host{
+		OS:  runtime.GOOS,
+		WSL: runtime.GOOS == "linux" && (os.Getenv("WSL_DISTRO_NAME") != "" || os.Getenv("WSL_INTEROP") != ""),
+	}
+}
+
+// Translation is the translation of a path.
+type Translation struct {
+	// Original is the path as it was written.
+	Original string
+	// Path is the translated path.
+	Path string
+	// Style is the style that Original was written in.
+	Style Style
+}
+
+// Translate returns the translation of path, as passed to a CLI running on
+// host, for a daemon running on daemonOS. It returns false if the path
+// doesn't need to be translated.
+//
+// On Windows, the paths of Windows drives written in the style of WSL or Git
+// Bash are translated to Windows paths. In WSL, Windows paths are translated
+// to the paths of the Windows drives in WSL, unless the daemon runs Windows.
+func Translate(host Host, daemonOS, path string) (Translation, bool) {
+	switch {
+	case host.OS == "windows":
+		if drive, rest, ok := cutDrive(path, "/mnt/"); ok {
+			return Translation{Original: path, Path: windowsPath(drive, rest), Style: WSL}, true
+		}
+		// Git Bash doesn't translate paths starting with "//" itself, so
+		// these are commonly used to prevent it from mangling the path.
+		p := path
+		if strings.HasPrefix(p, "//") {
+			p = p[1:]
+		}
+		if drive, rest, ok := cutDrive(p, "/"); ok {
+			return Translation{Original: path, Path: windowsPath(drive, rest), Style: GitBash}, true
+		}
+	case host.WSL && daemonOS != "windows":
+		if len(path) >= 2 && isLetter(path[0]) && path[1] == ':' && (len(path) == 2 || path[2] == '\\' || path[2] == '/') {
+			rest := strings.Trim(strings.ReplaceAll(path[2:], `\`, "/"), "/")
+			p := "/mnt/" + strings.ToLower(path[:1])
+			if rest != "" {
+				p += "/" + rest
+			}
+			return Translation{Original: path, Path: p, Style: Windows}, true
+		}
+	}
+	return Translation{}, false
+}
+
+// cutDrive cuts the drive letter following prefix from path, such as "c" in
+// "/mnt/c/Users" with the "/mnt/" prefix, and returns the rest of the path.
+func cutDrive(path, prefix string) (drive byte, rest string, ok bool) {
+	p, ok := strings.CutPrefix(path, prefix)
+	if !ok || len(p) == 0 || !isLetter(p[0]) || (len(p) > 1 && p[1] != '/') {
+		return 0, "", false
+	}
+	return p[0], p[1:], true
+}
+
+func windowsPath(drive byte, rest string) string {
+	return strings.ToUpper(string(drive)) + `:\` + strings.ReplaceAll(strings.Trim(rest, "/"), "/", `\`)
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,51 @@
+package mountpath
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestTranslate(t *testing.T) {
+	windows := Host{OS: "windows"}
+	wsl := Host{OS: "linux", WSL: true}
+	linux := Host{OS: "linux"}
+
+	testCases := []struct {
+		doc      string
+		host     Host
+		daemonOS string
+		path     string
+		expected string
+		style    Style
+	}{
+		{doc: "git bash", host: windows, daemonOS: "linux", path: "/c/Users/me/src", expected: `C:\Users\me\src`, style: GitBash},
+		{doc: "git bash double slash", host: windows, daemonOS: "linux", path: "//d/work/", expected: `D:\work`, style: GitBash},
+		{doc: "git bash drive", host: windows, daemonOS: "windows", path: "/c", expected: `C:\`, style: GitBash},
+		{doc: "wsl on windows", host: windows, daemonOS: "linux", path: "/mnt/c/Users/me", expected: `C:\Users\me`, style: WSL},
+		{doc: "windows on windows", host: windows, daemonOS: "linux", path: `C:\Users\me`},
+		{doc: "daemon path on windows", host: windows, daemonOS: "linux", path: "/var/run/docker.sock"},
+		{doc: "long first element on windows", host: windows, daemonOS: "linux", path: "/mnt/src"},
+		{doc: "windows in wsl", host: wsl, daemonOS: "linux", path: `C:\Users\me\src`, expected: "/mnt/c/Users/me/src", style: Windows},
+		{doc: "windows with slashes in wsl", host: wsl, path: "D:/work", expected: "/mnt/d/work", style: Windows},
+		{doc: "windows drive in wsl", host: wsl, daemonOS: "linux", path: `C:\`, expected: "/mnt/c", style: Windows},
+		{doc: "windows daemon in wsl", host: wsl, daemonOS: "windows", path: `C:\Users\me`},
+		{doc: "wsl in wsl", host: wsl, daemonOS: "linux", path: "/mnt/c/Users/me"},
+		{doc: "relative in wsl", host: wsl, daemonOS: "linux", path: "c:data"},
+		{doc: "linux", host: linux, daemonOS: "linux", path: `C:\Users\me`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.doc, func(t *testing.T) {
+			tr, ok := Translate(tc.host, tc.daemonOS, tc.path)
+			if tc.expected == "" {
+				assert.Check(t, !ok, "unexpected translation: %+v", tr)
+				return
+			}
+			assert.Assert(t, ok)
+			assert.Check(t, is.Equal(tr.Path, tc.expected))
+			assert.Check(t, is.Equal(tr.Style, tc.style))
+			assert.Check(t, is.Equal(tr.Original, tc.path))
+		})
+	}
+}
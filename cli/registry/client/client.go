@@ -2,7 +2,9 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -12,7 +14,9 @@ import (
 	"github.com/docker/distribution"
 	distributionclient "github.com/docker/distribution/registry/client"
 	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/errdefs"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -23,16 +27,50 @@ type RegistryClient interface {
 	GetManifest(ctx context.Context, ref reference.Named) (manifesttypes.ImageManifest, error)
 	GetManifestList(ctx context.Context, ref reference.Named) ([]manifesttypes.ImageManifest, error)
 	MountBlob(ctx context.Context, source reference.Canonical, target reference.Named) error
+	PutBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error
 	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
+	GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error)
+	GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error)
+	GetReferrers(ctx context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error)
+	GetTags(ctx context.Context, ref reference.Named) ([]string, error)
 }
 
 // NewRegistryClient returns a new RegistryClient with a resolver
-func NewRegistryClient(resolver AuthConfigResolver, userAgent string, insecure bool) RegistryClient {
-	return &client{
+func NewRegistryClient(resolver AuthConfigResolver, userAgent string, insecure bool, opts ...Option) RegistryClient {
+	c := &client{
 		authConfigResolver: resolver,
 		insecureRegistry:   insecure,
 		userAgent:          userAgent,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// Option configures the registry client.
+type Option func(*client)
+
+// RegistryOptions are the settings of a registry.
+type RegistryOptions struct {
+	// Mirrors are the URLs of the mirrors of the registry, which are tried
+	// before the registry when fetching manifests and blobs.
+	Mirrors []string
+	// Insecure allows connecting to the registry over plain HTTP, or over
+	// HTTPS without verifying its certificate.
+	Insecure bool
+	// CAFile is the path of a file with the PEM-encoded certificates of the
+	// certificate authorities to trust for the registry, in addition to the
+	// ones of the system.
+	CAFile string
+}
+
+// WithRegistryOptions sets a function that returns the settings of a
+// registry, by its domain, such as "docker.io".
+func WithRegistryOptions(fn func(domain string) RegistryOptions) Option {
+	return func(c *client) {
+		c.registryOptions = fn
+	}
 }
 
 // AuthConfigResolver returns Auth Configuration for an index
@@ -48,6 +86,31 @@ type client struct {
 	authConfigResolver AuthConfigResolver
 	insecureRegistry   bool
 	userAgent          string
+	registryOptions    func(domain string) RegistryOptions
+}
+
+// options returns the settings of the registry of ref.
+func (c *client) options(ref reference.Named) RegistryOptions {
+	var opts RegistryOptions
+	if c.registryOptions != nil {
+		opts = c.registryOptions(reference.Domain(ref))
+	}
+	opts.Insecure = opts.Insecure || c.insecureRegistry
+	return opts
+}
+
+// repositoryEndpoint returns the endpoint of the registry of ref to push to,
+// with the settings of the registry applied.
+func (c *client) repositoryEndpoint(ref reference.Named) (repositoryEndpoint, error) {
+	opts := c.options(ref)
+	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, opts.Insecure)
+	if err != nil {
+		return repositoryEndpoint{}, err
+	}
+	if err := configureTLS(&repoEndpoint.endpoint, opts); err != nil {
+		return repositoryEndpoint{}, err
+	}
+	return repoEndpoint, nil
 }
 
 // ErrBlobCreated returned when a blob mount request was created
@@ -74,7 +137,7 @@ var _ RegistryClient = &client{}
 
 // MountBlob into the registry, so it can be referenced by a manifest
 func (c *client) MountBlob(ctx context.Context, sourceRef reference.Canonical, targetRef reference.Named) error {
-	repoEndpoint, err := newDefaultRepositoryEndpoint(targetRef, c.insecureRegistry)
+	repoEndpoint, err := c.repositoryEndpoint(targetRef)
 	if err != nil {
 		return err
 	}
@@ -97,9 +160,42 @@ func (c *client) MountBlob(ctx context.Context, sourceRef reference.Canonical, t
 	return ErrBlobCreated{From: sourceRef, Target: targetRef}
 }
 
+// PutBlob uploads a blob to the repository of the reference, unless the
+// repository already has it.
+func (c *client) PutBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+	repoEndpoint, err := c.repositoryEndpoint(ref)
+	if err != nil {
+		return err
+	}
+	repoEndpoint.actions = trust.ActionsPushAndPull
+	repo, err := c.getRepositoryForReference(ctx, ref, repoEndpoint)
+	if err != nil {
+		return err
+	}
+	blobs := repo.Blobs(ctx)
+	switch _, err := blobs.Stat(ctx, desc.Digest); {
+	case err == nil:
+		logrus.Debugf("blob %s already exists in %s", desc.Digest, ref.Name())
+		return nil
+	case !errors.Is(err, distribution.ErrBlobUnknown):
+		return errors.Wrapf(err, "failed to check blob %s", desc.Digest)
+	}
+
+	w, err := blobs.Create(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to upload blob %s", desc.Digest)
+	}
+	if _, err := w.ReadFrom(content); err != nil {
+		_ = w.Cancel(ctx)
+		return errors.Wrapf(err, "failed to upload blob %s", desc.Digest)
+	}
+	_, err = w.Commit(ctx, distribution.Descriptor{MediaType: desc.MediaType, Digest: desc.Digest, Size: desc.Size})
+	return errors.Wrapf(err, "failed to upload blob %s", desc.Digest)
+}
+
 // PutManifest sends the manifest to a registry and returns the new digest
 func (c *client) PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error) {
-	repoEndpoint, err := newDefaultRepositoryEndpoint(ref, c.insecureRegistry)
+	repoEndpoint, err := c.repositoryEndpoint(ref)
 	if err != nil {
 		return "", err
 	}
@@ -186,6 +282,116 @@ func (c *client) GetManifestList(ctx context.Context, ref reference.Named) ([]ma
 	return result, err
 }
 
+// GetRawManifest returns the descriptor and the content of the manifest, or
+// image index, of the reference, as stored in the registry.
+func (c *client) GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	var desc ocispec.Descriptor
+	var payload []byte
+	fetch := func(ctx context.Context, repo distribution.Repository, ref reference.Named) (bool, error) {
+		manifest, err := getManifest(ctx, repo, ref)
+		if err != nil {
+			return false, err
+		}
+		mediaType, p, err := manifest.Payload()
+		if err != nil {
+			return false, err
+		}
+		desc, payload = ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(p), Size: int64(len(p))}, p
+		return true, nil
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return desc, payload, err
+}
+
+// GetBlob returns the content of a blob of the repository of the reference.
+func (c *client) GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error) {
+	var blob []byte
+	fetch := func(ctx context.Context, repo distribution.Repository, _ reference.Named) (bool, error) {
+		b, err := repo.Blobs(ctx).Get(ctx, ref.Digest())
+		if err != nil {
+			return false, err
+		}
+		blob = b
+		return true, nil
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return blob, err
+}
+
+// GetReferrers returns the descriptors of the manifests that refer to the
+// manifest of the reference, such as its signatures and attestations. The
+// referrers are listed with the referrers API of the registry, or, if the
+// registry doesn't support it, from the image index of the referrers tag
+// ("<algorithm>-<digest>") of the manifest.
+func (c *client) GetReferrers(ctx context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error) {
+	repoEndpoint, err := c.repositoryEndpoint(ref)
+	if err != nil {
+		return nil, err
+	}
+	httpTransport, err := c.getHTTPTransportForRepoEndpoint(ctx, repoEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(repoEndpoint.BaseURL(), "/") + "/v2/" + repoEndpoint.Name() + "/referrers/" + ref.Digest().String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ocispec.MediaTypeImageIndex)
+	resp, err := (&http.Client{Transport: httpTransport}).Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list referrers")
+	}
+	defer resp.Body.Close()
+
+	var index ocispec.Index
+	switch {
+	case resp.StatusCode == http.StatusOK && strings.HasPrefix(resp.Header.Get("Content-Type"), ocispec.MediaTypeImageIndex):
+		if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+			return nil, errors.Wrap(err, "invalid referrers response")
+		}
+	case resp.StatusCode == http.StatusOK, resp.StatusCode == http.StatusNotFound:
+		// The registry doesn't support the referrers API.
+		tagged, err := reference.WithTag(reference.TrimNamed(ref), ref.Digest().Algorithm().String()+"-"+ref.Digest().Encoded())
+		if err != nil {
+			return nil, err
+		}
+		_, payload, err := c.GetRawManifest(ctx, tagged)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if err := json.Unmarshal(payload, &index); err != nil {
+			return nil, errors.Wrap(err, "invalid referrers index")
+		}
+	default:
+		return nil, errors.Errorf("failed to list referrers: unexpected HTTP status %s", resp.Status)
+	}
+	return index.Manifests, nil
+}
+
+// GetTags returns the tags of the repository of the reference, in the order
+// that the registry lists them, which is the lexical order for registries
+// that implement the distribution spec.
+func (c *client) GetTags(ctx context.Context, ref reference.Named) ([]string, error) {
+	var tags []string
+	fetch := func(ctx context.Context, repo distribution.Repository, _ reference.Named) (bool, error) {
+		t, err := repo.Tags(ctx).All(ctx)
+		if err != nil {
+			return false, err
+		}
+		tags = t
+		return true, nil
+	}
+
+	err := c.iterateEndpoints(ctx, ref, fetch)
+	return tags, err
+}
+
 func getManifestOptionsFromReference(ref reference.Named) (digest.Digest, []distribution.ManifestServiceOption, error) {
 	if tagged, isTagged := ref.(reference.NamedTagged); isTagged {
 		tag := tagged.Tag()
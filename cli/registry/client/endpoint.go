@@ -1,8 +1,10 @@
 package client
 
 import (
+	"crypto/x509"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/distribution/reference"
@@ -11,6 +13,7 @@ import (
 	"github.com/docker/distribution/registry/client/transport"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/registry"
+	"github.com/docker/go-connections/tlsconfig"
 	"github.com/pkg/errors"
 )
 
@@ -74,6 +77,32 @@ func getDefaultEndpointFromRepoInfo(repoInfo *registry.RepositoryInfo) (registry
 	return endpoint, nil
 }
 
+// configureTLS adds the certificate authorities of the CA file of the
+// registry to the ones that the endpoint trusts.
+func configureTLS(endpoint *registry.APIEndpoint, opts RegistryOptions) error {
+	if opts.CAFile == "" {
+		return nil
+	}
+	pem, err := os.ReadFile(opts.CAFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the CA file of the registry")
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if endpoint.TLSConfig == nil {
+		endpoint.TLSConfig = tlsconfig.ServerDefault()
+	} else if endpoint.TLSConfig.RootCAs != nil {
+		pool = endpoint.TLSConfig.RootCAs
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return errors.Errorf("CA file %s of the registry has no PEM-encoded certificates", opts.CAFile)
+	}
+	endpoint.TLSConfig.RootCAs = pool
+	return nil
+}
+
 // getHTTPTransport builds a transport for use in communicating with a registry
 func getHTTPTransport(authConfig registrytypes.AuthConfig, endpoint registry.APIEndpoint, repoName, userAgent string, actions []string) (http.RoundTripper, error) {
 	// get the http transport, this will be used in a client to upload manifest
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"net/url"
 
 	"github.com/distribution/reference"
 	"github.com/docker/cli/cli/manifest/types"
@@ -14,6 +15,7 @@ import (
 	v2 "github.com/docker/distribution/registry/api/v2"
 	distclient "github.com/docker/distribution/registry/client"
 	"github.com/docker/docker/registry"
+	"github.com/docker/go-connections/tlsconfig"
 	"github.com/opencontainers/go-digest"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
@@ -215,7 +217,8 @@ func continueOnError(err error) bool {
 }
 
 func (c *client) iterateEndpoints(ctx context.Context, namedRef reference.Named, each func(context.Context, distribution.Repository, reference.Named) (bool, error)) error {
-	endpoints, err := allEndpoints(namedRef, c.insecureRegistry)
+	opts := c.options(namedRef)
+	endpoints, err := allEndpoints(namedRef, opts)
 	if err != nil {
 		return err
 	}
@@ -234,26 +237,29 @@ func (c *client) iterateEndpoints(ctx context.Context, namedRef reference.Named,
 			}
 		}
 
-		if c.insecureRegistry {
+		if opts.Insecure && !endpoint.Mirror {
 			endpoint.TLSConfig.InsecureSkipVerify = true
 		}
+		if err := configureTLS(&endpoint, opts); err != nil {
+			return err
+		}
 		repoEndpoint := repositoryEndpoint{endpoint: endpoint, info: repoInfo}
 		repo, err := c.getRepositoryForReference(ctx, namedRef, repoEndpoint)
 		if err != nil {
 			logrus.Debugf("error %s with repo endpoint %+v", err, repoEndpoint)
-			if _, ok := err.(ErrHTTPProto); ok {
+			if _, ok := err.(ErrHTTPProto); ok || endpoint.Mirror {
 				continue
 			}
 			return err
 		}
 
-		if endpoint.URL.Scheme == "http" && !c.insecureRegistry {
+		if endpoint.URL.Scheme == "http" && !opts.Insecure && !endpoint.Mirror {
 			logrus.Debugf("skipping non-tls registry endpoint: %s", endpoint.URL)
 			continue
 		}
 		done, err := each(ctx, repo, namedRef)
 		if err != nil {
-			if continueOnError(err) {
+			if endpoint.Mirror || continueOnError(err) {
 				if endpoint.URL.Scheme == "https" {
 					confirmedTLSRegistries[endpoint.URL.Host] = true
 				}
@@ -270,15 +276,34 @@ func (c *client) iterateEndpoints(ctx context.Context, namedRef reference.Named,
 	return newNotFoundError(namedRef.String())
 }
 
-// allEndpoints returns a list of endpoints ordered by priority (v2, http).
-func allEndpoints(namedRef reference.Named, insecure bool) ([]registry.APIEndpoint, error) {
+// allEndpoints returns a list of endpoints ordered by priority (mirrors, v2,
+// http).
+func allEndpoints(namedRef reference.Named, opts RegistryOptions) ([]registry.APIEndpoint, error) {
 	repoInfo, err := registry.ParseRepositoryInfo(namedRef)
 	if err != nil {
 		return nil, err
 	}
 
+	var endpoints []registry.APIEndpoint
+	for _, m := range opts.Mirrors {
+		mirror, err := registry.ValidateMirror(m)
+		if err != nil {
+			return nil, err
+		}
+		mirrorURL, err := url.Parse(mirror)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, registry.APIEndpoint{
+			URL:          mirrorURL,
+			Mirror:       true,
+			TrimHostname: true,
+			TLSConfig:    tlsconfig.ServerDefault(),
+		})
+	}
+
 	var serviceOpts registry.ServiceOptions
-	if insecure {
+	if opts.Insecure {
 		logrus.Debugf("allowing insecure registry for: %s", reference.Domain(namedRef))
 		serviceOpts.InsecureRegistries = []string{reference.Domain(namedRef)}
 	}
@@ -286,9 +311,13 @@ func allEndpoints(namedRef reference.Named, insecure bool) ([]registry.APIEndpoi
 	if err != nil {
 		return []registry.APIEndpoint{}, err
 	}
-	endpoints, err := registryService.LookupPullEndpoints(reference.Domain(repoInfo.Name))
+	registryEndpoints, err := registryService.LookupPullEndpoints(reference.Domain(repoInfo.Name))
+	if err != nil {
+		return nil, err
+	}
+	endpoints = append(endpoints, registryEndpoints...)
 	logrus.Debugf("endpoints for %s: %v", namedRef, endpoints)
-	return endpoints, err
+	return endpoints, nil
 }
 
 func newNotFoundError(ref string) *notFoundError {
@@ -14,6 +14,9 @@ func NoArgs(cmd *cobra.Command, args []string) error {
 	}
 
 	if cmd.HasSubCommands() {
+		if msg := suggestionsMessage(cmd, args[0]); msg != "" {
+			return errors.Errorf("%q is not a %s command.%s\nSee '%s --help'", args[0], cmd.CommandPath(), msg, cmd.CommandPath())
+		}
 		return errors.Errorf("\n" + strings.TrimRight(cmd.UsageString(), "\n"))
 	}
 
@@ -0,0 +1,51 @@
+package streams
+
+import "fmt"
+
+// Level is the level of output of a stream, set by the --quiet, --verbose,
+// and --debug options.
+type Level int
+
+const (
+	// LevelQuiet suppresses decorative output, such as progress and success
+	// messages.
+	LevelQuiet Level = iota - 1
+	// LevelNormal is the default level of output.
+	LevelNormal
+	// LevelVerbose adds details, such as a summary of the API requests.
+	LevelVerbose
+	// LevelDebug adds traces of the API requests and responses.
+	LevelDebug
+)
+
+// SetLevel sets the level of output of the stream.
+func (o *Out) SetLevel(level Level) {
+	o.level = level
+}
+
+// Level returns the level of output of the stream.
+func (o *Out) Level() Level {
+	return o.level
+}
+
+// Infof writes decorative output, such as progress and success messages,
+// which is suppressed at [LevelQuiet].
+func (o *Out) Infof(format string, a ...any) {
+	if o.level >= LevelNormal {
+		_, _ = fmt.Fprintf(o, format, a...)
+	}
+}
+
+// Verbosef writes output that is only written at [LevelVerbose] and above.
+func (o *Out) Verbosef(format string, a ...any) {
+	if o.level >= LevelVerbose {
+		_, _ = fmt.Fprintf(o, format, a...)
+	}
+}
+
+// Debugf writes output that is only written at [LevelDebug].
+func (o *Out) Debugf(format string, a ...any) {
+	if o.level >= LevelDebug {
+		_, _ = fmt.Fprintf(o, format, a...)
+	}
+}
@@ -0,0 +1,30 @@
+package streams
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		level    Level
+		expected string
+	}{
+		{level: LevelQuiet, expected: ""},
+		{level: LevelNormal, expected: "info "},
+		{level: LevelVerbose, expected: "info verbose "},
+		{level: LevelDebug, expected: "info verbose debug "},
+	}
+	for _, tc := range tests {
+		var buf bytes.Buffer
+		out := NewOut(&buf)
+		out.SetLevel(tc.level)
+		out.Infof("%s ", "info")
+		out.Verbosef("%s ", "verbose")
+		out.Debugf("%s ", "debug")
+		assert.Check(t, is.Equal(buf.String(), tc.expected))
+	}
+}
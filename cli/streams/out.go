@@ -13,7 +13,10 @@ import (
 // is connected, getting the TTY size, and putting the terminal in raw mode.
 type Out struct {
 	commonStream
-	out io.Writer
+	out    io.Writer
+	pager  string
+	paging bool
+	level  Level
 }
 
 func (o *Out) Write(p []byte) (int, error) {
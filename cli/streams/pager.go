@@ -0,0 +1,92 @@
+package streams
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultPager returns the pager command set by the PAGER environment
+// variable, or "less" if PAGER isn't set. Paging is disabled if PAGER is
+// set to an empty string or to "cat".
+func DefaultPager() string {
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		return "less"
+	}
+	if strings.TrimSpace(pager) == "cat" {
+		return ""
+	}
+	return pager
+}
+
+// SetPager sets the command of the pager that the output is piped to by
+// [Out.StartPager]. Paging is disabled if command is empty.
+func (o *Out) SetPager(command string) {
+	o.pager = command
+}
+
+// StartPager pipes the output to the pager set with [Out.SetPager], if the
+// output is a terminal, until the returned function is called, which waits
+// for the pager to exit. It is a no-op if no pager is set, if the pager
+// fails to start, or if the output is already piped to a pager.
+//
+// "less" is configured to exit if the output fits on the screen, and to
+// pass colors through, unless the LESS environment variable is set.
+func (o *Out) StartPager() (stop func()) {
+	args := strings.Fields(o.pager)
+	if len(args) == 0 || !o.isTerminal || o.paging {
+		return func() {}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = o.out
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		logrus.WithError(err).Debug("Error starting the pager")
+		return func() {}
+	}
+	if err := cmd.Start(); err != nil {
+		logrus.WithError(err).Debugf("Error starting the pager %q", o.pager)
+		return func() {}
+	}
+
+	out := o.out
+	o.out = &pagerWriter{w: w}
+	o.paging = true
+	return func() {
+		_ = w.Close()
+		_ = cmd.Wait()
+		o.out = out
+		o.paging = false
+	}
+}
+
+// pagerWriter writes to the input of a pager. The output is discarded once
+// the pager exits, for example if the user quits it before reading the
+// whole output, so that commands don't fail writing their output.
+type pagerWriter struct {
+	w      io.Writer
+	closed bool
+}
+
+func (p *pagerWriter) Write(b []byte) (int, error) {
+	if p.closed {
+		return len(b), nil
+	}
+	if _, err := p.w.Write(b); err != nil {
+		p.closed = true
+	}
+	return len(b), nil
+}
@@ -0,0 +1,55 @@
+package streams
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestDefaultPager(t *testing.T) {
+	t.Setenv("PAGER", "more -d")
+	assert.Check(t, is.Equal(DefaultPager(), "more -d"))
+	t.Setenv("PAGER", "cat")
+	assert.Check(t, is.Equal(DefaultPager(), ""))
+	t.Setenv("PAGER", "")
+	assert.Check(t, is.Equal(DefaultPager(), ""))
+}
+
+func TestStartPager(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOut(&buf)
+	out.SetPager("tr a-z A-Z")
+
+	// The output isn't piped to the pager if it isn't a terminal.
+	stop := out.StartPager()
+	_, _ = fmt.Fprint(out, "hello ")
+	stop()
+
+	out.SetIsTerminal(true)
+	stop = out.StartPager()
+	// The output is already piped to the pager.
+	out.StartPager()()
+	_, _ = fmt.Fprint(out, "world")
+	stop()
+	assert.Check(t, is.Equal(buf.String(), "hello WORLD"))
+
+	_, _ = fmt.Fprint(out, "!")
+	assert.Check(t, is.Equal(buf.String(), "hello WORLD!"))
+}
+
+func TestStartPagerExited(t *testing.T) {
+	var buf bytes.Buffer
+	out := NewOut(&buf)
+	out.SetIsTerminal(true)
+	out.SetPager("true")
+
+	stop := out.StartPager()
+	defer stop()
+	for i := 0; i < 1000; i++ {
+		_, err := fmt.Fprintln(out, "discarded once the pager exited")
+		assert.NilError(t, err)
+	}
+}
@@ -0,0 +1,103 @@
+// FIXME(thaJeztah): remove once we are a module; the go:build directive prevents go from downgrading language version to go1.16:
+//go:build go1.21
+
+package cli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// maxSuggestionDistance is the maximum edit distance between an unknown
+	// command, and the commands that are suggested for it.
+	maxSuggestionDistance = 2
+
+	// maxSuggestions is the maximum number of commands that are suggested
+	// for an unknown command.
+	maxSuggestions = 3
+)
+
+// SuggestCommands returns the names of the subcommands of cmd that name is
+// likely a misspelling of, the closest first. The commands whose name, or an
+// alias, is within a small edit distance of name are suggested, as are the
+// commands that name is a prefix of.
+func SuggestCommands(cmd *cobra.Command, name string) []string {
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	var suggestions []suggestion
+	name = strings.ToLower(name)
+	// Short names are within a small edit distance of most commands.
+	maxDistance := min(maxSuggestionDistance, max(1, len(name)/2))
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() {
+			continue
+		}
+		distance := -1
+		for _, n := range append([]string{c.Name()}, c.Aliases...) {
+			n = strings.ToLower(n)
+			d := EditDistance(name, n)
+			if d > maxDistance && !(len(name) > 1 && strings.HasPrefix(n, name)) {
+				continue
+			}
+			if distance == -1 || d < distance {
+				distance = d
+			}
+		}
+		if distance != -1 {
+			suggestions = append(suggestions, suggestion{name: c.Name(), distance: distance})
+		}
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].distance < suggestions[j].distance
+	})
+	names := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		if len(names) == maxSuggestions {
+			break
+		}
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// EditDistance returns the Levenshtein distance between a and b.
+func EditDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// suggestionsMessage returns the message suggesting the subcommands of cmd
+// that name is likely a misspelling of, or an empty string if there are
+// none.
+func suggestionsMessage(cmd *cobra.Command, name string) string {
+	suggestions := SuggestCommands(cmd, name)
+	if len(suggestions) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\nDid you mean this?\n")
+	for _, s := range suggestions {
+		sb.WriteString("\t" + cmd.CommandPath() + " " + s + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestSuggestCommands(t *testing.T) {
+	root := &cobra.Command{Use: "docker"}
+	for _, c := range []*cobra.Command{
+		{Use: "image", Run: func(*cobra.Command, []string) {}},
+		{Use: "images", Run: func(*cobra.Command, []string) {}},
+		{Use: "network", Run: func(*cobra.Command, []string) {}},
+		{Use: "rm", Aliases: []string{"remove"}, Run: func(*cobra.Command, []string) {}},
+		{Use: "secret", Hidden: true, Run: func(*cobra.Command, []string) {}},
+	} {
+		root.AddCommand(c)
+	}
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{name: "imges", expected: []string{"images", "image"}},
+		{name: "IMAGE", expected: []string{"image", "images"}},
+		{name: "net", expected: []string{"network"}},
+		{name: "remvoe", expected: []string{"rm"}},
+		{name: "secrt", expected: []string{}},
+		{name: "n", expected: []string{}},
+		{name: "rn", expected: []string{"rm"}},
+		{name: "zzzzzz", expected: []string{}},
+	}
+	for _, tc := range tests {
+		assert.Check(t, is.DeepEqual(SuggestCommands(root, tc.name), tc.expected), tc.name)
+	}
+}
+
+func TestNoArgsSuggestions(t *testing.T) {
+	cmd := &cobra.Command{Use: "image", Args: NoArgs, Run: func(*cobra.Command, []string) {}}
+	cmd.AddCommand(&cobra.Command{Use: "ls", Run: func(*cobra.Command, []string) {}})
+	assert.Check(t, is.Error(NoArgs(cmd, []string{"lss"}), "\"lss\" is not a image command.\n\nDid you mean this?\n\timage ls\n\nSee 'image --help'"))
+	assert.Check(t, is.ErrorContains(NoArgs(cmd, []string{"build"}), "Usage:"))
+}
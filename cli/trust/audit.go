@@ -0,0 +1,71 @@
+package trust
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BypassEntry is an entry of the audit log of commands that bypassed content
+// trust with the "--disable-content-trust" flag, while content trust was
+// enabled by the DOCKER_CONTENT_TRUST environment variable.
+type BypassEntry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user,omitempty"`
+	Command string    `json:"command"`
+	Args    []string  `json:"args,omitempty"`
+}
+
+// BypassAuditFile returns the path of the audit log of content trust bypasses.
+func BypassAuditFile() string {
+	return filepath.Join(GetTrustDirectory(), "bypass-audit.json")
+}
+
+// RecordBypass appends entry to the audit log of content trust bypasses.
+func RecordBypass(entry BypassEntry) error {
+	fileName := BypassAuditFile()
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(entry); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Bypasses returns the entries of the audit log of content trust bypasses,
+// oldest first.
+func Bypasses() ([]BypassEntry, error) {
+	fileName := BypassAuditFile()
+	f, err := os.Open(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []BypassEntry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry BypassEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errors.Wrapf(err, "invalid entry on line %d of %s", line, fileName)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,33 @@
+package trust
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/cli/cli/config"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/fs"
+)
+
+func TestBypassAuditLog(t *testing.T) {
+	dir := fs.NewDir(t, "trust-audit")
+	configDir := config.Dir()
+	config.SetDir(dir.Path())
+	t.Cleanup(func() { config.SetDir(configDir) })
+
+	entries, err := Bypasses()
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(entries, 0))
+
+	expected := []BypassEntry{
+		{Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), User: "alice", Command: "docker pull", Args: []string{"alpine"}},
+		{Time: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), Command: "docker build"},
+	}
+	for _, e := range expected {
+		assert.NilError(t, RecordBypass(e))
+	}
+	entries, err = Bypasses()
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(entries, expected))
+}
@@ -0,0 +1,457 @@
+package trust
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/version"
+	"github.com/docker/distribution"
+	"github.com/docker/docker/errdefs"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ArtifactTypeNotation is the artifact type of the manifests of Notation
+	// signatures, which are referrers of the manifests that they sign.
+	ArtifactTypeNotation = "application/vnd.cncf.notary.signature"
+	// MediaTypeJWSEnvelope is the media type of the JWS envelopes of
+	// Notation signatures.
+	MediaTypeJWSEnvelope = "application/jose+json"
+
+	mediaTypeNotationPayload = "application/vnd.cncf.notary.payload.v1+json"
+	mediaTypeEmptyJSON       = "application/vnd.oci.empty.v1+json"
+
+	headerSigningScheme   = "io.cncf.notary.signingScheme"
+	headerSigningTime     = "io.cncf.notary.signingTime"
+	headerExpiry          = "io.cncf.notary.expiry"
+	signingSchemeX509     = "notary.x509"
+	annotationThumbprints = "io.cncf.notary.x509chain.thumbprint#S256"
+)
+
+// NotationRegistry is the repository of a registry that Notation signatures
+// are pushed to, and fetched from. It's implemented by the registry client
+// of the CLI.
+type NotationRegistry interface {
+	PutBlob(ctx context.Context, ref reference.Named, desc ocispec.Descriptor, content io.Reader) error
+	PutManifest(ctx context.Context, ref reference.Named, manifest distribution.Manifest) (digest.Digest, error)
+	GetRawManifest(ctx context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error)
+	GetBlob(ctx context.Context, ref reference.Canonical) ([]byte, error)
+	GetReferrers(ctx context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error)
+}
+
+// NotationPayload is the payload that Notation signs.
+type NotationPayload struct {
+	TargetArtifact ocispec.Descriptor `json:"targetArtifact"`
+}
+
+// jwsEnvelope is a JWS envelope, in the JSON serialization, of a Notation
+// signature.
+type jwsEnvelope struct {
+	Payload   string `json:"payload"`
+	Protected string `json:"protected"`
+	Header    struct {
+		CertChain    [][]byte `json:"x5c"`
+		SigningAgent string   `json:"io.cncf.notary.signingAgent,omitempty"`
+	} `json:"header"`
+	Signature string `json:"signature"`
+}
+
+// jwsProtectedHeader is the protected header of a JWS envelope.
+type jwsProtectedHeader struct {
+	Algorithm     string     `json:"alg"`
+	ContentType   string     `json:"cty"`
+	Critical      []string   `json:"crit,omitempty"`
+	SigningScheme string     `json:"io.cncf.notary.signingScheme,omitempty"`
+	SigningTime   *time.Time `json:"io.cncf.notary.signingTime,omitempty"`
+	Expiry        *time.Time `json:"io.cncf.notary.expiry,omitempty"`
+}
+
+// NotationSigner signs manifests with a private key and its certificate
+// chain, as Notation does with the "notary.x509" signing scheme.
+type NotationSigner struct {
+	key   crypto.Signer
+	certs []*x509.Certificate
+	alg   string
+	hash  crypto.Hash
+}
+
+// NewNotationSigner returns a signer that signs with key, whose certificate
+// is the first of certs, followed by the certificates that issued it.
+func NewNotationSigner(key crypto.Signer, certs []*x509.Certificate) (*NotationSigner, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("no signing certificate")
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(certs[0].PublicKey) {
+		return nil, errors.New("the signing key doesn't match its certificate")
+	}
+	alg, hash, err := signatureAlgorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &NotationSigner{key: key, certs: certs, alg: alg, hash: hash}, nil
+}
+
+// LoadNotationSigner returns a signer for the PEM-encoded private key of
+// keyFile, and the PEM-encoded certificate chain of certFile, which starts
+// with the certificate of the key.
+func LoadNotationSigner(keyFile, certFile string) (*NotationSigner, error) {
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("invalid signing key %s: no PEM-encoded key found", keyFile)
+	}
+	var key any
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid signing key %s", keyFile)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("invalid signing key %s: unsupported key type %T", keyFile, key)
+	}
+
+	b, err = os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(b); block != nil; block, rest = pem.Decode(rest) {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid signing certificate %s", certFile)
+		}
+		certs = append(certs, cert)
+	}
+	s, err := NewNotationSigner(signer, certs)
+	return s, errors.Wrapf(err, "invalid signing key %s", keyFile)
+}
+
+// signatureAlgorithm returns the JWS algorithm that the Notary Project
+// specification requires for a key, and its hash.
+func signatureAlgorithm(key crypto.PublicKey) (string, crypto.Hash, error) {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch k.N.BitLen() {
+		case 2048:
+			return "PS256", crypto.SHA256, nil
+		case 3072:
+			return "PS384", crypto.SHA384, nil
+		case 4096:
+			return "PS512", crypto.SHA512, nil
+		}
+		return "", 0, errors.Errorf("unsupported RSA key size %d", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "ES256", crypto.SHA256, nil
+		case elliptic.P384():
+			return "ES384", crypto.SHA384, nil
+		case elliptic.P521():
+			return "ES512", crypto.SHA512, nil
+		}
+		return "", 0, errors.Errorf("unsupported ECDSA curve %s", k.Curve.Params().Name)
+	}
+	return "", 0, errors.Errorf("unsupported key type %T", key)
+}
+
+// Envelope returns the JWS envelope of the signature of target.
+func (s *NotationSigner) Envelope(target ocispec.Descriptor) ([]byte, error) {
+	payload, err := json.Marshal(NotationPayload{TargetArtifact: ocispec.Descriptor{
+		MediaType: target.MediaType,
+		Digest:    target.Digest,
+		Size:      target.Size,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	protected, err := json.Marshal(jwsProtectedHeader{
+		Algorithm:     s.alg,
+		ContentType:   mediaTypeNotationPayload,
+		Critical:      []string{headerSigningScheme},
+		SigningScheme: signingSchemeX509,
+		SigningTime:   &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope jwsEnvelope
+	envelope.Protected = base64.RawURLEncoding.EncodeToString(protected)
+	envelope.Payload = base64.RawURLEncoding.EncodeToString(payload)
+	h := s.hash.New()
+	h.Write([]byte(envelope.Protected + "." + envelope.Payload))
+	hashed := h.Sum(nil)
+
+	var sig []byte
+	switch k := s.key.Public().(type) {
+	case *rsa.PublicKey:
+		sig, err = s.key.Sign(rand.Reader, hashed, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: s.hash})
+	case *ecdsa.PublicKey:
+		// JWS signatures are the concatenation of the R and S values of
+		// ECDSA signatures, instead of their ASN.1 encoding.
+		var der []byte
+		if der, err = s.key.Sign(rand.Reader, hashed, s.hash); err == nil {
+			var rs struct{ R, S *big.Int }
+			if _, err = asn1.Unmarshal(der, &rs); err == nil {
+				size := (k.Curve.Params().BitSize + 7) / 8
+				sig = make([]byte, 2*size)
+				rs.R.FillBytes(sig[:size])
+				rs.S.FillBytes(sig[size:])
+			}
+		}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign")
+	}
+	envelope.Signature = base64.RawURLEncoding.EncodeToString(sig)
+	for _, cert := range s.certs {
+		envelope.Header.CertChain = append(envelope.Header.CertChain, cert.Raw)
+	}
+	envelope.Header.SigningAgent = "docker/" + version.Version
+	return json.Marshal(envelope)
+}
+
+// rawManifest is a manifest to push as is.
+type rawManifest struct {
+	mediaType string
+	payload   []byte
+}
+
+func (m rawManifest) References() []distribution.Descriptor { return nil }
+
+func (m rawManifest) Payload() (string, []byte, error) { return m.mediaType, m.payload, nil }
+
+// Sign signs the manifest of target in the repository of ref, and pushes the
+// signature to the repository as a referrer of the manifest. Registries that
+// don't support the referrers API list the signature in the image index of
+// the referrers tag ("<algorithm>-<digest>") of the manifest instead.
+func (s *NotationSigner) Sign(ctx context.Context, reg NotationRegistry, ref reference.Named, target ocispec.Descriptor) (ocispec.Descriptor, error) {
+	envelope, err := s.Envelope(target)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	emptyJSON := []byte("{}")
+	config := ocispec.Descriptor{MediaType: mediaTypeEmptyJSON, Digest: digest.FromBytes(emptyJSON), Size: int64(len(emptyJSON))}
+	layer := ocispec.Descriptor{MediaType: MediaTypeJWSEnvelope, Digest: digest.FromBytes(envelope), Size: int64(len(envelope))}
+	if err := reg.PutBlob(ctx, ref, config, bytes.NewReader(emptyJSON)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := reg.PutBlob(ctx, ref, layer, bytes.NewReader(envelope)); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	thumbprints := make([]string, 0, len(s.certs))
+	for _, cert := range s.certs {
+		sum := sha256.Sum256(cert.Raw)
+		thumbprints = append(thumbprints, hex.EncodeToString(sum[:]))
+	}
+	encodedThumbprints, err := json.Marshal(thumbprints)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	subject := ocispec.Descriptor{MediaType: target.MediaType, Digest: target.Digest, Size: target.Size}
+	payload, err := json.Marshal(ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactTypeNotation,
+		Config:       config,
+		Layers:       []ocispec.Descriptor{layer},
+		Subject:      &subject,
+		Annotations:  map[string]string{annotationThumbprints: string(encodedThumbprints)},
+	})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: ArtifactTypeNotation,
+		Digest:       digest.FromBytes(payload),
+		Size:         int64(len(payload)),
+	}
+	named, err := reference.WithDigest(reference.TrimNamed(ref), desc.Digest)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if _, err := reg.PutManifest(ctx, named, rawManifest{mediaType: desc.MediaType, payload: payload}); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	return desc, addReferrer(ctx, reg, ref, target.Digest, desc)
+}
+
+// addReferrer adds desc to the referrers tag of the manifest with the given
+// digest, unless the referrers of the manifest list it already, as they do
+// on registries that support the referrers API.
+func addReferrer(ctx context.Context, reg NotationRegistry, ref reference.Named, dgst digest.Digest, desc ocispec.Descriptor) error {
+	named, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return err
+	}
+	referrers, err := reg.GetReferrers(ctx, named)
+	if err != nil {
+		return err
+	}
+	for _, r := range referrers {
+		if r.Digest == desc.Digest {
+			return nil
+		}
+	}
+
+	tagged, err := reference.WithTag(reference.TrimNamed(ref), dgst.Algorithm().String()+"-"+dgst.Encoded())
+	if err != nil {
+		return err
+	}
+	index := ocispec.Index{Versioned: specs.Versioned{SchemaVersion: 2}, MediaType: ocispec.MediaTypeImageIndex}
+	if _, payload, err := reg.GetRawManifest(ctx, tagged); err == nil {
+		if err := json.Unmarshal(payload, &index); err != nil {
+			return errors.Wrap(err, "invalid referrers index")
+		}
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	}
+	index.Manifests = append(index.Manifests, desc)
+	payload, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	_, err = reg.PutManifest(ctx, tagged, rawManifest{mediaType: ocispec.MediaTypeImageIndex, payload: payload})
+	return err
+}
+
+// NotationEnvelope is the JWS envelope of a Notation signature.
+type NotationEnvelope struct {
+	// Payload is the signed payload.
+	Payload NotationPayload
+	// Certificates is the certificate chain of the signature, starting with
+	// the certificate of its key.
+	Certificates []*x509.Certificate
+	// SigningTime is the time that the signature claims it was made at.
+	SigningTime time.Time
+	// Expiry is the time after which the signature is no longer valid, if
+	// set.
+	Expiry time.Time
+
+	hash         crypto.Hash
+	signingInput []byte
+	signature    []byte
+}
+
+// ParseNotationEnvelope parses the JWS envelope of a Notation signature. The
+// signature isn't verified: see [NotationEnvelope.Verify].
+func ParseNotationEnvelope(b []byte) (*NotationEnvelope, error) {
+	var envelope jwsEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, errors.Wrap(err, "invalid signature envelope")
+	}
+	protected, err := base64.RawURLEncoding.DecodeString(envelope.Protected)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature envelope")
+	}
+	var header jwsProtectedHeader
+	if err := json.Unmarshal(protected, &header); err != nil {
+		return nil, errors.Wrap(err, "invalid signature envelope")
+	}
+	for _, c := range header.Critical {
+		if c != headerSigningScheme && c != headerSigningTime && c != headerExpiry {
+			return nil, errors.Errorf("unsupported critical header %q", c)
+		}
+	}
+	if header.SigningScheme != "" && header.SigningScheme != signingSchemeX509 {
+		return nil, errors.Errorf("unsupported signing scheme %q", header.SigningScheme)
+	}
+
+	e := &NotationEnvelope{signingInput: []byte(envelope.Protected + "." + envelope.Payload)}
+	switch header.Algorithm {
+	case "PS256", "ES256":
+		e.hash = crypto.SHA256
+	case "PS384", "ES384":
+		e.hash = crypto.SHA384
+	case "PS512", "ES512":
+		e.hash = crypto.SHA512
+	default:
+		return nil, errors.Errorf("unsupported signature algorithm %q", header.Algorithm)
+	}
+	if header.SigningTime != nil {
+		e.SigningTime = *header.SigningTime
+	}
+	if header.Expiry != nil {
+		e.Expiry = *header.Expiry
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature envelope")
+	}
+	if err := json.Unmarshal(payload, &e.Payload); err != nil {
+		return nil, errors.Wrap(err, "invalid signature payload")
+	}
+	if e.signature, err = base64.RawURLEncoding.DecodeString(envelope.Signature); err != nil {
+		return nil, errors.Wrap(err, "invalid signature envelope")
+	}
+	if len(envelope.Header.CertChain) == 0 {
+		return nil, errors.New("signature has no certificate")
+	}
+	for _, c := range envelope.Header.CertChain {
+		cert, err := x509.ParseCertificate(c)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid certificate")
+		}
+		e.Certificates = append(e.Certificates, cert)
+	}
+	return e, nil
+}
+
+// Verify verifies the signature of the envelope with the key of its
+// certificate. The certificate itself isn't verified.
+func (e *NotationEnvelope) Verify() error {
+	h := e.hash.New()
+	h.Write(e.signingInput)
+	hashed := h.Sum(nil)
+
+	switch k := e.Certificates[0].PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if len(e.signature)%2 == 0 {
+			r, s := new(big.Int).SetBytes(e.signature[:len(e.signature)/2]), new(big.Int).SetBytes(e.signature[len(e.signature)/2:])
+			if ecdsa.Verify(k, hashed, r, s) {
+				return nil
+			}
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPSS(k, e.hash, hashed, e.signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil {
+			return nil
+		}
+	default:
+		return errors.Errorf("unsupported key type %T", k)
+	}
+	return errors.New("invalid signature")
+}
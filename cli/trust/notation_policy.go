@@ -0,0 +1,337 @@
+package trust
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// Verification levels of Notation trust policies.
+const (
+	// VerificationStrict requires a valid signature.
+	VerificationStrict = "strict"
+	// VerificationPermissive requires a valid signature, but accepts
+	// signatures that expired.
+	VerificationPermissive = "permissive"
+	// VerificationAudit only reports the signatures that aren't valid.
+	VerificationAudit = "audit"
+	// VerificationSkip doesn't verify signatures.
+	VerificationSkip = "skip"
+)
+
+// NotationTrustPolicyFile returns the path of the default Notation trust
+// policy, which is used unless the "signing" configuration of the CLI sets
+// another one.
+func NotationTrustPolicyFile() string {
+	return filepath.Join(GetTrustDirectory(), "notation", "trustpolicy.json")
+}
+
+// NotationTrustPolicy is a Notation trust policy document. The trust stores
+// of the policy are the directories of certificates in the "truststore/x509"
+// directory next to the policy file, as in the configuration directory of
+// Notation: "truststore/x509/ca/NAME" for the trust store "ca:NAME".
+type NotationTrustPolicy struct {
+	Version       string                   `json:"version"`
+	TrustPolicies []NotationTrustStatement `json:"trustPolicies"`
+
+	dir string
+}
+
+// NotationTrustStatement is one of the trust policies of a Notation trust
+// policy document, which applies to the repositories of its registry scopes.
+type NotationTrustStatement struct {
+	Name                  string   `json:"name"`
+	RegistryScopes        []string `json:"registryScopes"`
+	SignatureVerification struct {
+		Level string `json:"level"`
+	} `json:"signatureVerification"`
+	TrustStores       []string `json:"trustStores,omitempty"`
+	TrustedIdentities []string `json:"trustedIdentities,omitempty"`
+}
+
+// NotationVerification is the result of the verification of the Notation
+// signatures of a manifest.
+type NotationVerification struct {
+	// Policy is the name of the trust policy that applies to the manifest.
+	Policy string
+	// Level is the verification level of the policy.
+	Level string
+	// Signer is the subject of the certificate of the valid signature, or
+	// its email address if the subject is empty.
+	Signer string
+	// Failure is why no signature is valid, if the level of the policy
+	// allows it.
+	Failure string
+}
+
+// LoadNotationTrustPolicy loads and validates a Notation trust policy.
+func LoadNotationTrustPolicy(filename string) (*NotationTrustPolicy, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Errorf("no trust policy found: create %s, or set the trust policy in the \"signing\" configuration of the CLI", filename)
+		}
+		return nil, err
+	}
+	policy := &NotationTrustPolicy{dir: filepath.Dir(filename)}
+	if err := json.Unmarshal(b, policy); err != nil {
+		return nil, errors.Wrapf(err, "invalid trust policy %s", filename)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid trust policy %s", filename)
+	}
+	return policy, nil
+}
+
+func (p *NotationTrustPolicy) validate() error {
+	if p.Version != "1.0" {
+		return errors.Errorf("unsupported version %q", p.Version)
+	}
+	names := map[string]bool{}
+	scopes := map[string]bool{}
+	for _, s := range p.TrustPolicies {
+		if s.Name == "" {
+			return errors.New("trust policy without a name")
+		}
+		if names[s.Name] {
+			return errors.Errorf("duplicate trust policy %q", s.Name)
+		}
+		names[s.Name] = true
+		if len(s.RegistryScopes) == 0 {
+			return errors.Errorf("trust policy %q has no registry scope", s.Name)
+		}
+		for _, scope := range s.RegistryScopes {
+			if scopes[scope] {
+				return errors.Errorf("registry scope %q is in more than one trust policy", scope)
+			}
+			scopes[scope] = true
+		}
+		switch s.SignatureVerification.Level {
+		case VerificationSkip:
+			continue
+		case VerificationStrict, VerificationPermissive, VerificationAudit:
+		default:
+			return errors.Errorf("trust policy %q has an invalid verification level %q", s.Name, s.SignatureVerification.Level)
+		}
+		if len(s.TrustStores) == 0 || len(s.TrustedIdentities) == 0 {
+			return errors.Errorf("trust policy %q must have trust stores and trusted identities", s.Name)
+		}
+		for _, id := range s.TrustedIdentities {
+			if dn, ok := strings.CutPrefix(id, "x509.subject:"); id != "*" && (!ok || len(parseDN(dn)) == 0) {
+				return errors.Errorf("trust policy %q has an invalid trusted identity %q", s.Name, id)
+			}
+		}
+	}
+	return nil
+}
+
+// statement returns the trust policy whose registry scopes include the
+// repository, or the wildcard policy ("*").
+func (p *NotationTrustPolicy) statement(repository string) (NotationTrustStatement, bool) {
+	var wildcard *NotationTrustStatement
+	for i, s := range p.TrustPolicies {
+		for _, scope := range s.RegistryScopes {
+			if scope == repository {
+				return s, true
+			}
+			if scope == "*" {
+				wildcard = &p.TrustPolicies[i]
+			}
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return NotationTrustStatement{}, false
+}
+
+// roots loads the certificates of the trust stores of s.
+func (p *NotationTrustPolicy) roots(s NotationTrustStatement) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, store := range s.TrustStores {
+		storeType, name, ok := strings.Cut(store, ":")
+		if !ok || (storeType != "ca" && storeType != "signingAuthority") {
+			return nil, errors.Errorf("invalid trust store %q of trust policy %q", store, s.Name)
+		}
+		dir := filepath.Join(p.dir, "truststore", "x509", storeType, name)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load trust store %q", store)
+		}
+		for _, entry := range entries {
+			b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			// Notation trust stores have PEM- or DER-encoded certificates.
+			if pool.AppendCertsFromPEM(b) {
+				continue
+			}
+			if cert, err := x509.ParseCertificate(b); err == nil {
+				pool.AddCert(cert)
+			}
+		}
+	}
+	return pool, nil
+}
+
+// Verify verifies the Notation signatures of the manifest with the given
+// digest in the repository of ref, against the trust policy that applies to
+// the repository. It returns an error if no signature is valid, unless the
+// policy only audits signatures.
+func (p *NotationTrustPolicy) Verify(ctx context.Context, reg NotationRegistry, ref reference.Named, dgst digest.Digest) (NotationVerification, error) {
+	s, ok := p.statement(ref.Name())
+	if !ok {
+		return NotationVerification{}, errors.Errorf("no trust policy applies to %s", ref.Name())
+	}
+	v := NotationVerification{Policy: s.Name, Level: s.SignatureVerification.Level}
+	if v.Level == VerificationSkip {
+		return v, nil
+	}
+	roots, err := p.roots(s)
+	if err != nil {
+		return v, err
+	}
+
+	named, err := reference.WithDigest(reference.TrimNamed(ref), dgst)
+	if err != nil {
+		return v, err
+	}
+	referrers, err := reg.GetReferrers(ctx, named)
+	if err != nil {
+		return v, err
+	}
+	failure := "no signature found"
+	for _, r := range referrers {
+		if r.ArtifactType != ArtifactTypeNotation {
+			continue
+		}
+		signer, err := p.verifySignature(ctx, reg, ref, r.Digest, dgst, s, roots)
+		if err == nil {
+			v.Signer = signer
+			return v, nil
+		}
+		failure = err.Error()
+	}
+	if v.Level == VerificationAudit {
+		v.Failure = failure
+		return v, nil
+	}
+	return v, errors.Errorf("signature verification failed for %s: %s", reference.FamiliarString(ref), failure)
+}
+
+// verifySignature verifies the Notation signature with the digest sigDigest
+// of the manifest with the digest dgst. It returns the subject of the
+// certificate of the signature.
+func (p *NotationTrustPolicy) verifySignature(ctx context.Context, reg NotationRegistry, ref reference.Named, sigDigest, dgst digest.Digest, s NotationTrustStatement, roots *x509.CertPool) (string, error) {
+	named, err := reference.WithDigest(reference.TrimNamed(ref), sigDigest)
+	if err != nil {
+		return "", err
+	}
+	desc, payload, err := reg.GetRawManifest(ctx, named)
+	if err != nil {
+		return "", err
+	}
+	if desc.Digest != sigDigest {
+		return "", errors.Errorf("content of manifest %s doesn't match its digest", sigDigest)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(payload, &manifest); err != nil {
+		return "", errors.Wrap(err, "invalid signature manifest")
+	}
+	if len(manifest.Layers) != 1 || manifest.Layers[0].MediaType != MediaTypeJWSEnvelope {
+		return "", errors.New("invalid signature manifest")
+	}
+	layer, err := reference.WithDigest(reference.TrimNamed(ref), manifest.Layers[0].Digest)
+	if err != nil {
+		return "", err
+	}
+	b, err := reg.GetBlob(ctx, layer)
+	if err != nil {
+		return "", err
+	}
+	if layer.Digest().Algorithm().FromBytes(b) != layer.Digest() {
+		return "", errors.Errorf("content of blob %s doesn't match its digest", layer.Digest())
+	}
+
+	envelope, err := ParseNotationEnvelope(b)
+	if err != nil {
+		return "", err
+	}
+	if envelope.Payload.TargetArtifact.Digest != dgst {
+		return "", errors.Errorf("signature is for %s, not for %s", envelope.Payload.TargetArtifact.Digest, dgst)
+	}
+	if err := envelope.Verify(); err != nil {
+		return "", err
+	}
+	cert := envelope.Certificates[0]
+	intermediates := x509.NewCertPool()
+	for _, c := range envelope.Certificates[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return "", errors.Wrap(err, "invalid certificate")
+	}
+	subject := cert.Subject.String()
+	if !trustedIdentity(s.TrustedIdentities, cert) {
+		return "", errors.Errorf("certificate subject %q is not a trusted identity", subject)
+	}
+	if !envelope.Expiry.IsZero() && time.Now().After(envelope.Expiry) && s.SignatureVerification.Level == VerificationStrict {
+		return "", errors.Errorf("signature expired on %s", envelope.Expiry.Format(time.RFC3339))
+	}
+	if subject == "" && len(cert.EmailAddresses) > 0 {
+		return cert.EmailAddresses[0], nil
+	}
+	return subject, nil
+}
+
+// trustedIdentity returns whether the subject of cert has all the attributes
+// of one of the "x509.subject" identities, or if any identity is trusted.
+func trustedIdentity(identities []string, cert *x509.Certificate) bool {
+	subject := parseDN(cert.Subject.String())
+	for _, id := range identities {
+		if id == "*" {
+			return true
+		}
+		dn, ok := strings.CutPrefix(id, "x509.subject:")
+		if !ok {
+			continue
+		}
+		matches := true
+		for k, v := range parseDN(dn) {
+			if subject[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDN parses the attributes of a distinguished name, such as
+// "C=US, ST=WA, O=example.com".
+func parseDN(dn string) map[string]string {
+	attrs := map[string]string{}
+	for _, attr := range strings.Split(dn, ",") {
+		if k, v, ok := strings.Cut(attr, "="); ok {
+			attrs[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,219 @@
+package trust
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/internal/test/registry"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+// addImage adds a manifest with the given tag to r.
+func addImage(t *testing.T, r *registry.Registry, ref reference.NamedTagged) ocispec.Descriptor {
+	t.Helper()
+	return r.AddManifest(t, ref.Name(), ref.Tag(), ocispec.MediaTypeImageManifest, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+	})
+}
+
+// newTestSigner returns a signer with a code signing certificate that has
+// the given subject, issued by a CA, and the PEM-encoded certificate of the
+// CA.
+func newTestSigner(t *testing.T, subject pkix.Name) (*NotationSigner, []byte) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	assert.NilError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	assert.NilError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	certDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}, ca, key.Public(), caKey)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(certDER)
+	assert.NilError(t, err)
+
+	signer, err := NewNotationSigner(key, []*x509.Certificate{cert, ca})
+	assert.NilError(t, err)
+	return signer, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+}
+
+// writeTrustPolicy writes a trust policy, and its trust store "ca:test" with
+// the given certificate, to a directory.
+func writeTrustPolicy(t *testing.T, policy string, ca []byte) string {
+	t.Helper()
+	dir := t.TempDir()
+	store := filepath.Join(dir, "truststore", "x509", "ca", "test")
+	assert.NilError(t, os.MkdirAll(store, 0o700))
+	assert.NilError(t, os.WriteFile(filepath.Join(store, "ca.pem"), ca, 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "trustpolicy.json"), []byte(policy), 0o600))
+	return filepath.Join(dir, "trustpolicy.json")
+}
+
+func testPolicy(level, identity string) string {
+	return `{
+	"version": "1.0",
+	"trustPolicies": [{
+		"name": "example",
+		"registryScopes": ["docker.io/library/example"],
+		"signatureVerification": {"level": "` + level + `"},
+		"trustStores": ["ca:test"],
+		"trustedIdentities": ["` + identity + `"]
+	}]
+}`
+}
+
+func TestNotationSignAndVerify(t *testing.T) {
+	subject := pkix.Name{Country: []string{"US"}, Organization: []string{"example.com"}, CommonName: "signer"}
+	for _, referrersAPI := range []bool{true, false} {
+		r := registry.New()
+		r.NoReferrersAPI = !referrersAPI
+		ref, err := reference.WithTag(reference.TrimNamed(mustParse(t, "example")), "latest")
+		assert.NilError(t, err)
+		desc := addImage(t, r, ref)
+
+		signer, ca := newTestSigner(t, subject)
+		sig, err := signer.Sign(context.Background(), r, ref, desc)
+		assert.NilError(t, err)
+
+		named, err := reference.WithDigest(reference.TrimNamed(ref), desc.Digest)
+		assert.NilError(t, err)
+		referrers, err := r.GetReferrers(context.Background(), named)
+		assert.NilError(t, err)
+		assert.Assert(t, is.Len(referrers, 1))
+		assert.Check(t, is.Equal(referrers[0].Digest, sig.Digest))
+		assert.Check(t, is.Equal(referrers[0].ArtifactType, ArtifactTypeNotation))
+
+		policy, err := LoadNotationTrustPolicy(writeTrustPolicy(t, testPolicy(VerificationStrict, "x509.subject: C=US, O=example.com"), ca))
+		assert.NilError(t, err)
+		v, err := policy.Verify(context.Background(), r, ref, desc.Digest)
+		assert.NilError(t, err)
+		assert.Check(t, is.DeepEqual(v, NotationVerification{Policy: "example", Level: VerificationStrict, Signer: "CN=signer,O=example.com,C=US"}))
+
+		// Signatures of other identities, or that don't chain to the trust
+		// store, aren't valid.
+		policy, err = LoadNotationTrustPolicy(writeTrustPolicy(t, testPolicy(VerificationStrict, "x509.subject: C=US, O=other.example.com"), ca))
+		assert.NilError(t, err)
+		_, err = policy.Verify(context.Background(), r, ref, desc.Digest)
+		assert.Check(t, is.Error(err, `signature verification failed for example:latest: certificate subject "CN=signer,O=example.com,C=US" is not a trusted identity`))
+
+		_, otherCA := newTestSigner(t, subject)
+		policy, err = LoadNotationTrustPolicy(writeTrustPolicy(t, testPolicy(VerificationStrict, "*"), otherCA))
+		assert.NilError(t, err)
+		_, err = policy.Verify(context.Background(), r, ref, desc.Digest)
+		assert.Check(t, is.ErrorContains(err, "invalid certificate: x509: certificate signed by unknown authority"))
+	}
+}
+
+func TestNotationVerifyLevels(t *testing.T) {
+	r := registry.New()
+	ref, err := reference.WithTag(reference.TrimNamed(mustParse(t, "example")), "latest")
+	assert.NilError(t, err)
+	desc := addImage(t, r, ref)
+	_, ca := newTestSigner(t, pkix.Name{CommonName: "signer"})
+
+	policy, err := LoadNotationTrustPolicy(writeTrustPolicy(t, testPolicy(VerificationStrict, "*"), ca))
+	assert.NilError(t, err)
+	_, err = policy.Verify(context.Background(), r, ref, desc.Digest)
+	assert.Check(t, is.Error(err, "signature verification failed for example:latest: no signature found"))
+
+	policy, err = LoadNotationTrustPolicy(writeTrustPolicy(t, testPolicy(VerificationAudit, "*"), ca))
+	assert.NilError(t, err)
+	v, err := policy.Verify(context.Background(), r, ref, desc.Digest)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v.Failure, "no signature found"))
+
+	policy, err = LoadNotationTrustPolicy(writeTrustPolicy(t, `{"version":"1.0","trustPolicies":[{"name":"all","registryScopes":["*"],"signatureVerification":{"level":"skip"}}]}`, ca))
+	assert.NilError(t, err)
+	v, err = policy.Verify(context.Background(), r, ref, desc.Digest)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(v, NotationVerification{Policy: "all", Level: VerificationSkip}))
+
+	other, err := reference.WithTag(reference.TrimNamed(mustParse(t, "other")), "latest")
+	assert.NilError(t, err)
+	policy, err = LoadNotationTrustPolicy(writeTrustPolicy(t, testPolicy(VerificationStrict, "*"), ca))
+	assert.NilError(t, err)
+	_, err = policy.Verify(context.Background(), r, other, desc.Digest)
+	assert.Check(t, is.Error(err, "no trust policy applies to docker.io/library/other"))
+}
+
+func TestLoadNotationTrustPolicyInvalid(t *testing.T) {
+	testCases := []struct {
+		policy      string
+		expectedErr string
+	}{
+		{policy: `{"version":"2.0"}`, expectedErr: `unsupported version "2.0"`},
+		{policy: strings.Replace(testPolicy(VerificationStrict, "*"), "strict", "lenient", 1), expectedErr: `trust policy "example" has an invalid verification level "lenient"`},
+		{policy: testPolicy(VerificationStrict, "CN=signer"), expectedErr: `trust policy "example" has an invalid trusted identity "CN=signer"`},
+		{policy: `{"version":"1.0","trustPolicies":[{"name":"a","registryScopes":["*"],"signatureVerification":{"level":"strict"}}]}`, expectedErr: `trust policy "a" must have trust stores and trusted identities`},
+		{policy: `{"version":"1.0","trustPolicies":[{"name":"a","registryScopes":["*"],"signatureVerification":{"level":"skip"}},{"name":"b","registryScopes":["*"],"signatureVerification":{"level":"skip"}}]}`, expectedErr: `registry scope "*" is in more than one trust policy`},
+	}
+	for _, tc := range testCases {
+		_, err := LoadNotationTrustPolicy(writeTrustPolicy(t, tc.policy, nil))
+		assert.Check(t, is.ErrorContains(err, tc.expectedErr))
+	}
+
+	_, err := LoadNotationTrustPolicy(filepath.Join(t.TempDir(), "trustpolicy.json"))
+	assert.Check(t, is.ErrorContains(err, "no trust policy found"))
+}
+
+func TestParseNotationEnvelope(t *testing.T) {
+	signer, _ := newTestSigner(t, pkix.Name{CommonName: "signer"})
+	dgst := digest.FromString("manifest")
+	b, err := signer.Envelope(ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: dgst, Size: 8})
+	assert.NilError(t, err)
+
+	envelope, err := ParseNotationEnvelope(b)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(envelope.Payload.TargetArtifact.Digest, dgst))
+	assert.Check(t, is.Len(envelope.Certificates, 2))
+	assert.Check(t, time.Since(envelope.SigningTime) < time.Minute)
+	assert.Check(t, envelope.Verify())
+
+	// Tampering with the payload invalidates the signature.
+	envelope.signingInput = bytes.Replace(envelope.signingInput, []byte("."), []byte(".e30"), 1)
+	assert.Check(t, is.Error(envelope.Verify(), "invalid signature"))
+}
+
+func mustParse(t *testing.T, s string) reference.Named {
+	t.Helper()
+	ref, err := reference.ParseNormalizedNamed(s)
+	assert.NilError(t, err)
+	return ref
+}
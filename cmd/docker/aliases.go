@@ -1,11 +1,12 @@
 package main
 
 import (
-	"os"
+	"fmt"
 	"strings"
 
 	pluginmanager "github.com/docker/cli/cli-plugins/manager"
 	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/command/alias"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -24,9 +25,26 @@ func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []st
 	aliasMap := dockerCli.ConfigFile().Aliases
 	aliases := make([][2][]string, 0, len(aliasMap))
 
+	for _, err := range alias.Validate(aliasMap) {
+		_, _ = fmt.Fprintf(dockerCli.Err(), "WARNING: %s\n", err)
+	}
+
+	// Aliases of commands defined by the user are expanded first, so that
+	// they can be aliases of "docker build".
+	expanded, err := alias.Expand(dockerCli, cmd, aliasMap, args)
+	if err != nil {
+		return args, osArgs, envs, err
+	}
+	// args are the arguments of osArgs after the global options.
+	if n := len(osArgs) - len(args); n >= 0 {
+		osArgs = append(osArgs[:n:n], expanded...)
+	}
+	args = expanded
+
 	for k, v := range aliasMap {
 		if _, ok := allowedAliases[k]; !ok {
-			return args, osArgs, envs, errors.Errorf("not allowed to alias %q (allowed: %#v)", k, allowedAliases)
+			// An alias of a command, expanded above.
+			continue
 		}
 		if c, _, err := cmd.Find(strings.Split(v, " ")); err == nil {
 			if !pluginmanager.IsPluginCommand(c) {
@@ -36,9 +54,9 @@ func processAliases(dockerCli command.Cli, cmd *cobra.Command, args, osArgs []st
 		aliases = append(aliases, [2][]string{{k}, {v}})
 	}
 
-	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, os.Args)
+	args, osArgs, envs, err = processBuilder(dockerCli, cmd, args, osArgs)
 	if err != nil {
-		return args, os.Args, envs, err
+		return args, osArgs, envs, err
 	}
 
 	for _, al := range aliases {
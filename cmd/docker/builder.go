@@ -129,7 +129,24 @@ func processBuilder(dockerCli command.Cli, cmd *cobra.Command, args, osargs []st
 	// overwrite the command path for this plugin using the alias name.
 	cmd.Annotations[pluginmanager.CommandAnnotationPluginCommandPath] = strings.Join(append([]string{cmd.CommandPath()}, fwcmdpath...), " ")
 
-	return fwargs, fwosargs, envs, nil
+	return forwardProgress(fwargs), forwardProgress(fwosargs), envs, nil
+}
+
+// forwardProgress replaces the "json" type of progress output of the
+// --progress option in args with the "rawjson" type of the builder, which
+// writes the progress of the build as JSON.
+func forwardProgress(args []string) []string {
+	var out []string
+	for i, arg := range args {
+		switch {
+		case arg == "--progress=json":
+			arg = "--progress=rawjson"
+		case arg == "json" && i > 0 && args[i-1] == "--progress":
+			arg = "rawjson"
+		}
+		out = append(out, arg)
+	}
+	return out
 }
 
 func forwardBuilder(alias string, args, osargs []string) ([]string, []string, []string, bool) {
@@ -150,6 +167,11 @@ func forwardBuilder(alias string, args, osargs []string) ([]string, []string, []
 			{"image"},
 		},
 	}
+	// "docker builder context" inspects the build context that the CLI sends
+	// to the legacy builder, and isn't a command of the builder component.
+	if len(args) > 1 && args[0] == "builder" && args[1] == "context" {
+		return args, osargs, nil, false
+	}
 	for _, al := range aliases {
 		if fwargs, changed := command.StringSliceReplaceAt(args, al[0], al[1], 0); changed {
 			fwosargs, _ := command.StringSliceReplaceAt(osargs, al[0], al[1], -1)
@@ -251,6 +251,18 @@ func TestBuilderBrokenEnforced(t *testing.T) {
 	})
 }
 
+func TestForwardBuilderContext(t *testing.T) {
+	args, osargs, _, forwarded := forwardBuilder(builderDefaultPlugin, []string{"builder", "prune"}, []string{"docker", "builder", "prune"})
+	assert.Check(t, forwarded)
+	assert.DeepEqual(t, []string{builderDefaultPlugin, "prune"}, args)
+	assert.DeepEqual(t, []string{"docker", builderDefaultPlugin, "prune"}, osargs)
+
+	args, osargs, _, forwarded = forwardBuilder(builderDefaultPlugin, []string{"builder", "context", "ls"}, []string{"docker", "builder", "context", "ls"})
+	assert.Check(t, !forwarded)
+	assert.DeepEqual(t, []string{"builder", "context", "ls"}, args)
+	assert.DeepEqual(t, []string{"docker", "builder", "context", "ls"}, osargs)
+}
+
 func TestHasBuilderName(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -296,3 +308,38 @@ func TestHasBuilderName(t *testing.T) {
 		})
 	}
 }
+
+func TestForwardProgress(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "no progress",
+			args:     []string{"buildx", "build", "."},
+			expected: []string{"buildx", "build", "."},
+		},
+		{
+			name:     "plain",
+			args:     []string{"buildx", "build", "--progress=plain", "."},
+			expected: []string{"buildx", "build", "--progress=plain", "."},
+		},
+		{
+			name:     "json",
+			args:     []string{"buildx", "build", "--progress=json", "."},
+			expected: []string{"buildx", "build", "--progress=rawjson", "."},
+		},
+		{
+			name:     "separate value",
+			args:     []string{"buildx", "build", "--progress", "json", "json"},
+			expected: []string{"buildx", "build", "--progress", "rawjson", "json"},
+		},
+	}
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.expected, forwardProgress(tt.args))
+		})
+	}
+}
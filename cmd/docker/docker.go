@@ -84,9 +84,12 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 			if len(args) == 0 {
 				return command.ShowHelp(dockerCli.Err())(cmd, args)
 			}
-			return fmt.Errorf("docker: '%s' is not a docker command.\nSee 'docker --help'", args[0])
+			return unknownCommandError(dockerCli, cmd, args[0])
 		},
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := command.ResolveFormatPreset(cmd.Flags(), dockerCli.ConfigFile()); err != nil {
+				return err
+			}
 			return isSupported(cmd, dockerCli)
 		},
 		Version:               fmt.Sprintf("%s, build %s", version.Version, version.GitCommit),
@@ -119,6 +122,74 @@ func newDockerCommand(dockerCli *command.DockerCli) *cli.TopLevelCommand {
 	return cli.NewTopLevelCommand(cmd, dockerCli, opts, cmd.Flags())
 }
 
+// unknownCommandError returns the error for the unknown command name,
+// suggesting the commands and CLI plugins that name is likely a misspelling
+// of.
+func unknownCommandError(dockerCli command.Cli, cmd *cobra.Command, name string) error {
+	_ = pluginmanager.AddPluginCommandStubs(dockerCli, cmd)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "docker: '%s' is not a docker command.\n", name)
+	if suggestions := cli.SuggestCommands(cmd, name); len(suggestions) > 0 {
+		sb.WriteString("\nDid you mean this?\n")
+		for _, s := range suggestions {
+			sb.WriteString("\tdocker " + s + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("See 'docker --help'")
+	return errors.New(sb.String())
+}
+
+// runSuggestion asks whether to run the command that is suggested for the
+// unknown command in args, if there's a single suggestion, and returns args
+// and osArgs with the unknown command replaced if confirmed. It's only used on
+// a terminal, and if the "runSuggestions" property of the configuration file
+// is set.
+func runSuggestion(ctx context.Context, dockerCli command.Cli, cmd *cobra.Command, args, osArgs []string) ([]string, []string) {
+	if len(args) == 0 || !dockerCli.ConfigFile().RunSuggestions || cli.HasCompletionArg(args) {
+		return args, osArgs
+	}
+	if !dockerCli.In().IsTerminal() || !dockerCli.Out().IsTerminal() {
+		return args, osArgs
+	}
+
+	parent, idx := cmd, 0
+	if c, rest, err := cmd.Find(args); err == nil {
+		// An unknown subcommand of a management command, such as
+		// "docker image lsx".
+		if !c.HasSubCommands() || len(rest) == 0 || len(rest) > len(args) || args[len(args)-len(rest)] != rest[0] {
+			return args, osArgs
+		}
+		parent, idx = c, len(args)-len(rest)
+	} else {
+		if _, err := pluginmanager.GetPlugin(args[0], dockerCli, cmd); !pluginmanager.IsNotFound(err) {
+			return args, osArgs
+		}
+		_ = pluginmanager.AddPluginCommandStubs(dockerCli, cmd)
+	}
+	suggestions := cli.SuggestCommands(parent, args[idx])
+	if len(suggestions) != 1 {
+		return args, osArgs
+	}
+
+	// The path of the management command, without "docker".
+	path := strings.TrimPrefix(parent.CommandPath(), cmd.Name())
+	name := strings.TrimSpace(path + " " + args[idx])
+	suggestion := strings.TrimSpace(path + " " + suggestions[0])
+	msg := fmt.Sprintf("docker: '%s' is not a docker command. Run 'docker %s' instead?", name, suggestion)
+	if ok, err := command.PromptForConfirmation(ctx, dockerCli.In(), dockerCli.Out(), msg); err != nil || !ok {
+		return args, osArgs
+	}
+
+	// args are the arguments of osArgs after the global options.
+	n := len(osArgs) - len(args)
+	args = append(append(append([]string{}, args[:idx]...), suggestions[0]), args[idx+1:]...)
+	if n >= 0 {
+		osArgs = append(osArgs[:n:n], args...)
+	}
+	return args, osArgs
+}
+
 func setFlagErrorFunc(dockerCli command.Cli, cmd *cobra.Command) {
 	// When invoking `docker stack --nonsense`, we need to make sure FlagErrorFunc return appropriate
 	// output if the feature is not supported.
@@ -204,6 +275,7 @@ func setHelpFunc(dockerCli command.Cli, cmd *cobra.Command) {
 			return
 		}
 
+		defer dockerCli.Out().StartPager()()
 		defaultHelpFunc(ccmd, args)
 	})
 }
@@ -252,6 +324,19 @@ func tryPluginRun(ctx context.Context, dockerCli command.Cli, cmd *cobra.Command
 		_ = srv.Close()
 	}()
 
+	// Broker the credentials of the registries that the plugin was
+	// granted, if it was restricted to some.
+	broker, err := pluginmanager.NewCredentialsBroker(dockerCli.ConfigFile(), subcommand)
+	if err != nil {
+		return errors.Wrap(err, "failed to broker the credentials of the plugin")
+	}
+	if broker != nil {
+		plugincmd.Env = append(plugincmd.Env, broker.Env()...)
+		defer func() {
+			_ = broker.Close()
+		}()
+	}
+
 	// Set additional environment variables specified by the caller.
 	plugincmd.Env = append(plugincmd.Env, envs...)
 
@@ -372,6 +457,7 @@ func runDocker(ctx context.Context, dockerCli *command.DockerCli) error {
 	if err != nil {
 		return err
 	}
+	args, os.Args = runSuggestion(ctx, dockerCli, cmd, args, os.Args)
 
 	if cli.HasCompletionArg(args) {
 		// We add plugin command stubs early only for completion. We don't
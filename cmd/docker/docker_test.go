@@ -75,3 +75,8 @@ func TestVersion(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Check(t, is.Contains(b.String(), "Docker version"))
 }
+
+func TestUnknownCommandSuggestions(t *testing.T) {
+	err := runCliCommand(t, nil, nil, "contianer")
+	assert.Check(t, is.Error(err, "docker: 'contianer' is not a docker command.\n\nDid you mean this?\n\tdocker container\n\nSee 'docker --help'"))
+}
@@ -0,0 +1,11 @@
+// Package diskspace returns the disk space of the filesystems of the host
+// the CLI runs on.
+package diskspace
+
+// Usage is the disk space of a filesystem.
+type Usage struct {
+	// Free is the disk space that is available to unprivileged users.
+	Free uint64
+	// Total is the size of the filesystem.
+	Total uint64
+}
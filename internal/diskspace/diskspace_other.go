@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !freebsd
+
+package diskspace
+
+import "github.com/pkg/errors"
+
+// Get returns the disk space of the filesystem that contains path.
+func Get(string) (Usage, error) {
+	return Usage{}, errors.New("not supported on this platform")
+}
@@ -0,0 +1,17 @@
+//go:build linux || darwin || freebsd
+
+package diskspace
+
+import "golang.org/x/sys/unix"
+
+// Get returns the disk space of the filesystem that contains path.
+func Get(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	return Usage{
+		Free:  uint64(st.Bavail) * uint64(st.Bsize),
+		Total: uint64(st.Blocks) * uint64(st.Bsize),
+	}, nil
+}
@@ -0,0 +1,67 @@
+// Package helpercontainer creates the helper containers that commands use to
+// access files on the daemon's host, such as the content of volumes. Helper
+// containers are never started; they're only used as a mount-point for the
+// archive endpoints.
+package helpercontainer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/pkg/errors"
+)
+
+// Create creates a helper container with the given label and mounts of
+// hostConfig on the daemon of apiClient, pulling the helper image if it's not
+// present, and returns the ID of the container.
+func Create(ctx context.Context, dockerCli command.Cli, apiClient client.APIClient, helperImage, label string, hostConfig *container.HostConfig) (string, error) {
+	config := &container.Config{
+		Image:           helperImage,
+		Cmd:             []string{"true"},
+		NetworkDisabled: true,
+		Labels:          map[string]string{label: "true"},
+	}
+
+	resp, err := apiClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	if errdefs.IsNotFound(err) {
+		dockerCli.Err().Infof("Unable to find image '%s' locally\n", helperImage)
+		if err := pullImage(ctx, dockerCli, apiClient, helperImage); err != nil {
+			return "", err
+		}
+		resp, err = apiClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create helper container")
+	}
+	return resp.ID, nil
+}
+
+// Remove removes a helper container. Removal is attempted even if ctx was
+// cancelled, to prevent leaving stale containers behind.
+func Remove(ctx context.Context, dockerCli command.Cli, apiClient client.APIClient, id string) {
+	err := apiClient.ContainerRemove(context.WithoutCancel(ctx), id, container.RemoveOptions{Force: true})
+	if err != nil {
+		_, _ = fmt.Fprintln(dockerCli.Err(), "failed to remove helper container:", err)
+	}
+}
+
+func pullImage(ctx context.Context, dockerCli command.Cli, apiClient client.APIClient, helperImage string) error {
+	encodedAuth, err := command.RetrieveAuthTokenFromImage(dockerCli.ConfigFile(), helperImage)
+	if err != nil {
+		return err
+	}
+	responseBody, err := apiClient.ImageCreate(ctx, helperImage, image.CreateOptions{
+		RegistryAuth: encodedAuth,
+	})
+	if err != nil {
+		return err
+	}
+	defer responseBody.Close()
+	return jsonmessage.DisplayJSONMessagesToStream(responseBody, dockerCli.Err(), nil)
+}
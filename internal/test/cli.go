@@ -143,6 +143,11 @@ func (c *FakeCli) DockerEndpoint() docker.Endpoint {
 	return c.dockerEndpoint
 }
 
+// SetServerInfo sets the "fake" server info
+func (c *FakeCli) SetServerInfo(info command.ServerInfo) {
+	c.server = info
+}
+
 // ServerInfo returns API server information for the server used by this client
 func (c *FakeCli) ServerInfo() command.ServerInfo {
 	return c.server
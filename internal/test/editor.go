@@ -0,0 +1,23 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// SetEditor sets the editor to a script that replaces the content of the
+// edited file with content.
+func SetEditor(t *testing.T, content string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as editor")
+	}
+	script := filepath.Join(t.TempDir(), "editor")
+	assert.NilError(t, os.WriteFile(script, []byte("#!/bin/sh\nprintf '"+content+"' > \"$1\"\n"), 0o755))
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+}
@@ -0,0 +1,196 @@
+// Package registry provides an in-memory registry to test the commands that
+// use the registry client.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/distribution/reference"
+	manifesttypes "github.com/docker/cli/cli/manifest/types"
+	"github.com/docker/distribution"
+	"github.com/docker/docker/errdefs"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+// Registry is an in-memory registry, with its manifests by reference, and
+// its blobs. It implements the RegistryClient interface of the registry
+// client package.
+type Registry struct {
+	// NoReferrersAPI makes the registry behave as a registry without the
+	// referrers API, which only lists referrers in referrers tags.
+	NoReferrersAPI bool
+
+	mu        sync.Mutex
+	manifests map[string][]byte
+	types     map[string]string
+	blobs     map[digest.Digest][]byte
+}
+
+// New returns an empty registry.
+func New() *Registry {
+	return &Registry{
+		manifests: map[string][]byte{},
+		types:     map[string]string{},
+		blobs:     map[digest.Digest][]byte{},
+	}
+}
+
+// AddBlob adds a blob with the given media type, and returns its
+// descriptor. v is either the content of the blob, or a value that is
+// marshaled to JSON.
+func (r *Registry) AddBlob(t *testing.T, mediaType string, v any) ocispec.Descriptor {
+	t.Helper()
+	b, ok := v.([]byte)
+	if !ok {
+		var err error
+		b, err = json.Marshal(v)
+		assert.NilError(t, err)
+	}
+	dgst := digest.FromBytes(b)
+	r.mu.Lock()
+	r.blobs[dgst] = b
+	r.mu.Unlock()
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(b))}
+}
+
+// AddManifest adds v, marshaled to JSON, as a manifest of repo by digest
+// and, if set, by tag, and returns its descriptor.
+func (r *Registry) AddManifest(t *testing.T, repo, tag, mediaType string, v any) ocispec.Descriptor {
+	t.Helper()
+	b, err := json.Marshal(v)
+	assert.NilError(t, err)
+	return r.putManifest(repo, tag, mediaType, b)
+}
+
+func (r *Registry) putManifest(repo, tag, mediaType string, b []byte) ocispec.Descriptor {
+	dgst := digest.FromBytes(b)
+	refs := []string{repo + "@" + dgst.String()}
+	if tag != "" {
+		refs = append(refs, repo+":"+tag)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ref := range refs {
+		r.manifests[ref], r.types[ref] = b, mediaType
+	}
+	return ocispec.Descriptor{MediaType: mediaType, Digest: dgst, Size: int64(len(b))}
+}
+
+// GetRawManifest returns the manifest of ref.
+func (r *Registry) GetRawManifest(_ context.Context, ref reference.Named) (ocispec.Descriptor, []byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.manifests[ref.String()]
+	if !ok {
+		return ocispec.Descriptor{}, nil, errdefs.NotFound(errors.New("manifest unknown"))
+	}
+	return ocispec.Descriptor{MediaType: r.types[ref.String()], Digest: digest.FromBytes(b), Size: int64(len(b))}, b, nil
+}
+
+// GetBlob returns the blob of ref.
+func (r *Registry) GetBlob(_ context.Context, ref reference.Canonical) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.blobs[ref.Digest()]
+	if !ok {
+		return nil, errdefs.NotFound(errors.New("blob unknown"))
+	}
+	return b, nil
+}
+
+// GetReferrers returns the manifests which subject is ref, or, if the
+// registry has no referrers API, the manifests of the referrers tag of ref.
+func (r *Registry) GetReferrers(ctx context.Context, ref reference.Canonical) ([]ocispec.Descriptor, error) {
+	if r.NoReferrersAPI {
+		tagged, _ := reference.WithTag(reference.TrimNamed(ref), "sha256-"+ref.Digest().Encoded())
+		_, b, err := r.GetRawManifest(ctx, tagged)
+		if err != nil {
+			return nil, nil
+		}
+		var index ocispec.Index
+		err = json.Unmarshal(b, &index)
+		return index.Manifests, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix := reference.TrimNamed(ref).String() + "@"
+	var referrers []ocispec.Descriptor
+	for name, b := range r.manifests {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		var m ocispec.Manifest
+		if json.Unmarshal(b, &m) == nil && m.Subject != nil && m.Subject.Digest == ref.Digest() {
+			referrers = append(referrers, ocispec.Descriptor{MediaType: r.types[name], ArtifactType: m.ArtifactType, Digest: digest.FromBytes(b), Size: int64(len(b))})
+		}
+	}
+	sort.Slice(referrers, func(i, j int) bool { return referrers[i].Digest < referrers[j].Digest })
+	return referrers, nil
+}
+
+// GetTags returns the tags of ref.
+func (r *Registry) GetTags(_ context.Context, ref reference.Named) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tags []string
+	for name := range r.manifests {
+		if tag, ok := strings.CutPrefix(name, ref.Name()+":"); ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// PutBlob adds the content of a blob, after checking it against desc.
+func (r *Registry) PutBlob(_ context.Context, _ reference.Named, desc ocispec.Descriptor, content io.Reader) error {
+	b, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if digest.FromBytes(b) != desc.Digest {
+		return errors.New("digest mismatch")
+	}
+	r.mu.Lock()
+	r.blobs[desc.Digest] = b
+	r.mu.Unlock()
+	return nil
+}
+
+// PutManifest adds a manifest by digest and, if ref is tagged, by tag.
+func (r *Registry) PutManifest(_ context.Context, ref reference.Named, m distribution.Manifest) (digest.Digest, error) {
+	mediaType, payload, err := m.Payload()
+	if err != nil {
+		return "", err
+	}
+	var tag string
+	if tagged, ok := ref.(reference.Tagged); ok {
+		tag = tagged.Tag()
+	}
+	return r.putManifest(ref.Name(), tag, mediaType, payload).Digest, nil
+}
+
+// GetManifest is not implemented.
+func (*Registry) GetManifest(context.Context, reference.Named) (manifesttypes.ImageManifest, error) {
+	return manifesttypes.ImageManifest{}, errdefs.NotImplemented(errors.New("not implemented"))
+}
+
+// GetManifestList is not implemented.
+func (*Registry) GetManifestList(context.Context, reference.Named) ([]manifesttypes.ImageManifest, error) {
+	return nil, errdefs.NotImplemented(errors.New("not implemented"))
+}
+
+// MountBlob is not implemented.
+func (*Registry) MountBlob(context.Context, reference.Canonical, reference.Named) error {
+	return errdefs.NotImplemented(errors.New("not implemented"))
+}
@@ -1,7 +1,10 @@
 package opts
 
 import (
+	"errors"
+	"io/fs"
 	"os"
+	"strings"
 )
 
 // ParseEnvFile reads a file with environment variables enumerated by lines
@@ -20,3 +23,73 @@ import (
 func ParseEnvFile(filename string) ([]string, error) {
 	return parseKeyValueFile(filename, os.LookupEnv)
 }
+
+// EnvFileOptions are the options of ReadEnvFiles.
+type EnvFileOptions struct {
+	// Optional skips the env files that don't exist, instead of returning
+	// an error.
+	Optional bool
+	// Interpolate substitutes the variables in the values of the env files,
+	// such as "${HOME}", with the variables that are set on earlier lines
+	// or in earlier files, or in the environment. Refer to [interpolate]
+	// for the syntax.
+	Interpolate bool
+}
+
+// ReadEnvFiles reads env files, and overrides any keys present in the files
+// with additional pairs specified in the override parameter, like
+// [ReadKVEnvStrings]. Unlike ReadKVEnvStrings, each variable is only returned
+// once, with the value of the last file or override that sets it.
+func ReadEnvFiles(files []string, override []string, opts EnvFileOptions) ([]string, error) {
+	var interpolateFn func(variable, value string) (string, error)
+	if opts.Interpolate {
+		vars := map[string]string{}
+		lookup := func(name string) (string, bool) {
+			if v, ok := vars[name]; ok {
+				return v, true
+			}
+			return os.LookupEnv(name)
+		}
+		interpolateFn = func(variable, value string) (string, error) {
+			v, err := interpolate(value, lookup)
+			if err != nil {
+				return "", err
+			}
+			vars[variable] = v
+			return v, nil
+		}
+	}
+
+	var variables []string
+	for _, ef := range files {
+		parsedVars, err := parseKeyValueFileWithInterpolation(ef, os.LookupEnv, interpolateFn)
+		if err != nil {
+			if opts.Optional && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		variables = append(variables, parsedVars...)
+	}
+	// parse the '-e' and '--env' after, to allow override
+	variables = append(variables, override...)
+
+	return dedupEnv(variables), nil
+}
+
+// dedupEnv returns variables with each variable once, at the position where
+// it's first set, with the value that it's last set to.
+func dedupEnv(variables []string) []string {
+	index := make(map[string]int, len(variables))
+	var result []string
+	for _, v := range variables {
+		k, _, _ := strings.Cut(v, "=")
+		if i, ok := index[k]; ok {
+			result[i] = v
+			continue
+		}
+		index[k] = len(result)
+		result = append(result, v)
+	}
+	return result
+}
@@ -22,6 +22,12 @@ func (e ErrBadKey) Error() string {
 }
 
 func parseKeyValueFile(filename string, emptyFn func(string) (string, bool)) ([]string, error) {
+	return parseKeyValueFileWithInterpolation(filename, emptyFn, nil)
+}
+
+// parseKeyValueFileWithInterpolation is like parseKeyValueFile, and passes
+// the values of the lines with a value through interpolateFn, if set.
+func parseKeyValueFileWithInterpolation(filename string, emptyFn func(string) (string, bool), interpolateFn func(variable, value string) (string, error)) ([]string, error) {
 	fh, err := os.Open(filename)
 	if err != nil {
 		return []string{}, err
@@ -58,6 +64,12 @@ func parseKeyValueFile(filename string, emptyFn func(string) (string, bool)) ([]
 			}
 
 			if hasValue {
+				if interpolateFn != nil {
+					var err error
+					if value, err = interpolateFn(variable, value); err != nil {
+						return []string{}, fmt.Errorf("env file %s line %d: %w", filename, currentLine, err)
+					}
+				}
 				// pass the value through, no trimming
 				lines = append(lines, variable+"="+value)
 			} else {
@@ -13,6 +13,8 @@ import (
 // GpuOpts is a Value type for parsing mounts
 type GpuOpts struct {
 	values []container.DeviceRequest
+	auto   bool
+	list   bool
 }
 
 func parseCount(s string) (int, error) {
@@ -27,6 +29,17 @@ func parseCount(s string) (int, error) {
 //
 //nolint:gocyclo
 func (o *GpuOpts) Set(value string) error {
+	switch value {
+	case "auto":
+		// "auto" requests all GPUs, as "all", but the CLI checks that
+		// the daemon has a GPU runtime first.
+		o.auto = true
+		value = "all"
+	case "list":
+		o.list = true
+		return nil
+	}
+
 	csvReader := csv.NewReader(strings.NewReader(value))
 	fields, err := csvReader.Read()
 	if err != nil {
@@ -109,3 +122,15 @@ func (o *GpuOpts) String() string {
 func (o *GpuOpts) Value() []container.DeviceRequest {
 	return o.values
 }
+
+// Auto returns whether "auto" was set, which requests all GPUs if the daemon
+// has a GPU runtime.
+func (o *GpuOpts) Auto() bool {
+	return o.auto
+}
+
+// List returns whether "list" was set, to list the GPUs of the daemon instead
+// of requesting GPUs.
+func (o *GpuOpts) List() bool {
+	return o.list
+}
@@ -46,3 +46,24 @@ func TestGpusOpts(t *testing.T) {
 		}))
 	}
 }
+
+func TestGpusOptAuto(t *testing.T) {
+	var gpus GpuOpts
+	assert.NilError(t, gpus.Set("auto"))
+	assert.Check(t, gpus.Auto())
+	assert.Check(t, !gpus.List())
+	gpuReqs := gpus.Value()
+	assert.Assert(t, is.Len(gpuReqs, 1))
+	assert.Check(t, is.DeepEqual(gpuReqs[0], container.DeviceRequest{
+		Count:        -1,
+		Capabilities: [][]string{{"gpu"}},
+		Options:      map[string]string{},
+	}))
+}
+
+func TestGpusOptList(t *testing.T) {
+	var gpus GpuOpts
+	assert.NilError(t, gpus.Set("list"))
+	assert.Check(t, gpus.List())
+	assert.Check(t, is.Len(gpus.Value(), 0))
+}
@@ -0,0 +1,134 @@
+package opts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// interpolate substitutes the variables in value, with the syntax of compose
+// files:
+//
+//   - "$NAME" and "${NAME}" are the value of NAME, or empty if it's not set.
+//   - "${NAME:-default}" is default if NAME is not set or empty, and
+//     "${NAME-default}" if NAME is not set.
+//   - "${NAME:?message}" is an error if NAME is not set or empty, and
+//     "${NAME?message}" if NAME is not set.
+//   - "${NAME:+other}" is other if NAME is set and not empty, and
+//     "${NAME+other}" if NAME is set.
+//   - "$$" is a literal "$".
+//
+// The default and other values are interpolated as well.
+func interpolate(value string, lookup func(string) (string, bool)) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c != '$' || i+1 == len(value) {
+			sb.WriteByte(c)
+			continue
+		}
+		switch next := value[i+1]; {
+		case next == '$':
+			sb.WriteByte('$')
+			i++
+		case next == '{':
+			end := closingBrace(value, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("invalid interpolation format for %q: missing closing brace", value)
+			}
+			s, err := substitute(value[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+			i = end
+		case isNameStart(next):
+			j := i + 1
+			for j < len(value) && isNameChar(value[j]) {
+				j++
+			}
+			v, _ := lookup(value[i+1 : j])
+			sb.WriteString(v)
+			i = j - 1
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), nil
+}
+
+// closingBrace returns the index of the brace that closes the "${" before
+// start, taking nested "${...}" into account, or -1 if there's none.
+func closingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch {
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '$':
+			i++
+		case value[i] == '$' && i+1 < len(value) && value[i+1] == '{':
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// substitute returns the value of the expression between the braces of
+// "${...}".
+func substitute(expr string, lookup func(string) (string, bool)) (string, error) {
+	n := 0
+	for n < len(expr) && isNameChar(expr[n]) {
+		n++
+	}
+	name, op := expr[:n], expr[n:]
+	if name == "" || !isNameStart(name[0]) {
+		return "", fmt.Errorf("invalid interpolation format for ${%s}: invalid variable name", expr)
+	}
+	v, ok := lookup(name)
+	if op == "" {
+		return v, nil
+	}
+
+	emptyIsUnset := strings.HasPrefix(op, ":")
+	op = strings.TrimPrefix(op, ":")
+	if op == "" {
+		return "", fmt.Errorf("invalid interpolation format for ${%s}", expr)
+	}
+	set := ok && (v != "" || !emptyIsUnset)
+	arg := op[1:]
+	switch op[0] {
+	case '-':
+		if set {
+			return v, nil
+		}
+		return interpolate(arg, lookup)
+	case '?':
+		if set {
+			return v, nil
+		}
+		message, err := interpolate(arg, lookup)
+		if err != nil {
+			return "", err
+		}
+		return "", fmt.Errorf("required variable %s is missing a value: %s", name, message)
+	case '+':
+		if !set {
+			return "", nil
+		}
+		return interpolate(arg, lookup)
+	default:
+		return "", fmt.Errorf("invalid interpolation format for ${%s}", expr)
+	}
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,60 @@
+package opts
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestInterpolate(t *testing.T) {
+	vars := map[string]string{"FOO": "foo", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{value: "plain", expected: "plain"},
+		{value: "$FOO/${FOO}", expected: "foo/foo"},
+		{value: "${FOO}bar", expected: "foobar"},
+		{value: "$UNSET-${UNSET}", expected: "-"},
+		{value: "$$FOO", expected: "$FOO"},
+		{value: "price: 5$", expected: "price: 5$"},
+		{value: "$1", expected: "$1"},
+		{value: "${UNSET:-default}", expected: "default"},
+		{value: "${EMPTY:-default}", expected: "default"},
+		{value: "${EMPTY-default}", expected: ""},
+		{value: "${UNSET-${FOO}}", expected: "foo"},
+		{value: "${FOO:+other}", expected: "other"},
+		{value: "${EMPTY:+other}", expected: ""},
+		{value: "${EMPTY+other}", expected: "other"},
+		{value: "${FOO:?required}", expected: "foo"},
+	}
+	for _, tc := range testCases {
+		actual, err := interpolate(tc.value, lookup)
+		assert.Check(t, err, tc.value)
+		assert.Check(t, is.Equal(actual, tc.expected), tc.value)
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	lookup := func(name string) (string, bool) { return "", false }
+	testCases := []struct {
+		value         string
+		expectedError string
+	}{
+		{value: "${FOO", expectedError: "missing closing brace"},
+		{value: "${}", expectedError: "invalid variable name"},
+		{value: "${1FOO}", expectedError: "invalid variable name"},
+		{value: "${FOO:}", expectedError: "invalid interpolation format"},
+		{value: "${FOO#bar}", expectedError: "invalid interpolation format"},
+		{value: "${FOO:?must be set}", expectedError: "required variable FOO is missing a value: must be set"},
+	}
+	for _, tc := range testCases {
+		_, err := interpolate(tc.value, lookup)
+		assert.Check(t, is.ErrorContains(err, tc.expectedError), tc.value)
+	}
+}
@@ -1,6 +1,8 @@
 package opts
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -69,3 +71,53 @@ NO_SUCH_ENV
 		})
 	}
 }
+
+func TestReadEnvFiles(t *testing.T) {
+	envFile1 := fs.NewFile(t, t.Name(), fs.WithContent(`HOST=db.example.com
+PORT=5432
+URL=postgres://${HOST}:${PORT:-5432}/app
+PASSWORD=pa$$word
+FROM_ENV
+`))
+	defer envFile1.Remove()
+	envFile2 := fs.NewFile(t, t.Name(), fs.WithContent("PORT=6543\nHOME_DIR=${FROM_ENV}/home\n"))
+	defer envFile2.Remove()
+	t.Setenv("FROM_ENV", "from-env")
+
+	envs, err := ReadEnvFiles([]string{envFile1.Path(), envFile2.Path()}, []string{"HOST=override"}, EnvFileOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, envs, []string{
+		"HOST=override",
+		"PORT=6543",
+		"URL=postgres://${HOST}:${PORT:-5432}/app",
+		"PASSWORD=pa$$word",
+		"FROM_ENV=from-env",
+		"HOME_DIR=${FROM_ENV}/home",
+	})
+
+	envs, err = ReadEnvFiles([]string{envFile1.Path(), envFile2.Path()}, nil, EnvFileOptions{Interpolate: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, envs, []string{
+		"HOST=db.example.com",
+		"PORT=6543",
+		"URL=postgres://db.example.com:5432/app",
+		"PASSWORD=pa$word",
+		"FROM_ENV=from-env",
+		"HOME_DIR=from-env/home",
+	})
+
+	missing := filepath.Join(t.TempDir(), "missing.env")
+	_, err = ReadEnvFiles([]string{missing, envFile2.Path()}, nil, EnvFileOptions{})
+	assert.Check(t, os.IsNotExist(err))
+	envs, err = ReadEnvFiles([]string{missing, envFile2.Path()}, nil, EnvFileOptions{Optional: true})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, envs, []string{"PORT=6543", "HOME_DIR=${FROM_ENV}/home"})
+}
+
+func TestReadEnvFilesInterpolationError(t *testing.T) {
+	envFile := fs.NewFile(t, t.Name(), fs.WithContent("A=a\nB=${UNSET:?is required}\n"))
+	defer envFile.Remove()
+
+	_, err := ReadEnvFiles([]string{envFile.Path()}, nil, EnvFileOptions{Interpolate: true})
+	assert.Error(t, err, "env file "+envFile.Path()+" line 2: required variable UNSET is missing a value: is required")
+}
@@ -0,0 +1,104 @@
+package opts
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RunSecret is a secret of a container that isn't a service, which is read
+// from a file or an environment variable on the client.
+type RunSecret struct {
+	ID string
+	// Source is the file that contains the secret.
+	Source string
+	// Env is the environment variable that contains the secret.
+	Env string
+	// Target is the path of the secret in the container, which is relative
+	// to /run/secrets unless it's absolute.
+	Target string
+}
+
+// RunSecretOpt is a Value type for parsing the secrets of containers, in the
+// format of the secrets of "docker build", such as "id=key,src=./key.txt".
+type RunSecretOpt struct {
+	values []RunSecret
+}
+
+// Set a new secret value
+func (o *RunSecretOpt) Set(value string) error {
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return err
+	}
+
+	var s RunSecret
+	for _, field := range fields {
+		key, val, ok := strings.Cut(field, "=")
+		if !ok {
+			// support a simple syntax of --secret foo
+			if len(fields) == 1 {
+				s.ID = field
+				continue
+			}
+			return fmt.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+		switch key {
+		case "id":
+			s.ID = val
+		case "source", "src":
+			s.Source = val
+		case "env":
+			s.Env = val
+		case "target", "dst":
+			s.Target = val
+		default:
+			return errors.New("invalid field in secret request: " + key)
+		}
+	}
+
+	if s.ID == "" {
+		return errors.New("id is required")
+	}
+	if s.Source != "" && s.Env != "" {
+		return errors.New("source and env cannot be used together")
+	}
+	if s.Source == "" && s.Env == "" {
+		// As with the secrets of "docker build", the secret is read from
+		// the environment variable of the same name if it's set, or from
+		// the file of the same name otherwise.
+		if _, ok := os.LookupEnv(s.ID); ok {
+			s.Env = s.ID
+		} else {
+			s.Source = s.ID
+		}
+	}
+	if s.Target == "" {
+		s.Target = s.ID
+	}
+
+	o.values = append(o.values, s)
+	return nil
+}
+
+// Type returns the type of this option
+func (o *RunSecretOpt) Type() string {
+	return "secret"
+}
+
+// String returns a string repr of this option
+func (o *RunSecretOpt) String() string {
+	secrets := []string{}
+	for _, s := range o.values {
+		secrets = append(secrets, s.ID+" -> "+s.Target)
+	}
+	return strings.Join(secrets, ", ")
+}
+
+// Value returns the secrets
+func (o *RunSecretOpt) Value() []RunSecret {
+	return o.values
+}
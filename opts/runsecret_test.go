@@ -0,0 +1,63 @@
+package opts
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestRunSecretOptions(t *testing.T) {
+	t.Setenv("API_TOKEN", "s3cr3t")
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected RunSecret
+	}{
+		{
+			name:     "File",
+			input:    "id=key,src=./key.txt",
+			expected: RunSecret{ID: "key", Source: "./key.txt", Target: "key"},
+		},
+		{
+			name:     "Env",
+			input:    "id=token,env=API_TOKEN,target=/etc/app/token",
+			expected: RunSecret{ID: "token", Env: "API_TOKEN", Target: "/etc/app/token"},
+		},
+		{
+			name:     "SimpleEnv",
+			input:    "API_TOKEN",
+			expected: RunSecret{ID: "API_TOKEN", Env: "API_TOKEN", Target: "API_TOKEN"},
+		},
+		{
+			name:     "SimpleFile",
+			input:    "id=key.txt",
+			expected: RunSecret{ID: "key.txt", Source: "key.txt", Target: "key.txt"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var opt RunSecretOpt
+			assert.NilError(t, opt.Set(tc.input))
+			assert.Check(t, is.DeepEqual(opt.Value(), []RunSecret{tc.expected}))
+		})
+	}
+}
+
+func TestRunSecretOptionsInvalid(t *testing.T) {
+	testCases := []struct {
+		input       string
+		expectedErr string
+	}{
+		{input: "src=./key.txt", expectedErr: "id is required"},
+		{input: "id=key,src=./key.txt,env=KEY", expectedErr: "source and env cannot be used together"},
+		{input: "id=key,mode=0400", expectedErr: "invalid field in secret request: mode"},
+		{input: "id=key,foo", expectedErr: "invalid field 'foo' must be a key=value pair"},
+	}
+	for _, tc := range testCases {
+		var opt RunSecretOpt
+		assert.Check(t, is.Error(opt.Set(tc.input), tc.expectedErr), tc.input)
+	}
+}
@@ -32,6 +32,7 @@ var basicFunctions = template.FuncMap{
 	"upper":    strings.ToUpper,
 	"pad":      padWithSpace,
 	"truncate": truncateWithLength,
+	"yaml":     formatYAML,
 }
 
 // HeaderFunctions are used to created headers of a table.
@@ -66,6 +67,9 @@ var HeaderFunctions = template.FuncMap{
 	"truncate": func(v string, _ int) string {
 		return v
 	},
+	"yaml": func(v string) string {
+		return v
+	},
 }
 
 // Parse creates a new anonymous template with the basic functions
@@ -19,6 +19,39 @@ func TestParseJSONFunctions(t *testing.T) {
 	assert.Check(t, is.Equal(want, b.String()))
 }
 
+func TestParseYAMLFunction(t *testing.T) {
+	tm, err := Parse(`{{yaml .}}`)
+	assert.NilError(t, err)
+
+	type mount struct {
+		Source string `json:"Source"`
+		RW     bool   `json:"RW"`
+	}
+	v := struct {
+		Name   string
+		Size   int64
+		Mounts []mount
+		Labels map[string]string
+		Extra  any
+	}{
+		Name:   "web",
+		Size:   1024,
+		Mounts: []mount{{Source: "/data", RW: true}},
+		Labels: map[string]string{},
+	}
+
+	var b bytes.Buffer
+	assert.NilError(t, tm.Execute(&b, v))
+	want := `Name: web
+Size: 1024
+Mounts:
+- Source: /data
+  RW: true
+Labels: {}
+Extra: null`
+	assert.Check(t, is.Equal(want, b.String()))
+}
+
 func TestParseStringFunctions(t *testing.T) {
 	tm, err := Parse(`{{join (split . ":") "/"}}`)
 	assert.NilError(t, err)